@@ -0,0 +1,123 @@
+// ekscc-lint checks EKSClusterConfig manifests with the validation of the operator without calling AWS or the
+// management cluster, so that invalid configs can be caught in CI pipelines before they are applied.
+//
+// Usage: ekscc-lint [flags] FILE... ("-" reads from stdin)
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+
+	"github.com/rancher/eks-operator/controller"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+)
+
+var (
+	update            bool
+	nodeGroupDefaults controller.NodeGroupDefaults
+)
+
+func init() {
+	flag.BoolVar(&update, "update", false, "Check the configs as updates of existing clusters instead of new clusters.")
+	flag.StringVar(&nodeGroupDefaults.InstanceType, "default-instance-type", "", "The --default-instance-type of the operator, set on node groups that don't set one before they are checked.")
+	flag.Func("default-disk-size", "The --default-disk-size of the operator, set on node groups that don't set one before they are checked.", func(value string) error {
+		size, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || size <= 0 {
+			return fmt.Errorf("invalid disk size [%s], must be a positive number of GiB", value)
+		}
+		nodeGroupDefaults.DiskSize = int32(size)
+		return nil
+	})
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] FILE... (- reads from stdin)\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+}
+
+func main() {
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// warnings of the validation, like generated node roles, aren't problems of the config
+	logrus.SetLevel(logrus.ErrorLevel)
+
+	var checked, failed int
+	for _, file := range flag.Args() {
+		configs, err := readConfigs(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", file, err)
+			failed++
+			continue
+		}
+		for _, config := range configs {
+			checked++
+			if err := controller.Lint(config, nodeGroupDefaults, update); err != nil {
+				name := config.Name
+				if config.Namespace != "" {
+					name = config.Namespace + "/" + name
+				}
+				fmt.Fprintf(os.Stderr, "%s: %s: %v\n", file, name, err)
+				failed++
+			}
+		}
+	}
+
+	if failed != 0 {
+		fmt.Fprintf(os.Stderr, "%d of %d configs failed\n", failed, checked)
+		os.Exit(1)
+	}
+	fmt.Printf("%d configs passed\n", checked)
+}
+
+// readConfigs reads the EKSClusterConfigs from a file with one or more YAML or JSON documents, other kinds of objects
+// are skipped. Unknown fields are rejected, so that misspelled fields don't go unnoticed.
+func readConfigs(file string) ([]*eksv1.EKSClusterConfig, error) {
+	var reader io.Reader = os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		reader = f
+	}
+
+	var configs []*eksv1.EKSClusterConfig
+	documents := utilyaml.NewYAMLReader(bufio.NewReader(reader))
+	for {
+		document, err := documents.Read()
+		if errors.Is(err, io.EOF) {
+			return configs, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading document: %w", err)
+		}
+
+		var typeMeta metav1.TypeMeta
+		if err := yaml.Unmarshal(document, &typeMeta); err != nil {
+			return nil, fmt.Errorf("error parsing document: %w", err)
+		}
+		if typeMeta.Kind != "EKSClusterConfig" || typeMeta.APIVersion != eksv1.SchemeGroupVersion.String() {
+			continue
+		}
+
+		config := &eksv1.EKSClusterConfig{}
+		if err := yaml.UnmarshalStrict(document, config); err != nil {
+			return nil, fmt.Errorf("error parsing EKSClusterConfig: %w", err)
+		}
+		configs = append(configs, config)
+	}
+}
@@ -0,0 +1,98 @@
+// kubectl-ekscc_kubeconfig prints a ready-to-use kubeconfig for the EKS cluster of an EKSClusterConfig, so that admins
+// of the management cluster can reach the cluster, e.g. during incidents, without the AWS credentials or the aws cli.
+// The token of the kubeconfig is valid for 15 minutes. It is a kubectl plugin when installed in the PATH.
+//
+// Usage: kubectl ekscc-kubeconfig [flags] NAME (the name or the display name of the EKSClusterConfig)
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/rancher/wrangler/v3/pkg/generated/controllers/core"
+	"github.com/rancher/wrangler/v3/pkg/kubeconfig"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/rancher/eks-operator/controller"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	eks "github.com/rancher/eks-operator/pkg/generated/controllers/eks.cattle.io"
+	eksv1controllers "github.com/rancher/eks-operator/pkg/generated/controllers/eks.cattle.io/v1"
+)
+
+var (
+	kubeconfigFile string
+	namespace      string
+)
+
+func init() {
+	flag.StringVar(&kubeconfigFile, "kubeconfig", "", "Path to the kubeconfig of the management cluster, the same as kubectl uses by default.")
+	flag.StringVar(&namespace, "namespace", "cattle-global-data", "Namespace of the EKSClusterConfig.")
+	flag.StringVar(&namespace, "n", "cattle-global-data", "Shorthand for --namespace.")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] NAME (the name or the display name of the EKSClusterConfig)\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+}
+
+func main() {
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(context.Background(), flag.Arg(0)); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, name string) error {
+	cfg, err := kubeconfig.GetNonInteractiveClientConfig(kubeconfigFile).ClientConfig()
+	if err != nil {
+		return fmt.Errorf("error building kubeconfig of the management cluster: %w", err)
+	}
+	eksFactory, err := eks.NewFactoryFromConfig(cfg)
+	if err != nil {
+		return err
+	}
+	coreFactory, err := core.NewFactoryFromConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	config, err := getConfig(eksFactory.Eks().V1().EKSClusterConfig(), name)
+	if err != nil {
+		return err
+	}
+	data, err := controller.Kubeconfig(ctx, coreFactory.Core().V1().Secret(), config)
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+// getConfig returns the EKSClusterConfig with the given name or, as the names of configs created by Rancher are
+// generated, with the given display name.
+func getConfig(configs eksv1controllers.EKSClusterConfigClient, name string) (*eksv1.EKSClusterConfig, error) {
+	config, err := configs.Get(namespace, name, metav1.GetOptions{})
+	if err == nil || !apierrors.IsNotFound(err) {
+		return config, err
+	}
+
+	list, err := configs.List(namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range list.Items {
+		if list.Items[i].Spec.DisplayName == name {
+			return &list.Items[i], nil
+		}
+	}
+	return nil, fmt.Errorf("EKSClusterConfig [%s] not found in namespace [%s]", name, namespace)
+}
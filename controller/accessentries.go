@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	awsservices "github.com/rancher/eks-operator/pkg/eks"
+)
+
+// validateClusterAdminPrincipals checks that the cluster admin principals are unique ARNs of IAM users or roles, and
+// that they aren't set for local clusters on Outposts, which don't support access entries.
+func validateClusterAdminPrincipals(config *eksv1.EKSClusterConfig) error {
+	if len(config.Spec.ClusterAdminPrincipals) == 0 {
+		return nil
+	}
+	if config.Spec.OutpostConfig != nil {
+		return fmt.Errorf("field [clusterAdminPrincipals] can't be set for cluster [%s (id: %s)] on outposts, local clusters don't support access entries",
+			config.Spec.DisplayName, config.Name)
+	}
+
+	principals := make(map[string]struct{}, len(config.Spec.ClusterAdminPrincipals))
+	for _, principal := range config.Spec.ClusterAdminPrincipals {
+		if !isIAMPrincipalARN(principal) {
+			return fmt.Errorf("field [clusterAdminPrincipals] must contain ARNs of IAM users or roles for cluster [%s (id: %s)], got [%s]",
+				config.Spec.DisplayName, config.Name, principal)
+		}
+		if _, ok := principals[principal]; ok {
+			return fmt.Errorf("cluster admin principal [%s] of cluster [%s (id: %s)] is not unique", principal, config.Spec.DisplayName, config.Name)
+		}
+		principals[principal] = struct{}{}
+	}
+
+	return nil
+}
+
+func isIAMPrincipalARN(value string) bool {
+	parsed, err := arn.Parse(value)
+	return err == nil && parsed.Service == "iam" &&
+		(strings.HasPrefix(parsed.Resource, "user/") || strings.HasPrefix(parsed.Resource, "role/"))
+}
+
+// updateClusterAdminPrincipals enables access entries on the cluster for new cluster admin principals and reconciles
+// their access entries. It's only called when the principals of the spec differ from the granted ones. It returns
+// whether the cluster is being updated, in which case the access entries are reconciled once it is active again.
+func updateClusterAdminPrincipals(ctx context.Context, config *eksv1.EKSClusterConfig, awsSVCs *awsServices) (bool, error) {
+	// the authentication mode can't be changed back, so it's left as it is once all principals were removed
+	if len(config.Spec.ClusterAdminPrincipals) != 0 {
+		updating, err := awsservices.UpdateAuthenticationMode(ctx, &awsservices.UpdateAuthenticationModeOpts{
+			EKSService: awsSVCs.eks,
+			Config:     config,
+		})
+		if err != nil || updating {
+			return updating, err
+		}
+	}
+
+	_, err := awsservices.UpdateClusterAdminPrincipals(ctx, &awsservices.UpdateClusterAdminPrincipalsOpts{
+		EKSService:        awsSVCs.eks,
+		Config:            config,
+		GrantedPrincipals: config.Status.ClusterAdminPrincipals,
+	})
+	return false, err
+}
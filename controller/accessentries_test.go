@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"testing"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateClusterAdminPrincipals(t *testing.T) {
+	tests := []struct {
+		name       string
+		principals []string
+		outpost    bool
+		expectErr  bool
+	}{
+		{
+			name: "no principals",
+		},
+		{
+			name:       "roles and users",
+			principals: []string{"arn:aws:iam::123456789012:role/break-glass", "arn:aws:iam::123456789012:user/oncall"},
+		},
+		{
+			name:       "assumed role session",
+			principals: []string{"arn:aws:sts::123456789012:assumed-role/break-glass/session"},
+			expectErr:  true,
+		},
+		{
+			name:       "group",
+			principals: []string{"arn:aws:iam::123456789012:group/admins"},
+			expectErr:  true,
+		},
+		{
+			name:       "duplicate principal",
+			principals: []string{"arn:aws:iam::123456789012:role/break-glass", "arn:aws:iam::123456789012:role/break-glass"},
+			expectErr:  true,
+		},
+		{
+			name:       "local cluster on outposts",
+			principals: []string{"arn:aws:iam::123456789012:role/break-glass"},
+			outpost:    true,
+			expectErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{ClusterAdminPrincipals: tt.principals}}
+			if tt.outpost {
+				config.Spec.OutpostConfig = &eksv1.OutpostConfig{}
+			}
+			err := validateClusterAdminPrincipals(config)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	awsservices "github.com/rancher/eks-operator/pkg/eks"
+	"github.com/rancher/eks-operator/pkg/eks/services"
+)
+
+// clusterOwnerTagKey is the tag the operator sets on the clusters it adopts, its value identifies the config that owns
+// the cluster as namespace/name.
+const clusterOwnerTagKey = "eks.cattle.io/owner"
+
+// adoptExistingCluster imports the cluster with the display name of configs with adoptExisting set if it exists in
+// EKS, and returns the updated config and whether it was adopted. The adoption is recorded on the status, the spec is
+// left as it is. Configs that already created resources in AWS are creating their own cluster and never adopt one.
+func (h *Handler) adoptExistingCluster(ctx context.Context, config *eksv1.EKSClusterConfig, awsSVCs *awsServices) (*eksv1.EKSClusterConfig, bool, error) {
+	if !config.Spec.AdoptExisting || config.Spec.Imported || hasCreatedResources(config) {
+		return config, false, nil
+	}
+
+	claimed, err := claimExistingCluster(ctx, config, awsSVCs.eks)
+	if err != nil || !claimed {
+		return config, false, err
+	}
+
+	logrus.Infof("Adopting existing cluster [%s (id: %s)]", config.Spec.DisplayName, config.Name)
+	adopted := config.DeepCopy()
+	adopted.Status.Adopted = true
+	adopted.Status.Phase = eksv1.PhaseImporting
+	updated, err := h.updateStatus(adopted)
+	if err != nil {
+		return config, false, fmt.Errorf("error adopting cluster [%s (id: %s)]: %w", config.Spec.DisplayName, config.Name, err)
+	}
+	h.recordEvent(updated, corev1.EventTypeNormal, eventReasonAdopted, "Adopted existing cluster [%s]", config.Spec.DisplayName)
+	return updated, true, nil
+}
+
+// hasCreatedResources returns whether resources the operator created for the cluster are recorded on the status.
+func hasCreatedResources(config *eksv1.EKSClusterConfig) bool {
+	return len(config.Status.CloudFormationStacks) != 0 || config.Status.ProvisioningBackend != "" ||
+		config.Status.NetworkFieldsSource != "" || config.Status.GeneratedNodeRole != "" ||
+		config.Status.OIDCProviderARN != "" || config.Status.ManagedLaunchTemplateID != ""
+}
+
+// claimExistingCluster tags the cluster in EKS with the display name of the config as owned by it and returns whether
+// it exists. Clusters owned by another config can't be claimed, and clusters the operator created for the config
+// itself aren't claimed.
+func claimExistingCluster(ctx context.Context, config *eksv1.EKSClusterConfig, eksService services.EKSServiceInterface) (bool, error) {
+	output, err := eksService.DescribeCluster(ctx, &eks.DescribeClusterInput{
+		Name: aws.String(config.Spec.DisplayName),
+	})
+	if notFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error describing cluster [%s (id: %s)]: %w", config.Spec.DisplayName, config.Name, err)
+	}
+	if output.Cluster == nil {
+		return false, nil
+	}
+
+	if uid := output.Cluster.Tags[awsservices.UIDTagKey]; uid != "" {
+		if uid == string(config.UID) {
+			// the cluster was created for the config, e.g. before its status was updated, so the creation continues
+			return false, nil
+		}
+		return false, fmt.Errorf("cannot adopt cluster [%s (id: %s)] because the operator created it for another config", config.Spec.DisplayName, config.Name)
+	}
+
+	owner := config.Namespace + "/" + config.Name
+	switch existingOwner := output.Cluster.Tags[clusterOwnerTagKey]; existingOwner {
+	case owner:
+		return true, nil
+	case "":
+		if _, err := eksService.TagResource(ctx, &eks.TagResourceInput{
+			ResourceArn: output.Cluster.Arn,
+			Tags:        map[string]string{clusterOwnerTagKey: owner},
+		}); err != nil {
+			return false, fmt.Errorf("error tagging cluster [%s (id: %s)] as owned by it: %w", config.Spec.DisplayName, config.Name, err)
+		}
+		return true, nil
+	default:
+		return false, fmt.Errorf("cannot adopt cluster [%s (id: %s)] because it is owned by config [%s]", config.Spec.DisplayName, config.Name, existingOwner)
+	}
+}
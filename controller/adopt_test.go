@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	awsservices "github.com/rancher/eks-operator/pkg/eks"
+	"github.com/rancher/eks-operator/pkg/eks/services/mock_services"
+)
+
+func TestClaimExistingCluster(t *testing.T) {
+	ctx := context.Background()
+	eksServiceMock := mock_services.NewMockEKSServiceInterface(gomock.NewController(t))
+	config := &eksv1.EKSClusterConfig{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "cattle-global-data", Name: "c-abc"},
+		Spec:       eksv1.EKSClusterConfigSpec{DisplayName: "test", AdoptExisting: true},
+	}
+	describe := func(tags map[string]string) {
+		eksServiceMock.EXPECT().DescribeCluster(ctx, &eks.DescribeClusterInput{Name: aws.String("test")}).Return(
+			&eks.DescribeClusterOutput{Cluster: &ekstypes.Cluster{Arn: aws.String("arn:aws:eks:us-east-1:123456789012:cluster/test"), Tags: tags}}, nil)
+	}
+
+	// clusters that don't exist are created
+	eksServiceMock.EXPECT().DescribeCluster(ctx, gomock.Any()).Return(nil, &ekstypes.ResourceNotFoundException{})
+	claimed, err := claimExistingCluster(ctx, config, eksServiceMock)
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+
+	describe(map[string]string{"team": "a"})
+	eksServiceMock.EXPECT().TagResource(ctx, &eks.TagResourceInput{
+		ResourceArn: aws.String("arn:aws:eks:us-east-1:123456789012:cluster/test"),
+		Tags:        map[string]string{clusterOwnerTagKey: "cattle-global-data/c-abc"},
+	}).Return(&eks.TagResourceOutput{}, nil)
+	claimed, err = claimExistingCluster(ctx, config, eksServiceMock)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+
+	describe(map[string]string{clusterOwnerTagKey: "cattle-global-data/c-abc"})
+	claimed, err = claimExistingCluster(ctx, config, eksServiceMock)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+
+	describe(map[string]string{clusterOwnerTagKey: "cattle-global-data/c-xyz"})
+	claimed, err = claimExistingCluster(ctx, config, eksServiceMock)
+	assert.ErrorContains(t, err, "owned by config [cattle-global-data/c-xyz]")
+	assert.False(t, claimed)
+
+	// clusters the operator created for the config are not adopted, those of other configs can't be
+	config.UID = "uid"
+	describe(map[string]string{awsservices.UIDTagKey: "uid"})
+	claimed, err = claimExistingCluster(ctx, config, eksServiceMock)
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+
+	describe(map[string]string{awsservices.UIDTagKey: "other"})
+	claimed, err = claimExistingCluster(ctx, config, eksServiceMock)
+	assert.ErrorContains(t, err, "created it for another config")
+	assert.False(t, claimed)
+}
+
+func TestHasCreatedResources(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{}
+	assert.False(t, hasCreatedResources(config))
+
+	config.Status.CloudFormationStacks = []eksv1.CloudFormationStack{{Name: "test-eks-service-role"}}
+	assert.True(t, hasCreatedResources(config))
+	config.Status.CloudFormationStacks = nil
+	config.Status.ProvisioningBackend = awsservices.ProvisioningBackendNative
+	assert.True(t, hasCreatedResources(config))
+}
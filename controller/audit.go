@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"fmt"
+	"slices"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+)
+
+// auditLoggingType is the logging type that sends the audit logs of the cluster to its log group
+const auditLoggingType = "audit"
+
+// logRetentionDays are the retention periods CloudWatch supports for log groups
+var logRetentionDays = []int32{1, 3, 5, 7, 14, 30, 60, 90, 120, 150, 180, 365, 400, 545, 731, 1096, 1827, 2192, 2557, 2922, 3288, 3653}
+
+// validateAuditLogging checks that the audit logs the audit logging applies to are enabled and that the retention and
+// alarm are supported by CloudWatch.
+func validateAuditLogging(config *eksv1.EKSClusterConfig) error {
+	auditLogging := config.Spec.AuditLogging
+	if auditLogging == nil {
+		return nil
+	}
+	if !slices.Contains(config.Spec.LoggingTypes, auditLoggingType) {
+		return fmt.Errorf("field [loggingTypes] must contain %s when auditLogging is set for cluster [%s (id: %s)]",
+			auditLoggingType, config.Spec.DisplayName, config.Name)
+	}
+	if auditLogging.RetentionInDays != 0 && !slices.Contains(logRetentionDays, auditLogging.RetentionInDays) {
+		return fmt.Errorf("field [auditLogging.retentionInDays] must be one of %v for cluster [%s (id: %s)]",
+			logRetentionDays, config.Spec.DisplayName, config.Name)
+	}
+	if auditLogging.AlarmThreshold < 0 {
+		return fmt.Errorf("field [auditLogging.alarmThreshold] can't be negative for cluster [%s (id: %s)]",
+			config.Spec.DisplayName, config.Name)
+	}
+	if period := auditLogging.AlarmPeriodSeconds; period < 0 || period != 0 && period != 10 && period != 30 && period%60 != 0 {
+		return fmt.Errorf("field [auditLogging.alarmPeriodSeconds] must be 10, 30 or a multiple of 60 for cluster [%s (id: %s)]",
+			config.Spec.DisplayName, config.Name)
+	}
+	if auditLogging.AlarmThreshold == 0 && (auditLogging.AlarmPeriodSeconds != 0 || len(auditLogging.AlarmActions) != 0) {
+		return fmt.Errorf("field [auditLogging.alarmThreshold] must be set along with the alarm period and actions for cluster [%s (id: %s)]",
+			config.Spec.DisplayName, config.Name)
+	}
+	return nil
+}
+
+// auditLoggingApplied returns whether the audit logging of the spec is the one that was last applied.
+func auditLoggingApplied(config *eksv1.EKSClusterConfig) bool {
+	spec, status := config.Spec.AuditLogging, config.Status.AuditLogging
+	if spec == nil || status == nil {
+		return spec == status
+	}
+	return spec.RetentionInDays == status.RetentionInDays &&
+		spec.FilterPattern == status.FilterPattern &&
+		spec.AlarmThreshold == status.AlarmThreshold &&
+		spec.AlarmPeriodSeconds == status.AlarmPeriodSeconds &&
+		slices.Equal(spec.AlarmActions, status.AlarmActions)
+}
@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+)
+
+func TestValidateAuditLogging(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{DisplayName: "test"}}
+	assert.NoError(t, validateAuditLogging(config))
+
+	config.Spec.AuditLogging = &eksv1.AuditLogging{RetentionInDays: 90}
+	assert.ErrorContains(t, validateAuditLogging(config), "loggingTypes", "the audit logs have to be enabled")
+	config.Spec.LoggingTypes = []string{"api", "audit"}
+	assert.NoError(t, validateAuditLogging(config))
+
+	config.Spec.AuditLogging.RetentionInDays = 100
+	assert.ErrorContains(t, validateAuditLogging(config), "retentionInDays")
+	config.Spec.AuditLogging.RetentionInDays = 0
+
+	config.Spec.AuditLogging.AlarmActions = []string{"arn:aws:sns:us-east-1:123456789012:audit"}
+	assert.ErrorContains(t, validateAuditLogging(config), "alarmThreshold", "alarm actions require an alarm")
+	config.Spec.AuditLogging.AlarmThreshold = 1
+	assert.NoError(t, validateAuditLogging(config))
+
+	for _, period := range []int32{10, 30, 60, 3600} {
+		config.Spec.AuditLogging.AlarmPeriodSeconds = period
+		assert.NoError(t, validateAuditLogging(config))
+	}
+	for _, period := range []int32{-60, 20, 90} {
+		config.Spec.AuditLogging.AlarmPeriodSeconds = period
+		assert.ErrorContains(t, validateAuditLogging(config), "alarmPeriodSeconds")
+	}
+}
+
+func TestAuditLoggingApplied(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{}
+	assert.True(t, auditLoggingApplied(config))
+
+	config.Spec.AuditLogging = &eksv1.AuditLogging{AlarmThreshold: 1}
+	assert.False(t, auditLoggingApplied(config))
+
+	config.Status.AuditLogging = &eksv1.AuditLogging{AlarmThreshold: 1, AlarmActions: []string{}}
+	assert.True(t, auditLoggingApplied(config), "empty and unset alarm actions are the same")
+
+	config.Spec.AuditLogging.AlarmActions = []string{"arn:aws:sns:us-east-1:123456789012:audit"}
+	assert.False(t, auditLoggingApplied(config))
+
+	config.Spec.AuditLogging = nil
+	assert.False(t, auditLoggingApplied(config), "audit logging removed from the spec still has to be removed upstream")
+}
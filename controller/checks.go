@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"sync"
+	"time"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+)
+
+// informationalCheckInterval is how often the checks whose results are only recorded on the status, like the resource
+// usage of a cluster, are run for each config. Configs are reconciled far more often than these results change.
+const informationalCheckInterval = 10 * time.Minute
+
+const (
+	// checkResourceUsage counts the AWS resources consumed by the cluster
+	checkResourceUsage = "resourceUsage"
+	// checkReadyNodes counts the ready nodes of the node groups in the downstream cluster
+	checkReadyNodes = "readyNodes"
+	// checkClusterInsights reads the insights EKS reports for the cluster
+	checkClusterInsights = "clusterInsights"
+)
+
+// checkThrottle limits how often the informational checks of each config are run, so that the AWS and downstream
+// requests behind them aren't sent on every reconcile. The times of the checks are kept in memory, so all checks run
+// again after the operator restarts.
+type checkThrottle struct {
+	sync.Mutex
+	interval time.Duration
+	lastRuns map[string]map[string]time.Time
+}
+
+func newCheckThrottle(interval time.Duration) *checkThrottle {
+	return &checkThrottle{interval: interval, lastRuns: make(map[string]map[string]time.Time)}
+}
+
+// due returns whether the named check of the config is due at the given time and records it as run if it is. Checks
+// are always due if there is no throttle.
+func (t *checkThrottle) due(config *eksv1.EKSClusterConfig, check string, now time.Time) bool {
+	if t == nil {
+		return true
+	}
+	t.Lock()
+	defer t.Unlock()
+	key := config.Namespace + "/" + config.Name
+	if lastRun, ok := t.lastRuns[key][check]; ok && now.Sub(lastRun) < t.interval {
+		return false
+	}
+	if t.lastRuns[key] == nil {
+		t.lastRuns[key] = make(map[string]time.Time)
+	}
+	t.lastRuns[key][check] = now
+	return true
+}
+
+// forget drops the check times of the config with the given key, once the config is gone.
+func (t *checkThrottle) forget(key string) {
+	if t == nil {
+		return
+	}
+	t.Lock()
+	defer t.Unlock()
+	delete(t.lastRuns, key)
+}
@@ -0,0 +1,33 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+)
+
+func TestCheckThrottle(t *testing.T) {
+	throttle := newCheckThrottle(time.Minute)
+	config := &eksv1.EKSClusterConfig{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "a"}}
+	other := &eksv1.EKSClusterConfig{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "b"}}
+	now := time.Now()
+
+	assert.True(t, throttle.due(config, checkResourceUsage, now))
+	assert.False(t, throttle.due(config, checkResourceUsage, now.Add(30*time.Second)))
+	// checks and configs are throttled separately
+	assert.True(t, throttle.due(config, "other", now))
+	assert.True(t, throttle.due(other, checkResourceUsage, now))
+	assert.True(t, throttle.due(config, checkResourceUsage, now.Add(time.Minute)))
+
+	throttle.forget("ns/a")
+	assert.True(t, throttle.due(config, "other", now))
+
+	var nilThrottle *checkThrottle
+	assert.True(t, nilThrottle.due(config, checkResourceUsage, now))
+	assert.True(t, nilThrottle.due(config, checkResourceUsage, now))
+	nilThrottle.forget("ns/a")
+}
@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"github.com/rancher/wrangler/v3/pkg/condition"
+)
+
+var (
+	// nodeGroupDeletionBlocked is true while node groups removed from the spec are kept because their
+	// deletion protection was enabled
+	nodeGroupDeletionBlocked = condition.Cond("NodeGroupDeletionBlocked")
+	// sharedSubnets is true if some of the provided subnets are shared from another account, so that the operator
+	// can't tag them
+	sharedSubnets = condition.Cond("SharedSubnets")
+	// nodegroupsReady is true once all node groups in the spec exist and are active, which can be well after the
+	// cluster itself became active
+	nodegroupsReady = condition.Cond("NodegroupsReady")
+	// nodegroupsDegraded is true while node groups are degraded upstream, its message lists their health issues
+	nodegroupsDegraded = condition.Cond("NodegroupsDegraded")
+	// taggingDegraded is true while the credentials of the cluster aren't allowed to tag its resources, the other
+	// updates are still made and tagging is retried
+	taggingDegraded = condition.Cond("TaggingDegraded")
+	// downstreamHealthy is true while the API server of the cluster is reachable with credentials generated by the
+	// operator and coredns is scheduled, it is probed whenever an update finishes and retried while it fails
+	downstreamHealthy = condition.Cond("DownstreamHealthy")
+	// updatesDeferred is true while disruptive updates are waiting for the maintenance window of the cluster to open,
+	// its message lists them
+	updatesDeferred = condition.Cond("UpdatesDeferred")
+	// drifted is true while the spec of a paused config differs from the cluster in EKS, its message lists the
+	// differences
+	drifted = condition.Cond("Drifted")
+	// nodeGroupRecreationRequired is true while node groups have changes EKS can't update and that aren't applied
+	// because the node groups don't have the Replace recreate policy, its message lists them
+	nodeGroupRecreationRequired = condition.Cond("NodeGroupRecreationRequired")
+	// insightWarnings is true while EKS reports insights with warnings or errors for the cluster, e.g. APIs removed in
+	// the next kubernetes version that are still in use, its message lists them
+	insightWarnings = condition.Cond("InsightWarnings")
+	// nodeGroupVersionSkew is true while the control plane isn't upgraded to the kubernetes version of the spec
+	// because upstream node groups would be too old for it, its message lists them
+	nodeGroupVersionSkew = condition.Cond("NodeGroupVersionSkew")
+)
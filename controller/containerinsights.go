@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	awsservices "github.com/rancher/eks-operator/pkg/eks"
+	"github.com/sirupsen/logrus"
+)
+
+// updateContainerInsights installs the CloudWatch observability add-on if Container Insights is enabled and removes it
+// if it is disabled. The policy of the CloudWatch agent is attached to and detached from the node instance role the
+// operator created along with the other additional policies of the role. It returns whether the add-on was installed.
+func updateContainerInsights(ctx context.Context, config *eksv1.EKSClusterConfig, awsSVCs *awsServices) (bool, error) {
+	installedArn, err := awsservices.CheckContainerInsightsAddon(ctx, config.Spec.DisplayName, awsSVCs.eks)
+	if err != nil {
+		return false, fmt.Errorf("error checking if container insights addon is installed: %w", err)
+	}
+
+	if !aws.ToBool(config.Spec.ContainerInsights) {
+		if installedArn == "" {
+			return false, nil
+		}
+		logrus.Infof("Disabling [container insights add-on] for cluster [%s (id: %s)]", config.Spec.DisplayName, config.Name)
+		// the add-on is removed in the background, the cluster doesn't need to wait on it
+		_, err := awsservices.DeleteContainerInsightsAddon(ctx, config.Spec.DisplayName, awsSVCs.eks)
+		return false, err
+	}
+	if installedArn != "" {
+		return false, nil
+	}
+
+	logrus.Infof("Enabling [container insights add-on] for cluster [%s (id: %s)]", config.Spec.DisplayName, config.Name)
+	if err := awsservices.InstallContainerInsightsAddon(ctx, awsSVCs.eks, config); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/golang/mock/gomock"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services/mock_services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateContainerInsights(t *testing.T) {
+	ctx := context.Background()
+	mockController := gomock.NewController(t)
+	eksServiceMock := mock_services.NewMockEKSServiceInterface(mockController)
+	awsSVCs := &awsServices{eks: eksServiceMock}
+	config := &eksv1.EKSClusterConfig{
+		Spec:   eksv1.EKSClusterConfigSpec{DisplayName: "test", ContainerInsights: aws.Bool(true)},
+		Status: eksv1.EKSClusterConfigStatus{GeneratedNodeRole: "arn:aws:iam::123456789012:role/test-node-instance-role-NodeInstanceRole-1"},
+	}
+	notFound := &ekstypes.ResourceNotFoundException{Message: aws.String("no addon")}
+	installed := &eks.DescribeAddonOutput{Addon: &ekstypes.Addon{AddonArn: aws.String("arn:aws:eks:us-west-2:123456789012:addon/test/amazon-cloudwatch-observability/1")}}
+
+	// the policy of the agent is left to the reconcile of the node instance role
+	eksServiceMock.EXPECT().DescribeAddon(ctx, gomock.Any()).Return(nil, notFound)
+	eksServiceMock.EXPECT().CreateAddon(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, input *eks.CreateAddonInput) (*eks.CreateAddonOutput, error) {
+			assert.Equal(t, "amazon-cloudwatch-observability", aws.ToString(input.AddonName))
+			return &eks.CreateAddonOutput{}, nil
+		})
+	updated, err := updateContainerInsights(ctx, config, awsSVCs)
+	require.NoError(t, err)
+	assert.True(t, updated)
+
+	eksServiceMock.EXPECT().DescribeAddon(ctx, gomock.Any()).Return(installed, nil)
+	updated, err = updateContainerInsights(ctx, config, awsSVCs)
+	require.NoError(t, err)
+	assert.False(t, updated)
+
+	// disabling removes the add-on without waiting on it
+	config.Spec.ContainerInsights = aws.Bool(false)
+	eksServiceMock.EXPECT().DescribeAddon(ctx, gomock.Any()).Return(installed, nil).Times(2)
+	eksServiceMock.EXPECT().DeleteAddon(ctx, gomock.Any()).Return(&eks.DeleteAddonOutput{}, nil)
+	updated, err = updateContainerInsights(ctx, config, awsSVCs)
+	require.NoError(t, err)
+	assert.False(t, updated)
+
+	eksServiceMock.EXPECT().DescribeAddon(ctx, gomock.Any()).Return(nil, notFound)
+	updated, err = updateContainerInsights(ctx, config, awsSVCs)
+	require.NoError(t, err)
+	assert.False(t, updated)
+}
@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+)
+
+// NodeGroupDefaults are the values the operator sets on node groups that omit them, so that they don't have to be
+// repeated in every config. Empty values aren't defaulted.
+type NodeGroupDefaults struct {
+	// InstanceType is set on on-demand node groups without an instance type, except ARM and GPU node groups, which
+	// need specific instance types
+	InstanceType string
+	// DiskSize is the disk size in GiB set on node groups without one
+	DiskSize int32
+}
+
+// setNodeGroupDefaults sets the defaults on the node groups of the config that omit them and returns whether any
+// changed. Node groups using a launch template provided by the user get their instance type and disk from it. Node
+// groups that exist upstream are left alone, since defaulting them would roll their nodes.
+func setNodeGroupDefaults(config *eksv1.EKSClusterConfig, defaults NodeGroupDefaults, upstreamNgs map[string]eksv1.NodeGroup) bool {
+	if config.Spec.Imported {
+		return false
+	}
+
+	var changed bool
+	for i := range config.Spec.NodeGroups {
+		ng := &config.Spec.NodeGroups[i]
+		if ng.LaunchTemplate != nil {
+			continue
+		}
+		if _, ok := upstreamNgs[aws.ToString(ng.NodegroupName)]; ok {
+			continue
+		}
+		if ng.DiskSize == nil && defaults.DiskSize > 0 {
+			ng.DiskSize = aws.Int32(defaults.DiskSize)
+			changed = true
+		}
+		if ng.InstanceType == "" && defaults.InstanceType != "" &&
+			!aws.ToBool(ng.RequestSpotInstances) && !aws.ToBool(ng.Arm) && !aws.ToBool(ng.Gpu) {
+			ng.InstanceType = defaults.InstanceType
+			changed = true
+		}
+	}
+	return changed
+}
+
+// applyNodeGroupDefaults stores the node group defaults of the operator in the spec of the config for the node groups
+// that aren't in the given upstream node groups yet, so that the defaulted values are visible and compared with the
+// upstream node groups like any other value.
+func (h *Handler) applyNodeGroupDefaults(config *eksv1.EKSClusterConfig, upstreamNgs map[string]eksv1.NodeGroup) (*eksv1.EKSClusterConfig, error) {
+	defaulted := config.DeepCopy()
+	if !setNodeGroupDefaults(defaulted, h.nodeGroupDefaults, upstreamNgs) {
+		return config, nil
+	}
+
+	updated, err := h.eksCC.Update(defaulted)
+	if err != nil {
+		return config, fmt.Errorf("error setting node group defaults for config [%s (id: %s)]: %w", config.Spec.DisplayName, config.Name, err)
+	}
+	return updated, nil
+}
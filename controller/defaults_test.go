@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetNodeGroupDefaults(t *testing.T) {
+	defaults := NodeGroupDefaults{InstanceType: "t3.large", DiskSize: 50}
+	config := &eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{NodeGroups: []eksv1.NodeGroup{
+		{NodegroupName: aws.String("omitted")},
+		{NodegroupName: aws.String("set"), InstanceType: "m5.xlarge", DiskSize: aws.Int32(100)},
+		{NodegroupName: aws.String("spot"), RequestSpotInstances: aws.Bool(true)},
+		{NodegroupName: aws.String("arm"), Arm: aws.Bool(true)},
+		{NodegroupName: aws.String("template"), LaunchTemplate: &eksv1.LaunchTemplate{ID: aws.String("lt")}},
+	}}}
+
+	assert.True(t, setNodeGroupDefaults(config, defaults, nil))
+	nodeGroups := config.Spec.NodeGroups
+	assert.Equal(t, "t3.large", nodeGroups[0].InstanceType)
+	assert.Equal(t, int32(50), aws.ToInt32(nodeGroups[0].DiskSize))
+	assert.Equal(t, "m5.xlarge", nodeGroups[1].InstanceType)
+	assert.Equal(t, int32(100), aws.ToInt32(nodeGroups[1].DiskSize))
+	assert.Empty(t, nodeGroups[2].InstanceType)
+	assert.Equal(t, int32(50), aws.ToInt32(nodeGroups[2].DiskSize))
+	assert.Empty(t, nodeGroups[3].InstanceType)
+	assert.Nil(t, nodeGroups[4].DiskSize)
+
+	// defaults are only set once
+	assert.False(t, setNodeGroupDefaults(config, defaults, nil))
+
+	assert.False(t, setNodeGroupDefaults(&eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{
+		NodeGroups: []eksv1.NodeGroup{{NodegroupName: aws.String("omitted")}},
+	}}, NodeGroupDefaults{}, nil))
+	assert.False(t, setNodeGroupDefaults(&eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{
+		Imported:   true,
+		NodeGroups: []eksv1.NodeGroup{{NodegroupName: aws.String("imported")}},
+	}}, defaults, nil))
+
+	// node groups that exist upstream aren't defaulted
+	config = &eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{NodeGroups: []eksv1.NodeGroup{
+		{NodegroupName: aws.String("existing")},
+		{NodegroupName: aws.String("new")},
+	}}}
+	assert.True(t, setNodeGroupDefaults(config, defaults, map[string]eksv1.NodeGroup{"existing": {}}))
+	assert.Empty(t, config.Spec.NodeGroups[0].InstanceType)
+	assert.Nil(t, config.Spec.NodeGroups[0].DiskSize)
+	assert.Equal(t, "t3.large", config.Spec.NodeGroups[1].InstanceType)
+}
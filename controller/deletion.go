@@ -0,0 +1,527 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	cftypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	awsservices "github.com/rancher/eks-operator/pkg/eks"
+	"github.com/rancher/eks-operator/pkg/eks/services"
+)
+
+const (
+	// cleanupFinalizer keeps a config around until the upstream resources of its cluster are deleted
+	cleanupFinalizer = "eks.cattle.io/cluster-cleanup"
+	// legacyCleanupFinalizer was added by the remove handler the operator registered before it managed its own
+	// finalizer, it is removed along with cleanupFinalizer
+	legacyCleanupFinalizer = "wrangler.cattle.io/" + controllerRemoveName
+
+	// the stages of the teardown of a cluster, in the order they run
+	deletionStageNodeGroups     = "nodeGroups"
+	deletionStageAddons         = "addons"
+	deletionStageLaunchTemplate = "launchTemplate"
+	deletionStageCluster        = "cluster"
+	deletionStageStacks         = "stacks"
+	deletionStageOIDCProvider   = "oidcProvider"
+	deletionStageLogGroup       = "logGroup"
+	deletionStageDone           = "done"
+
+	// deletionDryRunAnnotation set to true makes the deletion of a config only record the AWS resources that would be
+	// deleted in an event. The config is kept until the annotation is removed, which starts the actual deletion
+	deletionDryRunAnnotation = "eks.cattle.io/deletion-dry-run"
+
+	deletionPolicyDelete = "delete"
+	deletionPolicyRetain = "retain"
+	deletionPolicyForce  = "force"
+
+	// how much the checks on deleting clusters are spread out, as a fraction of the deleting interval, so that clusters
+	// deleted at the same time don't send their requests to AWS at the same time
+	deletionJitterFactor = 0.5
+)
+
+// deletionSlots bounds the number of clusters whose teardown runs at once across the operator, so that deleting many
+// clusters at once, e.g. by deleting their namespace, doesn't flood AWS with requests. A cluster holds its slot while
+// its teardown runs and frees it when the teardown fails, waits for AWS or finishes, so that failing or slow teardowns
+// don't keep the other clusters from being deleted.
+type deletionSlots struct {
+	sync.Mutex
+	max     int
+	holders map[string]struct{}
+}
+
+func newDeletionSlots(max int) *deletionSlots {
+	return &deletionSlots{max: max, holders: make(map[string]struct{})}
+}
+
+// acquire returns whether the config with the given key holds a slot, taking a free one if it doesn't yet. Slots are
+// unlimited if there is no maximum.
+func (s *deletionSlots) acquire(key string) bool {
+	if s == nil || s.max <= 0 {
+		return true
+	}
+	s.Lock()
+	defer s.Unlock()
+	if _, ok := s.holders[key]; ok {
+		return true
+	}
+	if len(s.holders) >= s.max {
+		return false
+	}
+	s.holders[key] = struct{}{}
+	return true
+}
+
+// release frees the slot of the config with the given key, if it holds one.
+func (s *deletionSlots) release(key string) {
+	if s == nil {
+		return
+	}
+	s.Lock()
+	defer s.Unlock()
+	delete(s.holders, key)
+}
+
+// requeueDeletion enqueues the deleting config again once the deleting interval has passed, spread out by
+// deletionJitterFactor.
+func (h *Handler) requeueDeletion(config *eksv1.EKSClusterConfig) {
+	h.eksEnqueueAfter(config.Namespace, config.Name, wait.Jitter(h.getRequeueInterval(config, deletingInterval), deletionJitterFactor))
+}
+
+// validateDeletionPolicy checks that the deletion policy of the cluster is known.
+func validateDeletionPolicy(config *eksv1.EKSClusterConfig) error {
+	switch config.Spec.DeletionPolicy {
+	case "", deletionPolicyDelete, deletionPolicyRetain, deletionPolicyForce:
+		return nil
+	}
+	return fmt.Errorf("invalid deletionPolicy [%s] for cluster [%s (id: %s)], valid values are %s, %s and %s",
+		config.Spec.DeletionPolicy, config.Spec.DisplayName, config.Name, deletionPolicyDelete, deletionPolicyRetain, deletionPolicyForce)
+}
+
+// neverCreated returns whether neither the cluster nor any resources for it were created, in which case there is nothing
+// to tear down. The stacks and roles of clusters are created before the cluster itself, across reconciles, so a config
+// deleted meanwhile still has resources to delete.
+func neverCreated(config *eksv1.EKSClusterConfig) bool {
+	return config.Status.Phase == eksv1.PhaseNotCreated && !hasCreatedResources(config)
+}
+
+// hasCleanupFinalizer returns whether the config is kept around to delete the upstream resources of its cluster.
+func hasCleanupFinalizer(config *eksv1.EKSClusterConfig) bool {
+	return slices.Contains(config.Finalizers, cleanupFinalizer) || slices.Contains(config.Finalizers, legacyCleanupFinalizer)
+}
+
+// ensureCleanupFinalizer adds the cleanup finalizer to configs of clusters managed by the operator. Imported clusters
+// are never deleted upstream, so they don't get one.
+func (h *Handler) ensureCleanupFinalizer(config *eksv1.EKSClusterConfig) (*eksv1.EKSClusterConfig, error) {
+	if config.Spec.Imported || slices.Contains(config.Finalizers, cleanupFinalizer) {
+		return config, nil
+	}
+
+	config = config.DeepCopy()
+	config.Finalizers = append(config.Finalizers, cleanupFinalizer)
+	updated, err := h.eksCC.Update(config)
+	if err != nil {
+		return config, fmt.Errorf("error adding finalizer to config [%s (id: %s)]: %w", config.Spec.DisplayName, config.Name, err)
+	}
+	return updated, nil
+}
+
+// removeCleanupFinalizers removes the cleanup finalizers from the config, so that its deletion can complete.
+func (h *Handler) removeCleanupFinalizers(config *eksv1.EKSClusterConfig) (*eksv1.EKSClusterConfig, error) {
+	config = config.DeepCopy()
+	config.Finalizers = slices.DeleteFunc(config.Finalizers, func(finalizer string) bool {
+		return finalizer == cleanupFinalizer || finalizer == legacyCleanupFinalizer
+	})
+	if _, err := h.eksCC.Update(config); err != nil {
+		return config, fmt.Errorf("error removing finalizer from config [%s (id: %s)]: %w", config.Spec.DisplayName, config.Name, err)
+	}
+	return nil, nil
+}
+
+// simulateDeletion records the AWS resources the deletion of the config would delete in an event, without deleting them.
+func (h *Handler) simulateDeletion(config *eksv1.EKSClusterConfig) (*eksv1.EKSClusterConfig, error) {
+	resources := getDeletionPlan(config)
+	logrus.Infof("Deletion dry run of cluster [%s (id: %s)], would delete %v", config.Spec.DisplayName, config.Name, resources)
+	if len(resources) == 0 {
+		h.recordEvent(config, corev1.EventTypeNormal, eventReasonDeletionDryRun, "Deleting cluster [%s] would not delete any AWS resources",
+			config.Spec.DisplayName)
+		return config, nil
+	}
+	h.recordEvent(config, corev1.EventTypeNormal, eventReasonDeletionDryRun, "Deleting cluster [%s] would delete %s",
+		config.Spec.DisplayName, strings.Join(resources, ", "))
+	return config, nil
+}
+
+// deletionStages are the stages of the teardown of a cluster, in the order they run
+var deletionStages = []string{
+	deletionStageNodeGroups,
+	deletionStageAddons,
+	deletionStageLaunchTemplate,
+	deletionStageCluster,
+	deletionStageStacks,
+	deletionStageOIDCProvider,
+	deletionStageLogGroup,
+}
+
+// deletionStep deletes AWS resources of a cluster in a stage of its teardown. Both the teardown and the deletion plan
+// of the dry run are built from getDeletionSteps, so that the plan lists what the teardown deletes.
+type deletionStep struct {
+	stage string
+	// resources are the AWS resources the step deletes, as they are listed in the deletion plan
+	resources []string
+	// run deletes the resources and returns whether it is waiting for them to be deleted. A step that is waiting is run
+	// again once the teardown is requeued, so it has to be repeatable.
+	run func(ctx context.Context, awsSVCs *awsServices) (bool, error)
+}
+
+// getDeletionSteps returns the steps of the teardown of the cluster, in the order they run.
+func getDeletionSteps(config *eksv1.EKSClusterConfig) []deletionStep {
+	name := config.Spec.DisplayName
+	force := config.Spec.DeletionPolicy == deletionPolicyForce
+	if config.Status.Phase == eksv1.PhaseNotCreated {
+		// only the stacks and roles were created, the cluster may be another one that already existed in EKS
+		return getStackDeletionSteps(config, force)
+	}
+	var steps []deletionStep
+
+	if len(config.Spec.NodeGroups) != 0 {
+		resources := make([]string, 0, len(config.Spec.NodeGroups))
+		for _, ng := range config.Spec.NodeGroups {
+			resources = append(resources, fmt.Sprintf("node group [%s]", aws.ToString(ng.NodegroupName)))
+		}
+		steps = append(steps, deletionStep{stage: deletionStageNodeGroups, resources: resources, run: func(ctx context.Context, awsSVCs *awsServices) (bool, error) {
+			waiting, err := deleteNodeGroups(ctx, config, config.Spec.NodeGroups, awsSVCs.eks, force)
+			if err != nil {
+				return false, fmt.Errorf("error deleting nodegroups for config [%s (id: %s)]: %w", name, config.Name, err)
+			}
+			if waiting {
+				logrus.Infof("Waiting for config [%s (id: %s)] node groups to delete", name, config.Name)
+			}
+			return waiting, nil
+		}})
+	}
+
+	if aws.ToBool(config.Spec.EBSCSIDriver) {
+		steps = append(steps, deletionStep{stage: deletionStageAddons, resources: []string{"ebs csi driver add-on"}, run: func(ctx context.Context, awsSVCs *awsServices) (bool, error) {
+			// the add-on is deleted before the stack of its role
+			deleted, err := awsservices.DeleteEBSAddon(ctx, name, awsSVCs.eks)
+			if err != nil {
+				return false, fmt.Errorf("error deleting ebs csi driver addon for config [%s (id: %s)]: %w", name, config.Name, err)
+			}
+			if !deleted {
+				logrus.Infof("Waiting for ebs csi driver addon of config [%s (id: %s)] to delete", name, config.Name)
+			}
+			return !deleted, nil
+		}})
+	}
+	if aws.ToBool(config.Spec.ContainerInsights) {
+		steps = append(steps, deletionStep{stage: deletionStageAddons, resources: []string{"container insights add-on"}, run: func(ctx context.Context, awsSVCs *awsServices) (bool, error) {
+			// nothing depends on the add-on, so it is deleted along with the cluster if it is still deleting
+			if _, err := awsservices.DeleteContainerInsightsAddon(ctx, name, awsSVCs.eks); err != nil {
+				return false, fmt.Errorf("error deleting container insights addon for config [%s (id: %s)]: %w", name, config.Name, err)
+			}
+			return false, nil
+		}})
+	}
+
+	if templateID := config.Status.ManagedLaunchTemplateID; templateID != "" {
+		steps = append(steps, deletionStep{stage: deletionStageLaunchTemplate, resources: []string{fmt.Sprintf("launch template [%s]", templateID)}, run: func(ctx context.Context, awsSVCs *awsServices) (bool, error) {
+			logrus.Infof("Deleting common launch template for config [%s (id: %s)]", name, config.Name)
+			return false, deleteLaunchTemplate(ctx, templateID, awsSVCs.ec2)
+		}})
+	}
+
+	clusterResources := []string{fmt.Sprintf("cluster [%s]", name)}
+	if len(config.Status.ClusterAdminPrincipals) != 0 {
+		// EKS deletes the access entries of the cluster along with it
+		clusterResources = append(clusterResources, fmt.Sprintf("access entries of principals [%s]", strings.Join(config.Status.ClusterAdminPrincipals, ", ")))
+	}
+	steps = append(steps, deletionStep{stage: deletionStageCluster, resources: clusterResources, run: func(ctx context.Context, awsSVCs *awsServices) (bool, error) {
+		deleted, err := deleteControlPlane(ctx, config, awsSVCs.eks)
+		if err != nil {
+			return false, fmt.Errorf("error deleting cluster [%s (id: %s)]: %w", name, config.Name, err)
+		}
+		if !deleted {
+			logrus.Infof("Waiting for control plane of config [%s (id: %s)] to delete", name, config.Name)
+		}
+		return !deleted, nil
+	}})
+
+	steps = append(steps, getStackDeletionSteps(config, force)...)
+
+	if providerARN := config.Status.OIDCProviderARN; providerARN != "" {
+		steps = append(steps, deletionStep{stage: deletionStageOIDCProvider, resources: []string{fmt.Sprintf("oidc provider [%s]", providerARN)}, run: func(ctx context.Context, awsSVCs *awsServices) (bool, error) {
+			logrus.Infof("Deleting oidc provider [%s] for config [%s (id: %s)]", providerARN, name, config.Name)
+			if err := awsservices.DeleteOIDCProvider(ctx, awsSVCs.iam, providerARN); err != nil {
+				return false, fmt.Errorf("error deleting oidc provider for config [%s (id: %s)]: %w", name, config.Name, err)
+			}
+			return false, nil
+		}})
+	}
+
+	if auditLogging := config.Status.AuditLogging; auditLogging != nil {
+		var resources []string
+		if auditLogging.AlarmThreshold != 0 {
+			resources = append(resources, fmt.Sprintf("alarm [%s]", awsservices.AuditResourceName(name)))
+		}
+		resources = append(resources, fmt.Sprintf("metric filter [%s]", awsservices.AuditResourceName(name)))
+		steps = append(steps, deletionStep{stage: deletionStageLogGroup, resources: resources, run: func(ctx context.Context, awsSVCs *awsServices) (bool, error) {
+			if err := awsservices.DeleteAuditLogging(ctx, &awsservices.DeleteAuditLoggingOpts{
+				CloudWatchLogsService: awsSVCs.logs,
+				CloudWatchService:     awsSVCs.cloudwatch,
+				ClusterName:           name,
+			}); err != nil {
+				return false, fmt.Errorf("error deleting audit logging for config [%s (id: %s)]: %w", name, config.Name, err)
+			}
+			return false, nil
+		}})
+	}
+	// the log group is deleted after the control plane, which would otherwise recreate it while it is sending logs
+	if aws.ToBool(config.Spec.DeleteLogGroup) {
+		logGroup := awsservices.ClusterLogGroupName(name)
+		steps = append(steps, deletionStep{stage: deletionStageLogGroup, resources: []string{fmt.Sprintf("log group [%s]", logGroup)}, run: func(ctx context.Context, awsSVCs *awsServices) (bool, error) {
+			logrus.Infof("Deleting log group [%s] for config [%s (id: %s)]", logGroup, name, config.Name)
+			if err := awsservices.DeleteClusterLogGroup(ctx, awsSVCs.logs, name); err != nil {
+				return false, fmt.Errorf("error deleting log group for config [%s (id: %s)]: %w", name, config.Name, err)
+			}
+			return false, nil
+		}})
+	}
+
+	return steps
+}
+
+// getStackDeletionSteps returns the steps that remove the cluster tags from the provided network resources if requested
+// and delete the CloudFormation stacks and IAM roles the operator created for the cluster. Stacks are only waited on
+// when force is set, so that the resources that fail to delete can be retained.
+func getStackDeletionSteps(config *eksv1.EKSClusterConfig, force bool) []deletionStep {
+	name := config.Spec.DisplayName
+	var steps []deletionStep
+	stackStep := func(resources []string, run func(ctx context.Context, awsSVCs *awsServices) (bool, error)) {
+		steps = append(steps, deletionStep{stage: deletionStageStacks, resources: resources, run: run})
+	}
+
+	if aws.ToBool(config.Spec.CleanupClusterTags) && len(config.Spec.Subnets) != 0 {
+		stackStep([]string{"cluster tags of the provided subnets and security groups"}, func(ctx context.Context, awsSVCs *awsServices) (bool, error) {
+			logrus.Infof("Removing cluster tags from provided subnets and security groups for config [%s (id: %s)]", name, config.Name)
+			return false, removeClusterTags(ctx, config, awsSVCs.ec2)
+		})
+	}
+
+	if aws.ToBool(config.Spec.EBSCSIDriver) {
+		// the pod identity role stack may exist without being recorded, e.g. if installing the add-on failed after it
+		// was created, and deleting a stack that doesn't exist is a no-op
+		stackNames := []string{getEBSCSIDriverRoleStackName(name), getEBSCSIDriverPodIdentityRoleStackName(name)}
+		stackStep(stackResources(stackNames...), func(ctx context.Context, awsSVCs *awsServices) (bool, error) {
+			logrus.Infof("Deleting ebs csi driver role for config [%s (id: %s)]", name, config.Name)
+			return deleteStacks(ctx, config, awsSVCs, force, stackNames...)
+		})
+	}
+
+	// roles created through IAM when CloudFormation was unavailable are deleted through IAM as well
+	native := config.Status.ProvisioningBackend == awsservices.ProvisioningBackendNative
+	if aws.ToString(config.Spec.ServiceRole) == "" {
+		roleName := getServiceRoleName(name)
+		if native {
+			stackStep([]string{fmt.Sprintf("IAM role [%s]", roleName)}, func(ctx context.Context, awsSVCs *awsServices) (bool, error) {
+				logrus.Infof("Deleting service role for config [%s (id: %s)]", name, config.Name)
+				return false, awsservices.DeleteRole(ctx, &awsservices.DeleteRoleOpts{IAMService: awsSVCs.iam, RoleName: roleName})
+			})
+		} else {
+			stackStep(stackResources(roleName), func(ctx context.Context, awsSVCs *awsServices) (bool, error) {
+				logrus.Infof("Deleting service role for config [%s (id: %s)]", name, config.Name)
+				return deleteStacks(ctx, config, awsSVCs, force, roleName)
+			})
+		}
+	}
+
+	if len(config.Spec.Subnets) == 0 {
+		stackName := getVPCStackName(name)
+		stackStep(stackResources(stackName), func(ctx context.Context, awsSVCs *awsServices) (bool, error) {
+			logrus.Infof("Deleting vpc, subnets, and security groups for config [%s (id: %s)]", name, config.Name)
+			return deleteStacks(ctx, config, awsSVCs, force, stackName)
+		})
+	}
+
+	roleARN := config.Status.GeneratedNodeRole
+	if !native {
+		stackName := getNodeInstanceRoleStackName(name)
+		stackStep(stackResources(stackName), func(ctx context.Context, awsSVCs *awsServices) (bool, error) {
+			logrus.Infof("Deleting node instance role for config [%s (id: %s)]", name, config.Name)
+			return deleteStacks(ctx, config, awsSVCs, force, stackName)
+		})
+	} else if roleARN != "" {
+		roleName := roleARN[strings.LastIndex(roleARN, "/")+1:]
+		stackStep([]string{fmt.Sprintf("IAM role [%s]", roleName)}, func(ctx context.Context, awsSVCs *awsServices) (bool, error) {
+			logrus.Infof("Deleting node instance role for config [%s (id: %s)]", name, config.Name)
+			return false, awsservices.DeleteRole(ctx, &awsservices.DeleteRoleOpts{IAMService: awsSVCs.iam, RoleName: roleName})
+		})
+	}
+
+	return steps
+}
+
+// stackResources returns the stacks with the given names as they are listed in the deletion plan.
+func stackResources(stackNames ...string) []string {
+	resources := make([]string, 0, len(stackNames))
+	for _, stackName := range stackNames {
+		resources = append(resources, fmt.Sprintf("stack [%s]", stackName))
+	}
+	return resources
+}
+
+// getDeletionPlan returns the AWS resources the teardown of the cluster deletes, in the order of the deletion stages.
+// It only depends on the config, so resources that are already gone upstream are listed as well.
+func getDeletionPlan(config *eksv1.EKSClusterConfig) []string {
+	if config.Spec.Imported || config.Spec.Paused || config.Spec.DeletionPolicy == deletionPolicyRetain || neverCreated(config) {
+		return nil
+	}
+
+	var resources []string
+	for _, step := range getDeletionSteps(config) {
+		resources = append(resources, step.resources...)
+	}
+	return resources
+}
+
+// runDeletionStage runs the steps of the current teardown stage of the cluster and returns the stage to continue with
+// and whether the current stage is waiting for upstream resources to be deleted. A stage that is waiting is run again
+// once it is requeued. Every stage can be repeated, so the teardown can resume from the recorded stage after a restart.
+func runDeletionStage(ctx context.Context, config *eksv1.EKSClusterConfig, awsSVCs *awsServices) (string, bool, error) {
+	stage := config.Status.DeletionStage
+	index := slices.Index(deletionStages, stage)
+	if index == -1 {
+		return deletionStageDone, false, nil
+	}
+
+	var waiting bool
+	for _, step := range getDeletionSteps(config) {
+		if step.stage != stage {
+			continue
+		}
+		stepWaiting, err := step.run(ctx, awsSVCs)
+		if err != nil {
+			return stage, false, err
+		}
+		waiting = waiting || stepWaiting
+	}
+	if waiting {
+		return stage, true, nil
+	}
+	if index == len(deletionStages)-1 {
+		return deletionStageDone, false, nil
+	}
+	return deletionStages[index+1], false, nil
+}
+
+// deleteControlPlane starts the deletion of the cluster if it isn't deleting yet and returns whether it is gone.
+func deleteControlPlane(ctx context.Context, config *eksv1.EKSClusterConfig, eksService services.EKSServiceInterface) (bool, error) {
+	clusterState, err := eksService.DescribeCluster(ctx, &eks.DescribeClusterInput{
+		Name: aws.String(config.Spec.DisplayName),
+	})
+	if err != nil {
+		if notFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	if clusterState.Cluster != nil && clusterState.Cluster.Status == ekstypes.ClusterStatusDeleting {
+		return false, nil
+	}
+
+	logrus.Infof("Starting control plane deletion for config [%s (id: %s)]", config.Spec.DisplayName, config.Name)
+	_, err = eksService.DeleteCluster(ctx, &eks.DeleteClusterInput{
+		Name: aws.String(config.Spec.DisplayName),
+	})
+	if err != nil {
+		if notFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+
+	return false, nil
+}
+
+// deleteStacks deletes the stacks recorded under the given canonical names and returns whether any of them are still
+// deleting, which is only waited on when force is set.
+func deleteStacks(ctx context.Context, config *eksv1.EKSClusterConfig, awsSVCs *awsServices, force bool, stackNames ...string) (bool, error) {
+	var waitingForStackDeletion bool
+	for _, name := range stackNames {
+		if !force {
+			if err := deleteStack(ctx, awsSVCs.cloudformation, config, name); err != nil {
+				return false, fmt.Errorf("error deleting stack [%s]: %w", name, err)
+			}
+			continue
+		}
+
+		deleted, err := forceDeleteStack(ctx, awsSVCs.cloudformation, config, name)
+		if err != nil {
+			return false, fmt.Errorf("error deleting stack [%s]: %w", name, err)
+		}
+		waitingForStackDeletion = waitingForStackDeletion || !deleted
+	}
+	return waitingForStackDeletion, nil
+}
+
+// forceDeleteStack deletes the stack recorded under the given canonical name and returns whether it is gone. A stack
+// that failed to delete is deleted again, retaining the resources that failed to delete.
+func forceDeleteStack(ctx context.Context, svc services.CloudFormationServiceInterface, config *eksv1.EKSClusterConfig, name string) (bool, error) {
+	stackName := name
+	if stackID := recordedStackID(config, name); stackID != "" {
+		stackName = stackID
+	}
+
+	output, err := svc.DescribeStacks(ctx, &cloudformation.DescribeStacksInput{
+		StackName: aws.String(stackName),
+	})
+	if err != nil {
+		if doesNotExist(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	if len(output.Stacks) == 0 {
+		return true, nil
+	}
+
+	input := &cloudformation.DeleteStackInput{
+		StackName: aws.String(stackName),
+	}
+	switch output.Stacks[0].StackStatus {
+	case cftypes.StackStatusDeleteComplete:
+		return true, nil
+	case cftypes.StackStatusDeleteInProgress:
+		return false, nil
+	case cftypes.StackStatusDeleteFailed:
+		resources, err := svc.DescribeStackResources(ctx, &cloudformation.DescribeStackResourcesInput{
+			StackName: aws.String(stackName),
+		})
+		if err != nil {
+			return false, err
+		}
+		for _, resource := range resources.StackResources {
+			if resource.ResourceStatus == cftypes.ResourceStatusDeleteFailed {
+				input.RetainResources = append(input.RetainResources, aws.ToString(resource.LogicalResourceId))
+			}
+		}
+		logrus.Warnf("Stack [%s] of cluster [%s (id: %s)] failed to delete, retaining resources %v",
+			name, config.Spec.DisplayName, config.Name, input.RetainResources)
+	}
+
+	if _, err := svc.DeleteStack(ctx, input); err != nil && !doesNotExist(err) {
+		return false, err
+	}
+	return false, nil
+}
@@ -0,0 +1,419 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	cftypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/golang/mock/gomock"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	awsservices "github.com/rancher/eks-operator/pkg/eks"
+	"github.com/rancher/eks-operator/pkg/eks/services/mock_services"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestHasCleanupFinalizer(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{}
+	assert.False(t, hasCleanupFinalizer(config))
+
+	config.Finalizers = []string{"other"}
+	assert.False(t, hasCleanupFinalizer(config))
+
+	config.Finalizers = []string{"other", cleanupFinalizer}
+	assert.True(t, hasCleanupFinalizer(config))
+
+	config.Finalizers = []string{legacyCleanupFinalizer}
+	assert.True(t, hasCleanupFinalizer(config))
+}
+
+func TestValidateDeletionPolicy(t *testing.T) {
+	for _, policy := range []string{"", deletionPolicyDelete, deletionPolicyRetain, deletionPolicyForce} {
+		config := &eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{DeletionPolicy: policy}}
+		assert.NoError(t, validateDeletionPolicy(config))
+	}
+
+	config := &eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{DeletionPolicy: "orphan"}}
+	assert.Error(t, validateDeletionPolicy(config))
+}
+
+func TestRunDeletionStage(t *testing.T) {
+	newConfig := func(stage, policy string) *eksv1.EKSClusterConfig {
+		return &eksv1.EKSClusterConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "test"},
+			Spec: eksv1.EKSClusterConfigSpec{
+				DisplayName:    "test",
+				DeletionPolicy: policy,
+				ServiceRole:    aws.String("role"),
+				Subnets:        []string{"subnet"},
+				NodeGroups:     []eksv1.NodeGroup{{NodegroupName: aws.String("ng1")}},
+			},
+			Status: eksv1.EKSClusterConfigStatus{Phase: eksv1.PhaseActive, DeletionStage: stage},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		stage           string
+		policy          string
+		mockCalls       func(eksMock *mock_services.MockEKSServiceInterface, cfMock *mock_services.MockCloudFormationServiceInterface)
+		expectedStage   string
+		expectedWaiting bool
+		expectedErr     bool
+	}{
+		{
+			name:  "node groups still deleting",
+			stage: deletionStageNodeGroups,
+			mockCalls: func(eksMock *mock_services.MockEKSServiceInterface, _ *mock_services.MockCloudFormationServiceInterface) {
+				eksMock.EXPECT().DescribeNodegroup(gomock.Any(), gomock.Any()).Return(&eks.DescribeNodegroupOutput{
+					Nodegroup: &ekstypes.Nodegroup{Status: ekstypes.NodegroupStatusDeleting},
+				}, nil)
+			},
+			expectedStage:   deletionStageNodeGroups,
+			expectedWaiting: true,
+		},
+		{
+			name:  "node groups deleted",
+			stage: deletionStageNodeGroups,
+			mockCalls: func(eksMock *mock_services.MockEKSServiceInterface, _ *mock_services.MockCloudFormationServiceInterface) {
+				eksMock.EXPECT().DescribeNodegroup(gomock.Any(), gomock.Any()).Return(nil, &ekstypes.ResourceNotFoundException{})
+			},
+			expectedStage: deletionStageAddons,
+		},
+		{
+			name:  "node group failed to delete",
+			stage: deletionStageNodeGroups,
+			mockCalls: func(eksMock *mock_services.MockEKSServiceInterface, _ *mock_services.MockCloudFormationServiceInterface) {
+				eksMock.EXPECT().DescribeNodegroup(gomock.Any(), gomock.Any()).Return(&eks.DescribeNodegroupOutput{
+					Nodegroup: &ekstypes.Nodegroup{Status: ekstypes.NodegroupStatusDeleteFailed},
+				}, nil)
+				eksMock.EXPECT().DeleteNodegroup(gomock.Any(), gomock.Any()).Return(&eks.DeleteNodegroupOutput{}, nil)
+			},
+			expectedStage:   deletionStageNodeGroups,
+			expectedWaiting: true,
+		},
+		{
+			name:   "node group failed to delete with force policy",
+			stage:  deletionStageNodeGroups,
+			policy: deletionPolicyForce,
+			mockCalls: func(eksMock *mock_services.MockEKSServiceInterface, _ *mock_services.MockCloudFormationServiceInterface) {
+				eksMock.EXPECT().DescribeNodegroup(gomock.Any(), gomock.Any()).Return(&eks.DescribeNodegroupOutput{
+					Nodegroup: &ekstypes.Nodegroup{Status: ekstypes.NodegroupStatusDeleteFailed},
+				}, nil)
+			},
+			expectedStage: deletionStageAddons,
+		},
+		{
+			name:   "node group deleting with force policy",
+			stage:  deletionStageNodeGroups,
+			policy: deletionPolicyForce,
+			mockCalls: func(eksMock *mock_services.MockEKSServiceInterface, _ *mock_services.MockCloudFormationServiceInterface) {
+				eksMock.EXPECT().DescribeNodegroup(gomock.Any(), gomock.Any()).Return(&eks.DescribeNodegroupOutput{
+					Nodegroup: &ekstypes.Nodegroup{Status: ekstypes.NodegroupStatusDeleting},
+				}, nil)
+			},
+			expectedStage:   deletionStageNodeGroups,
+			expectedWaiting: true,
+		},
+		{
+			name:   "node group started deleting with force policy",
+			stage:  deletionStageNodeGroups,
+			policy: deletionPolicyForce,
+			mockCalls: func(eksMock *mock_services.MockEKSServiceInterface, _ *mock_services.MockCloudFormationServiceInterface) {
+				eksMock.EXPECT().DescribeNodegroup(gomock.Any(), gomock.Any()).Return(&eks.DescribeNodegroupOutput{
+					Nodegroup: &ekstypes.Nodegroup{Status: ekstypes.NodegroupStatusActive},
+				}, nil)
+				eksMock.EXPECT().DeleteNodegroup(gomock.Any(), gomock.Any()).Return(nil, &ekstypes.ResourceInUseException{})
+			},
+			expectedStage:   deletionStageNodeGroups,
+			expectedWaiting: true,
+		},
+		{
+			name:  "node group deletion fails",
+			stage: deletionStageNodeGroups,
+			mockCalls: func(eksMock *mock_services.MockEKSServiceInterface, _ *mock_services.MockCloudFormationServiceInterface) {
+				eksMock.EXPECT().DescribeNodegroup(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+			},
+			expectedStage: deletionStageNodeGroups,
+			expectedErr:   true,
+		},
+		{
+			name:          "no ebs csi driver addon",
+			stage:         deletionStageAddons,
+			mockCalls:     func(_ *mock_services.MockEKSServiceInterface, _ *mock_services.MockCloudFormationServiceInterface) {},
+			expectedStage: deletionStageLaunchTemplate,
+		},
+		{
+			name:          "no managed launch template",
+			stage:         deletionStageLaunchTemplate,
+			mockCalls:     func(_ *mock_services.MockEKSServiceInterface, _ *mock_services.MockCloudFormationServiceInterface) {},
+			expectedStage: deletionStageCluster,
+		},
+		{
+			name:  "cluster deletion started",
+			stage: deletionStageCluster,
+			mockCalls: func(eksMock *mock_services.MockEKSServiceInterface, _ *mock_services.MockCloudFormationServiceInterface) {
+				eksMock.EXPECT().DescribeCluster(gomock.Any(), gomock.Any()).Return(&eks.DescribeClusterOutput{
+					Cluster: &ekstypes.Cluster{Status: ekstypes.ClusterStatusActive},
+				}, nil)
+				eksMock.EXPECT().DeleteCluster(gomock.Any(), &eks.DeleteClusterInput{Name: aws.String("test")}).Return(&eks.DeleteClusterOutput{}, nil)
+			},
+			expectedStage:   deletionStageCluster,
+			expectedWaiting: true,
+		},
+		{
+			name:  "cluster still deleting",
+			stage: deletionStageCluster,
+			mockCalls: func(eksMock *mock_services.MockEKSServiceInterface, _ *mock_services.MockCloudFormationServiceInterface) {
+				eksMock.EXPECT().DescribeCluster(gomock.Any(), gomock.Any()).Return(&eks.DescribeClusterOutput{
+					Cluster: &ekstypes.Cluster{Status: ekstypes.ClusterStatusDeleting},
+				}, nil)
+			},
+			expectedStage:   deletionStageCluster,
+			expectedWaiting: true,
+		},
+		{
+			name:  "cluster deleted",
+			stage: deletionStageCluster,
+			mockCalls: func(eksMock *mock_services.MockEKSServiceInterface, _ *mock_services.MockCloudFormationServiceInterface) {
+				eksMock.EXPECT().DescribeCluster(gomock.Any(), gomock.Any()).Return(nil, &ekstypes.ResourceNotFoundException{})
+			},
+			expectedStage: deletionStageStacks,
+		},
+		{
+			name:  "stacks deleted",
+			stage: deletionStageStacks,
+			mockCalls: func(_ *mock_services.MockEKSServiceInterface, cfMock *mock_services.MockCloudFormationServiceInterface) {
+				cfMock.EXPECT().DeleteStack(gomock.Any(), &cloudformation.DeleteStackInput{
+					StackName: aws.String(getNodeInstanceRoleStackName("test")),
+				}).Return(&cloudformation.DeleteStackOutput{}, nil)
+			},
+			expectedStage: deletionStageOIDCProvider,
+		},
+		{
+			name:   "stack failed to delete with force policy",
+			stage:  deletionStageStacks,
+			policy: deletionPolicyForce,
+			mockCalls: func(_ *mock_services.MockEKSServiceInterface, cfMock *mock_services.MockCloudFormationServiceInterface) {
+				stackName := aws.String(getNodeInstanceRoleStackName("test"))
+				cfMock.EXPECT().DescribeStacks(gomock.Any(), gomock.Any()).Return(&cloudformation.DescribeStacksOutput{
+					Stacks: []cftypes.Stack{{StackName: stackName, StackStatus: cftypes.StackStatusDeleteFailed}},
+				}, nil)
+				cfMock.EXPECT().DescribeStackResources(gomock.Any(), gomock.Any()).Return(&cloudformation.DescribeStackResourcesOutput{
+					StackResources: []cftypes.StackResource{
+						{LogicalResourceId: aws.String("NodeInstanceRole"), ResourceStatus: cftypes.ResourceStatusDeleteFailed},
+						{LogicalResourceId: aws.String("NodeInstanceProfile"), ResourceStatus: cftypes.ResourceStatusDeleteComplete},
+					},
+				}, nil)
+				cfMock.EXPECT().DeleteStack(gomock.Any(), &cloudformation.DeleteStackInput{
+					StackName:       stackName,
+					RetainResources: []string{"NodeInstanceRole"},
+				}).Return(&cloudformation.DeleteStackOutput{}, nil)
+			},
+			expectedStage:   deletionStageStacks,
+			expectedWaiting: true,
+		},
+		{
+			name:   "stacks deleted with force policy",
+			stage:  deletionStageStacks,
+			policy: deletionPolicyForce,
+			mockCalls: func(_ *mock_services.MockEKSServiceInterface, cfMock *mock_services.MockCloudFormationServiceInterface) {
+				cfMock.EXPECT().DescribeStacks(gomock.Any(), gomock.Any()).Return(nil, errors.New("Stack with id test does not exist"))
+			},
+			expectedStage: deletionStageOIDCProvider,
+		},
+		{
+			name:          "no oidc provider",
+			stage:         deletionStageOIDCProvider,
+			mockCalls:     func(_ *mock_services.MockEKSServiceInterface, _ *mock_services.MockCloudFormationServiceInterface) {},
+			expectedStage: deletionStageLogGroup,
+		},
+		{
+			name:          "log group kept",
+			stage:         deletionStageLogGroup,
+			mockCalls:     func(_ *mock_services.MockEKSServiceInterface, _ *mock_services.MockCloudFormationServiceInterface) {},
+			expectedStage: deletionStageDone,
+		},
+		{
+			name:  "stack deletion fails",
+			stage: deletionStageStacks,
+			mockCalls: func(_ *mock_services.MockEKSServiceInterface, cfMock *mock_services.MockCloudFormationServiceInterface) {
+				cfMock.EXPECT().DeleteStack(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+			},
+			expectedStage: deletionStageStacks,
+			expectedErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := gomock.NewController(t)
+			eksServiceMock := mock_services.NewMockEKSServiceInterface(mockController)
+			cloudFormationServiceMock := mock_services.NewMockCloudFormationServiceInterface(mockController)
+			tt.mockCalls(eksServiceMock, cloudFormationServiceMock)
+
+			stage, waiting, err := runDeletionStage(context.Background(), newConfig(tt.stage, tt.policy), &awsServices{
+				eks:            eksServiceMock,
+				cloudformation: cloudFormationServiceMock,
+			})
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedStage, stage)
+			assert.Equal(t, tt.expectedWaiting, waiting)
+		})
+	}
+}
+
+func TestRunDeletionStageNativeRoles(t *testing.T) {
+	ctx := context.Background()
+	mockController := gomock.NewController(t)
+	cfMock := mock_services.NewMockCloudFormationServiceInterface(mockController)
+	iamMock := mock_services.NewMockIAMServiceInterface(mockController)
+	awsSVCs := &awsServices{cloudformation: cfMock, iam: iamMock}
+
+	config := &eksv1.EKSClusterConfig{
+		Spec: eksv1.EKSClusterConfigSpec{DisplayName: "test", Subnets: []string{"subnet-1"}},
+		Status: eksv1.EKSClusterConfigStatus{
+			ProvisioningBackend: awsservices.ProvisioningBackendNative,
+			GeneratedNodeRole:   "arn:aws:iam::123456789012:role/test-node-instance-role",
+			DeletionStage:       deletionStageStacks,
+		},
+	}
+	// the roles are deleted through IAM and no stacks are deleted
+	for _, roleName := range []string{"test-eks-service-role", "test-node-instance-role"} {
+		iamMock.EXPECT().ListAttachedRolePolicies(ctx, &iam.ListAttachedRolePoliciesInput{RoleName: aws.String(roleName)}).Return(
+			&iam.ListAttachedRolePoliciesOutput{}, nil)
+		iamMock.EXPECT().DeleteRole(ctx, &iam.DeleteRoleInput{RoleName: aws.String(roleName)}).Return(&iam.DeleteRoleOutput{}, nil)
+	}
+
+	stage, waiting, err := runDeletionStage(ctx, config, awsSVCs)
+	assert.NoError(t, err)
+	assert.False(t, waiting)
+	assert.Equal(t, deletionStageOIDCProvider, stage)
+
+	// the roles were created before the cluster, which isn't deleted if the config is deleted before it was created
+	assert.False(t, neverCreated(config))
+	config.Status.DeletionStage = deletionStageCluster
+	stage, waiting, err = runDeletionStage(ctx, config, awsSVCs)
+	assert.NoError(t, err)
+	assert.False(t, waiting)
+	assert.Equal(t, deletionStageStacks, stage)
+}
+
+func TestDeletionSlots(t *testing.T) {
+	slots := newDeletionSlots(2)
+	assert.True(t, slots.acquire("ns/a"))
+	assert.True(t, slots.acquire("ns/b"))
+	assert.False(t, slots.acquire("ns/c"))
+	assert.True(t, slots.acquire("ns/a"), "clusters keep their slot until they release it")
+
+	slots.release("ns/a")
+	slots.release("ns/a")
+	assert.True(t, slots.acquire("ns/c"))
+	assert.False(t, slots.acquire("ns/a"))
+
+	// deletions aren't limited without a maximum
+	slots = newDeletionSlots(0)
+	for _, key := range []string{"ns/a", "ns/b", "ns/c"} {
+		assert.True(t, slots.acquire(key))
+	}
+	var nilSlots *deletionSlots
+	assert.True(t, nilSlots.acquire("ns/a"))
+	nilSlots.release("ns/a")
+}
+
+func TestGetDeletionPlan(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{
+		Spec: eksv1.EKSClusterConfigSpec{
+			DisplayName:       "test",
+			EBSCSIDriver:      aws.Bool(true),
+			ContainerInsights: aws.Bool(true),
+			DeleteLogGroup:    aws.Bool(true),
+			NodeGroups:        []eksv1.NodeGroup{{NodegroupName: aws.String("ng1")}},
+		},
+		Status: eksv1.EKSClusterConfigStatus{
+			Phase:                   eksv1.PhaseActive,
+			ClusterAdminPrincipals:  []string{"arn:aws:iam::123456789012:role/admin"},
+			ManagedLaunchTemplateID: "lt-123",
+			OIDCProviderARN:         "arn:aws:iam::123456789012:oidc-provider/oidc.eks.us-west-2.amazonaws.com/id/ABC",
+			AuditLogging:            &eksv1.AuditLogging{AlarmThreshold: 1},
+		},
+	}
+	assert.Equal(t, []string{
+		"node group [ng1]",
+		"ebs csi driver add-on",
+		"container insights add-on",
+		"launch template [lt-123]",
+		"cluster [test]",
+		"access entries of principals [arn:aws:iam::123456789012:role/admin]",
+		"stack [test-ebs-csi-driver-role]",
+		"stack [test-ebs-csi-driver-pod-identity-role]",
+		"stack [test-eks-service-role]",
+		"stack [test-eks-vpc]",
+		"stack [test-node-instance-role]",
+		"oidc provider [arn:aws:iam::123456789012:oidc-provider/oidc.eks.us-west-2.amazonaws.com/id/ABC]",
+		"alarm [test-audit-events]",
+		"metric filter [test-audit-events]",
+		"log group [/aws/eks/test/cluster]",
+	}, getDeletionPlan(config))
+
+	// provided resources and roles created through IAM
+	config.Spec = eksv1.EKSClusterConfigSpec{
+		DisplayName:        "test",
+		ServiceRole:        aws.String("provided"),
+		Subnets:            []string{"subnet-a"},
+		CleanupClusterTags: aws.Bool(true),
+	}
+	config.Status = eksv1.EKSClusterConfigStatus{
+		Phase:               eksv1.PhaseActive,
+		ProvisioningBackend: awsservices.ProvisioningBackendNative,
+		GeneratedNodeRole:   "arn:aws:iam::123456789012:role/test-node-instance-role",
+	}
+	assert.Equal(t, []string{
+		"cluster [test]",
+		"cluster tags of the provided subnets and security groups",
+		"IAM role [test-node-instance-role]",
+	}, getDeletionPlan(config))
+
+	config.Spec.DeletionPolicy = deletionPolicyRetain
+	assert.Empty(t, getDeletionPlan(config))
+	config.Spec.DeletionPolicy = ""
+	config.Status.Phase = eksv1.PhaseNotCreated
+	config.Status.ProvisioningBackend = ""
+	config.Status.GeneratedNodeRole = ""
+	assert.Empty(t, getDeletionPlan(config), "clusters that were never created aren't deleted")
+
+	// the stacks created before the cluster are deleted if the config is deleted meanwhile
+	config.Spec = eksv1.EKSClusterConfigSpec{DisplayName: "test", EBSCSIDriver: aws.Bool(true)}
+	config.Status.CloudFormationStacks = []eksv1.CloudFormationStack{
+		{Name: "test-eks-vpc", Status: "CREATE_COMPLETE"},
+		{Name: "test-eks-service-role", Status: "CREATE_IN_PROGRESS"},
+	}
+	assert.Equal(t, []string{
+		"stack [test-ebs-csi-driver-role]",
+		"stack [test-ebs-csi-driver-pod-identity-role]",
+		"stack [test-eks-service-role]",
+		"stack [test-eks-vpc]",
+		"stack [test-node-instance-role]",
+	}, getDeletionPlan(config))
+}
+
+func TestNeverCreated(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{Status: eksv1.EKSClusterConfigStatus{Phase: eksv1.PhaseNotCreated}}
+	assert.True(t, neverCreated(config))
+	config.Status.CloudFormationStacks = []eksv1.CloudFormationStack{{Name: "test-eks-vpc", Status: "CREATE_IN_PROGRESS"}}
+	assert.False(t, neverCreated(config), "stacks are created before the cluster")
+	config.Status = eksv1.EKSClusterConfigStatus{Phase: eksv1.PhaseNotCreated, ProvisioningBackend: awsservices.ProvisioningBackendNative}
+	assert.False(t, neverCreated(config), "roles are created before the cluster")
+	config.Status = eksv1.EKSClusterConfigStatus{Phase: eksv1.PhaseCreating}
+	assert.False(t, neverCreated(config))
+}
@@ -0,0 +1,176 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services"
+)
+
+const (
+	// how long the nodes of a node group are drained before it is deleted anyway when no timeout is configured
+	defaultNodeGroupDrainTimeout = 15 * time.Minute
+	// how often the drain of node groups is checked on while pods are being evicted
+	nodeGroupDrainInterval = 30 * time.Second
+
+	nodeGroupLabel = "eks.amazonaws.com/nodegroup"
+)
+
+// drainNodeGroups cordons and drains the nodes of the node groups that are about to be deleted and returns the names of
+// the ones that are still being drained, which must not be deleted yet, and whether the drain start times on the status
+// changed. Node groups are drained until all their pods are evicted or the drain timeout elapses, draining errors are
+// only logged so that they can't block the deletion for longer than the timeout.
+func (h *Handler) drainNodeGroups(ctx context.Context, config *eksv1.EKSClusterConfig, nodeGroups []eksv1.NodeGroup, awsSVCs *awsServices) (map[string]struct{}, bool) {
+	var changed bool
+	toDelete := make(map[string]struct{}, len(nodeGroups))
+	for _, ng := range nodeGroups {
+		toDelete[aws.ToString(ng.NodegroupName)] = struct{}{}
+	}
+	for name := range config.Status.NodeGroupDrainStartTimes {
+		if _, ok := toDelete[name]; !ok {
+			delete(config.Status.NodeGroupDrainStartTimes, name)
+			changed = true
+		}
+	}
+	if config.Spec.NodeGroupDrain == nil || len(nodeGroups) == 0 {
+		return nil, changed
+	}
+
+	timeout := defaultNodeGroupDrainTimeout
+	if config.Spec.NodeGroupDrain.Timeout != "" {
+		// the timeout is validated, an invalid one falls back to the default
+		if parsed, err := time.ParseDuration(config.Spec.NodeGroupDrain.Timeout); err == nil {
+			timeout = parsed
+		}
+	}
+
+	var client kubernetes.Interface
+	draining := make(map[string]struct{})
+	now := time.Now()
+	for _, ng := range nodeGroups {
+		name := aws.ToString(ng.NodegroupName)
+		startTime, err := time.Parse(time.RFC3339, config.Status.NodeGroupDrainStartTimes[name])
+		if err != nil {
+			if config.Status.NodeGroupDrainStartTimes == nil {
+				config.Status.NodeGroupDrainStartTimes = make(map[string]string)
+			}
+			startTime = now
+			config.Status.NodeGroupDrainStartTimes[name] = now.UTC().Format(time.RFC3339)
+			changed = true
+			h.recordEvent(config, corev1.EventTypeNormal, eventReasonNodegroupDraining, "Draining node group [%s]", name)
+		}
+		if now.Sub(startTime) >= timeout {
+			logrus.Warnf("Timed out draining node group [%s] of cluster [%s (id: %s)], deleting it", name, config.Spec.DisplayName, config.Name)
+			continue
+		}
+
+		var drained bool
+		if client == nil {
+			client, err = h.downstreamClient(ctx, config, awsSVCs)
+		}
+		if client != nil {
+			drained, err = drainNodeGroup(ctx, client, name)
+		}
+		if err != nil {
+			logrus.Warnf("Error draining node group [%s] of cluster [%s (id: %s)]: %v", name, config.Spec.DisplayName, config.Name, err)
+		}
+		if !drained {
+			draining[name] = struct{}{}
+		}
+	}
+
+	if len(draining) != 0 {
+		h.eksEnqueueAfter(config.Namespace, config.Name, nodeGroupDrainInterval)
+	}
+	return draining, changed
+}
+
+// drainNodeGroup cordons the nodes of the node group and requests the eviction of their pods, returning whether no
+// pods are left to evict. Evictions rejected because of a pod disruption budget are requested again on the next call.
+// In dry run mode the cordons and evictions are only logged and the node group is reported as drained, as its deletion
+// is skipped as well.
+func drainNodeGroup(ctx context.Context, client kubernetes.Interface, name string) (bool, error) {
+	dryRun := services.DryRunEnabled()
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", nodeGroupLabel, name)})
+	if err != nil {
+		return false, fmt.Errorf("error listing nodes: %w", err)
+	}
+
+	drained := true
+	for _, node := range nodes.Items {
+		if !node.Spec.Unschedulable {
+			if dryRun {
+				logrus.Infof("Dry run, skipping cordon of node [%s] of node group [%s]", node.Name, name)
+			} else if _, err := client.CoreV1().Nodes().Patch(ctx, node.Name, types.StrategicMergePatchType,
+				[]byte(`{"spec":{"unschedulable":true}}`), metav1.PatchOptions{}); err != nil {
+				return false, fmt.Errorf("error cordoning node [%s]: %w", node.Name, err)
+			}
+		}
+
+		pods, err := client.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{FieldSelector: "spec.nodeName=" + node.Name})
+		if err != nil {
+			return false, fmt.Errorf("error listing pods of node [%s]: %w", node.Name, err)
+		}
+		for _, pod := range pods.Items {
+			if !evictable(pod) {
+				continue
+			}
+			if dryRun {
+				logrus.Infof("Dry run, skipping eviction of pod [%s/%s] from node [%s]", pod.Namespace, pod.Name, node.Name)
+				continue
+			}
+			drained = false
+			if pod.DeletionTimestamp != nil {
+				continue
+			}
+			err := client.PolicyV1().Evictions(pod.Namespace).Evict(ctx, &policyv1.Eviction{
+				ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+			})
+			// evictions that would violate a pod disruption budget are rejected with too many requests
+			if err != nil && !apierrors.IsTooManyRequests(err) && !apierrors.IsNotFound(err) {
+				return false, fmt.Errorf("error evicting pod [%s/%s]: %w", pod.Namespace, pod.Name, err)
+			}
+		}
+	}
+	return drained, nil
+}
+
+// evictable returns whether the pod has to be evicted for its node to be drained. DaemonSet pods would be recreated on
+// the node and mirror pods can't be evicted, and pods that finished don't need to be.
+func evictable(pod corev1.Pod) bool {
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return false
+	}
+	if _, ok := pod.Annotations[corev1.MirrorPodAnnotationKey]; ok {
+		return false
+	}
+	for _, owner := range pod.OwnerReferences {
+		if owner.Kind == "DaemonSet" {
+			return false
+		}
+	}
+	return true
+}
+
+// validateNodeGroupDrain checks that the drain timeout of the config is a positive duration.
+func validateNodeGroupDrain(config *eksv1.EKSClusterConfig) error {
+	if config.Spec.NodeGroupDrain == nil || config.Spec.NodeGroupDrain.Timeout == "" {
+		return nil
+	}
+	if timeout, err := time.ParseDuration(config.Spec.NodeGroupDrain.Timeout); err != nil || timeout <= 0 {
+		return fmt.Errorf("invalid node group drain timeout [%s] for cluster [%s (id: %s)], must be a positive duration like 15m",
+			config.Spec.NodeGroupDrain.Timeout, config.Spec.DisplayName, config.Name)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services"
+)
+
+func TestDrainNodeGroup(t *testing.T) {
+	ctx := context.Background()
+	pod := func(name string, modify func(*corev1.Pod)) *corev1.Pod {
+		p := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       corev1.PodSpec{NodeName: "node-1"},
+		}
+		if modify != nil {
+			modify(p)
+		}
+		return p
+	}
+	client := fake.NewSimpleClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{nodeGroupLabel: "ng1"}}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-2", Labels: map[string]string{nodeGroupLabel: "ng2"}}},
+		pod("app", nil),
+		pod("protected", nil),
+		pod("daemon", func(p *corev1.Pod) {
+			p.OwnerReferences = []metav1.OwnerReference{{Kind: "DaemonSet", Name: "daemon"}}
+		}),
+		pod("mirror", func(p *corev1.Pod) {
+			p.Annotations = map[string]string{corev1.MirrorPodAnnotationKey: "mirror"}
+		}),
+		pod("job", func(p *corev1.Pod) {
+			p.Status.Phase = corev1.PodSucceeded
+		}),
+	)
+	var evicted []string
+	client.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		eviction := action.(k8stesting.CreateAction).GetObject().(*policyv1.Eviction)
+		if eviction.Name == "protected" {
+			return true, nil, apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 10)
+		}
+		evicted = append(evicted, eviction.Name)
+		return true, nil, nil
+	})
+
+	drained, err := drainNodeGroup(ctx, client, "ng1")
+	require.NoError(t, err)
+	assert.False(t, drained)
+	assert.Equal(t, []string{"app"}, evicted)
+	node, err := client.CoreV1().Nodes().Get(ctx, "node-1", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.True(t, node.Spec.Unschedulable)
+	node, err = client.CoreV1().Nodes().Get(ctx, "node-2", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.False(t, node.Spec.Unschedulable, "nodes of other node groups must not be cordoned")
+
+	// the pods are gone once they were evicted
+	require.NoError(t, client.CoreV1().Pods("default").Delete(ctx, "app", metav1.DeleteOptions{}))
+	require.NoError(t, client.CoreV1().Pods("default").Delete(ctx, "protected", metav1.DeleteOptions{}))
+	drained, err = drainNodeGroup(ctx, client, "ng1")
+	require.NoError(t, err)
+	assert.True(t, drained)
+}
+
+func TestDrainNodeGroupDryRun(t *testing.T) {
+	t.Cleanup(func() { services.SetDryRun(false) })
+	services.SetDryRun(true)
+
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{nodeGroupLabel: "ng1"}}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}, Spec: corev1.PodSpec{NodeName: "node-1"}},
+	)
+
+	drained, err := drainNodeGroup(ctx, client, "ng1")
+	require.NoError(t, err)
+	assert.True(t, drained, "the deletion of the node group is skipped as well, so it isn't waited on")
+	for _, action := range client.Actions() {
+		assert.Contains(t, []string{"list", "get", "watch"}, action.GetVerb(), "nothing is cordoned or evicted: %v", action)
+	}
+	node, err := client.CoreV1().Nodes().Get(ctx, "node-1", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.False(t, node.Spec.Unschedulable)
+}
+
+func TestDrainNodeGroups(t *testing.T) {
+	var requeued time.Duration
+	h := &Handler{eksEnqueueAfter: func(_, _ string, after time.Duration) { requeued = after }}
+	config := &eksv1.EKSClusterConfig{Status: eksv1.EKSClusterConfigStatus{
+		NodeGroupDrainStartTimes: map[string]string{
+			"deleted":   time.Now().Add(-time.Minute).UTC().Format(time.RFC3339),
+			"timed-out": time.Now().Add(-time.Hour).UTC().Format(time.RFC3339),
+		},
+	}}
+	nodeGroups := []eksv1.NodeGroup{{NodegroupName: aws.String("timed-out")}}
+
+	// the start times of node groups that aren't being deleted anymore are removed even if draining is disabled
+	draining, changed := h.drainNodeGroups(context.Background(), config, nodeGroups, nil)
+	assert.Empty(t, draining)
+	assert.True(t, changed)
+	assert.NotContains(t, config.Status.NodeGroupDrainStartTimes, "deleted")
+
+	config.Spec.NodeGroupDrain = &eksv1.NodeGroupDrain{Timeout: "10m"}
+	draining, changed = h.drainNodeGroups(context.Background(), config, nodeGroups, nil)
+	assert.Empty(t, draining, "node groups are deleted once the timeout elapsed")
+	assert.False(t, changed)
+	assert.Zero(t, requeued)
+}
+
+func TestValidateNodeGroupDrain(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{}
+	assert.NoError(t, validateNodeGroupDrain(config))
+	config.Spec.NodeGroupDrain = &eksv1.NodeGroupDrain{}
+	assert.NoError(t, validateNodeGroupDrain(config))
+	config.Spec.NodeGroupDrain.Timeout = "10m"
+	assert.NoError(t, validateNodeGroupDrain(config))
+	for _, timeout := range []string{"ten minutes", "0s", "-5m"} {
+		config.Spec.NodeGroupDrain.Timeout = timeout
+		assert.Error(t, validateNodeGroupDrain(config), timeout)
+	}
+}
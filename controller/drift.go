@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	corev1 "k8s.io/api/core/v1"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/utils"
+)
+
+// getDrift returns the fields of the spec that differ from the cluster in EKS, as the updates the operator would make
+// if the config wasn't paused. Fields that are unset in the spec are left as they are upstream, so they never drift.
+func getDrift(spec, upstreamSpec *eksv1.EKSClusterConfigSpec) []string {
+	var drift []string
+	differs := func(field string, value, upstreamValue interface{}) {
+		drift = append(drift, fmt.Sprintf("%s is [%v] upstream instead of [%v]", field, upstreamValue, value))
+	}
+
+	if spec.KubernetesVersion != nil && aws.ToString(spec.KubernetesVersion) != aws.ToString(upstreamSpec.KubernetesVersion) {
+		differs("kubernetesVersion", aws.ToString(spec.KubernetesVersion), aws.ToString(upstreamSpec.KubernetesVersion))
+	}
+	if spec.Tags != nil && (utils.GetKeyValuesToUpdate(spec.Tags, upstreamSpec.Tags) != nil || utils.GetKeysToDelete(spec.Tags, upstreamSpec.Tags) != nil) {
+		differs("tags", spec.Tags, upstreamSpec.Tags)
+	}
+	boolDiffers := func(field string, value, upstreamValue *bool) {
+		if value != nil && aws.ToBool(value) != aws.ToBool(upstreamValue) {
+			differs(field, aws.ToBool(value), aws.ToBool(upstreamValue))
+		}
+	}
+	boolDiffers("publicAccess", spec.PublicAccess, upstreamSpec.PublicAccess)
+	boolDiffers("privateAccess", spec.PrivateAccess, upstreamSpec.PrivateAccess)
+	boolDiffers("ebsCSIDriver", spec.EBSCSIDriver, upstreamSpec.EBSCSIDriver)
+	boolDiffers("secretsEncryption", spec.SecretsEncryption, upstreamSpec.SecretsEncryption)
+	if spec.PublicAccessSources != nil && !utils.CompareStringSliceElements(spec.PublicAccessSources, upstreamSpec.PublicAccessSources) {
+		differs("publicAccessSources", spec.PublicAccessSources, upstreamSpec.PublicAccessSources)
+	}
+	if spec.LoggingTypes != nil && !utils.CompareStringSliceElements(spec.LoggingTypes, upstreamSpec.LoggingTypes) {
+		differs("loggingTypes", spec.LoggingTypes, upstreamSpec.LoggingTypes)
+	}
+	if aws.ToBool(spec.SecretsEncryption) && aws.ToString(spec.KmsKey) != "" && aws.ToString(spec.KmsKey) != aws.ToString(upstreamSpec.KmsKey) {
+		differs("kmsKey", aws.ToString(spec.KmsKey), aws.ToString(upstreamSpec.KmsKey))
+	}
+
+	if spec.NodeGroups == nil {
+		return drift
+	}
+	upstreamNodeGroups := make(map[string]eksv1.NodeGroup, len(upstreamSpec.NodeGroups))
+	for _, ng := range upstreamSpec.NodeGroups {
+		upstreamNodeGroups[aws.ToString(ng.NodegroupName)] = ng
+	}
+	nodeGroups := make(map[string]struct{}, len(spec.NodeGroups))
+	for _, ng := range spec.NodeGroups {
+		name := aws.ToString(ng.NodegroupName)
+		nodeGroups[name] = struct{}{}
+		upstreamNg, ok := upstreamNodeGroups[name]
+		if !ok {
+			drift = append(drift, fmt.Sprintf("node group [%s] doesn't exist upstream", name))
+			continue
+		}
+
+		field := fmt.Sprintf("nodeGroups[%s].", name)
+		if ng.Version != nil && aws.ToString(ng.Version) != aws.ToString(upstreamNg.Version) {
+			differs(field+"version", aws.ToString(ng.Version), aws.ToString(upstreamNg.Version))
+		}
+		sizeDiffers := func(sizeField string, size, upstreamSize *int32) {
+			if size != nil && aws.ToInt32(size) != aws.ToInt32(upstreamSize) {
+				differs(field+sizeField, aws.ToInt32(size), aws.ToInt32(upstreamSize))
+			}
+		}
+		sizeDiffers("desiredSize", ng.DesiredSize, upstreamNg.DesiredSize)
+		sizeDiffers("minSize", ng.MinSize, upstreamNg.MinSize)
+		sizeDiffers("maxSize", ng.MaxSize, upstreamNg.MaxSize)
+		if ng.InstanceType != "" && upstreamNg.InstanceType != "" && ng.InstanceType != upstreamNg.InstanceType {
+			differs(field+"instanceType", ng.InstanceType, upstreamNg.InstanceType)
+		}
+		if ng.Labels != nil && !utils.CompareStringMaps(aws.ToStringMap(ng.Labels), aws.ToStringMap(upstreamNg.Labels)) {
+			differs(field+"labels", aws.ToStringMap(ng.Labels), aws.ToStringMap(upstreamNg.Labels))
+		}
+		if ng.LaunchTemplate != nil && ng.LaunchTemplate.Version != nil && upstreamNg.LaunchTemplate != nil &&
+			aws.ToInt64(ng.LaunchTemplate.Version) != aws.ToInt64(upstreamNg.LaunchTemplate.Version) {
+			differs(field+"launchTemplate.version", aws.ToInt64(ng.LaunchTemplate.Version), aws.ToInt64(upstreamNg.LaunchTemplate.Version))
+		}
+	}
+	for _, ng := range upstreamSpec.NodeGroups {
+		if _, ok := nodeGroups[aws.ToString(ng.NodegroupName)]; !ok {
+			drift = append(drift, fmt.Sprintf("node group [%s] isn't in the spec", aws.ToString(ng.NodegroupName)))
+		}
+	}
+	return drift
+}
+
+// setDriftStatus records the drift of a paused config in the status and the Drifted condition, or clears both once
+// there is none, and returns whether the status changed.
+func (h *Handler) setDriftStatus(config *eksv1.EKSClusterConfig, drift []string) bool {
+	if len(drift) == 0 {
+		if len(config.Status.Drift) == 0 && !drifted.IsTrue(config) {
+			return false
+		}
+		config.Status.Drift = nil
+		drifted.SetStatus(config, string(corev1.ConditionFalse))
+		drifted.Message(config, "")
+		return true
+	}
+
+	if drifted.IsTrue(config) && slices.Equal(config.Status.Drift, drift) {
+		return false
+	}
+	message := strings.Join(drift, "; ")
+	h.recordEvent(config, corev1.EventTypeWarning, eventReasonDrifted, "Cluster [%s] drifted from its spec: %s",
+		config.Spec.DisplayName, message)
+	config.Status.Drift = drift
+	drifted.SetStatus(config, string(corev1.ConditionTrue))
+	drifted.Message(config, message)
+	return true
+}
@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+)
+
+func TestGetDrift(t *testing.T) {
+	upstreamSpec := &eksv1.EKSClusterConfigSpec{
+		KubernetesVersion: aws.String("1.30"),
+		Tags:              map[string]string{"team": "a"},
+		PublicAccess:      aws.Bool(true),
+		PrivateAccess:     aws.Bool(false),
+		LoggingTypes:      []string{"audit", "api"},
+		NodeGroups: []eksv1.NodeGroup{
+			{NodegroupName: aws.String("ng1"), Version: aws.String("1.30"), DesiredSize: aws.Int32(3), MinSize: aws.Int32(1), MaxSize: aws.Int32(5)},
+			{NodegroupName: aws.String("manual"), Version: aws.String("1.30")},
+			{NodegroupName: aws.String("custom"), LaunchTemplate: &eksv1.LaunchTemplate{ID: aws.String("lt-1"), Version: aws.Int64(3)}},
+		},
+	}
+
+	// unset fields are left as they are upstream
+	assert.Empty(t, getDrift(&eksv1.EKSClusterConfigSpec{}, upstreamSpec))
+	assert.Empty(t, getDrift(&eksv1.EKSClusterConfigSpec{
+		KubernetesVersion: aws.String("1.30"),
+		LoggingTypes:      []string{"api", "audit"},
+	}, upstreamSpec))
+
+	spec := &eksv1.EKSClusterConfigSpec{
+		KubernetesVersion: aws.String("1.31"),
+		Tags:              map[string]string{"team": "b"},
+		PrivateAccess:     aws.Bool(true),
+		NodeGroups: []eksv1.NodeGroup{
+			{NodegroupName: aws.String("ng1"), Version: aws.String("1.30"), DesiredSize: aws.Int32(2)},
+			{NodegroupName: aws.String("ng2")},
+			{NodegroupName: aws.String("custom"), LaunchTemplate: &eksv1.LaunchTemplate{ID: aws.String("lt-1"), Version: aws.Int64(4)}},
+		},
+	}
+	assert.Equal(t, []string{
+		"kubernetesVersion is [1.30] upstream instead of [1.31]",
+		"tags is [map[team:a]] upstream instead of [map[team:b]]",
+		"privateAccess is [false] upstream instead of [true]",
+		"nodeGroups[ng1].desiredSize is [3] upstream instead of [2]",
+		"node group [ng2] doesn't exist upstream",
+		"nodeGroups[custom].launchTemplate.version is [3] upstream instead of [4]",
+		"node group [manual] isn't in the spec",
+	}, getDrift(spec, upstreamSpec))
+}
+
+func TestDriftStatus(t *testing.T) {
+	h := &Handler{}
+	config := &eksv1.EKSClusterConfig{}
+	assert.False(t, h.setDriftStatus(config, nil))
+	assert.Empty(t, config.Status.Conditions, "the condition is only added once the config drifts")
+
+	drift := []string{"kubernetesVersion is [1.30] upstream instead of [1.31]"}
+	assert.True(t, h.setDriftStatus(config, drift))
+	assert.True(t, drifted.IsTrue(config))
+	assert.Equal(t, drift, config.Status.Drift)
+	assert.Equal(t, drift[0], drifted.GetMessage(config))
+	assert.False(t, h.setDriftStatus(config, drift))
+
+	assert.True(t, h.setDriftStatus(config, nil))
+	assert.True(t, drifted.IsFalse(config))
+	assert.Empty(t, config.Status.Drift)
+	assert.False(t, h.setDriftStatus(config, nil))
+}
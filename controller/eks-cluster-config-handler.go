@@ -2,12 +2,18 @@ package controller
 
 import (
 	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	cftypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/eks"
@@ -19,7 +25,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/record"
 
 	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
 	awsservices "github.com/rancher/eks-operator/pkg/eks"
@@ -30,22 +37,58 @@ import (
 )
 
 const (
-	controllerName           = "eks-controller"
-	controllerRemoveName     = "eks-controller-remove"
-	eksConfigCreatingPhase   = "creating"
-	eksConfigNotCreatedPhase = ""
-	eksConfigActivePhase     = "active"
-	eksConfigUpdatingPhase   = "updating"
-	eksConfigImportingPhase  = "importing"
-	eksClusterConfigKind     = "EKSClusterConfig"
+	controllerName       = "eks-controller"
+	controllerRemoveName = "eks-controller-remove"
+	eksClusterConfigKind = "EKSClusterConfig"
+	eksInventoryKind     = "EKSInventory"
+
+	vpcModePublic  = "public"
+	vpcModePrivate = "private"
+
+	// number of NAT gateways in the VPC generated for the private vpcMode, one per private subnet
+	privateVPCNATGateways = 2
+
+	// number of thumbprints IAM allows for an OIDC provider
+	maxOIDCThumbprints = 5
 )
 
 type Handler struct {
-	eksCC           ekscontrollers.EKSClusterConfigClient
-	eksEnqueueAfter func(namespace, name string, duration time.Duration)
-	eksEnqueue      func(namespace, name string)
-	secrets         wranglerv1.SecretClient
-	secretsCache    wranglerv1.SecretCache
+	eksCC                 ekscontrollers.EKSClusterConfigClient
+	eksEnqueueAfter       func(namespace, name string, duration time.Duration)
+	eksEnqueue            func(namespace, name string)
+	inventoryEnqueueAfter func(namespace, name string, duration time.Duration)
+	templateCache         ekscontrollers.EKSClusterTemplateCache
+	secrets               wranglerv1.SecretClient
+	secretsCache          wranglerv1.SecretCache
+	configMaps            wranglerv1.ConfigMapClient
+	dynamic               dynamic.Interface
+	recorder              record.EventRecorder
+	costEstimation        bool
+	requeueIntervals      map[requeueInterval]time.Duration
+	nodeGroupDefaults     NodeGroupDefaults
+	deletionSlots         *deletionSlots
+	checks                *checkThrottle
+	cloudFormationDenials *denialCounter
+	// newAWSServices creates the AWS services of configs instead of newAWSv2Services if set, e.g. to fake AWS in tests
+	newAWSServices func(ctx context.Context, spec eksv1.EKSClusterConfigSpec) (*awsServices, error)
+}
+
+// RegisterOpts holds the optional features of the operator.
+type RegisterOpts struct {
+	// CostEstimation enables estimating the monthly cost of clusters before they are created
+	CostEstimation bool
+	// CreatingInterval, UpdatingInterval and DeletingInterval are how long configs wait before checking on clusters
+	// that are being created, updated and deleted again, and StackInterval how long they wait on the CloudFormation
+	// stacks being created for them. Defaults are used if unset.
+	CreatingInterval time.Duration
+	UpdatingInterval time.Duration
+	DeletingInterval time.Duration
+	StackInterval    time.Duration
+	// NodeGroupDefaults are set on node groups that omit the corresponding fields
+	NodeGroupDefaults NodeGroupDefaults
+	// MaxConcurrentDeletions is the number of clusters whose teardown runs at once, the other deleting clusters are
+	// requeued until a slot is free. It isn't limited if zero
+	MaxConcurrentDeletions int
 }
 
 type awsServices struct {
@@ -53,50 +96,107 @@ type awsServices struct {
 	eks            services.EKSServiceInterface
 	ec2            services.EC2ServiceInterface
 	iam            services.IAMServiceInterface
+	sts            services.STSServiceInterface
+	pricing        services.PricingServiceInterface
+	logs           services.CloudWatchLogsServiceInterface
+	cloudwatch     services.CloudWatchServiceInterface
 }
 
 func Register(
 	ctx context.Context,
 	secrets wranglerv1.SecretController,
-	eks ekscontrollers.EKSClusterConfigController) {
+	configMaps wranglerv1.ConfigMapController,
+	eks ekscontrollers.EKSClusterConfigController,
+	inventories ekscontrollers.EKSInventoryController,
+	templates ekscontrollers.EKSClusterTemplateController,
+	dynamicClient dynamic.Interface,
+	recorder record.EventRecorder,
+	opts *RegisterOpts) {
 	controller := &Handler{
-		eksCC:           eks,
-		eksEnqueue:      eks.Enqueue,
-		eksEnqueueAfter: eks.EnqueueAfter,
-		secretsCache:    secrets.Cache(),
-		secrets:         secrets,
+		eksCC:                 eks,
+		eksEnqueue:            eks.Enqueue,
+		eksEnqueueAfter:       eks.EnqueueAfter,
+		inventoryEnqueueAfter: inventories.EnqueueAfter,
+		templateCache:         templates.Cache(),
+		secretsCache:          secrets.Cache(),
+		secrets:               secrets,
+		configMaps:            configMaps,
+		dynamic:               dynamicClient,
+		recorder:              recorder,
+		costEstimation:        opts.CostEstimation,
+		requeueIntervals:      newRequeueIntervals(opts),
+		nodeGroupDefaults:     opts.NodeGroupDefaults,
+		deletionSlots:         newDeletionSlots(opts.MaxConcurrentDeletions),
+		checks:                newCheckThrottle(informationalCheckInterval),
+		cloudFormationDenials: newDenialCounter(),
 	}
 
 	// Register handlers
 	eks.OnChange(ctx, controllerName, controller.recordError(controller.OnEksConfigChanged))
-	eks.OnRemove(ctx, controllerRemoveName, controller.OnEksConfigRemoved)
+	inventories.OnChange(ctx, inventoryControllerName, controller.OnInventoryChanged)
+	templates.OnChange(ctx, templateControllerName, controller.OnTemplateChanged)
 }
 
-func (h *Handler) OnEksConfigChanged(_ string, config *eksv1.EKSClusterConfig) (*eksv1.EKSClusterConfig, error) {
+func (h *Handler) OnEksConfigChanged(key string, config *eksv1.EKSClusterConfig) (*eksv1.EKSClusterConfig, error) {
 	if config == nil {
+		h.checks.forget(key)
+		h.cloudFormationDenials.forget(key)
 		return nil, nil
 	}
 
 	if config.DeletionTimestamp != nil {
-		return nil, nil
+		return h.OnEksConfigRemoved(key, config)
+	}
+
+	config, err := h.ensureCleanupFinalizer(config)
+	if err != nil {
+		return config, err
+	}
+
+	config, err = h.applyTemplate(config)
+	if err != nil {
+		return config, err
+	}
+
+	if config.Status.Phase == eksv1.PhaseNotCreated {
+		// none of the node groups exist before the cluster is created, the ones added later are defaulted on update
+		config, err = h.applyNodeGroupDefaults(config, nil)
+		if err != nil {
+			return config, err
+		}
+	}
+
+	config, err = h.applyVersionNormalization(config)
+	if err != nil {
+		return config, err
+	}
+
+	if err := config.Status.Phase.Validate(); err != nil {
+		return config, fmt.Errorf("cluster [%s (id: %s)]: %w", config.Spec.DisplayName, config.Name, err)
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	ctx = withPayloadLogging(ctx, config)
 
-	awsSVCs, err := newAWSv2Services(ctx, h.secrets, config.Spec)
+	awsSVCs, err := h.awsServicesFor(ctx, config.Spec)
 	if err != nil {
 		return config, fmt.Errorf("error creating new AWS services: %w", err)
 	}
 
+	if config.Spec.Paused && (config.Status.Phase == eksv1.PhaseNotCreated || config.Status.Phase == eksv1.PhaseCreating) {
+		logrus.Infof("Cluster [%s (id: %s)] is paused, will not create EKS cluster", config.Spec.DisplayName, config.Name)
+		return config, nil
+	}
+
 	switch config.Status.Phase {
-	case eksConfigImportingPhase:
+	case eksv1.PhaseImporting:
 		return h.importCluster(ctx, config, awsSVCs)
-	case eksConfigNotCreatedPhase:
+	case eksv1.PhaseNotCreated:
 		return h.create(ctx, config, awsSVCs)
-	case eksConfigCreatingPhase:
+	case eksv1.PhaseCreating:
 		return h.waitForCreationComplete(ctx, config, awsSVCs)
-	case eksConfigActivePhase, eksConfigUpdatingPhase:
+	case eksv1.PhaseActive, eksv1.PhaseUpdating:
 		return h.checkAndUpdate(ctx, config, awsSVCs)
 	}
 
@@ -109,11 +209,21 @@ func (h *Handler) recordError(onChange func(key string, config *eksv1.EKSCluster
 	return func(key string, config *eksv1.EKSClusterConfig) (*eksv1.EKSClusterConfig, error) {
 		var err error
 		var message string
+		var previousPhase eksv1.Phase
+		if config != nil {
+			previousPhase = config.Status.Phase
+		}
 		config, err = onChange(key, config)
 		if config == nil {
 			// EKS config is likely deleting
 			return config, err
 		}
+		if isDryRun(err) {
+			// nothing was changed in AWS, the config is left as it is until its next change or resync instead of being
+			// marked as failed and retried
+			logrus.Infof("Cluster [%s (id: %s)]: %s", config.Spec.DisplayName, config.Name, err.Error())
+			return config, nil
+		}
 		if err != nil {
 			if !strings.Contains(err.Error(), "currently has update") {
 				// The update is valid in that the controller should retry but there is no actionable resolution as far
@@ -126,105 +236,113 @@ func (h *Handler) recordError(onChange func(key string, config *eksv1.EKSCluster
 		}
 
 		if config.Status.FailureMessage == message {
+			h.recordPhaseTransition(config, previousPhase)
 			return config, err
 		}
 
+		if message != "" {
+			h.recordEvent(config, corev1.EventTypeWarning, eventReasonFailed, message)
+		}
+
 		config = config.DeepCopy()
-		if message != "" && config.Status.Phase == eksConfigActivePhase {
+		if message != "" && config.Status.Phase.IsTerminal() {
 			// can assume an update is failing
-			config.Status.Phase = eksConfigUpdatingPhase
+			config.Status.Phase = eksv1.PhaseUpdating
 		}
 		config.Status.FailureMessage = message
 
 		var recordErr error
-		config, recordErr = h.eksCC.UpdateStatus(config)
+		config, recordErr = h.updateStatus(config)
 		if recordErr != nil {
 			logrus.Errorf("Error recording ekscc [%s (id: %s)] failure message: %s", config.Spec.DisplayName, config.Name, recordErr.Error())
 		}
+		h.recordPhaseTransition(config, previousPhase)
 		return config, err
 	}
 }
 
-func (h *Handler) OnEksConfigRemoved(_ string, config *eksv1.EKSClusterConfig) (*eksv1.EKSClusterConfig, error) {
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+// OnEksConfigRemoved tears down the upstream resources of a deleting config in stages, recording the stage reached on
+// the status, and removes the cleanup finalizers once the teardown is done. Stages that wait for upstream deletions are
+// requeued instead of blocking the worker, and clusters wait for a deletion slot before their teardown starts.
+func (h *Handler) OnEksConfigRemoved(key string, config *eksv1.EKSClusterConfig) (*eksv1.EKSClusterConfig, error) {
+	if !hasCleanupFinalizer(config) {
+		return nil, nil
+	}
 
-	awsSVCs, err := newAWSv2Services(ctx, h.secrets, config.Spec)
-	if err != nil {
-		return config, fmt.Errorf("error creating new AWS services: %w", err)
+	if config.Annotations[deletionDryRunAnnotation] == "true" && config.Status.DeletionStage == "" {
+		return h.simulateDeletion(config)
 	}
 
 	if config.Spec.Imported {
 		logrus.Infof("Cluster [%s (id: %s)] is imported, will not delete EKS cluster", config.Spec.DisplayName, config.Name)
-		return config, nil
+		return h.removeCleanupFinalizers(config)
+	}
+	if config.Spec.Paused {
+		logrus.Infof("Cluster [%s (id: %s)] is paused, will not delete EKS cluster", config.Spec.DisplayName, config.Name)
+		return h.removeCleanupFinalizers(config)
+	}
+	if config.Spec.DeletionPolicy == deletionPolicyRetain {
+		logrus.Infof("Cluster [%s (id: %s)] has the %s deletion policy, will not delete EKS cluster", config.Spec.DisplayName, config.Name, deletionPolicyRetain)
+		return h.removeCleanupFinalizers(config)
 	}
-	if config.Status.Phase == eksConfigNotCreatedPhase {
+	if neverCreated(config) {
 		// The most likely context here is that the cluster already existed in EKS, so we shouldn't delete it
 		logrus.Warnf("Cluster [%s (id: %s)] never advanced to creating status, will not delete EKS cluster", config.Spec.DisplayName, config.Name)
-		return config, nil
+		return h.removeCleanupFinalizers(config)
 	}
 
-	logrus.Infof("Deleting cluster [%s (id: %s)]", config.Spec.DisplayName, config.Name)
-
-	logrus.Infof("Starting node group deletion for config [%s (id: %s)]", config.Spec.DisplayName, config.Name)
-	waitingForNodegroupDeletion := true
-	for waitingForNodegroupDeletion {
-		waitingForNodegroupDeletion, err = deleteNodeGroups(ctx, config, config.Spec.NodeGroups, awsSVCs.eks)
-		if err != nil {
-			return config, fmt.Errorf("error deleting nodegroups for config [%s (id: %s)]", config.Spec.DisplayName, config.Name)
-		}
-		time.Sleep(10 * time.Second)
-		logrus.Infof("Waiting for config [%s (id: %s)] node groups to delete", config.Spec.DisplayName, config.Name)
+	if !h.deletionSlots.acquire(key) {
+		logrus.Infof("Waiting for other clusters to finish deleting before deleting cluster [%s (id: %s)]", config.Spec.DisplayName, config.Name)
+		h.requeueDeletion(config)
+		return config, nil
 	}
+	defer h.deletionSlots.release(key)
 
-	if config.Status.ManagedLaunchTemplateID != "" {
-		logrus.Infof("Deleting common launch template for config [%s (id: %s)]", config.Spec.DisplayName, config.Name)
-		deleteLaunchTemplate(ctx, config.Status.ManagedLaunchTemplateID, awsSVCs.ec2)
-	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = withPayloadLogging(ctx, config)
 
-	logrus.Infof("Starting control plane deletion for config [%s (id: %s)]", config.Spec.DisplayName, config.Name)
-	_, err = awsSVCs.eks.DeleteCluster(ctx, &eks.DeleteClusterInput{
-		Name: aws.String(config.Spec.DisplayName),
-	})
+	awsSVCs, err := h.awsServicesFor(ctx, config.Spec)
 	if err != nil {
-		if notFound(err) {
-			_, err = awsSVCs.eks.DeleteCluster(ctx, &eks.DeleteClusterInput{
-				Name: aws.String(config.Spec.DisplayName),
-			})
-		}
-
-		if err != nil && !notFound(err) {
-			return config, fmt.Errorf("error deleting cluster: %v", err)
-		}
+		return config, fmt.Errorf("error creating new AWS services: %w", err)
 	}
 
-	if aws.ToBool(config.Spec.EBSCSIDriver) {
-		logrus.Infof("Deleting ebs csi driver role for config [%s (id: %s)]", config.Spec.DisplayName, config.Name)
-		if err := deleteStack(ctx, awsSVCs.cloudformation, getEBSCSIDriverRoleStackName(config.Spec.DisplayName), getEBSCSIDriverRoleStackName(config.Spec.DisplayName)); err != nil {
-			return config, fmt.Errorf("error ebs csi driver role stack: %v", err)
+	if config.Status.DeletionStage == "" {
+		logrus.Infof("Deleting cluster [%s (id: %s)]", config.Spec.DisplayName, config.Name)
+		config = config.DeepCopy()
+		if !config.Spec.Imported && !config.Status.CloudFormationStacksMigrated {
+			if err := migrateStacks(ctx, awsSVCs.cloudformation, config); err != nil {
+				return config, fmt.Errorf("error discovering stacks for config [%s (id: %s)]: %w", config.Spec.DisplayName, config.Name, err)
+			}
 		}
-	}
-
-	if aws.ToString(config.Spec.ServiceRole) == "" {
-		logrus.Infof("Deleting service role for config [%s (id: %s)]", config.Spec.DisplayName, config.Name)
-		if err := deleteStack(ctx, awsSVCs.cloudformation, getServiceRoleName(config.Spec.DisplayName), getServiceRoleName(config.Spec.DisplayName)); err != nil {
-			return config, fmt.Errorf("error deleting service role stack: %v", err)
+		h.recordEvent(config, corev1.EventTypeNormal, eventReasonDeleting, "Deleting cluster [%s]", config.Spec.DisplayName)
+		logrus.Infof("Starting node group deletion for config [%s (id: %s)]", config.Spec.DisplayName, config.Name)
+		config.Status.DeletionStage = deletionStageNodeGroups
+		if config, err = h.updateStatus(config); err != nil {
+			return config, err
 		}
 	}
 
-	if len(config.Spec.Subnets) == 0 {
-		logrus.Infof("Deleting vpc, subnets, and security groups for config [%s (id: %s)]", config.Spec.DisplayName, config.Name)
-		if err := deleteStack(ctx, awsSVCs.cloudformation, getVPCStackName(config.Spec.DisplayName), getVPCStackName(config.Spec.DisplayName)); err != nil {
-			return config, fmt.Errorf("error deleting vpc stack: %v", err)
+	for config.Status.DeletionStage != deletionStageDone {
+		stage, waiting, err := runDeletionStage(ctx, config, awsSVCs)
+		if err != nil {
+			return config, err
+		}
+		if waiting {
+			h.requeueDeletion(config)
+			return config, nil
 		}
-	}
 
-	logrus.Infof("Deleting node instance role for config [%s (id: %s)]", config.Spec.DisplayName, config.Name)
-	if err := deleteStack(ctx, awsSVCs.cloudformation, fmt.Sprintf("%s-node-instance-role", config.Spec.DisplayName), fmt.Sprintf("%s-node-instance-role", config.Spec.DisplayName)); err != nil {
-		return config, fmt.Errorf("error deleting worker node stack: %v", err)
+		// the stage is recorded before the next one runs, so the teardown resumes from it after a restart
+		config = config.DeepCopy()
+		config.Status.DeletionStage = stage
+		if config, err = h.updateStatus(config); err != nil {
+			return config, err
+		}
 	}
 
-	return config, err
+	logrus.Infof("Finished deleting cluster [%s (id: %s)]", config.Spec.DisplayName, config.Name)
+	return h.removeCleanupFinalizers(config)
 }
 
 func (h *Handler) checkAndUpdate(ctx context.Context, config *eksv1.EKSClusterConfig, awsSVCs *awsServices) (*eksv1.EKSClusterConfig, error) {
@@ -235,15 +353,26 @@ func (h *Handler) checkAndUpdate(ctx context.Context, config *eksv1.EKSClusterCo
 	if err := validateUpdate(config); err != nil {
 		// validation failed, will be considered a failing update until resolved
 		config = config.DeepCopy()
-		config.Status.Phase = eksConfigUpdatingPhase
+		config.Status.Phase = eksv1.PhaseUpdating
 		var updateErr error
-		config, updateErr = h.eksCC.UpdateStatus(config)
+		config, updateErr = h.updateStatus(config)
 		if updateErr != nil {
 			return config, updateErr
 		}
 		return config, err
 	}
 
+	if !config.Spec.Imported && !config.Status.CloudFormationStacksMigrated {
+		// stacks created before they were recorded on the status are discovered once, imported clusters have none
+		config = config.DeepCopy()
+		if err := migrateStacks(ctx, awsSVCs.cloudformation, config); err != nil {
+			return config, fmt.Errorf("error discovering stacks for cluster [%s (id: %s)]: %w", config.Spec.DisplayName, config.Name, err)
+		}
+		if config.Status.CloudFormationStacksMigrated {
+			return h.updateStatus(config)
+		}
+	}
+
 	clusterState, err := awsservices.GetClusterState(ctx, &awsservices.GetClusterStatusOpts{
 		EKSService: awsSVCs.eks,
 		Config:     config,
@@ -252,18 +381,41 @@ func (h *Handler) checkAndUpdate(ctx context.Context, config *eksv1.EKSClusterCo
 		return config, err
 	}
 
+	if err := h.ensureKubeconfigSecret(config, clusterState); err != nil {
+		return config, err
+	}
+
 	if clusterState.Cluster.Status == ekstypes.ClusterStatusUpdating {
 		// upstream cluster is already updating, must wait until sending next update
 		logrus.Infof("Waiting for cluster [%s (id: %s)] to finish updating", config.Spec.DisplayName, config.Name)
-		if config.Status.Phase != eksConfigUpdatingPhase {
+		if config.Status.Phase != eksv1.PhaseUpdating {
 			config = config.DeepCopy()
-			config.Status.Phase = eksConfigUpdatingPhase
-			return h.eksCC.UpdateStatus(config)
+			config.Status.Phase = eksv1.PhaseUpdating
+			return h.updateStatus(config)
 		}
-		h.eksEnqueueAfter(config.Namespace, config.Name, 30*time.Second)
+		h.requeue(config, updatingInterval)
 		return config, nil
 	}
 
+	if len(config.Status.InProgressUpdates) != 0 {
+		updatedConfig := config.DeepCopy()
+		failures, err := checkInProgressUpdates(ctx, updatedConfig, awsSVCs.eks)
+		if err != nil {
+			return config, err
+		}
+		if len(updatedConfig.Status.InProgressUpdates) != len(config.Status.InProgressUpdates) {
+			config, err = h.updateStatus(updatedConfig)
+			if err != nil {
+				return config, err
+			}
+			if len(failures) != 0 {
+				// the failures are recorded in failureMessage, the updates are sent again once the config is retried
+				return config, fmt.Errorf("updates of cluster [%s (id: %s)] failed: %s", config.Spec.DisplayName, config.Name, strings.Join(failures, "; "))
+			}
+			return config, nil
+		}
+	}
+
 	ngs, err := awsSVCs.eks.ListNodegroups(ctx,
 		&eks.ListNodegroupsInput{
 			ClusterName: aws.String(config.Spec.DisplayName),
@@ -284,49 +436,233 @@ func (h *Handler) checkAndUpdate(ctx context.Context, config *eksv1.EKSClusterCo
 		if err != nil {
 			return config, err
 		}
+
+		if !nodeGroupSelected(config, ngName, ng.Nodegroup.Labels) {
+			continue
+		}
+		nodeGroupStates = append(nodeGroupStates, ng)
+		nodegroupARNs[ngName] = aws.ToString(ng.Nodegroup.NodegroupArn)
+	}
+
+	config = config.DeepCopy()
+	statusChanged := setNodegroupsReadyStatus(config, getNotReadyNodegroups(selectedNodeGroups(config), nodeGroupStates))
+	if setNodegroupRollouts(ctx, config, nodeGroupStates, awsSVCs) {
+		statusChanged = true
+	}
+	if setCapacityStatus(config, nodeGroupStates) {
+		statusChanged = true
+	}
+	if setNodeGroupStatusesStatus(config, nodeGroupStates, h.getReadyNodeCounts(ctx, config, awsSVCs)) {
+		statusChanged = true
+	}
+	if h.checks.due(config, checkResourceUsage, time.Now()) && setResourceUsageStatus(config, getResourceUsage(ctx, config, nodeGroupStates, awsSVCs)) {
+		statusChanged = true
+	}
+	now := time.Now()
+	for _, name := range setScalingWindowsStatus(config, now) {
+		statusChanged = true
+		if window := config.Status.NodeGroupScalingWindows[name]; window != "" {
+			h.recordEvent(config, corev1.EventTypeNormal, eventReasonScalingWindowStarted, "Scaling window [%s] of node group [%s] in cluster [%s] started",
+				window, name, config.Spec.DisplayName)
+		}
+	}
+	if next, ok := nextScalingWindowStart(config.Spec.NodeGroups, now); ok {
+		h.eksEnqueueAfter(config.Namespace, config.Name, next)
+	}
+	for _, ng := range nodeGroupStates {
 		if status := ng.Nodegroup.Status; status == ekstypes.NodegroupStatusUpdating || status == ekstypes.NodegroupStatusDeleting ||
 			status == ekstypes.NodegroupStatusCreating {
-			if config.Status.Phase != eksConfigUpdatingPhase {
-				config = config.DeepCopy()
-				config.Status.Phase = eksConfigUpdatingPhase
-				config, err = h.eksCC.UpdateStatus(config)
+			if config.Status.Phase != eksv1.PhaseUpdating {
+				config.Status.Phase = eksv1.PhaseUpdating
+				statusChanged = true
+			}
+			if statusChanged {
+				config, err = h.updateStatus(config)
 				if err != nil {
 					return config, err
 				}
 			}
-			logrus.Infof("Waiting for cluster [%s (id: %s)] to update nodegroups [%s]", config.Spec.DisplayName, config.Name, ngName)
-			h.eksEnqueueAfter(config.Namespace, config.Name, 30*time.Second)
+			logrus.Infof("Waiting for cluster [%s (id: %s)] to update nodegroups [%s]", config.Spec.DisplayName, config.Name, aws.ToString(ng.Nodegroup.NodegroupName))
+			h.requeue(config, updatingInterval)
 			return config, nil
 		}
+	}
 
-		nodeGroupStates = append(nodeGroupStates, ng)
-		nodegroupARNs[ngName] = aws.ToString(ng.Nodegroup.NodegroupArn)
+	degradedIssues, updatable := getDegradedNodegroupIssues(nodeGroupStates)
+	if setNodegroupsDegradedStatus(config, degradedIssues) {
+		statusChanged = true
+		if len(degradedIssues) != 0 {
+			h.recordEvent(config, corev1.EventTypeWarning, eventReasonNodegroupDegraded, "Node groups of cluster [%s] are degraded: %s",
+				config.Spec.DisplayName, strings.Join(degradedIssues, "; "))
+		}
+	}
+	if len(degradedIssues) != 0 && !updatable {
+		// the health issues can't be remediated by updating the node groups, wait for them to be resolved in AWS
+		if config.Status.Phase != eksv1.PhaseUpdating {
+			config.Status.Phase = eksv1.PhaseUpdating
+			statusChanged = true
+		}
+		if statusChanged {
+			config, err = h.updateStatus(config)
+			if err != nil {
+				return config, err
+			}
+		}
+		logrus.Infof("Waiting for degraded nodegroups of cluster [%s (id: %s)] to recover: %s", config.Spec.DisplayName, config.Name, strings.Join(degradedIssues, "; "))
+		h.requeue(config, updatingInterval)
+		return config, nil
+	}
+	if statusChanged {
+		return h.updateStatus(config)
 	}
 
-	if config.Status.Phase == eksConfigActivePhase && len(config.Status.TemplateVersionsToDelete) != 0 {
+	if config.Status.Phase == eksv1.PhaseActive && len(config.Status.TemplateVersionsToDelete) != 0 && !config.Spec.Paused {
 		// If there are any launch template versions that need to be cleaned up, we do it now.
-		awsservices.DeleteLaunchTemplateVersions(ctx, awsSVCs.ec2, config.Status.ManagedLaunchTemplateID, aws.StringSlice(config.Status.TemplateVersionsToDelete))
+		if err := awsservices.DeleteLaunchTemplateVersions(ctx, awsSVCs.ec2, config.Status.ManagedLaunchTemplateID, aws.StringSlice(config.Status.TemplateVersionsToDelete)); isDryRun(err) {
+			return config, err
+		}
 		config = config.DeepCopy()
 		config.Status.TemplateVersionsToDelete = nil
-		return h.eksCC.UpdateStatus(config)
+		return h.updateStatus(config)
 	}
 
-	upstreamSpec, clusterARN, err := BuildUpstreamClusterState(ctx, config.Spec.DisplayName, config.Status.ManagedLaunchTemplateID, clusterState, nodeGroupStates, awsSVCs.ec2, awsSVCs.eks, true)
+	// the security groups and platform version change with updates, and clusters that became active before the
+	// upstream cluster details were recorded get them recorded now
+	if updatedConfig := config.DeepCopy(); setUpstreamClusterStatus(updatedConfig, clusterState.Cluster) {
+		return h.updateStatus(updatedConfig)
+	}
+
+	if updatedConfig := config.DeepCopy(); setOwnedResourcesStatus(updatedConfig, nodegroupARNs) {
+		return h.updateStatus(updatedConfig)
+	}
+	insightsConfig := config.DeepCopy()
+	if h.checkInsights(ctx, insightsConfig, awsSVCs) {
+		return h.updateStatus(insightsConfig)
+	}
+
+	upstreamSpec, clusterARN, userDataHashes, err := buildUpstreamClusterState(ctx, config.Spec.DisplayName, config.Status.ManagedLaunchTemplateID, clusterState, nodeGroupStates, awsSVCs.ec2, awsSVCs.eks, true, false)
 	if err != nil {
 		return config, err
 	}
 
-	return h.updateUpstreamClusterState(ctx, upstreamSpec, config, awsSVCs, clusterARN, nodegroupARNs)
+	if exportRequested(config) {
+		return h.exportConfig(config, upstreamSpec)
+	}
+
+	var drift []string
+	if config.Spec.Paused {
+		spec := config.Spec.DeepCopy()
+		spec.Tags = getClusterTags(config)
+		spec.NodeGroups = nil
+		for _, ng := range selectedNodeGroups(config) {
+			spec.NodeGroups = append(spec.NodeGroups, applySharedLaunchTemplate(config, ng))
+		}
+		drift = getDrift(spec, upstreamSpec)
+	}
+	updatedConfig := config.DeepCopy()
+	statusChanged = h.setDriftStatus(updatedConfig, drift)
+	if config.Spec.Paused && updatedConfig.Status.Phase.IsTransitional() {
+		// the cluster and its node groups finished updating and nothing else is updated while paused
+		updatedConfig.Status.Phase = eksv1.PhaseActive
+		statusChanged = true
+	}
+	if statusChanged {
+		return h.updateStatus(updatedConfig)
+	}
+	if config.Spec.Paused {
+		// paused clusters are only compared with their spec, which is done again on the next resync
+		return config, nil
+	}
+
+	// the updates sent to EKS are tracked on the status until they finish
+	updates := &updateRecorder{EKSServiceInterface: awsSVCs.eks}
+	recordingSVCs := *awsSVCs
+	recordingSVCs.eks = updates
+	config, err = h.updateUpstreamClusterState(ctx, upstreamSpec, userDataHashes, config, &recordingSVCs, clusterARN, nodegroupARNs)
+	if len(updates.updates) == 0 || config == nil {
+		return config, err
+	}
+	config = config.DeepCopy()
+	config.Status.InProgressUpdates = append(config.Status.InProgressUpdates, updates.updates...)
+	config, updateErr := h.updateStatus(config)
+	if updateErr != nil {
+		return config, updateErr
+	}
+	return config, err
 }
 
 func validateUpdate(config *eksv1.EKSClusterConfig) error {
 	var clusterVersion *semver.Version
 	if config.Spec.KubernetesVersion != nil {
-		var err error
-		clusterVersion, err = semver.New(fmt.Sprintf("%s.0", aws.ToString(config.Spec.KubernetesVersion)))
+		version, err := utils.ParseKubernetesVersion(aws.ToString(config.Spec.KubernetesVersion))
 		if err != nil {
-			return fmt.Errorf("invalid version format for cluster [%s (id: %s)]: %s", config.Spec.DisplayName, config.Name, aws.ToString(config.Spec.KubernetesVersion))
+			return fmt.Errorf("invalid version format for cluster [%s (id: %s)]: %w", config.Spec.DisplayName, config.Name, err)
 		}
+		clusterVersion = &version
+	}
+
+	if err := validateOutpostConfig(config); err != nil {
+		return err
+	}
+
+	if err := validatePodIdentityAssociations(config); err != nil {
+		return err
+	}
+
+	if err := validateIdentityProviderConfigs(config); err != nil {
+		return err
+	}
+
+	if err := validateClusterAdminPrincipals(config); err != nil {
+		return err
+	}
+
+	if err := validateOIDCProviderARN(config); err != nil {
+		return err
+	}
+
+	if err := validateSecretsEncryption(config); err != nil {
+		return err
+	}
+
+	if err := validateOIDCThumbprints(config); err != nil {
+		return err
+	}
+
+	if err := validateMaintenanceWindow(config); err != nil {
+		return err
+	}
+
+	if err := validateServiceRolePolicyARNs(config); err != nil {
+		return err
+	}
+
+	if err := validateIAMRoleOptions(config); err != nil {
+		return err
+	}
+
+	if err := validateNodeGroupDrain(config); err != nil {
+		return err
+	}
+
+	if err := validateDownstreamTLS(config); err != nil {
+		return err
+	}
+
+	if err := validateDeletionPolicy(config); err != nil {
+		return err
+	}
+
+	if err := validateImportNodeGroupSelector(config); err != nil {
+		return err
+	}
+
+	if err := validateAuditLogging(config); err != nil {
+		return err
+	}
+
+	if err := validateSharedLaunchTemplates(config); err != nil {
+		return err
 	}
 
 	errs := make([]string, 0)
@@ -338,19 +674,43 @@ func validateUpdate(config *eksv1.EKSClusterConfig) error {
 		} else {
 			errs = append(errs, fmt.Sprintf("node group name [%s] is not unique within the cluster [%s (id: %s)] to avoid duplication", aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name))
 		}
+		if err := validateMetadataOptions(config, ng); err != nil {
+			errs = append(errs, err.Error())
+		}
+		if err := validatePlacement(config, ng); err != nil {
+			errs = append(errs, err.Error())
+		}
+		if err := validateBlockDeviceMappings(config, ng); err != nil {
+			errs = append(errs, err.Error())
+		}
+		if err := validateNetworkInterfaces(config, ng); err != nil {
+			errs = append(errs, err.Error())
+		}
+		if err := validateVolumeOptions(config, ng); err != nil {
+			errs = append(errs, err.Error())
+		}
+		if err := validateScheduledScaling(config, ng); err != nil {
+			errs = append(errs, err.Error())
+		}
+		if err := validateReleaseVersion(config, ng); err != nil {
+			errs = append(errs, err.Error())
+		}
+		if err := validateRecreatePolicy(config, ng); err != nil {
+			errs = append(errs, err.Error())
+		}
 
 		if ng.Version == nil {
 			continue
 		}
-		version, err := semver.New(fmt.Sprintf("%s.0", aws.ToString(ng.Version)))
+		version, err := utils.ParseKubernetesVersion(aws.ToString(ng.Version))
 		if err != nil {
-			errs = append(errs, fmt.Sprintf("invalid version format for node group [%s]: %s", aws.ToString(ng.NodegroupName), aws.ToString(ng.Version)))
+			errs = append(errs, fmt.Sprintf("invalid version format for node group [%s]: %v", aws.ToString(ng.NodegroupName), err))
 			continue
 		}
 		if clusterVersion == nil {
 			continue
 		}
-		if clusterVersion.EQ(*version) {
+		if clusterVersion.EQ(version) {
 			continue
 		}
 		if clusterVersion.Minor-version.Minor <= 3 {
@@ -370,23 +730,56 @@ func (h *Handler) create(ctx context.Context, config *eksv1.EKSClusterConfig, aw
 		return config, fmt.Errorf("aws services not initialized")
 	}
 
+	// existing clusters are adopted instead of failing the validation if requested
+	if adopted, ok, err := h.adoptExistingCluster(ctx, config, awsSVCs); err != nil || ok {
+		return adopted, err
+	}
+
 	if err := h.validateCreate(ctx, config, awsSVCs); err != nil {
 		return config, err
 	}
 
 	if config.Spec.Imported {
 		config = config.DeepCopy()
-		config.Status.Phase = eksConfigImportingPhase
-		return h.eksCC.UpdateStatus(config)
+		config.Status.Phase = eksv1.PhaseImporting
+		return h.updateStatus(config)
+	}
+
+	if h.costEstimation && config.Status.CostEstimate == nil {
+		// the estimate is best effort and doesn't block creating the cluster
+		estimate, err := awsservices.EstimateCost(ctx, &awsservices.EstimateCostOpts{
+			PricingService: awsSVCs.pricing,
+			Config:         config,
+			NATGateways:    getNATGatewayCount(config),
+		})
+		if err != nil {
+			logrus.Warnf("Could not estimate the cost of cluster [%s (id: %s)]: %v", config.Spec.DisplayName, config.Name, err)
+		} else {
+			logrus.Infof("Estimated monthly cost of cluster [%s (id: %s)] is %s %s", config.Spec.DisplayName, config.Name, estimate.MonthlyCost, estimate.Currency)
+			config = config.DeepCopy()
+			config.Status.CostEstimate = estimate
+			return h.updateStatus(config)
+		}
 	}
 
 	config, err := h.generateAndSetNetworking(ctx, config, awsSVCs)
 	if err != nil {
+		if inProgress := stackCreationInProgress(err); inProgress != nil {
+			return h.waitForStack(config, inProgress)
+		}
 		return config, fmt.Errorf("error generating and setting networking: %w", err)
 	}
 
 	roleARN, err := h.createOrGetServiceRole(ctx, config, awsSVCs)
 	if err != nil {
+		if inProgress := stackCreationInProgress(err); inProgress != nil {
+			return h.waitForStack(config, inProgress)
+		}
+		if config.Status.ProvisioningBackend == "" && h.shouldFallBackToNativeProvisioning(config, err) {
+			config = config.DeepCopy()
+			h.fallBackToNativeProvisioning(config, err)
+			return h.updateStatus(config)
+		}
 		return config, fmt.Errorf("error creating or getting service role: %w", err)
 	}
 
@@ -399,22 +792,21 @@ func (h *Handler) create(ctx context.Context, config *eksv1.EKSClusterConfig, aw
 	}
 
 	// If a user edits a cluster at the exact right (or wrong) time, then the
-	// `UpdateStatus` call may produce a conflict and will error. When the
-	// controller re-enters the create function, it will try to verify that a
-	// cluster with the same name in EKS does not exist (in the `validateCreate`
-	// call above). It will find the one that was created and error.
-	// Therefore, the `RetryOnConflict` will successfully update the status in this situation.
-	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		config, err = h.eksCC.Get(config.Namespace, config.Name, metav1.GetOptions{})
-		if err != nil {
-			return err
-		}
-		config.Status.Phase = eksConfigCreatingPhase
-		config.Status.FailureMessage = ""
-		config, err = h.eksCC.UpdateStatus(config)
-		return err
-	})
-	return config, err
+	// status update may produce a conflict. When the controller re-enters the
+	// create function, it will try to verify that a cluster with the same name
+	// in EKS does not exist (in the `validateCreate` call above). It will find
+	// the one that was created and error. Therefore, updateStatus applies the
+	// status regardless of the resource version so it is successfully updated
+	// in this situation.
+	config = config.DeepCopy()
+	config.Status.Phase = eksv1.PhaseCreating
+	config.Status.FailureMessage = ""
+	config.Status.CloudFormationStacksMigrated = true
+	if aws.ToString(config.Spec.ServiceRole) == "" && config.Status.ProvisioningBackend != awsservices.ProvisioningBackendNative {
+		config.Status.ProvisioningBackend = awsservices.ProvisioningBackendCloudFormation
+		setStackStatus(config, getServiceRoleName(config.Spec.DisplayName), "", string(cftypes.StackStatusCreateComplete))
+	}
+	return h.updateStatus(config)
 }
 
 func (h *Handler) validateCreate(ctx context.Context, config *eksv1.EKSClusterConfig, awsSVCs *awsServices) error {
@@ -433,8 +825,6 @@ func (h *Handler) validateCreate(ctx context.Context, config *eksv1.EKSClusterCo
 		}
 	}
 
-	// validate nodegroup version
-	nodeP := map[string]bool{}
 	if !config.Spec.Imported {
 		// Check for existing clusters in EKS with the same display name
 		listOutput, err := awsSVCs.eks.ListClusters(ctx, &eks.ListClustersInput{})
@@ -443,13 +833,83 @@ func (h *Handler) validateCreate(ctx context.Context, config *eksv1.EKSClusterCo
 		}
 		for _, cluster := range listOutput.Clusters {
 			if cluster == config.Spec.DisplayName {
-				return fmt.Errorf("cannot create cluster [%s (id: %s)] because a cluster in EKS exists with the same name", config.Spec.DisplayName, config.Name)
+				return fmt.Errorf("cannot create cluster [%s (id: %s)] because a cluster in EKS exists with the same name, set adoptExisting to import it", config.Spec.DisplayName, config.Name)
 			}
 		}
+	}
+
+	return validateCreateSpec(config)
+}
+
+// validateCreateSpec checks the spec of a cluster that is about to be created or imported. It doesn't call AWS or the
+// management cluster, so that configs can also be checked offline.
+func validateCreateSpec(config *eksv1.EKSClusterConfig) error {
+	if err := validatePodIdentityAssociations(config); err != nil {
+		return err
+	}
+
+	if err := validateIdentityProviderConfigs(config); err != nil {
+		return err
+	}
+
+	if err := validateClusterAdminPrincipals(config); err != nil {
+		return err
+	}
+
+	if err := validateOIDCProviderARN(config); err != nil {
+		return err
+	}
+
+	if err := validateSecretsEncryption(config); err != nil {
+		return err
+	}
+
+	if err := validateOIDCThumbprints(config); err != nil {
+		return err
+	}
+
+	if err := validateMaintenanceWindow(config); err != nil {
+		return err
+	}
+
+	if err := validateServiceRolePolicyARNs(config); err != nil {
+		return err
+	}
+
+	if err := validateIAMRoleOptions(config); err != nil {
+		return err
+	}
+
+	if err := validateNodeGroupDrain(config); err != nil {
+		return err
+	}
+
+	if err := validateDownstreamTLS(config); err != nil {
+		return err
+	}
+
+	if err := validateDeletionPolicy(config); err != nil {
+		return err
+	}
+
+	if err := validateAuditLogging(config); err != nil {
+		return err
+	}
+
+	if err := validateSharedLaunchTemplates(config); err != nil {
+		return err
+	}
+
+	// validate nodegroup version
+	nodeP := map[string]bool{}
+	if !config.Spec.Imported {
 		cannotBeNilError := "field [%s] cannot be nil for non-import cluster [%s (id: %s)]"
 		if config.Spec.KubernetesVersion == nil {
 			return fmt.Errorf(cannotBeNilError, "kubernetesVersion", config.Spec.DisplayName, config.Name)
 		}
+		if _, err := utils.NormalizeKubernetesVersion(aws.ToString(config.Spec.KubernetesVersion)); err != nil {
+			return fmt.Errorf("invalid version format for cluster [%s (id: %s)]: %w", config.Spec.DisplayName, config.Name, err)
+		}
 		if config.Spec.PrivateAccess == nil {
 			return fmt.Errorf(cannotBeNilError, "privateAccess", config.Spec.DisplayName, config.Name)
 		}
@@ -474,6 +934,12 @@ func (h *Handler) validateCreate(ctx context.Context, config *eksv1.EKSClusterCo
 		if config.Spec.PublicAccessSources == nil {
 			return fmt.Errorf(cannotBeNilError, "publicAccessSources", config.Spec.DisplayName, config.Name)
 		}
+		if err := validateOutpostConfig(config); err != nil {
+			return err
+		}
+		if err := validateVPCMode(config); err != nil {
+			return err
+		}
 	}
 	for _, ng := range config.Spec.NodeGroups {
 		cannotBeNilError := "field [%s] cannot be nil for nodegroup [%s] in non-nil cluster [%s (id: %s)]"
@@ -482,7 +948,7 @@ func (h *Handler) validateCreate(ctx context.Context, config *eksv1.EKSClusterCo
 				if ng.LaunchTemplate.ID == nil {
 					return fmt.Errorf(cannotBeNilError, "launchTemplate.ID", *ng.NodegroupName, config.Spec.DisplayName, config.Name)
 				}
-				if ng.LaunchTemplate.Version == nil {
+				if ng.LaunchTemplate.Version == nil && getSharedLaunchTemplate(config, ng.LaunchTemplate) == nil {
 					return fmt.Errorf(cannotBeNilError, "launchTemplate.Version", *ng.NodegroupName, config.Spec.DisplayName, config.Name)
 				}
 			} else {
@@ -536,7 +1002,31 @@ func (h *Handler) validateCreate(ctx context.Context, config *eksv1.EKSClusterCo
 			if ng.RequestSpotInstances == nil {
 				return fmt.Errorf(cannotBeNilError, "requestSpotInstances", *ng.NodegroupName, config.Spec.DisplayName, config.Name)
 			}
-			if ng.NodeRole == nil {
+			if err := validateMetadataOptions(config, ng); err != nil {
+				return err
+			}
+			if err := validatePlacement(config, ng); err != nil {
+				return err
+			}
+			if err := validateBlockDeviceMappings(config, ng); err != nil {
+				return err
+			}
+			if err := validateNetworkInterfaces(config, ng); err != nil {
+				return err
+			}
+			if err := validateVolumeOptions(config, ng); err != nil {
+				return err
+			}
+			if err := validateScheduledScaling(config, ng); err != nil {
+				return err
+			}
+			if err := validateReleaseVersion(config, ng); err != nil {
+				return err
+			}
+			if err := validateRecreatePolicy(config, ng); err != nil {
+				return err
+			}
+			if ng.NodeRole == nil && config.Spec.NodeRole == nil {
 				logrus.Warnf("nodeRole is not specified for nodegroup [%s] in cluster [%s (id: %s)], the controller will generate it", aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name)
 			}
 			if aws.ToBool(ng.RequestSpotInstances) {
@@ -549,13 +1039,55 @@ func (h *Handler) validateCreate(ctx context.Context, config *eksv1.EKSClusterCo
 				}
 			}
 		}
-		if aws.ToString(ng.Version) != *config.Spec.KubernetesVersion {
+		if aws.ToString(ng.Version) != aws.ToString(config.Spec.KubernetesVersion) {
 			return fmt.Errorf("nodegroup [%s] version must match cluster [%s (id: %s)] version on create", aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name)
 		}
 	}
 	return nil
 }
 
+// validateOIDCProviderARN checks that the OIDC provider in the spec, if any, is the ARN of an IAM OIDC provider.
+func validateOIDCProviderARN(config *eksv1.EKSClusterConfig) error {
+	providerARN := aws.ToString(config.Spec.OIDCProviderARN)
+	if providerARN == "" {
+		return nil
+	}
+
+	parsed, err := arn.Parse(providerARN)
+	if err != nil || parsed.Service != "iam" || !strings.HasPrefix(parsed.Resource, "oidc-provider/") {
+		return fmt.Errorf("field [oidcProviderArn] must be the ARN of an IAM OIDC provider for cluster [%s (id: %s)], got [%s]",
+			config.Spec.DisplayName, config.Name, providerARN)
+	}
+
+	return nil
+}
+
+// validateSecretsEncryption checks that a KMS key is set when secrets encryption is enabled.
+func validateSecretsEncryption(config *eksv1.EKSClusterConfig) error {
+	if aws.ToBool(config.Spec.SecretsEncryption) && aws.ToString(config.Spec.KmsKey) == "" {
+		return fmt.Errorf("field [kmsKey] must be set when secrets encryption is enabled for cluster [%s (id: %s)]",
+			config.Spec.DisplayName, config.Name)
+	}
+	return nil
+}
+
+// validateOIDCThumbprints checks that the OIDC thumbprints in the spec are sha1 thumbprints and that there are no more
+// than IAM allows for an OIDC provider.
+func validateOIDCThumbprints(config *eksv1.EKSClusterConfig) error {
+	if len(config.Spec.OIDCThumbprints) > maxOIDCThumbprints {
+		return fmt.Errorf("field [oidcThumbprints] can't have more than %d thumbprints for cluster [%s (id: %s)]",
+			maxOIDCThumbprints, config.Spec.DisplayName, config.Name)
+	}
+	for _, thumbprint := range config.Spec.OIDCThumbprints {
+		if decoded, err := hex.DecodeString(thumbprint); err != nil || len(decoded) != sha1.Size {
+			return fmt.Errorf("field [oidcThumbprints] must only contain sha1 thumbprints of 40 hex characters for cluster [%s (id: %s)], got [%s]",
+				config.Spec.DisplayName, config.Name, thumbprint)
+		}
+	}
+
+	return nil
+}
+
 func (h *Handler) generateAndSetNetworking(ctx context.Context, config *eksv1.EKSClusterConfig, awsSVCs *awsServices) (*eksv1.EKSClusterConfig, error) {
 	if awsSVCs == nil {
 		return nil, fmt.Errorf("aws services not initialized")
@@ -569,62 +1101,143 @@ func (h *Handler) generateAndSetNetworking(ctx context.Context, config *eksv1.EK
 	if len(config.Spec.Subnets) != 0 {
 		logrus.Infof("VPC info provided, skipping vpc/subnet/securitygroup creation")
 		config = config.DeepCopy()
+		if err := setupProvidedSubnets(ctx, config, awsSVCs); err != nil {
+			return config, err
+		}
 		// copy networking fields to status
 		config.Status.Subnets = config.Spec.Subnets
 		config.Status.SecurityGroups = config.Spec.SecurityGroups
 		config.Status.NetworkFieldsSource = "provided"
 	} else {
-		logrus.Infof("Bringing up vpc")
+		templateName, templateBody := awsservices.TemplateVPC, templates.VpcTemplate
+		if config.Spec.VPCMode == vpcModePrivate {
+			logrus.Infof("Bringing up private vpc with nat gateways")
+			templateName, templateBody = awsservices.TemplatePrivateVPC, templates.PrivateVpcTemplate
+		} else {
+			logrus.Infof("Bringing up vpc")
+		}
+		templateOverrides, err := h.getTemplateOverrides(config)
+		if err != nil {
+			return config, err
+		}
+		templateBody, err = awsservices.RenderTemplate(templateOverrides, templateName, templateBody, awsservices.NewTemplateParameters(config))
+		if err != nil {
+			return config, err
+		}
 		stack, err := awsservices.CreateStack(ctx, &awsservices.CreateStackOptions{
 			CloudFormationService: awsSVCs.cloudformation,
 			StackName:             getVPCStackName(config.Spec.DisplayName),
 			DisplayName:           config.Spec.DisplayName,
-			TemplateBody:          templates.VpcTemplate,
+			TemplateBody:          templateBody,
 			Capabilities:          []cftypes.Capability{},
 			Parameters:            []cftypes.Parameter{},
+			Tags:                  awsservices.OwnershipTags(config),
+			Template:              templateName,
 		})
 		if err != nil {
-			return config, fmt.Errorf("error creating stack with VPC template: %v", err)
+			if awsservices.CloudFormationUnavailable(err) {
+				return config, fmt.Errorf("CloudFormation is unavailable, subnets must be provided to create cluster [%s (id: %s)] without it: %w",
+					config.Spec.DisplayName, config.Name, err)
+			}
+			return config, fmt.Errorf("error creating stack with VPC template: %w", err)
 		}
 
 		virtualNetworkString := getParameterValueFromOutput("VpcId", stack.Stacks[0].Outputs)
 		subnetIDsString := getParameterValueFromOutput("SubnetIds", stack.Stacks[0].Outputs)
 
-		if subnetIDsString == "" {
-			return config, fmt.Errorf("no subnet ids were returned")
-		}
-
 		config = config.DeepCopy()
 		// copy generated field to status
 		config.Status.VirtualNetwork = virtualNetworkString
 		config.Status.Subnets = strings.Split(subnetIDsString, ",")
 		config.Status.NetworkFieldsSource = "generated"
+		setStackStatus(config, getVPCStackName(config.Spec.DisplayName), aws.ToString(stack.Stacks[0].StackId), string(stack.Stacks[0].StackStatus))
+	}
+
+	return h.updateStatus(config)
+}
+
+// rolePolicies are the additional policies of a role the operator created, along with the stack it was created with
+// unless it was created through IAM.
+type rolePolicies struct {
+	stackName  string
+	template   string
+	roleARN    string
+	policyARNs []string
+	// policies previously attached through IAM, detached if they aren't in policyARNs anymore
+	attached []string
+}
+
+// updateRolePolicies updates the additional policies of a role the operator created. Roles created through
+// CloudFormation are only updated through the AdditionalPolicyArns parameter of their stack, so that the stack keeps
+// owning their policies instead of drifting, roles created through IAM are updated through IAM.
+func (h *Handler) updateRolePolicies(ctx context.Context, config *eksv1.EKSClusterConfig, awsSVCs *awsServices, policies *rolePolicies) error {
+	if config.Status.ProvisioningBackend == awsservices.ProvisioningBackendNative && !stackRecorded(config, policies.stackName) {
+		_, err := awsservices.UpdateRolePolicies(ctx, &awsservices.UpdateRolePoliciesOpts{
+			IAMService:         awsSVCs.iam,
+			RoleARN:            policies.roleARN,
+			PolicyARNs:         policies.policyARNs,
+			AttachedPolicyARNs: policies.attached,
+		})
+		return err
 	}
 
-	return h.eksCC.UpdateStatus(config)
+	templateOverrides, err := h.getTemplateOverrides(config)
+	if err != nil {
+		return err
+	}
+	stackName := policies.stackName
+	if id := recordedStackID(config, stackName); id != "" {
+		stackName = id
+	}
+	_, err = awsservices.UpdateRoleStackPolicies(ctx, &awsservices.UpdateRoleStackPoliciesOpts{
+		CloudFormationService: awsSVCs.cloudformation,
+		Config:                config,
+		StackName:             stackName,
+		Template:              policies.template,
+		TemplateOverrides:     templateOverrides,
+		PolicyARNs:            policies.policyARNs,
+	})
+	return err
 }
 
 func (h *Handler) createOrGetServiceRole(ctx context.Context, config *eksv1.EKSClusterConfig, awsSVCs *awsServices) (string, error) {
+	if aws.ToString(config.Spec.ServiceRole) == "" && config.Status.ProvisioningBackend == awsservices.ProvisioningBackendNative {
+		logrus.Infof("Creating service role through IAM")
+		return awsservices.CreateServiceRole(ctx, &awsservices.CreateServiceRoleOpts{
+			IAMService: awsSVCs.iam,
+			Config:     config,
+			RoleName:   getServiceRoleName(config.Spec.DisplayName),
+		})
+	}
+
 	var roleARN string
 	if aws.ToString(config.Spec.ServiceRole) == "" {
 		logrus.Infof("Creating service role")
 
+		templateOverrides, err := h.getTemplateOverrides(config)
+		if err != nil {
+			return "", err
+		}
+		templateBody, err := awsservices.RenderTemplate(templateOverrides, awsservices.TemplateServiceRole, templates.ServiceRoleTemplate,
+			awsservices.NewTemplateParameters(config))
+		if err != nil {
+			return "", err
+		}
 		stack, err := awsservices.CreateStack(ctx, &awsservices.CreateStackOptions{
 			CloudFormationService: awsSVCs.cloudformation,
 			StackName:             getServiceRoleName(config.Spec.DisplayName),
 			DisplayName:           config.Spec.DisplayName,
-			TemplateBody:          templates.ServiceRoleTemplate,
+			TemplateBody:          templateBody,
 			Capabilities:          []cftypes.Capability{cftypes.CapabilityCapabilityIam},
-			Parameters:            nil,
+			Parameters:            awsservices.RoleStackParameters(config, config.Spec.ServiceRolePolicyARNs),
+			Tags:                  awsservices.OwnershipTags(config),
+			Template:              awsservices.TemplateServiceRole,
 		})
 		if err != nil {
-			return "", fmt.Errorf("error creating stack with service role template: %v", err)
+			return "", fmt.Errorf("error creating stack with service role template: %w", err)
 		}
 
 		roleARN = getParameterValueFromOutput("RoleArn", stack.Stacks[0].Outputs)
-		if roleARN == "" {
-			return "", fmt.Errorf("no RoleARN was returned")
-		}
 	} else {
 		logrus.Infof("Retrieving existing service role")
 		role, err := awsSVCs.iam.GetRole(ctx, &iam.GetRoleInput{
@@ -676,12 +1289,15 @@ func (h *Handler) waitForCreationComplete(ctx context.Context, config *eksv1.EKS
 		}
 		logrus.Infof("Cluster [%s (id: %s)] created successfully", config.Spec.DisplayName, config.Name)
 		config = config.DeepCopy()
-		config.Status.Phase = eksConfigActivePhase
-		return h.eksCC.UpdateStatus(config)
+		config.Status.Phase = eksv1.PhaseActive
+		setUpstreamClusterStatus(config, state.Cluster)
+		setUpdateGeneration(config)
+		setObservedGeneration(config)
+		return h.updateStatus(config)
 	}
 
 	logrus.Infof("Waiting for cluster [%s (id: %s)] to finish creating", config.Spec.DisplayName, config.Name)
-	h.eksEnqueueAfter(config.Namespace, config.Name, 30*time.Second)
+	h.requeue(config, creatingInterval)
 
 	return config, nil
 }
@@ -689,13 +1305,17 @@ func (h *Handler) waitForCreationComplete(ctx context.Context, config *eksv1.EKS
 // updateUpstreamClusterState compares the upstream spec with the config spec, then updates the upstream EKS cluster to
 // match the config spec. Function often returns after a single update because once the cluster is in updating phase in EKS,
 // no more updates will be accepted until the current update is finished.
-func (h *Handler) updateUpstreamClusterState(ctx context.Context, upstreamSpec *eksv1.EKSClusterConfigSpec, config *eksv1.EKSClusterConfig, awsSVCs *awsServices, clusterARN string, ngARNs map[string]string) (*eksv1.EKSClusterConfig, error) {
+func (h *Handler) updateUpstreamClusterState(ctx context.Context, upstreamSpec *eksv1.EKSClusterConfigSpec, userDataHashes map[string]string, config *eksv1.EKSClusterConfig, awsSVCs *awsServices, clusterARN string, ngARNs map[string]string) (*eksv1.EKSClusterConfig, error) {
 	if awsSVCs == nil {
 		return config, fmt.Errorf("aws services not initialized")
 	}
 
+	// disruptive updates are deferred until the maintenance window opens, the others are made right away
+	maintenanceOpen, untilMaintenance := maintenanceWindowOpen(config, time.Now())
+	var deferredUpdates []string
+
 	if config.Spec.KubernetesVersion != nil && upstreamSpec.KubernetesVersion != nil {
-		configVersion, err := semver.ParseTolerant(aws.ToString(config.Spec.KubernetesVersion))
+		configVersion, err := utils.ParseKubernetesVersion(aws.ToString(config.Spec.KubernetesVersion))
 		if err != nil {
 			return config, fmt.Errorf("couldn't parse config version: %w", err)
 		}
@@ -704,8 +1324,21 @@ func (h *Handler) updateUpstreamClusterState(ctx context.Context, upstreamSpec *
 			return config, fmt.Errorf("couldn't parse upstream version: %w", err)
 		}
 
-		// check kubernetes version for update
+		// check kubernetes version for update, the control plane isn't upgraded while the upstream node groups are too
+		// old for the new version, so that their kubelets keep working. The rest of the update continues, which
+		// upgrades the node groups if their versions in the spec allow it.
+		var outsideSkew []string
 		if configVersion.GT(upstreamVersion) {
+			outsideSkew = getNodegroupsOutsideVersionSkew(configVersion, upstreamSpec.NodeGroups)
+		}
+		if updatedConfig := config.DeepCopy(); h.setNodeGroupVersionSkewStatus(updatedConfig, aws.ToString(config.Spec.KubernetesVersion), outsideSkew) {
+			return h.updateStatus(updatedConfig)
+		}
+		if len(outsideSkew) != 0 {
+			logrus.Debugf("Upgrade of cluster [%s (id: %s)] is blocked by node groups %v", config.Spec.DisplayName, config.Name, outsideSkew)
+		} else if configVersion.GT(upstreamVersion) && !maintenanceOpen {
+			deferredUpdates = append(deferredUpdates, fmt.Sprintf("upgrade the cluster to kubernetes version %s", aws.ToString(config.Spec.KubernetesVersion)))
+		} else if configVersion.GT(upstreamVersion) {
 			updated, err := awsservices.UpdateClusterVersion(ctx, &awsservices.UpdateClusterVersionOpts{
 				EKSService:          awsSVCs.eks,
 				Config:              config,
@@ -746,14 +1379,49 @@ func (h *Handler) updateUpstreamClusterState(ctx context.Context, upstreamSpec *
 		}
 	}
 
-	// check tags for update
+	if config.Spec.SecurityGroups != nil {
+		updated, err := awsservices.UpdateClusterSecurityGroups(ctx, &awsservices.UpdateClusterSecurityGroupsOpts{
+			EKSService:          awsSVCs.eks,
+			Config:              config,
+			UpstreamClusterSpec: upstreamSpec,
+		})
+		if err != nil && !isResourceInUse(err) {
+			return config, fmt.Errorf("error updating cluster security groups: %w", err)
+		}
+		if updated {
+			return h.enqueueUpdate(config)
+		}
+	}
+
+	if config.Spec.SecretsEncryption != nil {
+		updated, err := awsservices.UpdateSecretsEncryption(ctx, &awsservices.UpdateSecretsEncryptionOpts{
+			EKSService:          awsSVCs.eks,
+			Config:              config,
+			UpstreamClusterSpec: upstreamSpec,
+		})
+		if err != nil && !isResourceInUse(err) {
+			return config, fmt.Errorf("error updating secrets encryption: %w", err)
+		}
+		if updated {
+			return h.enqueueUpdate(config)
+		}
+	}
+
+	// check tags for update, resources the credentials aren't allowed to tag are recorded in the TaggingDegraded
+	// condition instead of failing the update
+	var untaggedResources []string
 	if config.Spec.Tags != nil {
 		updated, err := awsservices.UpdateResourceTags(ctx, &awsservices.UpdateResourceTagsOpts{
 			EKSService:   awsSVCs.eks,
-			Tags:         config.Spec.Tags,
+			Tags:         getClusterTags(config),
 			UpstreamTags: upstreamSpec.Tags,
 			ResourceARN:  clusterARN,
 		})
+		if isAccessDenied(err) {
+			logrus.Warnf("Not allowed to update tags of cluster [%s (id: %s)]: %v", config.Spec.DisplayName, config.Name, err)
+			untaggedResources = append(untaggedResources, "cluster")
+			err, updated = nil, false
+		}
 		if err != nil && !isResourceInUse(err) {
 			return config, fmt.Errorf("error updating cluster tags: %w", err)
 		}
@@ -762,6 +1430,24 @@ func (h *Handler) updateUpstreamClusterState(ctx context.Context, upstreamSpec *
 		}
 	}
 
+	// EKS doesn't propagate the tags of the cluster to the security group it created for it, so they are added to it
+	// separately. Tagging it takes effect immediately, so there is nothing to wait for.
+	if tags := getClusterSecurityGroupTags(config.Spec); len(tags) != 0 {
+		_, err := awsservices.UpdateSecurityGroupTags(ctx, &awsservices.UpdateSecurityGroupTagsOpts{
+			EC2Service:      awsSVCs.ec2,
+			SecurityGroupID: config.Status.ClusterSecurityGroup,
+			Tags:            tags,
+		})
+		if isAccessDenied(err) {
+			logrus.Warnf("Not allowed to update tags of the security group of cluster [%s (id: %s)]: %v", config.Spec.DisplayName, config.Name, err)
+			untaggedResources = append(untaggedResources, "cluster security group")
+			err = nil
+		}
+		if err != nil {
+			return config, fmt.Errorf("error updating cluster security group tags: %w", err)
+		}
+	}
+
 	if config.Spec.LoggingTypes != nil {
 		// check logging for update
 		updated, err := awsservices.UpdateClusterLoggingTypes(ctx, &awsservices.UpdateLoggingTypesOpts{
@@ -777,15 +1463,123 @@ func (h *Handler) updateUpstreamClusterState(ctx context.Context, upstreamSpec *
 		}
 	}
 
+	// the log group is created by EKS once logs are sent to it, so the audit logging waits for it
+	if !auditLoggingApplied(config) {
+		err := awsservices.UpdateAuditLogging(ctx, &awsservices.UpdateAuditLoggingOpts{
+			CloudWatchLogsService: awsSVCs.logs,
+			CloudWatchService:     awsSVCs.cloudwatch,
+			Config:                config,
+		})
+		switch {
+		case logGroupNotFound(err):
+			logrus.Infof("Waiting for log group of cluster [%s (id: %s)] to apply audit logging", config.Spec.DisplayName, config.Name)
+			h.requeue(config, updatingInterval)
+		case err != nil:
+			return config, fmt.Errorf("error updating audit logging of cluster [%s (id: %s)]: %w", config.Spec.DisplayName, config.Name, err)
+		default:
+			config = config.DeepCopy()
+			config.Status.AuditLogging = config.Spec.AuditLogging.DeepCopy()
+			return h.updateStatus(config)
+		}
+	}
+
+	// the service role is only changed if the operator created it, policy changes take effect right away
+	serviceRoleStack := getServiceRoleName(config.Spec.DisplayName)
+	if (stackRecorded(config, serviceRoleStack) ||
+		aws.ToString(config.Spec.ServiceRole) == "" && config.Status.ProvisioningBackend == awsservices.ProvisioningBackendNative) &&
+		!utils.CompareStringSliceElements(config.Status.ServiceRolePolicyARNs, config.Spec.ServiceRolePolicyARNs) {
+		if err := h.updateRolePolicies(ctx, config, awsSVCs, &rolePolicies{
+			stackName:  serviceRoleStack,
+			template:   awsservices.TemplateServiceRole,
+			roleARN:    aws.ToString(upstreamSpec.ServiceRole),
+			policyARNs: config.Spec.ServiceRolePolicyARNs,
+			attached:   config.Status.ServiceRolePolicyARNs,
+		}); err != nil {
+			return config, fmt.Errorf("error updating service role policies of cluster [%s (id: %s)]: %w", config.Spec.DisplayName, config.Name, err)
+		}
+		config = config.DeepCopy()
+		config.Status.ServiceRolePolicyARNs = slices.Clone(config.Spec.ServiceRolePolicyARNs)
+		return h.updateStatus(config)
+	}
+
+	// the node instance role is only changed if the operator created it, the policy of the CloudWatch agent is attached
+	// while Container Insights is enabled, before the add-on is installed below
+	nodeInstanceRolePolicyARNs := awsservices.NodeInstanceRoleAdditionalPolicyARNs(config)
+	if config.Status.GeneratedNodeRole != "" &&
+		!utils.CompareStringSliceElements(config.Status.NodeInstanceRolePolicyARNs, nodeInstanceRolePolicyARNs) {
+		if err := h.updateRolePolicies(ctx, config, awsSVCs, &rolePolicies{
+			stackName:  getNodeInstanceRoleStackName(config.Spec.DisplayName),
+			template:   awsservices.TemplateNodeInstanceRole,
+			roleARN:    config.Status.GeneratedNodeRole,
+			policyARNs: nodeInstanceRolePolicyARNs,
+			attached:   config.Status.NodeInstanceRolePolicyARNs,
+		}); err != nil {
+			return config, fmt.Errorf("error updating node instance role policies of cluster [%s (id: %s)]: %w", config.Spec.DisplayName, config.Name, err)
+		}
+		config = config.DeepCopy()
+		config.Status.NodeInstanceRolePolicyARNs = nodeInstanceRolePolicyARNs
+		return h.updateStatus(config)
+	}
+
+	if config.Spec.PodIdentityAssociations != nil {
+		// check pod identity associations for update
+		updated, err := updatePodIdentity(ctx, config, awsSVCs)
+		if err != nil && !isResourceInUse(err) {
+			return config, fmt.Errorf("error updating pod identity associations: %w", err)
+		}
+		if updated {
+			return h.enqueueUpdate(config)
+		}
+	}
+
+	if !utils.CompareStringSliceElements(config.Status.ClusterAdminPrincipals, config.Spec.ClusterAdminPrincipals) {
+		// check cluster admin access entries for update
+		updating, err := updateClusterAdminPrincipals(ctx, config, awsSVCs)
+		if err != nil && !isResourceInUse(err) {
+			return config, fmt.Errorf("error updating cluster admin principals: %w", err)
+		}
+		if updating {
+			return h.enqueueUpdate(config)
+		}
+		if err == nil {
+			config = config.DeepCopy()
+			config.Status.ClusterAdminPrincipals = slices.Clone(config.Spec.ClusterAdminPrincipals)
+			return h.updateStatus(config)
+		}
+	}
+
+	if config.Spec.IdentityProviderConfigs != nil {
+		// check identity provider configs for update
+		updated, pending, err := updateIdentityProviders(ctx, config, awsSVCs)
+		if err != nil && !isResourceInUse(err) {
+			return config, fmt.Errorf("error updating identity provider configs: %w", err)
+		}
+		if updated {
+			return h.enqueueUpdate(config)
+		}
+		if pending {
+			if config.Status.Phase != eksv1.PhaseUpdating {
+				config = config.DeepCopy()
+				config.Status.Phase = eksv1.PhaseUpdating
+				return h.updateStatus(config)
+			}
+			logrus.Infof("Waiting for identity provider configs of cluster [%s (id: %s)] to be associated or disassociated", config.Spec.DisplayName, config.Name)
+			h.requeue(config, updatingInterval)
+			return config, nil
+		}
+	}
+
 	if config.Spec.NodeGroups == nil {
-		if config.Status.Phase != eksConfigActivePhase {
+		if config.Status.Phase != eksv1.PhaseActive || config.Status.ObservedGeneration != config.Generation {
 			logrus.Infof("Cluster [%s (id: %s)] finished updating", config.Spec.DisplayName, config.Name)
 			config = config.DeepCopy()
-			config.Status.Phase = eksConfigActivePhase
-			return h.eksCC.UpdateStatus(config)
+			config.Status.Phase = eksv1.PhaseActive
+			setObservedGeneration(config)
+			h.setDownstreamHealthyStatus(ctx, config, awsSVCs)
+			return h.updateStatus(config)
 		}
 
-		return config, nil
+		return h.reprobeDownstream(ctx, config, awsSVCs)
 	}
 
 	// check nodegroups for updates
@@ -797,21 +1591,91 @@ func (h *Handler) updateUpstreamClusterState(ctx context.Context, upstreamSpec *
 		upstreamNgs[aws.ToString(ng.NodegroupName)] = ng
 	}
 
-	for _, ng := range config.Spec.NodeGroups {
-		ngs[aws.ToString(ng.NodegroupName)] = ng
+	for _, ng := range selectedNodeGroups(config) {
+		// the sizes of node groups follow their active scaling window
+		ng = applyScalingWindow(ng, config.Status.NodeGroupScalingWindows[aws.ToString(ng.NodegroupName)])
+		ng = applyClusterAutoscalerTags(config, applySharedLaunchTemplate(config, ng))
+		ngs[aws.ToString(ng.NodegroupName)] = applyOwnershipTags(config, ng)
 	}
 
 	// Deep copy the config object here, so it's not copied multiple times for each
 	// nodegroup create/delete.
 	config = config.DeepCopy()
 
+	// node groups with deletion protection are not deleted when they are removed from the spec
+	protectedNodeGroups, blockedNodeGroups := getDeletionProtectedNodeGroups(selectedNodeGroups(config), config.Status.DeletionProtectedNodeGroups, upstreamNgs)
+	if setNodeGroupDeletionProtectionStatus(config, protectedNodeGroups, blockedNodeGroups) {
+		if len(blockedNodeGroups) != 0 {
+			logrus.Warnf("Deletion of node groups [%s] for cluster [%s (id: %s)] is blocked by deletion protection",
+				strings.Join(blockedNodeGroups, ", "), config.Spec.DisplayName, config.Name)
+			h.recordEvent(config, corev1.EventTypeWarning, eventReasonNodegroupDeletionBlocked, nodeGroupDeletionBlocked.GetMessage(config))
+		}
+		return h.updateStatus(config)
+	}
+	blocked := make(map[string]struct{}, len(blockedNodeGroups))
+	for _, name := range blockedNodeGroups {
+		blocked[name] = struct{}{}
+	}
+
+	// record the hashes of the upstream userdata, which are used to detect userdata changes in the spec
+	if !utils.CompareStringMaps(config.Status.NodeGroupUserDataHashes, userDataHashes) {
+		config.Status.NodeGroupUserDataHashes = userDataHashes
+		return h.updateStatus(config)
+	}
+
+	// node groups with changes EKS can't update are deleted and created again if their recreate policy allows it
+	recreation := planNodeGroupRecreation(selectedNodeGroups(config), upstreamNgs, maintenanceOpen)
+	if setNodeGroupRecreationStatus(config, recreation.required) {
+		if len(recreation.required) != 0 {
+			logrus.Warnf("Node groups of cluster [%s (id: %s)] have changes that require them to be recreated: %s",
+				config.Spec.DisplayName, config.Name, strings.Join(recreation.required, "; "))
+			h.recordEvent(config, corev1.EventTypeWarning, eventReasonNodegroupRecreationRequired, nodeGroupRecreationRequired.GetMessage(config))
+		}
+		return h.updateStatus(config)
+	}
+	if pruneRecreatingNodeGroups(config, selectedNodeGroups(config)) {
+		return h.updateStatus(config)
+	}
+	for _, name := range recreation.deferred {
+		deferredUpdates = append(deferredUpdates, fmt.Sprintf("recreate node group [%s]", name))
+	}
+	replacing := make(map[string]struct{}, len(recreation.replace))
+	for _, ng := range recreation.replace {
+		replacing[aws.ToString(ng.NodegroupName)] = struct{}{}
+	}
+
+	// node groups that are about to be created get the defaults of the operator, updating the spec enqueues the config
+	if defaulted, err := h.applyNodeGroupDefaults(config, upstreamNgs); err != nil || defaulted != config {
+		return defaulted, err
+	}
+
 	// check if node groups need to be created
 	var updatingNodegroups bool
+	var recreatingChanged bool
+	var nodegroupErrs []error
+	var stackInProgress *awsservices.StackCreationInProgressError
+	var fellBackToNativeProvisioning, checkedFallback bool
 	templateVersionsToAdd := make(map[string]string)
-	for _, ng := range config.Spec.NodeGroups {
-		if _, ok := upstreamNgs[aws.ToString(ng.NodegroupName)]; ok {
+	var nodeGroupsToCreate []eksv1.NodeGroup
+	for _, ng := range selectedNodeGroups(config) {
+		name := aws.ToString(ng.NodegroupName)
+		if _, ok := upstreamNgs[name]; ok {
+			continue
+		}
+		if waitingForRecreation(config, name) {
+			logrus.Infof("Waiting for node group [%s] of cluster [%s (id: %s)] to delete before creating it again", name, config.Spec.DisplayName, config.Name)
+			updatingNodegroups = true
 			continue
 		}
+		nodeGroupsToCreate = append(nodeGroupsToCreate, applyClusterAutoscalerTags(config, applySharedLaunchTemplate(config, ng)))
+	}
+	var templateOverrides map[string]string
+	if len(nodeGroupsToCreate) != 0 {
+		var err error
+		templateOverrides, err = h.getTemplateOverrides(config)
+		if err != nil {
+			return config, err
+		}
 		if err := awsservices.CreateLaunchTemplate(ctx, &awsservices.CreateLaunchTemplateOptions{
 			EC2Service: awsSVCs.ec2,
 			Config:     config,
@@ -820,69 +1684,156 @@ func (h *Handler) updateUpstreamClusterState(ctx context.Context, upstreamSpec *
 		}
 		// in this case update is set right away because creating the
 		// nodegroup may not be immediate
-		if config.Status.Phase != eksConfigUpdatingPhase {
-			config.Status.Phase = eksConfigUpdatingPhase
+		if config.Status.Phase != eksv1.PhaseUpdating {
+			config.Status.Phase = eksv1.PhaseUpdating
 			var err error
-			config, err = h.eksCC.UpdateStatus(config)
+			config, err = h.updateStatus(config)
 			if err != nil {
 				return config, err
 			}
 		}
+	}
 
+	// node groups are created concurrently, the results are recorded in the order of the spec
+	createResults := forEachNodeGroup(nodeGroupsToCreate, func(ng eksv1.NodeGroup) nodeGroupCreateResult {
 		ltVersion, generatedNodeRole, err := awsservices.CreateNodeGroup(ctx, &awsservices.CreateNodeGroupOptions{
 			EC2Service:            awsSVCs.ec2,
 			CloudFormationService: awsSVCs.cloudformation,
 			EKSService:            awsSVCs.eks,
+			IAMService:            awsSVCs.iam,
 			Config:                config,
 			NodeGroup:             ng,
+			TemplateOverrides:     templateOverrides,
 		})
-
-		if err != nil && !isResourceInUse(err) {
-			return config, fmt.Errorf("error creating nodegroup: %w", err)
-		}
-
+		return nodeGroupCreateResult{launchTemplateVersion: ltVersion, generatedNodeRole: generatedNodeRole, err: err}
+	})
+	for i, result := range createResults {
+		name := aws.ToString(nodeGroupsToCreate[i].NodegroupName)
 		// if a generated node role has not been set on the Status yet and it
 		// was just generated, set it
-		if config.Status.GeneratedNodeRole == "" && generatedNodeRole != "" {
-			config.Status.GeneratedNodeRole = generatedNodeRole
+		if config.Status.GeneratedNodeRole == "" && result.generatedNodeRole != "" {
+			config.Status.GeneratedNodeRole = result.generatedNodeRole
+			config.Status.NodeInstanceRolePolicyARNs = awsservices.NodeInstanceRoleAdditionalPolicyARNs(config)
+			if config.Status.ProvisioningBackend != awsservices.ProvisioningBackendNative {
+				config.Status.ProvisioningBackend = awsservices.ProvisioningBackendCloudFormation
+				setStackStatus(config, getNodeInstanceRoleStackName(config.Spec.DisplayName), "", string(cftypes.StackStatusCreateComplete))
+			}
 		}
-		if err != nil {
-			return config, err
+		if inProgress := stackCreationInProgress(result.err); inProgress != nil {
+			stackInProgress = inProgress
+			continue
+		}
+		if config.Status.ProvisioningBackend == "" && !checkedFallback && awsservices.CloudFormationUnavailable(result.err) {
+			// the node groups of a reconcile count as a single stack operation
+			checkedFallback = true
+			if h.shouldFallBackToNativeProvisioning(config, result.err) {
+				h.fallBackToNativeProvisioning(config, result.err)
+				fellBackToNativeProvisioning = true
+			}
 		}
-		templateVersionsToAdd[aws.ToString(ng.NodegroupName)] = ltVersion
+		if fellBackToNativeProvisioning && awsservices.CloudFormationUnavailable(result.err) {
+			// the node group is created with a node instance role from IAM once the backend is recorded
+			continue
+		}
+		if result.err != nil {
+			nodegroupErrs = append(nodegroupErrs, fmt.Errorf("error creating nodegroup [%s]: %w", name, result.err))
+			continue
+		}
+		h.recordEvent(config, corev1.EventTypeNormal, eventReasonNodegroupCreating, "Creating node group [%s]", name)
+		if idx := slices.Index(config.Status.RecreatingNodeGroups, name); idx != -1 {
+			config.Status.RecreatingNodeGroups = slices.Delete(config.Status.RecreatingNodeGroups, idx, idx+1)
+			recreatingChanged = true
+		}
+		templateVersionsToAdd[name] = result.launchTemplateVersion
 		updatingNodegroups = true
 	}
 
 	// check for node groups need to be deleted
 	templateVersionsToDelete := make(map[string]string)
+	var nodeGroupsToDelete []eksv1.NodeGroup
 	for _, ng := range upstreamSpec.NodeGroups {
 		if _, ok := ngs[aws.ToString(ng.NodegroupName)]; ok {
 			continue
 		}
-		templateVersionToDelete, _, err := deleteNodeGroup(ctx, config, ng, awsSVCs.eks)
-		if err != nil {
-			return config, err
+		if _, ok := blocked[aws.ToString(ng.NodegroupName)]; ok {
+			continue
+		}
+		nodeGroupsToDelete = append(nodeGroupsToDelete, ng)
+	}
+	nodeGroupsToDelete = append(nodeGroupsToDelete, recreation.replace...)
+	// node groups are only deleted once their nodes are drained
+	draining, drainStartTimesChanged := h.drainNodeGroups(ctx, config, nodeGroupsToDelete, awsSVCs)
+	nodeGroupsToDelete = slices.DeleteFunc(nodeGroupsToDelete, func(ng eksv1.NodeGroup) bool {
+		_, ok := draining[aws.ToString(ng.NodegroupName)]
+		return ok
+	})
+	deleteResults := forEachNodeGroup(nodeGroupsToDelete, func(ng eksv1.NodeGroup) nodeGroupDeleteResult {
+		templateVersionToDelete, _, err := deleteNodeGroup(ctx, config, ng, awsSVCs.eks, false)
+		return nodeGroupDeleteResult{templateVersionToDelete: templateVersionToDelete, err: err}
+	})
+	for i, result := range deleteResults {
+		name := aws.ToString(nodeGroupsToDelete[i].NodegroupName)
+		if result.err != nil {
+			nodegroupErrs = append(nodegroupErrs, fmt.Errorf("error deleting nodegroup [%s]: %w", name, result.err))
+			continue
+		}
+		if _, ok := replacing[name]; ok {
+			h.recordEvent(config, corev1.EventTypeNormal, eventReasonNodegroupRecreating,
+				"Deleting node group [%s] to create it again with changes EKS can't update", name)
+			if !slices.Contains(config.Status.RecreatingNodeGroups, name) {
+				config.Status.RecreatingNodeGroups = append(config.Status.RecreatingNodeGroups, name)
+				recreatingChanged = true
+			}
+		} else {
+			h.recordEvent(config, corev1.EventTypeNormal, eventReasonNodegroupDeleting, "Deleting node group [%s]", name)
 		}
 		updatingNodegroups = true
-		if templateVersionToDelete != nil {
-			templateVersionsToDelete[aws.ToString(ng.NodegroupName)] = *templateVersionToDelete
+		if result.templateVersionToDelete != nil {
+			templateVersionsToDelete[name] = *result.templateVersionToDelete
+		}
+	}
+
+	if !updatingNodegroups {
+		if fellBackToNativeProvisioning || drainStartTimesChanged {
+			return h.updateStatus(config)
+		}
+		if len(nodegroupErrs) != 0 {
+			return config, errors.Join(nodegroupErrs...)
+		}
+		if stackInProgress != nil {
+			return h.waitForStack(config, stackInProgress)
 		}
 	}
 
 	if updatingNodegroups {
-		if len(templateVersionsToDelete) != 0 || len(templateVersionsToAdd) != 0 {
-			config.Status.Phase = eksConfigUpdatingPhase
+		// node groups that are waiting for the node instance role stack are created once it is complete, the ones
+		// that failed are reported after the changes of the others are recorded
+		if stackInProgress != nil {
+			setStackStatus(config, stackInProgress.StackName, stackInProgress.StackID, string(cftypes.StackStatusCreateInProgress))
+		}
+		config = config.DeepCopy()
+		generationChanged := setUpdateGeneration(config)
+		if len(templateVersionsToDelete) != 0 || len(templateVersionsToAdd) != 0 || generationChanged || fellBackToNativeProvisioning ||
+			drainStartTimesChanged || recreatingChanged {
+			config.Status.Phase = eksv1.PhaseUpdating
 			config.Status.TemplateVersionsToDelete = append(config.Status.TemplateVersionsToDelete, utils.ValuesFromMap(templateVersionsToDelete)...)
 			config.Status.ManagedLaunchTemplateVersions = utils.SubtractMaps(config.Status.ManagedLaunchTemplateVersions, templateVersionsToDelete)
 			config.Status.ManagedLaunchTemplateVersions = utils.MergeMaps(config.Status.ManagedLaunchTemplateVersions, templateVersionsToAdd)
-			return h.eksCC.UpdateStatus(config)
+			updated, err := h.updateStatus(config)
+			if err != nil {
+				return updated, err
+			}
+			return updated, errors.Join(nodegroupErrs...)
+		}
+		if len(nodegroupErrs) != 0 {
+			return config, errors.Join(nodegroupErrs...)
 		}
 		return h.enqueueUpdate(config)
 	}
 
 	// check node groups for kubernetes version updates
 	desiredNgVersions := make(map[string]string)
-	for _, ng := range config.Spec.NodeGroups {
+	for _, ng := range selectedNodeGroups(config) {
 		if ng.Version != nil {
 			desiredVersion := aws.ToString(ng.Version)
 			if desiredVersion == "" {
@@ -900,7 +1851,15 @@ func (h *Handler) updateUpstreamClusterState(ctx context.Context, upstreamSpec *
 		// Some updates such as minSize, maxSize, and desiredSize can
 		// happen together
 
-		ng := ngs[aws.ToString(upstreamNg.NodegroupName)]
+		ng, ok := ngs[aws.ToString(upstreamNg.NodegroupName)]
+		if !ok {
+			// node group was removed from the spec but its deletion is blocked
+			continue
+		}
+		if _, ok := replacing[aws.ToString(ng.NodegroupName)]; ok {
+			// node group is drained before it is recreated
+			continue
+		}
 		ngVersionInput := &eks.UpdateNodegroupVersionInput{
 			NodegroupName: aws.String(aws.ToString(ng.NodegroupName)),
 			ClusterName:   aws.String(config.Spec.DisplayName),
@@ -908,7 +1867,11 @@ func (h *Handler) updateUpstreamClusterState(ctx context.Context, upstreamSpec *
 
 		// rancherManagedLaunchTemplate is true if user did not specify a custom launch template
 		rancherManagedLaunchTemplate := false
-		if upstreamNg.LaunchTemplate != nil {
+		if !maintenanceOpen {
+			if nodeGroupRolloutPending(config, upstreamNg, ng, desiredNgVersions[aws.ToString(ng.NodegroupName)]) {
+				deferredUpdates = append(deferredUpdates, fmt.Sprintf("roll out node group [%s]", aws.ToString(ng.NodegroupName)))
+			}
+		} else if upstreamNg.LaunchTemplate != nil {
 			upstreamTemplateVersion := aws.ToInt64(upstreamNg.LaunchTemplate.Version)
 			var err error
 			lt := ng.LaunchTemplate
@@ -927,25 +1890,38 @@ func (h *Handler) updateUpstreamClusterState(ctx context.Context, upstreamSpec *
 					}
 					templateVersionsToAdd[aws.ToString(ng.NodegroupName)] = strconv.FormatInt(*lt.Version, 10)
 				}
-			}
 
-			if lt != nil && aws.ToInt64(lt.Version) != upstreamTemplateVersion {
-				ngVersionInput.LaunchTemplate = &ekstypes.LaunchTemplateSpecification{
-					Id:      lt.ID,
-					Version: aws.String(strconv.FormatInt(*lt.Version, 10)),
+				if lt != nil && aws.ToInt64(lt.Version) != upstreamTemplateVersion {
+					ngVersionInput.LaunchTemplate = &ekstypes.LaunchTemplateSpecification{
+						Id:      lt.ID,
+						Version: aws.String(strconv.FormatInt(*lt.Version, 10)),
+					}
+				}
+			} else if lt != nil {
+				// In this case, the user is managing the launch template, so the new version from the spec is sent
+				// along with the kubernetes and AMI release versions if the launch template allows it.
+				if err := setCustomLaunchTemplateVersionUpdate(ctx, ngVersionInput, ng, upstreamNg, desiredNgVersions[aws.ToString(ng.NodegroupName)],
+					releaseVersionUpdate(config, ng), awsSVCs.ec2); err != nil {
+					return config, err
 				}
 			}
 		}
 
-		// a node group created from a custom launch template can only be updated with a new version of the launch template
-		// that uses an AMI with the desired kubernetes version, hence, only update on version mismatch if the node group was created with a rancher-managed launch template
+		// a node group created from a custom launch template can only be updated with a new version of the launch template,
+		// which is handled above, hence, only update on version mismatch here if the node group was created with a rancher-managed launch template
 		if ng.Version != nil && rancherManagedLaunchTemplate {
 			if aws.ToString(upstreamNg.Version) != desiredNgVersions[aws.ToString(ng.NodegroupName)] {
 				ngVersionInput.Version = aws.String(desiredNgVersions[aws.ToString(ng.NodegroupName)])
 			}
 		}
+		// a pinned AMI release version is sent along with the kubernetes version, otherwise EKS picks the latest release
+		if rancherManagedLaunchTemplate {
+			if releaseVersion := releaseVersionUpdate(config, ng); releaseVersion != nil {
+				ngVersionInput.ReleaseVersion = releaseVersion
+			}
+		}
 
-		if ngVersionInput.Version != nil || ngVersionInput.LaunchTemplate != nil {
+		if ngVersionInput.Version != nil || ngVersionInput.LaunchTemplate != nil || ngVersionInput.ReleaseVersion != nil {
 			updateNodegroupProperties = true
 			if err := awsservices.UpdateNodegroupVersion(ctx, &awsservices.UpdateNodegroupVersionOpts{
 				EKSService:     awsSVCs.eks,
@@ -971,16 +1947,23 @@ func (h *Handler) updateUpstreamClusterState(ctx context.Context, upstreamSpec *
 		}
 
 		if ng.Tags != nil {
-			var err error // initialize error here because we assign returned value to updateNodegroupProperties
-			updateNodegroupProperties, err = awsservices.UpdateResourceTags(ctx, &awsservices.UpdateResourceTagsOpts{
+			tagsUpdated, err := awsservices.UpdateResourceTags(ctx, &awsservices.UpdateResourceTagsOpts{
 				EKSService:   awsSVCs.eks,
 				Tags:         aws.ToStringMap(ng.Tags),
 				UpstreamTags: aws.ToStringMap(upstreamNg.Tags),
 				ResourceARN:  ngARNs[aws.ToString(ng.NodegroupName)],
 			})
+			if isAccessDenied(err) {
+				logrus.Warnf("Not allowed to update tags of node group [%s] in cluster [%s (id: %s)]: %v", aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name, err)
+				untaggedResources = append(untaggedResources, fmt.Sprintf("node group %s", aws.ToString(ng.NodegroupName)))
+				err, tagsUpdated = nil, false
+			}
 			if err != nil {
 				return config, fmt.Errorf("error updating cluster tags: %w", err)
 			}
+			if tagsUpdated {
+				updateNodegroupProperties = true
+			}
 		}
 	}
 
@@ -988,13 +1971,14 @@ func (h *Handler) updateUpstreamClusterState(ctx context.Context, upstreamSpec *
 		// if any updates are taking place on nodegroups, the config's phase needs
 		// to be set to "updating" and the controller will wait for the updates to
 		// finish before proceeding
-		if len(templateVersionsToDelete) != 0 || len(templateVersionsToAdd) != 0 {
-			config = config.DeepCopy()
+		config = config.DeepCopy()
+		generationChanged := setUpdateGeneration(config)
+		if len(templateVersionsToDelete) != 0 || len(templateVersionsToAdd) != 0 || generationChanged {
 			config.Status.TemplateVersionsToDelete = append(config.Status.TemplateVersionsToDelete, utils.ValuesFromMap(templateVersionsToDelete)...)
 			config.Status.ManagedLaunchTemplateVersions = utils.SubtractMaps(config.Status.ManagedLaunchTemplateVersions, templateVersionsToAdd)
 			config.Status.ManagedLaunchTemplateVersions = utils.MergeMaps(config.Status.ManagedLaunchTemplateVersions, templateVersionsToAdd)
-			config.Status.Phase = eksConfigUpdatingPhase
-			return h.eksCC.UpdateStatus(config)
+			config.Status.Phase = eksv1.PhaseUpdating
+			return h.updateStatus(config)
 		}
 		return h.enqueueUpdate(config)
 	}
@@ -1006,30 +1990,124 @@ func (h *Handler) updateUpstreamClusterState(ctx context.Context, upstreamSpec *
 			return nil, fmt.Errorf("error checking if ebs csi driver addon is installed: %w", err)
 		}
 		if installedArn == "" {
+			// pod identity is preferred when the agent is installed, so that the driver doesn't depend on the OIDC
+			// provider of the cluster
+			podIdentityAgentArn, err := awsservices.CheckPodIdentityAgentAddon(ctx, config.Spec.DisplayName, awsSVCs.eks)
+			if err != nil {
+				return nil, fmt.Errorf("error checking if pod identity agent addon is installed: %w", err)
+			}
+			podIdentity := podIdentityAgentArn != ""
+			roleStackName := getEBSCSIDriverRoleStackName(config.Spec.DisplayName)
+			if podIdentity {
+				roleStackName = getEBSCSIDriverPodIdentityRoleStackName(config.Spec.DisplayName)
+			}
+
+			templateOverrides, err := h.getTemplateOverrides(config)
+			if err != nil {
+				return config, err
+			}
 			logrus.Infof("Enabling [ebs csi driver add-on] for cluster [%s (id: %s)]", config.Spec.DisplayName, config.Name)
 			ebsCSIDriverInput := awsservices.EnableEBSCSIDriverInput{
-				EKSService:   awsSVCs.eks,
-				IAMService:   awsSVCs.iam,
-				CFService:    awsSVCs.cloudformation,
-				Config:       config,
-				AddonVersion: "latest",
+				EKSService:        awsSVCs.eks,
+				IAMService:        awsSVCs.iam,
+				CFService:         awsSVCs.cloudformation,
+				Config:            config,
+				AddonVersion:      "latest",
+				PodIdentity:       podIdentity,
+				TemplateOverrides: templateOverrides,
+			}
+			oidcProviderARN, err := awsservices.EnableEBSCSIDriver(ctx, &ebsCSIDriverInput)
+			if oidcProviderARN != "" {
+				// the provider is recorded even if the driver couldn't be enabled, so that it isn't leaked
+				config = config.DeepCopy()
+				config.Status.OIDCProviderARN = oidcProviderARN
 			}
-			if err := awsservices.EnableEBSCSIDriver(ctx, &ebsCSIDriverInput); err != nil {
+			if err != nil {
+				if inProgress := stackCreationInProgress(err); inProgress != nil {
+					return h.waitForStack(config, inProgress)
+				}
+				if oidcProviderARN != "" {
+					if _, updateErr := h.updateStatus(config); updateErr != nil {
+						return config, updateErr
+					}
+				}
 				return config, fmt.Errorf("error enabling ebs csi driver addon: %w", err)
 			}
+			if (setStackStatus(config, roleStackName, "", string(cftypes.StackStatusCreateComplete)) || oidcProviderARN != "") &&
+				config.Status.Phase == eksv1.PhaseActive {
+				return h.updateStatus(config)
+			}
+		} else if stackRecorded(config, getEBSCSIDriverPodIdentityRoleStackName(config.Spec.DisplayName)) {
+			templateOverrides, err := h.getTemplateOverrides(config)
+			if err != nil {
+				return config, err
+			}
+			updated, err := awsservices.UpdateEBSAddonPodIdentity(ctx, &awsservices.UpdateEBSAddonPodIdentityOpts{
+				EKSService:        awsSVCs.eks,
+				CFService:         awsSVCs.cloudformation,
+				Config:            config,
+				TemplateOverrides: templateOverrides,
+			})
+			if err != nil {
+				return config, fmt.Errorf("error updating ebs csi driver addon pod identity: %w", err)
+			}
+			if updated {
+				return h.enqueueUpdate(config)
+			}
+		}
+	}
+
+	if config.Spec.ContainerInsights != nil {
+		// check container insights add-on for update
+		updated, err := updateContainerInsights(ctx, config, awsSVCs)
+		if err != nil && !isResourceInUse(err) {
+			return config, fmt.Errorf("error updating container insights: %w", err)
+		}
+		if updated {
+			return h.enqueueUpdate(config)
+		}
+	}
+
+	if updatedConfig := config.DeepCopy(); setTaggingDegradedStatus(updatedConfig, untaggedResources) {
+		return h.updateStatus(updatedConfig)
+	}
+
+	if updatedConfig := config.DeepCopy(); h.setUpdatesDeferredStatus(updatedConfig, deferredUpdates) {
+		return h.updateStatus(updatedConfig)
+	}
+	if len(deferredUpdates) != 0 && untilMaintenance > 0 {
+		h.eksEnqueueAfter(config.Namespace, config.Name, untilMaintenance)
+	}
+
+	if nodegroupsDegraded.IsTrue(config) {
+		// updates that can remediate the health issues have been sent, keep checking until the node groups recover
+		if config.Status.Phase != eksv1.PhaseUpdating {
+			config = config.DeepCopy()
+			config.Status.Phase = eksv1.PhaseUpdating
+			return h.updateStatus(config)
 		}
+		logrus.Infof("Waiting for degraded nodegroups of cluster [%s (id: %s)] to recover", config.Spec.DisplayName, config.Name)
+		h.requeue(config, updatingInterval)
+		return config, nil
 	}
 
 	// no new updates, set to active
-	if config.Status.Phase != eksConfigActivePhase {
+	if config.Status.Phase != eksv1.PhaseActive || config.Status.ObservedGeneration != config.Generation {
 		logrus.Infof("Cluster [%s (id: %s)] finished updating", config.Spec.DisplayName, config.Name)
 		config = config.DeepCopy()
-		config.Status.Phase = eksConfigActivePhase
-		return h.eksCC.UpdateStatus(config)
+		config.Status.Phase = eksv1.PhaseActive
+		setObservedGeneration(config)
+		h.setDownstreamHealthyStatus(ctx, config, awsSVCs)
+		return h.updateStatus(config)
+	}
+
+	if taggingDegraded.IsTrue(config) {
+		// tagging is retried in case the missing permissions are granted
+		h.requeue(config, updatingInterval)
 	}
 
 	// check for node groups updates here
-	return config, nil
+	return h.reprobeDownstream(ctx, config, awsSVCs)
 }
 
 // importCluster cluster returns a spec representing the upstream state of the cluster matching to the
@@ -1061,9 +2139,88 @@ func (h *Handler) importCluster(ctx context.Context, config *eksv1.EKSClusterCon
 	}
 
 	config.Status.Subnets = clusterState.Cluster.ResourcesVpcConfig.SubnetIds
-	config.Status.SecurityGroups = clusterState.Cluster.ResourcesVpcConfig.SecurityGroupIds
-	config.Status.Phase = eksConfigActivePhase
-	return h.eksCC.UpdateStatus(config)
+	setUpstreamClusterStatus(config, clusterState.Cluster)
+	upstreamSpec, err := getUpstreamSpec(ctx, config, clusterState, awsSVCs)
+	if err != nil {
+		// the configuration is informational, the import doesn't depend on it
+		logrus.Warnf("Could not get the configuration of imported cluster [%s (id: %s)]: %v", config.Spec.DisplayName, config.Name, err)
+	}
+	config.Status.UpstreamSpec = upstreamSpec
+	// the operator doesn't create stacks for imported clusters, so there are none to discover
+	config.Status.CloudFormationStacksMigrated = true
+	config.Status.Phase = eksv1.PhaseActive
+	setObservedGeneration(config)
+	return h.updateStatus(config)
+}
+
+// getResourceUsage counts the AWS resources consumed by the cluster. The usage is only informational, so errors counting
+// the resources, e.g. missing permissions, are logged and nil is returned.
+func getResourceUsage(ctx context.Context, config *eksv1.EKSClusterConfig, nodeGroupStates []*eks.DescribeNodegroupOutput, awsSVCs *awsServices) *eksv1.ResourceUsage {
+	nodegroups := make([]*ekstypes.Nodegroup, 0, len(nodeGroupStates))
+	for _, ng := range nodeGroupStates {
+		nodegroups = append(nodegroups, ng.Nodegroup)
+	}
+	usage, err := awsservices.GetResourceUsage(ctx, &awsservices.GetResourceUsageOpts{
+		EC2Service: awsSVCs.ec2,
+		Config:     config,
+		Nodegroups: nodegroups,
+	})
+	if err != nil {
+		logrus.Warnf("Error counting the resources of cluster [%s (id: %s)]: %v", config.Spec.DisplayName, config.Name, err)
+		return nil
+	}
+	return usage
+}
+
+// setResourceUsageStatus records the resource usage of the cluster in the status and returns whether it changed.
+func setResourceUsageStatus(config *eksv1.EKSClusterConfig, usage *eksv1.ResourceUsage) bool {
+	if usage == nil || (config.Status.ResourceUsage != nil && *config.Status.ResourceUsage == *usage) {
+		return false
+	}
+	config.Status.ResourceUsage = usage
+	return true
+}
+
+// setUpstreamClusterStatus records the details of the upstream cluster that automation needs, like the OIDC issuer to
+// create IAM roles for service accounts, in the status so that it doesn't have to call AWS itself. It returns whether
+// the status changed.
+func setUpstreamClusterStatus(config *eksv1.EKSClusterConfig, cluster *ekstypes.Cluster) bool {
+	if cluster == nil {
+		return false
+	}
+
+	status := config.Status
+	status.ClusterARN = aws.ToString(cluster.Arn)
+	status.Endpoint = aws.ToString(cluster.Endpoint)
+	status.PlatformVersion = aws.ToString(cluster.PlatformVersion)
+	status.OIDCIssuer = ""
+	if cluster.Identity != nil && cluster.Identity.Oidc != nil {
+		status.OIDCIssuer = aws.ToString(cluster.Identity.Oidc.Issuer)
+	}
+	status.ClusterSecurityGroup = ""
+	if cluster.ResourcesVpcConfig != nil {
+		status.ClusterSecurityGroup = aws.ToString(cluster.ResourcesVpcConfig.ClusterSecurityGroupId)
+	}
+
+	changed := status.ClusterARN != config.Status.ClusterARN ||
+		status.Endpoint != config.Status.Endpoint ||
+		status.PlatformVersion != config.Status.PlatformVersion ||
+		status.OIDCIssuer != config.Status.OIDCIssuer ||
+		status.ClusterSecurityGroup != config.Status.ClusterSecurityGroup
+	config.Status = status
+	return changed
+}
+
+// getClusterSecurityGroupTags returns the tags of the security group EKS created for the cluster, the tags of the
+// cluster along with the security group specific ones, which take precedence.
+func getClusterSecurityGroupTags(spec eksv1.EKSClusterConfigSpec) map[string]string {
+	if len(spec.Tags) == 0 && len(spec.ClusterSecurityGroupTags) == 0 {
+		return nil
+	}
+	tags := make(map[string]string, len(spec.Tags)+len(spec.ClusterSecurityGroupTags))
+	maps.Copy(tags, spec.Tags)
+	maps.Copy(tags, spec.ClusterSecurityGroupTags)
+	return tags
 }
 
 // createCASecret creates a secret containing ca and endpoint. These can be used to create a kubeconfig via
@@ -1096,15 +2253,56 @@ func (h *Handler) createCASecret(config *eksv1.EKSClusterConfig, clusterState *e
 
 // enqueueUpdate enqueues the config if it is already in the updating phase. Otherwise, the
 // phase is updated to "updating". This is important because the object needs to reenter the
-// onChange handler to start waiting on the update.
+// onChange handler to start waiting on the update. The generation of the spec the update was
+// sent for is recorded along with the phase.
 func (h *Handler) enqueueUpdate(config *eksv1.EKSClusterConfig) (*eksv1.EKSClusterConfig, error) {
-	if config.Status.Phase == eksConfigUpdatingPhase {
+	if config.Status.Phase == eksv1.PhaseUpdating && config.Status.UpdateGeneration == config.Generation {
 		h.eksEnqueue(config.Namespace, config.Name)
 		return config, nil
 	}
 	config = config.DeepCopy()
-	config.Status.Phase = eksConfigUpdatingPhase
-	return h.eksCC.UpdateStatus(config)
+	config.Status.Phase = eksv1.PhaseUpdating
+	setUpdateGeneration(config)
+	return h.updateStatus(config)
+}
+
+// waitForStack records the stack that is being created on the config status and enqueues the config, so the stack is
+// checked on again without blocking the controller.
+func (h *Handler) waitForStack(config *eksv1.EKSClusterConfig, inProgress *awsservices.StackCreationInProgressError) (*eksv1.EKSClusterConfig, error) {
+	logrus.Infof("Waiting for stack [%s] of cluster [%s (id: %s)] to finish creating", inProgress.StackName, config.Spec.DisplayName, config.Name)
+	config = config.DeepCopy()
+	if setStackStatus(config, inProgress.StackName, inProgress.StackID, string(cftypes.StackStatusCreateInProgress)) {
+		// updating the status enqueues the config again
+		return h.updateStatus(config)
+	}
+	h.requeue(config, stackInterval)
+	return config, nil
+}
+
+// setStackStatus records the status of the stack with the given name on the config status and returns whether it changed.
+// The known stack ID is kept if the given one is empty.
+func setStackStatus(config *eksv1.EKSClusterConfig, name, id, status string) bool {
+	for i, stack := range config.Status.CloudFormationStacks {
+		if stack.Name != name {
+			continue
+		}
+		if id == "" {
+			id = stack.ID
+		}
+		if stack.ID == id && stack.Status == status {
+			return false
+		}
+		config.Status.CloudFormationStacks[i].ID = id
+		config.Status.CloudFormationStacks[i].Status = status
+		return true
+	}
+
+	config.Status.CloudFormationStacks = append(config.Status.CloudFormationStacks, eksv1.CloudFormationStack{
+		Name:   name,
+		ID:     id,
+		Status: status,
+	})
+	return true
 }
 
 func getVPCStackName(name string) string {
@@ -1115,14 +2313,22 @@ func getEBSCSIDriverRoleStackName(name string) string {
 	return name + "-ebs-csi-driver-role"
 }
 
+func getEBSCSIDriverPodIdentityRoleStackName(name string) string {
+	return name + "-ebs-csi-driver-pod-identity-role"
+}
+
+func getNodeInstanceRoleStackName(name string) string {
+	return name + "-node-instance-role"
+}
+
 func getServiceRoleName(name string) string {
 	return name + "-eks-service-role"
 }
 
 func getParameterValueFromOutput(key string, outputs []cftypes.Output) string {
 	for _, output := range outputs {
-		if *output.OutputKey == key {
-			return *output.OutputValue
+		if aws.ToString(output.OutputKey) == key {
+			return aws.ToString(output.OutputValue)
 		}
 	}
 
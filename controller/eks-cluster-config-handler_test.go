@@ -9,6 +9,7 @@ import (
 	cftypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
 
 	awssdkeks "github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/smithy-go"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -17,6 +18,7 @@ import (
 	"github.com/rancher/eks-operator/pkg/eks"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 
 	"github.com/rancher/eks-operator/pkg/eks/services/mock_services"
 	"github.com/rancher/eks-operator/pkg/test"
@@ -83,13 +85,14 @@ var _ = Describe("delete stack", func() {
 	var (
 		mockController            *gomock.Controller
 		mockCloudformationService *mock_services.MockCloudFormationServiceInterface
-		newStyleName              string
-		oldStyleName              string
+		config                    *eksv1.EKSClusterConfig
+		stackName                 string
 	)
 
 	BeforeEach(func() {
 		mockController = gomock.NewController(GinkgoT())
-		newStyleName = "test"
+		stackName = "test"
+		config = &eksv1.EKSClusterConfig{}
 		mockCloudformationService = mock_services.NewMockCloudFormationServiceInterface(mockController)
 	})
 
@@ -98,60 +101,143 @@ var _ = Describe("delete stack", func() {
 	})
 
 	It("should successfully delete a stack", func() {
-		mockCloudformationService.EXPECT().DescribeStacks(ctx,
-			&cloudformation.DescribeStacksInput{
-				StackName: &newStyleName,
-			},
-		).Return(
-			&cloudformation.DescribeStacksOutput{
-				Stacks: []cftypes.Stack{
-					{
-						StackName: &newStyleName,
-					},
-				},
-			}, nil)
+		mockCloudformationService.EXPECT().DeleteStack(ctx, &cloudformation.DeleteStackInput{
+			StackName: &stackName,
+		}).Return(nil, nil)
+
+		newerr := deleteStack(ctx, mockCloudformationService, config, stackName)
+		Expect(newerr).ToNot(HaveOccurred())
+	})
 
+	It("should successfully delete a stack recorded with legacy name by its ID", func() {
+		config.Status.CloudFormationStacks = []eksv1.CloudFormationStack{
+			{
+				Name: stackName,
+				ID:   "legacy-stack-id",
+			},
+		}
 		mockCloudformationService.EXPECT().DeleteStack(ctx, &cloudformation.DeleteStackInput{
-			StackName: &newStyleName,
+			StackName: aws.String("legacy-stack-id"),
 		}).Return(nil, nil)
 
-		newerr := deleteStack(ctx, mockCloudformationService, newStyleName, "")
+		newerr := deleteStack(ctx, mockCloudformationService, config, stackName)
 		Expect(newerr).ToNot(HaveOccurred())
 	})
-	It("should successfully delete a stack with old style name", func() {
-		mockCloudformationService.EXPECT().DescribeStacks(ctx,
-			&cloudformation.DescribeStacksInput{
-				StackName: &oldStyleName,
+
+	It("should not fail to delete a stack that does not exist", func() {
+		mockCloudformationService.EXPECT().DeleteStack(ctx, &cloudformation.DeleteStackInput{
+			StackName: &stackName,
+		}).Return(nil, errors.New("stack does not exist"))
+
+		newerr := deleteStack(ctx, mockCloudformationService, config, stackName)
+		Expect(newerr).ToNot(HaveOccurred())
+	})
+
+	It("should fail to delete a stack if DeleteStack returns error", func() {
+		mockCloudformationService.EXPECT().DeleteStack(ctx, &cloudformation.DeleteStackInput{
+			StackName: &stackName,
+		}).Return(nil, errors.New("error"))
+
+		newerr := deleteStack(ctx, mockCloudformationService, config, stackName)
+		Expect(newerr).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("migrate stacks", func() {
+	var (
+		mockController            *gomock.Controller
+		mockCloudformationService *mock_services.MockCloudFormationServiceInterface
+		config                    *eksv1.EKSClusterConfig
+		displayNameTag            []cftypes.Tag
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		mockCloudformationService = mock_services.NewMockCloudFormationServiceInterface(mockController)
+		config = &eksv1.EKSClusterConfig{
+			Spec: eksv1.EKSClusterConfigSpec{
+				DisplayName: "test",
 			},
-		).Return(
+		}
+		displayNameTag = []cftypes.Tag{{Key: aws.String("displayName"), Value: aws.String("test")}}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should record stacks with canonical and legacy names", func() {
+		mockCloudformationService.EXPECT().DescribeStacks(ctx, &cloudformation.DescribeStacksInput{}).Return(
 			&cloudformation.DescribeStacksOutput{
 				Stacks: []cftypes.Stack{
 					{
-						StackName: &oldStyleName,
+						StackName:   aws.String("test-eks-vpc"),
+						StackId:     aws.String("vpc-id"),
+						StackStatus: cftypes.StackStatusCreateComplete,
+						Tags:        displayNameTag,
+					},
+					{
+						StackName:   aws.String("legacy-service-role"),
+						StackId:     aws.String("service-role-id"),
+						StackStatus: cftypes.StackStatusCreateComplete,
+						Tags:        displayNameTag,
+						Outputs:     []cftypes.Output{{OutputKey: aws.String("RoleArn")}},
+					},
+					{
+						StackName:   aws.String("test-ebs-csi-driver-pod-identity-role"),
+						StackId:     aws.String("pod-identity-role-id"),
+						StackStatus: cftypes.StackStatusCreateComplete,
+						Tags:        displayNameTag,
+					},
+					{
+						StackName:   aws.String("other-eks-vpc"),
+						StackId:     aws.String("other-id"),
+						StackStatus: cftypes.StackStatusCreateComplete,
+						Tags:        []cftypes.Tag{{Key: aws.String("displayName"), Value: aws.String("other")}},
 					},
 				},
 			}, nil)
 
-		mockCloudformationService.EXPECT().DeleteStack(ctx, &cloudformation.DeleteStackInput{
-			StackName: &oldStyleName,
-		}).Return(nil, nil)
+		Expect(migrateStacks(ctx, mockCloudformationService, config)).To(Succeed())
+		Expect(config.Status.CloudFormationStacksMigrated).To(BeTrue())
+		Expect(config.Status.CloudFormationStacks).To(ConsistOf(
+			eksv1.CloudFormationStack{Name: "test-eks-vpc", ID: "vpc-id", Status: string(cftypes.StackStatusCreateComplete)},
+			eksv1.CloudFormationStack{Name: "test-eks-service-role", ID: "service-role-id", Status: string(cftypes.StackStatusCreateComplete)},
+			eksv1.CloudFormationStack{Name: "test-ebs-csi-driver-pod-identity-role", ID: "pod-identity-role-id", Status: string(cftypes.StackStatusCreateComplete)},
+		))
+	})
 
-		newerr := deleteStack(ctx, mockCloudformationService, "", oldStyleName)
-		Expect(newerr).ToNot(HaveOccurred())
+	It("should fail to migrate stacks if DescribeStacks returns error", func() {
+		mockCloudformationService.EXPECT().DescribeStacks(ctx, gomock.Any()).Return(nil, errors.New("error"))
+
+		Expect(migrateStacks(ctx, mockCloudformationService, config)).ToNot(Succeed())
+		Expect(config.Status.CloudFormationStacksMigrated).To(BeFalse())
 	})
 
-	It("should fail to delete a stack if DescribeStacks returns no stacks", func() {
-		mockCloudformationService.EXPECT().DescribeStacks(ctx,
-			&cloudformation.DescribeStacksInput{
-				StackName: &newStyleName,
-			},
-		).Return(&cloudformation.DescribeStacksOutput{}, nil)
+	It("should describe stacks with canonical names if the credentials can't list stacks", func() {
+		mockCloudformationService.EXPECT().DescribeStacks(ctx, &cloudformation.DescribeStacksInput{}).Return(nil, &smithy.GenericAPIError{Code: "AccessDenied"})
+		mockCloudformationService.EXPECT().DescribeStacks(ctx, &cloudformation.DescribeStacksInput{StackName: aws.String("test-eks-vpc")}).Return(
+			&cloudformation.DescribeStacksOutput{Stacks: []cftypes.Stack{{
+				StackName:   aws.String("test-eks-vpc"),
+				StackId:     aws.String("vpc-id"),
+				StackStatus: cftypes.StackStatusCreateComplete,
+			}}}, nil)
+		mockCloudformationService.EXPECT().DescribeStacks(ctx, gomock.Any()).Return(
+			nil, &smithy.GenericAPIError{Code: "ValidationError", Message: "Stack with id test does not exist"}).Times(4)
 
-		mockCloudformationService.EXPECT().DeleteStack(ctx, &cloudformation.DeleteStackInput{
-			StackName: &newStyleName,
-		}).Return(nil, errors.New("error"))
-		newerr := deleteStack(ctx, mockCloudformationService, newStyleName, "")
-		Expect(newerr).To(HaveOccurred())
+		Expect(migrateStacks(ctx, mockCloudformationService, config)).To(Succeed())
+		Expect(config.Status.CloudFormationStacksMigrated).To(BeTrue())
+		Expect(config.Status.CloudFormationStacks).To(ConsistOf(
+			eksv1.CloudFormationStack{Name: "test-eks-vpc", ID: "vpc-id", Status: string(cftypes.StackStatusCreateComplete)},
+		))
+	})
+
+	It("should leave stacks unmigrated if the credentials can't describe them", func() {
+		mockCloudformationService.EXPECT().DescribeStacks(ctx, gomock.Any()).Return(nil, &smithy.GenericAPIError{Code: "AccessDenied"}).Times(2)
+
+		Expect(migrateStacks(ctx, mockCloudformationService, config)).To(Succeed())
+		Expect(config.Status.CloudFormationStacksMigrated).To(BeFalse())
+		Expect(config.Status.CloudFormationStacks).To(BeEmpty())
 	})
 })
 
@@ -166,6 +252,7 @@ var _ = Describe("updateCluster", func() {
 			eksCC:        eksFactory.Eks().V1().EKSClusterConfig(),
 			secrets:      coreFactory.Core().V1().Secret(),
 			secretsCache: coreFactory.Core().V1().Secret().Cache(),
+			dynamic:      dynamic.NewForConfigOrDie(cfg),
 		}
 
 		eksConfig = &eksv1.EKSClusterConfig{
@@ -191,7 +278,7 @@ var _ = Describe("updateCluster", func() {
 				},
 			},
 			Status: eksv1.EKSClusterConfigStatus{
-				Phase: "active",
+				Phase: eksv1.PhaseActive,
 			},
 		}
 
@@ -203,14 +290,14 @@ var _ = Describe("updateCluster", func() {
 	})
 
 	It("should not allow duplicate node group names", func() {
-		eksConfig.Status.Phase = "active"
+		eksConfig.Status.Phase = eksv1.PhaseActive
 		eksConfig.Spec.NodeGroups = append(eksConfig.Spec.NodeGroups, eksConfig.Spec.NodeGroups...)
 		_, err := handler.OnEksConfigChanged("", eksConfig)
 		Expect(err).To(MatchError("node group name [ng1] is not unique within the cluster [test (id: test)] to avoid duplication"))
 	})
 
 	It("should not allow node group versions outside version skew", func() {
-		eksConfig.Status.Phase = "active"
+		eksConfig.Status.Phase = eksv1.PhaseActive
 		eksConfig.Spec.KubernetesVersion = aws.String("1.25")
 		eksConfig.Spec.NodeGroups = append(eksConfig.Spec.NodeGroups, eksv1.NodeGroup{
 			NodegroupName: aws.String("ng2"),
@@ -5,6 +5,10 @@ import (
 	"strings"
 
 	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/aws/smithy-go"
+
+	awsservices "github.com/rancher/eks-operator/pkg/eks"
+	"github.com/rancher/eks-operator/pkg/eks/services"
 )
 
 func isResourceInUse(err error) bool {
@@ -34,3 +38,37 @@ func notFound(err error) bool {
 	}
 	return false
 }
+
+// isAccessDenied returns whether the request failed because the credentials aren't allowed to make it.
+func isAccessDenied(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.ErrorCode() {
+	case "AccessDeniedException", "AccessDenied", "UnauthorizedOperation":
+		return true
+	}
+	return false
+}
+
+// stackCreationInProgress returns the StackCreationInProgressError wrapped in err, or nil if there is none.
+func stackCreationInProgress(err error) *awsservices.StackCreationInProgressError {
+	var inProgress *awsservices.StackCreationInProgressError
+	if errors.As(err, &inProgress) {
+		return inProgress
+	}
+	return nil
+}
+
+// isDryRun returns whether the request was skipped because the operator runs in dry run mode.
+func isDryRun(err error) bool {
+	var dryRunErr *services.DryRunError
+	return errors.As(err, &dryRunErr)
+}
+
+// logGroupNotFound returns whether the request failed because the log group doesn't exist.
+func logGroupNotFound(err error) bool {
+	var apiErr *services.APIError
+	return errors.As(err, &apiErr) && apiErr.Code == services.CloudWatchLogsResourceNotFound
+}
@@ -0,0 +1,63 @@
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+)
+
+const (
+	// Event reasons recorded on EKSClusterConfig objects
+	eventReasonCreating                    = "Creating"
+	eventReasonCreated                     = "Created"
+	eventReasonImported                    = "Imported"
+	eventReasonAdopted                     = "Adopted"
+	eventReasonUpdating                    = "Updating"
+	eventReasonUpdated                     = "Updated"
+	eventReasonDeleting                    = "Deleting"
+	eventReasonDeletionDryRun              = "DeletionDryRun"
+	eventReasonNodegroupCreating           = "NodegroupCreating"
+	eventReasonNodegroupDeleting           = "NodegroupDeleting"
+	eventReasonNodegroupDraining           = "NodegroupDraining"
+	eventReasonNodegroupDeletionBlocked    = "NodegroupDeletionBlocked"
+	eventReasonNodegroupDegraded           = "NodegroupDegraded"
+	eventReasonNodegroupRecreating         = "NodegroupRecreating"
+	eventReasonNodegroupRecreationRequired = "NodegroupRecreationRequired"
+	eventReasonUpgradeBlocked              = "UpgradeBlocked"
+	eventReasonDownstreamUnhealthy         = "DownstreamUnhealthy"
+	eventReasonScalingWindowStarted        = "ScalingWindowStarted"
+	eventReasonUpdatesDeferred             = "UpdatesDeferred"
+	eventReasonInsightWarnings             = "InsightWarnings"
+	eventReasonDrifted                     = "Drifted"
+	eventReasonFailed                      = "Failed"
+	eventReasonCloudFormationUnavailable   = "CloudFormationUnavailable"
+)
+
+// recordPhaseTransition records an event on the config if its phase changed from the given previous phase
+// to one of the lifecycle phases users are interested in.
+func (h *Handler) recordPhaseTransition(config *eksv1.EKSClusterConfig, previousPhase eksv1.Phase) {
+	if h.recorder == nil || config == nil || config.Status.Phase == previousPhase {
+		return
+	}
+
+	switch {
+	case config.Status.Phase == eksv1.PhaseCreating:
+		h.recorder.Eventf(config, corev1.EventTypeNormal, eventReasonCreating, "Creating cluster [%s]", config.Spec.DisplayName)
+	case config.Status.Phase == eksv1.PhaseActive && previousPhase == eksv1.PhaseCreating:
+		h.recorder.Eventf(config, corev1.EventTypeNormal, eventReasonCreated, "Cluster [%s] created successfully", config.Spec.DisplayName)
+	case config.Status.Phase == eksv1.PhaseActive && previousPhase == eksv1.PhaseImporting:
+		h.recorder.Eventf(config, corev1.EventTypeNormal, eventReasonImported, "Cluster [%s] imported successfully", config.Spec.DisplayName)
+	case config.Status.Phase == eksv1.PhaseUpdating:
+		h.recorder.Eventf(config, corev1.EventTypeNormal, eventReasonUpdating, "Updating cluster [%s]", config.Spec.DisplayName)
+	case config.Status.Phase == eksv1.PhaseActive && previousPhase == eksv1.PhaseUpdating:
+		h.recorder.Eventf(config, corev1.EventTypeNormal, eventReasonUpdated, "Cluster [%s] finished updating", config.Spec.DisplayName)
+	}
+}
+
+// recordEvent records an event on the config if an event recorder is configured.
+func (h *Handler) recordEvent(config *eksv1.EKSClusterConfig, eventType, reason, messageFmt string, args ...interface{}) {
+	if h.recorder == nil || config == nil {
+		return
+	}
+	h.recorder.Eventf(config, eventType, reason, messageFmt, args...)
+}
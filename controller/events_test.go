@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"testing"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestRecordPhaseTransition(t *testing.T) {
+	tests := []struct {
+		name          string
+		previousPhase eksv1.Phase
+		phase         eksv1.Phase
+		expectedEvent string
+	}{
+		{
+			name:          "creating",
+			previousPhase: eksv1.PhaseNotCreated,
+			phase:         eksv1.PhaseCreating,
+			expectedEvent: "Normal Creating Creating cluster [test]",
+		},
+		{
+			name:          "created",
+			previousPhase: eksv1.PhaseCreating,
+			phase:         eksv1.PhaseActive,
+			expectedEvent: "Normal Created Cluster [test] created successfully",
+		},
+		{
+			name:          "imported",
+			previousPhase: eksv1.PhaseImporting,
+			phase:         eksv1.PhaseActive,
+			expectedEvent: "Normal Imported Cluster [test] imported successfully",
+		},
+		{
+			name:          "updating",
+			previousPhase: eksv1.PhaseActive,
+			phase:         eksv1.PhaseUpdating,
+			expectedEvent: "Normal Updating Updating cluster [test]",
+		},
+		{
+			name:          "updated",
+			previousPhase: eksv1.PhaseUpdating,
+			phase:         eksv1.PhaseActive,
+			expectedEvent: "Normal Updated Cluster [test] finished updating",
+		},
+		{
+			name:          "unchanged phase",
+			previousPhase: eksv1.PhaseActive,
+			phase:         eksv1.PhaseActive,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(1)
+			h := &Handler{recorder: recorder}
+			config := &eksv1.EKSClusterConfig{
+				Spec:   eksv1.EKSClusterConfigSpec{DisplayName: "test"},
+				Status: eksv1.EKSClusterConfigStatus{Phase: tt.phase},
+			}
+
+			h.recordPhaseTransition(config, tt.previousPhase)
+
+			select {
+			case event := <-recorder.Events:
+				assert.Equal(t, tt.expectedEvent, event)
+			default:
+				assert.Empty(t, tt.expectedEvent)
+			}
+		})
+	}
+}
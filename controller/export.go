@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+)
+
+const (
+	// exportAnnotation requests an export of the cluster whenever its value changes, e.g. to the current time
+	exportAnnotation          = "eks.cattle.io/export"
+	exportConfigMapNameFormat = "%s-export"
+	exportConfigMapKey        = "eksclusterconfig.yaml"
+
+	lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+)
+
+// exportRequested returns whether the export annotation was set or changed since the last export.
+func exportRequested(config *eksv1.EKSClusterConfig) bool {
+	request := config.Annotations[exportAnnotation]
+	return request != "" && request != config.Status.LastExportRequest
+}
+
+// exportConfig stores a manifest of the effective state of the cluster in the export ConfigMap of the config and
+// records the export request on the status. The ConfigMap isn't owned by the config, so that it outlives the config it
+// is meant to recover.
+func (h *Handler) exportConfig(config *eksv1.EKSClusterConfig, upstreamSpec *eksv1.EKSClusterConfigSpec) (*eksv1.EKSClusterConfig, error) {
+	manifest, err := buildExportManifest(config, upstreamSpec)
+	if err != nil {
+		return config, fmt.Errorf("error building export manifest for cluster [%s (id: %s)]: %w", config.Spec.DisplayName, config.Name, err)
+	}
+
+	name := fmt.Sprintf(exportConfigMapNameFormat, config.Name)
+	configMap, err := h.configMaps.Get(config.Namespace, name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		_, err = h.configMaps.Create(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: config.Namespace,
+			},
+			Data: map[string]string{exportConfigMapKey: string(manifest)},
+		})
+	case err == nil:
+		configMap = configMap.DeepCopy()
+		configMap.OwnerReferences = removeOwnerReference(configMap.OwnerReferences, config.UID)
+		configMap.Data = map[string]string{exportConfigMapKey: string(manifest)}
+		_, err = h.configMaps.Update(configMap)
+	}
+	if err != nil {
+		return config, fmt.Errorf("error storing export of cluster [%s (id: %s)] in configmap [%s]: %w", config.Spec.DisplayName, config.Name, name, err)
+	}
+
+	logrus.Infof("Exported cluster [%s (id: %s)] to configmap [%s]", config.Spec.DisplayName, config.Name, name)
+	config = config.DeepCopy()
+	config.Status.LastExportRequest = config.Annotations[exportAnnotation]
+	return h.updateStatus(config)
+}
+
+// buildExportManifest renders the effective state of the cluster as an EKSClusterConfig manifest that can be applied
+// to recover the config. Fields that are unset in the spec are filled in from the upstream cluster and the status is
+// left out. Imported is kept as it was, so that the recovered config manages the cluster like the original one did.
+// Credentials stay referenced through the amazonCredentialSecret.
+func buildExportManifest(config *eksv1.EKSClusterConfig, upstreamSpec *eksv1.EKSClusterConfigSpec) ([]byte, error) {
+	spec := *config.Spec.DeepCopy()
+	if spec.KubernetesVersion == nil {
+		spec.KubernetesVersion = upstreamSpec.KubernetesVersion
+	}
+	if spec.Tags == nil {
+		spec.Tags = upstreamSpec.Tags
+	}
+	if spec.PublicAccess == nil {
+		spec.PublicAccess = upstreamSpec.PublicAccess
+	}
+	if spec.PrivateAccess == nil {
+		spec.PrivateAccess = upstreamSpec.PrivateAccess
+	}
+	if spec.PublicAccessSources == nil {
+		spec.PublicAccessSources = upstreamSpec.PublicAccessSources
+	}
+	if spec.LoggingTypes == nil {
+		spec.LoggingTypes = upstreamSpec.LoggingTypes
+	}
+	if spec.EBSCSIDriver == nil {
+		spec.EBSCSIDriver = upstreamSpec.EBSCSIDriver
+	}
+	if spec.SecretsEncryption == nil {
+		spec.SecretsEncryption = upstreamSpec.SecretsEncryption
+	}
+	if spec.KmsKey == nil {
+		spec.KmsKey = upstreamSpec.KmsKey
+	}
+	if spec.ServiceRole == nil {
+		spec.ServiceRole = upstreamSpec.ServiceRole
+	}
+	if len(spec.Subnets) == 0 {
+		spec.Subnets = upstreamSpec.Subnets
+	}
+	if len(spec.SecurityGroups) == 0 {
+		spec.SecurityGroups = upstreamSpec.SecurityGroups
+	}
+	if spec.NodeGroups == nil {
+		spec.NodeGroups = upstreamSpec.NodeGroups
+	}
+
+	annotations := make(map[string]string, len(config.Annotations))
+	for key, value := range config.Annotations {
+		if key == exportAnnotation || key == lastAppliedConfigAnnotation {
+			continue
+		}
+		annotations[key] = value
+	}
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+
+	exported := &eksv1.EKSClusterConfig{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: eksv1.SchemeGroupVersion.String(),
+			Kind:       eksClusterConfigKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        config.Name,
+			Namespace:   config.Namespace,
+			Labels:      config.Labels,
+			Annotations: annotations,
+		},
+		Spec: spec,
+	}
+
+	// the status is not omitted when empty, so it is removed from the rendered object
+	object := map[string]interface{}{}
+	data, err := yaml.Marshal(exported)
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	delete(object, "status")
+	metadata, _ := object["metadata"].(map[string]interface{})
+	delete(metadata, "creationTimestamp")
+
+	return yaml.Marshal(object)
+}
+
+// removeOwnerReference returns the owner references without the ones to the owner with the given UID, e.g. the ones
+// set on export ConfigMaps by earlier versions.
+func removeOwnerReference(references []metav1.OwnerReference, uid types.UID) []metav1.OwnerReference {
+	var result []metav1.OwnerReference
+	for _, reference := range references {
+		if reference.UID != uid {
+			result = append(result, reference)
+		}
+	}
+	return result
+}
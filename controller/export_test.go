@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+)
+
+func TestExportRequested(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{}
+	assert.False(t, exportRequested(config))
+
+	config.Annotations = map[string]string{exportAnnotation: "1"}
+	assert.True(t, exportRequested(config))
+
+	config.Status.LastExportRequest = "1"
+	assert.False(t, exportRequested(config))
+}
+
+func TestBuildExportManifest(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "c-test",
+			Namespace: "cattle-global-data",
+			Annotations: map[string]string{
+				exportAnnotation: "1",
+				"other":          "value",
+			},
+			UID:             "uid",
+			ResourceVersion: "10",
+		},
+		Spec: eksv1.EKSClusterConfigSpec{
+			AmazonCredentialSecret: "cattle-global-data:cc-test",
+			DisplayName:            "test",
+			Region:                 "us-west-2",
+			LoggingTypes:           []string{},
+		},
+		Status: eksv1.EKSClusterConfigStatus{Phase: eksv1.PhaseActive},
+	}
+	upstreamSpec := &eksv1.EKSClusterConfigSpec{
+		KubernetesVersion: aws.String("1.30"),
+		LoggingTypes:      []string{"api"},
+		Subnets:           []string{"subnet-1"},
+		NodeGroups:        []eksv1.NodeGroup{{NodegroupName: aws.String("ng1")}},
+	}
+
+	manifest, err := buildExportManifest(config, upstreamSpec)
+	require.NoError(t, err)
+
+	object := map[string]interface{}{}
+	require.NoError(t, yaml.Unmarshal(manifest, &object))
+	assert.NotContains(t, object, "status")
+	assert.Equal(t, map[string]interface{}{
+		"name":        "c-test",
+		"namespace":   "cattle-global-data",
+		"annotations": map[string]interface{}{"other": "value"},
+	}, object["metadata"])
+
+	exported := &eksv1.EKSClusterConfig{}
+	require.NoError(t, yaml.Unmarshal(manifest, exported))
+	assert.Equal(t, eksClusterConfigKind, exported.Kind)
+	assert.False(t, exported.Spec.Imported)
+	assert.Equal(t, "cattle-global-data:cc-test", exported.Spec.AmazonCredentialSecret)
+	assert.Equal(t, aws.String("1.30"), exported.Spec.KubernetesVersion)
+	assert.Equal(t, []string{}, exported.Spec.LoggingTypes)
+	assert.Equal(t, []string{"subnet-1"}, exported.Spec.Subnets)
+	assert.Len(t, exported.Spec.NodeGroups, 1)
+
+	config.Spec.Imported = true
+	manifest, err = buildExportManifest(config, upstreamSpec)
+	require.NoError(t, err)
+	exported = &eksv1.EKSClusterConfig{}
+	require.NoError(t, yaml.Unmarshal(manifest, exported))
+	assert.True(t, exported.Spec.Imported)
+}
+
+func TestRemoveOwnerReference(t *testing.T) {
+	references := []metav1.OwnerReference{{UID: "uid"}, {UID: "other"}}
+	assert.Equal(t, []metav1.OwnerReference{{UID: "other"}}, removeOwnerReference(references, "uid"))
+	assert.Nil(t, removeOwnerReference(references[:1], "uid"))
+}
@@ -49,8 +49,24 @@ func NodeGroupIssueIsUpdatable(code string) bool {
 }
 
 // BuildUpstreamClusterState builds the upstream cluster state from the given eks cluster and node group states.
+// Userdata from rancher-managed launch templates is included, since the upstream spec is synced back into the spec and
+// the next launch template version would be created without it otherwise.
 func BuildUpstreamClusterState(ctx context.Context, name, managedTemplateID string, clusterState *eks.DescribeClusterOutput, nodeGroupStates []*eks.DescribeNodegroupOutput, ec2Service services.EC2ServiceInterface, eksService services.EKSServiceInterface, includeManagedLaunchTemplate bool) (*eksv1.EKSClusterConfigSpec, string, error) {
+	upstreamSpec, clusterARN, _, err := buildUpstreamClusterState(ctx, name, managedTemplateID, clusterState, nodeGroupStates, ec2Service, eksService, includeManagedLaunchTemplate, true)
+	if upstreamSpec != nil {
+		for i := range upstreamSpec.NodeGroups {
+			upstreamSpec.NodeGroups[i].Tags = withoutClusterAutoscalerTags(name, upstreamSpec.NodeGroups[i].Tags)
+		}
+	}
+	return upstreamSpec, clusterARN, err
+}
+
+// buildUpstreamClusterState builds the upstream cluster state and additionally returns the hashes of the userdata
+// in the rancher-managed launch template versions, keyed by node group name. The userdata itself is only included if
+// requested, the operator compares it by the hashes so that it is never stored on the status.
+func buildUpstreamClusterState(ctx context.Context, name, managedTemplateID string, clusterState *eks.DescribeClusterOutput, nodeGroupStates []*eks.DescribeNodegroupOutput, ec2Service services.EC2ServiceInterface, eksService services.EKSServiceInterface, includeManagedLaunchTemplate, includeUserData bool) (*eksv1.EKSClusterConfigSpec, string, map[string]string, error) {
 	upstreamSpec := &eksv1.EKSClusterConfigSpec{}
+	userDataHashes := make(map[string]string)
 
 	upstreamSpec.Imported = true
 	upstreamSpec.DisplayName = name
@@ -58,7 +74,7 @@ func BuildUpstreamClusterState(ctx context.Context, name, managedTemplateID stri
 	// set kubernetes version
 	upstreamVersion := aws.ToString(clusterState.Cluster.Version)
 	if upstreamVersion == "" {
-		return nil, "", fmt.Errorf("cannot detect cluster [%s] upstream kubernetes version", name)
+		return nil, "", nil, fmt.Errorf("cannot detect cluster [%s] upstream kubernetes version", name)
 	}
 	upstreamSpec.KubernetesVersion = aws.String(upstreamVersion)
 
@@ -103,7 +119,7 @@ func BuildUpstreamClusterState(ctx context.Context, name, managedTemplateID stri
 	upstreamSpec.EBSCSIDriver = aws.Bool(false)
 	currentARN, err := awsservices.CheckEBSAddon(ctx, name, eksService)
 	if err != nil {
-		return nil, "", fmt.Errorf("error checking if ebs csi driver addon is installed: %w", err)
+		return nil, "", nil, fmt.Errorf("error checking if ebs csi driver addon is installed: %w", err)
 	}
 	if strings.Contains(currentARN, "aws-ebs-csi-driver") {
 		upstreamSpec.EBSCSIDriver = aws.Bool(true)
@@ -137,6 +153,12 @@ func BuildUpstreamClusterState(ctx context.Context, name, managedTemplateID stri
 			ngToAdd.SpotInstanceTypes = ng.Nodegroup.InstanceTypes
 		}
 
+		if ng.Nodegroup.NodeRepairConfig != nil {
+			ngToAdd.NodeRepairConfig = &eksv1.NodeRepairConfig{
+				Enabled: ng.Nodegroup.NodeRepairConfig.Enabled,
+			}
+		}
+
 		if ng.Nodegroup.LaunchTemplate != nil {
 			var version *int64
 			versionNumber, err := strconv.ParseInt(aws.ToString(ng.Nodegroup.LaunchTemplate.Version), 10, 64)
@@ -166,29 +188,87 @@ func BuildUpstreamClusterState(ctx context.Context, name, managedTemplateID stri
 							continue
 						}
 
-						return nil, "", fmt.Errorf("rancher-managed launch template for node group [%s] in cluster [%s] not found, must create new node group and destroy existing",
+						return nil, "", nil, fmt.Errorf("rancher-managed launch template for node group [%s] in cluster [%s] not found, must create new node group and destroy existing",
 							aws.ToString(ngToAdd.NodegroupName),
 							upstreamSpec.DisplayName,
 						)
 					}
-					return nil, "", fmt.Errorf("error getting launch template info for node group [%s] in cluster [%s]", aws.ToString(ngToAdd.NodegroupName), upstreamSpec.DisplayName)
+					return nil, "", nil, fmt.Errorf("error getting launch template info for node group [%s] in cluster [%s]", aws.ToString(ngToAdd.NodegroupName), upstreamSpec.DisplayName)
 				}
 				launchTemplateData := launchTemplateRequestOutput.LaunchTemplateVersions[0].LaunchTemplateData
 
 				if len(launchTemplateData.BlockDeviceMappings) == 0 {
-					return nil, "", fmt.Errorf("launch template for node group [%s] in cluster [%s] is malformed", aws.ToString(ngToAdd.NodegroupName), upstreamSpec.DisplayName)
+					return nil, "", nil, fmt.Errorf("launch template for node group [%s] in cluster [%s] is malformed", aws.ToString(ngToAdd.NodegroupName), upstreamSpec.DisplayName)
 				}
-				ngToAdd.DiskSize = launchTemplateData.BlockDeviceMappings[0].Ebs.VolumeSize
+				ebs := launchTemplateData.BlockDeviceMappings[0].Ebs
+				ngToAdd.DiskSize = ebs.VolumeSize
+				if ebs.VolumeType != "" {
+					ngToAdd.VolumeType = aws.String(string(ebs.VolumeType))
+				}
+				ngToAdd.Iops = ebs.Iops
+				ngToAdd.Throughput = ebs.Throughput
+				ngToAdd.Encrypted = ebs.Encrypted
+				ngToAdd.KmsKeyID = ebs.KmsKeyId
 				ngToAdd.Ec2SshKey = launchTemplateData.KeyName
 				ngToAdd.ImageID = launchTemplateData.ImageId
 				ngToAdd.InstanceType = string(launchTemplateData.InstanceType)
 				ngToAdd.ResourceTags = utils.GetInstanceTags(launchTemplateData.TagSpecifications)
+				if metadataOptions := launchTemplateData.MetadataOptions; metadataOptions != nil {
+					ngToAdd.MetadataOptions = &eksv1.MetadataOptions{
+						HTTPPutResponseHopLimit: metadataOptions.HttpPutResponseHopLimit,
+					}
+					if metadataOptions.HttpTokens != "" {
+						ngToAdd.MetadataOptions.HTTPTokens = aws.String(string(metadataOptions.HttpTokens))
+					}
+					if metadataOptions.InstanceMetadataTags != "" {
+						ngToAdd.MetadataOptions.InstanceMetadataTags = aws.String(string(metadataOptions.InstanceMetadataTags))
+					}
+				}
+				for _, mapping := range launchTemplateData.BlockDeviceMappings[1:] {
+					if mapping.Ebs == nil {
+						continue
+					}
+					blockDeviceMapping := eksv1.BlockDeviceMapping{
+						DeviceName:          mapping.DeviceName,
+						VolumeSize:          mapping.Ebs.VolumeSize,
+						Iops:                mapping.Ebs.Iops,
+						Throughput:          mapping.Ebs.Throughput,
+						Encrypted:           mapping.Ebs.Encrypted,
+						KmsKeyID:            mapping.Ebs.KmsKeyId,
+						DeleteOnTermination: mapping.Ebs.DeleteOnTermination,
+					}
+					if mapping.Ebs.VolumeType != "" {
+						blockDeviceMapping.VolumeType = aws.String(string(mapping.Ebs.VolumeType))
+					}
+					ngToAdd.BlockDeviceMappings = append(ngToAdd.BlockDeviceMappings, blockDeviceMapping)
+				}
+				for _, networkInterface := range launchTemplateData.NetworkInterfaces {
+					ngToAdd.NetworkInterfaces = append(ngToAdd.NetworkInterfaces, eksv1.NetworkInterface{
+						NetworkCardIndex:         networkInterface.NetworkCardIndex,
+						DeviceIndex:              networkInterface.DeviceIndex,
+						AssociatePublicIPAddress: networkInterface.AssociatePublicIpAddress,
+						SecurityGroups:           networkInterface.Groups,
+						InterfaceType:            networkInterface.InterfaceType,
+					})
+				}
+				if placement := launchTemplateData.Placement; placement != nil {
+					ngToAdd.Placement = &eksv1.Placement{
+						GroupName:       placement.GroupName,
+						PartitionNumber: placement.PartitionNumber,
+					}
+					if placement.Tenancy != "" {
+						ngToAdd.Placement.Tenancy = aws.String(string(placement.Tenancy))
+					}
+				}
 
 				userData := aws.ToString(launchTemplateData.UserData)
 				if userData != "" {
 					decodedUserdata, err := base64.StdEncoding.DecodeString(userData)
 					if err == nil {
-						ngToAdd.UserData = aws.String(string(decodedUserdata))
+						userDataHashes[aws.ToString(ngToAdd.NodegroupName)] = utils.HashUserData(aws.String(string(decodedUserdata)))
+						if includeUserData {
+							ngToAdd.UserData = aws.String(string(decodedUserdata))
+						}
 					} else {
 						logrus.Warnf("Could not decode userdata for nodegroup [%s] in cluster[%s]", aws.ToString(ngToAdd.NodegroupName), name)
 					}
@@ -228,9 +308,96 @@ func BuildUpstreamClusterState(ctx context.Context, name, managedTemplateID stri
 		upstreamSpec.KmsKey = clusterState.Cluster.EncryptionConfig[0].Provider.KeyArn
 	}
 
+	if outpostConfig := clusterState.Cluster.OutpostConfig; outpostConfig != nil {
+		upstreamSpec.OutpostConfig = &eksv1.OutpostConfig{
+			OutpostARNs:              outpostConfig.OutpostArns,
+			ControlPlaneInstanceType: aws.ToString(outpostConfig.ControlPlaneInstanceType),
+		}
+		if placement := outpostConfig.ControlPlanePlacement; placement != nil {
+			upstreamSpec.OutpostConfig.ControlPlanePlacementGroupName = aws.ToString(placement.GroupName)
+		}
+	}
+
 	upstreamSpec.ServiceRole = clusterState.Cluster.RoleArn
 	if upstreamSpec.ServiceRole == nil {
 		upstreamSpec.ServiceRole = aws.String("")
 	}
-	return upstreamSpec, aws.ToString(clusterState.Cluster.Arn), nil
+	return upstreamSpec, aws.ToString(clusterState.Cluster.Arn), userDataHashes, nil
+}
+
+// getUpstreamSpec returns the configuration of the cluster in EKS, including the add-ons and Fargate profiles that the
+// spec doesn't cover.
+func getUpstreamSpec(ctx context.Context, config *eksv1.EKSClusterConfig, clusterState *eks.DescribeClusterOutput, awsSVCs *awsServices) (*eksv1.UpstreamSpec, error) {
+	clusterName := config.Spec.DisplayName
+	ngs, err := awsSVCs.eks.ListNodegroups(ctx, &eks.ListNodegroupsInput{
+		ClusterName: aws.String(clusterName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing node groups: %w", err)
+	}
+	nodeGroupStates := make([]*eks.DescribeNodegroupOutput, 0, len(ngs.Nodegroups))
+	for _, ngName := range ngs.Nodegroups {
+		ng, err := awsSVCs.eks.DescribeNodegroup(ctx, &eks.DescribeNodegroupInput{
+			ClusterName:   aws.String(clusterName),
+			NodegroupName: aws.String(ngName),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error describing node group [%s]: %w", ngName, err)
+		}
+		if nodeGroupSelected(config, ngName, ng.Nodegroup.Labels) {
+			nodeGroupStates = append(nodeGroupStates, ng)
+		}
+	}
+
+	spec, _, _, err := buildUpstreamClusterState(ctx, clusterName, config.Status.ManagedLaunchTemplateID, clusterState, nodeGroupStates,
+		awsSVCs.ec2, awsSVCs.eks, true, false)
+	if err != nil {
+		return nil, err
+	}
+	upstreamSpec := &eksv1.UpstreamSpec{EKSClusterConfigSpec: *spec}
+	// the upstream spec describes EKS, not how the config reaches it
+	upstreamSpec.Region = config.Spec.Region
+	upstreamSpec.AmazonCredentialSecret = config.Spec.AmazonCredentialSecret
+	if accessConfig := clusterState.Cluster.AccessConfig; accessConfig != nil {
+		upstreamSpec.AuthenticationMode = string(accessConfig.AuthenticationMode)
+	}
+
+	addons, err := awsservices.GetAddons(ctx, &awsservices.GetAddonsOpts{
+		EKSService:  awsSVCs.eks,
+		ClusterName: clusterName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, addon := range addons {
+		upstreamSpec.Addons = append(upstreamSpec.Addons, eksv1.UpstreamAddon{
+			Name:                  aws.ToString(addon.AddonName),
+			Version:               aws.ToString(addon.AddonVersion),
+			ServiceAccountRoleARN: aws.ToString(addon.ServiceAccountRoleArn),
+		})
+	}
+
+	profiles, err := awsservices.GetFargateProfiles(ctx, &awsservices.GetFargateProfilesOpts{
+		EKSService:  awsSVCs.eks,
+		ClusterName: clusterName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, profile := range profiles {
+		upstreamProfile := eksv1.UpstreamFargateProfile{
+			Name:                aws.ToString(profile.FargateProfileName),
+			PodExecutionRoleARN: aws.ToString(profile.PodExecutionRoleArn),
+			Subnets:             profile.Subnets,
+		}
+		for _, selector := range profile.Selectors {
+			upstreamProfile.Selectors = append(upstreamProfile.Selectors, eksv1.FargateProfileSelector{
+				Namespace: aws.ToString(selector.Namespace),
+				Labels:    selector.Labels,
+			})
+		}
+		upstreamSpec.FargateProfiles = append(upstreamSpec.FargateProfiles, upstreamProfile)
+	}
+
+	return upstreamSpec, nil
 }
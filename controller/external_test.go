@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services/mock_services"
+	"github.com/rancher/eks-operator/utils"
+)
+
+func TestGetUpstreamSpec(t *testing.T) {
+	ctx := context.Background()
+	eksServiceMock := mock_services.NewMockEKSServiceInterface(gomock.NewController(t))
+	config := &eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{
+		DisplayName:            "test",
+		Region:                 "us-east-1",
+		AmazonCredentialSecret: "cattle-global-data:cc-abc",
+		Imported:               true,
+	}}
+	clusterState := &eks.DescribeClusterOutput{Cluster: &ekstypes.Cluster{
+		Version:            aws.String("1.30"),
+		ResourcesVpcConfig: &ekstypes.VpcConfigResponse{SubnetIds: []string{"subnet-a"}},
+		Logging: &ekstypes.Logging{ClusterLogging: []ekstypes.LogSetup{
+			{Enabled: aws.Bool(true), Types: []ekstypes.LogType{ekstypes.LogTypeAudit}},
+		}},
+		EncryptionConfig: []ekstypes.EncryptionConfig{{Provider: &ekstypes.Provider{KeyArn: aws.String("arn:aws:kms:us-east-1:123456789012:key/abc")}}},
+		AccessConfig:     &ekstypes.AccessConfigResponse{AuthenticationMode: ekstypes.AuthenticationModeApiAndConfigMap},
+	}}
+
+	eksServiceMock.EXPECT().ListNodegroups(ctx, gomock.Any()).Return(&eks.ListNodegroupsOutput{}, nil)
+	eksServiceMock.EXPECT().DescribeAddon(ctx, &eks.DescribeAddonInput{ClusterName: aws.String("test"), AddonName: aws.String("aws-ebs-csi-driver")}).Return(
+		nil, &ekstypes.ResourceNotFoundException{})
+	eksServiceMock.EXPECT().ListAddons(ctx, gomock.Any()).Return(&eks.ListAddonsOutput{Addons: []string{"vpc-cni"}}, nil)
+	eksServiceMock.EXPECT().DescribeAddon(ctx, &eks.DescribeAddonInput{ClusterName: aws.String("test"), AddonName: aws.String("vpc-cni")}).Return(
+		&eks.DescribeAddonOutput{Addon: &ekstypes.Addon{
+			AddonName:             aws.String("vpc-cni"),
+			AddonVersion:          aws.String("v1.18.1-eksbuild.3"),
+			ServiceAccountRoleArn: aws.String("arn:aws:iam::123456789012:role/vpc-cni"),
+		}}, nil)
+	eksServiceMock.EXPECT().ListFargateProfiles(ctx, gomock.Any()).Return(&eks.ListFargateProfilesOutput{FargateProfileNames: []string{"system"}}, nil)
+	eksServiceMock.EXPECT().DescribeFargateProfile(ctx, gomock.Any()).Return(&eks.DescribeFargateProfileOutput{FargateProfile: &ekstypes.FargateProfile{
+		FargateProfileName:  aws.String("system"),
+		PodExecutionRoleArn: aws.String("arn:aws:iam::123456789012:role/fargate"),
+		Subnets:             []string{"subnet-a"},
+		Selectors:           []ekstypes.FargateProfileSelector{{Namespace: aws.String("kube-system"), Labels: map[string]string{"k8s-app": "kube-dns"}}},
+	}}, nil)
+
+	upstreamSpec, err := getUpstreamSpec(ctx, config, clusterState, &awsServices{eks: eksServiceMock})
+	require.NoError(t, err)
+	assert.Equal(t, "us-east-1", upstreamSpec.Region)
+	assert.Equal(t, "cattle-global-data:cc-abc", upstreamSpec.AmazonCredentialSecret)
+	assert.Equal(t, []string{"audit"}, upstreamSpec.LoggingTypes)
+	assert.True(t, aws.ToBool(upstreamSpec.SecretsEncryption))
+	assert.False(t, aws.ToBool(upstreamSpec.EBSCSIDriver))
+	assert.Equal(t, "API_AND_CONFIG_MAP", upstreamSpec.AuthenticationMode)
+	assert.Equal(t, []eksv1.UpstreamAddon{
+		{Name: "vpc-cni", Version: "v1.18.1-eksbuild.3", ServiceAccountRoleARN: "arn:aws:iam::123456789012:role/vpc-cni"},
+	}, upstreamSpec.Addons)
+	assert.Equal(t, []eksv1.UpstreamFargateProfile{{
+		Name:                "system",
+		PodExecutionRoleARN: "arn:aws:iam::123456789012:role/fargate",
+		Subnets:             []string{"subnet-a"},
+		Selectors:           []eksv1.FargateProfileSelector{{Namespace: "kube-system", Labels: map[string]string{"k8s-app": "kube-dns"}}},
+	}}, upstreamSpec.FargateProfiles)
+}
+
+func TestBuildUpstreamClusterStateUserData(t *testing.T) {
+	ctx := context.Background()
+	mockController := gomock.NewController(t)
+	eksServiceMock := mock_services.NewMockEKSServiceInterface(mockController)
+	ec2ServiceMock := mock_services.NewMockEC2ServiceInterface(mockController)
+	clusterState := &eks.DescribeClusterOutput{Cluster: &ekstypes.Cluster{
+		Version:            aws.String("1.30"),
+		ResourcesVpcConfig: &ekstypes.VpcConfigResponse{},
+	}}
+	nodeGroupStates := []*eks.DescribeNodegroupOutput{{Nodegroup: &ekstypes.Nodegroup{
+		NodegroupName:  aws.String("ng"),
+		Version:        aws.String("1.30"),
+		ScalingConfig:  &ekstypes.NodegroupScalingConfig{},
+		LaunchTemplate: &ekstypes.LaunchTemplateSpecification{Id: aws.String("lt-managed"), Version: aws.String("2")},
+	}}}
+	userData := "#!/bin/bash\necho hello"
+
+	eksServiceMock.EXPECT().DescribeAddon(ctx, gomock.Any()).Return(nil, &ekstypes.ResourceNotFoundException{}).Times(2)
+	ec2ServiceMock.EXPECT().DescribeLaunchTemplateVersions(ctx, gomock.Any()).Return(&ec2.DescribeLaunchTemplateVersionsOutput{
+		LaunchTemplateVersions: []ec2types.LaunchTemplateVersion{{LaunchTemplateData: &ec2types.ResponseLaunchTemplateData{
+			BlockDeviceMappings: []ec2types.LaunchTemplateBlockDeviceMapping{{Ebs: &ec2types.LaunchTemplateEbsBlockDevice{VolumeSize: aws.Int32(20)}}},
+			UserData:            aws.String(base64.StdEncoding.EncodeToString([]byte(userData))),
+		}}},
+	}, nil).Times(2)
+
+	// the exported spec is synced back into the spec, so it has to keep the userdata
+	spec, _, err := BuildUpstreamClusterState(ctx, "test", "lt-managed", clusterState, nodeGroupStates, ec2ServiceMock, eksServiceMock, false)
+	require.NoError(t, err)
+	require.Len(t, spec.NodeGroups, 1)
+	assert.Equal(t, userData, aws.ToString(spec.NodeGroups[0].UserData))
+
+	spec, _, hashes, err := buildUpstreamClusterState(ctx, "test", "lt-managed", clusterState, nodeGroupStates, ec2ServiceMock, eksServiceMock, false, false)
+	require.NoError(t, err)
+	assert.Nil(t, spec.NodeGroups[0].UserData)
+	assert.Equal(t, map[string]string{"ng": utils.HashUserData(aws.String(userData))}, hashes)
+}
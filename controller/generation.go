@@ -0,0 +1,25 @@
+package controller
+
+import (
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+)
+
+// setUpdateGeneration records that an upstream update was sent for the current generation of the spec and returns
+// whether it changed.
+func setUpdateGeneration(config *eksv1.EKSClusterConfig) bool {
+	if config.Status.UpdateGeneration == config.Generation {
+		return false
+	}
+	config.Status.UpdateGeneration = config.Generation
+	return true
+}
+
+// setObservedGeneration records that the upstream cluster matches the current generation of the spec and returns
+// whether it changed.
+func setObservedGeneration(config *eksv1.EKSClusterConfig) bool {
+	if config.Status.ObservedGeneration == config.Generation {
+		return false
+	}
+	config.Status.ObservedGeneration = config.Generation
+	return true
+}
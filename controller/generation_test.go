@@ -0,0 +1,19 @@
+package controller
+
+import (
+	"testing"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGenerations(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{ObjectMeta: metav1.ObjectMeta{Generation: 2}}
+
+	assert.True(t, setUpdateGeneration(config))
+	assert.False(t, setUpdateGeneration(config))
+	assert.True(t, setObservedGeneration(config))
+	assert.Equal(t, int64(2), config.Status.UpdateGeneration)
+	assert.Equal(t, int64(2), config.Status.ObservedGeneration)
+}
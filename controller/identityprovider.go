@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	awsservices "github.com/rancher/eks-operator/pkg/eks"
+)
+
+// validateIdentityProviderConfigs checks that there is at most one identity provider config, since EKS only allows a
+// single OIDC identity provider per cluster, and that it is complete and served over https.
+func validateIdentityProviderConfigs(config *eksv1.EKSClusterConfig) error {
+	if len(config.Spec.IdentityProviderConfigs) > 1 {
+		return fmt.Errorf("only one identity provider config can be associated with cluster [%s (id: %s)]",
+			config.Spec.DisplayName, config.Name)
+	}
+	for _, idpConfig := range config.Spec.IdentityProviderConfigs {
+		if idpConfig.Name == "" || idpConfig.IssuerURL == "" || idpConfig.ClientID == "" {
+			return fmt.Errorf("name, issuerUrl and clientId are required for identity provider configs of cluster [%s (id: %s)]",
+				config.Spec.DisplayName, config.Name)
+		}
+		if !strings.HasPrefix(idpConfig.IssuerURL, "https://") {
+			return fmt.Errorf("issuerUrl [%s] of identity provider config [%s] of cluster [%s (id: %s)] must use https",
+				idpConfig.IssuerURL, idpConfig.Name, config.Spec.DisplayName, config.Name)
+		}
+	}
+
+	return nil
+}
+
+// updateIdentityProviders reconciles the identity provider configs of the cluster. It returns whether anything was
+// changed upstream and whether configs are still being associated or disassociated.
+func updateIdentityProviders(ctx context.Context, config *eksv1.EKSClusterConfig, awsSVCs *awsServices) (bool, bool, error) {
+	upstreamConfigs, err := awsservices.GetIdentityProviderConfigs(ctx, &awsservices.GetIdentityProviderConfigsOpts{
+		EKSService:  awsSVCs.eks,
+		ClusterName: config.Spec.DisplayName,
+	})
+	if err != nil {
+		return false, false, fmt.Errorf("error getting identity provider configs: %w", err)
+	}
+
+	updated, err := awsservices.UpdateIdentityProviderConfigs(ctx, &awsservices.UpdateIdentityProviderConfigsOpts{
+		EKSService:      awsSVCs.eks,
+		Config:          config,
+		UpstreamConfigs: upstreamConfigs,
+	})
+	if err != nil {
+		return false, false, err
+	}
+
+	return updated, awsservices.IdentityProviderConfigsPending(upstreamConfigs), nil
+}
@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"testing"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateIdentityProviderConfigs(t *testing.T) {
+	tests := []struct {
+		name      string
+		configs   []eksv1.IdentityProviderConfig
+		expectErr bool
+	}{
+		{
+			name: "no identity provider configs",
+		},
+		{
+			name:    "valid identity provider config",
+			configs: []eksv1.IdentityProviderConfig{{Name: "corp", IssuerURL: "https://issuer", ClientID: "client"}},
+		},
+		{
+			name:      "missing client id",
+			configs:   []eksv1.IdentityProviderConfig{{Name: "corp", IssuerURL: "https://issuer"}},
+			expectErr: true,
+		},
+		{
+			name:      "issuer without https",
+			configs:   []eksv1.IdentityProviderConfig{{Name: "corp", IssuerURL: "http://issuer", ClientID: "client"}},
+			expectErr: true,
+		},
+		{
+			name: "more than one identity provider config",
+			configs: []eksv1.IdentityProviderConfig{
+				{Name: "corp1", IssuerURL: "https://issuer1", ClientID: "client"},
+				{Name: "corp2", IssuerURL: "https://issuer2", ClientID: "client"},
+			},
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{IdentityProviderConfigs: tt.configs}}
+			err := validateIdentityProviderConfigs(config)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
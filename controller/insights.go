@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	awsservices "github.com/rancher/eks-operator/pkg/eks"
+	"github.com/rancher/eks-operator/pkg/features"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// checkInsights records the insights EKS reports with warnings or errors for the cluster in the InsightWarnings
+// condition, so that problems like removed APIs in use are visible ahead of upgrades. It returns whether the status
+// changed. Insights are only read when the ClusterInsights feature is enabled, at most once per
+// informationalCheckInterval. They are advisory, so failing to read them is logged and leaves the condition as it is.
+func (h *Handler) checkInsights(ctx context.Context, config *eksv1.EKSClusterConfig, awsSVCs *awsServices) bool {
+	if !features.Enabled(features.ClusterInsights) || !h.checks.due(config, checkClusterInsights, time.Now()) {
+		return false
+	}
+
+	warnings, err := awsservices.GetInsightWarnings(ctx, &awsservices.GetInsightWarningsOpts{
+		EKSService:  awsSVCs.eks,
+		ClusterName: config.Spec.DisplayName,
+	})
+	if err != nil {
+		logrus.Warnf("Could not read the insights of cluster [%s (id: %s)]: %v", config.Spec.DisplayName, config.Name, err)
+		return false
+	}
+
+	if !setInsightWarningsStatus(config, warnings) {
+		return false
+	}
+	if len(warnings) != 0 {
+		h.recordEvent(config, corev1.EventTypeWarning, eventReasonInsightWarnings, "EKS reports insights with warnings for cluster [%s]: %s",
+			config.Spec.DisplayName, strings.Join(warnings, "; "))
+	}
+	return true
+}
+
+// setInsightWarningsStatus sets the InsightWarnings condition from the insights with warnings and returns whether it
+// changed. The condition is only added to clusters that had insights with warnings.
+func setInsightWarningsStatus(config *eksv1.EKSClusterConfig, warnings []string) bool {
+	if len(warnings) == 0 {
+		if !insightWarnings.IsTrue(config) {
+			return false
+		}
+		insightWarnings.SetStatus(config, string(corev1.ConditionFalse))
+		insightWarnings.Message(config, "")
+		return true
+	}
+
+	message := strings.Join(warnings, "; ")
+	if insightWarnings.IsTrue(config) && insightWarnings.GetMessage(config) == message {
+		return false
+	}
+	insightWarnings.SetStatus(config, string(corev1.ConditionTrue))
+	insightWarnings.Message(config, message)
+	return true
+}
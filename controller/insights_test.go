@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/aws/smithy-go"
+	"github.com/golang/mock/gomock"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services/mock_services"
+	"github.com/rancher/eks-operator/pkg/features"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckInsights(t *testing.T) {
+	ctx := context.Background()
+	eksServiceMock := mock_services.NewMockEKSServiceInterface(gomock.NewController(t))
+	h := &Handler{}
+	awsSVCs := &awsServices{eks: eksServiceMock}
+	config := &eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{DisplayName: "test"}}
+
+	changed := h.checkInsights(ctx, config, awsSVCs)
+	assert.False(t, changed, "insights are only read when the feature is enabled")
+
+	t.Cleanup(func() { require.NoError(t, features.Set("")) })
+	require.NoError(t, features.Set("ClusterInsights=true"))
+	eksServiceMock.EXPECT().ListInsights(ctx, gomock.Any()).Return(&eks.ListInsightsOutput{
+		Insights: []ekstypes.InsightSummary{{
+			Name:          aws.String("Deprecated APIs removed in Kubernetes v1.32"),
+			InsightStatus: &ekstypes.InsightStatus{Status: ekstypes.InsightStatusValueError},
+		}},
+	}, nil)
+	changed = h.checkInsights(ctx, config, awsSVCs)
+	assert.True(t, changed)
+	assert.True(t, insightWarnings.IsTrue(config))
+	assert.Equal(t, "Deprecated APIs removed in Kubernetes v1.32 (ERROR)", insightWarnings.GetMessage(config))
+
+	eksServiceMock.EXPECT().ListInsights(ctx, gomock.Any()).Return(nil, &smithy.GenericAPIError{Code: "AccessDeniedException"})
+	changed = h.checkInsights(ctx, config, awsSVCs)
+	assert.False(t, changed, "credentials that can't read insights leave the condition as it is")
+	assert.True(t, insightWarnings.IsTrue(config))
+
+	eksServiceMock.EXPECT().ListInsights(ctx, gomock.Any()).Return(nil, &smithy.GenericAPIError{Code: "ThrottlingException"})
+	changed = h.checkInsights(ctx, config, awsSVCs)
+	assert.False(t, changed, "errors leave the condition as it is")
+	assert.True(t, insightWarnings.IsTrue(config))
+
+	eksServiceMock.EXPECT().ListInsights(ctx, gomock.Any()).Return(&eks.ListInsightsOutput{}, nil)
+	changed = h.checkInsights(ctx, config, awsSVCs)
+	assert.True(t, changed)
+	assert.True(t, insightWarnings.IsFalse(config))
+
+	// insights are read at most once per interval
+	h.checks = newCheckThrottle(time.Minute)
+	eksServiceMock.EXPECT().ListInsights(ctx, gomock.Any()).Return(&eks.ListInsightsOutput{}, nil)
+	assert.False(t, h.checkInsights(ctx, config, awsSVCs))
+	assert.False(t, h.checkInsights(ctx, config, awsSVCs))
+}
+
+func TestSetInsightWarningsStatus(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{}
+
+	assert.False(t, setInsightWarningsStatus(config, nil))
+	assert.Empty(t, config.Status.Conditions)
+	assert.True(t, setInsightWarningsStatus(config, []string{"a (WARNING)", "b (ERROR)"}))
+	assert.Equal(t, "a (WARNING); b (ERROR)", insightWarnings.GetMessage(config))
+	assert.False(t, setInsightWarningsStatus(config, []string{"a (WARNING)", "b (ERROR)"}))
+	assert.True(t, setInsightWarningsStatus(config, nil))
+	assert.True(t, insightWarnings.IsFalse(config))
+}
@@ -8,13 +8,20 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	cftypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
 	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	awsservices "github.com/rancher/eks-operator/pkg/eks"
 	"github.com/rancher/eks-operator/pkg/eks/services"
 	"github.com/rancher/eks-operator/utils"
 	wranglerv1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// debugAWSPayloadsAnnotation logs the payloads of the AWS requests of a single config and of their responses if it is
+// "true", with user data and credentials redacted
+const debugAWSPayloadsAnnotation = "eks.cattle.io/debug-aws-payloads"
+
 func newAWSConfigV2(ctx context.Context, secretClient wranglerv1.SecretClient, spec eksv1.EKSClusterConfigSpec) (aws.Config, error) {
 	cfg, err := config.LoadDefaultConfig(ctx)
 	if err != nil {
@@ -44,7 +51,7 @@ func newAWSConfigV2(ctx context.Context, secretClient wranglerv1.SecretClient, s
 		cfg.Credentials = credentials.NewStaticCredentialsProvider(accessKey, secretKey, "")
 	}
 
-	return cfg, nil
+	return services.WithDryRun(services.WithRateLimiting(services.WithPayloadLogging(services.WithTransport(cfg)))), nil
 }
 
 func newAWSv2Services(ctx context.Context, secretClient wranglerv1.SecretClient, spec eksv1.EKSClusterConfigSpec) (*awsServices, error) {
@@ -55,23 +62,46 @@ func newAWSv2Services(ctx context.Context, secretClient wranglerv1.SecretClient,
 
 	return &awsServices{
 		eks:            services.NewEKSService(cfg),
-		cloudformation: services.NewCloudFormationService(cfg),
+		cloudformation: services.NewStackCache(services.NewCloudFormationService(cfg)),
 		iam:            services.NewIAMService(cfg),
 		ec2:            services.NewEC2Service(cfg),
+		sts:            services.NewSTSService(cfg),
+		pricing:        services.NewPricingService(cfg),
+		logs:           services.NewCloudWatchLogsService(cfg),
+		cloudwatch:     services.NewCloudWatchService(cfg),
 	}, nil
 }
 
-func deleteStack(ctx context.Context, svc services.CloudFormationServiceInterface, newStyleName, oldStyleName string) error {
-	name := newStyleName
-	_, err := svc.DescribeStacks(ctx, &cloudformation.DescribeStacksInput{
-		StackName: aws.String(name),
-	})
-	if doesNotExist(err) {
-		name = oldStyleName
+// awsServicesFor returns the AWS services for the credentials and region of the spec.
+func (h *Handler) awsServicesFor(ctx context.Context, spec eksv1.EKSClusterConfigSpec) (*awsServices, error) {
+	if h.newAWSServices != nil {
+		return h.newAWSServices(ctx, spec)
+	}
+	return newAWSv2Services(ctx, h.secrets, spec)
+}
+
+// withPayloadLogging returns a context that logs the payloads of the AWS requests sent with it if the config has the
+// debug-aws-payloads annotation.
+func withPayloadLogging(ctx context.Context, config *eksv1.EKSClusterConfig) context.Context {
+	if config.Annotations[debugAWSPayloadsAnnotation] != "true" {
+		return ctx
+	}
+	return services.LogPayloads(ctx, fmt.Sprintf("%s (id: %s)", config.Spec.DisplayName, config.Name))
+}
+
+// deleteStack deletes the stack recorded under the given canonical name. The stack ID is used when it is known, so stacks
+// created with legacy names are deleted as well.
+func deleteStack(ctx context.Context, svc services.CloudFormationServiceInterface, config *eksv1.EKSClusterConfig, name string) error {
+	stackName := name
+	for _, stack := range config.Status.CloudFormationStacks {
+		if stack.Name == name && stack.ID != "" {
+			stackName = stack.ID
+			break
+		}
 	}
 
-	_, err = svc.DeleteStack(ctx, &cloudformation.DeleteStackInput{
-		StackName: aws.String(name),
+	_, err := svc.DeleteStack(ctx, &cloudformation.DeleteStackInput{
+		StackName: aws.String(stackName),
 	})
 	if err != nil && !doesNotExist(err) {
 		return fmt.Errorf("error deleting stack: %w", err)
@@ -79,3 +109,115 @@ func deleteStack(ctx context.Context, svc services.CloudFormationServiceInterfac
 
 	return nil
 }
+
+// migrateStacks records the stacks of the cluster on the config status under their canonical names. Stacks are
+// discovered by the displayName tag, so stacks created with legacy names are found as well and identified by their
+// outputs. Credentials that can't list the stacks of the account are limited to describing the stacks with canonical
+// names, and if they can't describe those either the stacks are left unmigrated, to be discovered again later. The
+// config is expected to be a copy that can be modified.
+func migrateStacks(ctx context.Context, svc services.CloudFormationServiceInterface, config *eksv1.EKSClusterConfig) error {
+	stacks, err := awsservices.GetClusterStacks(ctx, &awsservices.GetClusterStacksOpts{
+		CloudFormationService: svc,
+		DisplayName:           config.Spec.DisplayName,
+	})
+	if isAccessDenied(err) {
+		logrus.Infof("Credentials of cluster [%s (id: %s)] can't list stacks, only stacks with canonical names are discovered: %v",
+			config.Spec.DisplayName, config.Name, err)
+		stacks, err = describeCanonicalStacks(ctx, svc, config.Spec.DisplayName)
+		if isAccessDenied(err) {
+			logrus.Warnf("Skipping stack discovery for cluster [%s (id: %s)], the credentials can't describe its stacks: %v",
+				config.Spec.DisplayName, config.Name, err)
+			return nil
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, stack := range stacks {
+		name := getCanonicalStackName(config.Spec.DisplayName, stack)
+		if name == "" {
+			continue
+		}
+		if name != aws.ToString(stack.StackName) {
+			if recordedStackID(config, name) != "" {
+				// a stack with the canonical name takes precedence over a legacy one
+				continue
+			}
+			logrus.Infof("Recording stack [%s] with legacy name as [%s] for cluster [%s (id: %s)]",
+				aws.ToString(stack.StackName), name, config.Spec.DisplayName, config.Name)
+		}
+		setStackStatus(config, name, aws.ToString(stack.StackId), string(stack.StackStatus))
+	}
+
+	config.Status.CloudFormationStacksMigrated = true
+	return nil
+}
+
+// describeCanonicalStacks describes the stacks of the cluster with canonical names one by one, for credentials that
+// can't list the stacks of the account. Stacks that don't exist are left out.
+func describeCanonicalStacks(ctx context.Context, svc services.CloudFormationServiceInterface, displayName string) ([]cftypes.Stack, error) {
+	var stacks []cftypes.Stack
+	for _, name := range canonicalStackNames(displayName) {
+		output, err := svc.DescribeStacks(ctx, &cloudformation.DescribeStacksInput{StackName: aws.String(name)})
+		if doesNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		stacks = append(stacks, output.Stacks...)
+	}
+	return stacks, nil
+}
+
+// canonicalStackNames returns the names the operator uses for the stacks of the cluster, keyed by the output that
+// identifies each stack.
+func canonicalStackNames(displayName string) map[string]string {
+	return map[string]string{
+		"VpcId":                       getVPCStackName(displayName),
+		"RoleArn":                     getServiceRoleName(displayName),
+		"NodeInstanceRole":            getNodeInstanceRoleStackName(displayName),
+		"EBSCSIDriverRole":            getEBSCSIDriverRoleStackName(displayName),
+		"EBSCSIDriverPodIdentityRole": getEBSCSIDriverPodIdentityRoleStackName(displayName),
+	}
+}
+
+// getCanonicalStackName returns the name the operator uses for the given stack of the cluster, determined by the stack
+// name or, for legacy names, by the stack outputs. An empty string is returned if the stack is unknown.
+func getCanonicalStackName(displayName string, stack cftypes.Stack) string {
+	stackNames := canonicalStackNames(displayName)
+
+	for _, name := range stackNames {
+		if aws.ToString(stack.StackName) == name {
+			return name
+		}
+	}
+
+	for _, output := range stack.Outputs {
+		if name, ok := stackNames[aws.ToString(output.OutputKey)]; ok {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// stackRecorded returns whether a stack is recorded on the config status under the given canonical name.
+func stackRecorded(config *eksv1.EKSClusterConfig, name string) bool {
+	for _, stack := range config.Status.CloudFormationStacks {
+		if stack.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func recordedStackID(config *eksv1.EKSClusterConfig, name string) string {
+	for _, stack := range config.Status.CloudFormationStacks {
+		if stack.Name == name {
+			return stack.ID
+		}
+	}
+	return ""
+}
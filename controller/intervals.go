@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+)
+
+// requeueInterval is the kind of upstream operation a config is requeued to wait for
+type requeueInterval string
+
+const (
+	creatingInterval requeueInterval = "creating"
+	updatingInterval requeueInterval = "updating"
+	deletingInterval requeueInterval = "deleting"
+	stackInterval    requeueInterval = "stack"
+
+	// requeueIntervalAnnotationFormat is the annotation that overrides an interval for a single config, e.g.
+	// eks.cattle.io/updating-interval: 2m
+	requeueIntervalAnnotationFormat = "eks.cattle.io/%s-interval"
+)
+
+// defaultRequeueIntervals are used for the intervals that aren't configured on the operator
+var defaultRequeueIntervals = map[requeueInterval]time.Duration{
+	creatingInterval: 30 * time.Second,
+	updatingInterval: 30 * time.Second,
+	deletingInterval: 10 * time.Second,
+	stackInterval:    10 * time.Second,
+}
+
+// newRequeueIntervals returns the requeue intervals of the operator, falling back to the defaults for the ones that
+// aren't set.
+func newRequeueIntervals(opts *RegisterOpts) map[requeueInterval]time.Duration {
+	intervals := map[requeueInterval]time.Duration{
+		creatingInterval: opts.CreatingInterval,
+		updatingInterval: opts.UpdatingInterval,
+		deletingInterval: opts.DeletingInterval,
+		stackInterval:    opts.StackInterval,
+	}
+	for interval, duration := range intervals {
+		if duration <= 0 {
+			intervals[interval] = defaultRequeueIntervals[interval]
+		}
+	}
+	return intervals
+}
+
+// getRequeueInterval returns how long the config waits before checking on an upstream operation of the given kind
+// again. The interval of the operator is used unless the config overrides it with an annotation.
+func (h *Handler) getRequeueInterval(config *eksv1.EKSClusterConfig, interval requeueInterval) time.Duration {
+	duration := h.requeueIntervals[interval]
+	if duration <= 0 {
+		duration = defaultRequeueIntervals[interval]
+	}
+
+	annotation := fmt.Sprintf(requeueIntervalAnnotationFormat, interval)
+	value, ok := config.Annotations[annotation]
+	if !ok {
+		return duration
+	}
+	override, err := time.ParseDuration(value)
+	if err != nil || override <= 0 {
+		logrus.Warnf("Ignoring invalid annotation [%s: %s] of cluster [%s (id: %s)], it must be a positive duration such as 1m",
+			annotation, value, config.Spec.DisplayName, config.Name)
+		return duration
+	}
+	return override
+}
+
+// requeue enqueues the config again once the interval of the given kind has passed.
+func (h *Handler) requeue(config *eksv1.EKSClusterConfig, interval requeueInterval) {
+	h.eksEnqueueAfter(config.Namespace, config.Name, h.getRequeueInterval(config, interval))
+}
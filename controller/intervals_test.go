@@ -0,0 +1,30 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetRequeueInterval(t *testing.T) {
+	h := &Handler{requeueIntervals: newRequeueIntervals(&RegisterOpts{UpdatingInterval: 2 * time.Minute})}
+	config := &eksv1.EKSClusterConfig{}
+
+	assert.Equal(t, 30*time.Second, h.getRequeueInterval(config, creatingInterval))
+	assert.Equal(t, 2*time.Minute, h.getRequeueInterval(config, updatingInterval))
+	assert.Equal(t, 10*time.Second, h.getRequeueInterval(config, deletingInterval))
+	assert.Equal(t, 10*time.Second, h.getRequeueInterval(config, stackInterval))
+
+	config.ObjectMeta = metav1.ObjectMeta{Annotations: map[string]string{
+		"eks.cattle.io/creating-interval": "1m",
+		"eks.cattle.io/updating-interval": "invalid",
+		"eks.cattle.io/deleting-interval": "-1s",
+	}}
+	assert.Equal(t, time.Minute, h.getRequeueInterval(config, creatingInterval))
+	// invalid overrides fall back to the interval of the operator
+	assert.Equal(t, 2*time.Minute, h.getRequeueInterval(config, updatingInterval))
+	assert.Equal(t, 10*time.Second, h.getRequeueInterval(config, deletingInterval))
+}
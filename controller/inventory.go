@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	awsservices "github.com/rancher/eks-operator/pkg/eks"
+)
+
+const (
+	inventoryControllerName = "eks-inventory-controller"
+
+	// how often the clusters of an inventory are discovered again, since upstream clusters can't be watched
+	inventorySyncInterval = 10 * time.Minute
+)
+
+// OnInventoryChanged discovers the upstream clusters in the regions of the inventory and records the ones that are not
+// represented by an EKSClusterConfig on its status. The inventory is synced whenever its spec changes and every
+// inventorySyncInterval otherwise.
+func (h *Handler) OnInventoryChanged(_ string, inventory *eksv1.EKSInventory) (*eksv1.EKSInventory, error) {
+	if inventory == nil || inventory.DeletionTimestamp != nil {
+		return inventory, nil
+	}
+
+	if inventory.Status.ObservedGeneration == inventory.Generation {
+		if lastSync, err := time.Parse(time.RFC3339, inventory.Status.LastSyncTime); err == nil {
+			if wait := inventorySyncInterval - time.Since(lastSync); wait > 0 {
+				h.inventoryEnqueueAfter(inventory.Namespace, inventory.Name, wait)
+				return inventory, nil
+			}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	inventory = inventory.DeepCopy()
+	clusters, syncErr := h.discoverClusters(ctx, inventory)
+	if syncErr == nil {
+		inventory.Status.Clusters = clusters
+		inventory.Status.LastSyncTime = time.Now().UTC().Format(time.RFC3339)
+		inventory.Status.ObservedGeneration = inventory.Generation
+		inventory.Status.FailureMessage = ""
+	} else {
+		inventory.Status.FailureMessage = syncErr.Error()
+	}
+
+	updated, err := h.updateInventoryStatus(inventory)
+	if err != nil {
+		return inventory, fmt.Errorf("error updating status of inventory [%s/%s]: %w", inventory.Namespace, inventory.Name, err)
+	}
+	if syncErr != nil {
+		return updated, syncErr
+	}
+
+	logrus.Infof("Discovered [%d] clusters that are not managed by an eksclusterconfig for inventory [%s/%s]",
+		len(clusters), inventory.Namespace, inventory.Name)
+	h.inventoryEnqueueAfter(inventory.Namespace, inventory.Name, inventorySyncInterval)
+	return updated, nil
+}
+
+// discoverClusters returns the upstream clusters in the regions of the inventory that are not represented by an
+// EKSClusterConfig.
+func (h *Handler) discoverClusters(ctx context.Context, inventory *eksv1.EKSInventory) ([]eksv1.DiscoveredCluster, error) {
+	configs, err := h.eksCC.List("", metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing eksclusterconfigs: %w", err)
+	}
+
+	var discovered []eksv1.DiscoveredCluster
+	for _, region := range inventory.Spec.Regions {
+		awsSVCs, err := h.awsServicesFor(ctx, eksv1.EKSClusterConfigSpec{
+			AmazonCredentialSecret: inventory.Spec.AmazonCredentialSecret,
+			Region:                 region,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error creating new AWS services: %w", err)
+		}
+
+		clusters, err := awsservices.GetClusters(ctx, &awsservices.GetClustersOpts{
+			EKSService: awsSVCs.eks,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error discovering clusters in region [%s]: %w", region, err)
+		}
+		discovered = append(discovered, getUnmanagedClusters(region, clusters, configs.Items)...)
+	}
+
+	sort.Slice(discovered, func(i, j int) bool {
+		if discovered[i].Region != discovered[j].Region {
+			return discovered[i].Region < discovered[j].Region
+		}
+		return discovered[i].Name < discovered[j].Name
+	})
+	return discovered, nil
+}
+
+// getUnmanagedClusters returns the clusters of the region that are not represented by any of the configs. A cluster is
+// represented by a config with its display name and region.
+func getUnmanagedClusters(region string, clusters []ekstypes.Cluster, configs []eksv1.EKSClusterConfig) []eksv1.DiscoveredCluster {
+	managed := make(map[string]struct{}, len(configs))
+	for _, config := range configs {
+		if config.Spec.Region == region {
+			managed[config.Spec.DisplayName] = struct{}{}
+		}
+	}
+
+	var unmanaged []eksv1.DiscoveredCluster
+	for _, cluster := range clusters {
+		if _, ok := managed[aws.ToString(cluster.Name)]; ok {
+			continue
+		}
+		unmanaged = append(unmanaged, eksv1.DiscoveredCluster{
+			Name:              aws.ToString(cluster.Name),
+			Region:            region,
+			ARN:               aws.ToString(cluster.Arn),
+			KubernetesVersion: aws.ToString(cluster.Version),
+			Status:            string(cluster.Status),
+		})
+	}
+	return unmanaged
+}
@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetUnmanagedClusters(t *testing.T) {
+	clusters := []ekstypes.Cluster{
+		{
+			Name:    aws.String("managed"),
+			Arn:     aws.String("arn:aws:eks:us-west-2:123456789012:cluster/managed"),
+			Version: aws.String("1.30"),
+			Status:  ekstypes.ClusterStatusActive,
+		},
+		{
+			Name:    aws.String("unmanaged"),
+			Arn:     aws.String("arn:aws:eks:us-west-2:123456789012:cluster/unmanaged"),
+			Version: aws.String("1.29"),
+			Status:  ekstypes.ClusterStatusUpdating,
+		},
+	}
+	configs := []eksv1.EKSClusterConfig{
+		{Spec: eksv1.EKSClusterConfigSpec{DisplayName: "managed", Region: "us-west-2"}},
+		// a config for a cluster with the same name in another region doesn't represent it
+		{Spec: eksv1.EKSClusterConfigSpec{DisplayName: "unmanaged", Region: "us-east-1"}},
+	}
+
+	assert.Equal(t, []eksv1.DiscoveredCluster{
+		{
+			Name:              "unmanaged",
+			Region:            "us-west-2",
+			ARN:               "arn:aws:eks:us-west-2:123456789012:cluster/unmanaged",
+			KubernetesVersion: "1.29",
+			Status:            "UPDATING",
+		},
+	}, getUnmanagedClusters("us-west-2", clusters, configs))
+
+	assert.Empty(t, getUnmanagedClusters("us-west-2", clusters[:1], configs))
+}
@@ -0,0 +1,147 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	awsservices "github.com/rancher/eks-operator/pkg/eks"
+	"github.com/rancher/eks-operator/pkg/eks/services"
+	wranglerv1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+)
+
+const (
+	kubeconfigSecretNameFormat = "%s-kubeconfig"
+	kubeconfigSecretKey        = "value"
+)
+
+// ensureKubeconfigSecret creates or updates the kubeconfig secret of the config if generateKubeconfig is enabled. The
+// secret is owned by the config, so it is removed along with it.
+func (h *Handler) ensureKubeconfigSecret(config *eksv1.EKSClusterConfig, clusterState *eks.DescribeClusterOutput) error {
+	if !aws.ToBool(config.Spec.GenerateKubeconfig) {
+		return nil
+	}
+
+	kubeconfig, err := buildKubeconfig(config, clusterState)
+	if err != nil {
+		return fmt.Errorf("error building kubeconfig for cluster [%s (id: %s)]: %w", config.Spec.DisplayName, config.Name, err)
+	}
+
+	name := fmt.Sprintf(kubeconfigSecretNameFormat, config.Name)
+	secret, err := h.secretsCache.Get(config.Namespace, name)
+	switch {
+	case apierrors.IsNotFound(err):
+		logrus.Infof("Creating kubeconfig secret [%s] for cluster [%s (id: %s)]", name, config.Spec.DisplayName, config.Name)
+		_, err = h.secrets.Create(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: config.Namespace,
+				OwnerReferences: []metav1.OwnerReference{
+					{
+						APIVersion: eksv1.SchemeGroupVersion.String(),
+						Kind:       eksClusterConfigKind,
+						UID:        config.UID,
+						Name:       config.Name,
+					},
+				},
+			},
+			Data: map[string][]byte{kubeconfigSecretKey: kubeconfig},
+		})
+	case err == nil && !bytes.Equal(secret.Data[kubeconfigSecretKey], kubeconfig):
+		logrus.Infof("Updating kubeconfig secret [%s] for cluster [%s (id: %s)]", name, config.Spec.DisplayName, config.Name)
+		secret = secret.DeepCopy()
+		secret.Data = map[string][]byte{kubeconfigSecretKey: kubeconfig}
+		_, err = h.secrets.Update(secret)
+	}
+	if err != nil {
+		return fmt.Errorf("error storing kubeconfig secret [%s] for cluster [%s (id: %s)]: %w", name, config.Spec.DisplayName, config.Name, err)
+	}
+
+	return nil
+}
+
+// buildKubeconfig returns a kubeconfig for the cluster that gets tokens from the aws cli, the same way the one written
+// by aws eks update-kubeconfig does.
+func buildKubeconfig(config *eksv1.EKSClusterConfig, clusterState *eks.DescribeClusterOutput) ([]byte, error) {
+	if clusterState.Cluster == nil || clusterState.Cluster.CertificateAuthority == nil {
+		return nil, fmt.Errorf("no endpoint or certificate authority was returned")
+	}
+	ca, err := base64.StdEncoding.DecodeString(aws.ToString(clusterState.Cluster.CertificateAuthority.Data))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding certificate authority: %w", err)
+	}
+
+	return writeKubeconfig(config, aws.ToString(clusterState.Cluster.Endpoint), ca, &clientcmdapi.AuthInfo{
+		Exec: &clientcmdapi.ExecConfig{
+			APIVersion:      "client.authentication.k8s.io/v1beta1",
+			Command:         "aws",
+			Args:            []string{"eks", "get-token", "--cluster-name", config.Spec.DisplayName, "--region", config.Spec.Region, "--output", "json"},
+			InteractiveMode: clientcmdapi.NeverExecInteractiveMode,
+		},
+	})
+}
+
+// Kubeconfig returns a ready-to-use kubeconfig for the cluster of the config, e.g. for admins reaching a managed
+// cluster during an incident. The endpoint and certificate authority are read from the secret the operator stores for
+// the cluster, and the token is generated with the AWS credentials of the config, so neither the aws cli nor access to
+// the credentials is needed to use it. EKS accepts the token for 15 minutes.
+func Kubeconfig(ctx context.Context, secretClient wranglerv1.SecretClient, config *eksv1.EKSClusterConfig) ([]byte, error) {
+	secret, err := secretClient.Get(config.Namespace, config.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting endpoint and certificate authority of cluster [%s (id: %s)]: %w", config.Spec.DisplayName, config.Name, err)
+	}
+	endpoint := string(secret.Data["endpoint"])
+	if endpoint == "" {
+		return nil, fmt.Errorf("cluster [%s (id: %s)] has no endpoint, it may not be active yet", config.Spec.DisplayName, config.Name)
+	}
+	ca, err := base64.StdEncoding.DecodeString(string(secret.Data["ca"]))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding certificate authority of cluster [%s (id: %s)]: %w", config.Spec.DisplayName, config.Name, err)
+	}
+
+	cfg, err := newAWSConfigV2(ctx, secretClient, config.Spec)
+	if err != nil {
+		return nil, err
+	}
+	token, err := awsservices.GetClusterToken(ctx, &awsservices.GetClusterTokenOpts{
+		STSService:  services.NewSTSService(cfg),
+		ClusterName: config.Spec.DisplayName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error generating token for cluster [%s (id: %s)]: %w", config.Spec.DisplayName, config.Name, err)
+	}
+
+	return writeKubeconfig(config, endpoint, ca, &clientcmdapi.AuthInfo{Token: token})
+}
+
+// writeKubeconfig returns a kubeconfig with a single context named after the cluster, for the API server of the
+// cluster with the given credentials.
+func writeKubeconfig(config *eksv1.EKSClusterConfig, server string, ca []byte, authInfo *clientcmdapi.AuthInfo) ([]byte, error) {
+	name := config.Spec.DisplayName
+	tlsConfig := downstreamTLSClientConfig(config, ca)
+	kubeconfig := clientcmdapi.NewConfig()
+	kubeconfig.Clusters[name] = &clientcmdapi.Cluster{
+		Server:                   server,
+		CertificateAuthorityData: tlsConfig.CAData,
+		TLSServerName:            tlsConfig.ServerName,
+	}
+	kubeconfig.AuthInfos[name] = authInfo
+	kubeconfig.Contexts[name] = &clientcmdapi.Context{
+		Cluster:  name,
+		AuthInfo: name,
+	}
+	kubeconfig.CurrentContext = name
+
+	return clientcmd.Write(*kubeconfig)
+}
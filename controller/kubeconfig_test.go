@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	wranglerv1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+)
+
+func TestBuildKubeconfig(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{DisplayName: "test", Region: "us-west-2"}}
+	clusterState := &eks.DescribeClusterOutput{
+		Cluster: &ekstypes.Cluster{
+			Endpoint:             aws.String("https://test.eks.amazonaws.com"),
+			CertificateAuthority: &ekstypes.Certificate{Data: aws.String(base64.StdEncoding.EncodeToString([]byte("ca")))},
+		},
+	}
+
+	data, err := buildKubeconfig(config, clusterState)
+	require.NoError(t, err)
+
+	kubeconfig, err := clientcmd.Load(data)
+	require.NoError(t, err)
+	assert.Equal(t, "test", kubeconfig.CurrentContext)
+	assert.Equal(t, "https://test.eks.amazonaws.com", kubeconfig.Clusters["test"].Server)
+	assert.Equal(t, []byte("ca"), kubeconfig.Clusters["test"].CertificateAuthorityData)
+	assert.Equal(t, "aws", kubeconfig.AuthInfos["test"].Exec.Command)
+	assert.Equal(t, []string{"eks", "get-token", "--cluster-name", "test", "--region", "us-west-2", "--output", "json"},
+		kubeconfig.AuthInfos["test"].Exec.Args)
+
+	config.Spec.DownstreamTLS = &eksv1.DownstreamTLS{ServerName: "api.test.internal"}
+	data, err = buildKubeconfig(config, clusterState)
+	require.NoError(t, err)
+	kubeconfig, err = clientcmd.Load(data)
+	require.NoError(t, err)
+	assert.Equal(t, "api.test.internal", kubeconfig.Clusters["test"].TLSServerName)
+
+	clusterState.Cluster.CertificateAuthority = nil
+	_, err = buildKubeconfig(config, clusterState)
+	assert.Error(t, err)
+}
+
+// secretGetter returns the secrets it holds, calls other than Get panic.
+type secretGetter struct {
+	wranglerv1.SecretClient
+	secrets []*corev1.Secret
+}
+
+func (s secretGetter) Get(namespace, name string, _ metav1.GetOptions) (*corev1.Secret, error) {
+	for _, secret := range s.secrets {
+		if secret.Namespace == namespace && secret.Name == name {
+			return secret, nil
+		}
+	}
+	return nil, apierrors.NewNotFound(corev1.Resource("secrets"), name)
+}
+
+func TestKubeconfig(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "c-abc", Namespace: "cattle-global-data"},
+		Spec: eksv1.EKSClusterConfigSpec{
+			DisplayName:            "test",
+			Region:                 "us-west-2",
+			AmazonCredentialSecret: "cattle-global-data:cc-abc",
+		},
+	}
+	credentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "cc-abc", Namespace: "cattle-global-data"},
+		Data: map[string][]byte{
+			"amazonec2credentialConfig-accessKey": []byte("AKIAEXAMPLE"),
+			"amazonec2credentialConfig-secretKey": []byte("secret"),
+		},
+	}
+	clusterSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "c-abc", Namespace: "cattle-global-data"},
+		Data: map[string][]byte{
+			"endpoint": []byte("https://test.eks.amazonaws.com"),
+			"ca":       []byte(base64.StdEncoding.EncodeToString([]byte("ca"))),
+		},
+	}
+
+	data, err := Kubeconfig(context.Background(), secretGetter{secrets: []*corev1.Secret{credentials, clusterSecret}}, config)
+	require.NoError(t, err)
+
+	kubeconfig, err := clientcmd.Load(data)
+	require.NoError(t, err)
+	assert.Equal(t, "test", kubeconfig.CurrentContext)
+	assert.Equal(t, "https://test.eks.amazonaws.com", kubeconfig.Clusters["test"].Server)
+	assert.Equal(t, []byte("ca"), kubeconfig.Clusters["test"].CertificateAuthorityData)
+	assert.Nil(t, kubeconfig.AuthInfos["test"].Exec)
+	assert.True(t, strings.HasPrefix(kubeconfig.AuthInfos["test"].Token, "k8s-aws-v1."))
+
+	// the cluster secret is only stored once the cluster is active
+	_, err = Kubeconfig(context.Background(), secretGetter{secrets: []*corev1.Secret{credentials}}, config)
+	assert.Error(t, err)
+}
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/sirupsen/logrus"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+)
+
+// getSharedLaunchTemplate returns the shared launch template of the config the given launch template refers to by ID
+// or name, or nil if it doesn't refer to one.
+func getSharedLaunchTemplate(config *eksv1.EKSClusterConfig, lt *eksv1.LaunchTemplate) *eksv1.LaunchTemplate {
+	if lt == nil {
+		return nil
+	}
+	for i, shared := range config.Spec.SharedLaunchTemplates {
+		if lt.ID != nil && aws.ToString(lt.ID) == aws.ToString(shared.ID) {
+			return &config.Spec.SharedLaunchTemplates[i]
+		}
+		if lt.Name != nil && aws.ToString(lt.Name) == aws.ToString(shared.Name) {
+			return &config.Spec.SharedLaunchTemplates[i]
+		}
+	}
+	return nil
+}
+
+// applySharedLaunchTemplate returns the node group with the ID, name and version of the shared launch template its
+// launch template refers to, if any. The launch template is copied, so the spec is left untouched.
+func applySharedLaunchTemplate(config *eksv1.EKSClusterConfig, ng eksv1.NodeGroup) eksv1.NodeGroup {
+	shared := getSharedLaunchTemplate(config, ng.LaunchTemplate)
+	if shared == nil {
+		return ng
+	}
+
+	lt := *ng.LaunchTemplate
+	if lt.ID == nil {
+		lt.ID = shared.ID
+	}
+	if lt.Name == nil {
+		lt.Name = shared.Name
+	}
+	lt.Version = shared.Version
+	ng.LaunchTemplate = &lt
+	return ng
+}
+
+// validateSharedLaunchTemplates checks that the shared launch templates have an ID or name and a version and are
+// unique. Node groups referring to one of them that pin another version of it only get a warning, as the version of the
+// shared launch template wins.
+func validateSharedLaunchTemplates(config *eksv1.EKSClusterConfig) error {
+	ids := make(map[string]struct{}, len(config.Spec.SharedLaunchTemplates))
+	names := make(map[string]struct{}, len(config.Spec.SharedLaunchTemplates))
+	for _, lt := range config.Spec.SharedLaunchTemplates {
+		id, name := aws.ToString(lt.ID), aws.ToString(lt.Name)
+		if id == "" && name == "" {
+			return fmt.Errorf("shared launch templates of cluster [%s (id: %s)] must have an id or a name", config.Spec.DisplayName, config.Name)
+		}
+		if lt.Version == nil {
+			return fmt.Errorf("shared launch template [%s] of cluster [%s (id: %s)] must have a version", id+name, config.Spec.DisplayName, config.Name)
+		}
+		if _, ok := ids[id]; ok && id != "" {
+			return fmt.Errorf("shared launch template [%s] of cluster [%s (id: %s)] is not unique", id, config.Spec.DisplayName, config.Name)
+		}
+		if _, ok := names[name]; ok && name != "" {
+			return fmt.Errorf("shared launch template [%s] of cluster [%s (id: %s)] is not unique", name, config.Spec.DisplayName, config.Name)
+		}
+		ids[id] = struct{}{}
+		names[name] = struct{}{}
+	}
+
+	for _, ng := range config.Spec.NodeGroups {
+		shared := getSharedLaunchTemplate(config, ng.LaunchTemplate)
+		if shared == nil || ng.LaunchTemplate.Version == nil {
+			continue
+		}
+		if aws.ToInt64(ng.LaunchTemplate.Version) != aws.ToInt64(shared.Version) {
+			logrus.Warnf("Ignoring launchTemplate.version [%d] of nodegroup [%s] in cluster [%s (id: %s)], it follows version [%d] of the shared launch template",
+				aws.ToInt64(ng.LaunchTemplate.Version), aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name, aws.ToInt64(shared.Version))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+)
+
+func TestApplySharedLaunchTemplate(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{
+		Spec: eksv1.EKSClusterConfigSpec{
+			SharedLaunchTemplates: []eksv1.LaunchTemplate{
+				{ID: aws.String("lt-1"), Name: aws.String("workers"), Version: aws.Int64(3)},
+			},
+		},
+	}
+
+	byID := eksv1.NodeGroup{NodegroupName: aws.String("ng1"), LaunchTemplate: &eksv1.LaunchTemplate{ID: aws.String("lt-1")}}
+	assert.Equal(t, &eksv1.LaunchTemplate{ID: aws.String("lt-1"), Name: aws.String("workers"), Version: aws.Int64(3)},
+		applySharedLaunchTemplate(config, byID).LaunchTemplate)
+	assert.Nil(t, byID.LaunchTemplate.Version, "the spec is left untouched")
+
+	byName := eksv1.NodeGroup{NodegroupName: aws.String("ng2"), LaunchTemplate: &eksv1.LaunchTemplate{Name: aws.String("workers"), Version: aws.Int64(3)}}
+	assert.Equal(t, &eksv1.LaunchTemplate{ID: aws.String("lt-1"), Name: aws.String("workers"), Version: aws.Int64(3)},
+		applySharedLaunchTemplate(config, byName).LaunchTemplate)
+
+	// changing the version of the shared launch template rolls all node groups referring to it
+	config.Spec.SharedLaunchTemplates[0].Version = aws.Int64(4)
+	assert.Equal(t, int64(4), aws.ToInt64(applySharedLaunchTemplate(config, byID).LaunchTemplate.Version))
+	assert.Equal(t, int64(4), aws.ToInt64(applySharedLaunchTemplate(config, byName).LaunchTemplate.Version))
+
+	other := eksv1.NodeGroup{NodegroupName: aws.String("ng3"), LaunchTemplate: &eksv1.LaunchTemplate{ID: aws.String("lt-2"), Version: aws.Int64(1)}}
+	assert.Equal(t, other, applySharedLaunchTemplate(config, other))
+	managed := eksv1.NodeGroup{NodegroupName: aws.String("ng4")}
+	assert.Equal(t, managed, applySharedLaunchTemplate(config, managed))
+}
+
+func TestValidateSharedLaunchTemplates(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{
+		Spec: eksv1.EKSClusterConfigSpec{
+			SharedLaunchTemplates: []eksv1.LaunchTemplate{
+				{ID: aws.String("lt-1"), Version: aws.Int64(3)},
+				{Name: aws.String("workers"), Version: aws.Int64(1)},
+			},
+			NodeGroups: []eksv1.NodeGroup{
+				{NodegroupName: aws.String("ng1"), LaunchTemplate: &eksv1.LaunchTemplate{ID: aws.String("lt-1")}},
+				{NodegroupName: aws.String("ng2"), LaunchTemplate: &eksv1.LaunchTemplate{ID: aws.String("lt-1"), Version: aws.Int64(3)}},
+				{NodegroupName: aws.String("ng3"), LaunchTemplate: &eksv1.LaunchTemplate{Name: aws.String("workers")}},
+				{NodegroupName: aws.String("ng4"), LaunchTemplate: &eksv1.LaunchTemplate{ID: aws.String("lt-2"), Version: aws.Int64(7)}},
+				{NodegroupName: aws.String("ng5")},
+			},
+		},
+	}
+	assert.NoError(t, validateSharedLaunchTemplates(config))
+
+	// bumping the shared version doesn't block node groups that pinned the previous one
+	bumped := config.DeepCopy()
+	bumped.Spec.SharedLaunchTemplates[0].Version = aws.Int64(4)
+	assert.NoError(t, validateSharedLaunchTemplates(bumped))
+	assert.Equal(t, int64(4), aws.ToInt64(applySharedLaunchTemplate(bumped, bumped.Spec.NodeGroups[1]).LaunchTemplate.Version))
+
+	for _, shared := range [][]eksv1.LaunchTemplate{
+		{{Version: aws.Int64(1)}},
+		{{ID: aws.String("lt-1")}},
+		{{ID: aws.String("lt-1"), Version: aws.Int64(3)}, {ID: aws.String("lt-1"), Version: aws.Int64(3)}},
+		{{ID: aws.String("lt-1"), Version: aws.Int64(3)}, {Name: aws.String("workers"), Version: aws.Int64(1)}, {Name: aws.String("workers"), Version: aws.Int64(1)}},
+	} {
+		invalid := config.DeepCopy()
+		invalid.Spec.SharedLaunchTemplates = shared
+		assert.Error(t, validateSharedLaunchTemplates(invalid), shared)
+	}
+}
@@ -0,0 +1,379 @@
+package controller
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	awssdkeks "github.com/aws/aws-sdk-go-v2/service/eks"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services/mock_services"
+	"github.com/rancher/eks-operator/pkg/features"
+	"github.com/rancher/eks-operator/pkg/test"
+)
+
+// fakeAccountID is the account the fake AWS services run in.
+const fakeAccountID = "123456789012"
+
+// fakeEKSService keeps the clusters created through it in memory, so that the handler can be run through whole
+// lifecycles. Clusters become active on the second describe and are gone on the second describe after their deletion.
+// Calls that aren't faked go to the embedded mock and fail the test unless they are expected.
+type fakeEKSService struct {
+	*mock_services.MockEKSServiceInterface
+
+	mu       sync.Mutex
+	clusters map[string]*ekstypes.Cluster
+	calls    []string
+}
+
+func newFakeEKSService(ctrl *gomock.Controller) *fakeEKSService {
+	return &fakeEKSService{
+		MockEKSServiceInterface: mock_services.NewMockEKSServiceInterface(ctrl),
+		clusters:                map[string]*ekstypes.Cluster{},
+	}
+}
+
+func (f *fakeEKSService) record(call string) {
+	f.calls = append(f.calls, call)
+}
+
+// clusterCalls returns the names of the calls made on clusters so far, in order.
+func (f *fakeEKSService) clusterCalls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
+
+func (f *fakeEKSService) ListClusters(_ context.Context, _ *awssdkeks.ListClustersInput) (*awssdkeks.ListClustersOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("ListClusters")
+	output := &awssdkeks.ListClustersOutput{}
+	for name := range f.clusters {
+		output.Clusters = append(output.Clusters, name)
+	}
+	return output, nil
+}
+
+func (f *fakeEKSService) CreateCluster(_ context.Context, input *awssdkeks.CreateClusterInput) (*awssdkeks.CreateClusterOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("CreateCluster")
+	name := aws.ToString(input.Name)
+	if _, ok := f.clusters[name]; ok {
+		return nil, &ekstypes.ResourceInUseException{Message: aws.String(fmt.Sprintf("cluster %s already exists", name))}
+	}
+
+	cluster := &ekstypes.Cluster{
+		Name:                 input.Name,
+		Arn:                  aws.String("arn:aws:eks:us-west-2:" + fakeAccountID + ":cluster/" + name),
+		Version:              input.Version,
+		RoleArn:              input.RoleArn,
+		Status:               ekstypes.ClusterStatusCreating,
+		Endpoint:             aws.String("https://" + name + ".eks.amazonaws.com"),
+		CertificateAuthority: &ekstypes.Certificate{Data: aws.String(base64.StdEncoding.EncodeToString([]byte("ca")))},
+		Logging:              input.Logging,
+		EncryptionConfig:     input.EncryptionConfig,
+		Tags:                 map[string]string{},
+		ResourcesVpcConfig: &ekstypes.VpcConfigResponse{
+			EndpointPrivateAccess: aws.ToBool(input.ResourcesVpcConfig.EndpointPrivateAccess),
+			EndpointPublicAccess:  aws.ToBool(input.ResourcesVpcConfig.EndpointPublicAccess),
+			PublicAccessCidrs:     input.ResourcesVpcConfig.PublicAccessCidrs,
+			SecurityGroupIds:      input.ResourcesVpcConfig.SecurityGroupIds,
+			SubnetIds:             input.ResourcesVpcConfig.SubnetIds,
+		},
+	}
+	for key, value := range input.Tags {
+		cluster.Tags[key] = value
+	}
+	f.clusters[name] = cluster
+	return &awssdkeks.CreateClusterOutput{Cluster: cluster}, nil
+}
+
+func (f *fakeEKSService) DescribeCluster(_ context.Context, input *awssdkeks.DescribeClusterInput) (*awssdkeks.DescribeClusterOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("DescribeCluster")
+	name := aws.ToString(input.Name)
+	cluster, ok := f.clusters[name]
+	if !ok {
+		return nil, &ekstypes.ResourceNotFoundException{Message: aws.String(fmt.Sprintf("no cluster found for name: %s", name))}
+	}
+
+	described := *cluster
+	switch cluster.Status {
+	case ekstypes.ClusterStatusCreating:
+		cluster.Status = ekstypes.ClusterStatusActive
+	case ekstypes.ClusterStatusDeleting:
+		delete(f.clusters, name)
+	}
+	return &awssdkeks.DescribeClusterOutput{Cluster: &described}, nil
+}
+
+func (f *fakeEKSService) DeleteCluster(_ context.Context, input *awssdkeks.DeleteClusterInput) (*awssdkeks.DeleteClusterOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("DeleteCluster")
+	cluster, ok := f.clusters[aws.ToString(input.Name)]
+	if !ok {
+		return nil, &ekstypes.ResourceNotFoundException{}
+	}
+	cluster.Status = ekstypes.ClusterStatusDeleting
+	return &awssdkeks.DeleteClusterOutput{Cluster: cluster}, nil
+}
+
+func (f *fakeEKSService) TagResource(_ context.Context, input *awssdkeks.TagResourceInput) (*awssdkeks.TagResourceOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("TagResource")
+	for _, cluster := range f.clusters {
+		if aws.ToString(cluster.Arn) == aws.ToString(input.ResourceArn) {
+			for key, value := range input.Tags {
+				cluster.Tags[key] = value
+			}
+			return &awssdkeks.TagResourceOutput{}, nil
+		}
+	}
+	return nil, &ekstypes.ResourceNotFoundException{}
+}
+
+func (f *fakeEKSService) UntagResource(_ context.Context, input *awssdkeks.UntagResourceInput) (*awssdkeks.UntagResourceOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("UntagResource")
+	for _, cluster := range f.clusters {
+		if aws.ToString(cluster.Arn) == aws.ToString(input.ResourceArn) {
+			for _, key := range input.TagKeys {
+				delete(cluster.Tags, key)
+			}
+			return &awssdkeks.UntagResourceOutput{}, nil
+		}
+	}
+	return nil, &ekstypes.ResourceNotFoundException{}
+}
+
+func (f *fakeEKSService) ListNodegroups(_ context.Context, _ *awssdkeks.ListNodegroupsInput) (*awssdkeks.ListNodegroupsOutput, error) {
+	return &awssdkeks.ListNodegroupsOutput{}, nil
+}
+
+func (f *fakeEKSService) DescribeAddon(_ context.Context, input *awssdkeks.DescribeAddonInput) (*awssdkeks.DescribeAddonOutput, error) {
+	return nil, &ekstypes.ResourceNotFoundException{Message: aws.String(fmt.Sprintf("no addon found for name: %s", aws.ToString(input.AddonName)))}
+}
+
+// newFakeAWSServices returns AWS services that keep EKS clusters in the given fake and answer the read calls the
+// handler makes for clusters without node groups or add-ons.
+func newFakeAWSServices(ctrl *gomock.Controller, eksFake *fakeEKSService) *awsServices {
+	ec2Mock := mock_services.NewMockEC2ServiceInterface(ctrl)
+	ec2Mock.EXPECT().DescribeSubnets(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, input *ec2.DescribeSubnetsInput) (*ec2.DescribeSubnetsOutput, error) {
+			output := &ec2.DescribeSubnetsOutput{}
+			for _, id := range input.SubnetIds {
+				output.Subnets = append(output.Subnets, ec2types.Subnet{
+					SubnetId: aws.String(id),
+					VpcId:    aws.String("vpc-1"),
+					OwnerId:  aws.String(fakeAccountID),
+				})
+			}
+			return output, nil
+		}).AnyTimes()
+	ec2Mock.EXPECT().CreateTags(gomock.Any(), gomock.Any()).Return(&ec2.CreateTagsOutput{}, nil).AnyTimes()
+
+	stsMock := mock_services.NewMockSTSServiceInterface(ctrl)
+	stsMock.EXPECT().GetCallerIdentity(gomock.Any(), gomock.Any()).Return(
+		&sts.GetCallerIdentityOutput{Account: aws.String(fakeAccountID)}, nil).AnyTimes()
+
+	iamMock := mock_services.NewMockIAMServiceInterface(ctrl)
+	iamMock.EXPECT().GetRole(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, input *iam.GetRoleInput) (*iam.GetRoleOutput, error) {
+			return &iam.GetRoleOutput{Role: &iamtypes.Role{
+				RoleName: input.RoleName,
+				Arn:      aws.String("arn:aws:iam::" + fakeAccountID + ":role/" + aws.ToString(input.RoleName)),
+			}}, nil
+		}).AnyTimes()
+
+	return &awsServices{
+		eks:            eksFake,
+		cloudformation: mock_services.NewMockCloudFormationServiceInterface(ctrl),
+		ec2:            ec2Mock,
+		iam:            iamMock,
+		sts:            stsMock,
+		pricing:        mock_services.NewMockPricingServiceInterface(ctrl),
+		logs:           mock_services.NewMockCloudWatchLogsServiceInterface(ctrl),
+	}
+}
+
+// enqueueRecorder records the enqueues of a handler instead of sending them to a controller.
+type enqueueRecorder struct {
+	mu       sync.Mutex
+	enqueues []string
+}
+
+func (r *enqueueRecorder) enqueue(namespace, name string) {
+	r.enqueueAfter(namespace, name, 0)
+}
+
+func (r *enqueueRecorder) enqueueAfter(namespace, name string, duration time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.enqueues = append(r.enqueues, fmt.Sprintf("%s/%s after %s", namespace, name, duration))
+}
+
+func (r *enqueueRecorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.enqueues)
+}
+
+// newLifecycleHandler returns a handler that writes to the envtest API server and makes its AWS calls to the given
+// fakes.
+func newLifecycleHandler(awsSVCs *awsServices, enqueues *enqueueRecorder) *Handler {
+	return &Handler{
+		eksCC:           eksFactory.Eks().V1().EKSClusterConfig(),
+		eksEnqueue:      enqueues.enqueue,
+		eksEnqueueAfter: enqueues.enqueueAfter,
+		secrets:         coreFactory.Core().V1().Secret(),
+		secretsCache:    coreFactory.Core().V1().Secret().Cache(),
+		configMaps:      coreFactory.Core().V1().ConfigMap(),
+		dynamic:         dynamic.NewForConfigOrDie(cfg),
+		deletionSlots:   newDeletionSlots(0),
+		newAWSServices: func(_ context.Context, _ eksv1.EKSClusterConfigSpec) (*awsServices, error) {
+			return awsSVCs, nil
+		},
+	}
+}
+
+var _ = Describe("cluster lifecycle", func() {
+	var (
+		eksConfig *eksv1.EKSClusterConfig
+		eksFake   *fakeEKSService
+		awsSVCs   *awsServices
+		enqueues  *enqueueRecorder
+		handler   *Handler
+	)
+
+	// reconcile runs the handler on the current config until it stops writing the status, like the controller does
+	// as it is triggered by its own status writes, and returns the config it settled on. Nil is returned once the
+	// config is gone.
+	reconcile := func() *eksv1.EKSClusterConfig {
+		current, err := handler.eksCC.Get(eksConfig.Namespace, eksConfig.Name, metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		for range 10 {
+			updated, err := handler.recordError(handler.OnEksConfigChanged)(current.Namespace+"/"+current.Name, current)
+			Expect(err).NotTo(HaveOccurred())
+			if updated == nil || updated.ResourceVersion == current.ResourceVersion {
+				return updated
+			}
+			current, err = handler.eksCC.Get(eksConfig.Namespace, eksConfig.Name, metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Fail("the handler kept writing the status of the config")
+		return nil
+	}
+
+	BeforeEach(func() {
+		// the downstream API server isn't faked
+		Expect(features.Set("DownstreamProbe=false")).To(Succeed())
+		DeferCleanup(features.Set, "")
+
+		ctrl := gomock.NewController(GinkgoT())
+		eksFake = newFakeEKSService(ctrl)
+		enqueues = &enqueueRecorder{}
+		awsSVCs = newFakeAWSServices(ctrl, eksFake)
+		handler = newLifecycleHandler(awsSVCs, enqueues)
+
+		eksConfig = &eksv1.EKSClusterConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "lifecycle",
+				Namespace: "default",
+			},
+			Spec: eksv1.EKSClusterConfigSpec{
+				DisplayName:         "lifecycle",
+				Region:              "us-west-2",
+				KubernetesVersion:   aws.String("1.30"),
+				PrivateAccess:       aws.Bool(false),
+				PublicAccess:        aws.Bool(true),
+				PublicAccessSources: []string{"0.0.0.0/0"},
+				SecretsEncryption:   aws.Bool(false),
+				EBSCSIDriver:        aws.Bool(false),
+				ServiceRole:         aws.String("lifecycle-role"),
+				Subnets:             []string{"subnet-1", "subnet-2"},
+				SecurityGroups:      []string{},
+				LoggingTypes:        []string{},
+				Tags:                map[string]string{"team": "a"},
+				NodeGroups:          []eksv1.NodeGroup{},
+			},
+		}
+		Expect(cl.Create(ctx, eksConfig)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		caSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: eksConfig.Name, Namespace: eksConfig.Namespace}}
+		Expect(test.CleanupAndWait(ctx, cl, eksConfig, caSecret)).To(Succeed())
+	})
+
+	It("should create, update and delete a cluster", func() {
+		By("creating the cluster")
+		config := reconcile()
+		Expect(config.Status.Phase).To(Equal(eksv1.PhaseCreating))
+		Expect(eksFake.clusterCalls()).To(Equal([]string{"ListClusters", "CreateCluster", "DescribeCluster"}))
+		Expect(enqueues.count()).To(Equal(1), "the creation is waited for")
+
+		By("waiting for the cluster to become active")
+		config = reconcile()
+		Expect(config.Status.Phase).To(Equal(eksv1.PhaseActive))
+		Expect(config.Status.ClusterARN).To(Equal("arn:aws:eks:us-west-2:" + fakeAccountID + ":cluster/lifecycle"))
+		caSecret, err := handler.secrets.Get(config.Namespace, config.Name, metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(caSecret.Data).To(HaveKeyWithValue("endpoint", []byte("https://lifecycle.eks.amazonaws.com")))
+
+		By("reconciling the active cluster without changes")
+		calls := len(eksFake.clusterCalls())
+		config = reconcile()
+		Expect(config.Status.Phase).To(Equal(eksv1.PhaseActive))
+		Expect(eksFake.clusterCalls()[calls:]).To(HaveEach("DescribeCluster"), "nothing is updated")
+
+		By("updating the tags of the cluster")
+		config.Spec.Tags = map[string]string{"team": "b"}
+		_, err = handler.eksCC.Update(config)
+		Expect(err).NotTo(HaveOccurred())
+		config = reconcile()
+		Expect(eksFake.clusterCalls()).To(ContainElement("TagResource"))
+		Expect(eksFake.clusters["lifecycle"].Tags).To(HaveKeyWithValue("team", "b"))
+		Expect(config.Status.Phase).To(Equal(eksv1.PhaseActive))
+		Expect(config.Status.FailureMessage).To(BeEmpty())
+
+		By("deleting the cluster")
+		awsSVCs.cloudformation.(*mock_services.MockCloudFormationServiceInterface).EXPECT().DeleteStack(gomock.Any(),
+			&cloudformation.DeleteStackInput{StackName: aws.String(getNodeInstanceRoleStackName("lifecycle"))}).
+			Return(&cloudformation.DeleteStackOutput{}, nil)
+		enqueuesBeforeDeletion := enqueues.count()
+		Expect(cl.Delete(ctx, config)).To(Succeed())
+		config = reconcile()
+		Expect(config.Status.DeletionStage).To(Equal(deletionStageCluster))
+		Expect(eksFake.clusterCalls()).To(ContainElement("DeleteCluster"))
+		Expect(enqueues.count()).To(BeNumerically(">", enqueuesBeforeDeletion), "the deletion of the control plane is waited for")
+
+		Expect(reconcile()).To(BeNil())
+		Expect(eksFake.clusters).To(BeEmpty())
+		_, err = handler.eksCC.Get(eksConfig.Namespace, eksConfig.Name, metav1.GetOptions{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+})
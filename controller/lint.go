@@ -0,0 +1,18 @@
+package controller
+
+import (
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+)
+
+// Lint checks a config the way the controller does before creating or importing the cluster, or before updating it if
+// update is set, after setting the node group defaults of the operator. Checks that need AWS or the management cluster,
+// like name conflicts, are skipped, so that configs can be checked offline, e.g. by ekscc-lint in CI pipelines before
+// they are applied.
+func Lint(config *eksv1.EKSClusterConfig, defaults NodeGroupDefaults, update bool) error {
+	config = config.DeepCopy()
+	setNodeGroupDefaults(config, defaults, nil)
+	if update {
+		return validateUpdate(config)
+	}
+	return validateCreateSpec(config)
+}
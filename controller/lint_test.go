@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLint(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{
+		DisplayName:         "test",
+		KubernetesVersion:   aws.String("1.30"),
+		PrivateAccess:       aws.Bool(false),
+		PublicAccess:        aws.Bool(true),
+		SecretsEncryption:   aws.Bool(false),
+		Tags:                map[string]string{},
+		Subnets:             []string{},
+		SecurityGroups:      []string{},
+		LoggingTypes:        []string{},
+		PublicAccessSources: []string{},
+		NodeGroups: []eksv1.NodeGroup{{
+			NodegroupName:        aws.String("ng"),
+			Version:              aws.String("1.30"),
+			Ec2SshKey:            aws.String(""),
+			ResourceTags:         map[string]string{},
+			MinSize:              aws.Int32(1),
+			MaxSize:              aws.Int32(2),
+			DesiredSize:          aws.Int32(1),
+			Gpu:                  aws.Bool(false),
+			Subnets:              []string{},
+			Tags:                 map[string]*string{},
+			Labels:               map[string]*string{},
+			RequestSpotInstances: aws.Bool(false),
+		}},
+	}}
+
+	// the node group has neither an instance type nor a disk size until the defaults are set
+	assert.Error(t, Lint(config, NodeGroupDefaults{}, false))
+	assert.NoError(t, Lint(config, NodeGroupDefaults{InstanceType: "t3.large", DiskSize: 20}, false))
+	assert.Nil(t, config.Spec.NodeGroups[0].DiskSize, "config must not be modified")
+	assert.NoError(t, Lint(config, NodeGroupDefaults{}, true))
+
+	config.Spec.NodeGroups[0].Version = aws.String("1.26")
+	assert.Error(t, Lint(config, NodeGroupDefaults{InstanceType: "t3.large", DiskSize: 20}, false))
+	assert.Error(t, Lint(config, NodeGroupDefaults{}, true))
+
+	// imported clusters don't need a version
+	assert.NoError(t, Lint(&eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{
+		DisplayName: "imported",
+		Imported:    true,
+		NodeGroups:  []eksv1.NodeGroup{{NodegroupName: aws.String("ng")}},
+	}}, NodeGroupDefaults{}, false))
+}
@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	corev1 "k8s.io/api/core/v1"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/utils"
+)
+
+// parseMaintenanceWindow parses the schedule, duration and time zone of a maintenance window.
+func parseMaintenanceWindow(window *eksv1.MaintenanceWindow) (*utils.Schedule, time.Duration, *time.Location, error) {
+	schedule, err := utils.ParseSchedule(window.Schedule)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	duration, err := time.ParseDuration(window.Duration)
+	if err != nil || duration <= 0 {
+		return nil, 0, nil, fmt.Errorf("invalid duration [%s], must be a positive duration like 6h", window.Duration)
+	}
+	location, err := time.LoadLocation(window.TimeZone)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("invalid time zone [%s]: %w", window.TimeZone, err)
+	}
+	return schedule, duration, location, nil
+}
+
+// maintenanceWindowOpen returns whether disruptive updates can be made at now and, if they can't, how long it is until
+// the maintenance window of the config opens next. Clusters without a maintenance window are always open, as are
+// windows that can't be parsed, the validation rejects them.
+func maintenanceWindowOpen(config *eksv1.EKSClusterConfig, now time.Time) (bool, time.Duration) {
+	if config.Spec.MaintenanceWindow == nil {
+		return true, 0
+	}
+	schedule, duration, location, err := parseMaintenanceWindow(config.Spec.MaintenanceWindow)
+	if err != nil {
+		return true, 0
+	}
+
+	if start, ok := schedule.Prev(now.In(location)); ok && now.Before(start.Add(duration)) {
+		return true, 0
+	}
+	next, ok := schedule.Next(now.In(location))
+	if !ok {
+		return false, 0
+	}
+	return false, next.Sub(now)
+}
+
+// nodeGroupRolloutPending returns whether updating the node group to the spec replaces its nodes, which is the case
+// for version and AMI release version updates and new launch template versions.
+func nodeGroupRolloutPending(config *eksv1.EKSClusterConfig, upstreamNg, ng eksv1.NodeGroup, desiredVersion string) bool {
+	if upstreamNg.LaunchTemplate == nil {
+		return false
+	}
+	if ng.LaunchTemplate == nil && config.Status.ManagedLaunchTemplateID == aws.ToString(upstreamNg.LaunchTemplate.ID) {
+		return launchTemplateVersionNeeded(config, upstreamNg, ng) ||
+			ng.Version != nil && aws.ToString(upstreamNg.Version) != desiredVersion ||
+			releaseVersionUpdate(config, ng) != nil
+	}
+	return ng.LaunchTemplate != nil && ng.LaunchTemplate.Version != nil &&
+		aws.ToInt64(ng.LaunchTemplate.Version) != aws.ToInt64(upstreamNg.LaunchTemplate.Version)
+}
+
+// setUpdatesDeferredStatus sets the UpdatesDeferred condition from the disruptive updates waiting for the maintenance
+// window and returns whether it changed. A normal event is recorded when updates start being deferred. The condition
+// is only added to clusters that had deferred updates.
+func (h *Handler) setUpdatesDeferredStatus(config *eksv1.EKSClusterConfig, deferred []string) bool {
+	if len(deferred) == 0 {
+		if !updatesDeferred.IsTrue(config) {
+			return false
+		}
+		updatesDeferred.SetStatus(config, string(corev1.ConditionFalse))
+		updatesDeferred.Message(config, "")
+		return true
+	}
+
+	message := fmt.Sprintf("waiting for the maintenance window to %s", strings.Join(deferred, ", "))
+	if updatesDeferred.IsTrue(config) && updatesDeferred.GetMessage(config) == message {
+		return false
+	}
+	h.recordEvent(config, corev1.EventTypeNormal, eventReasonUpdatesDeferred, "Updates of cluster [%s] are deferred: %s",
+		config.Spec.DisplayName, message)
+	updatesDeferred.SetStatus(config, string(corev1.ConditionTrue))
+	updatesDeferred.Message(config, message)
+	return true
+}
+
+// validateMaintenanceWindow checks that the maintenance window of the config has a valid schedule, duration and time
+// zone.
+func validateMaintenanceWindow(config *eksv1.EKSClusterConfig) error {
+	if config.Spec.MaintenanceWindow == nil {
+		return nil
+	}
+	if _, _, _, err := parseMaintenanceWindow(config.Spec.MaintenanceWindow); err != nil {
+		return fmt.Errorf("maintenance window of cluster [%s (id: %s)]: %w", config.Spec.DisplayName, config.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+)
+
+func TestMaintenanceWindow(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{}
+	// a Wednesday
+	now := time.Date(2024, time.May, 15, 12, 30, 0, 0, time.UTC)
+	open, _ := maintenanceWindowOpen(config, now)
+	assert.True(t, open, "clusters without a maintenance window are always open")
+
+	// Saturdays from 02:00 to 08:00 in Berlin, 00:00 to 06:00 UTC
+	config.Spec.MaintenanceWindow = &eksv1.MaintenanceWindow{Schedule: "0 2 * * 6", Duration: "6h", TimeZone: "Europe/Berlin"}
+	assert.NoError(t, validateMaintenanceWindow(config))
+	open, untilOpen := maintenanceWindowOpen(config, now)
+	assert.False(t, open)
+	assert.Equal(t, 2*24*time.Hour+11*time.Hour+30*time.Minute, untilOpen)
+
+	open, _ = maintenanceWindowOpen(config, time.Date(2024, time.May, 18, 5, 59, 0, 0, time.UTC))
+	assert.True(t, open)
+	open, untilOpen = maintenanceWindowOpen(config, time.Date(2024, time.May, 18, 6, 0, 0, 0, time.UTC))
+	assert.False(t, open)
+	assert.Equal(t, 7*24*time.Hour-6*time.Hour, untilOpen)
+
+	for _, window := range []eksv1.MaintenanceWindow{
+		{Schedule: "0 2 * *", Duration: "6h"},
+		{Schedule: "0 2 * * 6", Duration: "six hours"},
+		{Schedule: "0 2 * * 6", Duration: "-1h"},
+		{Schedule: "0 2 * * 6", Duration: "6h", TimeZone: "Mars/Olympus"},
+	} {
+		config.Spec.MaintenanceWindow = &window
+		assert.Error(t, validateMaintenanceWindow(config), window)
+	}
+}
+
+func TestNodeGroupRolloutPending(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{Status: eksv1.EKSClusterConfigStatus{
+		ManagedLaunchTemplateID: "lt-managed",
+		NodeGroupUserDataHashes: map[string]string{},
+	}}
+	upstreamNg := eksv1.NodeGroup{
+		NodegroupName:  aws.String("ng"),
+		Version:        aws.String("1.29"),
+		InstanceType:   "t3.large",
+		LaunchTemplate: &eksv1.LaunchTemplate{ID: aws.String("lt-managed"), Version: aws.Int64(1)},
+	}
+
+	ng := upstreamNg
+	ng.LaunchTemplate = nil
+	assert.False(t, nodeGroupRolloutPending(config, upstreamNg, ng, "1.29"))
+	assert.True(t, nodeGroupRolloutPending(config, upstreamNg, ng, "1.30"))
+	config.Status.NodeGroupStatuses = map[string]eksv1.NodeGroupStatus{"ng": {ReleaseVersion: "1.29.3-20240625"}}
+	ng.ReleaseVersion = aws.String("1.29.5-20240705")
+	assert.True(t, nodeGroupRolloutPending(config, upstreamNg, ng, "1.29"))
+	ng.ReleaseVersion = nil
+	ng.InstanceType = "t3.xlarge"
+	assert.True(t, nodeGroupRolloutPending(config, upstreamNg, ng, "1.29"))
+
+	// custom launch templates are only rolled out with new versions of them
+	upstreamNg.LaunchTemplate = &eksv1.LaunchTemplate{ID: aws.String("lt-custom"), Version: aws.Int64(1)}
+	ng = upstreamNg
+	ng.LaunchTemplate = &eksv1.LaunchTemplate{ID: aws.String("lt-custom"), Version: aws.Int64(1)}
+	assert.False(t, nodeGroupRolloutPending(config, upstreamNg, ng, "1.30"))
+	ng.LaunchTemplate.Version = aws.Int64(2)
+	assert.True(t, nodeGroupRolloutPending(config, upstreamNg, ng, "1.29"))
+}
+
+func TestUpdatesDeferredStatus(t *testing.T) {
+	h := &Handler{}
+	config := &eksv1.EKSClusterConfig{}
+	assert.False(t, h.setUpdatesDeferredStatus(config, nil))
+	assert.Empty(t, config.Status.Conditions, "the condition is only added once updates are deferred")
+
+	assert.True(t, h.setUpdatesDeferredStatus(config, []string{"roll out node group [ng]"}))
+	assert.True(t, updatesDeferred.IsTrue(config))
+	assert.Equal(t, "waiting for the maintenance window to roll out node group [ng]", updatesDeferred.GetMessage(config))
+	assert.False(t, h.setUpdatesDeferredStatus(config, []string{"roll out node group [ng]"}))
+
+	assert.True(t, h.setUpdatesDeferredStatus(config, nil))
+	assert.True(t, updatesDeferred.IsFalse(config))
+	assert.Empty(t, updatesDeferred.GetMessage(config))
+	assert.False(t, h.setUpdatesDeferredStatus(config, nil))
+}
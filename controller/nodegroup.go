@@ -2,27 +2,148 @@ package controller
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/aws/aws-sdk-go-v2/service/eks"
 	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/blang/semver"
 	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
 	awsservices "github.com/rancher/eks-operator/pkg/eks"
 	"github.com/rancher/eks-operator/pkg/eks/services"
+	"github.com/rancher/eks-operator/pkg/features"
 	"github.com/rancher/eks-operator/utils"
 	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
 )
 
+// recreatePolicyReplace recreates node groups whose changes EKS can't update
+const recreatePolicyReplace = "Replace"
+
+// maxNodegroupVersionSkew is the number of minor versions the upstream node groups may be older than the control plane
+// it is upgraded to. It is stricter than the skew allowed between the versions in the spec, so that the kubelets of the
+// live nodes keep working with the upgraded API server.
+const maxNodegroupVersionSkew = 2
+
+// nodeGroupSelected returns whether the operator manages the node group with the given name and kubernetes labels,
+// which it does for all node groups unless the cluster is imported with an import node group selector.
+func nodeGroupSelected(config *eksv1.EKSClusterConfig, name string, labels map[string]string) bool {
+	selector := config.Spec.ImportNodeGroupSelector
+	if !config.Spec.Imported || selector == nil {
+		return true
+	}
+	for key, value := range selector.MatchLabels {
+		if labelValue, ok := labels[key]; !ok || labelValue != value {
+			return false
+		}
+	}
+	if len(selector.Names) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(selector.Names, func(pattern string) bool {
+		matched, _ := path.Match(pattern, name)
+		return matched
+	})
+}
+
+// selectedNodeGroups returns the node groups of the spec the operator manages, see nodeGroupSelected. Node groups of the
+// spec that aren't selected, e.g. ones Rancher syncs into it from the exported upstream spec, are ignored like the
+// unselected node groups upstream.
+func selectedNodeGroups(config *eksv1.EKSClusterConfig) []eksv1.NodeGroup {
+	if !config.Spec.Imported || config.Spec.ImportNodeGroupSelector == nil || config.Spec.NodeGroups == nil {
+		return config.Spec.NodeGroups
+	}
+	selected := make([]eksv1.NodeGroup, 0, len(config.Spec.NodeGroups))
+	for _, ng := range config.Spec.NodeGroups {
+		if nodeGroupSelected(config, aws.ToString(ng.NodegroupName), aws.ToStringMap(ng.Labels)) {
+			selected = append(selected, ng)
+		}
+	}
+	return selected
+}
+
+// validateImportNodeGroupSelector checks that the import node group selector is only set for imported clusters and that
+// its name patterns are valid.
+func validateImportNodeGroupSelector(config *eksv1.EKSClusterConfig) error {
+	selector := config.Spec.ImportNodeGroupSelector
+	if selector == nil {
+		return nil
+	}
+	if !config.Spec.Imported {
+		return fmt.Errorf("import node group selector of cluster [%s (id: %s)] can only be set for imported clusters",
+			config.Spec.DisplayName, config.Name)
+	}
+	for _, pattern := range selector.Names {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid node group name pattern [%s] in the import node group selector of cluster [%s (id: %s)]",
+				pattern, config.Spec.DisplayName, config.Name)
+		}
+	}
+	return nil
+}
+
+// getNodegroupsOutsideVersionSkew returns the upstream node groups that would be more than maxNodegroupVersionSkew
+// minor versions older than the control plane once it is upgraded to the given version. Node groups whose version isn't
+// known, such as those being upgraded, are not included.
+func getNodegroupsOutsideVersionSkew(clusterVersion semver.Version, upstreamNodeGroups []eksv1.NodeGroup) []string {
+	var outside []string
+	for _, ng := range upstreamNodeGroups {
+		if ng.Version == nil {
+			continue
+		}
+		version, err := semver.ParseTolerant(aws.ToString(ng.Version))
+		if err != nil {
+			continue
+		}
+		if version.Major == clusterVersion.Major && clusterVersion.Minor > version.Minor+maxNodegroupVersionSkew {
+			outside = append(outside, fmt.Sprintf("%s (%s)", aws.ToString(ng.NodegroupName), aws.ToString(ng.Version)))
+		}
+	}
+	return outside
+}
+
+// setNodeGroupVersionSkewStatus sets the NodeGroupVersionSkew condition from the node groups that block the upgrade of
+// the control plane to the given version and returns whether it changed. A warning event is recorded when the upgrade
+// becomes blocked or is blocked by other node groups, not on every reconcile. The condition is only added to clusters
+// whose upgrade was blocked.
+func (h *Handler) setNodeGroupVersionSkewStatus(config *eksv1.EKSClusterConfig, version string, outsideSkew []string) bool {
+	if len(outsideSkew) == 0 {
+		if !nodeGroupVersionSkew.IsTrue(config) {
+			return false
+		}
+		nodeGroupVersionSkew.SetStatus(config, string(corev1.ConditionFalse))
+		nodeGroupVersionSkew.Message(config, "")
+		return true
+	}
+
+	message := fmt.Sprintf("not upgrading to kubernetes version %s, node groups %v would be more than %d minor versions older",
+		version, outsideSkew, maxNodegroupVersionSkew)
+	if nodeGroupVersionSkew.IsTrue(config) && nodeGroupVersionSkew.GetMessage(config) == message {
+		return false
+	}
+	logrus.Warnf("Not upgrading cluster [%s (id: %s)] to kubernetes version %s, node groups %v would be more than %d minor versions older",
+		config.Spec.DisplayName, config.Name, version, outsideSkew, maxNodegroupVersionSkew)
+	h.recordEvent(config, corev1.EventTypeWarning, eventReasonUpgradeBlocked,
+		"Not upgrading cluster [%s] to kubernetes version %s, node groups %v must be upgraded first",
+		config.Spec.DisplayName, version, outsideSkew)
+	nodeGroupVersionSkew.SetStatus(config, string(corev1.ConditionTrue))
+	nodeGroupVersionSkew.Message(config, message)
+	return true
+}
+
 func newLaunchTemplateVersionIfNeeded(ctx context.Context, config *eksv1.EKSClusterConfig, upstreamNg, ng eksv1.NodeGroup, ec2Service services.EC2ServiceInterface) (*eksv1.LaunchTemplate, error) {
-	if aws.ToString(upstreamNg.UserData) != aws.ToString(ng.UserData) ||
-		aws.ToString(upstreamNg.Ec2SshKey) != aws.ToString(ng.Ec2SshKey) ||
-		aws.ToInt32(upstreamNg.DiskSize) != aws.ToInt32(ng.DiskSize) ||
-		aws.ToString(upstreamNg.ImageID) != aws.ToString(ng.ImageID) ||
-		(!aws.ToBool(upstreamNg.RequestSpotInstances) && upstreamNg.InstanceType != ng.InstanceType) ||
-		!utils.CompareStringMaps(upstreamNg.ResourceTags, ng.ResourceTags) {
-		lt, err := awsservices.CreateNewLaunchTemplateVersion(ctx, ec2Service, config.Status.ManagedLaunchTemplateID, ng)
+	if launchTemplateVersionNeeded(config, upstreamNg, ng) {
+		lt, err := awsservices.CreateManagedLaunchTemplateVersion(ctx, ec2Service, config, ng)
 		if err != nil {
 			return nil, err
 		}
@@ -33,7 +154,419 @@ func newLaunchTemplateVersionIfNeeded(ctx context.Context, config *eksv1.EKSClus
 	return nil, nil
 }
 
-func deleteLaunchTemplate(ctx context.Context, templateID string, ec2Service services.EC2ServiceInterface) {
+// launchTemplateVersionNeeded returns whether the node group differs from the upstream node group in settings of the
+// rancher-managed launch template, so that a new version of it is needed.
+func launchTemplateVersionNeeded(config *eksv1.EKSClusterConfig, upstreamNg, ng eksv1.NodeGroup) bool {
+	// upstream userdata is compared by its hash recorded on the status, so that it never has to be kept around
+	upstreamUserDataHash := config.Status.NodeGroupUserDataHashes[aws.ToString(ng.NodegroupName)]
+	userDataChanged := upstreamUserDataHash != utils.HashUserData(ng.UserData)
+	if userDataChanged {
+		logrus.Debugf("[userdata] node group [%s] config: %s, upstream: %s", aws.ToString(ng.NodegroupName),
+			utils.RedactUserData(ng.UserData), utils.RedactUserDataHash(upstreamUserDataHash))
+	}
+
+	return userDataChanged ||
+		aws.ToString(upstreamNg.Ec2SshKey) != aws.ToString(ng.Ec2SshKey) ||
+		aws.ToInt32(upstreamNg.DiskSize) != aws.ToInt32(ng.DiskSize) ||
+		!compareVolumeOptions(upstreamNg, ng) ||
+		aws.ToString(upstreamNg.ImageID) != aws.ToString(ng.ImageID) ||
+		(!aws.ToBool(upstreamNg.RequestSpotInstances) && upstreamNg.InstanceType != ng.InstanceType) ||
+		!utils.CompareStringMaps(upstreamNg.ResourceTags, ng.ResourceTags) ||
+		!compareMetadataOptions(upstreamNg.MetadataOptions, ng.MetadataOptions) ||
+		!comparePlacement(upstreamNg.Placement, ng.Placement) ||
+		!compareBlockDeviceMappings(upstreamNg.BlockDeviceMappings, ng.BlockDeviceMappings) ||
+		!compareNetworkInterfaces(upstreamNg.NetworkInterfaces, ng.NetworkInterfaces, config.Status.ClusterSecurityGroup)
+}
+
+// compareNetworkInterfaces returns true if the given network interfaces are equivalent and in the same order. The
+// cluster security group is added to every interface when the launch template is built, so it's ignored.
+func compareNetworkInterfaces(upstream, desired []eksv1.NetworkInterface, clusterSecurityGroup string) bool {
+	securityGroups := func(networkInterface eksv1.NetworkInterface) []string {
+		return slices.DeleteFunc(slices.Clone(networkInterface.SecurityGroups), func(securityGroup string) bool {
+			return securityGroup == clusterSecurityGroup
+		})
+	}
+	return slices.EqualFunc(upstream, desired, func(upstream, desired eksv1.NetworkInterface) bool {
+		return aws.ToInt32(upstream.NetworkCardIndex) == aws.ToInt32(desired.NetworkCardIndex) &&
+			aws.ToInt32(upstream.DeviceIndex) == aws.ToInt32(desired.DeviceIndex) &&
+			aws.ToBool(upstream.AssociatePublicIPAddress) == aws.ToBool(desired.AssociatePublicIPAddress) &&
+			utils.CompareStringSliceElements(securityGroups(upstream), securityGroups(desired)) &&
+			aws.ToString(upstream.InterfaceType) == aws.ToString(desired.InterfaceType)
+	})
+}
+
+// compareBlockDeviceMappings returns true if the given additional block device mappings are equivalent and in the same
+// order.
+func compareBlockDeviceMappings(upstream, desired []eksv1.BlockDeviceMapping) bool {
+	return slices.EqualFunc(upstream, desired, func(upstream, desired eksv1.BlockDeviceMapping) bool {
+		return aws.ToString(upstream.DeviceName) == aws.ToString(desired.DeviceName) &&
+			aws.ToInt32(upstream.VolumeSize) == aws.ToInt32(desired.VolumeSize) &&
+			aws.ToString(upstream.VolumeType) == aws.ToString(desired.VolumeType) &&
+			aws.ToInt32(upstream.Iops) == aws.ToInt32(desired.Iops) &&
+			aws.ToInt32(upstream.Throughput) == aws.ToInt32(desired.Throughput) &&
+			aws.ToBool(upstream.Encrypted) == aws.ToBool(desired.Encrypted) &&
+			aws.ToString(upstream.KmsKeyID) == aws.ToString(desired.KmsKeyID) &&
+			aws.ToBool(upstream.DeleteOnTermination) == aws.ToBool(desired.DeleteOnTermination)
+	})
+}
+
+// compareMetadataOptions returns true if the given instance metadata options are equivalent. Unset options are the same
+// as empty ones, neither sets any metadata options in the launch template.
+func compareMetadataOptions(upstream, desired *eksv1.MetadataOptions) bool {
+	if upstream == nil {
+		upstream = &eksv1.MetadataOptions{}
+	}
+	if desired == nil {
+		desired = &eksv1.MetadataOptions{}
+	}
+
+	return aws.ToString(upstream.HTTPTokens) == aws.ToString(desired.HTTPTokens) &&
+		aws.ToInt32(upstream.HTTPPutResponseHopLimit) == aws.ToInt32(desired.HTTPPutResponseHopLimit) &&
+		aws.ToString(upstream.InstanceMetadataTags) == aws.ToString(desired.InstanceMetadataTags)
+}
+
+// comparePlacement returns true if the given placements launch nodes in the same place. The strategy and partition count
+// are only used to create the placement group, so they aren't part of the launch template and not compared.
+func comparePlacement(upstream, desired *eksv1.Placement) bool {
+	if upstream == nil {
+		upstream = &eksv1.Placement{}
+	}
+	if desired == nil {
+		desired = &eksv1.Placement{}
+	}
+	tenancy := func(placement *eksv1.Placement) string {
+		if aws.ToString(placement.Tenancy) == "" {
+			return string(ec2types.TenancyDefault)
+		}
+		return aws.ToString(placement.Tenancy)
+	}
+
+	return aws.ToString(upstream.GroupName) == aws.ToString(desired.GroupName) &&
+		aws.ToInt32(upstream.PartitionNumber) == aws.ToInt32(desired.PartitionNumber) &&
+		tenancy(upstream) == tenancy(desired)
+}
+
+// compareVolumeOptions returns true if the EBS volume options other than the disk size of the given node groups are
+// equivalent.
+func compareVolumeOptions(upstreamNg, ng eksv1.NodeGroup) bool {
+	return aws.ToString(upstreamNg.VolumeType) == aws.ToString(ng.VolumeType) &&
+		aws.ToInt32(upstreamNg.Iops) == aws.ToInt32(ng.Iops) &&
+		aws.ToInt32(upstreamNg.Throughput) == aws.ToInt32(ng.Throughput) &&
+		aws.ToBool(upstreamNg.Encrypted) == aws.ToBool(ng.Encrypted) &&
+		aws.ToString(upstreamNg.KmsKeyID) == aws.ToString(ng.KmsKeyID)
+}
+
+// validateVolumeOptions validates the EBS volume options of the given node group.
+func validateVolumeOptions(config *eksv1.EKSClusterConfig, ng eksv1.NodeGroup) error {
+	if ng.VolumeType == nil && ng.Iops == nil && ng.Throughput == nil && ng.Encrypted == nil && ng.KmsKeyID == nil {
+		return nil
+	}
+
+	if ng.LaunchTemplate != nil {
+		return fmt.Errorf("nodegroup [%s] in cluster [%s (id: %s)]: volume options cannot be specified with a custom launch template, set them on the launch template instead",
+			aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name)
+	}
+	if err := validateEBSVolume(ng.VolumeType, ng.Iops, ng.Throughput, ng.Encrypted, ng.KmsKeyID); err != nil {
+		return fmt.Errorf("nodegroup [%s] in cluster [%s (id: %s)]: %w", aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name, err)
+	}
+
+	return nil
+}
+
+// validateBlockDeviceMappings validates the additional block device mappings of the given node group. Whether a
+// mapping replaces the root device of a custom AMI is only known once the AMI is described, so it's checked when the
+// launch template is built.
+func validateBlockDeviceMappings(config *eksv1.EKSClusterConfig, ng eksv1.NodeGroup) error {
+	if len(ng.BlockDeviceMappings) == 0 {
+		return nil
+	}
+
+	if ng.LaunchTemplate != nil {
+		return fmt.Errorf("nodegroup [%s] in cluster [%s (id: %s)]: blockDeviceMappings cannot be specified with a custom launch template, set them on the launch template instead",
+			aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name)
+	}
+	deviceNames := make(map[string]struct{}, len(ng.BlockDeviceMappings))
+	for _, mapping := range ng.BlockDeviceMappings {
+		deviceName := aws.ToString(mapping.DeviceName)
+		if deviceName == "" {
+			return fmt.Errorf("nodegroup [%s] in cluster [%s (id: %s)]: blockDeviceMappings must have a deviceName",
+				aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name)
+		}
+		if _, ok := deviceNames[deviceName]; ok {
+			return fmt.Errorf("nodegroup [%s] in cluster [%s (id: %s)]: block device [%s] is mapped more than once",
+				aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name, deviceName)
+		}
+		deviceNames[deviceName] = struct{}{}
+		if aws.ToInt32(mapping.VolumeSize) <= 0 {
+			return fmt.Errorf("nodegroup [%s] in cluster [%s (id: %s)]: block device [%s] must have a positive volumeSize",
+				aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name, deviceName)
+		}
+		if err := validateEBSVolume(mapping.VolumeType, mapping.Iops, mapping.Throughput, mapping.Encrypted, mapping.KmsKeyID); err != nil {
+			return fmt.Errorf("nodegroup [%s] in cluster [%s (id: %s)]: block device [%s]: %w",
+				aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name, deviceName, err)
+		}
+	}
+
+	return nil
+}
+
+// validateNetworkInterfaces validates the network interfaces of the given node group.
+func validateNetworkInterfaces(config *eksv1.EKSClusterConfig, ng eksv1.NodeGroup) error {
+	if len(ng.NetworkInterfaces) == 0 {
+		return nil
+	}
+
+	if ng.LaunchTemplate != nil {
+		return fmt.Errorf("nodegroup [%s] in cluster [%s (id: %s)]: networkInterfaces cannot be specified with a custom launch template, set them on the launch template instead",
+			aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name)
+	}
+	type position struct{ networkCardIndex, deviceIndex int32 }
+	positions := make(map[position]struct{}, len(ng.NetworkInterfaces))
+	for _, networkInterface := range ng.NetworkInterfaces {
+		p := position{aws.ToInt32(networkInterface.NetworkCardIndex), aws.ToInt32(networkInterface.DeviceIndex)}
+		if p.networkCardIndex < 0 || p.deviceIndex < 0 {
+			return fmt.Errorf("nodegroup [%s] in cluster [%s (id: %s)]: network interface indexes can't be negative",
+				aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name)
+		}
+		if _, ok := positions[p]; ok {
+			return fmt.Errorf("nodegroup [%s] in cluster [%s (id: %s)]: more than one network interface with networkCardIndex %d and deviceIndex %d",
+				aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name, p.networkCardIndex, p.deviceIndex)
+		}
+		positions[p] = struct{}{}
+		if networkInterface.AssociatePublicIPAddress != nil && (p != position{} || len(ng.NetworkInterfaces) > 1) {
+			return fmt.Errorf("nodegroup [%s] in cluster [%s (id: %s)]: associatePublicIpAddress can only be specified for the primary network interface of nodes with a single interface",
+				aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name)
+		}
+		switch interfaceType := aws.ToString(networkInterface.InterfaceType); ec2types.NetworkInterfaceType(interfaceType) {
+		case "", ec2types.NetworkInterfaceTypeInterface, ec2types.NetworkInterfaceTypeEfa, ec2types.NetworkInterfaceTypeEfaOnly:
+		default:
+			return fmt.Errorf("nodegroup [%s] in cluster [%s (id: %s)]: invalid network interface type [%s], must be interface, efa or efa-only",
+				aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name, interfaceType)
+		}
+	}
+	if _, ok := positions[position{}]; !ok {
+		return fmt.Errorf("nodegroup [%s] in cluster [%s (id: %s)]: the primary network interface with networkCardIndex and deviceIndex 0 must be configured",
+			aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name)
+	}
+
+	return nil
+}
+
+// validateEBSVolume validates the type, performance and encryption options of an EBS volume.
+func validateEBSVolume(volumeTypeName *string, iops, throughput *int32, encrypted *bool, kmsKeyID *string) error {
+	volumeType := ec2types.VolumeType(aws.ToString(volumeTypeName))
+	if volumeType != "" && !slices.Contains(volumeType.Values(), volumeType) {
+		return fmt.Errorf("invalid volumeType [%s]", volumeType)
+	}
+	if iops != nil && volumeType != ec2types.VolumeTypeGp3 && volumeType != ec2types.VolumeTypeIo1 && volumeType != ec2types.VolumeTypeIo2 {
+		return fmt.Errorf("iops can only be specified for gp3, io1 and io2 volumes")
+	}
+	if iops == nil && (volumeType == ec2types.VolumeTypeIo1 || volumeType == ec2types.VolumeTypeIo2) {
+		return fmt.Errorf("iops must be specified for %s volumes", volumeType)
+	}
+	if throughput != nil && volumeType != ec2types.VolumeTypeGp3 {
+		return fmt.Errorf("throughput can only be specified for gp3 volumes")
+	}
+	if aws.ToString(kmsKeyID) != "" && !aws.ToBool(encrypted) {
+		return fmt.Errorf("kmsKeyId can only be specified for encrypted volumes")
+	}
+
+	return nil
+}
+
+// validateReleaseVersion validates the AMI release version of the given node group, which must be a release of its
+// kubernetes version.
+func validateReleaseVersion(config *eksv1.EKSClusterConfig, ng eksv1.NodeGroup) error {
+	releaseVersion := aws.ToString(ng.ReleaseVersion)
+	if releaseVersion == "" {
+		return nil
+	}
+
+	if aws.ToString(ng.ImageID) != "" {
+		return fmt.Errorf("nodegroup [%s] in cluster [%s (id: %s)]: releaseVersion cannot be specified with an imageId",
+			aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name)
+	}
+	version := aws.ToString(ng.Version)
+	if version == "" {
+		version = aws.ToString(config.Spec.KubernetesVersion)
+	}
+	if version != "" && !strings.HasPrefix(releaseVersion, version+".") {
+		return fmt.Errorf("nodegroup [%s] in cluster [%s (id: %s)]: releaseVersion [%s] is not a release of kubernetes version %s",
+			aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name, releaseVersion, version)
+	}
+
+	return nil
+}
+
+func validateRecreatePolicy(config *eksv1.EKSClusterConfig, ng eksv1.NodeGroup) error {
+	if ng.RecreatePolicy != "" && ng.RecreatePolicy != recreatePolicyReplace {
+		return fmt.Errorf("nodegroup [%s] in cluster [%s (id: %s)]: recreatePolicy [%s] is invalid, it must be empty or %s",
+			aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name, ng.RecreatePolicy, recreatePolicyReplace)
+	}
+
+	return nil
+}
+
+// releaseVersionUpdate returns the AMI release version the node group has to be updated to, or nil if it isn't pinned
+// or the upstream node group, as recorded on the status, already runs it.
+func releaseVersionUpdate(config *eksv1.EKSClusterConfig, ng eksv1.NodeGroup) *string {
+	if aws.ToString(ng.ReleaseVersion) == "" {
+		return nil
+	}
+	upstream, ok := config.Status.NodeGroupStatuses[aws.ToString(ng.NodegroupName)]
+	if !ok || upstream.ReleaseVersion == aws.ToString(ng.ReleaseVersion) {
+		return nil
+	}
+	return ng.ReleaseVersion
+}
+
+// validateMetadataOptions validates the instance metadata options of the given node group.
+func validateMetadataOptions(config *eksv1.EKSClusterConfig, ng eksv1.NodeGroup) error {
+	metadataOptions := ng.MetadataOptions
+	if metadataOptions == nil {
+		return nil
+	}
+
+	if ng.LaunchTemplate != nil {
+		return fmt.Errorf("nodegroup [%s] in cluster [%s (id: %s)]: metadataOptions cannot be specified with a custom launch template, set them on the launch template instead",
+			aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name)
+	}
+	switch httpTokens := aws.ToString(metadataOptions.HTTPTokens); ec2types.LaunchTemplateHttpTokensState(httpTokens) {
+	case "", ec2types.LaunchTemplateHttpTokensStateOptional, ec2types.LaunchTemplateHttpTokensStateRequired:
+	default:
+		return fmt.Errorf("nodegroup [%s] in cluster [%s (id: %s)]: invalid metadataOptions.httpTokens [%s], must be optional or required",
+			aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name, httpTokens)
+	}
+	if hopLimit := metadataOptions.HTTPPutResponseHopLimit; hopLimit != nil && (*hopLimit < 1 || *hopLimit > 64) {
+		return fmt.Errorf("nodegroup [%s] in cluster [%s (id: %s)]: invalid metadataOptions.httpPutResponseHopLimit [%d], must be between 1 and 64",
+			aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name, *hopLimit)
+	}
+	switch metadataTags := aws.ToString(metadataOptions.InstanceMetadataTags); ec2types.LaunchTemplateInstanceMetadataTagsState(metadataTags) {
+	case "", ec2types.LaunchTemplateInstanceMetadataTagsStateDisabled, ec2types.LaunchTemplateInstanceMetadataTagsStateEnabled:
+	default:
+		return fmt.Errorf("nodegroup [%s] in cluster [%s (id: %s)]: invalid metadataOptions.instanceMetadataTags [%s], must be enabled or disabled",
+			aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name, metadataTags)
+	}
+
+	return nil
+}
+
+// validatePlacement validates the placement group and tenancy of the given node group.
+func validatePlacement(config *eksv1.EKSClusterConfig, ng eksv1.NodeGroup) error {
+	placement := ng.Placement
+	if placement == nil {
+		return nil
+	}
+
+	if ng.LaunchTemplate != nil {
+		return fmt.Errorf("nodegroup [%s] in cluster [%s (id: %s)]: placement cannot be specified with a custom launch template, set it on the launch template instead",
+			aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name)
+	}
+	switch tenancy := aws.ToString(placement.Tenancy); ec2types.Tenancy(tenancy) {
+	case "", ec2types.TenancyDefault, ec2types.TenancyDedicated, ec2types.TenancyHost:
+	default:
+		return fmt.Errorf("nodegroup [%s] in cluster [%s (id: %s)]: invalid placement.tenancy [%s], must be default, dedicated or host",
+			aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name, tenancy)
+	}
+	if aws.ToString(placement.GroupName) == "" {
+		if placement.Strategy != nil || placement.PartitionCount != nil || placement.PartitionNumber != nil {
+			return fmt.Errorf("nodegroup [%s] in cluster [%s (id: %s)]: placement.groupName must be specified along with the strategy and partitions of the placement group",
+				aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name)
+		}
+		return nil
+	}
+	strategy := ec2types.PlacementStrategy(aws.ToString(placement.Strategy))
+	switch strategy {
+	case "", ec2types.PlacementStrategyCluster, ec2types.PlacementStrategySpread, ec2types.PlacementStrategyPartition:
+	default:
+		return fmt.Errorf("nodegroup [%s] in cluster [%s (id: %s)]: invalid placement.strategy [%s], must be cluster, spread or partition",
+			aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name, strategy)
+	}
+	if partitionCount := placement.PartitionCount; partitionCount != nil {
+		if strategy != ec2types.PlacementStrategyPartition {
+			return fmt.Errorf("nodegroup [%s] in cluster [%s (id: %s)]: placement.partitionCount can only be specified for the partition strategy",
+				aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name)
+		}
+		if *partitionCount < 1 || *partitionCount > 7 {
+			return fmt.Errorf("nodegroup [%s] in cluster [%s (id: %s)]: invalid placement.partitionCount [%d], must be between 1 and 7",
+				aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name, *partitionCount)
+		}
+	}
+	if partitionNumber := placement.PartitionNumber; partitionNumber != nil {
+		if strategy != "" && strategy != ec2types.PlacementStrategyPartition {
+			return fmt.Errorf("nodegroup [%s] in cluster [%s (id: %s)]: placement.partitionNumber can only be specified for the partition strategy",
+				aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name)
+		}
+		if *partitionNumber < 1 || (placement.PartitionCount != nil && *partitionNumber > *placement.PartitionCount) {
+			return fmt.Errorf("nodegroup [%s] in cluster [%s (id: %s)]: invalid placement.partitionNumber [%d], must be one of the partitions of the placement group",
+				aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name, *partitionNumber)
+		}
+	}
+
+	return nil
+}
+
+// setCustomLaunchTemplateVersionUpdate sets the launch template version of a user-provided launch template on the
+// given UpdateNodegroupVersionInput if it differs from the upstream one. The kubernetes version and the pinned AMI
+// release version, if any, are only sent along with it if the new launch template version doesn't specify an AMI,
+// because EKS rejects version updates for node groups that use a custom AMI from their launch template.
+func setCustomLaunchTemplateVersionUpdate(ctx context.Context, ngVersionInput *eks.UpdateNodegroupVersionInput, ng, upstreamNg eksv1.NodeGroup, desiredVersion string, releaseVersion *string, ec2Service services.EC2ServiceInterface) error {
+	lt := ng.LaunchTemplate
+	if lt == nil || lt.Version == nil || upstreamNg.LaunchTemplate == nil {
+		return nil
+	}
+	if aws.ToInt64(lt.Version) == aws.ToInt64(upstreamNg.LaunchTemplate.Version) {
+		return nil
+	}
+
+	ngVersionInput.LaunchTemplate = &ekstypes.LaunchTemplateSpecification{
+		Version: aws.String(strconv.FormatInt(*lt.Version, 10)),
+	}
+	if lt.ID != nil {
+		ngVersionInput.LaunchTemplate.Id = lt.ID
+	} else {
+		ngVersionInput.LaunchTemplate.Name = lt.Name
+	}
+
+	versionChanged := desiredVersion != "" && desiredVersion != aws.ToString(upstreamNg.Version)
+	if !versionChanged && releaseVersion == nil {
+		return nil
+	}
+
+	templateID := lt.ID
+	if templateID == nil {
+		templateID = upstreamNg.LaunchTemplate.ID
+	}
+	output, err := awsservices.GetLaunchTemplateVersions(ctx, &awsservices.GetLaunchTemplateVersionsOpts{
+		EC2Service:       ec2Service,
+		LaunchTemplateID: templateID,
+		Versions:         []*string{ngVersionInput.LaunchTemplate.Version},
+	})
+	if err != nil {
+		return fmt.Errorf("error getting launch template version [%s] for node group [%s]: %w",
+			aws.ToString(ngVersionInput.LaunchTemplate.Version), aws.ToString(ng.NodegroupName), err)
+	}
+	if len(output.LaunchTemplateVersions) == 0 {
+		return fmt.Errorf("launch template version [%s] for node group [%s] not found",
+			aws.ToString(ngVersionInput.LaunchTemplate.Version), aws.ToString(ng.NodegroupName))
+	}
+
+	if data := output.LaunchTemplateVersions[0].LaunchTemplateData; data != nil && aws.ToString(data.ImageId) != "" {
+		logrus.Infof("Launch template version [%s] of node group [%s] specifies an AMI, kubernetes and AMI release versions will be taken from it",
+			aws.ToString(ngVersionInput.LaunchTemplate.Version), aws.ToString(ng.NodegroupName))
+		return nil
+	}
+	if versionChanged {
+		ngVersionInput.Version = aws.String(desiredVersion)
+	}
+	ngVersionInput.ReleaseVersion = releaseVersion
+
+	return nil
+}
+
+// deleteLaunchTemplate deletes the launch template, retrying a few times. Failures are only logged, except requests
+// skipped in dry run mode.
+func deleteLaunchTemplate(ctx context.Context, templateID string, ec2Service services.EC2ServiceInterface) error {
 	var err error
 	for i := 0; i < 5; i++ {
 		_, err = ec2Service.DeleteLaunchTemplate(ctx, &ec2.DeleteLaunchTemplateInput{
@@ -41,7 +574,10 @@ func deleteLaunchTemplate(ctx context.Context, templateID string, ec2Service ser
 		})
 
 		if err == nil || doesNotExist(err) {
-			return
+			return nil
+		}
+		if isDryRun(err) {
+			return err
 		}
 
 		time.Sleep(10 * time.Second)
@@ -51,22 +587,70 @@ func deleteLaunchTemplate(ctx context.Context, templateID string, ec2Service ser
 		templateID,
 		err,
 	)
+	return nil
 }
 
-func deleteNodeGroups(ctx context.Context, config *eksv1.EKSClusterConfig, nodeGroups []eksv1.NodeGroup, eksService services.EKSServiceInterface) (bool, error) {
+// maxConcurrentNodegroupOperations is the number of node groups of a cluster that are created or deleted at once
+const maxConcurrentNodegroupOperations = 5
+
+type nodeGroupCreateResult struct {
+	launchTemplateVersion string
+	generatedNodeRole     string
+	err                   error
+}
+
+type nodeGroupDeleteResult struct {
+	templateVersionToDelete *string
+	deleteInProgress        bool
+	err                     error
+}
+
+// forEachNodeGroup calls fn for each of the node groups, running at most maxConcurrentNodegroupOperations calls at
+// once, or one at a time if the ConcurrentNodegroups feature is disabled, and returns the results in the order of the
+// node groups.
+func forEachNodeGroup[T any](nodeGroups []eksv1.NodeGroup, fn func(eksv1.NodeGroup) T) []T {
+	concurrency := maxConcurrentNodegroupOperations
+	if !features.Enabled(features.ConcurrentNodegroups) {
+		concurrency = 1
+	}
+	results := make([]T, len(nodeGroups))
+	workers := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, ng := range nodeGroups {
+		wg.Add(1)
+		workers <- struct{}{}
+		go func(i int, ng eksv1.NodeGroup) {
+			defer wg.Done()
+			defer func() { <-workers }()
+			results[i] = fn(ng)
+		}(i, ng)
+	}
+	wg.Wait()
+	return results
+}
+
+// deleteNodeGroups deletes the given node groups and returns whether any of them are still deleting. Node groups that
+// failed to delete are skipped instead of retried if skipFailed is set.
+func deleteNodeGroups(ctx context.Context, config *eksv1.EKSClusterConfig, nodeGroups []eksv1.NodeGroup, eksService services.EKSServiceInterface, skipFailed bool) (bool, error) {
+	results := forEachNodeGroup(nodeGroups, func(ng eksv1.NodeGroup) nodeGroupDeleteResult {
+		_, deleteInProgress, err := deleteNodeGroup(ctx, config, ng, eksService, skipFailed)
+		return nodeGroupDeleteResult{deleteInProgress: deleteInProgress, err: err}
+	})
+
 	var waitingForNodegroupDeletion bool
-	for _, ng := range nodeGroups {
-		_, deleteInProgress, err := deleteNodeGroup(ctx, config, ng, eksService)
-		if err != nil {
-			return false, err
+	var errs []error
+	for i, result := range results {
+		if result.err != nil {
+			errs = append(errs, fmt.Errorf("error deleting nodegroup [%s]: %w", aws.ToString(nodeGroups[i].NodegroupName), result.err))
+			continue
 		}
-		waitingForNodegroupDeletion = waitingForNodegroupDeletion || deleteInProgress
+		waitingForNodegroupDeletion = waitingForNodegroupDeletion || result.deleteInProgress
 	}
 
-	return waitingForNodegroupDeletion, nil
+	return waitingForNodegroupDeletion, errors.Join(errs...)
 }
 
-func deleteNodeGroup(ctx context.Context, config *eksv1.EKSClusterConfig, ng eksv1.NodeGroup, eksService services.EKSServiceInterface) (*string, bool, error) {
+func deleteNodeGroup(ctx context.Context, config *eksv1.EKSClusterConfig, ng eksv1.NodeGroup, eksService services.EKSServiceInterface, skipFailed bool) (*string, bool, error) {
 	var templateVersionToDelete *string
 	ngState, err := eksService.DescribeNodegroup(ctx,
 		&eks.DescribeNodegroupInput{
@@ -80,12 +664,24 @@ func deleteNodeGroup(ctx context.Context, config *eksv1.EKSClusterConfig, ng eks
 		return templateVersionToDelete, false, err
 	}
 
+	if skipFailed && ngState.Nodegroup.Status == ekstypes.NodegroupStatusDeleteFailed {
+		logrus.Warnf("Node group [%s] of cluster [%s (id: %s)] failed to delete, skipping it",
+			aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name)
+		return templateVersionToDelete, false, nil
+	}
+
 	if ngState.Nodegroup.Status != ekstypes.NodegroupStatusDeleting {
 		_, err = eksService.DeleteNodegroup(ctx,
 			&eks.DeleteNodegroupInput{
 				ClusterName:   aws.String(config.Spec.DisplayName),
 				NodegroupName: ng.NodegroupName,
 			})
+		if isResourceInUse(err) {
+			// the node group started deleting or updating since it was described, so it is waited on
+			logrus.Infof("Node group [%s] of cluster [%s (id: %s)] is in use, waiting to delete it",
+				aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name)
+			return templateVersionToDelete, true, nil
+		}
 		if err != nil {
 			return templateVersionToDelete, false, err
 		}
@@ -99,6 +695,383 @@ func deleteNodeGroup(ctx context.Context, config *eksv1.EKSClusterConfig, ng eks
 	return templateVersionToDelete, true, err
 }
 
+// getDeletionProtectedNodeGroups returns the names of the node groups that are deletion protected and the names of the
+// protected node groups whose deletion is blocked. A node group stays protected after its entry is removed from the spec
+// as long as it still exists upstream, so deletion protection has to be disabled before the entry can be removed.
+func getDeletionProtectedNodeGroups(nodeGroups []eksv1.NodeGroup, previouslyProtected []string, upstreamNgs map[string]eksv1.NodeGroup) ([]string, []string) {
+	var protected, blocked []string
+	inSpec := make(map[string]struct{}, len(nodeGroups))
+	for _, ng := range nodeGroups {
+		inSpec[aws.ToString(ng.NodegroupName)] = struct{}{}
+		if aws.ToBool(ng.DeletionProtection) {
+			protected = append(protected, aws.ToString(ng.NodegroupName))
+		}
+	}
+
+	for _, name := range previouslyProtected {
+		if _, ok := inSpec[name]; ok {
+			continue
+		}
+		if _, ok := upstreamNgs[name]; !ok {
+			continue
+		}
+		protected = append(protected, name)
+		blocked = append(blocked, name)
+	}
+
+	return protected, blocked
+}
+
+// setNodeGroupDeletionProtectionStatus records the protected node groups and the NodeGroupDeletionBlocked condition on
+// the config status and returns whether the status changed.
+func setNodeGroupDeletionProtectionStatus(config *eksv1.EKSClusterConfig, protected, blocked []string) bool {
+	changed := !utils.CompareStringSliceElements(config.Status.DeletionProtectedNodeGroups, protected)
+	config.Status.DeletionProtectedNodeGroups = protected
+
+	status, message := string(corev1.ConditionFalse), ""
+	if len(blocked) != 0 {
+		status = string(corev1.ConditionTrue)
+		message = fmt.Sprintf("deletion of node groups [%s] is blocked by deletion protection, "+
+			"disable deletionProtection on them before removing them from the spec", strings.Join(blocked, ", "))
+	}
+	currentStatus := nodeGroupDeletionBlocked.GetStatus(config)
+	if currentStatus == "" && len(blocked) == 0 {
+		// don't add the condition to clusters that never had a blocked deletion
+		return changed
+	}
+	if currentStatus != status || nodeGroupDeletionBlocked.GetMessage(config) != message {
+		nodeGroupDeletionBlocked.SetStatus(config, status)
+		nodeGroupDeletionBlocked.Message(config, message)
+		changed = true
+	}
+
+	return changed
+}
+
+// getImmutableNodeGroupChanges returns the fields of the node group spec that differ from the upstream node group and
+// that EKS can't update, fields that aren't set in the spec are not compared. Empty subnets count as unset, as node
+// groups are created in the subnets of the cluster then.
+func getImmutableNodeGroupChanges(ng, upstreamNg eksv1.NodeGroup) []string {
+	var changes []string
+	if len(ng.Subnets) != 0 && !utils.CompareStringSliceElements(ng.Subnets, upstreamNg.Subnets) {
+		changes = append(changes, "subnets")
+	}
+	if ng.RequestSpotInstances != nil && aws.ToBool(ng.RequestSpotInstances) != aws.ToBool(upstreamNg.RequestSpotInstances) {
+		changes = append(changes, "requestSpotInstances")
+	} else if aws.ToBool(ng.RequestSpotInstances) && ng.SpotInstanceTypes != nil &&
+		!utils.CompareStringSliceElements(ng.SpotInstanceTypes, upstreamNg.SpotInstanceTypes) {
+		changes = append(changes, "spotInstanceTypes")
+	}
+	if !aws.ToBool(ng.RequestSpotInstances) && upstreamNg.LaunchTemplate == nil && ng.LaunchTemplate == nil &&
+		ng.InstanceType != "" && upstreamNg.InstanceType != "" && ng.InstanceType != upstreamNg.InstanceType {
+		// the instance type of node groups with a launch template is updated with a new launch template version
+		changes = append(changes, "instanceType")
+	}
+	if aws.ToString(ng.NodeRole) != "" && aws.ToString(upstreamNg.NodeRole) != "" && aws.ToString(ng.NodeRole) != aws.ToString(upstreamNg.NodeRole) {
+		changes = append(changes, "nodeRole")
+	}
+
+	return changes
+}
+
+// nodeGroupRecreationPlan lists the node groups in the spec with changes EKS can't update.
+type nodeGroupRecreationPlan struct {
+	// node groups that are deleted to be created again now
+	replace []eksv1.NodeGroup
+	// names of the node groups that are recreated once the maintenance window opens
+	deferred []string
+	// descriptions of the changes that are not applied because the node groups aren't recreated automatically
+	required []string
+}
+
+// planNodeGroupRecreation returns which of the node groups in the spec are recreated to apply changes EKS can't
+// update. Only node groups with the Replace recreate policy and without deletion protection are recreated, during the
+// maintenance window if the cluster has one.
+func planNodeGroupRecreation(nodeGroups []eksv1.NodeGroup, upstreamNgs map[string]eksv1.NodeGroup, maintenanceOpen bool) nodeGroupRecreationPlan {
+	var plan nodeGroupRecreationPlan
+	for _, ng := range nodeGroups {
+		name := aws.ToString(ng.NodegroupName)
+		upstreamNg, ok := upstreamNgs[name]
+		if !ok {
+			continue
+		}
+		changes := getImmutableNodeGroupChanges(ng, upstreamNg)
+		if len(changes) == 0 {
+			continue
+		}
+
+		switch {
+		case ng.RecreatePolicy != recreatePolicyReplace:
+			plan.required = append(plan.required, fmt.Sprintf("%s (%s)", name, strings.Join(changes, ", ")))
+		case aws.ToBool(ng.DeletionProtection):
+			plan.required = append(plan.required, fmt.Sprintf("%s (%s, deletion protected)", name, strings.Join(changes, ", ")))
+		case !maintenanceOpen:
+			plan.deferred = append(plan.deferred, name)
+		default:
+			plan.replace = append(plan.replace, upstreamNg)
+		}
+	}
+
+	return plan
+}
+
+// pruneRecreatingNodeGroups removes the node groups that were removed from the spec while they were recreated from the
+// recreating node groups of the status and returns whether any were removed.
+func pruneRecreatingNodeGroups(config *eksv1.EKSClusterConfig, nodeGroups []eksv1.NodeGroup) bool {
+	length := len(config.Status.RecreatingNodeGroups)
+	config.Status.RecreatingNodeGroups = slices.DeleteFunc(config.Status.RecreatingNodeGroups, func(name string) bool {
+		return !slices.ContainsFunc(nodeGroups, func(ng eksv1.NodeGroup) bool {
+			return aws.ToString(ng.NodegroupName) == name
+		})
+	})
+	return len(config.Status.RecreatingNodeGroups) != length
+}
+
+// waitingForRecreation returns whether the node group with the given name is recreated and its old node group is
+// still deleting, EKS can't create the new one with the same name until it is gone.
+func waitingForRecreation(config *eksv1.EKSClusterConfig, name string) bool {
+	return slices.Contains(config.Status.RecreatingNodeGroups, name) &&
+		config.Status.NodeGroupStatuses[name].Status == string(ekstypes.NodegroupStatusDeleting)
+}
+
+// setNodeGroupRecreationStatus records the NodeGroupRecreationRequired condition on the config status and returns
+// whether the status changed.
+func setNodeGroupRecreationStatus(config *eksv1.EKSClusterConfig, required []string) bool {
+	status, message := string(corev1.ConditionFalse), ""
+	if len(required) != 0 {
+		status = string(corev1.ConditionTrue)
+		message = fmt.Sprintf("node groups [%s] have changes that require them to be recreated, set recreatePolicy "+
+			"to %s on them to replace them or revert the changes", strings.Join(required, "; "), recreatePolicyReplace)
+	}
+	currentStatus := nodeGroupRecreationRequired.GetStatus(config)
+	if currentStatus == "" && len(required) == 0 {
+		// don't add the condition to clusters that never had changes requiring recreation
+		return false
+	}
+	if currentStatus == status && nodeGroupRecreationRequired.GetMessage(config) == message {
+		return false
+	}
+	nodeGroupRecreationRequired.SetStatus(config, status)
+	nodeGroupRecreationRequired.Message(config, message)
+
+	return true
+}
+
+// getNotReadyNodegroups returns the names of the node groups in the spec that don't exist upstream yet or aren't active.
+func getNotReadyNodegroups(nodeGroups []eksv1.NodeGroup, nodeGroupStates []*eks.DescribeNodegroupOutput) []string {
+	statuses := make(map[string]ekstypes.NodegroupStatus, len(nodeGroupStates))
+	for _, ng := range nodeGroupStates {
+		statuses[aws.ToString(ng.Nodegroup.NodegroupName)] = ng.Nodegroup.Status
+	}
+
+	var notReady []string
+	for _, ng := range nodeGroups {
+		if statuses[aws.ToString(ng.NodegroupName)] != ekstypes.NodegroupStatusActive {
+			notReady = append(notReady, aws.ToString(ng.NodegroupName))
+		}
+	}
+
+	return notReady
+}
+
+// setNodegroupRollouts records the progress of the version updates of the updating node groups on the status and
+// returns whether it changed. The progress is informational, so node groups whose progress can't be determined are
+// left out rather than failing the reconcile, and rollouts of node groups that finished updating are removed.
+func setNodegroupRollouts(ctx context.Context, config *eksv1.EKSClusterConfig, nodeGroupStates []*eks.DescribeNodegroupOutput, awsSVCs *awsServices) bool {
+	var rollouts map[string]eksv1.NodeGroupRollout
+	for _, ng := range nodeGroupStates {
+		if ng.Nodegroup.Status != ekstypes.NodegroupStatusUpdating {
+			continue
+		}
+		name := aws.ToString(ng.Nodegroup.NodegroupName)
+		rollout, err := awsservices.GetNodegroupRollout(ctx, &awsservices.GetNodegroupRolloutOpts{
+			EKSService:  awsSVCs.eks,
+			EC2Service:  awsSVCs.ec2,
+			ClusterName: config.Spec.DisplayName,
+			Nodegroup:   ng.Nodegroup,
+			UpdateID:    config.Status.NodeGroupRollouts[name].UpdateID,
+		})
+		if err != nil {
+			logrus.Warnf("Error getting rollout progress of node group [%s] of cluster [%s (id: %s)]: %v", name, config.Spec.DisplayName, config.Name, err)
+			continue
+		}
+		if rollout == nil {
+			continue
+		}
+		logrus.Infof("Rolling out node group [%s] of cluster [%s (id: %s)]: [%d/%d] nodes updated, batch [%d/%d]",
+			name, config.Spec.DisplayName, config.Name, rollout.UpdatedNodes, rollout.TotalNodes, rollout.CurrentBatch, rollout.TotalBatches)
+		if rollouts == nil {
+			rollouts = make(map[string]eksv1.NodeGroupRollout)
+		}
+		rollouts[name] = *rollout
+	}
+
+	if len(rollouts) == 0 && len(config.Status.NodeGroupRollouts) == 0 || reflect.DeepEqual(rollouts, config.Status.NodeGroupRollouts) {
+		return false
+	}
+	config.Status.NodeGroupRollouts = rollouts
+	return true
+}
+
+// setCapacityStatus records the node capacity of the upstream node groups on the status and returns whether it
+// changed. Node groups with a custom AMI count as GPU capacity when they are marked as GPU node groups in the spec.
+func setCapacityStatus(config *eksv1.EKSClusterConfig, nodeGroupStates []*eks.DescribeNodegroupOutput) bool {
+	gpu := make(map[string]bool, len(config.Spec.NodeGroups))
+	for _, ng := range config.Spec.NodeGroups {
+		gpu[aws.ToString(ng.NodegroupName)] = aws.ToBool(ng.Gpu)
+	}
+
+	capacity := &eksv1.CapacitySummary{}
+	for _, ng := range nodeGroupStates {
+		capacity.NodeGroups++
+		if ng.Nodegroup.ScalingConfig == nil {
+			continue
+		}
+		desired := aws.ToInt32(ng.Nodegroup.ScalingConfig.DesiredSize)
+		capacity.DesiredNodes += desired
+		if ng.Nodegroup.Status == ekstypes.NodegroupStatusActive && (ng.Nodegroup.Health == nil || len(ng.Nodegroup.Health.Issues) == 0) {
+			capacity.ReadyNodes += desired
+		}
+		if ng.Nodegroup.CapacityType == ekstypes.CapacityTypesSpot {
+			capacity.SpotNodes += desired
+		} else {
+			capacity.OnDemandNodes += desired
+		}
+		amiType := string(ng.Nodegroup.AmiType)
+		if strings.Contains(amiType, "GPU") || strings.Contains(amiType, "NVIDIA") ||
+			ng.Nodegroup.AmiType == ekstypes.AMITypesCustom && gpu[aws.ToString(ng.Nodegroup.NodegroupName)] {
+			capacity.GPUNodes += desired
+		}
+	}
+
+	if reflect.DeepEqual(capacity, config.Status.Capacity) {
+		return false
+	}
+	config.Status.Capacity = capacity
+	return true
+}
+
+// setNodeGroupStatusesStatus records the status, health issues and AMI release version of the upstream node groups on
+// the status and returns whether they changed. The ready nodes of the node groups are recorded as well if they were
+// counted, node groups without ready nodes are missing from readyNodes.
+func setNodeGroupStatusesStatus(config *eksv1.EKSClusterConfig, nodeGroupStates []*eks.DescribeNodegroupOutput, readyNodes map[string]int32) bool {
+	var statuses map[string]eksv1.NodeGroupStatus
+	for _, ng := range nodeGroupStates {
+		status := eksv1.NodeGroupStatus{
+			Status:         string(ng.Nodegroup.Status),
+			ReleaseVersion: aws.ToString(ng.Nodegroup.ReleaseVersion),
+		}
+		if ng.Nodegroup.Health != nil {
+			for _, issue := range ng.Nodegroup.Health.Issues {
+				status.Issues = append(status.Issues, eksv1.NodeGroupIssue{
+					Code:        string(issue.Code),
+					Message:     aws.ToString(issue.Message),
+					ResourceIDs: issue.ResourceIds,
+				})
+			}
+		}
+		name := aws.ToString(ng.Nodegroup.NodegroupName)
+		if readyNodes != nil {
+			status.ReadyNodes = aws.Int32(readyNodes[name])
+		}
+		if statuses == nil {
+			statuses = make(map[string]eksv1.NodeGroupStatus, len(nodeGroupStates))
+		}
+		statuses[name] = status
+	}
+
+	if len(statuses) == 0 && len(config.Status.NodeGroupStatuses) == 0 || reflect.DeepEqual(statuses, config.Status.NodeGroupStatuses) {
+		return false
+	}
+	config.Status.NodeGroupStatuses = statuses
+	return true
+}
+
+// setNodegroupsReadyStatus sets the NodegroupsReady condition from the node groups that aren't ready and returns
+// whether it changed.
+func setNodegroupsReadyStatus(config *eksv1.EKSClusterConfig, notReady []string) bool {
+	status, message := string(corev1.ConditionTrue), ""
+	if len(notReady) != 0 {
+		status = string(corev1.ConditionFalse)
+		message = fmt.Sprintf("waiting for node groups [%s] to become active", strings.Join(notReady, ", "))
+	}
+	if nodegroupsReady.GetStatus(config) == status && nodegroupsReady.GetMessage(config) == message {
+		return false
+	}
+
+	nodegroupsReady.SetStatus(config, status)
+	nodegroupsReady.Message(config, message)
+	return true
+}
+
+// getDegradedNodegroupIssues returns descriptions of the health issues of degraded node groups and whether all of them
+// can be remediated by updating the node groups.
+func getDegradedNodegroupIssues(nodeGroupStates []*eks.DescribeNodegroupOutput) ([]string, bool) {
+	var issues []string
+	updatable := true
+	for _, ng := range nodeGroupStates {
+		if ng.Nodegroup.Status != ekstypes.NodegroupStatusDegraded {
+			continue
+		}
+		name := aws.ToString(ng.Nodegroup.NodegroupName)
+		if ng.Nodegroup.Health == nil || len(ng.Nodegroup.Health.Issues) == 0 {
+			issues = append(issues, fmt.Sprintf("[%s] is degraded", name))
+			continue
+		}
+		for _, issue := range ng.Nodegroup.Health.Issues {
+			issues = append(issues, fmt.Sprintf("[%s] %s: %s", name, issue.Code, aws.ToString(issue.Message)))
+			if !NodeGroupIssueIsUpdatable(string(issue.Code)) {
+				updatable = false
+			}
+		}
+	}
+
+	return issues, updatable
+}
+
+// setNodegroupsDegradedStatus sets the NodegroupsDegraded condition from the health issues of degraded node groups and
+// returns whether it changed. The condition is only added to clusters that had degraded node groups.
+func setNodegroupsDegradedStatus(config *eksv1.EKSClusterConfig, issues []string) bool {
+	if len(issues) == 0 {
+		if !nodegroupsDegraded.IsTrue(config) {
+			return false
+		}
+		nodegroupsDegraded.SetStatus(config, string(corev1.ConditionFalse))
+		nodegroupsDegraded.Message(config, "")
+		return true
+	}
+
+	message := strings.Join(issues, "; ")
+	if nodegroupsDegraded.IsTrue(config) && nodegroupsDegraded.GetMessage(config) == message {
+		return false
+	}
+	nodegroupsDegraded.SetStatus(config, string(corev1.ConditionTrue))
+	nodegroupsDegraded.Message(config, message)
+	return true
+}
+
+// setTaggingDegradedStatus sets the TaggingDegraded condition from the resources the credentials of the cluster weren't
+// allowed to tag and returns whether it changed. The condition is only added to clusters that failed to tag resources.
+func setTaggingDegradedStatus(config *eksv1.EKSClusterConfig, untaggedResources []string) bool {
+	if len(untaggedResources) == 0 {
+		if !taggingDegraded.IsTrue(config) {
+			return false
+		}
+		taggingDegraded.SetStatus(config, string(corev1.ConditionFalse))
+		taggingDegraded.Message(config, "")
+		return true
+	}
+
+	message := fmt.Sprintf("the credentials are not allowed to update the tags of [%s], eks:TagResource, "+
+		"eks:UntagResource and ec2:CreateTags permissions are required", strings.Join(untaggedResources, ", "))
+	if taggingDegraded.IsTrue(config) && taggingDegraded.GetMessage(config) == message {
+		return false
+	}
+	taggingDegraded.SetStatus(config, string(corev1.ConditionTrue))
+	taggingDegraded.Message(config, message)
+	return true
+}
+
 // getNodegroupConfigUpdate returns an UpdateNodegroupConfigInput that represents desired state and a bool
 // indicating whether an update needs to take place to achieve the desired state.
 func getNodegroupConfigUpdate(clusterName string, ng eksv1.NodeGroup, upstreamNg eksv1.NodeGroup) (eks.UpdateNodegroupConfigInput, bool) {
@@ -122,6 +1095,19 @@ func getNodegroupConfigUpdate(clusterName string, ng eksv1.NodeGroup, upstreamNg
 		}
 	}
 
+	if ng.NodeRepairConfig != nil && ng.NodeRepairConfig.Enabled != nil {
+		var upstreamEnabled bool
+		if upstreamNg.NodeRepairConfig != nil {
+			upstreamEnabled = aws.ToBool(upstreamNg.NodeRepairConfig.Enabled)
+		}
+		if upstreamEnabled != aws.ToBool(ng.NodeRepairConfig.Enabled) {
+			sendUpdateNodegroupConfig = true
+			nodegroupConfig.NodeRepairConfig = &ekstypes.NodeRepairConfig{
+				Enabled: ng.NodeRepairConfig.Enabled,
+			}
+		}
+	}
+
 	if ng.DesiredSize != nil {
 		nodegroupConfig.ScalingConfig.DesiredSize = ng.DesiredSize
 		if aws.ToInt32(upstreamNg.DesiredSize) != aws.ToInt32(ng.DesiredSize) {
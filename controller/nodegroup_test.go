@@ -1,14 +1,28 @@
 package controller
 
 import (
+	"context"
+	"fmt"
+	"slices"
 	"sort"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/aws/aws-sdk-go-v2/service/eks"
 	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/aws/smithy-go"
+	"github.com/blang/semver"
+	"github.com/golang/mock/gomock"
 	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services/mock_services"
+	"github.com/rancher/eks-operator/pkg/features"
+	"github.com/rancher/eks-operator/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetNodegroupConfigUpdate(t *testing.T) {
@@ -125,6 +139,29 @@ func TestGetNodegroupConfigUpdate(t *testing.T) {
 				}},
 			expectedNgNeedsUpdate: true,
 		},
+		{
+			// test case where node repair should be enabled
+			clusterName: "testcluster8",
+			ng1:         eksv1.NodeGroup{NodeRepairConfig: &eksv1.NodeRepairConfig{Enabled: aws.Bool(true)}},
+			ng2:         eksv1.NodeGroup{},
+			expectedNgUpdateInput: eks.UpdateNodegroupConfigInput{
+				ClusterName:      aws.String("testcluster8"),
+				NodeRepairConfig: &ekstypes.NodeRepairConfig{Enabled: aws.Bool(true)},
+				ScalingConfig:    &ekstypes.NodegroupScalingConfig{},
+			},
+			expectedNgNeedsUpdate: true,
+		},
+		{
+			// test case where node repair is unset and left as it is upstream
+			clusterName: "testcluster9",
+			ng1:         eksv1.NodeGroup{},
+			ng2:         eksv1.NodeGroup{NodeRepairConfig: &eksv1.NodeRepairConfig{Enabled: aws.Bool(true)}},
+			expectedNgUpdateInput: eks.UpdateNodegroupConfigInput{
+				ClusterName:   aws.String("testcluster9"),
+				ScalingConfig: &ekstypes.NodegroupScalingConfig{},
+			},
+			expectedNgNeedsUpdate: false,
+		},
 	}
 	for _, testCase := range testCases {
 		ngUpdateInput, ngNeedsUpdate := getNodegroupConfigUpdate(testCase.clusterName, testCase.ng1, testCase.ng2)
@@ -137,3 +174,1083 @@ func TestGetNodegroupConfigUpdate(t *testing.T) {
 		asserts.Equal(testCase.expectedNgNeedsUpdate, ngNeedsUpdate)
 	}
 }
+
+func TestSetCustomLaunchTemplateVersionUpdate(t *testing.T) {
+	type customLaunchTemplateUpdateTestCase struct {
+		name                   string
+		ng                     eksv1.NodeGroup
+		upstreamNg             eksv1.NodeGroup
+		desiredVersion         string
+		releaseVersion         *string
+		launchTemplateData     *ec2types.ResponseLaunchTemplateData
+		expectedLaunchTemplate *ekstypes.LaunchTemplateSpecification
+		expectedVersion        *string
+		expectedReleaseVersion *string
+	}
+	asserts := assert.New(t)
+	testCases := []customLaunchTemplateUpdateTestCase{
+		{
+			name:           "no update if launch template version didn't change",
+			ng:             eksv1.NodeGroup{NodegroupName: aws.String("ng"), Version: aws.String("1.30"), LaunchTemplate: &eksv1.LaunchTemplate{ID: aws.String("lt"), Version: aws.Int64(1)}},
+			upstreamNg:     eksv1.NodeGroup{NodegroupName: aws.String("ng"), Version: aws.String("1.29"), LaunchTemplate: &eksv1.LaunchTemplate{ID: aws.String("lt"), Version: aws.Int64(1)}},
+			desiredVersion: "1.30",
+		},
+		{
+			name:                   "only launch template version if kubernetes version didn't change",
+			ng:                     eksv1.NodeGroup{NodegroupName: aws.String("ng"), Version: aws.String("1.30"), LaunchTemplate: &eksv1.LaunchTemplate{ID: aws.String("lt"), Version: aws.Int64(2)}},
+			upstreamNg:             eksv1.NodeGroup{NodegroupName: aws.String("ng"), Version: aws.String("1.30"), LaunchTemplate: &eksv1.LaunchTemplate{ID: aws.String("lt"), Version: aws.Int64(1)}},
+			desiredVersion:         "1.30",
+			expectedLaunchTemplate: &ekstypes.LaunchTemplateSpecification{Id: aws.String("lt"), Version: aws.String("2")},
+		},
+		{
+			name:                   "kubernetes version if launch template doesn't specify an AMI",
+			ng:                     eksv1.NodeGroup{NodegroupName: aws.String("ng"), Version: aws.String("1.30"), LaunchTemplate: &eksv1.LaunchTemplate{ID: aws.String("lt"), Version: aws.Int64(2)}},
+			upstreamNg:             eksv1.NodeGroup{NodegroupName: aws.String("ng"), Version: aws.String("1.29"), LaunchTemplate: &eksv1.LaunchTemplate{ID: aws.String("lt"), Version: aws.Int64(1)}},
+			desiredVersion:         "1.30",
+			launchTemplateData:     &ec2types.ResponseLaunchTemplateData{},
+			expectedLaunchTemplate: &ekstypes.LaunchTemplateSpecification{Id: aws.String("lt"), Version: aws.String("2")},
+			expectedVersion:        aws.String("1.30"),
+		},
+		{
+			name:                   "no kubernetes version if launch template specifies an AMI",
+			ng:                     eksv1.NodeGroup{NodegroupName: aws.String("ng"), Version: aws.String("1.30"), LaunchTemplate: &eksv1.LaunchTemplate{Name: aws.String("lt-name"), Version: aws.Int64(2)}},
+			upstreamNg:             eksv1.NodeGroup{NodegroupName: aws.String("ng"), Version: aws.String("1.29"), LaunchTemplate: &eksv1.LaunchTemplate{ID: aws.String("lt"), Version: aws.Int64(1)}},
+			desiredVersion:         "1.30",
+			launchTemplateData:     &ec2types.ResponseLaunchTemplateData{ImageId: aws.String("ami-test")},
+			expectedLaunchTemplate: &ekstypes.LaunchTemplateSpecification{Name: aws.String("lt-name"), Version: aws.String("2")},
+		},
+		{
+			name:                   "release version if launch template doesn't specify an AMI",
+			ng:                     eksv1.NodeGroup{NodegroupName: aws.String("ng"), Version: aws.String("1.30"), LaunchTemplate: &eksv1.LaunchTemplate{ID: aws.String("lt"), Version: aws.Int64(2)}},
+			upstreamNg:             eksv1.NodeGroup{NodegroupName: aws.String("ng"), Version: aws.String("1.30"), LaunchTemplate: &eksv1.LaunchTemplate{ID: aws.String("lt"), Version: aws.Int64(1)}},
+			desiredVersion:         "1.30",
+			releaseVersion:         aws.String("1.30.2-20240807"),
+			launchTemplateData:     &ec2types.ResponseLaunchTemplateData{},
+			expectedLaunchTemplate: &ekstypes.LaunchTemplateSpecification{Id: aws.String("lt"), Version: aws.String("2")},
+			expectedReleaseVersion: aws.String("1.30.2-20240807"),
+		},
+		{
+			name:                   "no release version if launch template specifies an AMI",
+			ng:                     eksv1.NodeGroup{NodegroupName: aws.String("ng"), Version: aws.String("1.30"), LaunchTemplate: &eksv1.LaunchTemplate{ID: aws.String("lt"), Version: aws.Int64(2)}},
+			upstreamNg:             eksv1.NodeGroup{NodegroupName: aws.String("ng"), Version: aws.String("1.30"), LaunchTemplate: &eksv1.LaunchTemplate{ID: aws.String("lt"), Version: aws.Int64(1)}},
+			desiredVersion:         "1.30",
+			releaseVersion:         aws.String("1.30.2-20240807"),
+			launchTemplateData:     &ec2types.ResponseLaunchTemplateData{ImageId: aws.String("ami-test")},
+			expectedLaunchTemplate: &ekstypes.LaunchTemplateSpecification{Id: aws.String("lt"), Version: aws.String("2")},
+		},
+	}
+	for _, testCase := range testCases {
+		mockController := gomock.NewController(t)
+		ec2ServiceMock := mock_services.NewMockEC2ServiceInterface(mockController)
+		if testCase.launchTemplateData != nil {
+			ec2ServiceMock.EXPECT().DescribeLaunchTemplateVersions(gomock.Any(), &ec2.DescribeLaunchTemplateVersionsInput{
+				LaunchTemplateId: aws.String("lt"),
+				Versions:         []string{"2"},
+			}).Return(&ec2.DescribeLaunchTemplateVersionsOutput{
+				LaunchTemplateVersions: []ec2types.LaunchTemplateVersion{
+					{LaunchTemplateData: testCase.launchTemplateData},
+				},
+			}, nil)
+		}
+
+		ngVersionInput := &eks.UpdateNodegroupVersionInput{}
+		err := setCustomLaunchTemplateVersionUpdate(context.Background(), ngVersionInput, testCase.ng, testCase.upstreamNg, testCase.desiredVersion, testCase.releaseVersion, ec2ServiceMock)
+		asserts.NoError(err, testCase.name)
+		asserts.Equal(testCase.expectedLaunchTemplate, ngVersionInput.LaunchTemplate, testCase.name)
+		asserts.Equal(testCase.expectedVersion, ngVersionInput.Version, testCase.name)
+		asserts.Equal(testCase.expectedReleaseVersion, ngVersionInput.ReleaseVersion, testCase.name)
+		mockController.Finish()
+	}
+}
+
+func TestNewLaunchTemplateVersionIfNeededUserData(t *testing.T) {
+	userData := "Content-Type: multipart/mixed ..."
+	tests := []struct {
+		name             string
+		userData         *string
+		upstreamHashes   map[string]string
+		expectNewVersion bool
+	}{
+		{
+			name:           "userdata unchanged",
+			userData:       aws.String(userData),
+			upstreamHashes: map[string]string{"ng": utils.HashUserData(aws.String(userData))},
+		},
+		{
+			name: "no userdata",
+		},
+		{
+			name:             "userdata changed",
+			userData:         aws.String(userData + "changed"),
+			upstreamHashes:   map[string]string{"ng": utils.HashUserData(aws.String(userData))},
+			expectNewVersion: true,
+		},
+		{
+			name:             "userdata added",
+			userData:         aws.String(userData),
+			expectNewVersion: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := gomock.NewController(t)
+			ec2ServiceMock := mock_services.NewMockEC2ServiceInterface(mockController)
+			if tt.expectNewVersion {
+				ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(gomock.Any(), gomock.Any()).Return(&ec2.CreateLaunchTemplateVersionOutput{
+					LaunchTemplateVersion: &ec2types.LaunchTemplateVersion{
+						LaunchTemplateId: aws.String("lt"),
+						VersionNumber:    aws.Int64(2),
+					},
+				}, nil)
+			}
+			config := &eksv1.EKSClusterConfig{
+				Status: eksv1.EKSClusterConfigStatus{
+					ManagedLaunchTemplateID: "lt",
+					NodeGroupUserDataHashes: tt.upstreamHashes,
+				},
+			}
+			ng := eksv1.NodeGroup{NodegroupName: aws.String("ng"), UserData: tt.userData}
+			upstreamNg := eksv1.NodeGroup{NodegroupName: aws.String("ng")}
+			expectedUserData := aws.ToString(tt.userData)
+
+			lt, err := newLaunchTemplateVersionIfNeeded(context.Background(), config, upstreamNg, ng, ec2ServiceMock)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectNewVersion, lt != nil)
+			// the userdata in the spec must not be replaced by its encoded form
+			assert.Equal(t, expectedUserData, aws.ToString(ng.UserData))
+		})
+	}
+}
+
+func TestValidateVolumeOptions(t *testing.T) {
+	tests := []struct {
+		name        string
+		ng          eksv1.NodeGroup
+		expectedErr bool
+	}{
+		{
+			name: "no volume options",
+			ng:   eksv1.NodeGroup{NodegroupName: aws.String("ng")},
+		},
+		{
+			name: "gp3 with iops and throughput",
+			ng: eksv1.NodeGroup{NodegroupName: aws.String("ng"), VolumeType: aws.String("gp3"), Iops: aws.Int32(4000),
+				Throughput: aws.Int32(250), Encrypted: aws.Bool(true), KmsKeyID: aws.String("key")},
+		},
+		{
+			name: "io2 with iops",
+			ng:   eksv1.NodeGroup{NodegroupName: aws.String("ng"), VolumeType: aws.String("io2"), Iops: aws.Int32(4000)},
+		},
+		{
+			name:        "custom launch template",
+			ng:          eksv1.NodeGroup{NodegroupName: aws.String("ng"), LaunchTemplate: &eksv1.LaunchTemplate{ID: aws.String("lt")}, VolumeType: aws.String("gp3")},
+			expectedErr: true,
+		},
+		{
+			name:        "invalid volume type",
+			ng:          eksv1.NodeGroup{NodegroupName: aws.String("ng"), VolumeType: aws.String("gp4")},
+			expectedErr: true,
+		},
+		{
+			name:        "io2 without iops",
+			ng:          eksv1.NodeGroup{NodegroupName: aws.String("ng"), VolumeType: aws.String("io2")},
+			expectedErr: true,
+		},
+		{
+			name:        "iops for gp2",
+			ng:          eksv1.NodeGroup{NodegroupName: aws.String("ng"), VolumeType: aws.String("gp2"), Iops: aws.Int32(4000)},
+			expectedErr: true,
+		},
+		{
+			name:        "throughput for io2",
+			ng:          eksv1.NodeGroup{NodegroupName: aws.String("ng"), VolumeType: aws.String("io2"), Iops: aws.Int32(4000), Throughput: aws.Int32(250)},
+			expectedErr: true,
+		},
+		{
+			name:        "kms key without encryption",
+			ng:          eksv1.NodeGroup{NodegroupName: aws.String("ng"), KmsKeyID: aws.String("key")},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateVolumeOptions(&eksv1.EKSClusterConfig{}, tt.ng)
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateReleaseVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		ng          eksv1.NodeGroup
+		expectedErr bool
+	}{
+		{
+			name: "no release version",
+			ng:   eksv1.NodeGroup{NodegroupName: aws.String("ng"), Version: aws.String("1.30")},
+		},
+		{
+			name: "release of the node group version",
+			ng:   eksv1.NodeGroup{NodegroupName: aws.String("ng"), Version: aws.String("1.30"), ReleaseVersion: aws.String("1.30.0-20240703")},
+		},
+		{
+			name: "release of the cluster version",
+			ng:   eksv1.NodeGroup{NodegroupName: aws.String("ng"), ReleaseVersion: aws.String("1.29.3-20240625")},
+		},
+		{
+			name:        "release of another version",
+			ng:          eksv1.NodeGroup{NodegroupName: aws.String("ng"), Version: aws.String("1.30"), ReleaseVersion: aws.String("1.29.3-20240625")},
+			expectedErr: true,
+		},
+		{
+			name:        "custom AMI",
+			ng:          eksv1.NodeGroup{NodegroupName: aws.String("ng"), ImageID: aws.String("ami-1"), ReleaseVersion: aws.String("1.29.3-20240625")},
+			expectedErr: true,
+		},
+		{
+			name: "custom launch template",
+			ng:   eksv1.NodeGroup{NodegroupName: aws.String("ng"), LaunchTemplate: &eksv1.LaunchTemplate{ID: aws.String("lt")}, ReleaseVersion: aws.String("1.29.3-20240625")},
+		},
+	}
+
+	config := &eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{KubernetesVersion: aws.String("1.29")}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateReleaseVersion(config, tt.ng)
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReleaseVersionUpdate(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{Status: eksv1.EKSClusterConfigStatus{
+		NodeGroupStatuses: map[string]eksv1.NodeGroupStatus{"ng": {ReleaseVersion: "1.30.0-20240703"}},
+	}}
+	ng := eksv1.NodeGroup{NodegroupName: aws.String("ng")}
+	assert.Nil(t, releaseVersionUpdate(config, ng), "unpinned node groups keep their release")
+
+	ng.ReleaseVersion = aws.String("1.30.0-20240703")
+	assert.Nil(t, releaseVersionUpdate(config, ng))
+
+	ng.ReleaseVersion = aws.String("1.30.2-20240807")
+	assert.Equal(t, "1.30.2-20240807", aws.ToString(releaseVersionUpdate(config, ng)))
+
+	ng.NodegroupName = aws.String("new")
+	assert.Nil(t, releaseVersionUpdate(config, ng), "node groups without recorded status are not updated")
+}
+
+func TestCompareVolumeOptions(t *testing.T) {
+	upstreamNg := eksv1.NodeGroup{VolumeType: aws.String("gp3"), Iops: aws.Int32(3000), Encrypted: aws.Bool(true)}
+
+	assert.True(t, compareVolumeOptions(upstreamNg, eksv1.NodeGroup{VolumeType: aws.String("gp3"), Iops: aws.Int32(3000), Encrypted: aws.Bool(true)}))
+	assert.False(t, compareVolumeOptions(upstreamNg, eksv1.NodeGroup{VolumeType: aws.String("io2"), Iops: aws.Int32(3000), Encrypted: aws.Bool(true)}))
+	assert.False(t, compareVolumeOptions(upstreamNg, eksv1.NodeGroup{VolumeType: aws.String("gp3"), Iops: aws.Int32(4000), Encrypted: aws.Bool(true)}))
+	assert.False(t, compareVolumeOptions(upstreamNg, eksv1.NodeGroup{VolumeType: aws.String("gp3"), Iops: aws.Int32(3000)}))
+	assert.True(t, compareVolumeOptions(eksv1.NodeGroup{Encrypted: aws.Bool(false)}, eksv1.NodeGroup{}))
+}
+
+func TestCompareMetadataOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		upstream *eksv1.MetadataOptions
+		desired  *eksv1.MetadataOptions
+		expected bool
+	}{
+		{
+			name:     "both unset",
+			expected: true,
+		},
+		{
+			name:     "equal",
+			upstream: &eksv1.MetadataOptions{HTTPTokens: aws.String("required"), HTTPPutResponseHopLimit: aws.Int32(2)},
+			desired:  &eksv1.MetadataOptions{HTTPTokens: aws.String("required"), HTTPPutResponseHopLimit: aws.Int32(2)},
+			expected: true,
+		},
+		{
+			name:     "unset and empty",
+			upstream: &eksv1.MetadataOptions{},
+			expected: true,
+		},
+		{
+			name:     "empty and unset",
+			desired:  &eksv1.MetadataOptions{},
+			expected: true,
+		},
+		{
+			name:    "added",
+			desired: &eksv1.MetadataOptions{HTTPTokens: aws.String("required")},
+		},
+		{
+			name:     "removed",
+			upstream: &eksv1.MetadataOptions{HTTPTokens: aws.String("required")},
+		},
+		{
+			name:     "http tokens changed",
+			upstream: &eksv1.MetadataOptions{HTTPTokens: aws.String("optional")},
+			desired:  &eksv1.MetadataOptions{HTTPTokens: aws.String("required")},
+		},
+		{
+			name:     "instance metadata tags changed",
+			upstream: &eksv1.MetadataOptions{InstanceMetadataTags: aws.String("disabled")},
+			desired:  &eksv1.MetadataOptions{InstanceMetadataTags: aws.String("enabled")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, compareMetadataOptions(tt.upstream, tt.desired))
+		})
+	}
+}
+
+func TestValidateMetadataOptions(t *testing.T) {
+	tests := []struct {
+		name        string
+		ng          eksv1.NodeGroup
+		expectedErr bool
+	}{
+		{
+			name: "no metadata options",
+			ng:   eksv1.NodeGroup{NodegroupName: aws.String("ng")},
+		},
+		{
+			name: "valid metadata options",
+			ng: eksv1.NodeGroup{NodegroupName: aws.String("ng"), MetadataOptions: &eksv1.MetadataOptions{
+				HTTPTokens:              aws.String("required"),
+				HTTPPutResponseHopLimit: aws.Int32(2),
+				InstanceMetadataTags:    aws.String("disabled"),
+			}},
+		},
+		{
+			name: "custom launch template",
+			ng: eksv1.NodeGroup{NodegroupName: aws.String("ng"), LaunchTemplate: &eksv1.LaunchTemplate{ID: aws.String("lt")},
+				MetadataOptions: &eksv1.MetadataOptions{HTTPTokens: aws.String("required")}},
+			expectedErr: true,
+		},
+		{
+			name:        "invalid http tokens",
+			ng:          eksv1.NodeGroup{NodegroupName: aws.String("ng"), MetadataOptions: &eksv1.MetadataOptions{HTTPTokens: aws.String("always")}},
+			expectedErr: true,
+		},
+		{
+			name:        "invalid hop limit",
+			ng:          eksv1.NodeGroup{NodegroupName: aws.String("ng"), MetadataOptions: &eksv1.MetadataOptions{HTTPPutResponseHopLimit: aws.Int32(65)}},
+			expectedErr: true,
+		},
+		{
+			name:        "invalid instance metadata tags",
+			ng:          eksv1.NodeGroup{NodegroupName: aws.String("ng"), MetadataOptions: &eksv1.MetadataOptions{InstanceMetadataTags: aws.String("on")}},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMetadataOptions(&eksv1.EKSClusterConfig{}, tt.ng)
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateBlockDeviceMappings(t *testing.T) {
+	tests := []struct {
+		name        string
+		mappings    []eksv1.BlockDeviceMapping
+		expectedErr bool
+	}{
+		{
+			name: "no block device mappings",
+		},
+		{
+			name: "valid block device mappings",
+			mappings: []eksv1.BlockDeviceMapping{
+				{DeviceName: aws.String("/dev/xvdb"), VolumeSize: aws.Int32(100), VolumeType: aws.String("gp3"), Iops: aws.Int32(4000)},
+				{DeviceName: aws.String("/dev/xvdc"), VolumeSize: aws.Int32(50), Encrypted: aws.Bool(true), KmsKeyID: aws.String("key")},
+			},
+		},
+		{
+			name:        "missing device name",
+			mappings:    []eksv1.BlockDeviceMapping{{VolumeSize: aws.Int32(100)}},
+			expectedErr: true,
+		},
+		{
+			name: "duplicate device name",
+			mappings: []eksv1.BlockDeviceMapping{
+				{DeviceName: aws.String("/dev/xvdb"), VolumeSize: aws.Int32(100)},
+				{DeviceName: aws.String("/dev/xvdb"), VolumeSize: aws.Int32(50)},
+			},
+			expectedErr: true,
+		},
+		{
+			name:        "missing volume size",
+			mappings:    []eksv1.BlockDeviceMapping{{DeviceName: aws.String("/dev/xvdb")}},
+			expectedErr: true,
+		},
+		{
+			name:        "throughput of gp2 volume",
+			mappings:    []eksv1.BlockDeviceMapping{{DeviceName: aws.String("/dev/xvdb"), VolumeSize: aws.Int32(100), VolumeType: aws.String("gp2"), Throughput: aws.Int32(500)}},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateBlockDeviceMappings(&eksv1.EKSClusterConfig{}, eksv1.NodeGroup{NodegroupName: aws.String("ng"), BlockDeviceMappings: tt.mappings})
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	assert.Error(t, validateBlockDeviceMappings(&eksv1.EKSClusterConfig{}, eksv1.NodeGroup{
+		NodegroupName:       aws.String("ng"),
+		LaunchTemplate:      &eksv1.LaunchTemplate{ID: aws.String("lt")},
+		BlockDeviceMappings: []eksv1.BlockDeviceMapping{{DeviceName: aws.String("/dev/xvdb"), VolumeSize: aws.Int32(100)}},
+	}), "block devices of custom launch templates are set on them")
+}
+
+func TestCompareBlockDeviceMappings(t *testing.T) {
+	mapping := eksv1.BlockDeviceMapping{DeviceName: aws.String("/dev/xvdb"), VolumeSize: aws.Int32(100)}
+	assert.True(t, compareBlockDeviceMappings(nil, []eksv1.BlockDeviceMapping{}))
+	assert.True(t, compareBlockDeviceMappings([]eksv1.BlockDeviceMapping{mapping}, []eksv1.BlockDeviceMapping{mapping}))
+	assert.False(t, compareBlockDeviceMappings(nil, []eksv1.BlockDeviceMapping{mapping}))
+
+	resized := mapping
+	resized.VolumeSize = aws.Int32(200)
+	assert.False(t, compareBlockDeviceMappings([]eksv1.BlockDeviceMapping{mapping}, []eksv1.BlockDeviceMapping{resized}))
+}
+
+func TestValidateNetworkInterfaces(t *testing.T) {
+	tests := []struct {
+		name              string
+		networkInterfaces []eksv1.NetworkInterface
+		expectedErr       bool
+	}{
+		{
+			name: "no network interfaces",
+		},
+		{
+			name:              "private primary interface",
+			networkInterfaces: []eksv1.NetworkInterface{{AssociatePublicIPAddress: aws.Bool(false), SecurityGroups: []string{"sg-nodes"}}},
+		},
+		{
+			name: "network cards",
+			networkInterfaces: []eksv1.NetworkInterface{
+				{InterfaceType: aws.String("efa")},
+				{NetworkCardIndex: aws.Int32(1), DeviceIndex: aws.Int32(1), InterfaceType: aws.String("efa-only")},
+			},
+		},
+		{
+			name:              "missing primary interface",
+			networkInterfaces: []eksv1.NetworkInterface{{DeviceIndex: aws.Int32(1)}},
+			expectedErr:       true,
+		},
+		{
+			name:              "duplicate interface",
+			networkInterfaces: []eksv1.NetworkInterface{{}, {DeviceIndex: aws.Int32(0)}},
+			expectedErr:       true,
+		},
+		{
+			name:              "public IP of multiple interfaces",
+			networkInterfaces: []eksv1.NetworkInterface{{AssociatePublicIPAddress: aws.Bool(true)}, {DeviceIndex: aws.Int32(1)}},
+			expectedErr:       true,
+		},
+		{
+			name:              "invalid interface type",
+			networkInterfaces: []eksv1.NetworkInterface{{InterfaceType: aws.String("trunk")}},
+			expectedErr:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateNetworkInterfaces(&eksv1.EKSClusterConfig{}, eksv1.NodeGroup{NodegroupName: aws.String("ng"), NetworkInterfaces: tt.networkInterfaces})
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCompareNetworkInterfaces(t *testing.T) {
+	desired := []eksv1.NetworkInterface{{AssociatePublicIPAddress: aws.Bool(false), SecurityGroups: []string{"sg-nodes"}}}
+	upstream := []eksv1.NetworkInterface{{DeviceIndex: aws.Int32(0), AssociatePublicIPAddress: aws.Bool(false), SecurityGroups: []string{"sg-cluster", "sg-nodes"}}}
+	assert.True(t, compareNetworkInterfaces(upstream, desired, "sg-cluster"))
+	assert.Equal(t, []string{"sg-cluster", "sg-nodes"}, upstream[0].SecurityGroups, "security groups must not be modified")
+	assert.False(t, compareNetworkInterfaces(nil, desired, "sg-cluster"))
+
+	desired[0].AssociatePublicIPAddress = aws.Bool(true)
+	assert.False(t, compareNetworkInterfaces(upstream, desired, "sg-cluster"))
+}
+
+func TestComparePlacement(t *testing.T) {
+	assert.True(t, comparePlacement(nil, nil))
+	assert.True(t, comparePlacement(nil, &eksv1.Placement{Tenancy: aws.String("default")}))
+	// the strategy and partition count aren't part of the upstream launch template
+	assert.True(t, comparePlacement(&eksv1.Placement{GroupName: aws.String("hpc")},
+		&eksv1.Placement{GroupName: aws.String("hpc"), Strategy: aws.String("partition"), PartitionCount: aws.Int32(3)}))
+	assert.False(t, comparePlacement(nil, &eksv1.Placement{GroupName: aws.String("hpc")}))
+	assert.False(t, comparePlacement(&eksv1.Placement{GroupName: aws.String("hpc"), PartitionNumber: aws.Int32(1)},
+		&eksv1.Placement{GroupName: aws.String("hpc"), PartitionNumber: aws.Int32(2)}))
+	assert.False(t, comparePlacement(&eksv1.Placement{Tenancy: aws.String("default")}, &eksv1.Placement{Tenancy: aws.String("dedicated")}))
+}
+
+func TestValidatePlacement(t *testing.T) {
+	tests := []struct {
+		name        string
+		placement   *eksv1.Placement
+		expectedErr bool
+	}{
+		{
+			name: "no placement",
+		},
+		{
+			name:      "partition placement group",
+			placement: &eksv1.Placement{GroupName: aws.String("hpc"), Strategy: aws.String("partition"), PartitionCount: aws.Int32(3), PartitionNumber: aws.Int32(3)},
+		},
+		{
+			name:      "existing placement group",
+			placement: &eksv1.Placement{GroupName: aws.String("hpc"), PartitionNumber: aws.Int32(2)},
+		},
+		{
+			name:      "dedicated tenancy",
+			placement: &eksv1.Placement{Tenancy: aws.String("dedicated")},
+		},
+		{
+			name:        "invalid tenancy",
+			placement:   &eksv1.Placement{Tenancy: aws.String("shared")},
+			expectedErr: true,
+		},
+		{
+			name:        "strategy without group name",
+			placement:   &eksv1.Placement{Strategy: aws.String("cluster")},
+			expectedErr: true,
+		},
+		{
+			name:        "invalid strategy",
+			placement:   &eksv1.Placement{GroupName: aws.String("hpc"), Strategy: aws.String("pack")},
+			expectedErr: true,
+		},
+		{
+			name:        "partition count of cluster strategy",
+			placement:   &eksv1.Placement{GroupName: aws.String("hpc"), Strategy: aws.String("cluster"), PartitionCount: aws.Int32(2)},
+			expectedErr: true,
+		},
+		{
+			name:        "too many partitions",
+			placement:   &eksv1.Placement{GroupName: aws.String("hpc"), Strategy: aws.String("partition"), PartitionCount: aws.Int32(8)},
+			expectedErr: true,
+		},
+		{
+			name:        "partition number out of range",
+			placement:   &eksv1.Placement{GroupName: aws.String("hpc"), Strategy: aws.String("partition"), PartitionCount: aws.Int32(2), PartitionNumber: aws.Int32(3)},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePlacement(&eksv1.EKSClusterConfig{}, eksv1.NodeGroup{NodegroupName: aws.String("ng"), Placement: tt.placement})
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	assert.Error(t, validatePlacement(&eksv1.EKSClusterConfig{}, eksv1.NodeGroup{
+		NodegroupName:  aws.String("ng"),
+		LaunchTemplate: &eksv1.LaunchTemplate{ID: aws.String("lt")},
+		Placement:      &eksv1.Placement{Tenancy: aws.String("dedicated")},
+	}), "placements of custom launch templates are set on them")
+}
+
+func TestGetDeletionProtectedNodeGroups(t *testing.T) {
+	asserts := assert.New(t)
+	testCases := []struct {
+		name                string
+		nodeGroups          []eksv1.NodeGroup
+		previouslyProtected []string
+		upstreamNgs         map[string]eksv1.NodeGroup
+		expectedProtected   []string
+		expectedBlocked     []string
+	}{
+		{
+			name:        "no deletion protection",
+			nodeGroups:  []eksv1.NodeGroup{{NodegroupName: aws.String("ng1")}},
+			upstreamNgs: map[string]eksv1.NodeGroup{"ng1": {}},
+		},
+		{
+			name:              "protected node group in spec",
+			nodeGroups:        []eksv1.NodeGroup{{NodegroupName: aws.String("ng1"), DeletionProtection: aws.Bool(true)}, {NodegroupName: aws.String("ng2")}},
+			upstreamNgs:       map[string]eksv1.NodeGroup{"ng1": {}, "ng2": {}},
+			expectedProtected: []string{"ng1"},
+		},
+		{
+			name:                "protected node group removed from spec",
+			nodeGroups:          []eksv1.NodeGroup{{NodegroupName: aws.String("ng2")}},
+			previouslyProtected: []string{"ng1"},
+			upstreamNgs:         map[string]eksv1.NodeGroup{"ng1": {}, "ng2": {}},
+			expectedProtected:   []string{"ng1"},
+			expectedBlocked:     []string{"ng1"},
+		},
+		{
+			name:                "deletion protection disabled in spec",
+			nodeGroups:          []eksv1.NodeGroup{{NodegroupName: aws.String("ng1"), DeletionProtection: aws.Bool(false)}},
+			previouslyProtected: []string{"ng1"},
+			upstreamNgs:         map[string]eksv1.NodeGroup{"ng1": {}},
+		},
+		{
+			name:                "protected node group no longer exists upstream",
+			previouslyProtected: []string{"ng1"},
+			upstreamNgs:         map[string]eksv1.NodeGroup{},
+		},
+	}
+	for _, testCase := range testCases {
+		protected, blocked := getDeletionProtectedNodeGroups(testCase.nodeGroups, testCase.previouslyProtected, testCase.upstreamNgs)
+		asserts.Equal(testCase.expectedProtected, protected, testCase.name)
+		asserts.Equal(testCase.expectedBlocked, blocked, testCase.name)
+	}
+}
+
+func TestSetNodeGroupDeletionProtectionStatus(t *testing.T) {
+	asserts := assert.New(t)
+	config := &eksv1.EKSClusterConfig{}
+
+	asserts.False(setNodeGroupDeletionProtectionStatus(config, nil, nil))
+	asserts.Empty(config.Status.Conditions)
+
+	asserts.True(setNodeGroupDeletionProtectionStatus(config, []string{"ng1"}, []string{"ng1"}))
+	asserts.True(nodeGroupDeletionBlocked.IsTrue(config))
+	asserts.Contains(nodeGroupDeletionBlocked.GetMessage(config), "ng1")
+	asserts.False(setNodeGroupDeletionProtectionStatus(config, []string{"ng1"}, []string{"ng1"}))
+
+	asserts.True(setNodeGroupDeletionProtectionStatus(config, nil, nil))
+	asserts.True(nodeGroupDeletionBlocked.IsFalse(config))
+	asserts.Empty(config.Status.DeletionProtectedNodeGroups)
+}
+
+func TestGetImmutableNodeGroupChanges(t *testing.T) {
+	asserts := assert.New(t)
+	upstreamNg := eksv1.NodeGroup{
+		NodegroupName:        aws.String("ng1"),
+		InstanceType:         "t3.medium",
+		Subnets:              []string{"subnet-a", "subnet-b"},
+		RequestSpotInstances: aws.Bool(false),
+		NodeRole:             aws.String("arn:aws:iam::123456789012:role/node"),
+	}
+	testCases := []struct {
+		name     string
+		ng       eksv1.NodeGroup
+		upstream eksv1.NodeGroup
+		expected []string
+	}{
+		{
+			name:     "unset fields",
+			ng:       eksv1.NodeGroup{NodegroupName: aws.String("ng1")},
+			upstream: upstreamNg,
+		},
+		{
+			name:     "empty subnets",
+			ng:       eksv1.NodeGroup{Subnets: []string{}},
+			upstream: upstreamNg,
+		},
+		{
+			name:     "subnets in another order",
+			ng:       eksv1.NodeGroup{Subnets: []string{"subnet-b", "subnet-a"}},
+			upstream: upstreamNg,
+		},
+		{
+			name: "all immutable fields changed",
+			ng: eksv1.NodeGroup{
+				InstanceType: "m5.large",
+				Subnets:      []string{"subnet-c"},
+				NodeRole:     aws.String("arn:aws:iam::123456789012:role/other"),
+			},
+			upstream: upstreamNg,
+			expected: []string{"subnets", "instanceType", "nodeRole"},
+		},
+		{
+			name:     "spot instances requested",
+			ng:       eksv1.NodeGroup{RequestSpotInstances: aws.Bool(true), SpotInstanceTypes: []string{"m5.large"}},
+			upstream: upstreamNg,
+			expected: []string{"requestSpotInstances"},
+		},
+		{
+			name:     "spot instance types changed",
+			ng:       eksv1.NodeGroup{RequestSpotInstances: aws.Bool(true), SpotInstanceTypes: []string{"m5.large"}},
+			upstream: eksv1.NodeGroup{RequestSpotInstances: aws.Bool(true), SpotInstanceTypes: []string{"t3.medium"}},
+			expected: []string{"spotInstanceTypes"},
+		},
+		{
+			name:     "instance type of a node group with a launch template",
+			ng:       eksv1.NodeGroup{InstanceType: "m5.large"},
+			upstream: eksv1.NodeGroup{InstanceType: "t3.medium", LaunchTemplate: &eksv1.LaunchTemplate{ID: aws.String("lt-1")}},
+		},
+	}
+	for _, testCase := range testCases {
+		asserts.Equal(testCase.expected, getImmutableNodeGroupChanges(testCase.ng, testCase.upstream), testCase.name)
+	}
+}
+
+func TestPlanNodeGroupRecreation(t *testing.T) {
+	asserts := assert.New(t)
+	upstreamNgs := map[string]eksv1.NodeGroup{
+		"ng1": {NodegroupName: aws.String("ng1"), Subnets: []string{"subnet-a"}},
+		"ng2": {NodegroupName: aws.String("ng2"), Subnets: []string{"subnet-a"}},
+		"ng3": {NodegroupName: aws.String("ng3"), Subnets: []string{"subnet-a"}},
+		"ng4": {NodegroupName: aws.String("ng4"), Subnets: []string{"subnet-a"}},
+	}
+	nodeGroups := []eksv1.NodeGroup{
+		{NodegroupName: aws.String("ng1"), Subnets: []string{"subnet-b"}},
+		{NodegroupName: aws.String("ng2"), Subnets: []string{"subnet-b"}, RecreatePolicy: recreatePolicyReplace},
+		{NodegroupName: aws.String("ng3"), Subnets: []string{"subnet-b"}, RecreatePolicy: recreatePolicyReplace, DeletionProtection: aws.Bool(true)},
+		{NodegroupName: aws.String("ng4"), Subnets: []string{"subnet-a"}, RecreatePolicy: recreatePolicyReplace},
+		{NodegroupName: aws.String("ng5"), Subnets: []string{"subnet-b"}, RecreatePolicy: recreatePolicyReplace},
+	}
+
+	plan := planNodeGroupRecreation(nodeGroups, upstreamNgs, true)
+	asserts.Equal([]eksv1.NodeGroup{upstreamNgs["ng2"]}, plan.replace)
+	asserts.Empty(plan.deferred)
+	asserts.Equal([]string{"ng1 (subnets)", "ng3 (subnets, deletion protected)"}, plan.required)
+
+	plan = planNodeGroupRecreation(nodeGroups, upstreamNgs, false)
+	asserts.Empty(plan.replace)
+	asserts.Equal([]string{"ng2"}, plan.deferred, "node groups are only recreated during the maintenance window")
+	asserts.Equal([]string{"ng1 (subnets)", "ng3 (subnets, deletion protected)"}, plan.required)
+}
+
+func TestSetNodeGroupRecreationStatus(t *testing.T) {
+	asserts := assert.New(t)
+	config := &eksv1.EKSClusterConfig{}
+
+	asserts.False(setNodeGroupRecreationStatus(config, nil))
+	asserts.Empty(config.Status.Conditions)
+
+	asserts.True(setNodeGroupRecreationStatus(config, []string{"ng1 (subnets)"}))
+	asserts.True(nodeGroupRecreationRequired.IsTrue(config))
+	asserts.Contains(nodeGroupRecreationRequired.GetMessage(config), "ng1 (subnets)")
+	asserts.False(setNodeGroupRecreationStatus(config, []string{"ng1 (subnets)"}))
+
+	asserts.True(setNodeGroupRecreationStatus(config, nil))
+	asserts.True(nodeGroupRecreationRequired.IsFalse(config))
+}
+
+func TestRecreatingNodeGroups(t *testing.T) {
+	asserts := assert.New(t)
+	config := &eksv1.EKSClusterConfig{}
+	config.Status.RecreatingNodeGroups = []string{"ng1", "ng2"}
+	config.Status.NodeGroupStatuses = map[string]eksv1.NodeGroupStatus{"ng1": {Status: string(ekstypes.NodegroupStatusDeleting)}}
+	nodeGroups := []eksv1.NodeGroup{{NodegroupName: aws.String("ng1")}, {NodegroupName: aws.String("ng2")}}
+
+	asserts.False(pruneRecreatingNodeGroups(config, nodeGroups))
+	asserts.True(waitingForRecreation(config, "ng1"))
+	asserts.False(waitingForRecreation(config, "ng2"), "the old node group is gone")
+	asserts.False(waitingForRecreation(config, "ng3"), "the node group isn't recreated")
+
+	// node groups removed from the spec while they are recreated are dropped
+	asserts.True(pruneRecreatingNodeGroups(config, nodeGroups[:1]))
+	asserts.Equal([]string{"ng1"}, config.Status.RecreatingNodeGroups)
+}
+
+func TestValidateRecreatePolicy(t *testing.T) {
+	asserts := assert.New(t)
+	config := &eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{DisplayName: "test"}}
+
+	asserts.NoError(validateRecreatePolicy(config, eksv1.NodeGroup{NodegroupName: aws.String("ng1")}))
+	asserts.NoError(validateRecreatePolicy(config, eksv1.NodeGroup{NodegroupName: aws.String("ng1"), RecreatePolicy: "Replace"}))
+	asserts.Error(validateRecreatePolicy(config, eksv1.NodeGroup{NodegroupName: aws.String("ng1"), RecreatePolicy: "replace"}))
+}
+
+func TestNodegroupsReadyStatus(t *testing.T) {
+	asserts := assert.New(t)
+	nodeGroups := []eksv1.NodeGroup{{NodegroupName: aws.String("ng1")}, {NodegroupName: aws.String("ng2")}}
+	nodeGroupState := func(name string, status ekstypes.NodegroupStatus) *eks.DescribeNodegroupOutput {
+		return &eks.DescribeNodegroupOutput{Nodegroup: &ekstypes.Nodegroup{NodegroupName: aws.String(name), Status: status}}
+	}
+	config := &eksv1.EKSClusterConfig{}
+
+	notReady := getNotReadyNodegroups(nodeGroups, []*eks.DescribeNodegroupOutput{nodeGroupState("ng1", ekstypes.NodegroupStatusCreating)})
+	asserts.Equal([]string{"ng1", "ng2"}, notReady)
+	asserts.True(setNodegroupsReadyStatus(config, notReady))
+	asserts.True(nodegroupsReady.IsFalse(config))
+	asserts.Contains(nodegroupsReady.GetMessage(config), "ng1, ng2")
+	asserts.False(setNodegroupsReadyStatus(config, notReady))
+
+	notReady = getNotReadyNodegroups(nodeGroups, []*eks.DescribeNodegroupOutput{
+		nodeGroupState("ng1", ekstypes.NodegroupStatusActive),
+		nodeGroupState("ng2", ekstypes.NodegroupStatusActive),
+		nodeGroupState("removed", ekstypes.NodegroupStatusDeleting),
+	})
+	asserts.Empty(notReady)
+	asserts.True(setNodegroupsReadyStatus(config, notReady))
+	asserts.True(nodegroupsReady.IsTrue(config))
+	asserts.Empty(nodegroupsReady.GetMessage(config))
+}
+
+func TestNodegroupsDegradedStatus(t *testing.T) {
+	asserts := assert.New(t)
+	degraded := func(name string, codes ...ekstypes.NodegroupIssueCode) *eks.DescribeNodegroupOutput {
+		health := &ekstypes.NodegroupHealth{}
+		for _, code := range codes {
+			health.Issues = append(health.Issues, ekstypes.Issue{Code: code, Message: aws.String("message")})
+		}
+		return &eks.DescribeNodegroupOutput{Nodegroup: &ekstypes.Nodegroup{
+			NodegroupName: aws.String(name),
+			Status:        ekstypes.NodegroupStatusDegraded,
+			Health:        health,
+		}}
+	}
+	active := &eks.DescribeNodegroupOutput{Nodegroup: &ekstypes.Nodegroup{NodegroupName: aws.String("active"), Status: ekstypes.NodegroupStatusActive}}
+	config := &eksv1.EKSClusterConfig{}
+
+	issues, updatable := getDegradedNodegroupIssues([]*eks.DescribeNodegroupOutput{active})
+	asserts.Empty(issues)
+	asserts.True(updatable)
+	asserts.False(setNodegroupsDegradedStatus(config, issues))
+	asserts.Empty(config.Status.Conditions)
+
+	issues, updatable = getDegradedNodegroupIssues([]*eks.DescribeNodegroupOutput{active, degraded("ng1", ekstypes.NodegroupIssueCodeInstanceLimitExceeded)})
+	asserts.Equal([]string{"[ng1] InstanceLimitExceeded: message"}, issues)
+	asserts.True(updatable)
+	asserts.True(setNodegroupsDegradedStatus(config, issues))
+	asserts.True(nodegroupsDegraded.IsTrue(config))
+	asserts.False(setNodegroupsDegradedStatus(config, issues))
+
+	issues, updatable = getDegradedNodegroupIssues([]*eks.DescribeNodegroupOutput{
+		degraded("ng1", ekstypes.NodegroupIssueCodeInstanceLimitExceeded, ekstypes.NodegroupIssueCodeIamInstanceProfileNotFound),
+	})
+	asserts.Len(issues, 2)
+	asserts.False(updatable)
+	asserts.True(setNodegroupsDegradedStatus(config, issues))
+
+	asserts.True(setNodegroupsDegradedStatus(config, nil))
+	asserts.True(nodegroupsDegraded.IsFalse(config))
+	asserts.Empty(nodegroupsDegraded.GetMessage(config))
+}
+
+func TestCapacityStatus(t *testing.T) {
+	asserts := assert.New(t)
+	nodeGroupState := func(name string, status ekstypes.NodegroupStatus, capacityType ekstypes.CapacityTypes, amiType ekstypes.AMITypes, desired int32) *eks.DescribeNodegroupOutput {
+		return &eks.DescribeNodegroupOutput{Nodegroup: &ekstypes.Nodegroup{
+			NodegroupName: aws.String(name),
+			Status:        status,
+			CapacityType:  capacityType,
+			AmiType:       amiType,
+			ScalingConfig: &ekstypes.NodegroupScalingConfig{DesiredSize: aws.Int32(desired)},
+		}}
+	}
+	config := &eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{
+		NodeGroups: []eksv1.NodeGroup{{NodegroupName: aws.String("custom"), Gpu: aws.Bool(true)}},
+	}}
+	nodeGroupStates := []*eks.DescribeNodegroupOutput{
+		nodeGroupState("ondemand", ekstypes.NodegroupStatusActive, ekstypes.CapacityTypesOnDemand, ekstypes.AMITypesAl2023X8664Standard, 3),
+		nodeGroupState("spot", ekstypes.NodegroupStatusCreating, ekstypes.CapacityTypesSpot, ekstypes.AMITypesAl2023X8664Standard, 2),
+		nodeGroupState("gpu", ekstypes.NodegroupStatusActive, ekstypes.CapacityTypesOnDemand, ekstypes.AMITypesAl2X8664Gpu, 1),
+		nodeGroupState("custom", ekstypes.NodegroupStatusDegraded, ekstypes.CapacityTypesOnDemand, ekstypes.AMITypesCustom, 4),
+	}
+
+	asserts.True(setCapacityStatus(config, nodeGroupStates))
+	asserts.Equal(&eksv1.CapacitySummary{
+		NodeGroups:    4,
+		DesiredNodes:  10,
+		ReadyNodes:    4,
+		OnDemandNodes: 8,
+		SpotNodes:     2,
+		GPUNodes:      5,
+	}, config.Status.Capacity)
+	asserts.False(setCapacityStatus(config, nodeGroupStates))
+
+	asserts.True(setCapacityStatus(config, nil))
+	asserts.Equal(&eksv1.CapacitySummary{}, config.Status.Capacity)
+}
+
+func TestNodeGroupStatusesStatus(t *testing.T) {
+	asserts := assert.New(t)
+	config := &eksv1.EKSClusterConfig{}
+	nodeGroupStates := []*eks.DescribeNodegroupOutput{
+		{Nodegroup: &ekstypes.Nodegroup{
+			NodegroupName:  aws.String("active"),
+			Status:         ekstypes.NodegroupStatusActive,
+			ReleaseVersion: aws.String("1.30.0-20240703"),
+		}},
+		{Nodegroup: &ekstypes.Nodegroup{
+			NodegroupName:  aws.String("degraded"),
+			Status:         ekstypes.NodegroupStatusDegraded,
+			ReleaseVersion: aws.String("1.30.0-20240703"),
+			Health: &ekstypes.NodegroupHealth{Issues: []ekstypes.Issue{{
+				Code:        ekstypes.NodegroupIssueCodeAsgInstanceLaunchFailures,
+				Message:     aws.String("message"),
+				ResourceIds: []string{"asg"},
+			}}},
+		}},
+	}
+
+	asserts.True(setNodeGroupStatusesStatus(config, nodeGroupStates, nil))
+	asserts.Equal(map[string]eksv1.NodeGroupStatus{
+		"active": {Status: "ACTIVE", ReleaseVersion: "1.30.0-20240703"},
+		"degraded": {
+			Status:         "DEGRADED",
+			ReleaseVersion: "1.30.0-20240703",
+			Issues:         []eksv1.NodeGroupIssue{{Code: "AsgInstanceLaunchFailures", Message: "message", ResourceIDs: []string{"asg"}}},
+		},
+	}, config.Status.NodeGroupStatuses)
+	asserts.False(setNodeGroupStatusesStatus(config, nodeGroupStates, nil))
+
+	// node groups without ready nodes have none once the nodes were counted
+	asserts.True(setNodeGroupStatusesStatus(config, nodeGroupStates, map[string]int32{"active": 2}))
+	asserts.Equal(aws.Int32(2), config.Status.NodeGroupStatuses["active"].ReadyNodes)
+	asserts.Equal(aws.Int32(0), config.Status.NodeGroupStatuses["degraded"].ReadyNodes)
+	asserts.False(setNodeGroupStatusesStatus(config, nodeGroupStates, map[string]int32{"active": 2}))
+
+	asserts.True(setNodeGroupStatusesStatus(config, nil, nil))
+	asserts.Nil(config.Status.NodeGroupStatuses)
+	asserts.False(setNodeGroupStatusesStatus(config, nil, nil))
+	// statuses applied as empty are the same as none
+	config.Status.NodeGroupStatuses = map[string]eksv1.NodeGroupStatus{}
+	asserts.False(setNodeGroupStatusesStatus(config, nil, nil))
+}
+
+func TestTaggingDegradedStatus(t *testing.T) {
+	asserts := assert.New(t)
+	config := &eksv1.EKSClusterConfig{}
+
+	asserts.True(isAccessDenied(fmt.Errorf("error tagging: %w", &smithy.GenericAPIError{Code: "AccessDeniedException"})))
+	asserts.False(isAccessDenied(&ekstypes.ResourceInUseException{}))
+
+	asserts.False(setTaggingDegradedStatus(config, nil))
+	asserts.Empty(config.Status.Conditions)
+
+	asserts.True(setTaggingDegradedStatus(config, []string{"cluster", "node group ng1"}))
+	asserts.True(taggingDegraded.IsTrue(config))
+	asserts.Contains(taggingDegraded.GetMessage(config), "cluster, node group ng1")
+	asserts.False(setTaggingDegradedStatus(config, []string{"cluster", "node group ng1"}))
+
+	asserts.True(setTaggingDegradedStatus(config, nil))
+	asserts.True(taggingDegraded.IsFalse(config))
+	asserts.Empty(taggingDegraded.GetMessage(config))
+}
+
+func TestGetNodegroupsOutsideVersionSkew(t *testing.T) {
+	nodeGroups := []eksv1.NodeGroup{
+		{NodegroupName: aws.String("current"), Version: aws.String("1.30")},
+		{NodegroupName: aws.String("oldest-supported"), Version: aws.String("1.28")},
+		{NodegroupName: aws.String("too-old"), Version: aws.String("1.27")},
+		// the version of node groups that are being upgraded isn't known
+		{NodegroupName: aws.String("upgrading")},
+	}
+
+	assert.Empty(t, getNodegroupsOutsideVersionSkew(semver.MustParse("1.29.0"), nodeGroups))
+	assert.Equal(t, []string{"too-old (1.27)"}, getNodegroupsOutsideVersionSkew(semver.MustParse("1.30.0"), nodeGroups))
+	assert.Equal(t, []string{"oldest-supported (1.28)", "too-old (1.27)"}, getNodegroupsOutsideVersionSkew(semver.MustParse("1.31.0"), nodeGroups))
+}
+
+func TestNodeGroupVersionSkewStatus(t *testing.T) {
+	h := &Handler{}
+	config := &eksv1.EKSClusterConfig{}
+	assert.False(t, h.setNodeGroupVersionSkewStatus(config, "1.31", nil))
+	assert.Empty(t, config.Status.Conditions, "the condition is only added once an upgrade is blocked")
+
+	assert.True(t, h.setNodeGroupVersionSkewStatus(config, "1.31", []string{"ng (1.28)"}))
+	assert.True(t, nodeGroupVersionSkew.IsTrue(config))
+	assert.Equal(t, "not upgrading to kubernetes version 1.31, node groups [ng (1.28)] would be more than 2 minor versions older",
+		nodeGroupVersionSkew.GetMessage(config))
+	assert.False(t, h.setNodeGroupVersionSkewStatus(config, "1.31", []string{"ng (1.28)"}), "the event is only recorded once")
+	assert.True(t, h.setNodeGroupVersionSkewStatus(config, "1.31", []string{"ng (1.28)", "other (1.28)"}))
+
+	assert.True(t, h.setNodeGroupVersionSkewStatus(config, "1.31", nil))
+	assert.True(t, nodeGroupVersionSkew.IsFalse(config))
+	assert.Empty(t, nodeGroupVersionSkew.GetMessage(config))
+	assert.False(t, h.setNodeGroupVersionSkewStatus(config, "1.31", nil))
+}
+
+func TestForEachNodeGroup(t *testing.T) {
+	var nodeGroups []eksv1.NodeGroup
+	for i := 0; i < 3*maxConcurrentNodegroupOperations; i++ {
+		nodeGroups = append(nodeGroups, eksv1.NodeGroup{NodegroupName: aws.String(fmt.Sprintf("ng%d", i))})
+	}
+
+	var running, maxRunning int32
+	var mu sync.Mutex
+	fn := func(ng eksv1.NodeGroup) string {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return aws.ToString(ng.NodegroupName)
+	}
+	results := forEachNodeGroup(nodeGroups, fn)
+
+	assert.LessOrEqual(t, maxRunning, int32(maxConcurrentNodegroupOperations))
+	for i, result := range results {
+		assert.Equal(t, fmt.Sprintf("ng%d", i), result)
+	}
+	assert.Empty(t, forEachNodeGroup(nil, func(eksv1.NodeGroup) string { return "" }))
+
+	t.Cleanup(func() { require.NoError(t, features.Set("")) })
+	require.NoError(t, features.Set("ConcurrentNodegroups=false"))
+	maxRunning = 0
+	assert.Len(t, forEachNodeGroup(nodeGroups, fn), len(nodeGroups))
+	assert.Equal(t, int32(1), maxRunning)
+}
+
+func TestImportNodeGroupSelector(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{Imported: true}}
+	assert.True(t, nodeGroupSelected(config, "karpenter", nil), "all node groups are selected without a selector")
+
+	config.Spec.ImportNodeGroupSelector = &eksv1.NodeGroupSelector{
+		Names:       []string{"team-a-*", "system"},
+		MatchLabels: map[string]string{"managed-by": "rancher"},
+	}
+	assert.True(t, nodeGroupSelected(config, "team-a-workers", map[string]string{"managed-by": "rancher", "team": "a"}))
+	assert.True(t, nodeGroupSelected(config, "system", map[string]string{"managed-by": "rancher"}))
+	assert.False(t, nodeGroupSelected(config, "team-b-workers", map[string]string{"managed-by": "rancher"}))
+	assert.False(t, nodeGroupSelected(config, "team-a-workers", map[string]string{"managed-by": "karpenter"}))
+	assert.False(t, nodeGroupSelected(config, "team-a-workers", nil))
+
+	config.Spec.NodeGroups = []eksv1.NodeGroup{
+		{NodegroupName: aws.String("team-a-workers"), Labels: map[string]*string{"managed-by": aws.String("rancher")}},
+	}
+	assert.NoError(t, validateImportNodeGroupSelector(config))
+	// unselected node groups of the spec are ignored instead of failing the validation
+	selected := config.Spec.NodeGroups
+	config.Spec.NodeGroups = append(slices.Clone(selected), eksv1.NodeGroup{NodegroupName: aws.String("team-b-workers")})
+	assert.NoError(t, validateImportNodeGroupSelector(config))
+	assert.Equal(t, selected, selectedNodeGroups(config))
+	config.Spec.NodeGroups = nil
+	assert.Nil(t, selectedNodeGroups(config))
+
+	config.Spec.ImportNodeGroupSelector.Names = []string{"team-[a"}
+	assert.Error(t, validateImportNodeGroupSelector(config))
+
+	// clusters created by the operator manage all of their node groups
+	config.Spec.Imported = false
+	assert.True(t, nodeGroupSelected(config, "team-b-workers", nil))
+	assert.Error(t, validateImportNodeGroupSelector(config))
+}
@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	awsservices "github.com/rancher/eks-operator/pkg/eks"
+)
+
+// types of the resources in the owned resources manifest of a cluster
+const (
+	ownedResourceCluster             = "cluster"
+	ownedResourceNodegroup           = "nodegroup"
+	ownedResourceLaunchTemplate      = "launchTemplate"
+	ownedResourceCloudFormationStack = "cloudFormationStack"
+	ownedResourceIAMRole             = "iamRole"
+	ownedResourceOIDCProvider        = "oidcProvider"
+	ownedResourceVPC                 = "vpc"
+	ownedResourceSubnet              = "subnet"
+	ownedResourceSecurityGroup       = "securityGroup"
+	ownedResourceMetricFilter        = "metricFilter"
+	ownedResourceAlarm               = "alarm"
+)
+
+// getOwnedResources returns the AWS resources the operator created for the cluster, as recorded on its status, along
+// with the upstream node groups whose ARNs are given keyed by node group name. The cluster and its node groups are
+// not listed for imported clusters. ARNs that aren't recorded are derived from the ARN of the cluster.
+func getOwnedResources(config *eksv1.EKSClusterConfig, nodegroupARNs map[string]string) []eksv1.OwnedResource {
+	clusterARN, err := arn.Parse(config.Status.ClusterARN)
+	resourceARN := func(service, resource string, global bool) string {
+		if err != nil {
+			return ""
+		}
+		region := clusterARN.Region
+		if global {
+			region = ""
+		}
+		return arn.ARN{Partition: clusterARN.Partition, Service: service, Region: region, AccountID: clusterARN.AccountID, Resource: resource}.String()
+	}
+
+	var resources []eksv1.OwnedResource
+	if !config.Spec.Imported {
+		resources = append(resources, eksv1.OwnedResource{Type: ownedResourceCluster, ID: config.Spec.DisplayName, ARN: config.Status.ClusterARN})
+		names := make([]string, 0, len(nodegroupARNs))
+		for name := range nodegroupARNs {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			resources = append(resources, eksv1.OwnedResource{Type: ownedResourceNodegroup, ID: name, ARN: nodegroupARNs[name]})
+		}
+	}
+	if id := config.Status.ManagedLaunchTemplateID; id != "" {
+		resources = append(resources, eksv1.OwnedResource{Type: ownedResourceLaunchTemplate, ID: id, ARN: resourceARN("ec2", "launch-template/"+id, false)})
+	}
+	for _, stack := range config.Status.CloudFormationStacks {
+		resources = append(resources, eksv1.OwnedResource{Type: ownedResourceCloudFormationStack, ID: stack.Name, ARN: stack.ID})
+	}
+	if config.Status.ProvisioningBackend == awsservices.ProvisioningBackendNative && aws.ToString(config.Spec.ServiceRole) == "" && !config.Spec.Imported {
+		roleName := getServiceRoleName(config.Spec.DisplayName)
+		resources = append(resources, eksv1.OwnedResource{Type: ownedResourceIAMRole, ID: roleName, ARN: resourceARN("iam", "role/"+roleName, true)})
+	}
+	if roleARN := config.Status.GeneratedNodeRole; roleARN != "" {
+		resources = append(resources, eksv1.OwnedResource{Type: ownedResourceIAMRole, ID: roleARN[strings.LastIndex(roleARN, "/")+1:], ARN: roleARN})
+	}
+	if providerARN := config.Status.OIDCProviderARN; providerARN != "" {
+		resources = append(resources, eksv1.OwnedResource{Type: ownedResourceOIDCProvider, ID: providerARN[strings.LastIndex(providerARN, "/")+1:], ARN: providerARN})
+	}
+	if config.Status.NetworkFieldsSource == "generated" {
+		if vpc := config.Status.VirtualNetwork; vpc != "" {
+			resources = append(resources, eksv1.OwnedResource{Type: ownedResourceVPC, ID: vpc, ARN: resourceARN("ec2", "vpc/"+vpc, false)})
+		}
+		for _, subnet := range config.Status.Subnets {
+			resources = append(resources, eksv1.OwnedResource{Type: ownedResourceSubnet, ID: subnet, ARN: resourceARN("ec2", "subnet/"+subnet, false)})
+		}
+		for _, securityGroup := range config.Status.SecurityGroups {
+			resources = append(resources, eksv1.OwnedResource{Type: ownedResourceSecurityGroup, ID: securityGroup, ARN: resourceARN("ec2", "security-group/"+securityGroup, false)})
+		}
+	}
+	if auditLogging := config.Status.AuditLogging; auditLogging != nil {
+		name := awsservices.AuditResourceName(config.Spec.DisplayName)
+		resources = append(resources, eksv1.OwnedResource{Type: ownedResourceMetricFilter, ID: name})
+		if auditLogging.AlarmThreshold != 0 {
+			resources = append(resources, eksv1.OwnedResource{Type: ownedResourceAlarm, ID: name, ARN: resourceARN("cloudwatch", "alarm:"+name, false)})
+		}
+	}
+
+	return resources
+}
+
+// setOwnedResourcesStatus records the owned resources manifest of the cluster in the status and returns whether it
+// changed.
+func setOwnedResourcesStatus(config *eksv1.EKSClusterConfig, nodegroupARNs map[string]string) bool {
+	resources := getOwnedResources(config, nodegroupARNs)
+	if slices.Equal(config.Status.OwnedResources, resources) {
+		return false
+	}
+	config.Status.OwnedResources = resources
+	return true
+}
@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	awsservices "github.com/rancher/eks-operator/pkg/eks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetOwnedResources(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{
+		Spec: eksv1.EKSClusterConfigSpec{DisplayName: "test"},
+		Status: eksv1.EKSClusterConfigStatus{
+			ClusterARN:              "arn:aws:eks:us-east-1:123456789012:cluster/test",
+			ManagedLaunchTemplateID: "lt-1",
+			CloudFormationStacks: []eksv1.CloudFormationStack{
+				{Name: "test-eks-vpc", ID: "arn:aws:cloudformation:us-east-1:123456789012:stack/test-eks-vpc/1"},
+			},
+			ProvisioningBackend: awsservices.ProvisioningBackendNative,
+			GeneratedNodeRole:   "arn:aws:iam::123456789012:role/test-node-instance-role",
+			NetworkFieldsSource: "generated",
+			VirtualNetwork:      "vpc-1",
+			Subnets:             []string{"subnet-1"},
+			AuditLogging:        &eksv1.AuditLogging{AlarmThreshold: 1},
+		},
+	}
+	nodegroupARNs := map[string]string{
+		"ng2": "arn:aws:eks:us-east-1:123456789012:nodegroup/test/ng2/1",
+		"ng1": "arn:aws:eks:us-east-1:123456789012:nodegroup/test/ng1/1",
+	}
+
+	assert.Equal(t, []eksv1.OwnedResource{
+		{Type: "cluster", ID: "test", ARN: "arn:aws:eks:us-east-1:123456789012:cluster/test"},
+		{Type: "nodegroup", ID: "ng1", ARN: "arn:aws:eks:us-east-1:123456789012:nodegroup/test/ng1/1"},
+		{Type: "nodegroup", ID: "ng2", ARN: "arn:aws:eks:us-east-1:123456789012:nodegroup/test/ng2/1"},
+		{Type: "launchTemplate", ID: "lt-1", ARN: "arn:aws:ec2:us-east-1:123456789012:launch-template/lt-1"},
+		{Type: "cloudFormationStack", ID: "test-eks-vpc", ARN: "arn:aws:cloudformation:us-east-1:123456789012:stack/test-eks-vpc/1"},
+		{Type: "iamRole", ID: "test-eks-service-role", ARN: "arn:aws:iam::123456789012:role/test-eks-service-role"},
+		{Type: "iamRole", ID: "test-node-instance-role", ARN: "arn:aws:iam::123456789012:role/test-node-instance-role"},
+		{Type: "vpc", ID: "vpc-1", ARN: "arn:aws:ec2:us-east-1:123456789012:vpc/vpc-1"},
+		{Type: "subnet", ID: "subnet-1", ARN: "arn:aws:ec2:us-east-1:123456789012:subnet/subnet-1"},
+		{Type: "metricFilter", ID: "test-audit-events"},
+		{Type: "alarm", ID: "test-audit-events", ARN: "arn:aws:cloudwatch:us-east-1:123456789012:alarm:test-audit-events"},
+	}, getOwnedResources(config, nodegroupARNs))
+
+	config.Spec.Imported = true
+	config.Spec.ServiceRole = aws.String("arn:aws:iam::123456789012:role/provided")
+	config.Status = eksv1.EKSClusterConfigStatus{ManagedLaunchTemplateID: "lt-1"}
+	assert.Equal(t, []eksv1.OwnedResource{{Type: "launchTemplate", ID: "lt-1"}}, getOwnedResources(config, nodegroupARNs),
+		"imported clusters and their node groups aren't owned, ARNs can't be derived without the cluster ARN")
+}
+
+func TestSetOwnedResourcesStatus(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{DisplayName: "test"}}
+
+	assert.True(t, setOwnedResourcesStatus(config, nil))
+	assert.Equal(t, []eksv1.OwnedResource{{Type: "cluster", ID: "test"}}, config.Status.OwnedResources)
+	assert.False(t, setOwnedResourcesStatus(config, nil))
+	assert.True(t, setOwnedResourcesStatus(config, map[string]string{"ng1": "arn"}))
+}
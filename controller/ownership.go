@@ -0,0 +1,26 @@
+package controller
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	awsservices "github.com/rancher/eks-operator/pkg/eks"
+)
+
+// getClusterTags returns the tags of the cluster in EKS, the tags of the spec with the ownership tags added. Imported
+// clusters weren't created by the operator and keep the tags of the spec, nil tags leave the cluster untouched.
+func getClusterTags(config *eksv1.EKSClusterConfig) map[string]string {
+	if config.Spec.Tags == nil || config.Spec.Imported {
+		return config.Spec.Tags
+	}
+	return awsservices.WithOwnershipTags(config, config.Spec.Tags)
+}
+
+// applyOwnershipTags returns the node group with the ownership tags added to its tags, the same way as the tags of the
+// cluster. The tags are copied, so the spec is left untouched.
+func applyOwnershipTags(config *eksv1.EKSClusterConfig, ng eksv1.NodeGroup) eksv1.NodeGroup {
+	if ng.Tags == nil || config.Spec.Imported {
+		return ng
+	}
+	ng.Tags = aws.StringMap(awsservices.WithOwnershipTags(config, aws.ToStringMap(ng.Tags)))
+	return ng
+}
@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	awsservices "github.com/rancher/eks-operator/pkg/eks"
+)
+
+func TestGetClusterTags(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "cattle-global-data", UID: "1234"},
+		Spec:       eksv1.EKSClusterConfigSpec{DisplayName: "test", Tags: map[string]string{"team": "platform"}},
+	}
+
+	assert.Equal(t, map[string]string{
+		"team":                        "platform",
+		awsservices.ManagedTagKey:     "true",
+		awsservices.ClusterNameTagKey: "test",
+		awsservices.NamespaceTagKey:   "cattle-global-data",
+		awsservices.UIDTagKey:         "1234",
+	}, getClusterTags(config))
+	assert.Equal(t, map[string]string{"team": "platform"}, config.Spec.Tags, "the spec is left untouched")
+
+	config.Spec.Imported = true
+	assert.Equal(t, map[string]string{"team": "platform"}, getClusterTags(config), "imported clusters aren't owned")
+
+	config.Spec.Imported = false
+	config.Spec.Tags = nil
+	assert.Nil(t, getClusterTags(config), "nil tags leave the cluster untouched")
+}
+
+func TestApplyOwnershipTags(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{DisplayName: "test"}}
+	ng := eksv1.NodeGroup{NodegroupName: aws.String("ng1"), Tags: map[string]*string{"team": aws.String("platform")}}
+
+	assert.Equal(t, map[string]string{
+		"team":                        "platform",
+		awsservices.ManagedTagKey:     "true",
+		awsservices.ClusterNameTagKey: "test",
+	}, aws.ToStringMap(applyOwnershipTags(config, ng).Tags))
+	assert.Len(t, ng.Tags, 1, "the spec is left untouched")
+
+	assert.Nil(t, applyOwnershipTags(config, eksv1.NodeGroup{NodegroupName: aws.String("ng2")}).Tags)
+}
@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	awsservices "github.com/rancher/eks-operator/pkg/eks"
+	"github.com/sirupsen/logrus"
+)
+
+// validatePodIdentityAssociations checks that all pod identity associations are complete and that each service account
+// is associated with a single role.
+func validatePodIdentityAssociations(config *eksv1.EKSClusterConfig) error {
+	serviceAccounts := make(map[string]struct{}, len(config.Spec.PodIdentityAssociations))
+	for _, association := range config.Spec.PodIdentityAssociations {
+		if association.Namespace == "" || association.ServiceAccount == "" || association.RoleARN == "" {
+			return fmt.Errorf("namespace, serviceAccount and roleArn are required for pod identity associations of cluster [%s (id: %s)]",
+				config.Spec.DisplayName, config.Name)
+		}
+		key := association.Namespace + "/" + association.ServiceAccount
+		if _, ok := serviceAccounts[key]; ok {
+			return fmt.Errorf("service account [%s] has more than one pod identity association in cluster [%s (id: %s)]",
+				key, config.Spec.DisplayName, config.Name)
+		}
+		serviceAccounts[key] = struct{}{}
+	}
+
+	return nil
+}
+
+// updatePodIdentity installs the EKS Pod Identity agent add-on if there are pod identity associations in the spec and
+// reconciles the associations. It returns whether anything was changed upstream.
+func updatePodIdentity(ctx context.Context, config *eksv1.EKSClusterConfig, awsSVCs *awsServices) (bool, error) {
+	if len(config.Spec.PodIdentityAssociations) != 0 {
+		installedArn, err := awsservices.CheckPodIdentityAgentAddon(ctx, config.Spec.DisplayName, awsSVCs.eks)
+		if err != nil {
+			return false, fmt.Errorf("error checking if pod identity agent addon is installed: %w", err)
+		}
+		if installedArn == "" {
+			logrus.Infof("Enabling [pod identity agent add-on] for cluster [%s (id: %s)]", config.Spec.DisplayName, config.Name)
+			if err := awsservices.InstallPodIdentityAgentAddon(ctx, awsSVCs.eks, config); err != nil {
+				return false, err
+			}
+			return true, nil
+		}
+	}
+
+	if config.Spec.PodIdentityAssociations == nil {
+		return false, nil
+	}
+	upstreamAssociations, err := awsservices.GetPodIdentityAssociations(ctx, &awsservices.GetPodIdentityAssociationsOpts{
+		EKSService:  awsSVCs.eks,
+		ClusterName: config.Spec.DisplayName,
+	})
+	if err != nil {
+		return false, fmt.Errorf("error getting pod identity associations: %w", err)
+	}
+
+	return awsservices.UpdatePodIdentityAssociations(ctx, &awsservices.UpdatePodIdentityAssociationsOpts{
+		EKSService:           awsSVCs.eks,
+		Config:               config,
+		UpstreamAssociations: upstreamAssociations,
+	})
+}
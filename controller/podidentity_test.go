@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"testing"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePodIdentityAssociations(t *testing.T) {
+	tests := []struct {
+		name         string
+		associations []eksv1.PodIdentityAssociation
+		expectErr    bool
+	}{
+		{
+			name: "no associations",
+		},
+		{
+			name: "valid associations",
+			associations: []eksv1.PodIdentityAssociation{
+				{Namespace: "ns", ServiceAccount: "sa1", RoleARN: "role"},
+				{Namespace: "ns", ServiceAccount: "sa2", RoleARN: "role"},
+			},
+		},
+		{
+			name:         "missing role",
+			associations: []eksv1.PodIdentityAssociation{{Namespace: "ns", ServiceAccount: "sa"}},
+			expectErr:    true,
+		},
+		{
+			name: "duplicate service account",
+			associations: []eksv1.PodIdentityAssociation{
+				{Namespace: "ns", ServiceAccount: "sa", RoleARN: "role1"},
+				{Namespace: "ns", ServiceAccount: "sa", RoleARN: "role2"},
+			},
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{PodIdentityAssociations: tt.associations}}
+			err := validatePodIdentityAssociations(config)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
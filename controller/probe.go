@@ -0,0 +1,230 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	awsservices "github.com/rancher/eks-operator/pkg/eks"
+	"github.com/rancher/eks-operator/pkg/features"
+)
+
+const (
+	// timeout of each request the operator sends to the API server of the cluster
+	downstreamProbeTimeout = 10 * time.Second
+	// how often the downstream health probe is retried while it fails
+	downstreamProbeInterval = 5 * time.Minute
+
+	coreDNSNamespace     = "kube-system"
+	coreDNSLabelSelector = "k8s-app=kube-dns"
+)
+
+// setDownstreamHealthyStatus probes the downstream cluster and sets the DownstreamHealthy condition from the result,
+// returning whether it changed. A warning event is recorded when the probe starts failing. Nothing is probed if the
+// DownstreamProbe feature is disabled.
+func (h *Handler) setDownstreamHealthyStatus(ctx context.Context, config *eksv1.EKSClusterConfig, awsSVCs *awsServices) bool {
+	if !features.Enabled(features.DownstreamProbe) {
+		return false
+	}
+
+	status, message := string(corev1.ConditionTrue), ""
+	if err := h.probeDownstream(ctx, config, awsSVCs); err != nil {
+		logrus.Warnf("Downstream health probe of cluster [%s (id: %s)] failed: %v", config.Spec.DisplayName, config.Name, err)
+		status, message = string(corev1.ConditionFalse), err.Error()
+	}
+	if downstreamHealthy.GetStatus(config) == status && downstreamHealthy.GetMessage(config) == message {
+		return false
+	}
+
+	if status == string(corev1.ConditionFalse) && !downstreamHealthy.IsFalse(config) {
+		h.recordEvent(config, corev1.EventTypeWarning, eventReasonDownstreamUnhealthy, "Cluster [%s] is active but unusable: %s",
+			config.Spec.DisplayName, message)
+	}
+	downstreamHealthy.SetStatus(config, status)
+	downstreamHealthy.Message(config, message)
+	return true
+}
+
+// reprobeDownstream probes active clusters that haven't been probed yet or failed their last probe, and keeps retrying
+// every downstreamProbeInterval until the probe succeeds.
+func (h *Handler) reprobeDownstream(ctx context.Context, config *eksv1.EKSClusterConfig, awsSVCs *awsServices) (*eksv1.EKSClusterConfig, error) {
+	if downstreamHealthy.IsTrue(config) || !features.Enabled(features.DownstreamProbe) {
+		return config, nil
+	}
+
+	if updatedConfig := config.DeepCopy(); h.setDownstreamHealthyStatus(ctx, updatedConfig, awsSVCs) {
+		var err error
+		config, err = h.updateStatus(updatedConfig)
+		if err != nil {
+			return config, err
+		}
+	}
+	if !downstreamHealthy.IsTrue(config) {
+		h.eksEnqueueAfter(config.Namespace, config.Name, downstreamProbeInterval)
+	}
+	return config, nil
+}
+
+// probeDownstream checks that the API server of the cluster is reachable with credentials generated by the operator
+// from the stored endpoint and certificate authority, which catches private endpoints the operator can't reach and
+// access entries or aws-auth mappings it isn't part of.
+func (h *Handler) probeDownstream(ctx context.Context, config *eksv1.EKSClusterConfig, awsSVCs *awsServices) error {
+	client, err := h.downstreamClient(ctx, config, awsSVCs)
+	if err != nil {
+		return err
+	}
+
+	// node groups created outside of the config can't be known, so coredns is only required to be scheduled once the
+	// config has node groups
+	return checkDownstream(ctx, client, len(config.Spec.NodeGroups) != 0)
+}
+
+// downstreamClient returns a client for the API server of the cluster with a token generated by the operator, from the
+// endpoint and certificate authority stored in the secret of the config.
+func (h *Handler) downstreamClient(ctx context.Context, config *eksv1.EKSClusterConfig, awsSVCs *awsServices) (kubernetes.Interface, error) {
+	secret, err := h.secretsCache.Get(config.Namespace, config.Name)
+	if err != nil {
+		return nil, fmt.Errorf("error getting endpoint and certificate authority: %w", err)
+	}
+	ca, err := base64.StdEncoding.DecodeString(string(secret.Data["ca"]))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding certificate authority: %w", err)
+	}
+
+	token, err := awsservices.GetClusterToken(ctx, &awsservices.GetClusterTokenOpts{
+		STSService:  awsSVCs.sts,
+		ClusterName: config.Spec.DisplayName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error generating token: %w", err)
+	}
+
+	client, err := kubernetes.NewForConfig(&rest.Config{
+		Host:            string(secret.Data["endpoint"]),
+		BearerToken:     token,
+		TLSClientConfig: downstreamTLSClientConfig(config, ca),
+		Timeout:         downstreamProbeTimeout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating client: %w", err)
+	}
+	return client, nil
+}
+
+// getReadyNodeCounts returns the number of Ready nodes of each node group in the downstream cluster, counted by the
+// node group label of the nodes. The nodes are listed at most once per informationalCheckInterval, the counts recorded
+// on the status are returned in between and if the nodes can't be listed. Nil is returned if the DownstreamNodeCounts
+// feature is disabled.
+func (h *Handler) getReadyNodeCounts(ctx context.Context, config *eksv1.EKSClusterConfig, awsSVCs *awsServices) map[string]int32 {
+	if !features.Enabled(features.DownstreamNodeCounts) {
+		return nil
+	}
+	if !h.checks.due(config, checkReadyNodes, time.Now()) {
+		return getRecordedReadyNodeCounts(config)
+	}
+
+	client, err := h.downstreamClient(ctx, config, awsSVCs)
+	if err != nil {
+		logrus.Warnf("Could not count the ready nodes of cluster [%s (id: %s)]: %v", config.Spec.DisplayName, config.Name, err)
+		return getRecordedReadyNodeCounts(config)
+	}
+	counts, err := countReadyNodes(ctx, client)
+	if err != nil {
+		logrus.Warnf("Could not count the ready nodes of cluster [%s (id: %s)]: %v", config.Spec.DisplayName, config.Name, err)
+		return getRecordedReadyNodeCounts(config)
+	}
+	return counts
+}
+
+// getRecordedReadyNodeCounts returns the ready nodes of the node groups recorded on the status, or nil if they were
+// never counted.
+func getRecordedReadyNodeCounts(config *eksv1.EKSClusterConfig) map[string]int32 {
+	var counts map[string]int32
+	for name, status := range config.Status.NodeGroupStatuses {
+		if status.ReadyNodes == nil {
+			continue
+		}
+		if counts == nil {
+			counts = make(map[string]int32, len(config.Status.NodeGroupStatuses))
+		}
+		counts[name] = *status.ReadyNodes
+	}
+	return counts
+}
+
+// countReadyNodes returns the number of nodes with the Ready condition for each node group label value.
+func countReadyNodes(ctx context.Context, client kubernetes.Interface) (map[string]int32, error) {
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: nodeGroupLabel})
+	if err != nil {
+		return nil, fmt.Errorf("error listing nodes: %w", err)
+	}
+
+	counts := make(map[string]int32)
+	for _, node := range nodes.Items {
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
+				counts[node.Labels[nodeGroupLabel]]++
+				break
+			}
+		}
+	}
+	return counts, nil
+}
+
+// downstreamTLSClientConfig returns the TLS settings for the API server of the cluster, trusting its certificate
+// authority along with the CA bundle of the config and verifying the certificate against its server name if set.
+func downstreamTLSClientConfig(config *eksv1.EKSClusterConfig, ca []byte) rest.TLSClientConfig {
+	tlsConfig := rest.TLSClientConfig{CAData: ca}
+	if downstreamTLS := config.Spec.DownstreamTLS; downstreamTLS != nil {
+		tlsConfig.ServerName = downstreamTLS.ServerName
+		if downstreamTLS.CABundle != "" {
+			tlsConfig.CAData = slices.Concat(bytes.TrimSpace(ca), []byte("\n"), []byte(downstreamTLS.CABundle))
+		}
+	}
+	return tlsConfig
+}
+
+// validateDownstreamTLS checks that the CA bundle of the config contains PEM encoded certificates.
+func validateDownstreamTLS(config *eksv1.EKSClusterConfig) error {
+	if config.Spec.DownstreamTLS == nil || config.Spec.DownstreamTLS.CABundle == "" {
+		return nil
+	}
+	if !x509.NewCertPool().AppendCertsFromPEM([]byte(config.Spec.DownstreamTLS.CABundle)) {
+		return fmt.Errorf("downstream CA bundle of cluster [%s (id: %s)] contains no PEM encoded certificates",
+			config.Spec.DisplayName, config.Name)
+	}
+	return nil
+}
+
+// checkDownstream checks that the API server is reachable and, if requireDNS is set, that at least one coredns pod has
+// been scheduled to a node.
+func checkDownstream(ctx context.Context, client kubernetes.Interface, requireDNS bool) error {
+	if _, err := client.Discovery().ServerVersion(); err != nil {
+		return fmt.Errorf("API server is not reachable: %w", err)
+	}
+	if !requireDNS {
+		return nil
+	}
+
+	pods, err := client.CoreV1().Pods(coreDNSNamespace).List(ctx, metav1.ListOptions{LabelSelector: coreDNSLabelSelector})
+	if err != nil {
+		return fmt.Errorf("error listing coredns pods: %w", err)
+	}
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName != "" {
+			return nil
+		}
+	}
+	return fmt.Errorf("no coredns pods are scheduled")
+}
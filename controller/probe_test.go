@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/features"
+)
+
+func TestCheckDownstream(t *testing.T) {
+	ctx := context.Background()
+	coreDNSPod := func(name, nodeName string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: coreDNSNamespace, Labels: map[string]string{"k8s-app": "kube-dns"}},
+			Spec:       corev1.PodSpec{NodeName: nodeName},
+		}
+	}
+
+	assert.NoError(t, checkDownstream(ctx, fake.NewSimpleClientset(), false))
+	assert.ErrorContains(t, checkDownstream(ctx, fake.NewSimpleClientset(), true), "no coredns pods are scheduled")
+	assert.ErrorContains(t, checkDownstream(ctx, fake.NewSimpleClientset(coreDNSPod("coredns-1", "")), true), "no coredns pods are scheduled")
+	assert.NoError(t, checkDownstream(ctx, fake.NewSimpleClientset(coreDNSPod("coredns-1", ""), coreDNSPod("coredns-2", "node")), true))
+}
+
+func TestCountReadyNodes(t *testing.T) {
+	node := func(name, nodeGroup string, ready corev1.ConditionStatus) *corev1.Node {
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{}},
+			Status:     corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}}},
+		}
+		if nodeGroup != "" {
+			node.Labels[nodeGroupLabel] = nodeGroup
+		}
+		return node
+	}
+
+	counts, err := countReadyNodes(context.Background(), fake.NewSimpleClientset(
+		node("ng1-a", "ng1", corev1.ConditionTrue),
+		node("ng1-b", "ng1", corev1.ConditionTrue),
+		node("ng1-c", "ng1", corev1.ConditionUnknown),
+		node("ng2-a", "ng2", corev1.ConditionFalse),
+		node("self-managed", "", corev1.ConditionTrue),
+	))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int32{"ng1": 2}, counts)
+}
+
+func TestGetReadyNodeCounts(t *testing.T) {
+	t.Cleanup(func() { require.NoError(t, features.Set("")) })
+	require.NoError(t, features.Set("DownstreamNodeCounts=true"))
+
+	h := &Handler{checks: newCheckThrottle(time.Minute)}
+	config := &eksv1.EKSClusterConfig{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "test"}}
+	config.Status.NodeGroupStatuses = map[string]eksv1.NodeGroupStatus{
+		"ng1": {Status: "ACTIVE", ReadyNodes: aws.Int32(2)},
+		"ng2": {Status: "ACTIVE", ReadyNodes: aws.Int32(0)},
+	}
+
+	// the recorded counts are kept until the nodes are listed again
+	assert.True(t, h.checks.due(config, checkReadyNodes, time.Now()))
+	assert.Equal(t, map[string]int32{"ng1": 2, "ng2": 0}, h.getReadyNodeCounts(context.Background(), config, nil))
+
+	config.Status.NodeGroupStatuses = map[string]eksv1.NodeGroupStatus{"ng1": {Status: "ACTIVE"}}
+	assert.Nil(t, h.getReadyNodeCounts(context.Background(), config, nil), "nodes that were never counted stay unknown")
+
+	require.NoError(t, features.Set(""))
+	config.Status.NodeGroupStatuses = map[string]eksv1.NodeGroupStatus{"ng1": {Status: "ACTIVE", ReadyNodes: aws.Int32(2)}}
+	assert.Nil(t, h.getReadyNodeCounts(context.Background(), config, nil), "nodes aren't counted if disabled")
+}
+
+func TestDownstreamTLS(t *testing.T) {
+	server := httptest.NewTLSServer(nil)
+	defer server.Close()
+	proxyCA := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	config := &eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{DisplayName: "test"}}
+	assert.NoError(t, validateDownstreamTLS(config))
+	assert.Equal(t, rest.TLSClientConfig{CAData: []byte("ca")}, downstreamTLSClientConfig(config, []byte("ca")))
+
+	config.Spec.DownstreamTLS = &eksv1.DownstreamTLS{ServerName: "api.test.internal", CABundle: proxyCA}
+	assert.NoError(t, validateDownstreamTLS(config))
+	tlsConfig := downstreamTLSClientConfig(config, []byte("ca\n"))
+	assert.Equal(t, "api.test.internal", tlsConfig.ServerName)
+	assert.Equal(t, "ca\n"+proxyCA, string(tlsConfig.CAData))
+
+	config.Spec.DownstreamTLS.CABundle = "not a certificate"
+	assert.Error(t, validateDownstreamTLS(config))
+}
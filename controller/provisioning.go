@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	awsservices "github.com/rancher/eks-operator/pkg/eks"
+)
+
+// cloudFormationDenialsBeforeFallback is how many stack operations of a config CloudFormation has to deny before the
+// roles of the config are created through IAM instead, so that a denial while the permissions of the credentials are
+// still being set up doesn't switch the config to native provisioning for good.
+const cloudFormationDenialsBeforeFallback = 3
+
+// denialCounter counts the CloudFormation denials of each config in memory.
+type denialCounter struct {
+	sync.Mutex
+	counts map[string]int
+}
+
+func newDenialCounter() *denialCounter {
+	return &denialCounter{counts: make(map[string]int)}
+}
+
+// add records a denial for the config with the given key and returns how many were recorded. Every denial counts as
+// the last one allowed if there is no counter.
+func (c *denialCounter) add(key string) int {
+	if c == nil {
+		return cloudFormationDenialsBeforeFallback
+	}
+	c.Lock()
+	defer c.Unlock()
+	c.counts[key]++
+	return c.counts[key]
+}
+
+// forget drops the denials of the config with the given key.
+func (c *denialCounter) forget(key string) {
+	if c == nil {
+		return
+	}
+	c.Lock()
+	defer c.Unlock()
+	delete(c.counts, key)
+}
+
+// shouldFallBackToNativeProvisioning returns whether the stack operation of the config that failed with err means that
+// its roles have to be created through IAM. That is the case right away if CloudFormation isn't supported, and once
+// CloudFormation denied the operations of the config cloudFormationDenialsBeforeFallback times in a row if the
+// credentials aren't allowed to use it.
+func (h *Handler) shouldFallBackToNativeProvisioning(config *eksv1.EKSClusterConfig, err error) bool {
+	key := config.Namespace + "/" + config.Name
+	if awsservices.CloudFormationUnsupported(err) {
+		return true
+	}
+	if !awsservices.CloudFormationDenied(err) {
+		return false
+	}
+	denials := h.cloudFormationDenials.add(key)
+	if denials < cloudFormationDenialsBeforeFallback {
+		logrus.Warnf("CloudFormation denied a stack operation of cluster [%s (id: %s)] (%d of %d before falling back to IAM): %v",
+			config.Spec.DisplayName, config.Name, denials, cloudFormationDenialsBeforeFallback, err)
+		return false
+	}
+	h.cloudFormationDenials.forget(key)
+	return true
+}
+
+// fallBackToNativeProvisioning records on the status that the roles of the config are created through IAM directly
+// from now on, because the stack operation failed with err as CloudFormation is unavailable.
+func (h *Handler) fallBackToNativeProvisioning(config *eksv1.EKSClusterConfig, err error) {
+	logrus.Warnf("CloudFormation is unavailable for cluster [%s (id: %s)], creating its roles through IAM: %v",
+		config.Spec.DisplayName, config.Name, err)
+	h.recordEvent(config, corev1.EventTypeWarning, eventReasonCloudFormationUnavailable,
+		"CloudFormation is unavailable, roles of cluster [%s] are created through IAM", config.Spec.DisplayName)
+	config.Status.ProvisioningBackend = awsservices.ProvisioningBackendNative
+}
@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	cftypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/smithy-go"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	awsservices "github.com/rancher/eks-operator/pkg/eks"
+	"github.com/rancher/eks-operator/pkg/eks/services/mock_services"
+)
+
+func TestShouldFallBackToNativeProvisioning(t *testing.T) {
+	h := &Handler{cloudFormationDenials: newDenialCounter()}
+	config := &eksv1.EKSClusterConfig{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "test"}}
+	denied := &smithy.GenericAPIError{
+		Code:    "AccessDenied",
+		Message: "User: arn:aws:iam::123456789012:user/test is not authorized to perform: cloudformation:CreateStack",
+	}
+
+	// credential errors never switch to native provisioning
+	for i := 0; i < cloudFormationDenialsBeforeFallback; i++ {
+		assert.False(t, h.shouldFallBackToNativeProvisioning(config, &smithy.GenericAPIError{Code: "InvalidClientTokenId"}))
+	}
+
+	for i := 1; i < cloudFormationDenialsBeforeFallback; i++ {
+		assert.False(t, h.shouldFallBackToNativeProvisioning(config, denied))
+	}
+	assert.True(t, h.shouldFallBackToNativeProvisioning(config, denied))
+	// the denials are counted again from the start
+	assert.False(t, h.shouldFallBackToNativeProvisioning(config, denied))
+
+	assert.True(t, h.shouldFallBackToNativeProvisioning(config, &smithy.GenericAPIError{Code: "OptInRequired"}))
+	assert.True(t, (&Handler{}).shouldFallBackToNativeProvisioning(config, denied))
+}
+
+func TestUpdateRolePolicies(t *testing.T) {
+	ctx := context.Background()
+	mockController := gomock.NewController(t)
+	cfMock := mock_services.NewMockCloudFormationServiceInterface(mockController)
+	iamMock := mock_services.NewMockIAMServiceInterface(mockController)
+	awsSVCs := &awsServices{cloudformation: cfMock, iam: iamMock}
+	h := &Handler{}
+
+	config := &eksv1.EKSClusterConfig{
+		Spec: eksv1.EKSClusterConfigSpec{DisplayName: "test"},
+		Status: eksv1.EKSClusterConfigStatus{
+			CloudFormationStacks: []eksv1.CloudFormationStack{{Name: "test-eks-service-role", ID: "arn:aws:cloudformation:us-west-2:123456789012:stack/test-eks-service-role/1"}},
+		},
+	}
+	policies := &rolePolicies{
+		stackName:  "test-eks-service-role",
+		template:   awsservices.TemplateServiceRole,
+		roleARN:    "arn:aws:iam::123456789012:role/test-eks-service-role",
+		policyARNs: []string{"arn:aws:iam::aws:policy/AmazonEKSComputePolicy"},
+		attached:   []string{"arn:aws:iam::aws:policy/AmazonEKSNetworkingPolicy"},
+	}
+
+	// roles of stacks are only updated through their stack, the native backend doesn't change that
+	for _, backend := range []string{"", awsservices.ProvisioningBackendNative} {
+		config.Status.ProvisioningBackend = backend
+		cfMock.EXPECT().UpdateStack(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, input *cloudformation.UpdateStackInput) (*cloudformation.UpdateStackOutput, error) {
+				assert.Equal(t, "arn:aws:cloudformation:us-west-2:123456789012:stack/test-eks-service-role/1", aws.ToString(input.StackName))
+				assert.Equal(t, []cftypes.Parameter{{
+					ParameterKey:   aws.String("AdditionalPolicyArns"),
+					ParameterValue: aws.String("arn:aws:iam::aws:policy/AmazonEKSComputePolicy"),
+				}}, input.Parameters)
+				return &cloudformation.UpdateStackOutput{}, nil
+			})
+		assert.NoError(t, h.updateRolePolicies(ctx, config, awsSVCs, policies))
+	}
+
+	// roles created through IAM are updated through IAM
+	config.Status.CloudFormationStacks = nil
+	iamMock.EXPECT().ListAttachedRolePolicies(ctx, &iam.ListAttachedRolePoliciesInput{RoleName: aws.String("test-eks-service-role")}).Return(
+		&iam.ListAttachedRolePoliciesOutput{AttachedPolicies: []iamtypes.AttachedPolicy{
+			{PolicyArn: aws.String("arn:aws:iam::aws:policy/AmazonEKSNetworkingPolicy")},
+		}}, nil)
+	iamMock.EXPECT().AttachRolePolicy(ctx, &iam.AttachRolePolicyInput{
+		RoleName:  aws.String("test-eks-service-role"),
+		PolicyArn: aws.String("arn:aws:iam::aws:policy/AmazonEKSComputePolicy"),
+	}).Return(&iam.AttachRolePolicyOutput{}, nil)
+	iamMock.EXPECT().DetachRolePolicy(ctx, &iam.DetachRolePolicyInput{
+		RoleName:  aws.String("test-eks-service-role"),
+		PolicyArn: aws.String("arn:aws:iam::aws:policy/AmazonEKSNetworkingPolicy"),
+	}).Return(&iam.DetachRolePolicyOutput{}, nil)
+	assert.NoError(t, h.updateRolePolicies(ctx, config, awsSVCs, policies))
+}
@@ -0,0 +1,188 @@
+package controller
+
+import (
+	"fmt"
+	"maps"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/utils"
+)
+
+const (
+	clusterAutoscalerEnabledTagKey = "k8s.io/cluster-autoscaler/enabled"
+	clusterAutoscalerTagKeyPrefix  = "k8s.io/cluster-autoscaler/"
+)
+
+// parseScalingWindow parses the schedule and time zone of a scaling window.
+func parseScalingWindow(window eksv1.ScalingWindow) (*utils.Schedule, *time.Location, error) {
+	schedule, err := utils.ParseSchedule(window.Schedule)
+	if err != nil {
+		return nil, nil, err
+	}
+	location, err := time.LoadLocation(window.TimeZone)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid time zone [%s]: %w", window.TimeZone, err)
+	}
+	return schedule, location, nil
+}
+
+// activeScalingWindow returns the scaling window of the node group that started last at or before now, or nil if none
+// has started yet. Windows that can't be parsed are skipped, the validation rejects them.
+func activeScalingWindow(ng eksv1.NodeGroup, now time.Time) *eksv1.ScalingWindow {
+	var active *eksv1.ScalingWindow
+	var activeSince time.Time
+	for i := range ng.ScheduledScaling {
+		schedule, location, err := parseScalingWindow(ng.ScheduledScaling[i])
+		if err != nil {
+			continue
+		}
+		if since, ok := schedule.Prev(now.In(location)); ok && (active == nil || since.After(activeSince)) {
+			active, activeSince = &ng.ScheduledScaling[i], since
+		}
+	}
+	return active
+}
+
+// nextScalingWindowStart returns how long it is until the next scaling window of any of the node groups starts, false
+// is returned if no node group has scaling windows.
+func nextScalingWindowStart(nodeGroups []eksv1.NodeGroup, now time.Time) (time.Duration, bool) {
+	var next time.Time
+	for _, ng := range nodeGroups {
+		for _, window := range ng.ScheduledScaling {
+			schedule, location, err := parseScalingWindow(window)
+			if err != nil {
+				continue
+			}
+			if start, ok := schedule.Next(now.In(location)); ok && (next.IsZero() || start.Before(next)) {
+				next = start
+			}
+		}
+	}
+	if next.IsZero() {
+		return 0, false
+	}
+	return next.Sub(now), true
+}
+
+// setScalingWindowsStatus records the active scaling window of each node group in the status and returns the names of
+// the node groups whose window changed, sorted by name.
+func setScalingWindowsStatus(config *eksv1.EKSClusterConfig, now time.Time) []string {
+	windows := make(map[string]string)
+	for _, ng := range config.Spec.NodeGroups {
+		if window := activeScalingWindow(ng, now); window != nil {
+			windows[aws.ToString(ng.NodegroupName)] = window.Name
+		}
+	}
+
+	var changed []string
+	for name, window := range windows {
+		if config.Status.NodeGroupScalingWindows[name] != window {
+			changed = append(changed, name)
+		}
+	}
+	for name := range config.Status.NodeGroupScalingWindows {
+		if _, ok := windows[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	if len(windows) == 0 {
+		windows = nil
+	}
+	config.Status.NodeGroupScalingWindows = windows
+	sort.Strings(changed)
+	return changed
+}
+
+// applyScalingWindow returns the node group with its sizes overridden by the sizes of the scaling window with the given
+// name.
+func applyScalingWindow(ng eksv1.NodeGroup, windowName string) eksv1.NodeGroup {
+	if windowName == "" {
+		return ng
+	}
+	for _, window := range ng.ScheduledScaling {
+		if window.Name != windowName {
+			continue
+		}
+		if window.MinSize != nil {
+			ng.MinSize = window.MinSize
+		}
+		if window.MaxSize != nil {
+			ng.MaxSize = window.MaxSize
+		}
+		if window.DesiredSize != nil {
+			ng.DesiredSize = window.DesiredSize
+		}
+		break
+	}
+	return ng
+}
+
+// applyClusterAutoscalerTags returns the node group with the cluster-autoscaler discovery tags added to its tags if the
+// config enables them. The tags are copied, so the spec is left untouched. EKS propagates the tags of managed node
+// groups to their auto scaling groups, the instances don't need them.
+func applyClusterAutoscalerTags(config *eksv1.EKSClusterConfig, ng eksv1.NodeGroup) eksv1.NodeGroup {
+	if !aws.ToBool(config.Spec.ClusterAutoscalerTags) {
+		return ng
+	}
+
+	tags := map[string]*string{
+		clusterAutoscalerEnabledTagKey:                          aws.String("true"),
+		clusterAutoscalerTagKeyPrefix + config.Spec.DisplayName: aws.String("owned"),
+	}
+	maps.Copy(tags, ng.Tags)
+	ng.Tags = tags
+	return ng
+}
+
+// withoutClusterAutoscalerTags returns the tags of a node group of the cluster with the given name without the
+// cluster-autoscaler discovery tags, so that they are added and removed by the clusterAutoscalerTags option alone
+// instead of being synced into the spec.
+func withoutClusterAutoscalerTags(name string, tags map[string]*string) map[string]*string {
+	if tags[clusterAutoscalerEnabledTagKey] == nil && tags[clusterAutoscalerTagKeyPrefix+name] == nil {
+		return tags
+	}
+	tags = maps.Clone(tags)
+	delete(tags, clusterAutoscalerEnabledTagKey)
+	delete(tags, clusterAutoscalerTagKeyPrefix+name)
+	return tags
+}
+
+// validateScheduledScaling checks that the scaling windows of the node group have unique names and valid schedules,
+// and that the sizes of the node group stay consistent while each of them is active.
+func validateScheduledScaling(config *eksv1.EKSClusterConfig, ng eksv1.NodeGroup) error {
+	names := make(map[string]struct{}, len(ng.ScheduledScaling))
+	for _, window := range ng.ScheduledScaling {
+		if window.Name == "" {
+			return fmt.Errorf("scaling windows of node group [%s] in cluster [%s (id: %s)] must have a name",
+				aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name)
+		}
+		if _, ok := names[window.Name]; ok {
+			return fmt.Errorf("scaling window name [%s] is not unique within node group [%s] in cluster [%s (id: %s)]",
+				window.Name, aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name)
+		}
+		names[window.Name] = struct{}{}
+
+		if _, _, err := parseScalingWindow(window); err != nil {
+			return fmt.Errorf("scaling window [%s] of node group [%s] in cluster [%s (id: %s)]: %w",
+				window.Name, aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name, err)
+		}
+
+		scaled := applyScalingWindow(ng, window.Name)
+		if aws.ToInt32(scaled.MinSize) < 0 || aws.ToInt32(scaled.MaxSize) < 0 || aws.ToInt32(scaled.DesiredSize) < 0 ||
+			(scaled.MinSize != nil && scaled.MaxSize != nil && *scaled.MinSize > *scaled.MaxSize) ||
+			(scaled.MinSize != nil && scaled.DesiredSize != nil && *scaled.DesiredSize < *scaled.MinSize) ||
+			(scaled.MaxSize != nil && scaled.DesiredSize != nil && *scaled.DesiredSize > *scaled.MaxSize) {
+			return fmt.Errorf("scaling window [%s] of node group [%s] in cluster [%s (id: %s)] must keep minSize <= desiredSize <= maxSize",
+				window.Name, aws.ToString(ng.NodegroupName), config.Spec.DisplayName, config.Name)
+		}
+	}
+	return nil
+}
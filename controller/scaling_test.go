@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/utils"
+)
+
+func TestSchedule(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "5-1 * * * *", "*/0 * * * *", "a * * * *"} {
+		_, err := utils.ParseSchedule(expr)
+		assert.Error(t, err, expr)
+	}
+
+	// a Wednesday
+	now := time.Date(2024, time.May, 15, 12, 30, 0, 0, time.UTC)
+	weekdayEvenings, err := utils.ParseSchedule("0 20 * * 1-5")
+	require.NoError(t, err)
+	prev, ok := weekdayEvenings.Prev(now)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2024, time.May, 14, 20, 0, 0, 0, time.UTC), prev)
+	next, ok := weekdayEvenings.Next(now)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2024, time.May, 15, 20, 0, 0, 0, time.UTC), next)
+
+	// Monday morning is preceded by Friday evening
+	prev, ok = weekdayEvenings.Prev(time.Date(2024, time.May, 13, 8, 0, 0, 0, time.UTC))
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2024, time.May, 10, 20, 0, 0, 0, time.UTC), prev)
+
+	// the day of month or the day of week has to match if both are restricted, 7 is Sunday
+	firstOrSunday, err := utils.ParseSchedule("*/15 6 1 * 7")
+	require.NoError(t, err)
+	next, ok = firstOrSunday.Next(now)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2024, time.May, 19, 6, 0, 0, 0, time.UTC), next)
+	prev, ok = firstOrSunday.Prev(now)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2024, time.May, 12, 6, 45, 0, 0, time.UTC), prev)
+
+	never, err := utils.ParseSchedule("0 0 30 2 *")
+	require.NoError(t, err)
+	_, ok = never.Next(now)
+	assert.False(t, ok)
+}
+
+func TestScalingWindows(t *testing.T) {
+	ng := eksv1.NodeGroup{
+		NodegroupName: aws.String("dev"),
+		MinSize:       aws.Int32(1),
+		MaxSize:       aws.Int32(5),
+		DesiredSize:   aws.Int32(3),
+		ScheduledScaling: []eksv1.ScalingWindow{
+			{Name: "night", Schedule: "0 20 * * *", TimeZone: "Europe/Berlin", MinSize: aws.Int32(0), DesiredSize: aws.Int32(0)},
+			{Name: "day", Schedule: "0 8 * * 1-5", TimeZone: "Europe/Berlin"},
+		},
+	}
+	config := &eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{NodeGroups: []eksv1.NodeGroup{ng}}}
+	require.NoError(t, validateScheduledScaling(config, ng))
+
+	// 22:00 in Berlin
+	night := time.Date(2024, time.May, 15, 20, 0, 0, 0, time.UTC)
+	assert.Equal(t, "night", activeScalingWindow(ng, night).Name)
+	assert.Equal(t, []string{"dev"}, setScalingWindowsStatus(config, night))
+	assert.Equal(t, map[string]string{"dev": "night"}, config.Status.NodeGroupScalingWindows)
+	assert.Nil(t, setScalingWindowsStatus(config, night))
+	next, ok := nextScalingWindowStart(config.Spec.NodeGroups, night)
+	require.True(t, ok)
+	assert.Equal(t, 10*time.Hour, next)
+
+	scaled := applyScalingWindow(ng, "night")
+	assert.Equal(t, int32(0), aws.ToInt32(scaled.MinSize))
+	assert.Equal(t, int32(5), aws.ToInt32(scaled.MaxSize))
+	assert.Equal(t, int32(0), aws.ToInt32(scaled.DesiredSize))
+	assert.Equal(t, int32(3), aws.ToInt32(ng.DesiredSize))
+
+	// the day window restores the sizes of the node group
+	day := time.Date(2024, time.May, 16, 7, 0, 0, 0, time.UTC)
+	assert.Equal(t, []string{"dev"}, setScalingWindowsStatus(config, day))
+	assert.Equal(t, ng, applyScalingWindow(ng, config.Status.NodeGroupScalingWindows["dev"]))
+
+	config.Spec.NodeGroups[0].ScheduledScaling = nil
+	assert.Equal(t, []string{"dev"}, setScalingWindowsStatus(config, day))
+	assert.Nil(t, config.Status.NodeGroupScalingWindows)
+	_, ok = nextScalingWindowStart(config.Spec.NodeGroups, day)
+	assert.False(t, ok)
+
+	for _, windows := range [][]eksv1.ScalingWindow{
+		{{Schedule: "0 20 * * *"}},
+		{{Name: "night", Schedule: "0 20 * * *"}, {Name: "night", Schedule: "0 8 * * *"}},
+		{{Name: "night", Schedule: "0 20 * *"}},
+		{{Name: "night", Schedule: "0 20 * * *", TimeZone: "Mars/Olympus"}},
+		{{Name: "night", Schedule: "0 20 * * *", DesiredSize: aws.Int32(0)}},
+		{{Name: "night", Schedule: "0 20 * * *", MaxSize: aws.Int32(2)}},
+	} {
+		ng.ScheduledScaling = windows
+		assert.Error(t, validateScheduledScaling(config, ng), windows)
+	}
+}
+
+func TestApplyClusterAutoscalerTags(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{DisplayName: "test"}}
+	ng := eksv1.NodeGroup{
+		NodegroupName: aws.String("ng"),
+		Tags:          map[string]*string{"team": aws.String("a")},
+		ResourceTags:  map[string]string{clusterAutoscalerEnabledTagKey: "false"},
+	}
+	assert.Equal(t, ng, applyClusterAutoscalerTags(config, ng), "tags are only added if enabled")
+
+	config.Spec.ClusterAutoscalerTags = aws.Bool(true)
+	tagged := applyClusterAutoscalerTags(config, ng)
+	assert.Equal(t, map[string]string{
+		"team":                           "a",
+		clusterAutoscalerEnabledTagKey:   "true",
+		"k8s.io/cluster-autoscaler/test": "owned",
+	}, aws.ToStringMap(tagged.Tags))
+	assert.Equal(t, ng.ResourceTags, tagged.ResourceTags, "the launch template isn't tagged")
+	assert.Len(t, ng.Tags, 1, "the spec must not be modified")
+
+	assert.Equal(t, ng.Tags, withoutClusterAutoscalerTags("test", tagged.Tags))
+	assert.Len(t, tagged.Tags, 3, "the tags must not be modified")
+	assert.Contains(t, withoutClusterAutoscalerTags("other", tagged.Tags), "k8s.io/cluster-autoscaler/test", "tags of other clusters are kept")
+}
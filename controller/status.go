@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// statusFieldManager is the field manager the operator applies the status with
+const statusFieldManager = "eks-operator"
+
+var (
+	eksClusterConfigResource = eksv1.SchemeGroupVersion.WithResource("eksclusterconfigs")
+	eksInventoryResource     = eksv1.SchemeGroupVersion.WithResource("eksinventories")
+)
+
+// updateStatus writes the status of the given config with server-side apply. Only the status fields of the operator
+// are applied, so fields that other controllers add to the status are left alone, and the apply is forced since the
+// operator is the authority on its own fields. Applying doesn't depend on the resource version, so changes to the rest
+// of the object don't cause conflicts.
+func (h *Handler) updateStatus(config *eksv1.EKSClusterConfig) (*eksv1.EKSClusterConfig, error) {
+	updated := &eksv1.EKSClusterConfig{}
+	if err := h.applyStatus(eksClusterConfigResource, eksClusterConfigKind, config.Namespace, config.Name, &config.Status, updated); err != nil {
+		return config, err
+	}
+	return updated, nil
+}
+
+// updateInventoryStatus writes the status of the given inventory with server-side apply, like updateStatus.
+func (h *Handler) updateInventoryStatus(inventory *eksv1.EKSInventory) (*eksv1.EKSInventory, error) {
+	updated := &eksv1.EKSInventory{}
+	if err := h.applyStatus(eksInventoryResource, eksInventoryKind, inventory.Namespace, inventory.Name, &inventory.Status, updated); err != nil {
+		return inventory, err
+	}
+	return updated, nil
+}
+
+// applyStatus applies the status to the status subresource of the named object and converts the result into the
+// given object.
+func (h *Handler) applyStatus(resource schema.GroupVersionResource, kind, namespace, name string, status interface{}, result runtime.Object) error {
+	fields, err := statusApplyConfiguration(status)
+	if err != nil {
+		return err
+	}
+	applyConfig := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": eksv1.SchemeGroupVersion.String(),
+		"kind":       kind,
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": namespace,
+		},
+		"status": fields,
+	}}
+
+	applied, err := h.dynamic.Resource(resource).Namespace(namespace).ApplyStatus(context.TODO(), name, applyConfig,
+		metav1.ApplyOptions{FieldManager: statusFieldManager, Force: true})
+	if err != nil {
+		return err
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(applied.Object, result)
+}
+
+// statusApplyConfiguration converts the status, a pointer to a status struct, to the fields applied by the operator.
+// Unset lists and maps are applied as empty values rather than left out, so that clearing them takes effect even on
+// fields that were first written by an update instead of an apply, and unset objects are left out.
+func statusApplyConfiguration(status interface{}) (map[string]interface{}, error) {
+	fields, err := runtime.DefaultUnstructuredConverter.ToUnstructured(status)
+	if err != nil {
+		return nil, err
+	}
+
+	statusType := reflect.TypeOf(status).Elem()
+	for i := 0; i < statusType.NumField(); i++ {
+		field := statusType.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if value, ok := fields[name]; !ok || value != nil {
+			continue
+		}
+		switch field.Type.Kind() {
+		case reflect.Slice:
+			fields[name] = []interface{}{}
+		case reflect.Map:
+			fields[name] = map[string]interface{}{}
+		default:
+			delete(fields, name)
+		}
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestUpdateStatus(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", ResourceVersion: "1"},
+		Status: eksv1.EKSClusterConfigStatus{
+			Phase:          eksv1.PhaseActive,
+			SecurityGroups: []string{"sg"},
+		},
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{eksClusterConfigResource: "EKSClusterConfigList"})
+
+	var applied map[string]interface{}
+	client.PrependReactor("patch", "eksclusterconfigs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch := action.(k8stesting.PatchAction)
+		assert.Equal(t, types.ApplyPatchType, patch.GetPatchType())
+		assert.Equal(t, "status", patch.GetSubresource())
+		require.NoError(t, json.Unmarshal(patch.GetPatch(), &applied))
+
+		result := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(patch.GetPatch(), &result))
+		result["metadata"].(map[string]interface{})["resourceVersion"] = "2"
+		return true, &unstructured.Unstructured{Object: result}, nil
+	})
+	h := &Handler{dynamic: client}
+
+	result, err := h.updateStatus(config)
+	require.NoError(t, err)
+	assert.Equal(t, "2", result.ResourceVersion)
+	assert.Equal(t, eksv1.PhaseActive, result.Status.Phase)
+
+	assert.Equal(t, map[string]interface{}{"name": "test", "namespace": "default"}, applied["metadata"])
+	status := applied["status"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"sg"}, status["securityGroups"])
+	// unset lists and maps are applied as empty so that they are cleared, unset objects are left out
+	assert.Equal(t, []interface{}{}, status["subnets"])
+	assert.Equal(t, map[string]interface{}{}, status["managedLaunchTemplateVersions"])
+	assert.NotContains(t, status, "costEstimate")
+
+	client.PrependReactor("patch", "eksclusterconfigs", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("error applying status")
+	})
+	result, err = h.updateStatus(config)
+	assert.Error(t, err)
+	assert.Equal(t, config, result)
+}
+
+func TestUpdateInventoryStatus(t *testing.T) {
+	inventory := &eksv1.EKSInventory{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		Status:     eksv1.EKSInventoryStatus{LastSyncTime: "2024-01-01T00:00:00Z"},
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{eksInventoryResource: "EKSInventoryList"})
+
+	var applied map[string]interface{}
+	client.PrependReactor("patch", "eksinventories", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch := action.(k8stesting.PatchAction)
+		assert.Equal(t, types.ApplyPatchType, patch.GetPatchType())
+		assert.Equal(t, "status", patch.GetSubresource())
+		require.NoError(t, json.Unmarshal(patch.GetPatch(), &applied))
+
+		result := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(patch.GetPatch(), &result))
+		return true, &unstructured.Unstructured{Object: result}, nil
+	})
+	h := &Handler{dynamic: client}
+
+	result, err := h.updateInventoryStatus(inventory)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-01-01T00:00:00Z", result.Status.LastSyncTime)
+	assert.Equal(t, "EKSInventory", applied["kind"])
+	// clearing the discovered clusters takes effect
+	assert.Equal(t, []interface{}{}, applied["status"].(map[string]interface{})["clusters"])
+}
+
+func TestSetUpstreamClusterStatus(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{
+		Status: eksv1.EKSClusterConfigStatus{SecurityGroups: []string{"sg-2", "sg-1"}},
+	}
+	cluster := &ekstypes.Cluster{
+		Arn:             aws.String("arn:aws:eks:us-east-1:123456789012:cluster/test"),
+		Endpoint:        aws.String("https://test.eks.amazonaws.com"),
+		PlatformVersion: aws.String("eks.1"),
+		Identity: &ekstypes.Identity{
+			Oidc: &ekstypes.OIDC{Issuer: aws.String("https://oidc.eks.us-east-1.amazonaws.com/id/TEST")},
+		},
+		ResourcesVpcConfig: &ekstypes.VpcConfigResponse{
+			ClusterSecurityGroupId: aws.String("sg-cluster"),
+			SecurityGroupIds:       []string{"sg-1", "sg-2"},
+		},
+	}
+
+	assert.True(t, setUpstreamClusterStatus(config, cluster))
+	assert.Equal(t, "arn:aws:eks:us-east-1:123456789012:cluster/test", config.Status.ClusterARN)
+	assert.Equal(t, "https://test.eks.amazonaws.com", config.Status.Endpoint)
+	assert.Equal(t, "eks.1", config.Status.PlatformVersion)
+	assert.Equal(t, "https://oidc.eks.us-east-1.amazonaws.com/id/TEST", config.Status.OIDCIssuer)
+	assert.Equal(t, "sg-cluster", config.Status.ClusterSecurityGroup)
+	// the security groups the cluster was created with are left alone
+	assert.Equal(t, []string{"sg-2", "sg-1"}, config.Status.SecurityGroups)
+
+	assert.False(t, setUpstreamClusterStatus(config, cluster))
+
+	cluster.PlatformVersion = aws.String("eks.2")
+	cluster.ResourcesVpcConfig.SecurityGroupIds = []string{"sg-3"}
+	assert.True(t, setUpstreamClusterStatus(config, cluster))
+	assert.Equal(t, "eks.2", config.Status.PlatformVersion)
+	assert.Equal(t, []string{"sg-2", "sg-1"}, config.Status.SecurityGroups)
+
+	assert.False(t, setUpstreamClusterStatus(config, nil))
+}
+
+func TestSetResourceUsageStatus(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{}
+	assert.False(t, setResourceUsageStatus(config, nil))
+
+	usage := &eksv1.ResourceUsage{LaunchTemplates: 1, CloudFormationStacks: 2, NetworkInterfaces: 3}
+	assert.True(t, setResourceUsageStatus(config, usage))
+	assert.Equal(t, usage, config.Status.ResourceUsage)
+	assert.False(t, setResourceUsageStatus(config, &eksv1.ResourceUsage{LaunchTemplates: 1, CloudFormationStacks: 2, NetworkInterfaces: 3}))
+
+	assert.True(t, setResourceUsageStatus(config, &eksv1.ResourceUsage{LaunchTemplates: 1, CloudFormationStacks: 2, NetworkInterfaces: 5}))
+	assert.Equal(t, int32(5), config.Status.ResourceUsage.NetworkInterfaces)
+}
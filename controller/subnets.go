@@ -0,0 +1,194 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	awsservices "github.com/rancher/eks-operator/pkg/eks"
+	"github.com/rancher/eks-operator/pkg/eks/services"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const clusterTagKeyFormat = "kubernetes.io/cluster/%s"
+
+// setupProvidedSubnets validates the subnets provided in the spec and tags the ones owned by the account the cluster is
+// created in for the cluster. Subnets shared from other accounts through AWS RAM can only be tagged by their owner, so
+// they are recorded in the SharedSubnets condition instead.
+func setupProvidedSubnets(ctx context.Context, config *eksv1.EKSClusterConfig, awsSVCs *awsServices) error {
+	subnets, err := awsservices.GetSubnets(ctx, &awsservices.GetSubnetsOpts{
+		EC2Service: awsSVCs.ec2,
+		SubnetIDs:  config.Spec.Subnets,
+	})
+	if err != nil {
+		return fmt.Errorf("error getting subnets for cluster [%s (id: %s)]: %w", config.Spec.DisplayName, config.Name, err)
+	}
+	vpcID, err := validateProvidedSubnets(config, subnets)
+	if err != nil {
+		return err
+	}
+	config.Status.VirtualNetwork = vpcID
+
+	identity, err := awsSVCs.sts.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return fmt.Errorf("error getting account for cluster [%s (id: %s)]: %w", config.Spec.DisplayName, config.Name, err)
+	}
+	owned, shared := getSharedSubnets(subnets, aws.ToString(identity.Account))
+
+	if err := awsservices.TagSubnets(ctx, &awsservices.TagSubnetsOptions{
+		EC2Service: awsSVCs.ec2,
+		SubnetIDs:  owned,
+		Tags:       map[string]string{fmt.Sprintf(clusterTagKeyFormat, config.Spec.DisplayName): "shared"},
+	}); err != nil {
+		return fmt.Errorf("error tagging subnets for cluster [%s (id: %s)]: %w", config.Spec.DisplayName, config.Name, err)
+	}
+
+	if len(shared) != 0 {
+		logrus.Infof("Subnets [%s] for cluster [%s (id: %s)] are shared from other accounts, skipping tagging",
+			strings.Join(shared, ", "), config.Spec.DisplayName, config.Name)
+	}
+	setSharedSubnetsStatus(config, shared)
+
+	return nil
+}
+
+// validateVPCMode validates the mode of the VPC generated for the cluster.
+func validateVPCMode(config *eksv1.EKSClusterConfig) error {
+	switch config.Spec.VPCMode {
+	case "", vpcModePublic:
+		return nil
+	case vpcModePrivate:
+	default:
+		return fmt.Errorf("invalid vpcMode [%s] for cluster [%s (id: %s)], must be %s or %s", config.Spec.VPCMode, config.Spec.DisplayName, config.Name, vpcModePublic, vpcModePrivate)
+	}
+
+	if len(config.Spec.Subnets) != 0 {
+		return fmt.Errorf("vpcMode cannot be specified along with subnets for cluster [%s (id: %s)]", config.Spec.DisplayName, config.Name)
+	}
+	if !aws.ToBool(config.Spec.PrivateAccess) {
+		return fmt.Errorf("private access must be enabled for cluster [%s (id: %s)] with a private vpc", config.Spec.DisplayName, config.Name)
+	}
+
+	return nil
+}
+
+// getNATGatewayCount returns the number of NAT gateways created along with the cluster.
+func getNATGatewayCount(config *eksv1.EKSClusterConfig) int32 {
+	if len(config.Spec.Subnets) == 0 && config.Spec.VPCMode == vpcModePrivate {
+		return privateVPCNATGateways
+	}
+	return 0
+}
+
+// validateProvidedSubnets checks that all subnets in the spec exist and are in the same VPC, and returns the ID of the VPC.
+func validateProvidedSubnets(config *eksv1.EKSClusterConfig, subnets []ec2types.Subnet) (string, error) {
+	found := make(map[string]struct{}, len(subnets))
+	vpcIDs := make(map[string]struct{})
+	for _, subnet := range subnets {
+		found[aws.ToString(subnet.SubnetId)] = struct{}{}
+		vpcIDs[aws.ToString(subnet.VpcId)] = struct{}{}
+	}
+
+	for _, subnetID := range config.Spec.Subnets {
+		if _, ok := found[subnetID]; !ok {
+			return "", fmt.Errorf("subnet [%s] for cluster [%s (id: %s)] not found, subnets shared from other accounts must be shared with the account through AWS RAM",
+				subnetID, config.Spec.DisplayName, config.Name)
+		}
+	}
+	if len(vpcIDs) != 1 {
+		ids := make([]string, 0, len(vpcIDs))
+		for id := range vpcIDs {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		return "", fmt.Errorf("subnets for cluster [%s (id: %s)] must all be in the same VPC, found VPCs [%s]",
+			config.Spec.DisplayName, config.Name, strings.Join(ids, ", "))
+	}
+
+	return aws.ToString(subnets[0].VpcId), nil
+}
+
+// getSharedSubnets splits the given subnets into the ones owned by the given account and descriptions of the ones
+// shared from other accounts.
+func getSharedSubnets(subnets []ec2types.Subnet, account string) ([]string, []string) {
+	var owned, shared []string
+	for _, subnet := range subnets {
+		owner := aws.ToString(subnet.OwnerId)
+		if owner == "" || owner == account {
+			owned = append(owned, aws.ToString(subnet.SubnetId))
+			continue
+		}
+		shared = append(shared, fmt.Sprintf("%s (owner: %s)", aws.ToString(subnet.SubnetId), owner))
+	}
+
+	return owned, shared
+}
+
+// setSharedSubnetsStatus records the subnets shared from other accounts and what their owner needs to do in the
+// SharedSubnets condition. The condition is only added to clusters that use shared subnets.
+func setSharedSubnetsStatus(config *eksv1.EKSClusterConfig, shared []string) {
+	if len(shared) == 0 {
+		if sharedSubnets.GetStatus(config) != "" {
+			sharedSubnets.SetStatus(config, string(corev1.ConditionFalse))
+			sharedSubnets.Message(config, "")
+		}
+		return
+	}
+
+	sharedSubnets.SetStatus(config, string(corev1.ConditionTrue))
+	sharedSubnets.Message(config, fmt.Sprintf("subnets [%s] are shared from other accounts and can't be tagged by the operator, "+
+		"the owner account must tag them with [%s=shared] and the kubernetes.io/role/elb or kubernetes.io/role/internal-elb "+
+		"tags needed by load balancers", strings.Join(shared, ", "), fmt.Sprintf(clusterTagKeyFormat, config.Spec.DisplayName)))
+}
+
+// removeClusterTags removes the cluster tag from the provided subnets and security groups of the cluster. Failing to
+// remove the tags doesn't block the deletion of the cluster, only requests skipped in dry run mode are returned.
+func removeClusterTags(ctx context.Context, config *eksv1.EKSClusterConfig, ec2Service services.EC2ServiceInterface) error {
+	removed, err := awsservices.DeleteResourceTag(ctx, &awsservices.DeleteResourceTagOpts{
+		EC2Service:  ec2Service,
+		ResourceIDs: getClusterTaggedResourceIDs(config),
+		TagKey:      fmt.Sprintf(clusterTagKeyFormat, config.Spec.DisplayName),
+	})
+	if isDryRun(err) {
+		return err
+	}
+	if err != nil {
+		logrus.Warnf("Could not remove cluster tags from subnets and security groups of cluster [%s (id: %s)]: %v, will not retry",
+			config.Spec.DisplayName, config.Name, err)
+		return nil
+	}
+	if len(removed) != 0 {
+		logrus.Infof("Removed cluster tags from [%s] for cluster [%s (id: %s)]", strings.Join(removed, ", "), config.Spec.DisplayName, config.Name)
+	}
+	return nil
+}
+
+// getClusterTaggedResourceIDs returns the IDs of the provided subnets and security groups that can carry the cluster
+// tag, including subnets of node groups.
+func getClusterTaggedResourceIDs(config *eksv1.EKSClusterConfig) []string {
+	seen := make(map[string]struct{})
+	var ids []string
+	add := func(resourceIDs []string) {
+		for _, id := range resourceIDs {
+			if _, ok := seen[id]; ok || id == "" {
+				continue
+			}
+			seen[id] = struct{}{}
+			ids = append(ids, id)
+		}
+	}
+
+	add(config.Spec.Subnets)
+	add(config.Spec.SecurityGroups)
+	for _, ng := range config.Spec.NodeGroups {
+		add(ng.Subnets)
+	}
+
+	return ids
+}
@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateProvidedSubnets(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{DisplayName: "test", Subnets: []string{"subnet-a", "subnet-b"}}}
+
+	vpcID, err := validateProvidedSubnets(config, []ec2types.Subnet{
+		{SubnetId: aws.String("subnet-a"), VpcId: aws.String("vpc-a")},
+		{SubnetId: aws.String("subnet-b"), VpcId: aws.String("vpc-a")},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "vpc-a", vpcID)
+
+	_, err = validateProvidedSubnets(config, []ec2types.Subnet{
+		{SubnetId: aws.String("subnet-a"), VpcId: aws.String("vpc-a")},
+	})
+	assert.ErrorContains(t, err, "subnet [subnet-b]")
+
+	_, err = validateProvidedSubnets(config, []ec2types.Subnet{
+		{SubnetId: aws.String("subnet-a"), VpcId: aws.String("vpc-a")},
+		{SubnetId: aws.String("subnet-b"), VpcId: aws.String("vpc-b")},
+	})
+	assert.ErrorContains(t, err, "found VPCs [vpc-a, vpc-b]")
+}
+
+func TestGetSharedSubnets(t *testing.T) {
+	owned, shared := getSharedSubnets([]ec2types.Subnet{
+		{SubnetId: aws.String("subnet-a"), OwnerId: aws.String("111111111111")},
+		{SubnetId: aws.String("subnet-b"), OwnerId: aws.String("222222222222")},
+		{SubnetId: aws.String("subnet-c")},
+	}, "111111111111")
+
+	assert.Equal(t, []string{"subnet-a", "subnet-c"}, owned)
+	assert.Equal(t, []string{"subnet-b (owner: 222222222222)"}, shared)
+}
+
+func TestSetSharedSubnetsStatus(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{DisplayName: "test"}}
+
+	setSharedSubnetsStatus(config, nil)
+	assert.Empty(t, config.Status.Conditions)
+
+	setSharedSubnetsStatus(config, []string{"subnet-b (owner: 222222222222)"})
+	assert.True(t, sharedSubnets.IsTrue(config))
+	assert.Contains(t, sharedSubnets.GetMessage(config), "subnet-b (owner: 222222222222)")
+	assert.Contains(t, sharedSubnets.GetMessage(config), "kubernetes.io/cluster/test=shared")
+
+	setSharedSubnetsStatus(config, nil)
+	assert.True(t, sharedSubnets.IsFalse(config))
+	assert.Empty(t, sharedSubnets.GetMessage(config))
+}
+
+func TestGetClusterTaggedResourceIDs(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{
+		Subnets:        []string{"subnet-a", "subnet-b"},
+		SecurityGroups: []string{"sg-a"},
+		NodeGroups: []eksv1.NodeGroup{
+			{Subnets: []string{"subnet-b", "subnet-c"}},
+			{},
+		},
+	}}
+
+	assert.Equal(t, []string{"subnet-a", "subnet-b", "sg-a", "subnet-c"}, getClusterTaggedResourceIDs(config))
+}
+
+func TestValidateVPCMode(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        eksv1.EKSClusterConfigSpec
+		expectedErr bool
+	}{
+		{
+			name: "default",
+		},
+		{
+			name: "public",
+			spec: eksv1.EKSClusterConfigSpec{VPCMode: "public"},
+		},
+		{
+			name: "private",
+			spec: eksv1.EKSClusterConfigSpec{VPCMode: "private", PrivateAccess: aws.Bool(true)},
+		},
+		{
+			name:        "private without private access",
+			spec:        eksv1.EKSClusterConfigSpec{VPCMode: "private", PrivateAccess: aws.Bool(false)},
+			expectedErr: true,
+		},
+		{
+			name:        "private with provided subnets",
+			spec:        eksv1.EKSClusterConfigSpec{VPCMode: "private", PrivateAccess: aws.Bool(true), Subnets: []string{"subnet-a"}},
+			expectedErr: true,
+		},
+		{
+			name:        "invalid",
+			spec:        eksv1.EKSClusterConfigSpec{VPCMode: "isolated"},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateVPCMode(&eksv1.EKSClusterConfig{Spec: tt.spec})
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package controller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+)
+
+const (
+	templateControllerName = "eks-template-controller"
+
+	// templateRenderAnnotation records the last rendering of a config from its template, it is written along with the
+	// rendered spec so that both are always in sync
+	templateRenderAnnotation = "eks.cattle.io/template-render"
+)
+
+// fields of the spec that are never rendered from a template
+var nonTemplateFields = map[string]struct{}{
+	"displayName": {},
+	"templateRef": {},
+}
+
+// templateRender is the last rendering of a config from its template.
+type templateRender struct {
+	Name       string `json:"name"`
+	Generation int64  `json:"generation"`
+	// hashes of the values rendered into the fields of the spec. A field whose value no longer matches its hash was
+	// changed in the config and overrides the template from then on.
+	FieldHashes map[string]string `json:"fieldHashes"`
+}
+
+// OnTemplateChanged enqueues the configs that reference the template, so that they are rendered from its new
+// generation if they follow it.
+func (h *Handler) OnTemplateChanged(_ string, template *eksv1.EKSClusterTemplate) (*eksv1.EKSClusterTemplate, error) {
+	if template == nil || template.DeletionTimestamp != nil {
+		return template, nil
+	}
+
+	configs, err := h.eksCC.List(template.Namespace, metav1.ListOptions{})
+	if err != nil {
+		return template, fmt.Errorf("error listing eksclusterconfigs in namespace [%s]: %w", template.Namespace, err)
+	}
+	for _, config := range configs.Items {
+		if config.Spec.TemplateRef != nil && config.Spec.TemplateRef.Name == template.Name {
+			h.eksEnqueue(config.Namespace, config.Name)
+		}
+	}
+	return template, nil
+}
+
+// applyTemplate renders the config from the generation of its template it references and stores the result in its
+// spec. Nothing changes if the config was already rendered from that generation.
+func (h *Handler) applyTemplate(config *eksv1.EKSClusterConfig) (*eksv1.EKSClusterConfig, error) {
+	ref := config.Spec.TemplateRef
+	if ref == nil {
+		return config, nil
+	}
+
+	template, err := h.templateCache.Get(config.Namespace, ref.Name)
+	if err != nil {
+		return config, fmt.Errorf("error getting template [%s] of config [%s (id: %s)]: %w", ref.Name, config.Spec.DisplayName, config.Name, err)
+	}
+
+	if ref.Generation != 0 && ref.Generation != template.Generation {
+		if previous := getTemplateRender(config); previous.Name == ref.Name && previous.Generation == ref.Generation {
+			// the template changed since, its changes are rolled out once the referenced generation is raised
+			return config, nil
+		}
+		return config, fmt.Errorf("generation [%d] of template [%s] for config [%s (id: %s)] is not available, the current generation is [%d]",
+			ref.Generation, ref.Name, config.Spec.DisplayName, config.Name, template.Generation)
+	}
+
+	rendered := config.DeepCopy()
+	changed, err := renderTemplate(rendered, template)
+	if err != nil {
+		return config, fmt.Errorf("error rendering template [%s] for config [%s (id: %s)]: %w", ref.Name, config.Spec.DisplayName, config.Name, err)
+	}
+	if !changed {
+		return config, nil
+	}
+
+	logrus.Infof("Rendering config [%s (id: %s)] from generation [%d] of template [%s]", config.Spec.DisplayName, config.Name, template.Generation, template.Name)
+	updated, err := h.eksCC.Update(rendered)
+	if err != nil {
+		return config, fmt.Errorf("error updating config [%s (id: %s)] rendered from template [%s]: %w", config.Spec.DisplayName, config.Name, ref.Name, err)
+	}
+	return updated, nil
+}
+
+// renderTemplate sets the fields of the template on the spec of the config and returns whether the config was rendered
+// from another template or generation before. Fields that are unset in the config, or still have the value they were
+// last rendered with, are taken from the template, the others are overrides and are kept. The config is expected to
+// be a copy that can be modified.
+func renderTemplate(config *eksv1.EKSClusterConfig, template *eksv1.EKSClusterTemplate) (bool, error) {
+	previous := getTemplateRender(config)
+	if previous.Name == template.Name && previous.Generation == template.Generation {
+		return false, nil
+	}
+	if previous.Name != template.Name {
+		// values rendered from another template are overrides of this one
+		previous.FieldHashes = nil
+	}
+
+	spec, err := toFieldMap(config.Spec)
+	if err != nil {
+		return false, err
+	}
+	templateSpec, err := toFieldMap(template.Spec.Template)
+	if err != nil {
+		return false, err
+	}
+
+	render := templateRender{
+		Name:        template.Name,
+		Generation:  template.Generation,
+		FieldHashes: map[string]string{},
+	}
+	for field, value := range templateSpec {
+		if _, ok := nonTemplateFields[field]; ok || isUnsetField(value) {
+			continue
+		}
+		current := spec[field]
+		if !isUnsetField(current) {
+			hash, ok := previous.FieldHashes[field]
+			if !ok || hash != hashField(current) {
+				continue
+			}
+		}
+		spec[field] = value
+		render.FieldHashes[field] = hashField(value)
+	}
+
+	specJSON, err := json.Marshal(spec)
+	if err != nil {
+		return false, err
+	}
+	renderedSpec := eksv1.EKSClusterConfigSpec{}
+	if err := json.Unmarshal(specJSON, &renderedSpec); err != nil {
+		return false, err
+	}
+	renderJSON, err := json.Marshal(render)
+	if err != nil {
+		return false, err
+	}
+
+	config.Spec = renderedSpec
+	if config.Annotations == nil {
+		config.Annotations = map[string]string{}
+	}
+	config.Annotations[templateRenderAnnotation] = string(renderJSON)
+	return true, nil
+}
+
+// getTemplateRender returns the last rendering of the config from a template, it is empty if the config was never
+// rendered or the annotation is invalid.
+func getTemplateRender(config *eksv1.EKSClusterConfig) templateRender {
+	render := templateRender{}
+	if value, ok := config.Annotations[templateRenderAnnotation]; ok {
+		if err := json.Unmarshal([]byte(value), &render); err != nil {
+			logrus.Warnf("Ignoring invalid annotation [%s] of config [%s (id: %s)]: %v", templateRenderAnnotation, config.Spec.DisplayName, config.Name, err)
+			return templateRender{}
+		}
+	}
+	return render
+}
+
+func toFieldMap(spec eksv1.EKSClusterConfigSpec) (map[string]interface{}, error) {
+	specJSON, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(specJSON, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// isUnsetField returns whether the JSON value of a spec field is unset, empty strings are unset since string fields
+// of the spec aren't pointers.
+func isUnsetField(value interface{}) bool {
+	return value == nil || value == ""
+}
+
+func hashField(value interface{}) string {
+	// maps are encoded with sorted keys, so the hash is stable
+	valueJSON, _ := json.Marshal(value)
+	hash := sha256.Sum256(valueJSON)
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	template := &eksv1.EKSClusterTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "standard", Generation: 1},
+		Spec: eksv1.EKSClusterTemplateSpec{Template: eksv1.EKSClusterConfigSpec{
+			DisplayName:       "ignored",
+			Region:            "us-west-2",
+			KubernetesVersion: aws.String("1.29"),
+			PrivateAccess:     aws.Bool(false),
+			Tags:              map[string]string{"team": "platform"},
+		}},
+	}
+	config := &eksv1.EKSClusterConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec: eksv1.EKSClusterConfigSpec{
+			DisplayName: "test",
+			Region:      "eu-west-1",
+			TemplateRef: &eksv1.TemplateReference{Name: "standard"},
+		},
+	}
+
+	changed, err := renderTemplate(config, template)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "test", config.Spec.DisplayName)
+	// fields set in the config override the template
+	assert.Equal(t, "eu-west-1", config.Spec.Region)
+	assert.Equal(t, "1.29", aws.ToString(config.Spec.KubernetesVersion))
+	assert.False(t, aws.ToBool(config.Spec.PrivateAccess))
+	assert.NotNil(t, config.Spec.PrivateAccess)
+	assert.Equal(t, map[string]string{"team": "platform"}, config.Spec.Tags)
+	assert.Nil(t, config.Spec.PublicAccess)
+	assert.Equal(t, &eksv1.TemplateReference{Name: "standard"}, config.Spec.TemplateRef)
+
+	changed, err = renderTemplate(config, template)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	// rendered fields follow the template, unless they were changed in the config since
+	config.Spec.Tags = map[string]string{"team": "data"}
+	template.Generation = 2
+	template.Spec.Template.KubernetesVersion = aws.String("1.30")
+	template.Spec.Template.Tags = map[string]string{"team": "platform", "env": "prod"}
+	changed, err = renderTemplate(config, template)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "1.30", aws.ToString(config.Spec.KubernetesVersion))
+	assert.Equal(t, map[string]string{"team": "data"}, config.Spec.Tags)
+	assert.Equal(t, int64(2), getTemplateRender(config).Generation)
+}
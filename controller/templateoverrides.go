@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	awsservices "github.com/rancher/eks-operator/pkg/eks"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// getTemplateOverrides returns the CloudFormation templates in the ConfigMap referenced by the cloudFormationTemplates
+// field of the spec, keyed by template name, or nil if the field isn't set. Keys that aren't template names are
+// rejected, so that misspelled templates don't silently fall back to the built-in ones.
+func (h *Handler) getTemplateOverrides(config *eksv1.EKSClusterConfig) (map[string]string, error) {
+	name := config.Spec.CloudFormationTemplates
+	if name == "" {
+		return nil, nil
+	}
+
+	configMap, err := h.configMaps.Get(config.Namespace, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting CloudFormation templates ConfigMap [%s/%s] of cluster [%s (id: %s)]: %w",
+			config.Namespace, name, config.Spec.DisplayName, config.Name, err)
+	}
+	for key := range configMap.Data {
+		if !slices.Contains(awsservices.TemplateNames, key) {
+			return nil, fmt.Errorf("unknown CloudFormation template [%s] in ConfigMap [%s/%s] of cluster [%s (id: %s)], known templates are %s",
+				key, config.Namespace, name, config.Spec.DisplayName, config.Name, strings.Join(awsservices.TemplateNames, ", "))
+		}
+	}
+	return configMap.Data, nil
+}
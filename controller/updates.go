@@ -0,0 +1,147 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	awsservices "github.com/rancher/eks-operator/pkg/eks"
+	"github.com/rancher/eks-operator/pkg/eks/services"
+)
+
+// updateRecorder is an EKS service that records the updates sent through it, so that they can be tracked on the status
+// of the config until they finish.
+type updateRecorder struct {
+	services.EKSServiceInterface
+	updates []eksv1.EKSUpdate
+}
+
+func (r *updateRecorder) record(update *ekstypes.Update, nodegroupName, addonName *string) {
+	if update == nil || update.Id == nil {
+		return
+	}
+	r.updates = append(r.updates, eksv1.EKSUpdate{
+		ID:            aws.ToString(update.Id),
+		Type:          string(update.Type),
+		NodegroupName: aws.ToString(nodegroupName),
+		AddonName:     aws.ToString(addonName),
+	})
+}
+
+func (r *updateRecorder) UpdateClusterConfig(ctx context.Context, input *eks.UpdateClusterConfigInput) (*eks.UpdateClusterConfigOutput, error) {
+	output, err := r.EKSServiceInterface.UpdateClusterConfig(ctx, input)
+	if err == nil {
+		r.record(output.Update, nil, nil)
+	}
+	return output, err
+}
+
+func (r *updateRecorder) UpdateClusterVersion(ctx context.Context, input *eks.UpdateClusterVersionInput) (*eks.UpdateClusterVersionOutput, error) {
+	output, err := r.EKSServiceInterface.UpdateClusterVersion(ctx, input)
+	if err == nil {
+		r.record(output.Update, nil, nil)
+	}
+	return output, err
+}
+
+func (r *updateRecorder) UpdateNodegroupConfig(ctx context.Context, input *eks.UpdateNodegroupConfigInput) (*eks.UpdateNodegroupConfigOutput, error) {
+	output, err := r.EKSServiceInterface.UpdateNodegroupConfig(ctx, input)
+	if err == nil {
+		r.record(output.Update, input.NodegroupName, nil)
+	}
+	return output, err
+}
+
+func (r *updateRecorder) UpdateNodegroupVersion(ctx context.Context, input *eks.UpdateNodegroupVersionInput) (*eks.UpdateNodegroupVersionOutput, error) {
+	output, err := r.EKSServiceInterface.UpdateNodegroupVersion(ctx, input)
+	if err == nil {
+		r.record(output.Update, input.NodegroupName, nil)
+	}
+	return output, err
+}
+
+func (r *updateRecorder) UpdateAddon(ctx context.Context, input *eks.UpdateAddonInput) (*eks.UpdateAddonOutput, error) {
+	output, err := r.EKSServiceInterface.UpdateAddon(ctx, input)
+	if err == nil {
+		r.record(output.Update, nil, input.AddonName)
+	}
+	return output, err
+}
+
+func (r *updateRecorder) AssociateEncryptionConfig(ctx context.Context, input *eks.AssociateEncryptionConfigInput) (*eks.AssociateEncryptionConfigOutput, error) {
+	output, err := r.EKSServiceInterface.AssociateEncryptionConfig(ctx, input)
+	if err == nil {
+		r.record(output.Update, nil, nil)
+	}
+	return output, err
+}
+
+func (r *updateRecorder) AssociateIdentityProviderConfig(ctx context.Context, input *eks.AssociateIdentityProviderConfigInput) (*eks.AssociateIdentityProviderConfigOutput, error) {
+	output, err := r.EKSServiceInterface.AssociateIdentityProviderConfig(ctx, input)
+	if err == nil {
+		r.record(output.Update, nil, nil)
+	}
+	return output, err
+}
+
+func (r *updateRecorder) DisassociateIdentityProviderConfig(ctx context.Context, input *eks.DisassociateIdentityProviderConfigInput) (*eks.DisassociateIdentityProviderConfigOutput, error) {
+	output, err := r.EKSServiceInterface.DisassociateIdentityProviderConfig(ctx, input)
+	if err == nil {
+		r.record(output.Update, nil, nil)
+	}
+	return output, err
+}
+
+// checkInProgressUpdates describes the in-progress updates on the status of the config, removes the ones that finished
+// and returns the errors of the ones that failed.
+func checkInProgressUpdates(ctx context.Context, config *eksv1.EKSClusterConfig, eksService services.EKSServiceInterface) ([]string, error) {
+	var inProgress []eksv1.EKSUpdate
+	var failures []string
+	for _, update := range config.Status.InProgressUpdates {
+		upstreamUpdate, err := awsservices.GetUpdate(ctx, &awsservices.GetUpdateOpts{
+			EKSService:  eksService,
+			ClusterName: config.Spec.DisplayName,
+			Update:      update,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if upstreamUpdate == nil {
+			continue
+		}
+
+		switch upstreamUpdate.Status {
+		case ekstypes.UpdateStatusInProgress:
+			inProgress = append(inProgress, update)
+		case ekstypes.UpdateStatusFailed:
+			failures = append(failures, formatUpdateFailure(update, upstreamUpdate.Errors))
+		}
+	}
+
+	config.Status.InProgressUpdates = inProgress
+	return failures, nil
+}
+
+// formatUpdateFailure describes a failed update along with the errors EKS reported for it.
+func formatUpdateFailure(update eksv1.EKSUpdate, errorDetails []ekstypes.ErrorDetail) string {
+	description := fmt.Sprintf("%s [%s]", update.Type, update.ID)
+	if update.NodegroupName != "" {
+		description += fmt.Sprintf(" of node group [%s]", update.NodegroupName)
+	} else if update.AddonName != "" {
+		description += fmt.Sprintf(" of add-on [%s]", update.AddonName)
+	}
+
+	details := make([]string, 0, len(errorDetails))
+	for _, detail := range errorDetails {
+		details = append(details, fmt.Sprintf("%s: %s", detail.ErrorCode, aws.ToString(detail.ErrorMessage)))
+	}
+	if len(details) == 0 {
+		return description + " failed"
+	}
+	return fmt.Sprintf("%s failed: %s", description, strings.Join(details, ", "))
+}
@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/golang/mock/gomock"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services/mock_services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateRecorder(t *testing.T) {
+	ctx := context.Background()
+	eksServiceMock := mock_services.NewMockEKSServiceInterface(gomock.NewController(t))
+	eksServiceMock.EXPECT().UpdateClusterVersion(ctx, gomock.Any()).Return(&eks.UpdateClusterVersionOutput{
+		Update: &ekstypes.Update{Id: aws.String("version-update"), Type: ekstypes.UpdateTypeVersionUpdate},
+	}, nil)
+	eksServiceMock.EXPECT().UpdateNodegroupConfig(ctx, gomock.Any()).Return(&eks.UpdateNodegroupConfigOutput{
+		Update: &ekstypes.Update{Id: aws.String("config-update"), Type: ekstypes.UpdateTypeConfigUpdate},
+	}, nil)
+	eksServiceMock.EXPECT().UpdateAddon(ctx, gomock.Any()).Return(nil, &ekstypes.ResourceInUseException{})
+
+	recorder := &updateRecorder{EKSServiceInterface: eksServiceMock}
+	_, err := recorder.UpdateClusterVersion(ctx, &eks.UpdateClusterVersionInput{Name: aws.String("test")})
+	require.NoError(t, err)
+	_, err = recorder.UpdateNodegroupConfig(ctx, &eks.UpdateNodegroupConfigInput{ClusterName: aws.String("test"), NodegroupName: aws.String("ng1")})
+	require.NoError(t, err)
+	_, err = recorder.UpdateAddon(ctx, &eks.UpdateAddonInput{ClusterName: aws.String("test"), AddonName: aws.String("aws-ebs-csi-driver")})
+	require.Error(t, err)
+
+	assert.Equal(t, []eksv1.EKSUpdate{
+		{ID: "version-update", Type: "VersionUpdate"},
+		{ID: "config-update", Type: "ConfigUpdate", NodegroupName: "ng1"},
+	}, recorder.updates)
+}
+
+func TestCheckInProgressUpdates(t *testing.T) {
+	ctx := context.Background()
+	eksServiceMock := mock_services.NewMockEKSServiceInterface(gomock.NewController(t))
+	describe := func(id, nodegroupName string, status ekstypes.UpdateStatus, errorDetails ...ekstypes.ErrorDetail) {
+		input := &eks.DescribeUpdateInput{Name: aws.String("test"), UpdateId: aws.String(id)}
+		if nodegroupName != "" {
+			input.NodegroupName = aws.String(nodegroupName)
+		}
+		eksServiceMock.EXPECT().DescribeUpdate(ctx, input).Return(
+			&eks.DescribeUpdateOutput{Update: &ekstypes.Update{Id: aws.String(id), Status: status, Errors: errorDetails}}, nil)
+	}
+	describe("successful", "", ekstypes.UpdateStatusSuccessful)
+	describe("in-progress", "ng1", ekstypes.UpdateStatusInProgress)
+	describe("failed", "ng2", ekstypes.UpdateStatusFailed, ekstypes.ErrorDetail{
+		ErrorCode:    ekstypes.ErrorCodeAccessDenied,
+		ErrorMessage: aws.String("not authorized to perform ec2:RunInstances"),
+	})
+	eksServiceMock.EXPECT().DescribeUpdate(ctx, gomock.Any()).Return(nil, &ekstypes.ResourceNotFoundException{})
+
+	config := &eksv1.EKSClusterConfig{
+		Spec: eksv1.EKSClusterConfigSpec{DisplayName: "test"},
+		Status: eksv1.EKSClusterConfigStatus{InProgressUpdates: []eksv1.EKSUpdate{
+			{ID: "successful", Type: "ConfigUpdate"},
+			{ID: "in-progress", Type: "VersionUpdate", NodegroupName: "ng1"},
+			{ID: "failed", Type: "VersionUpdate", NodegroupName: "ng2"},
+			{ID: "deleted", Type: "ConfigUpdate", NodegroupName: "ng3"},
+		}},
+	}
+	failures, err := checkInProgressUpdates(ctx, config, eksServiceMock)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"VersionUpdate [failed] of node group [ng2] failed: AccessDenied: not authorized to perform ec2:RunInstances"}, failures)
+	assert.Equal(t, []eksv1.EKSUpdate{{ID: "in-progress", Type: "VersionUpdate", NodegroupName: "ng1"}}, config.Status.InProgressUpdates)
+}
@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+)
+
+// validateServiceRolePolicyARNs checks that additional service role policies are only set for service roles the
+// operator creates, and that they are ARNs of IAM policies.
+func validateServiceRolePolicyARNs(config *eksv1.EKSClusterConfig) error {
+	if len(config.Spec.ServiceRolePolicyARNs) == 0 {
+		return nil
+	}
+
+	if aws.ToString(config.Spec.ServiceRole) != "" {
+		return fmt.Errorf("field [serviceRolePolicyArns] cannot be set along with serviceRole for cluster [%s (id: %s)], attach the policies to the service role instead",
+			config.Spec.DisplayName, config.Name)
+	}
+	for _, policyARN := range config.Spec.ServiceRolePolicyARNs {
+		if !isIAMPolicyARN(policyARN) {
+			return fmt.Errorf("field [serviceRolePolicyArns] must contain ARNs of IAM policies for cluster [%s (id: %s)], got [%s]",
+				config.Spec.DisplayName, config.Name, policyARN)
+		}
+	}
+
+	return nil
+}
+
+// validateIAMRoleOptions checks that the permissions boundary and the additional node instance role policies of the
+// roles the operator creates are ARNs of IAM policies, and that the shared node role is the ARN of an IAM role that
+// isn't combined with policies for the node instance role the operator then doesn't create.
+func validateIAMRoleOptions(config *eksv1.EKSClusterConfig) error {
+	if boundary := config.Spec.IAMPermissionsBoundary; boundary != "" && !isIAMPolicyARN(boundary) {
+		return fmt.Errorf("field [iamPermissionsBoundary] must be the ARN of an IAM policy for cluster [%s (id: %s)], got [%s]",
+			config.Spec.DisplayName, config.Name, boundary)
+	}
+	for _, policyARN := range config.Spec.NodeInstanceRolePolicyARNs {
+		if !isIAMPolicyARN(policyARN) {
+			return fmt.Errorf("field [nodeInstanceRolePolicyArns] must contain ARNs of IAM policies for cluster [%s (id: %s)], got [%s]",
+				config.Spec.DisplayName, config.Name, policyARN)
+		}
+	}
+	if nodeRole := aws.ToString(config.Spec.NodeRole); nodeRole != "" {
+		if !isIAMRoleARN(nodeRole) {
+			return fmt.Errorf("field [nodeRole] must be the ARN of an IAM role for cluster [%s (id: %s)], got [%s]",
+				config.Spec.DisplayName, config.Name, nodeRole)
+		}
+		if len(config.Spec.NodeInstanceRolePolicyARNs) != 0 {
+			return fmt.Errorf("field [nodeInstanceRolePolicyArns] can't be set along with [nodeRole] for cluster [%s (id: %s)], the node instance role is not created",
+				config.Spec.DisplayName, config.Name)
+		}
+	}
+
+	return nil
+}
+
+func isIAMPolicyARN(value string) bool {
+	parsed, err := arn.Parse(value)
+	return err == nil && parsed.Service == "iam" && strings.HasPrefix(parsed.Resource, "policy/")
+}
+
+func isIAMRoleARN(value string) bool {
+	parsed, err := arn.Parse(value)
+	return err == nil && parsed.Service == "iam" && strings.HasPrefix(parsed.Resource, "role/")
+}
+
+// validateOutpostConfig validates that a local cluster on AWS Outposts doesn't use features that local clusters
+// don't support.
+func validateOutpostConfig(config *eksv1.EKSClusterConfig) error {
+	outpostConfig := config.Spec.OutpostConfig
+	if outpostConfig == nil {
+		return nil
+	}
+
+	if len(outpostConfig.OutpostARNs) == 0 {
+		return fmt.Errorf("field [outpostConfig.outpostArns] cannot be empty for cluster [%s (id: %s)]", config.Spec.DisplayName, config.Name)
+	}
+	if len(outpostConfig.OutpostARNs) > 1 {
+		return fmt.Errorf("field [outpostConfig.outpostArns] can only contain a single outpost for cluster [%s (id: %s)]", config.Spec.DisplayName, config.Name)
+	}
+	if parsed, err := arn.Parse(outpostConfig.OutpostARNs[0]); err != nil || parsed.Service != "outposts" {
+		return fmt.Errorf("field [outpostConfig.outpostArns] must contain the ARN of an outpost for cluster [%s (id: %s)], got [%s]",
+			config.Spec.DisplayName, config.Name, outpostConfig.OutpostARNs[0])
+	}
+	if outpostConfig.ControlPlaneInstanceType == "" {
+		return fmt.Errorf("field [outpostConfig.controlPlaneInstanceType] cannot be empty for cluster [%s (id: %s)]", config.Spec.DisplayName, config.Name)
+	}
+	if len(config.Spec.Subnets) == 0 {
+		return fmt.Errorf("subnets on the outpost must be provided for local cluster [%s (id: %s)] on AWS Outposts", config.Spec.DisplayName, config.Name)
+	}
+	if aws.ToBool(config.Spec.PublicAccess) {
+		return fmt.Errorf("public access is not supported for local cluster [%s (id: %s)] on AWS Outposts", config.Spec.DisplayName, config.Name)
+	}
+	if len(config.Spec.NodeGroups) != 0 {
+		return fmt.Errorf("managed node groups are not supported for local cluster [%s (id: %s)] on AWS Outposts, use self-managed nodes instead", config.Spec.DisplayName, config.Name)
+	}
+	if aws.ToBool(config.Spec.EBSCSIDriver) {
+		return fmt.Errorf("the ebs csi driver add-on is not supported for local cluster [%s (id: %s)] on AWS Outposts", config.Spec.DisplayName, config.Name)
+	}
+	if len(config.Spec.PodIdentityAssociations) != 0 {
+		return fmt.Errorf("pod identity associations are not supported for local cluster [%s (id: %s)] on AWS Outposts, use IAM roles for service accounts instead", config.Spec.DisplayName, config.Name)
+	}
+	if aws.ToBool(config.Spec.ContainerInsights) {
+		return fmt.Errorf("the container insights add-on is not supported for local cluster [%s (id: %s)] on AWS Outposts", config.Spec.DisplayName, config.Name)
+	}
+
+	return nil
+}
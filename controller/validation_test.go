@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateServiceRolePolicyARNs(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{DisplayName: "test"}}
+	assert.NoError(t, validateServiceRolePolicyARNs(config))
+
+	config.Spec.ServiceRolePolicyARNs = []string{"arn:aws:iam::aws:policy/AmazonEKSComputePolicy", "arn:aws:iam::123456789012:policy/custom"}
+	assert.NoError(t, validateServiceRolePolicyARNs(config))
+
+	config.Spec.ServiceRolePolicyARNs = []string{"AmazonEKSComputePolicy"}
+	assert.Error(t, validateServiceRolePolicyARNs(config))
+	config.Spec.ServiceRolePolicyARNs = []string{"arn:aws:iam::123456789012:role/custom"}
+	assert.Error(t, validateServiceRolePolicyARNs(config))
+
+	// policies of provided service roles are managed by their owners
+	config.Spec.ServiceRolePolicyARNs = []string{"arn:aws:iam::aws:policy/AmazonEKSComputePolicy"}
+	config.Spec.ServiceRole = aws.String("role")
+	assert.Error(t, validateServiceRolePolicyARNs(config))
+}
+
+func TestValidateIAMRoleOptions(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{DisplayName: "test"}}
+	assert.NoError(t, validateIAMRoleOptions(config))
+
+	config.Spec.IAMPermissionsBoundary = "arn:aws:iam::123456789012:policy/boundary"
+	config.Spec.NodeInstanceRolePolicyARNs = []string{"arn:aws:iam::aws:policy/AmazonSSMManagedInstanceCore"}
+	assert.NoError(t, validateIAMRoleOptions(config))
+
+	config.Spec.IAMPermissionsBoundary = "boundary"
+	assert.Error(t, validateIAMRoleOptions(config))
+
+	config.Spec.IAMPermissionsBoundary = ""
+	config.Spec.NodeInstanceRolePolicyARNs = []string{"arn:aws:iam::123456789012:role/custom"}
+	assert.Error(t, validateIAMRoleOptions(config))
+
+	config.Spec.NodeInstanceRolePolicyARNs = nil
+	config.Spec.NodeRole = aws.String("arn:aws:iam::123456789012:role/shared-nodes")
+	assert.NoError(t, validateIAMRoleOptions(config))
+
+	config.Spec.NodeRole = aws.String("arn:aws:iam::123456789012:instance-profile/shared-nodes")
+	assert.Error(t, validateIAMRoleOptions(config))
+
+	// the node instance role isn't created when the shared node role is set
+	config.Spec.NodeRole = aws.String("arn:aws:iam::123456789012:role/shared-nodes")
+	config.Spec.NodeInstanceRolePolicyARNs = []string{"arn:aws:iam::aws:policy/AmazonSSMManagedInstanceCore"}
+	assert.Error(t, validateIAMRoleOptions(config))
+}
+
+func TestValidateOutpostConfig(t *testing.T) {
+	valid := &eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{
+		DisplayName: "test",
+		Subnets:     []string{"subnet-1"},
+		OutpostConfig: &eksv1.OutpostConfig{
+			OutpostARNs:                    []string{"arn:aws:outposts:us-west-2:123456789012:outpost/op-1234567890abcdef0"},
+			ControlPlaneInstanceType:       "m5.large",
+			ControlPlanePlacementGroupName: "control-plane",
+		},
+	}}
+	assert.NoError(t, validateOutpostConfig(valid))
+
+	for name, invalidate := range map[string]func(config *eksv1.EKSClusterConfig){
+		"no outposts": func(config *eksv1.EKSClusterConfig) { config.Spec.OutpostConfig.OutpostARNs = nil },
+		"several outposts": func(config *eksv1.EKSClusterConfig) {
+			config.Spec.OutpostConfig.OutpostARNs = append(config.Spec.OutpostConfig.OutpostARNs, "arn:aws:outposts:us-west-2:123456789012:outpost/op-2")
+		},
+		"not an outpost": func(config *eksv1.EKSClusterConfig) {
+			config.Spec.OutpostConfig.OutpostARNs = []string{"op-1234567890abcdef0"}
+		},
+		"no control plane type": func(config *eksv1.EKSClusterConfig) { config.Spec.OutpostConfig.ControlPlaneInstanceType = "" },
+		"no subnets":            func(config *eksv1.EKSClusterConfig) { config.Spec.Subnets = nil },
+		"public access":         func(config *eksv1.EKSClusterConfig) { config.Spec.PublicAccess = aws.Bool(true) },
+		"managed node groups":   func(config *eksv1.EKSClusterConfig) { config.Spec.NodeGroups = []eksv1.NodeGroup{{}} },
+		"ebs csi driver":        func(config *eksv1.EKSClusterConfig) { config.Spec.EBSCSIDriver = aws.Bool(true) },
+		"pod identity association": func(config *eksv1.EKSClusterConfig) {
+			config.Spec.PodIdentityAssociations = []eksv1.PodIdentityAssociation{{}}
+		},
+		"container insights": func(config *eksv1.EKSClusterConfig) { config.Spec.ContainerInsights = aws.Bool(true) },
+	} {
+		invalid := valid.DeepCopy()
+		invalidate(invalid)
+		assert.Error(t, validateOutpostConfig(invalid), name)
+	}
+}
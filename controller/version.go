@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/utils"
+)
+
+// normalizeVersions rewrites the kubernetes versions of the cluster and its node groups in the major.minor format EKS
+// reports them in and returns whether any changed. Versions that can't be normalized are left for validation to reject.
+func normalizeVersions(config *eksv1.EKSClusterConfig) bool {
+	var changed bool
+	normalize := func(version *string) *string {
+		if version == nil {
+			return nil
+		}
+		normalized, err := utils.NormalizeKubernetesVersion(*version)
+		if err != nil || normalized == *version {
+			return version
+		}
+		changed = true
+		return aws.String(normalized)
+	}
+
+	config.Spec.KubernetesVersion = normalize(config.Spec.KubernetesVersion)
+	for i := range config.Spec.NodeGroups {
+		config.Spec.NodeGroups[i].Version = normalize(config.Spec.NodeGroups[i].Version)
+	}
+	return changed
+}
+
+// applyVersionNormalization stores the normalized kubernetes versions in the spec of the config, so that versions
+// supplied as e.g. v1.30 compare equal to the versions of the upstream cluster.
+func (h *Handler) applyVersionNormalization(config *eksv1.EKSClusterConfig) (*eksv1.EKSClusterConfig, error) {
+	normalized := config.DeepCopy()
+	if !normalizeVersions(normalized) {
+		return config, nil
+	}
+
+	updated, err := h.eksCC.Update(normalized)
+	if err != nil {
+		return config, fmt.Errorf("error normalizing kubernetes versions for config [%s (id: %s)]: %w", config.Spec.DisplayName, config.Name, err)
+	}
+	return updated, nil
+}
@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/utils"
+)
+
+func TestNormalizeKubernetesVersion(t *testing.T) {
+	tests := []struct {
+		version     string
+		expected    string
+		expectedErr bool
+	}{
+		{version: "1.30", expected: "1.30"},
+		{version: "v1.30", expected: "1.30"},
+		{version: " V1.29 ", expected: "1.29"},
+		{version: "1.30.0", expectedErr: true},
+		{version: "1.30.x", expectedErr: true},
+		{version: "1", expectedErr: true},
+		{version: "1.030", expectedErr: true},
+		{version: "1.-1", expectedErr: true},
+		{version: "", expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			version, err := utils.NormalizeKubernetesVersion(tt.version)
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, version)
+		})
+	}
+}
+
+func TestNormalizeVersions(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{
+		KubernetesVersion: aws.String("v1.30"),
+		NodeGroups: []eksv1.NodeGroup{
+			{NodegroupName: aws.String("ng1"), Version: aws.String("1.30")},
+			{NodegroupName: aws.String("ng2"), Version: aws.String("v1.29")},
+			{NodegroupName: aws.String("ng3"), Version: aws.String("1.29.1")},
+			{NodegroupName: aws.String("ng4")},
+		},
+	}}
+
+	assert.True(t, normalizeVersions(config))
+	assert.Equal(t, "1.30", aws.ToString(config.Spec.KubernetesVersion))
+	assert.Equal(t, "1.30", aws.ToString(config.Spec.NodeGroups[0].Version))
+	assert.Equal(t, "1.29", aws.ToString(config.Spec.NodeGroups[1].Version))
+	// invalid versions are rejected by validation instead
+	assert.Equal(t, "1.29.1", aws.ToString(config.Spec.NodeGroups[2].Version))
+	assert.Nil(t, config.Spec.NodeGroups[3].Version)
+
+	assert.False(t, normalizeVersions(config))
+}
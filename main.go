@@ -5,30 +5,157 @@ package main
 
 import (
 	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	// time zones of scaling windows are loaded from the embedded database, the image doesn't ship one
+	_ "time/tzdata"
 
 	"github.com/rancher/eks-operator/controller"
+	eksapiv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	awsservices "github.com/rancher/eks-operator/pkg/eks"
+	"github.com/rancher/eks-operator/pkg/eks/services"
+	"github.com/rancher/eks-operator/pkg/features"
 	eksv1 "github.com/rancher/eks-operator/pkg/generated/controllers/eks.cattle.io"
+	"github.com/rancher/eks-operator/pkg/metrics"
 	"github.com/rancher/wrangler/v3/pkg/generated/controllers/apps"
 	core3 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core"
 	"github.com/rancher/wrangler/v3/pkg/kubeconfig"
+	"github.com/rancher/wrangler/v3/pkg/schemes"
 	"github.com/rancher/wrangler/v3/pkg/signals"
 	"github.com/rancher/wrangler/v3/pkg/start"
 	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
 )
 
 var (
 	masterURL      string
 	kubeconfigFile string
 	debug          bool
+	metricsAddress string
+	costEstimation bool
+	dryRun         bool
+
+	creatingInterval time.Duration
+	updatingInterval time.Duration
+	deletingInterval time.Duration
+	stackInterval    time.Duration
+
+	awsRateLimit           services.RateLimitOpts
+	maxConcurrentDeletions int
+	awsEndpoints           services.EndpointOpts
+	awsTransport           services.TransportOpts
+	awsCABundle            string
+
+	nodeGroupDefaults controller.NodeGroupDefaults
+	resourceTags      = map[string]string{}
+
+	workers           int
+	controllerWorkers = map[string]int{}
 )
 
+// workerKinds are the kinds whose controllers can be given their own number of workers with --controller-workers
+var workerKinds = []string{"EKSClusterConfig", "EKSInventory", "EKSClusterTemplate"}
+
 func init() {
 	flag.StringVar(&kubeconfigFile, "kubeconfig", "", "Path to a kubeconfig. Only required if out-of-cluster.")
 	flag.StringVar(&masterURL, "master", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig. Only required if out-of-cluster.")
 	flag.BoolVar(&debug, "debug", false, "Variable to set log level to debug; default is false")
+	flag.StringVar(&metricsAddress, "metrics-address", "", "The address to serve controller and workqueue metrics on, e.g. :8080. Metrics are disabled if empty.")
+	flag.BoolVar(&costEstimation, "cost-estimation", false, "Estimate the monthly cost of clusters with the AWS Price List API before creating them and record it on their status.")
+	flag.BoolVar(&dryRun, "dry-run", false, "Log the requests that would change something in AWS, and the cordons and evictions of node group drains, instead of sending them, e.g. to validate a new version of the operator against existing clusters.")
+	flag.DurationVar(&creatingInterval, "creating-interval", durationFromEnv("EKS_OPERATOR_CREATING_INTERVAL"), "How often clusters that are being created are checked on, e.g. 1m. Defaults to 30s, can be set with EKS_OPERATOR_CREATING_INTERVAL.")
+	flag.DurationVar(&updatingInterval, "updating-interval", durationFromEnv("EKS_OPERATOR_UPDATING_INTERVAL"), "How often clusters that are being updated are checked on, e.g. 1m. Defaults to 30s, can be set with EKS_OPERATOR_UPDATING_INTERVAL.")
+	flag.DurationVar(&deletingInterval, "deleting-interval", durationFromEnv("EKS_OPERATOR_DELETING_INTERVAL"), "How often clusters that are being deleted are checked on, e.g. 1m. Defaults to 10s, can be set with EKS_OPERATOR_DELETING_INTERVAL.")
+	flag.DurationVar(&stackInterval, "stack-interval", durationFromEnv("EKS_OPERATOR_STACK_INTERVAL"), "How often the CloudFormation stacks being created for clusters are checked on, e.g. 1m. Defaults to 10s, can be set with EKS_OPERATOR_STACK_INTERVAL.")
+	flag.Float64Var(&awsRateLimit.QPS, "aws-qps", 0, "The number of requests per second sent to AWS across all clusters. Requests aren't limited if zero.")
+	flag.IntVar(&awsRateLimit.Burst, "aws-burst", 0, "The number of requests that can be sent to AWS at once. Defaults to --aws-qps rounded up.")
+	flag.StringVar(&awsRateLimit.RetryMode, "aws-retry-mode", "", "The retry mode of AWS requests, standard or adaptive. Adaptive slows down all requests once AWS throttles them.")
+	flag.IntVar(&awsRateLimit.MaxAttempts, "aws-max-attempts", 0, "The maximum number of attempts of an AWS request. The SDK default is used if zero.")
+	flag.DurationVar(&awsRateLimit.MaxBackoff, "aws-max-backoff", 0, "The longest wait between retries of an AWS request, e.g. 30s. The SDK default is used if zero.")
+	flag.IntVar(&maxConcurrentDeletions, "max-concurrent-deletions", 0, "The number of clusters whose teardown runs at once, the others are requeued until one of them fails, waits for AWS or finishes. Not limited if zero.")
+	flag.Func("aws-endpoints", "Comma separated service=URL pairs overriding the endpoints of AWS services, e.g. eks=https://vpce-123.eks.{region}.vpce.amazonaws.com. {region} is replaced with the region of the cluster.", func(value string) error {
+		urls, err := services.ParseEndpointURLs(value)
+		awsEndpoints.URLs = urls
+		return err
+	})
+	flag.BoolVar(&awsEndpoints.FIPS, "aws-fips", false, "Send requests to the FIPS endpoints of the AWS services whose endpoints aren't overridden.")
+	flag.StringVar(&awsTransport.Proxy, "aws-proxy", "", "The URL of the proxy requests to AWS and OIDC issuers are sent through. HTTP_PROXY and HTTPS_PROXY are used if neither --aws-proxy nor --aws-no-proxy is set.")
+	flag.StringVar(&awsTransport.NoProxy, "aws-no-proxy", "", "Comma separated hosts, domains and CIDRs that are reached without --aws-proxy.")
+	flag.StringVar(&awsCABundle, "aws-ca-bundle", "", "Path to a file with PEM encoded certificate authorities trusted by requests to AWS and OIDC issuers in addition to the system ones.")
+	flag.StringVar(&nodeGroupDefaults.InstanceType, "default-instance-type", "", "The instance type of node groups that don't set one, e.g. t3.large. Not defaulted if empty.")
+	flag.Func("default-disk-size", "The disk size in GiB of node groups that don't set one, e.g. 50. Not defaulted if unset.", func(value string) error {
+		size, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || size <= 0 {
+			return fmt.Errorf("invalid disk size [%s], must be a positive number of GiB", value)
+		}
+		nodeGroupDefaults.DiskSize = int32(size)
+		return nil
+	})
+	flag.Func("resource-tags", "Comma separated key=value tags set on the AWS resources the operator creates along with the ownership tags, e.g. cost-center=42 for chargeback. Tags set in the spec of a cluster take precedence.",
+		parseResourceTags)
+	flag.IntVar(&workers, "workers", 3, "The number of objects each controller reconciles at once.")
+	flag.Func("controller-workers", fmt.Sprintf("Comma separated Kind=workers pairs overriding --workers for the controllers of single kinds, e.g. EKSClusterConfig=10. Known kinds are %s.",
+		strings.Join(workerKinds, ", ")), parseControllerWorkers)
+	flag.Func("feature-gates", fmt.Sprintf("Comma separated Feature=true|false pairs enabling or disabling features of the operator. Known features are %s.",
+		strings.Join(features.Known(), ", ")), features.Set)
 	flag.Parse()
 }
 
+// durationFromEnv returns the duration set in the environment variable, or zero so that the controller default is used
+func durationFromEnv(name string) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		logrus.Fatalf("Error parsing %s: %s", name, err.Error())
+	}
+	return duration
+}
+
+// parseControllerWorkers parses the Kind=workers pairs of --controller-workers
+func parseControllerWorkers(value string) error {
+	for _, pair := range strings.Split(value, ",") {
+		if pair == "" {
+			continue
+		}
+		kind, count, ok := strings.Cut(pair, "=")
+		if !ok || !slices.Contains(workerKinds, kind) {
+			return fmt.Errorf("invalid controller workers [%s], must be Kind=workers with one of the kinds %s", pair, strings.Join(workerKinds, ", "))
+		}
+		n, err := strconv.Atoi(count)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid number of workers [%s] for %s, must be a positive number", count, kind)
+		}
+		controllerWorkers[kind] = n
+	}
+	return nil
+}
+
+// parseResourceTags parses the key=value pairs of --resource-tags
+func parseResourceTags(value string) error {
+	for _, pair := range strings.Split(value, ",") {
+		if pair == "" {
+			continue
+		}
+		key, tagValue, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("invalid resource tag [%s], must be key=value", pair)
+		}
+		resourceTags[key] = tagValue
+	}
+	return nil
+}
+
 func main() {
 	// set up signals so we handle the first shutdown signal gracefully
 	ctx := signals.SetupSignalContext()
@@ -38,12 +165,53 @@ func main() {
 		logrus.Debugf("Loglevel set to [%v]", logrus.DebugLevel)
 	}
 
+	if workers <= 0 {
+		logrus.Fatalf("Invalid number of workers [%d], must be a positive number", workers)
+	}
+
 	// This will load the kubeconfig file in a style the same as kubectl
 	cfg, err := kubeconfig.GetNonInteractiveClientConfig(kubeconfigFile).ClientConfig()
 	if err != nil {
 		logrus.Fatalf("Error building kubeconfig: %s", err.Error())
 	}
 
+	for _, state := range features.States() {
+		logrus.Infof("Feature [%s] (%s) enabled: %t", state.Feature, state.Stage, state.Enabled)
+	}
+
+	if err := services.SetRateLimiting(awsRateLimit); err != nil {
+		logrus.Fatalf("Error configuring AWS rate limiting: %s", err.Error())
+	}
+
+	if dryRun {
+		logrus.Warn("Running in dry run mode, requests that would change something in AWS are logged instead of sent")
+	}
+	services.SetDryRun(dryRun)
+
+	if err := services.SetEndpoints(awsEndpoints); err != nil {
+		logrus.Fatalf("Error configuring AWS endpoints: %s", err.Error())
+	}
+
+	if awsCABundle != "" {
+		awsTransport.CABundle, err = os.ReadFile(awsCABundle)
+		if err != nil {
+			logrus.Fatalf("Error reading AWS CA bundle: %s", err.Error())
+		}
+	}
+	if err := services.SetTransport(awsTransport); err != nil {
+		logrus.Fatalf("Error configuring AWS transport: %s", err.Error())
+	}
+
+	if err := awsservices.SetResourceTags(resourceTags); err != nil {
+		logrus.Fatalf("Error configuring resource tags: %s", err.Error())
+	}
+
+	if metricsAddress != "" {
+		// metrics have to be registered before the controllers are created
+		metrics.Register()
+		metrics.Serve(ctx, metricsAddress)
+	}
+
 	// Generated apps controller
 	apps := apps.NewFactoryFromConfigOrDie(cfg)
 	// core
@@ -57,16 +225,49 @@ func main() {
 	if err != nil {
 		logrus.Fatalf("Error building eks factory: %s", err.Error())
 	}
+	// the workers of single kinds have to be set before their controllers are created
+	for kind, n := range controllerWorkers {
+		eks.SetThreadiness(eksapiv1.SchemeGroupVersion.WithKind(kind), n)
+	}
+
+	// Event recorder used to surface cluster lifecycle transitions on EKSClusterConfig objects
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		logrus.Fatalf("Error building kubernetes clientset: %s", err.Error())
+	}
+	// Status is written with server-side apply, which the generated clients don't support
+	dynamicClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		logrus.Fatalf("Error building dynamic client: %s", err.Error())
+	}
+
+	broadcaster := record.NewBroadcaster(record.WithContext(ctx))
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: clientset.CoreV1().Events("")})
+	recorder := broadcaster.NewRecorder(schemes.All, corev1.EventSource{Component: "eks-operator"})
 
 	// The typical pattern is to build all your controller/clients then just pass to each handler
 	// the bare minimum of what they need.  This will eventually help with writing tests.  So
 	// don't pass in something like kubeClient, apps, or sample
 	controller.Register(ctx,
 		core.Core().V1().Secret(),
-		eks.Eks().V1().EKSClusterConfig())
+		core.Core().V1().ConfigMap(),
+		eks.Eks().V1().EKSClusterConfig(),
+		eks.Eks().V1().EKSInventory(),
+		eks.Eks().V1().EKSClusterTemplate(),
+		dynamicClient,
+		recorder,
+		&controller.RegisterOpts{
+			CostEstimation:         costEstimation,
+			CreatingInterval:       creatingInterval,
+			UpdatingInterval:       updatingInterval,
+			DeletingInterval:       deletingInterval,
+			StackInterval:          stackInterval,
+			NodeGroupDefaults:      nodeGroupDefaults,
+			MaxConcurrentDeletions: maxConcurrentDeletions,
+		})
 
 	// Start all the controllers
-	if err := start.All(ctx, 3, apps, eks, core); err != nil {
+	if err := start.All(ctx, workers, apps, eks, core); err != nil {
 		logrus.Fatalf("Error starting: %s", err.Error())
 	}
 
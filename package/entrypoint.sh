@@ -6,4 +6,4 @@ if [ -x "$(command -v c_rehash)" ]; then
   # and the eks-operator container is run as non-root user.
   c_rehash
 fi
-eks-operator
+exec eks-operator "$@"
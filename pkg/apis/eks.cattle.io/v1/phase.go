@@ -0,0 +1,48 @@
+package v1
+
+import "fmt"
+
+// Phase is the lifecycle phase of an EKSClusterConfig, recorded on its status.
+type Phase string
+
+const (
+	// PhaseNotCreated is the phase of configs that haven't been picked up by the operator yet.
+	PhaseNotCreated Phase = ""
+	// PhaseCreating is the phase of clusters that are being created.
+	PhaseCreating Phase = "creating"
+	// PhaseImporting is the phase of existing clusters that are being imported.
+	PhaseImporting Phase = "importing"
+	// PhaseActive is the phase of clusters that match their spec.
+	PhaseActive Phase = "active"
+	// PhaseUpdating is the phase of clusters that are being updated to their spec, or failed to be.
+	PhaseUpdating Phase = "updating"
+)
+
+// phases are all known phases, new phases have to be added here to pass validation.
+var phases = []Phase{PhaseNotCreated, PhaseCreating, PhaseImporting, PhaseActive, PhaseUpdating}
+
+// IsTerminal returns whether the cluster is at rest in the phase, so that the operator only acts on it again once its
+// spec or the upstream cluster changes.
+func (p Phase) IsTerminal() bool {
+	return p == PhaseActive
+}
+
+// IsTransitional returns whether the cluster is moving to another phase, so that the operator keeps checking on it
+// until it gets there.
+func (p Phase) IsTransitional() bool {
+	switch p {
+	case PhaseCreating, PhaseImporting, PhaseUpdating:
+		return true
+	}
+	return false
+}
+
+// Validate returns an error if the phase isn't one of the known phases.
+func (p Phase) Validate() error {
+	for _, phase := range phases {
+		if p == phase {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown phase [%s]", p)
+}
@@ -0,0 +1,22 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPhase(t *testing.T) {
+	for _, phase := range phases {
+		assert.NoError(t, phase.Validate(), phase)
+		assert.False(t, phase.IsTerminal() && phase.IsTransitional(), "phase [%s] can't be terminal and transitional", phase)
+	}
+	assert.Error(t, Phase("deleting").Validate())
+
+	assert.True(t, PhaseActive.IsTerminal())
+	assert.False(t, PhaseUpdating.IsTerminal())
+	assert.True(t, PhaseCreating.IsTransitional())
+	assert.True(t, PhaseImporting.IsTransitional())
+	assert.True(t, PhaseUpdating.IsTransitional())
+	assert.False(t, PhaseNotCreated.IsTransitional())
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package v1
 
 import (
+	"github.com/rancher/wrangler/v3/pkg/genericcondition"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -31,7 +32,73 @@ type EKSClusterConfig struct {
 	Status EKSClusterConfigStatus `json:"status"`
 }
 
-// EKSClusterConfigSpec is the spec for a EKSClusterConfig resource
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// EKSInventory lists the EKS clusters visible to an AWS credential that are not represented by an EKSClusterConfig yet,
+// so that they can be offered for import. The operator refreshes the status periodically.
+type EKSInventory struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   EKSInventorySpec   `json:"spec"`
+	Status EKSInventoryStatus `json:"status"`
+}
+
+type EKSInventorySpec struct {
+	AmazonCredentialSecret string `json:"amazonCredentialSecret"`
+	// regions the clusters are discovered in
+	Regions []string `json:"regions"`
+}
+
+type EKSInventoryStatus struct {
+	// clusters that are not represented by an EKSClusterConfig, sorted by region and name
+	Clusters []DiscoveredCluster `json:"clusters"`
+	// time the clusters were last discovered, in RFC 3339 format
+	LastSyncTime string `json:"lastSyncTime"`
+	// generation of the spec the clusters were last discovered for
+	ObservedGeneration int64  `json:"observedGeneration"`
+	FailureMessage     string `json:"failureMessage"`
+}
+
+// DiscoveredCluster is an upstream EKS cluster found by an EKSInventory
+type DiscoveredCluster struct {
+	Name              string `json:"name"`
+	Region            string `json:"region"`
+	ARN               string `json:"arn"`
+	KubernetesVersion string `json:"kubernetesVersion"`
+	Status            string `json:"status"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// EKSClusterTemplate is a blueprint for EKSClusterConfigs in its namespace. Configs that reference it are rendered from
+// its template, with the fields set in the config taking precedence.
+type EKSClusterTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec EKSClusterTemplateSpec `json:"spec"`
+}
+
+type EKSClusterTemplateSpec struct {
+	// the fields rendered into the referencing configs, fields that are unset aren't rendered. The display name is
+	// always taken from the config.
+	Template EKSClusterConfigSpec `json:"template"`
+}
+
+// TemplateReference references the EKSClusterTemplate a config is rendered from
+type TemplateReference struct {
+	// name of the template in the namespace of the config
+	Name string `json:"name"`
+	// generation of the template the config is rendered from, so that template changes are only rolled out to the
+	// config once it is raised to the new generation. The config follows the latest generation if it is 0.
+	Generation int64 `json:"generation"`
+}
+
+// EKSClusterConfigSpec is the spec for a EKSClusterConfig resource. Optional slice and map fields that are unset (null)
+// are left as they are upstream, while empty values clear them, e.g. empty loggingTypes disable all control plane logging.
 type EKSClusterConfigSpec struct {
 	AmazonCredentialSecret string            `json:"amazonCredentialSecret"`
 	DisplayName            string            `json:"displayName" norman:"noupdate"`
@@ -39,21 +106,183 @@ type EKSClusterConfigSpec struct {
 	Imported               bool              `json:"imported" norman:"noupdate"`
 	KubernetesVersion      *string           `json:"kubernetesVersion" norman:"pointer"`
 	Tags                   map[string]string `json:"tags"`
-	SecretsEncryption      *bool             `json:"secretsEncryption" norman:"noupdate"`
-	KmsKey                 *string           `json:"kmsKey" norman:"noupdate,pointer"`
+	SecretsEncryption      *bool             `json:"secretsEncryption"`
+	KmsKey                 *string           `json:"kmsKey" norman:"pointer"`
 	PublicAccess           *bool             `json:"publicAccess"`
 	PrivateAccess          *bool             `json:"privateAccess"`
 	EBSCSIDriver           *bool             `json:"ebsCSIDriver"`
 	PublicAccessSources    []string          `json:"publicAccessSources"`
 	LoggingTypes           []string          `json:"loggingTypes"`
 	Subnets                []string          `json:"subnets" norman:"noupdate"`
-	SecurityGroups         []string          `json:"securityGroups" norman:"noupdate"`
-	ServiceRole            *string           `json:"serviceRole" norman:"noupdate,pointer"`
-	NodeGroups             []NodeGroup       `json:"nodeGroups"`
+	// VPCMode selects the VPC generated when no subnets are provided, public (default) or private
+	VPCMode        string         `json:"vpcMode" norman:"noupdate"`
+	SecurityGroups []string       `json:"securityGroups"`
+	ServiceRole    *string        `json:"serviceRole" norman:"noupdate,pointer"`
+	NodeGroups     []NodeGroup    `json:"nodeGroups"`
+	OutpostConfig  *OutpostConfig `json:"outpostConfig" norman:"noupdate"`
+	// whether the kubernetes.io/cluster/<name> tags are removed from the provided subnets and security groups when
+	// the cluster is deleted
+	CleanupClusterTags *bool `json:"cleanupClusterTags"`
+	// IAM roles assumed by the pods of service accounts through EKS Pod Identity, the eks-pod-identity-agent add-on is
+	// installed when there is at least one
+	PodIdentityAssociations []PodIdentityAssociation `json:"podIdentityAssociations"`
+	// external OIDC identity providers associated with the cluster, whose users and groups can authenticate to it
+	IdentityProviderConfigs []IdentityProviderConfig `json:"identityProviderConfigs"`
+	// whether a kubeconfig for the cluster is stored in the <name>-kubeconfig secret, it authenticates through the aws
+	// cli, so its consumers need AWS credentials with access to the cluster
+	GenerateKubeconfig *bool `json:"generateKubeconfig"`
+	// what happens to the AWS resources of the cluster when the config is deleted: delete (default) deletes them,
+	// retain leaves them in place and force doesn't wait on node groups that failed to delete and retains the
+	// resources that fail to delete with their stacks
+	DeletionPolicy string `json:"deletionPolicy"`
+	// whether the /aws/eks/<displayName>/cluster CloudWatch log group of the control plane logs is deleted along with
+	// the cluster, it is kept by default and isn't deleted with the retain deletion policy
+	DeleteLogGroup *bool `json:"deleteLogGroup"`
+	// the template the config is rendered from, the fields set in the config override the template
+	TemplateRef *TemplateReference `json:"templateRef"`
+	// ARN of a pre-existing IAM OIDC provider for the issuer of the cluster, the IAM role of the ebs csi driver trusts it
+	// instead of a provider found or created by the operator. The operator never deletes it
+	OIDCProviderARN *string `json:"oidcProviderArn"`
+	// tags added to the security group EKS creates for the cluster along with tags, e.g. karpenter.sh/discovery. Tags
+	// are only ever added to the security group, removing them from the config leaves them in place
+	ClusterSecurityGroupTags map[string]string `json:"clusterSecurityGroupTags"`
+	// sha1 thumbprints of the root certificate of the OIDC issuer of the cluster, used for the IAM OIDC provider the
+	// operator creates for the ebs csi driver instead of fetching the certificate of the issuer, for environments where
+	// outbound TLS is intercepted
+	OIDCThumbprints []string `json:"oidcThumbprints"`
+	// window during which disruptive updates, kubernetes version upgrades and node group rollouts, are made. Outside of
+	// it they are deferred until the window opens, other updates are made right away. Disruptive updates are made at
+	// any time if unset
+	MaintenanceWindow *MaintenanceWindow `json:"maintenanceWindow"`
+	// ARNs of managed policies attached to the service role the operator created for the cluster in addition to the
+	// EKS cluster policies, e.g. for EKS Auto Mode. It can't be set along with serviceRole
+	ServiceRolePolicyARNs []string `json:"serviceRolePolicyArns"`
+	// cordon and drain the nodes of node groups removed from the spec before deleting them, using the endpoint and
+	// certificate authority of the cluster. Node groups are deleted without draining if unset
+	NodeGroupDrain *NodeGroupDrain `json:"nodeGroupDrain"`
+	// import the cluster in EKS with the display name instead of failing to create the cluster if one exists, unless
+	// it is tagged as owned by another config. An adopted cluster is managed like one the operator created, including
+	// its deletion along with the config unless the deletion policy is retain
+	AdoptExisting bool `json:"adoptExisting"`
+	// how the operator verifies the certificate of the API server of the cluster in health probes, drains and the
+	// generated kubeconfig, for private endpoints reached through VPC peering or proxies
+	DownstreamTLS *DownstreamTLS `json:"downstreamTLS"`
+	// whether the k8s.io/cluster-autoscaler/enabled and k8s.io/cluster-autoscaler/<displayName> tags the
+	// cluster-autoscaler discovers node groups by are added to the tags of the node groups, which EKS propagates to their
+	// auto scaling groups. Tags set in the node groups take precedence
+	ClusterAutoscalerTags *bool `json:"clusterAutoscalerTags"`
+	// for imported clusters, the node groups the operator manages. The other node groups, e.g. ones managed by other
+	// tools, are left out of the upstream spec and never updated or deleted, and are ignored if they are in the spec.
+	// All node groups are managed if unset
+	ImportNodeGroupSelector *NodeGroupSelector `json:"importNodeGroupSelector"`
+	// whether the operator only reports the drift between the spec and the cluster in EKS, in the Drifted condition
+	// and the drift of the status, without changing any AWS resources, e.g. during migrations and audits. Paused
+	// clusters aren't created and deleting a paused config leaves its AWS resources in place
+	Paused bool `json:"paused"`
+	// CloudWatch retention of the control plane logs and metric filter and alarm on the audit events of the cluster,
+	// which requires the audit logging type. The resources are removed along with the cluster
+	AuditLogging *AuditLogging `json:"auditLogging"`
+	// name of a ConfigMap in the namespace of the config with CloudFormation templates that replace the built-in ones
+	// when the stacks of the cluster are created. The keys are vpc, privateVpc, serviceRole, nodeInstanceRole,
+	// ebsCsiDriver and ebsCsiDriverPodIdentity, templates that aren't in the ConfigMap are built in. The templates are
+	// Go templates with the ClusterName, Region, EC2ServicePrincipal and, for ebsCsiDriver, ProviderID parameters, and
+	// must have the outputs of the built-in templates. The serviceRole and nodeInstanceRole templates are given the
+	// PermissionsBoundary and AdditionalPolicyArns stack parameters if iamPermissionsBoundary or
+	// nodeInstanceRolePolicyArns are set
+	CloudFormationTemplates string `json:"cloudFormationTemplates"`
+	// custom launch templates shared by several node groups. A node group whose launchTemplate refers to one of them
+	// by ID or name takes its version, so that changing the version here rolls all the node groups referencing it.
+	// A launchTemplate version set on such node groups is ignored
+	SharedLaunchTemplates []LaunchTemplate `json:"sharedLaunchTemplates"`
+	// ARN of the managed policy set as the permissions boundary of the service role and node instance role the
+	// operator creates, as required by many organizations. It's only applied when the roles are created
+	IAMPermissionsBoundary string `json:"iamPermissionsBoundary"`
+	// ARNs of managed policies attached to the node instance role the operator creates in addition to the EKS node
+	// policies, e.g. for SSM. Policies added or removed later are attached to or detached from the role
+	NodeInstanceRolePolicyARNs []string `json:"nodeInstanceRolePolicyArns"`
+	// ARN of a pre-created node instance role used by the node groups without a nodeRole, e.g. a role shared by
+	// several clusters. The operator doesn't create the node instance role when it's set. It's only used for node
+	// groups created after it's set, as the role of a node group can't be changed
+	NodeRole *string `json:"nodeRole" norman:"pointer"`
+	// ARNs of IAM users or roles granted break-glass admin access to the cluster through access entries with the
+	// AmazonEKSClusterAdminPolicy. The authentication mode of the cluster is changed to API_AND_CONFIG_MAP if needed,
+	// which can't be undone. Removing a principal deletes its access entry if the operator created it
+	ClusterAdminPrincipals []string `json:"clusterAdminPrincipals"`
+	// whether CloudWatch Container Insights is enabled through the amazon-cloudwatch-observability add-on. The
+	// CloudWatchAgentServerPolicy is attached to the node instance role the operator creates. The operator doesn't
+	// change the roles it didn't create, so it must be attached to the shared nodeRole and the nodeRole of node groups
+	// as well. Disabling it removes the add-on and detaches the policy from the node instance role the operator
+	// created. It's not supported on AWS Outposts
+	ContainerInsights *bool `json:"containerInsights"`
+}
+
+// AuditLogging configures the CloudWatch log group of the control plane logs of a cluster and a metric filter counting
+// its audit events, optionally with an alarm on the count.
+type AuditLogging struct {
+	// number of days the control plane logs are kept, one of the values CloudWatch supports, e.g. 90 or 365. The logs
+	// never expire if zero
+	RetentionInDays int32 `json:"retentionInDays"`
+	// CloudWatch filter pattern selecting the audit events that are counted, e.g. { $.verb = "delete" }. Defaults to
+	// requests that were forbidden
+	FilterPattern string `json:"filterPattern"`
+	// number of matching audit events within alarmPeriodSeconds that sets off the alarm. No alarm is created if zero
+	AlarmThreshold int32 `json:"alarmThreshold"`
+	// period the matching audit events are counted over, 300 if zero
+	AlarmPeriodSeconds int32 `json:"alarmPeriodSeconds"`
+	// ARNs of the actions taken when the alarm goes off, e.g. SNS topics
+	AlarmActions []string `json:"alarmActions"`
+}
+
+// NodeGroupSelector selects node groups by their name and kubernetes labels, a node group is selected if it matches
+// both.
+type NodeGroupSelector struct {
+	// names of the node groups, shell patterns like team-a-* are supported. Node groups of any name match if empty
+	Names []string `json:"names"`
+	// kubernetes labels the node groups must have
+	MatchLabels map[string]string `json:"matchLabels"`
+}
+
+// DownstreamTLS overrides the verification of the certificate of the API server of the cluster.
+type DownstreamTLS struct {
+	// host name the certificate is verified against instead of the host of the endpoint, e.g. the host name of the
+	// endpoint when it is reached through a proxy
+	ServerName string `json:"serverName"`
+	// PEM encoded certificates trusted in addition to the certificate authority of the cluster, e.g. of a proxy that
+	// terminates TLS
+	CABundle string `json:"caBundle"`
+}
+
+// IdentityProviderConfig is an OIDC identity provider associated with the cluster. Identity provider configs can't be
+// modified upstream, so changing one disassociates and associates it again.
+type IdentityProviderConfig struct {
+	Name           string            `json:"name"`
+	IssuerURL      string            `json:"issuerUrl"`
+	ClientID       string            `json:"clientId"`
+	UsernameClaim  string            `json:"usernameClaim"`
+	UsernamePrefix string            `json:"usernamePrefix"`
+	GroupsClaim    string            `json:"groupsClaim"`
+	GroupsPrefix   string            `json:"groupsPrefix"`
+	RequiredClaims map[string]string `json:"requiredClaims"`
+}
+
+// PodIdentityAssociation associates an IAM role with a service account in the cluster
+type PodIdentityAssociation struct {
+	Namespace      string `json:"namespace"`
+	ServiceAccount string `json:"serviceAccount"`
+	RoleARN        string `json:"roleArn"`
+}
+
+// OutpostConfig configures the cluster as a local cluster with its control plane running on AWS Outposts
+type OutpostConfig struct {
+	OutpostARNs              []string `json:"outpostArns"`
+	ControlPlaneInstanceType string   `json:"controlPlaneInstanceType"`
+	// name of an existing placement group the control plane instances are launched in, e.g. a spread placement group
+	// so that they run on different hosts of the outpost
+	ControlPlanePlacementGroupName string `json:"controlPlanePlacementGroupName"`
 }
 
 type EKSClusterConfigStatus struct {
-	Phase                         string            `json:"phase"`
+	Phase                         Phase             `json:"phase"`
 	VirtualNetwork                string            `json:"virtualNetwork"`
 	Subnets                       []string          `json:"subnets"`
 	SecurityGroups                []string          `json:"securityGroups"`
@@ -64,6 +293,216 @@ type EKSClusterConfigStatus struct {
 	NetworkFieldsSource string `json:"networkFieldsSource"`
 	FailureMessage      string `json:"failureMessage"`
 	GeneratedNodeRole   string `json:"generatedNodeRole"`
+	// names of node groups that had deletion protection enabled the last time they were present in the spec
+	DeletionProtectedNodeGroups []string                            `json:"deletionProtectedNodeGroups"`
+	Conditions                  []genericcondition.GenericCondition `json:"conditions"`
+	// CloudFormation stacks created by the operator for the cluster, recorded under their canonical names
+	CloudFormationStacks []CloudFormationStack `json:"cloudFormationStacks"`
+	// whether stacks created before they were recorded on the status, possibly with legacy names, have been discovered
+	CloudFormationStacksMigrated bool `json:"cloudFormationStacksMigrated"`
+	// sha256 hashes of the userdata in the rancher-managed launch template version of each node group, keyed by node
+	// group name, so that userdata drift can be detected without storing it outside of AWS
+	NodeGroupUserDataHashes map[string]string `json:"nodeGroupUserDataHashes"`
+	// estimated monthly cost of the cluster, only set when cost estimation is enabled on the operator
+	CostEstimate *CostEstimate `json:"costEstimate"`
+	// generation of the spec the last upstream update was sent for
+	UpdateGeneration int64 `json:"updateGeneration"`
+	// generation of the spec the upstream cluster last finished updating to, the spec has been fully applied when it
+	// matches metadata.generation
+	ObservedGeneration int64 `json:"observedGeneration"`
+	// value of the eks.cattle.io/export annotation the cluster was last exported for
+	LastExportRequest string `json:"lastExportRequest"`
+	// stage the teardown of a deleting cluster has reached, the teardown resumes from it after a restart. Valid values
+	// are nodeGroups, addons, launchTemplate, cluster, stacks, oidcProvider and done
+	DeletionStage string `json:"deletionStage"`
+	// progress of the version updates that are replacing the nodes of node groups, keyed by node group name
+	NodeGroupRollouts map[string]NodeGroupRollout `json:"nodeGroupRollouts"`
+	// ARN of the IAM OIDC provider the operator created for the ebs csi driver, it is deleted along with the cluster
+	OIDCProviderARN string `json:"oidcProviderArn"`
+	// node capacity of the upstream node groups of the cluster, so that it can be displayed without querying AWS
+	Capacity *CapacitySummary `json:"capacity"`
+	// ID of the security group EKS created for the cluster
+	ClusterSecurityGroup string `json:"clusterSecurityGroup"`
+	// name of the scaling window of each node group that is active, keyed by node group name
+	NodeGroupScalingWindows map[string]string `json:"nodeGroupScalingWindows"`
+	// ARN of the upstream cluster
+	ClusterARN string `json:"clusterArn"`
+	// URL of the API server of the upstream cluster
+	Endpoint string `json:"endpoint"`
+	// URL of the OIDC issuer of the upstream cluster, used to create IAM roles for service accounts
+	OIDCIssuer string `json:"oidcIssuer"`
+	// EKS platform version of the upstream cluster
+	PlatformVersion string `json:"platformVersion"`
+	// status, health issues and AMI release version of each upstream node group, keyed by node group name
+	NodeGroupStatuses map[string]NodeGroupStatus `json:"nodeGroupStatuses"`
+	// updates sent to EKS that haven't finished yet, they are checked on until they do so that the errors of the ones
+	// that fail are recorded in failureMessage
+	InProgressUpdates []EKSUpdate `json:"inProgressUpdates"`
+	// number of AWS resources with account or region quotas that the cluster consumes, so that capacity across an
+	// account can be planned from the management cluster
+	ResourceUsage *ResourceUsage `json:"resourceUsage"`
+	// ARNs of the additional policies the operator applied to the service role, through the parameter of its stack or
+	// through IAM for roles created without CloudFormation, where only those are detached when removed from the spec
+	ServiceRolePolicyARNs []string `json:"serviceRolePolicyArns"`
+	// ARNs of the additional policies the operator applied to the node instance role it created, including the policy
+	// of the CloudWatch agent while Container Insights is enabled, through the parameter of its stack or through IAM for
+	// roles created without CloudFormation, where only those are detached when removed
+	NodeInstanceRolePolicyARNs []string `json:"nodeInstanceRolePolicyArns"`
+	// backend the operator created the service and node instance roles with. Valid values are cloudformation and
+	// native, roles are created through IAM directly when CloudFormation is unavailable in the region
+	ProvisioningBackend string `json:"provisioningBackend"`
+	// time the drain of each node group being deleted started, in RFC 3339 format, keyed by node group name
+	NodeGroupDrainStartTimes map[string]string `json:"nodeGroupDrainStartTimes"`
+	// configuration of an imported cluster as it was in EKS when it was imported, so that it can be shown before the
+	// spec is filled in from it
+	UpstreamSpec *UpstreamSpec `json:"upstreamSpec"`
+	// fields of the spec that differ from the cluster in EKS while the config is paused
+	Drift []string `json:"drift"`
+	// audit logging configuration that was last applied, so that the resources of settings removed from the spec are
+	// removed as well
+	AuditLogging *AuditLogging `json:"auditLogging"`
+	// node groups that are deleted to be created again with changes EKS can't update, they are created once the
+	// deletion finished. Node groups removed from the spec in the meantime are dropped
+	RecreatingNodeGroups []string `json:"recreatingNodeGroups"`
+	// AWS resources the operator created for the cluster, so that external tooling can audit or clean them up if the
+	// management cluster is lost. Resources created by CloudFormation stacks are listed along with their stacks
+	OwnedResources []OwnedResource `json:"ownedResources"`
+	// principals the operator granted cluster admin access to, so that only their access entries are deleted when they
+	// are removed from the spec
+	ClusterAdminPrincipals []string `json:"clusterAdminPrincipals"`
+	// whether the cluster existed in EKS and was adopted instead of created
+	Adopted bool `json:"adopted"`
+}
+
+// UpstreamSpec is the configuration of a cluster in EKS in the format of the spec, along with the add-ons and Fargate
+// profiles of the cluster, which the spec doesn't cover.
+type UpstreamSpec struct {
+	EKSClusterConfigSpec `json:",inline"`
+	Addons               []UpstreamAddon          `json:"addons"`
+	FargateProfiles      []UpstreamFargateProfile `json:"fargateProfiles"`
+	// authentication mode of the access config of the cluster
+	AuthenticationMode string `json:"authenticationMode"`
+}
+
+// UpstreamAddon is an add-on installed in a cluster in EKS.
+type UpstreamAddon struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// ARN of the IAM role of the service account of the add-on, if it has one
+	ServiceAccountRoleARN string `json:"serviceAccountRoleArn"`
+}
+
+// UpstreamFargateProfile is a Fargate profile of a cluster in EKS.
+type UpstreamFargateProfile struct {
+	Name                string                   `json:"name"`
+	PodExecutionRoleARN string                   `json:"podExecutionRoleArn"`
+	Subnets             []string                 `json:"subnets"`
+	Selectors           []FargateProfileSelector `json:"selectors"`
+}
+
+// FargateProfileSelector selects the pods a Fargate profile runs by their namespace and labels.
+type FargateProfileSelector struct {
+	Namespace string            `json:"namespace"`
+	Labels    map[string]string `json:"labels"`
+}
+
+// CapacitySummary is the node capacity of a cluster summed over its upstream node groups. EKS doesn't report the
+// readiness of individual nodes, so the nodes of active node groups without health issues count as ready.
+type CapacitySummary struct {
+	NodeGroups    int32 `json:"nodeGroups"`
+	DesiredNodes  int32 `json:"desiredNodes"`
+	ReadyNodes    int32 `json:"readyNodes"`
+	OnDemandNodes int32 `json:"onDemandNodes"`
+	SpotNodes     int32 `json:"spotNodes"`
+	GPUNodes      int32 `json:"gpuNodes"`
+}
+
+// NodeGroupStatus is the state of an upstream node group as reported by EKS, so that health issues like
+// AsgInstanceLaunchFailures can be seen without going to the AWS console.
+type NodeGroupStatus struct {
+	// status of the node group, e.g. ACTIVE or DEGRADED
+	Status         string           `json:"status"`
+	Issues         []NodeGroupIssue `json:"issues"`
+	ReleaseVersion string           `json:"releaseVersion"`
+	// number of Ready nodes of the node group in the downstream cluster, unset if they couldn't be counted
+	ReadyNodes *int32 `json:"readyNodes,omitempty"`
+}
+
+// NodeGroupIssue is a health issue of a node group.
+type NodeGroupIssue struct {
+	Code        string   `json:"code"`
+	Message     string   `json:"message"`
+	ResourceIDs []string `json:"resourceIds"`
+}
+
+// ResourceUsage is the number of AWS resources with quotas that a cluster consumes.
+type ResourceUsage struct {
+	// launch templates of the upstream node groups, including the rancher-managed one
+	LaunchTemplates int32 `json:"launchTemplates"`
+	// CloudFormation stacks the operator created for the cluster
+	CloudFormationStacks int32 `json:"cloudFormationStacks"`
+	// elastic IPs of the NAT gateways of the VPC the operator created for the cluster, clusters in provided VPCs don't
+	// consume any
+	ElasticIPs int32 `json:"elasticIps"`
+	// network interfaces with the cluster security group, those of the control plane and of the nodes of node groups
+	// without custom security groups
+	NetworkInterfaces int32 `json:"networkInterfaces"`
+}
+
+// EKSUpdate is an update sent to EKS. Updates of node groups and add-ons are described along with the name of their
+// node group or add-on.
+type EKSUpdate struct {
+	ID string `json:"id"`
+	// type of the update, e.g. VersionUpdate or ConfigUpdate
+	Type          string `json:"type"`
+	NodegroupName string `json:"nodegroupName"`
+	AddonName     string `json:"addonName"`
+}
+
+// NodeGroupRollout is the progress of a node group version update, based on the instances of the auto scaling groups of
+// the node group. Nodes launched since the update started count as updated, so a rollout whose updated nodes don't
+// increase over time is stuck rather than slow.
+type NodeGroupRollout struct {
+	UpdateID string `json:"updateId"`
+	// time the update started, in RFC 3339 format
+	StartTime    string `json:"startTime"`
+	UpdatedNodes int32  `json:"updatedNodes"`
+	TotalNodes   int32  `json:"totalNodes"`
+	// nodes are replaced in batches of the maximum number of unavailable nodes of the node group
+	CurrentBatch int32 `json:"currentBatch"`
+	TotalBatches int32 `json:"totalBatches"`
+}
+
+// CostEstimate is an estimate of the monthly on-demand cost of a cluster based on the AWS Price List, computed before the
+// cluster is created
+type CostEstimate struct {
+	Currency    string             `json:"currency"`
+	MonthlyCost string             `json:"monthlyCost"`
+	Items       []CostEstimateItem `json:"items"`
+}
+
+type CostEstimateItem struct {
+	Name     string `json:"name"`
+	Quantity int32  `json:"quantity"`
+	// on-demand price of a single unit per hour
+	HourlyCost  string `json:"hourlyCost"`
+	MonthlyCost string `json:"monthlyCost"`
+}
+
+type CloudFormationStack struct {
+	Name   string `json:"name"`
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+// OwnedResource is an AWS resource the operator created for a cluster
+type OwnedResource struct {
+	// type of the resource, e.g. nodegroup, launchTemplate or cloudFormationStack
+	Type string `json:"type"`
+	// name or ID of the resource
+	ID string `json:"id"`
+	// ARN of the resource, empty for resources without one
+	ARN string `json:"arn"`
 }
 
 type NodeGroup struct {
@@ -72,6 +511,11 @@ type NodeGroup struct {
 	ImageID              *string            `json:"imageId" norman:"pointer"`
 	NodegroupName        *string            `json:"nodegroupName" norman:"required,pointer" wrangler:"required"`
 	DiskSize             *int32             `json:"diskSize"`
+	VolumeType           *string            `json:"volumeType" norman:"pointer"`
+	Iops                 *int32             `json:"iops"`
+	Throughput           *int32             `json:"throughput"`
+	Encrypted            *bool              `json:"encrypted"`
+	KmsKeyID             *string            `json:"kmsKeyId" norman:"pointer"`
 	InstanceType         string             `json:"instanceType" norman:"pointer"`
 	Labels               map[string]*string `json:"labels"`
 	Ec2SshKey            *string            `json:"ec2SshKey" norman:"pointer"`
@@ -87,6 +531,126 @@ type NodeGroup struct {
 	RequestSpotInstances *bool              `json:"requestSpotInstances"`
 	SpotInstanceTypes    []string           `json:"spotInstanceTypes"`
 	NodeRole             *string            `json:"nodeRole" norman:"pointer"`
+	DeletionProtection   *bool              `json:"deletionProtection"`
+	MetadataOptions      *MetadataOptions   `json:"metadataOptions"`
+	NodeRepairConfig     *NodeRepairConfig  `json:"nodeRepairConfig"`
+	// windows changing the sizes of the node group on a schedule, e.g. to scale it to zero at night. A window lasts
+	// until the next window of the node group starts
+	ScheduledScaling []ScalingWindow `json:"scheduledScaling"`
+	// release version of the EKS optimized AMI of the node group, e.g. 1.30.0-20240703, so that node AMIs only roll
+	// when it is changed. The latest release of the kubernetes version of the node group is used if unset. It can't be
+	// set for node groups with an imageId, with a custom launch template it is applied along with new versions of the
+	// template that don't specify an AMI
+	ReleaseVersion *string `json:"releaseVersion" norman:"pointer"`
+	// placement group and tenancy of the nodes of a node group with a rancher-managed launch template
+	Placement *Placement `json:"placement"`
+	// EBS volumes attached to the nodes of a node group with a rancher-managed launch template in addition to the root
+	// volume, e.g. a separate volume for containerd
+	BlockDeviceMappings []BlockDeviceMapping `json:"blockDeviceMappings"`
+	// network interfaces of the nodes of a node group with a rancher-managed launch template, e.g. to keep nodes in
+	// subnets that map public IPs on launch private. The primary interface has to be configured when any are
+	NetworkInterfaces []NetworkInterface `json:"networkInterfaces"`
+	// what happens when fields EKS can't update on an existing node group change, i.e. subnets, requestSpotInstances,
+	// spotInstanceTypes, nodeRole and the instanceType of node groups without a launch template. Replace drains and
+	// deletes the node group and creates it again during the maintenance window, otherwise the changes are reported
+	// in the NodeGroupRecreationRequired condition and not applied. The node group has no capacity from the deletion
+	// until the new one is active, so workloads need room on other node groups
+	RecreatePolicy string `json:"recreatePolicy"`
+}
+
+// ScalingWindow overrides the sizes of a node group from the time its schedule fires until the next window of the node
+// group starts. Sizes that aren't set are taken from the node group, so a window without sizes restores them.
+type ScalingWindow struct {
+	Name string `json:"name"`
+	// cron expression with five fields of when the window starts, e.g. "0 20 * * 1-5" for 8pm on weekdays
+	Schedule string `json:"schedule"`
+	// IANA time zone the schedule is in, e.g. Europe/Berlin, UTC by default
+	TimeZone    string `json:"timeZone"`
+	MinSize     *int32 `json:"minSize"`
+	MaxSize     *int32 `json:"maxSize"`
+	DesiredSize *int32 `json:"desiredSize"`
+}
+
+// MaintenanceWindow is a recurring window that opens when its schedule fires and stays open for its duration.
+type MaintenanceWindow struct {
+	// cron expression with five fields of when the window opens, e.g. "0 22 * * 6" for Saturdays at 10pm
+	Schedule string `json:"schedule"`
+	// how long the window stays open, e.g. 6h
+	Duration string `json:"duration"`
+	// IANA time zone the schedule is in, e.g. Europe/Berlin, UTC by default
+	TimeZone string `json:"timeZone"`
+}
+
+// NodeGroupDrain configures the draining of the nodes of node groups before they are deleted. Pods are evicted, so pod
+// disruption budgets are respected, except for DaemonSet and mirror pods.
+type NodeGroupDrain struct {
+	// how long to wait for the pods to be evicted before the node group is deleted anyway, e.g. 10m, 15m by default
+	Timeout string `json:"timeout"`
+}
+
+// Placement configures where the nodes of a node group with a rancher-managed launch template are placed. The
+// placement group is created with the strategy and partition count if they are set and it doesn't exist yet, it isn't
+// deleted with the cluster since node groups of other clusters can use it as well.
+type Placement struct {
+	// name of the placement group the nodes are launched in
+	GroupName *string `json:"groupName" norman:"pointer"`
+	// strategy of the placement group, cluster, spread or partition
+	Strategy *string `json:"strategy" norman:"pointer"`
+	// number of partitions of a placement group with the partition strategy, between 1 and 7
+	PartitionCount *int32 `json:"partitionCount"`
+	// partition of a placement group with the partition strategy the nodes are launched in, spread over all of them
+	// if unset
+	PartitionNumber *int32 `json:"partitionNumber"`
+	// tenancy of the nodes, default, dedicated or host
+	Tenancy *string `json:"tenancy" norman:"pointer"`
+}
+
+// BlockDeviceMapping is an EBS volume attached to the nodes of a node group.
+type BlockDeviceMapping struct {
+	// device name the volume is exposed as, e.g. /dev/xvdb
+	DeviceName *string `json:"deviceName" norman:"pointer"`
+	// size of the volume in GiB
+	VolumeSize *int32 `json:"volumeSize"`
+	// type of the volume, the EBS default if unset
+	VolumeType *string `json:"volumeType" norman:"pointer"`
+	Iops       *int32  `json:"iops"`
+	Throughput *int32  `json:"throughput"`
+	Encrypted  *bool   `json:"encrypted"`
+	KmsKeyID   *string `json:"kmsKeyId" norman:"pointer"`
+	// whether the volume is deleted when the node is terminated, true by default
+	DeleteOnTermination *bool `json:"deleteOnTermination"`
+}
+
+// NetworkInterface configures a network interface of the nodes of a node group. The cluster security group is always
+// added to the security groups of the interface, so that nodes can join the cluster.
+type NetworkInterface struct {
+	// index of the network card the interface is attached to, 0 by default
+	NetworkCardIndex *int32 `json:"networkCardIndex"`
+	// position of the interface on its network card, 0 by default. The interface with network card and device index 0
+	// is the primary interface
+	DeviceIndex *int32 `json:"deviceIndex"`
+	// whether the primary interface gets a public IP address, overriding the MapPublicIpOnLaunch setting of the subnet
+	AssociatePublicIPAddress *bool `json:"associatePublicIpAddress"`
+	// security groups of the interface in addition to the cluster security group
+	SecurityGroups []string `json:"securityGroups"`
+	// type of the interface, interface, efa or efa-only, interface by default
+	InterfaceType *string `json:"interfaceType" norman:"pointer"`
+}
+
+// NodeRepairConfig configures the automatic repair of unhealthy nodes in a node group
+type NodeRepairConfig struct {
+	Enabled *bool `json:"enabled"`
+}
+
+// MetadataOptions configures the instance metadata service of the nodes in a node group with a rancher-managed
+// launch template
+type MetadataOptions struct {
+	// whether IMDSv2 session tokens are required or optional
+	HTTPTokens *string `json:"httpTokens" norman:"pointer"`
+	// maximum number of network hops a metadata request can travel, between 1 and 64
+	HTTPPutResponseHopLimit *int32 `json:"httpPutResponseHopLimit"`
+	// whether instance tags are accessible from the instance metadata, enabled or disabled
+	InstanceMetadataTags *string `json:"instanceMetadataTags" norman:"pointer"`
 }
 
 type LaunchTemplate struct {
@@ -22,9 +22,188 @@ limitations under the License.
 package v1
 
 import (
+	genericcondition "github.com/rancher/wrangler/v3/pkg/genericcondition"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditLogging) DeepCopyInto(out *AuditLogging) {
+	*out = *in
+	if in.AlarmActions != nil {
+		in, out := &in.AlarmActions, &out.AlarmActions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditLogging.
+func (in *AuditLogging) DeepCopy() *AuditLogging {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditLogging)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlockDeviceMapping) DeepCopyInto(out *BlockDeviceMapping) {
+	*out = *in
+	if in.DeviceName != nil {
+		in, out := &in.DeviceName, &out.DeviceName
+		*out = new(string)
+		**out = **in
+	}
+	if in.VolumeSize != nil {
+		in, out := &in.VolumeSize, &out.VolumeSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.VolumeType != nil {
+		in, out := &in.VolumeType, &out.VolumeType
+		*out = new(string)
+		**out = **in
+	}
+	if in.Iops != nil {
+		in, out := &in.Iops, &out.Iops
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Throughput != nil {
+		in, out := &in.Throughput, &out.Throughput
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Encrypted != nil {
+		in, out := &in.Encrypted, &out.Encrypted
+		*out = new(bool)
+		**out = **in
+	}
+	if in.KmsKeyID != nil {
+		in, out := &in.KmsKeyID, &out.KmsKeyID
+		*out = new(string)
+		**out = **in
+	}
+	if in.DeleteOnTermination != nil {
+		in, out := &in.DeleteOnTermination, &out.DeleteOnTermination
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlockDeviceMapping.
+func (in *BlockDeviceMapping) DeepCopy() *BlockDeviceMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(BlockDeviceMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CapacitySummary) DeepCopyInto(out *CapacitySummary) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CapacitySummary.
+func (in *CapacitySummary) DeepCopy() *CapacitySummary {
+	if in == nil {
+		return nil
+	}
+	out := new(CapacitySummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudFormationStack) DeepCopyInto(out *CloudFormationStack) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudFormationStack.
+func (in *CloudFormationStack) DeepCopy() *CloudFormationStack {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudFormationStack)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostEstimate) DeepCopyInto(out *CostEstimate) {
+	*out = *in
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CostEstimateItem, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostEstimate.
+func (in *CostEstimate) DeepCopy() *CostEstimate {
+	if in == nil {
+		return nil
+	}
+	out := new(CostEstimate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostEstimateItem) DeepCopyInto(out *CostEstimateItem) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostEstimateItem.
+func (in *CostEstimateItem) DeepCopy() *CostEstimateItem {
+	if in == nil {
+		return nil
+	}
+	out := new(CostEstimateItem)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiscoveredCluster) DeepCopyInto(out *DiscoveredCluster) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiscoveredCluster.
+func (in *DiscoveredCluster) DeepCopy() *DiscoveredCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(DiscoveredCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DownstreamTLS) DeepCopyInto(out *DownstreamTLS) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DownstreamTLS.
+func (in *DownstreamTLS) DeepCopy() *DownstreamTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(DownstreamTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EKSClusterConfig) DeepCopyInto(out *EKSClusterConfig) {
 	*out = *in
@@ -158,6 +337,122 @@ func (in *EKSClusterConfigSpec) DeepCopyInto(out *EKSClusterConfigSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.OutpostConfig != nil {
+		in, out := &in.OutpostConfig, &out.OutpostConfig
+		*out = new(OutpostConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CleanupClusterTags != nil {
+		in, out := &in.CleanupClusterTags, &out.CleanupClusterTags
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PodIdentityAssociations != nil {
+		in, out := &in.PodIdentityAssociations, &out.PodIdentityAssociations
+		*out = make([]PodIdentityAssociation, len(*in))
+		copy(*out, *in)
+	}
+	if in.IdentityProviderConfigs != nil {
+		in, out := &in.IdentityProviderConfigs, &out.IdentityProviderConfigs
+		*out = make([]IdentityProviderConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.GenerateKubeconfig != nil {
+		in, out := &in.GenerateKubeconfig, &out.GenerateKubeconfig
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DeleteLogGroup != nil {
+		in, out := &in.DeleteLogGroup, &out.DeleteLogGroup
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TemplateRef != nil {
+		in, out := &in.TemplateRef, &out.TemplateRef
+		*out = new(TemplateReference)
+		**out = **in
+	}
+	if in.OIDCProviderARN != nil {
+		in, out := &in.OIDCProviderARN, &out.OIDCProviderARN
+		*out = new(string)
+		**out = **in
+	}
+	if in.ClusterSecurityGroupTags != nil {
+		in, out := &in.ClusterSecurityGroupTags, &out.ClusterSecurityGroupTags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.OIDCThumbprints != nil {
+		in, out := &in.OIDCThumbprints, &out.OIDCThumbprints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindow)
+		**out = **in
+	}
+	if in.ServiceRolePolicyARNs != nil {
+		in, out := &in.ServiceRolePolicyARNs, &out.ServiceRolePolicyARNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeGroupDrain != nil {
+		in, out := &in.NodeGroupDrain, &out.NodeGroupDrain
+		*out = new(NodeGroupDrain)
+		**out = **in
+	}
+	if in.DownstreamTLS != nil {
+		in, out := &in.DownstreamTLS, &out.DownstreamTLS
+		*out = new(DownstreamTLS)
+		**out = **in
+	}
+	if in.ClusterAutoscalerTags != nil {
+		in, out := &in.ClusterAutoscalerTags, &out.ClusterAutoscalerTags
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ImportNodeGroupSelector != nil {
+		in, out := &in.ImportNodeGroupSelector, &out.ImportNodeGroupSelector
+		*out = new(NodeGroupSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AuditLogging != nil {
+		in, out := &in.AuditLogging, &out.AuditLogging
+		*out = new(AuditLogging)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SharedLaunchTemplates != nil {
+		in, out := &in.SharedLaunchTemplates, &out.SharedLaunchTemplates
+		*out = make([]LaunchTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeInstanceRolePolicyARNs != nil {
+		in, out := &in.NodeInstanceRolePolicyARNs, &out.NodeInstanceRolePolicyARNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeRole != nil {
+		in, out := &in.NodeRole, &out.NodeRole
+		*out = new(string)
+		**out = **in
+	}
+	if in.ClusterAdminPrincipals != nil {
+		in, out := &in.ClusterAdminPrincipals, &out.ClusterAdminPrincipals
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ContainerInsights != nil {
+		in, out := &in.ContainerInsights, &out.ContainerInsights
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -196,6 +491,116 @@ func (in *EKSClusterConfigStatus) DeepCopyInto(out *EKSClusterConfigStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.DeletionProtectedNodeGroups != nil {
+		in, out := &in.DeletionProtectedNodeGroups, &out.DeletionProtectedNodeGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]genericcondition.GenericCondition, len(*in))
+		copy(*out, *in)
+	}
+	if in.CloudFormationStacks != nil {
+		in, out := &in.CloudFormationStacks, &out.CloudFormationStacks
+		*out = make([]CloudFormationStack, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeGroupUserDataHashes != nil {
+		in, out := &in.NodeGroupUserDataHashes, &out.NodeGroupUserDataHashes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CostEstimate != nil {
+		in, out := &in.CostEstimate, &out.CostEstimate
+		*out = new(CostEstimate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeGroupRollouts != nil {
+		in, out := &in.NodeGroupRollouts, &out.NodeGroupRollouts
+		*out = make(map[string]NodeGroupRollout, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = new(CapacitySummary)
+		**out = **in
+	}
+	if in.NodeGroupScalingWindows != nil {
+		in, out := &in.NodeGroupScalingWindows, &out.NodeGroupScalingWindows
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodeGroupStatuses != nil {
+		in, out := &in.NodeGroupStatuses, &out.NodeGroupStatuses
+		*out = make(map[string]NodeGroupStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.InProgressUpdates != nil {
+		in, out := &in.InProgressUpdates, &out.InProgressUpdates
+		*out = make([]EKSUpdate, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResourceUsage != nil {
+		in, out := &in.ResourceUsage, &out.ResourceUsage
+		*out = new(ResourceUsage)
+		**out = **in
+	}
+	if in.ServiceRolePolicyARNs != nil {
+		in, out := &in.ServiceRolePolicyARNs, &out.ServiceRolePolicyARNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeInstanceRolePolicyARNs != nil {
+		in, out := &in.NodeInstanceRolePolicyARNs, &out.NodeInstanceRolePolicyARNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeGroupDrainStartTimes != nil {
+		in, out := &in.NodeGroupDrainStartTimes, &out.NodeGroupDrainStartTimes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.UpstreamSpec != nil {
+		in, out := &in.UpstreamSpec, &out.UpstreamSpec
+		*out = new(UpstreamSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AuditLogging != nil {
+		in, out := &in.AuditLogging, &out.AuditLogging
+		*out = new(AuditLogging)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RecreatingNodeGroups != nil {
+		in, out := &in.RecreatingNodeGroups, &out.RecreatingNodeGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.OwnedResources != nil {
+		in, out := &in.OwnedResources, &out.OwnedResources
+		*out = make([]OwnedResource, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterAdminPrincipals != nil {
+		in, out := &in.ClusterAdminPrincipals, &out.ClusterAdminPrincipals
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -210,86 +615,815 @@ func (in *EKSClusterConfigStatus) DeepCopy() *EKSClusterConfigStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LaunchTemplate) DeepCopyInto(out *LaunchTemplate) {
+func (in *EKSClusterTemplate) DeepCopyInto(out *EKSClusterTemplate) {
 	*out = *in
-	if in.ID != nil {
-		in, out := &in.ID, &out.ID
-		*out = new(string)
-		**out = **in
-	}
-	if in.Name != nil {
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EKSClusterTemplate.
+func (in *EKSClusterTemplate) DeepCopy() *EKSClusterTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(EKSClusterTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EKSClusterTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EKSClusterTemplateList) DeepCopyInto(out *EKSClusterTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]EKSClusterTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EKSClusterTemplateList.
+func (in *EKSClusterTemplateList) DeepCopy() *EKSClusterTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(EKSClusterTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EKSClusterTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EKSClusterTemplateSpec) DeepCopyInto(out *EKSClusterTemplateSpec) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EKSClusterTemplateSpec.
+func (in *EKSClusterTemplateSpec) DeepCopy() *EKSClusterTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EKSClusterTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EKSInventory) DeepCopyInto(out *EKSInventory) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EKSInventory.
+func (in *EKSInventory) DeepCopy() *EKSInventory {
+	if in == nil {
+		return nil
+	}
+	out := new(EKSInventory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EKSInventory) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EKSInventoryList) DeepCopyInto(out *EKSInventoryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]EKSInventory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EKSInventoryList.
+func (in *EKSInventoryList) DeepCopy() *EKSInventoryList {
+	if in == nil {
+		return nil
+	}
+	out := new(EKSInventoryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EKSInventoryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EKSInventorySpec) DeepCopyInto(out *EKSInventorySpec) {
+	*out = *in
+	if in.Regions != nil {
+		in, out := &in.Regions, &out.Regions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EKSInventorySpec.
+func (in *EKSInventorySpec) DeepCopy() *EKSInventorySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EKSInventorySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EKSInventoryStatus) DeepCopyInto(out *EKSInventoryStatus) {
+	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]DiscoveredCluster, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EKSInventoryStatus.
+func (in *EKSInventoryStatus) DeepCopy() *EKSInventoryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EKSInventoryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EKSUpdate) DeepCopyInto(out *EKSUpdate) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EKSUpdate.
+func (in *EKSUpdate) DeepCopy() *EKSUpdate {
+	if in == nil {
+		return nil
+	}
+	out := new(EKSUpdate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FargateProfileSelector) DeepCopyInto(out *FargateProfileSelector) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FargateProfileSelector.
+func (in *FargateProfileSelector) DeepCopy() *FargateProfileSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(FargateProfileSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IdentityProviderConfig) DeepCopyInto(out *IdentityProviderConfig) {
+	*out = *in
+	if in.RequiredClaims != nil {
+		in, out := &in.RequiredClaims, &out.RequiredClaims
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdentityProviderConfig.
+func (in *IdentityProviderConfig) DeepCopy() *IdentityProviderConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(IdentityProviderConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LaunchTemplate) DeepCopyInto(out *LaunchTemplate) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(string)
+		**out = **in
+	}
+	if in.Name != nil {
 		in, out := &in.Name, &out.Name
 		*out = new(string)
 		**out = **in
 	}
-	if in.Version != nil {
-		in, out := &in.Version, &out.Version
-		*out = new(int64)
-		**out = **in
+	if in.Version != nil {
+		in, out := &in.Version, &out.Version
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LaunchTemplate.
+func (in *LaunchTemplate) DeepCopy() *LaunchTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(LaunchTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetadataOptions) DeepCopyInto(out *MetadataOptions) {
+	*out = *in
+	if in.HTTPTokens != nil {
+		in, out := &in.HTTPTokens, &out.HTTPTokens
+		*out = new(string)
+		**out = **in
+	}
+	if in.HTTPPutResponseHopLimit != nil {
+		in, out := &in.HTTPPutResponseHopLimit, &out.HTTPPutResponseHopLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.InstanceMetadataTags != nil {
+		in, out := &in.InstanceMetadataTags, &out.InstanceMetadataTags
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataOptions.
+func (in *MetadataOptions) DeepCopy() *MetadataOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(MetadataOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkInterface) DeepCopyInto(out *NetworkInterface) {
+	*out = *in
+	if in.NetworkCardIndex != nil {
+		in, out := &in.NetworkCardIndex, &out.NetworkCardIndex
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DeviceIndex != nil {
+		in, out := &in.DeviceIndex, &out.DeviceIndex
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AssociatePublicIPAddress != nil {
+		in, out := &in.AssociatePublicIPAddress, &out.AssociatePublicIPAddress
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SecurityGroups != nil {
+		in, out := &in.SecurityGroups, &out.SecurityGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.InterfaceType != nil {
+		in, out := &in.InterfaceType, &out.InterfaceType
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkInterface.
+func (in *NetworkInterface) DeepCopy() *NetworkInterface {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkInterface)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeGroup) DeepCopyInto(out *NodeGroup) {
+	*out = *in
+	if in.Gpu != nil {
+		in, out := &in.Gpu, &out.Gpu
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Arm != nil {
+		in, out := &in.Arm, &out.Arm
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ImageID != nil {
+		in, out := &in.ImageID, &out.ImageID
+		*out = new(string)
+		**out = **in
+	}
+	if in.NodegroupName != nil {
+		in, out := &in.NodegroupName, &out.NodegroupName
+		*out = new(string)
+		**out = **in
+	}
+	if in.DiskSize != nil {
+		in, out := &in.DiskSize, &out.DiskSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.VolumeType != nil {
+		in, out := &in.VolumeType, &out.VolumeType
+		*out = new(string)
+		**out = **in
+	}
+	if in.Iops != nil {
+		in, out := &in.Iops, &out.Iops
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Throughput != nil {
+		in, out := &in.Throughput, &out.Throughput
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Encrypted != nil {
+		in, out := &in.Encrypted, &out.Encrypted
+		*out = new(bool)
+		**out = **in
+	}
+	if in.KmsKeyID != nil {
+		in, out := &in.KmsKeyID, &out.KmsKeyID
+		*out = new(string)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]*string, len(*in))
+		for key, val := range *in {
+			var outVal *string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = new(string)
+				**out = **in
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Ec2SshKey != nil {
+		in, out := &in.Ec2SshKey, &out.Ec2SshKey
+		*out = new(string)
+		**out = **in
+	}
+	if in.DesiredSize != nil {
+		in, out := &in.DesiredSize, &out.DesiredSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MinSize != nil {
+		in, out := &in.MinSize, &out.MinSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]*string, len(*in))
+		for key, val := range *in {
+			var outVal *string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = new(string)
+				**out = **in
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.ResourceTags != nil {
+		in, out := &in.ResourceTags, &out.ResourceTags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.UserData != nil {
+		in, out := &in.UserData, &out.UserData
+		*out = new(string)
+		**out = **in
+	}
+	if in.Version != nil {
+		in, out := &in.Version, &out.Version
+		*out = new(string)
+		**out = **in
+	}
+	if in.LaunchTemplate != nil {
+		in, out := &in.LaunchTemplate, &out.LaunchTemplate
+		*out = new(LaunchTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RequestSpotInstances != nil {
+		in, out := &in.RequestSpotInstances, &out.RequestSpotInstances
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SpotInstanceTypes != nil {
+		in, out := &in.SpotInstanceTypes, &out.SpotInstanceTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeRole != nil {
+		in, out := &in.NodeRole, &out.NodeRole
+		*out = new(string)
+		**out = **in
+	}
+	if in.DeletionProtection != nil {
+		in, out := &in.DeletionProtection, &out.DeletionProtection
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MetadataOptions != nil {
+		in, out := &in.MetadataOptions, &out.MetadataOptions
+		*out = new(MetadataOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeRepairConfig != nil {
+		in, out := &in.NodeRepairConfig, &out.NodeRepairConfig
+		*out = new(NodeRepairConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScheduledScaling != nil {
+		in, out := &in.ScheduledScaling, &out.ScheduledScaling
+		*out = make([]ScalingWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ReleaseVersion != nil {
+		in, out := &in.ReleaseVersion, &out.ReleaseVersion
+		*out = new(string)
+		**out = **in
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(Placement)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BlockDeviceMappings != nil {
+		in, out := &in.BlockDeviceMappings, &out.BlockDeviceMappings
+		*out = make([]BlockDeviceMapping, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NetworkInterfaces != nil {
+		in, out := &in.NetworkInterfaces, &out.NetworkInterfaces
+		*out = make([]NetworkInterface, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeGroup.
+func (in *NodeGroup) DeepCopy() *NodeGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeGroupDrain) DeepCopyInto(out *NodeGroupDrain) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeGroupDrain.
+func (in *NodeGroupDrain) DeepCopy() *NodeGroupDrain {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeGroupDrain)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeGroupIssue) DeepCopyInto(out *NodeGroupIssue) {
+	*out = *in
+	if in.ResourceIDs != nil {
+		in, out := &in.ResourceIDs, &out.ResourceIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeGroupIssue.
+func (in *NodeGroupIssue) DeepCopy() *NodeGroupIssue {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeGroupIssue)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeGroupRollout) DeepCopyInto(out *NodeGroupRollout) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeGroupRollout.
+func (in *NodeGroupRollout) DeepCopy() *NodeGroupRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeGroupRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeGroupSelector) DeepCopyInto(out *NodeGroupSelector) {
+	*out = *in
+	if in.Names != nil {
+		in, out := &in.Names, &out.Names
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MatchLabels != nil {
+		in, out := &in.MatchLabels, &out.MatchLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LaunchTemplate.
-func (in *LaunchTemplate) DeepCopy() *LaunchTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeGroupSelector.
+func (in *NodeGroupSelector) DeepCopy() *NodeGroupSelector {
 	if in == nil {
 		return nil
 	}
-	out := new(LaunchTemplate)
+	out := new(NodeGroupSelector)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeGroup) DeepCopyInto(out *NodeGroup) {
+func (in *NodeGroupStatus) DeepCopyInto(out *NodeGroupStatus) {
 	*out = *in
-	if in.Gpu != nil {
-		in, out := &in.Gpu, &out.Gpu
-		*out = new(bool)
+	if in.Issues != nil {
+		in, out := &in.Issues, &out.Issues
+		*out = make([]NodeGroupIssue, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ReadyNodes != nil {
+		in, out := &in.ReadyNodes, &out.ReadyNodes
+		*out = new(int32)
 		**out = **in
 	}
-	if in.Arm != nil {
-		in, out := &in.Arm, &out.Arm
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeGroupStatus.
+func (in *NodeGroupStatus) DeepCopy() *NodeGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeRepairConfig) DeepCopyInto(out *NodeRepairConfig) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
 		*out = new(bool)
 		**out = **in
 	}
-	if in.ImageID != nil {
-		in, out := &in.ImageID, &out.ImageID
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeRepairConfig.
+func (in *NodeRepairConfig) DeepCopy() *NodeRepairConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeRepairConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutpostConfig) DeepCopyInto(out *OutpostConfig) {
+	*out = *in
+	if in.OutpostARNs != nil {
+		in, out := &in.OutpostARNs, &out.OutpostARNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutpostConfig.
+func (in *OutpostConfig) DeepCopy() *OutpostConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OutpostConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OwnedResource) DeepCopyInto(out *OwnedResource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OwnedResource.
+func (in *OwnedResource) DeepCopy() *OwnedResource {
+	if in == nil {
+		return nil
+	}
+	out := new(OwnedResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Placement) DeepCopyInto(out *Placement) {
+	*out = *in
+	if in.GroupName != nil {
+		in, out := &in.GroupName, &out.GroupName
 		*out = new(string)
 		**out = **in
 	}
-	if in.NodegroupName != nil {
-		in, out := &in.NodegroupName, &out.NodegroupName
+	if in.Strategy != nil {
+		in, out := &in.Strategy, &out.Strategy
 		*out = new(string)
 		**out = **in
 	}
-	if in.DiskSize != nil {
-		in, out := &in.DiskSize, &out.DiskSize
+	if in.PartitionCount != nil {
+		in, out := &in.PartitionCount, &out.PartitionCount
 		*out = new(int32)
 		**out = **in
 	}
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(map[string]*string, len(*in))
-		for key, val := range *in {
-			var outVal *string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = new(string)
-				**out = **in
-			}
-			(*out)[key] = outVal
-		}
+	if in.PartitionNumber != nil {
+		in, out := &in.PartitionNumber, &out.PartitionNumber
+		*out = new(int32)
+		**out = **in
 	}
-	if in.Ec2SshKey != nil {
-		in, out := &in.Ec2SshKey, &out.Ec2SshKey
+	if in.Tenancy != nil {
+		in, out := &in.Tenancy, &out.Tenancy
 		*out = new(string)
 		**out = **in
 	}
-	if in.DesiredSize != nil {
-		in, out := &in.DesiredSize, &out.DesiredSize
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Placement.
+func (in *Placement) DeepCopy() *Placement {
+	if in == nil {
+		return nil
+	}
+	out := new(Placement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodIdentityAssociation) DeepCopyInto(out *PodIdentityAssociation) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodIdentityAssociation.
+func (in *PodIdentityAssociation) DeepCopy() *PodIdentityAssociation {
+	if in == nil {
+		return nil
+	}
+	out := new(PodIdentityAssociation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceUsage) DeepCopyInto(out *ResourceUsage) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceUsage.
+func (in *ResourceUsage) DeepCopy() *ResourceUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScalingWindow) DeepCopyInto(out *ScalingWindow) {
+	*out = *in
+	if in.MinSize != nil {
+		in, out := &in.MinSize, &out.MinSize
 		*out = new(int32)
 		**out = **in
 	}
@@ -298,77 +1432,109 @@ func (in *NodeGroup) DeepCopyInto(out *NodeGroup) {
 		*out = new(int32)
 		**out = **in
 	}
-	if in.MinSize != nil {
-		in, out := &in.MinSize, &out.MinSize
+	if in.DesiredSize != nil {
+		in, out := &in.DesiredSize, &out.DesiredSize
 		*out = new(int32)
 		**out = **in
 	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScalingWindow.
+func (in *ScalingWindow) DeepCopy() *ScalingWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(ScalingWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateReference) DeepCopyInto(out *TemplateReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateReference.
+func (in *TemplateReference) DeepCopy() *TemplateReference {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpstreamAddon) DeepCopyInto(out *UpstreamAddon) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpstreamAddon.
+func (in *UpstreamAddon) DeepCopy() *UpstreamAddon {
+	if in == nil {
+		return nil
+	}
+	out := new(UpstreamAddon)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpstreamFargateProfile) DeepCopyInto(out *UpstreamFargateProfile) {
+	*out = *in
 	if in.Subnets != nil {
 		in, out := &in.Subnets, &out.Subnets
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.Tags != nil {
-		in, out := &in.Tags, &out.Tags
-		*out = make(map[string]*string, len(*in))
-		for key, val := range *in {
-			var outVal *string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = new(string)
-				**out = **in
-			}
-			(*out)[key] = outVal
-		}
-	}
-	if in.ResourceTags != nil {
-		in, out := &in.ResourceTags, &out.ResourceTags
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
+	if in.Selectors != nil {
+		in, out := &in.Selectors, &out.Selectors
+		*out = make([]FargateProfileSelector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.UserData != nil {
-		in, out := &in.UserData, &out.UserData
-		*out = new(string)
-		**out = **in
-	}
-	if in.Version != nil {
-		in, out := &in.Version, &out.Version
-		*out = new(string)
-		**out = **in
-	}
-	if in.LaunchTemplate != nil {
-		in, out := &in.LaunchTemplate, &out.LaunchTemplate
-		*out = new(LaunchTemplate)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.RequestSpotInstances != nil {
-		in, out := &in.RequestSpotInstances, &out.RequestSpotInstances
-		*out = new(bool)
-		**out = **in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpstreamFargateProfile.
+func (in *UpstreamFargateProfile) DeepCopy() *UpstreamFargateProfile {
+	if in == nil {
+		return nil
 	}
-	if in.SpotInstanceTypes != nil {
-		in, out := &in.SpotInstanceTypes, &out.SpotInstanceTypes
-		*out = make([]string, len(*in))
+	out := new(UpstreamFargateProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpstreamSpec) DeepCopyInto(out *UpstreamSpec) {
+	*out = *in
+	in.EKSClusterConfigSpec.DeepCopyInto(&out.EKSClusterConfigSpec)
+	if in.Addons != nil {
+		in, out := &in.Addons, &out.Addons
+		*out = make([]UpstreamAddon, len(*in))
 		copy(*out, *in)
 	}
-	if in.NodeRole != nil {
-		in, out := &in.NodeRole, &out.NodeRole
-		*out = new(string)
-		**out = **in
+	if in.FargateProfiles != nil {
+		in, out := &in.FargateProfiles, &out.FargateProfiles
+		*out = make([]UpstreamFargateProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeGroup.
-func (in *NodeGroup) DeepCopy() *NodeGroup {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpstreamSpec.
+func (in *UpstreamSpec) DeepCopy() *UpstreamSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeGroup)
+	out := new(UpstreamSpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -40,3 +40,37 @@ func NewEKSClusterConfig(namespace, name string, obj EKSClusterConfig) *EKSClust
 	obj.Namespace = namespace
 	return &obj
 }
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// EKSClusterTemplateList is a list of EKSClusterTemplate resources
+type EKSClusterTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []EKSClusterTemplate `json:"items"`
+}
+
+func NewEKSClusterTemplate(namespace, name string, obj EKSClusterTemplate) *EKSClusterTemplate {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("EKSClusterTemplate").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// EKSInventoryList is a list of EKSInventory resources
+type EKSInventoryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []EKSInventory `json:"items"`
+}
+
+func NewEKSInventory(namespace, name string, obj EKSInventory) *EKSInventory {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("EKSInventory").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
@@ -28,7 +28,9 @@ import (
 )
 
 var (
-	EKSClusterConfigResourceName = "eksclusterconfigs"
+	EKSClusterConfigResourceName   = "eksclusterconfigs"
+	EKSClusterTemplateResourceName = "eksclustertemplates"
+	EKSInventoryResourceName       = "eksinventories"
 )
 
 // SchemeGroupVersion is group version used to register these objects
@@ -54,6 +56,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&EKSClusterConfig{},
 		&EKSClusterConfigList{},
+		&EKSClusterTemplate{},
+		&EKSClusterTemplateList{},
+		&EKSInventory{},
+		&EKSInventoryList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
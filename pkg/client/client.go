@@ -0,0 +1,182 @@
+// Package client exposes the higher-level EKS operations of the operator to other controllers, so they can be reused
+// without depending on the operator's reconciliation logic.
+package client
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/rancher/eks-operator/controller"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	awsservices "github.com/rancher/eks-operator/pkg/eks"
+	"github.com/rancher/eks-operator/pkg/eks/services"
+)
+
+// StackCreationInProgressError is returned by operations that create CloudFormation stacks while a stack is still
+// being created. The operation should be retried later to pick up where it left off.
+type StackCreationInProgressError = awsservices.StackCreationInProgressError
+
+// Client performs EKS operations the same way the operator does.
+type Client interface {
+	// CreateCluster creates the EKS cluster described by the given config.
+	CreateCluster(ctx context.Context, opts *CreateClusterOpts) error
+	// CreateNodeGroup creates a managed node group for the given config, along with the launch template version
+	// and node instance role it needs.
+	CreateNodeGroup(ctx context.Context, opts *CreateNodeGroupOpts) (*CreateNodeGroupResult, error)
+	// BuildUpstreamClusterState builds a spec from the state of the cluster and its node groups in AWS.
+	BuildUpstreamClusterState(ctx context.Context, opts *BuildUpstreamClusterStateOpts) (*UpstreamClusterState, error)
+	// EnableEBSCSIDriver installs the EBS CSI driver add-on, including its OIDC provider and IAM role.
+	EnableEBSCSIDriver(ctx context.Context, opts *EnableEBSCSIDriverOpts) error
+	// EstimateCost estimates the monthly on-demand cost of the cluster described by the given config before it is
+	// created, e.g. to review changes that would create clusters.
+	EstimateCost(ctx context.Context, opts *EstimateCostOpts) (*eksv1.CostEstimate, error)
+}
+
+// Opts holds the AWS services used by the client.
+type Opts struct {
+	EKSService            services.EKSServiceInterface
+	EC2Service            services.EC2ServiceInterface
+	IAMService            services.IAMServiceInterface
+	CloudFormationService services.CloudFormationServiceInterface
+	PricingService        services.PricingServiceInterface
+}
+
+type client struct {
+	eks            services.EKSServiceInterface
+	ec2            services.EC2ServiceInterface
+	iam            services.IAMServiceInterface
+	cloudformation services.CloudFormationServiceInterface
+	pricing        services.PricingServiceInterface
+}
+
+// New returns a client that uses the given AWS services.
+func New(opts *Opts) Client {
+	return &client{
+		eks:            opts.EKSService,
+		ec2:            opts.EC2Service,
+		iam:            opts.IAMService,
+		cloudformation: opts.CloudFormationService,
+		pricing:        opts.PricingService,
+	}
+}
+
+// NewFromConfig returns a client that creates its AWS services from the given config.
+func NewFromConfig(cfg aws.Config) Client {
+	return New(&Opts{
+		EKSService:            services.NewEKSService(cfg),
+		EC2Service:            services.NewEC2Service(cfg),
+		IAMService:            services.NewIAMService(cfg),
+		CloudFormationService: services.NewCloudFormationService(cfg),
+		PricingService:        services.NewPricingService(cfg),
+	})
+}
+
+type CreateClusterOpts struct {
+	Config *eksv1.EKSClusterConfig
+	// RoleARN is the ARN of the IAM role that the EKS control plane assumes
+	RoleARN string
+}
+
+func (c *client) CreateCluster(ctx context.Context, opts *CreateClusterOpts) error {
+	return awsservices.CreateCluster(ctx, &awsservices.CreateClusterOptions{
+		EKSService: c.eks,
+		Config:     opts.Config,
+		RoleARN:    opts.RoleARN,
+	})
+}
+
+type CreateNodeGroupOpts struct {
+	Config    *eksv1.EKSClusterConfig
+	NodeGroup eksv1.NodeGroup
+}
+
+type CreateNodeGroupResult struct {
+	// LaunchTemplateVersion is the version of the rancher-managed launch template used by the node group, if any
+	LaunchTemplateVersion string
+	// GeneratedNodeRole is the ARN of the node instance role generated for node groups without a node role
+	GeneratedNodeRole string
+}
+
+func (c *client) CreateNodeGroup(ctx context.Context, opts *CreateNodeGroupOpts) (*CreateNodeGroupResult, error) {
+	launchTemplateVersion, generatedNodeRole, err := awsservices.CreateNodeGroup(ctx, &awsservices.CreateNodeGroupOptions{
+		EC2Service:            c.ec2,
+		CloudFormationService: c.cloudformation,
+		EKSService:            c.eks,
+		Config:                opts.Config,
+		NodeGroup:             opts.NodeGroup,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &CreateNodeGroupResult{
+		LaunchTemplateVersion: launchTemplateVersion,
+		GeneratedNodeRole:     generatedNodeRole,
+	}, nil
+}
+
+type BuildUpstreamClusterStateOpts struct {
+	// DisplayName is the name of the cluster in EKS
+	DisplayName string
+	// ManagedLaunchTemplateID is the ID of the rancher-managed launch template of the cluster, if any
+	ManagedLaunchTemplateID string
+	// IncludeManagedLaunchTemplate keeps the rancher-managed launch template on the node groups that use it
+	IncludeManagedLaunchTemplate bool
+	ClusterState                 *eks.DescribeClusterOutput
+	NodeGroupStates              []*eks.DescribeNodegroupOutput
+}
+
+type UpstreamClusterState struct {
+	Spec       *eksv1.EKSClusterConfigSpec
+	ClusterARN string
+}
+
+func (c *client) BuildUpstreamClusterState(ctx context.Context, opts *BuildUpstreamClusterStateOpts) (*UpstreamClusterState, error) {
+	spec, clusterARN, err := controller.BuildUpstreamClusterState(ctx, opts.DisplayName, opts.ManagedLaunchTemplateID,
+		opts.ClusterState, opts.NodeGroupStates, c.ec2, c.eks, opts.IncludeManagedLaunchTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UpstreamClusterState{
+		Spec:       spec,
+		ClusterARN: clusterARN,
+	}, nil
+}
+
+type EnableEBSCSIDriverOpts struct {
+	Config *eksv1.EKSClusterConfig
+	// AddonVersion is the version of the add-on to install, the latest one is installed if empty
+	AddonVersion string
+}
+
+func (c *client) EnableEBSCSIDriver(ctx context.Context, opts *EnableEBSCSIDriverOpts) error {
+	addonVersion := opts.AddonVersion
+	if addonVersion == "" {
+		addonVersion = "latest"
+	}
+
+	_, err := awsservices.EnableEBSCSIDriver(ctx, &awsservices.EnableEBSCSIDriverInput{
+		EKSService:   c.eks,
+		IAMService:   c.iam,
+		CFService:    c.cloudformation,
+		Config:       opts.Config,
+		AddonVersion: addonVersion,
+	})
+	return err
+}
+
+type EstimateCostOpts struct {
+	Config *eksv1.EKSClusterConfig
+	// NATGateways is the number of NAT gateways created along with the cluster
+	NATGateways int32
+}
+
+func (c *client) EstimateCost(ctx context.Context, opts *EstimateCostOpts) (*eksv1.CostEstimate, error) {
+	return awsservices.EstimateCost(ctx, &awsservices.EstimateCostOpts{
+		PricingService: c.pricing,
+		Config:         opts.Config,
+		NATGateways:    opts.NATGateways,
+	})
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/golang/mock/gomock"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services/mock_services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateCluster(t *testing.T) {
+	mockController := gomock.NewController(t)
+	eksServiceMock := mock_services.NewMockEKSServiceInterface(mockController)
+	c := New(&Opts{EKSService: eksServiceMock})
+
+	eksServiceMock.EXPECT().CreateCluster(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, input *eks.CreateClusterInput) (*eks.CreateClusterOutput, error) {
+			assert.Equal(t, "test", aws.ToString(input.Name))
+			assert.Equal(t, "role-arn", aws.ToString(input.RoleArn))
+			return &eks.CreateClusterOutput{}, nil
+		})
+
+	err := c.CreateCluster(context.Background(), &CreateClusterOpts{
+		Config:  &eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{DisplayName: "test"}},
+		RoleARN: "role-arn",
+	})
+	assert.NoError(t, err)
+}
+
+func TestCreateNodeGroup(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{DisplayName: "test"}}
+	nodeGroup := eksv1.NodeGroup{
+		NodegroupName:  aws.String("ng"),
+		NodeRole:       aws.String("node-role"),
+		LaunchTemplate: &eksv1.LaunchTemplate{ID: aws.String("lt"), Version: aws.Int64(3)},
+	}
+
+	t.Run("created", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		eksServiceMock := mock_services.NewMockEKSServiceInterface(mockController)
+		c := New(&Opts{EKSService: eksServiceMock})
+
+		eksServiceMock.EXPECT().CreateNodegroup(gomock.Any(), gomock.Any()).Return(&eks.CreateNodegroupOutput{}, nil)
+
+		result, err := c.CreateNodeGroup(context.Background(), &CreateNodeGroupOpts{Config: config, NodeGroup: nodeGroup})
+		assert.NoError(t, err)
+		assert.Equal(t, &CreateNodeGroupResult{LaunchTemplateVersion: "3"}, result)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		eksServiceMock := mock_services.NewMockEKSServiceInterface(mockController)
+		ec2ServiceMock := mock_services.NewMockEC2ServiceInterface(mockController)
+		c := New(&Opts{EKSService: eksServiceMock, EC2Service: ec2ServiceMock})
+
+		eksServiceMock.EXPECT().CreateNodegroup(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+		ec2ServiceMock.EXPECT().DeleteLaunchTemplateVersions(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+		result, err := c.CreateNodeGroup(context.Background(), &CreateNodeGroupOpts{Config: config, NodeGroup: nodeGroup})
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+}
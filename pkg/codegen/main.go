@@ -43,6 +43,14 @@ func main() {
 		return c
 	})
 
+	eksInventory := newCRD(&eksv1.EKSInventory{}, nil)
+
+	eksClusterTemplate := newCRD(&eksv1.EKSClusterTemplate{}, func(c crd.CRD) crd.CRD {
+		c.Status = false
+		c.ShortNames = []string{"eksct"}
+		return c
+	})
+
 	obj, err := eksClusterConfig.ToCustomResourceDefinition()
 	if err != nil {
 		panic(err)
@@ -52,7 +60,25 @@ func main() {
 		"helm.sh/resource-policy": "keep",
 	})
 
-	eksCCYaml, err := yaml.Export(obj)
+	inventoryObj, err := eksInventory.ToCustomResourceDefinition()
+	if err != nil {
+		panic(err)
+	}
+
+	inventoryObj.(*unstructured.Unstructured).SetAnnotations(map[string]string{
+		"helm.sh/resource-policy": "keep",
+	})
+
+	templateObj, err := eksClusterTemplate.ToCustomResourceDefinition()
+	if err != nil {
+		panic(err)
+	}
+
+	templateObj.(*unstructured.Unstructured).SetAnnotations(map[string]string{
+		"helm.sh/resource-policy": "keep",
+	})
+
+	eksCCYaml, err := yaml.Export(obj, inventoryObj, templateObj)
 	if err != nil {
 		panic(err)
 	}
@@ -0,0 +1,166 @@
+package eks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	hoursPerMonth = 730
+	costCurrency  = "USD"
+
+	controlPlaneUsageType = "AmazonEKS-Hours:perCluster"
+	natGatewayUsageType   = "NatGateway-Hours"
+	instanceUsageType     = "BoxUsage:%s"
+)
+
+type EstimateCostOpts struct {
+	PricingService services.PricingServiceInterface
+	Config         *eksv1.EKSClusterConfig
+	// NATGateways is the number of NAT gateways created along with the cluster
+	NATGateways int32
+}
+
+// EstimateCost estimates the monthly on-demand cost of the control plane, node groups and NAT gateways of the cluster
+// described by the config. Spot node groups are estimated with the on-demand price of their first instance type, which
+// is an upper bound, and node groups whose instance type is only known to their custom launch template are skipped.
+func EstimateCost(ctx context.Context, opts *EstimateCostOpts) (*eksv1.CostEstimate, error) {
+	region := opts.Config.Spec.Region
+	estimate := &eksv1.CostEstimate{Currency: costCurrency}
+	var total float64
+	addItem := func(name string, quantity int32, hourly float64) {
+		monthly := hourly * hoursPerMonth * float64(quantity)
+		total += monthly
+		estimate.Items = append(estimate.Items, eksv1.CostEstimateItem{
+			Name:        name,
+			Quantity:    quantity,
+			HourlyCost:  formatCost(hourly),
+			MonthlyCost: formatCost(monthly),
+		})
+	}
+
+	hourly, err := getHourlyPrice(ctx, opts.PricingService, "AmazonEKS", region, controlPlaneUsageType, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error getting control plane price: %w", err)
+	}
+	addItem("control plane", 1, hourly)
+
+	for _, ng := range opts.Config.Spec.NodeGroups {
+		instanceType, name := ng.InstanceType, fmt.Sprintf("node group %s", aws.ToString(ng.NodegroupName))
+		if aws.ToBool(ng.RequestSpotInstances) && len(ng.SpotInstanceTypes) != 0 {
+			instanceType, name = ng.SpotInstanceTypes[0], name+" (spot, on-demand price)"
+		}
+		if instanceType == "" {
+			logrus.Infof("Skipping cost estimation of node group [%s] in cluster [%s (id: %s)] without an instance type",
+				aws.ToString(ng.NodegroupName), opts.Config.Spec.DisplayName, opts.Config.Name)
+			continue
+		}
+
+		hourly, err := getHourlyPrice(ctx, opts.PricingService, "AmazonEC2", region, fmt.Sprintf(instanceUsageType, instanceType), []services.PricingFilter{
+			termMatch("instanceType", instanceType),
+			termMatch("operatingSystem", "Linux"),
+			termMatch("tenancy", "Shared"),
+			termMatch("preInstalledSw", "NA"),
+			termMatch("capacitystatus", "Used"),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error getting price of instance type [%s]: %w", instanceType, err)
+		}
+		addItem(fmt.Sprintf("%s (%s)", name, instanceType), aws.ToInt32(ng.DesiredSize), hourly)
+	}
+
+	if opts.NATGateways > 0 {
+		hourly, err := getHourlyPrice(ctx, opts.PricingService, "AmazonEC2", region, natGatewayUsageType, []services.PricingFilter{
+			termMatch("productFamily", "NAT Gateway"),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error getting NAT gateway price: %w", err)
+		}
+		addItem("NAT gateway", opts.NATGateways, hourly)
+	}
+
+	estimate.MonthlyCost = formatCost(total)
+	return estimate, nil
+}
+
+// getHourlyPrice returns the hourly on-demand price of the first product of the service in the region with the given
+// usage type. Usage types are prefixed with a region code outside of us-east-1.
+func getHourlyPrice(ctx context.Context, svc services.PricingServiceInterface, serviceCode, region, usageType string, filters []services.PricingFilter) (float64, error) {
+	input := &services.GetProductsInput{
+		ServiceCode: serviceCode,
+		Filters:     append([]services.PricingFilter{termMatch("regionCode", region)}, filters...),
+		MaxResults:  aws.Int32(100),
+	}
+	for {
+		output, err := svc.GetProducts(ctx, input)
+		if err != nil {
+			return 0, err
+		}
+		for _, priceList := range output.PriceList {
+			product := priceListProduct{}
+			if err := json.Unmarshal([]byte(priceList), &product); err != nil {
+				return 0, fmt.Errorf("error decoding price list: %w", err)
+			}
+			if productUsageType := product.Product.Attributes["usagetype"]; productUsageType != usageType &&
+				!strings.HasSuffix(productUsageType, "-"+usageType) {
+				continue
+			}
+			if price, ok := product.hourlyPrice(); ok {
+				return price, nil
+			}
+		}
+		if output.NextToken == nil {
+			return 0, fmt.Errorf("no price found for [%s] in region [%s]", usageType, region)
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+// priceListProduct holds the parts of a price list entry needed to get its hourly on-demand price.
+type priceListProduct struct {
+	Product struct {
+		Attributes map[string]string `json:"attributes"`
+	} `json:"product"`
+	Terms struct {
+		OnDemand map[string]struct {
+			PriceDimensions map[string]struct {
+				Unit         string            `json:"unit"`
+				PricePerUnit map[string]string `json:"pricePerUnit"`
+			} `json:"priceDimensions"`
+		} `json:"OnDemand"`
+	} `json:"terms"`
+}
+
+// hourlyPrice returns the hourly on-demand price of the product, if it has one.
+func (p priceListProduct) hourlyPrice() (float64, bool) {
+	for _, term := range p.Terms.OnDemand {
+		for _, dimension := range term.PriceDimensions {
+			if dimension.Unit != "Hrs" {
+				continue
+			}
+			price, err := strconv.ParseFloat(dimension.PricePerUnit[costCurrency], 64)
+			if err != nil {
+				continue
+			}
+			return price, true
+		}
+	}
+
+	return 0, false
+}
+
+func termMatch(field, value string) services.PricingFilter {
+	return services.PricingFilter{Type: "TERM_MATCH", Field: field, Value: value}
+}
+
+func formatCost(cost float64) string {
+	return strconv.FormatFloat(cost, 'f', 2, 64)
+}
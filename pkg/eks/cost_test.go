@@ -0,0 +1,107 @@
+package eks
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services"
+	"github.com/rancher/eks-operator/pkg/eks/services/mock_services"
+)
+
+func priceList(usageType, unit, price string) string {
+	return fmt.Sprintf(`{"product":{"attributes":{"usagetype":%q}},"terms":{"OnDemand":{"SKU.TERM":{"priceDimensions":{"SKU.TERM.RATE":{"unit":%q,"pricePerUnit":{"USD":%q}}}}}}}`,
+		usageType, unit, price)
+}
+
+var _ = Describe("EstimateCost", func() {
+	var (
+		mockController     *gomock.Controller
+		pricingServiceMock *mock_services.MockPricingServiceInterface
+		estimateCostOpts   *EstimateCostOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		pricingServiceMock = mock_services.NewMockPricingServiceInterface(mockController)
+		estimateCostOpts = &EstimateCostOpts{
+			PricingService: pricingServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				Spec: eksv1.EKSClusterConfigSpec{
+					Region: "us-west-2",
+					NodeGroups: []eksv1.NodeGroup{
+						{
+							NodegroupName: aws.String("ng1"),
+							InstanceType:  "m5.large",
+							DesiredSize:   aws.Int32(2),
+						},
+						{
+							NodegroupName:  aws.String("ng2"),
+							LaunchTemplate: &eksv1.LaunchTemplate{ID: aws.String("lt")},
+						},
+					},
+				},
+			},
+			NATGateways: 2,
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should estimate cost", func() {
+		pricingServiceMock.EXPECT().GetProducts(ctx, gomock.Any()).DoAndReturn(
+			func(_ interface{}, input *services.GetProductsInput) (*services.GetProductsOutput, error) {
+				Expect(input.Filters[0]).To(Equal(termMatch("regionCode", "us-west-2")))
+				switch {
+				case input.ServiceCode == "AmazonEKS":
+					return &services.GetProductsOutput{PriceList: []string{
+						priceList("USW2-AmazonEKS-Hours:extendedSupport", "Hrs", "0.6"),
+						priceList("USW2-AmazonEKS-Hours:perCluster", "Hrs", "0.1"),
+					}}, nil
+				case input.Filters[1].Field == "instanceType":
+					if input.NextToken == nil {
+						return &services.GetProductsOutput{
+							PriceList: []string{priceList("USW2-HostBoxUsage:m5.large", "Hrs", "0")},
+							NextToken: aws.String("next"),
+						}, nil
+					}
+					return &services.GetProductsOutput{PriceList: []string{priceList("USW2-BoxUsage:m5.large", "Hrs", "0.096")}}, nil
+				default:
+					return &services.GetProductsOutput{PriceList: []string{
+						priceList("USW2-NatGateway-Bytes", "GB", "0.045"),
+						priceList("USW2-NatGateway-Hours", "Hrs", "0.045"),
+					}}, nil
+				}
+			}).Times(4)
+
+		estimate, err := EstimateCost(ctx, estimateCostOpts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(estimate.Currency).To(Equal("USD"))
+		Expect(estimate.Items).To(Equal([]eksv1.CostEstimateItem{
+			{Name: "control plane", Quantity: 1, HourlyCost: "0.10", MonthlyCost: "73.00"},
+			{Name: "node group ng1 (m5.large)", Quantity: 2, HourlyCost: "0.10", MonthlyCost: "140.16"},
+			{Name: "NAT gateway", Quantity: 2, HourlyCost: "0.04", MonthlyCost: "65.70"},
+		}))
+		Expect(estimate.MonthlyCost).To(Equal("278.86"))
+	})
+
+	It("should return error if no price is found", func() {
+		pricingServiceMock.EXPECT().GetProducts(ctx, gomock.Any()).Return(&services.GetProductsOutput{}, nil)
+
+		_, err := EstimateCost(ctx, estimateCostOpts)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return error if getting products failed", func() {
+		pricingServiceMock.EXPECT().GetProducts(ctx, gomock.Any()).Return(nil, errors.New("error getting products"))
+
+		_, err := EstimateCost(ctx, estimateCostOpts)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -1,17 +1,15 @@
 package eks
 
 import (
-	"bytes"
 	"context"
 	"crypto/sha1"
-	"crypto/tls"
 	"encoding/base64"
 	"errors"
 	"fmt"
-	"html/template"
 	"net/http"
 	"net/url"
 	"path"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -25,7 +23,8 @@ import (
 	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
 	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
-	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/smithy-go"
+	"github.com/sirupsen/logrus"
 
 	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
 	"github.com/rancher/eks-operator/pkg/eks/services"
@@ -40,6 +39,10 @@ const (
 	createFailedStatus       = "CREATE_FAILED"
 	rollbackInProgressStatus = "ROLLBACK_IN_PROGRESS"
 
+	// reason of resources whose creation was cancelled because another resource of the stack failed
+	stackResourceCancelledReason = "Resource creation cancelled"
+	maxStackFailureReasons       = 10
+
 	LaunchTemplateNameFormat = "rancher-managed-lt-%s"
 	launchTemplateTagKey     = "rancher-managed-template"
 	launchTemplateTagValue   = "do-not-modify-or-delete"
@@ -47,8 +50,33 @@ const (
 
 	defaultAudienceOpenIDConnect = "sts.amazonaws.com"
 	ebsCSIAddonName              = "aws-ebs-csi-driver"
+	podIdentityAgentAddonName    = "eks-pod-identity-agent"
+	containerInsightsAddonName   = "amazon-cloudwatch-observability"
+	ebsCSIServiceAccount         = "ebs-csi-controller-sa"
+	// time the thumbprint of an oidc issuer is fetched within
+	issuerRequestTimeout = 30 * time.Second
+
+	// backends the service and node instance roles of clusters are created with
+	ProvisioningBackendCloudFormation = "cloudformation"
+	ProvisioningBackendNative         = "native"
+)
+
+var (
+	// policies of the roles created through IAM, the same as the ones of the role templates
+	serviceRolePolicyARNs = []string{
+		"arn:aws:iam::aws:policy/AmazonEKSServicePolicy",
+		"arn:aws:iam::aws:policy/AmazonEKSClusterPolicy",
+	}
+	nodeInstanceRolePolicyARNs = []string{
+		"arn:aws:iam::aws:policy/AmazonEKSWorkerNodePolicy",
+		"arn:aws:iam::aws:policy/AmazonEKS_CNI_Policy",
+		"arn:aws:iam::aws:policy/AmazonEC2ContainerRegistryReadOnly",
+	}
 )
 
+// ContainerInsightsPolicyARN is the policy the CloudWatch agent of Container Insights needs on the node instance role
+const ContainerInsightsPolicyARN = "arn:aws:iam::aws:policy/CloudWatchAgentServerPolicy"
+
 type CreateClusterOptions struct {
 	EKSService services.EKSServiceInterface
 	Config     *eksv1.EKSClusterConfig
@@ -73,11 +101,31 @@ func newClusterInput(config *eksv1.EKSClusterConfig, roleARN string) *eks.Create
 			SubnetIds:             config.Status.Subnets,
 			PublicAccessCidrs:     getPublicAccessCidrs(config.Spec.PublicAccessSources),
 		},
-		Tags:    getTags(config.Spec.Tags),
+		Tags:    WithOwnershipTags(config, config.Spec.Tags),
 		Logging: getLogging(config.Spec.LoggingTypes),
 		Version: config.Spec.KubernetesVersion,
 	}
 
+	if config.Spec.OutpostConfig != nil {
+		createClusterInput.OutpostConfig = &ekstypes.OutpostConfigRequest{
+			OutpostArns:              config.Spec.OutpostConfig.OutpostARNs,
+			ControlPlaneInstanceType: aws.String(config.Spec.OutpostConfig.ControlPlaneInstanceType),
+		}
+		if groupName := config.Spec.OutpostConfig.ControlPlanePlacementGroupName; groupName != "" {
+			createClusterInput.OutpostConfig.ControlPlanePlacement = &ekstypes.ControlPlanePlacementRequest{
+				GroupName: aws.String(groupName),
+			}
+		}
+	}
+
+	if len(config.Spec.ClusterAdminPrincipals) != 0 {
+		// the access entries of the cluster admin principals are created once the cluster is active, the creator of the
+		// cluster keeps its admin access either way
+		createClusterInput.AccessConfig = &ekstypes.CreateAccessConfigRequest{
+			AuthenticationMode: ekstypes.AuthenticationModeApiAndConfigMap,
+		}
+	}
+
 	if aws.ToBool(config.Spec.SecretsEncryption) {
 		createClusterInput.EncryptionConfig = []ekstypes.EncryptionConfig{
 			{
@@ -99,71 +147,172 @@ type CreateStackOptions struct {
 	TemplateBody          string
 	Capabilities          []cftypes.Capability
 	Parameters            []cftypes.Parameter
+	// Tags are set on the stack and the resources it creates along with the displayName tag
+	Tags map[string]string
+	// Template is the name of the template the stack is created from, the outputs it must return are checked once the
+	// stack is created
+	Template string
+}
+
+// StackCreationInProgressError is returned by CreateStack while the stack is still being created.
+type StackCreationInProgressError struct {
+	StackName string
+	StackID   string
 }
 
+func (e *StackCreationInProgressError) Error() string {
+	return fmt.Sprintf("stack [%s] is still being created", e.StackName)
+}
+
+// MissingStackOutputError is returned by CreateStack if a created stack didn't return an output its template must
+// return, which happens if an override of the template doesn't declare it.
+type MissingStackOutputError struct {
+	StackName string
+	StackID   string
+	Template  string
+	OutputKey string
+}
+
+func (e *MissingStackOutputError) Error() string {
+	return fmt.Sprintf("stack [%s (id: %s)] created from template [%s] didn't return the required output [%s]",
+		e.StackName, e.StackID, e.Template, e.OutputKey)
+}
+
+// CreateStack starts the creation of the stack if it doesn't exist yet and returns its state without waiting for the
+// creation to finish. A *StackCreationInProgressError is returned while the stack is being created, in which case
+// CreateStack should be called again later to check on it.
 func CreateStack(ctx context.Context, opts *CreateStackOptions) (*cloudformation.DescribeStacksOutput, error) {
+	tags := []cftypes.Tag{
+		{
+			Key:   aws.String("displayName"),
+			Value: aws.String(opts.DisplayName),
+		},
+	}
+	for _, key := range sortedTagKeys(opts.Tags) {
+		tags = append(tags, cftypes.Tag{Key: aws.String(key), Value: aws.String(opts.Tags[key])})
+	}
 	_, err := opts.CloudFormationService.CreateStack(ctx, &cloudformation.CreateStackInput{
 		StackName:    aws.String(opts.StackName),
 		TemplateBody: aws.String(opts.TemplateBody),
 		Capabilities: opts.Capabilities,
 		Parameters:   opts.Parameters,
-		Tags: []cftypes.Tag{
-			{
-				Key:   aws.String("displayName"),
-				Value: aws.String(opts.DisplayName),
-			},
-		},
+		Tags:         tags,
 	})
 	if err != nil && !alreadyExistsInCloudFormationError(err) {
-		return nil, fmt.Errorf("error creating master: %v", err)
+		return nil, fmt.Errorf("error creating master: %w", err)
+	}
+
+	stack, err := opts.CloudFormationService.DescribeStacks(ctx, &cloudformation.DescribeStacksInput{
+		StackName: aws.String(opts.StackName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error polling stack info: %w", err)
 	}
 
-	var stack *cloudformation.DescribeStacksOutput
-	status := createInProgressStatus
+	if stack == nil || stack.Stacks == nil || len(stack.Stacks) == 0 {
+		return nil, fmt.Errorf("stack did not have output: %v", err)
+	}
 
-	for status == createInProgressStatus {
-		time.Sleep(time.Second * 5)
-		stack, err = opts.CloudFormationService.DescribeStacks(ctx, &cloudformation.DescribeStacksInput{
-			StackName: aws.String(opts.StackName),
-		})
-		if err != nil {
-			return nil, fmt.Errorf("error polling stack info: %v", err)
+	switch string(stack.Stacks[0].StackStatus) {
+	case createInProgressStatus:
+		return nil, &StackCreationInProgressError{
+			StackName: opts.StackName,
+			StackID:   aws.ToString(stack.Stacks[0].StackId),
 		}
-
-		if stack == nil || stack.Stacks == nil || len(stack.Stacks) == 0 {
-			return nil, fmt.Errorf("stack did not have output: %v", err)
+	case createCompleteStatus:
+		if err := validateStackOutputs(opts.Template, stack.Stacks[0]); err != nil {
+			return nil, err
 		}
+		return stack, nil
+	}
+
+	reasons := getStackFailureReasons(ctx, opts.CloudFormationService, opts.StackName)
+	if len(reasons) == 0 {
+		return nil, fmt.Errorf("stack [%s] failed to create: reason unknown", opts.StackName)
+	}
+	return nil, fmt.Errorf("stack [%s] failed to create:\n%s", opts.StackName, strings.Join(reasons, "\n"))
+}
 
-		status = string(stack.Stacks[0].StackStatus)
+// getStackFailureReasons returns the reasons of all resources of the stack that failed to create, oldest first, reading
+// through all pages of stack events. Resources whose creation was only cancelled because of another failure are left
+// out. If no resource failed, the reason the rollback was started is returned instead. At most
+// maxStackFailureReasons reasons are returned so that the failure message stays readable.
+func getStackFailureReasons(ctx context.Context, svc services.CloudFormationServiceInterface, stackName string) []string {
+	var failed []string
+	var rollbackReason string
+	seen := make(map[string]struct{})
+	input := &cloudformation.DescribeStackEventsInput{
+		StackName: aws.String(stackName),
 	}
+	for {
+		events, err := svc.DescribeStackEvents(ctx, input)
+		if err != nil {
+			logrus.Warnf("Could not get events of stack [%s]: %v", stackName, err)
+			break
+		}
+		for _, event := range events.StackEvents {
+			// guard against nil pointer dereference
+			if event.LogicalResourceId == nil || event.ResourceStatusReason == nil {
+				continue
+			}
 
-	if status != createCompleteStatus {
-		reason := "reason unknown"
-		events, err := opts.CloudFormationService.DescribeStackEvents(ctx, &cloudformation.DescribeStackEventsInput{
-			StackName: aws.String(opts.StackName),
-		})
-		if err == nil {
-			for _, event := range events.StackEvents {
-				// guard against nil pointer dereference
-				if event.LogicalResourceId == nil || event.ResourceStatusReason == nil {
+			switch event.ResourceStatus {
+			case cftypes.ResourceStatusCreateFailed:
+				if strings.Contains(*event.ResourceStatusReason, stackResourceCancelledReason) {
 					continue
 				}
-
-				if event.ResourceStatus == cftypes.ResourceStatusCreateFailed {
-					reason = *event.ResourceStatusReason
-					break
-				}
-
-				if event.ResourceStatus == cftypes.ResourceStatusRollbackInProgress {
-					reason = *event.ResourceStatusReason
-					// do not break so that CREATE_FAILED takes priority
+				reason := fmt.Sprintf("- %s (%s): %s", *event.LogicalResourceId, aws.ToString(event.ResourceType), *event.ResourceStatusReason)
+				if _, ok := seen[reason]; !ok {
+					seen[reason] = struct{}{}
+					failed = append(failed, reason)
 				}
+			case cftypes.ResourceStatusRollbackInProgress:
+				rollbackReason = *event.ResourceStatusReason
 			}
 		}
-		return nil, fmt.Errorf("stack failed to create: %v", reason)
+		if events.NextToken == nil {
+			break
+		}
+		input.NextToken = events.NextToken
+	}
+
+	if len(failed) == 0 {
+		if rollbackReason != "" {
+			return []string{"- " + rollbackReason}
+		}
+		return nil
+	}
+
+	// events are listed newest first
+	slices.Reverse(failed)
+	if len(failed) > maxStackFailureReasons {
+		failed = append(failed[:maxStackFailureReasons], fmt.Sprintf("- and %d more", len(failed)-maxStackFailureReasons))
 	}
+	return failed
+}
 
-	return stack, nil
+type TagSubnetsOptions struct {
+	EC2Service services.EC2ServiceInterface
+	SubnetIDs  []string
+	Tags       map[string]string
+}
+
+// TagSubnets adds the given tags to the subnets. Subnets shared from other accounts can only be tagged by their owner.
+func TagSubnets(ctx context.Context, opts *TagSubnetsOptions) error {
+	if len(opts.SubnetIDs) == 0 || len(opts.Tags) == 0 {
+		return nil
+	}
+
+	tags := make([]ec2types.Tag, 0, len(opts.Tags))
+	for key, value := range opts.Tags {
+		tags = append(tags, ec2types.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+
+	_, err := opts.EC2Service.CreateTags(ctx, &ec2.CreateTagsInput{
+		Resources: opts.SubnetIDs,
+		Tags:      tags,
+	})
+	return err
 }
 
 type CreateLaunchTemplateOptions struct {
@@ -176,7 +325,7 @@ func CreateLaunchTemplate(ctx context.Context, opts *CreateLaunchTemplateOptions
 		LaunchTemplateIds: []string{opts.Config.Status.ManagedLaunchTemplateID},
 	})
 	if opts.Config.Status.ManagedLaunchTemplateID == "" || doesNotExist(err) {
-		lt, err := createLaunchTemplate(ctx, opts.EC2Service, opts.Config.Spec.DisplayName)
+		lt, err := createLaunchTemplate(ctx, opts.EC2Service, opts.Config.Spec.DisplayName, OwnershipTags(opts.Config))
 		if err != nil {
 			return fmt.Errorf("error creating launch template: %w", err)
 		}
@@ -188,22 +337,26 @@ func CreateLaunchTemplate(ctx context.Context, opts *CreateLaunchTemplateOptions
 	return nil
 }
 
-func createLaunchTemplate(ctx context.Context, ec2Service services.EC2ServiceInterface, clusterDisplayName string) (*eksv1.LaunchTemplate, error) {
+func createLaunchTemplate(ctx context.Context, ec2Service services.EC2ServiceInterface, clusterDisplayName string, tags map[string]string) (*eksv1.LaunchTemplate, error) {
 	// The first version of the rancher-managed launch template will be the default version.
 	// Since the default version cannot be deleted until the launch template is deleted, it will not be used for any node group.
 	// Also, launch templates cannot be created blank, so fake userdata is added to the first version.
+	templateTags := []ec2types.Tag{
+		{
+			Key:   aws.String(launchTemplateTagKey),
+			Value: aws.String(launchTemplateTagValue),
+		},
+	}
+	for _, key := range sortedTagKeys(tags) {
+		templateTags = append(templateTags, ec2types.Tag{Key: aws.String(key), Value: aws.String(tags[key])})
+	}
 	launchTemplateCreateInput := &ec2.CreateLaunchTemplateInput{
 		LaunchTemplateData: &ec2types.RequestLaunchTemplateData{UserData: aws.String("cGxhY2Vob2xkZXIK")},
 		LaunchTemplateName: aws.String(fmt.Sprintf(LaunchTemplateNameFormat, clusterDisplayName)),
 		TagSpecifications: []ec2types.TagSpecification{
 			{
 				ResourceType: ec2types.ResourceTypeLaunchTemplate,
-				Tags: []ec2types.Tag{
-					{
-						Key:   aws.String(launchTemplateTagKey),
-						Value: aws.String(launchTemplateTagValue),
-					},
-				},
+				Tags:         templateTags,
 			},
 		},
 	}
@@ -224,9 +377,12 @@ type CreateNodeGroupOptions struct {
 	EC2Service            services.EC2ServiceInterface
 	CloudFormationService services.CloudFormationServiceInterface
 	EKSService            services.EKSServiceInterface
+	IAMService            services.IAMServiceInterface
 
 	Config    *eksv1.EKSClusterConfig
 	NodeGroup eksv1.NodeGroup
+	// TemplateOverrides replace the built-in CloudFormation templates, keyed by template name
+	TemplateOverrides map[string]string
 }
 
 func CreateNodeGroup(ctx context.Context, opts *CreateNodeGroupOptions) (string, string, error) {
@@ -247,16 +403,22 @@ func CreateNodeGroup(ctx context.Context, opts *CreateNodeGroupOptions) (string,
 		CapacityType: capacityType,
 	}
 
+	if opts.NodeGroup.NodeRepairConfig != nil {
+		nodeGroupCreateInput.NodeRepairConfig = &ekstypes.NodeRepairConfig{
+			Enabled: opts.NodeGroup.NodeRepairConfig.Enabled,
+		}
+	}
+
 	lt := opts.NodeGroup.LaunchTemplate
 
-	if len(opts.NodeGroup.ResourceTags) > 0 {
-		nodeGroupCreateInput.Tags = opts.NodeGroup.ResourceTags
-	}
+	// the ownership tags are only set on the node group, not on its instances, so that they don't cause new launch
+	// template versions
+	nodeGroupCreateInput.Tags = WithOwnershipTags(opts.Config, opts.NodeGroup.ResourceTags)
 
 	if lt == nil {
 		// In this case, the user has not specified their own launch template.
 		// If the cluster doesn't have a launch template associated with it, then we create one.
-		lt, err = CreateNewLaunchTemplateVersion(ctx, opts.EC2Service, opts.Config.Status.ManagedLaunchTemplateID, opts.NodeGroup)
+		lt, err = CreateManagedLaunchTemplateVersion(ctx, opts.EC2Service, opts.Config, opts.NodeGroup)
 		if err != nil {
 			return "", "", err
 		}
@@ -288,6 +450,10 @@ func CreateNodeGroup(ctx context.Context, opts *CreateNodeGroupOptions) (string,
 		}
 	}
 
+	if aws.ToString(opts.NodeGroup.ReleaseVersion) != "" && opts.NodeGroup.LaunchTemplate == nil && aws.ToString(opts.NodeGroup.ImageID) == "" {
+		nodeGroupCreateInput.ReleaseVersion = opts.NodeGroup.ReleaseVersion
+	}
+
 	if len(opts.NodeGroup.Subnets) != 0 {
 		nodeGroupCreateInput.Subnets = opts.NodeGroup.Subnets
 	} else {
@@ -296,27 +462,24 @@ func CreateNodeGroup(ctx context.Context, opts *CreateNodeGroupOptions) (string,
 
 	generatedNodeRole := opts.Config.Status.GeneratedNodeRole
 
-	if aws.ToString(opts.NodeGroup.NodeRole) == "" {
+	if aws.ToString(opts.NodeGroup.NodeRole) != "" {
+		nodeGroupCreateInput.NodeRole = opts.NodeGroup.NodeRole
+	} else if aws.ToString(opts.Config.Spec.NodeRole) != "" {
+		// the shared node role of the config replaces the generated one, which is not created
+		nodeGroupCreateInput.NodeRole = opts.Config.Spec.NodeRole
+	} else {
 		if opts.Config.Status.GeneratedNodeRole == "" {
-			finalTemplate := fmt.Sprintf(templates.NodeInstanceRoleTemplate, getEC2ServiceEndpoint(opts.Config.Spec.Region))
-			output, err := CreateStack(ctx, &CreateStackOptions{
-				CloudFormationService: opts.CloudFormationService,
-				StackName:             fmt.Sprintf("%s-node-instance-role", opts.Config.Spec.DisplayName),
-				DisplayName:           opts.Config.Spec.DisplayName,
-				TemplateBody:          finalTemplate,
-				Capabilities:          []cftypes.Capability{cftypes.CapabilityCapabilityIam},
-				Parameters:            []cftypes.Parameter{},
-			})
+			generatedNodeRole, err = createNodeInstanceRole(ctx, opts)
 			if err != nil {
-				// If there was an error creating the node role stack, return an empty launch template
-				// version and the error.
+				// If there was an error creating the node role or its stack is still being created, the template
+				// version should be deleted, as a new one is created the next time the node group is created.
+				if opts.NodeGroup.LaunchTemplate == nil && lt.ID != nil {
+					DeleteLaunchTemplateVersions(ctx, opts.EC2Service, *lt.ID, []*string{launchTemplateVersion})
+				}
 				return "", "", err
 			}
-			generatedNodeRole = getParameterValueFromOutput("NodeInstanceRole", output.Stacks[0].Outputs)
 		}
 		nodeGroupCreateInput.NodeRole = aws.String(generatedNodeRole)
-	} else {
-		nodeGroupCreateInput.NodeRole = opts.NodeGroup.NodeRole
 	}
 
 	_, err = opts.EKSService.CreateNodegroup(ctx, nodeGroupCreateInput)
@@ -331,6 +494,171 @@ func CreateNodeGroup(ctx context.Context, opts *CreateNodeGroupOptions) (string,
 	return aws.ToString(launchTemplateVersion), generatedNodeRole, err
 }
 
+// createNodeInstanceRole creates the node instance role shared by the node groups of the cluster without one, with the
+// provisioning backend of the cluster, and returns its ARN.
+func createNodeInstanceRole(ctx context.Context, opts *CreateNodeGroupOptions) (string, error) {
+	name := fmt.Sprintf("%s-node-instance-role", opts.Config.Spec.DisplayName)
+	service := getEC2ServiceEndpoint(opts.Config.Spec.Region)
+	additionalPolicyARNs := NodeInstanceRoleAdditionalPolicyARNs(opts.Config)
+	if opts.Config.Status.ProvisioningBackend == ProvisioningBackendNative {
+		policyARNs := append(slices.Clone(nodeInstanceRolePolicyARNs), additionalPolicyARNs...)
+		return createRole(ctx, opts.IAMService, name, opts.Config.Spec.DisplayName, service, opts.Config.Spec.IAMPermissionsBoundary, policyARNs)
+	}
+
+	templateBody, err := RenderTemplate(opts.TemplateOverrides, TemplateNodeInstanceRole, fmt.Sprintf(templates.NodeInstanceRoleTemplate, service),
+		NewTemplateParameters(opts.Config))
+	if err != nil {
+		return "", err
+	}
+	output, err := CreateStack(ctx, &CreateStackOptions{
+		CloudFormationService: opts.CloudFormationService,
+		StackName:             name,
+		DisplayName:           opts.Config.Spec.DisplayName,
+		TemplateBody:          templateBody,
+		Capabilities:          []cftypes.Capability{cftypes.CapabilityCapabilityIam},
+		Parameters:            RoleStackParameters(opts.Config, additionalPolicyARNs),
+		Tags:                  OwnershipTags(opts.Config),
+		Template:              TemplateNodeInstanceRole,
+	})
+	if err != nil {
+		return "", err
+	}
+	return getParameterValueFromOutput("NodeInstanceRole", output.Stacks[0].Outputs), nil
+}
+
+// NodeInstanceRoleAdditionalPolicyARNs returns the policies attached to the node instance role the operator creates in
+// addition to the EKS node policies: the ones of the spec and, if Container Insights is enabled, the policy of the
+// CloudWatch agent.
+func NodeInstanceRoleAdditionalPolicyARNs(config *eksv1.EKSClusterConfig) []string {
+	policyARNs := slices.Clone(config.Spec.NodeInstanceRolePolicyARNs)
+	if aws.ToBool(config.Spec.ContainerInsights) && !slices.Contains(policyARNs, ContainerInsightsPolicyARN) {
+		policyARNs = append(policyARNs, ContainerInsightsPolicyARN)
+	}
+	return policyARNs
+}
+
+type CreateServiceRoleOpts struct {
+	IAMService services.IAMServiceInterface
+	Config     *eksv1.EKSClusterConfig
+	RoleName   string
+}
+
+// CreateServiceRole creates the service role of the cluster through IAM instead of the service role stack, with the
+// additional policies of the spec, and returns its ARN.
+func CreateServiceRole(ctx context.Context, opts *CreateServiceRoleOpts) (string, error) {
+	policyARNs := append(slices.Clone(serviceRolePolicyARNs), opts.Config.Spec.ServiceRolePolicyARNs...)
+	return createRole(ctx, opts.IAMService, opts.RoleName, opts.Config.Spec.DisplayName, "eks.amazonaws.com", opts.Config.Spec.IAMPermissionsBoundary, policyARNs)
+}
+
+// RoleStackParameters returns the parameters of the stacks of the service role and node instance role, the
+// permissions boundary of the config and the given additional policies. Parameters that aren't set are left out, so
+// that template overrides only have to declare the parameters they are used with.
+func RoleStackParameters(config *eksv1.EKSClusterConfig, policyARNs []string) []cftypes.Parameter {
+	var parameters []cftypes.Parameter
+	if boundary := config.Spec.IAMPermissionsBoundary; boundary != "" {
+		parameters = append(parameters, cftypes.Parameter{ParameterKey: aws.String("PermissionsBoundary"), ParameterValue: aws.String(boundary)})
+	}
+	if len(policyARNs) != 0 {
+		parameters = append(parameters, cftypes.Parameter{ParameterKey: aws.String("AdditionalPolicyArns"), ParameterValue: aws.String(strings.Join(policyARNs, ","))})
+	}
+	return parameters
+}
+
+// createRole creates a role that the given service can assume, with the permissions boundary if it's set, and attaches
+// the policies to it. A role that already exists, because a previous attempt failed to attach the policies, is reused.
+func createRole(ctx context.Context, iamService services.IAMServiceInterface, name, displayName, service, permissionsBoundary string, policyARNs []string) (string, error) {
+	assumeRolePolicy := fmt.Sprintf(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"Service":"%s"},"Action":"sts:AssumeRole"}]}`, service)
+	createRoleInput := &iam.CreateRoleInput{
+		RoleName:                 aws.String(name),
+		AssumeRolePolicyDocument: aws.String(assumeRolePolicy),
+		Tags: []iamtypes.Tag{
+			{
+				Key:   aws.String("displayName"),
+				Value: aws.String(displayName),
+			},
+		},
+	}
+	if permissionsBoundary != "" {
+		createRoleInput.PermissionsBoundary = aws.String(permissionsBoundary)
+	}
+	output, err := iamService.CreateRole(ctx, createRoleInput)
+	var role *iamtypes.Role
+	var alreadyExists *iamtypes.EntityAlreadyExistsException
+	switch {
+	case errors.As(err, &alreadyExists):
+		existing, err := iamService.GetRole(ctx, &iam.GetRoleInput{RoleName: aws.String(name)})
+		if err != nil {
+			return "", fmt.Errorf("error getting role [%s]: %w", name, err)
+		}
+		role = existing.Role
+	case err != nil:
+		return "", fmt.Errorf("error creating role [%s]: %w", name, err)
+	default:
+		role = output.Role
+	}
+
+	for _, policyARN := range policyARNs {
+		if _, err := iamService.AttachRolePolicy(ctx, &iam.AttachRolePolicyInput{
+			RoleName:  aws.String(name),
+			PolicyArn: aws.String(policyARN),
+		}); err != nil {
+			return "", fmt.Errorf("error attaching policy [%s] to role [%s]: %w", policyARN, name, err)
+		}
+	}
+	return aws.ToString(role.Arn), nil
+}
+
+// CreateManagedLaunchTemplateVersion creates a new version of the managed launch template of the config for the node
+// group. When the template has reached its version limit, the versions that are no longer in use are deleted and the
+// creation is retried once, so that the limit doesn't fail every reconcile until the versions are cleaned up manually.
+func CreateManagedLaunchTemplateVersion(ctx context.Context, ec2Service services.EC2ServiceInterface, config *eksv1.EKSClusterConfig, group eksv1.NodeGroup) (*eksv1.LaunchTemplate, error) {
+	group, err := addClusterSecurityGroup(config, group)
+	if err != nil {
+		return nil, err
+	}
+
+	lt, err := CreateNewLaunchTemplateVersion(ctx, ec2Service, config.Status.ManagedLaunchTemplateID, group)
+	if !isLaunchTemplateVersionLimitExceeded(err) {
+		return lt, err
+	}
+
+	logrus.Warnf("Launch template [%s] of cluster [%s (id: %s)] reached its version limit, deleting unused versions",
+		config.Status.ManagedLaunchTemplateID, config.Spec.DisplayName, config.Name)
+	deleted, cleanupErr := DeleteUnusedLaunchTemplateVersions(ctx, ec2Service, config)
+	if cleanupErr != nil {
+		return nil, fmt.Errorf("error deleting unused versions of launch template [%s]: %w", config.Status.ManagedLaunchTemplateID, cleanupErr)
+	}
+	if deleted == 0 {
+		return nil, fmt.Errorf("launch template [%s] reached its version limit and has no unused versions: %w", config.Status.ManagedLaunchTemplateID, err)
+	}
+
+	return CreateNewLaunchTemplateVersion(ctx, ec2Service, config.Status.ManagedLaunchTemplateID, group)
+}
+
+// addClusterSecurityGroup returns the node group with the cluster security group added to the security groups of its
+// network interfaces. EKS only attaches the cluster security group to nodes whose launch template doesn't configure
+// security groups, so nodes couldn't join the cluster without it.
+func addClusterSecurityGroup(config *eksv1.EKSClusterConfig, group eksv1.NodeGroup) (eksv1.NodeGroup, error) {
+	if len(group.NetworkInterfaces) == 0 {
+		return group, nil
+	}
+	clusterSecurityGroup := config.Status.ClusterSecurityGroup
+	if clusterSecurityGroup == "" {
+		return group, fmt.Errorf("the network interfaces of nodegroup [%s] can't be configured before the security group of cluster [%s (id: %s)] is known",
+			aws.ToString(group.NodegroupName), config.Spec.DisplayName, config.Name)
+	}
+
+	networkInterfaces := make([]eksv1.NetworkInterface, 0, len(group.NetworkInterfaces))
+	for _, networkInterface := range group.NetworkInterfaces {
+		if !slices.Contains(networkInterface.SecurityGroups, clusterSecurityGroup) {
+			networkInterface.SecurityGroups = append([]string{clusterSecurityGroup}, networkInterface.SecurityGroups...)
+		}
+		networkInterfaces = append(networkInterfaces, networkInterface)
+	}
+	group.NetworkInterfaces = networkInterfaces
+	return group, nil
+}
+
 func CreateNewLaunchTemplateVersion(ctx context.Context, ec2Service services.EC2ServiceInterface, launchTemplateID string, group eksv1.NodeGroup) (*eksv1.LaunchTemplate, error) {
 	launchTemplate, err := buildLaunchTemplateData(ctx, ec2Service, group)
 	if err != nil {
@@ -360,12 +688,13 @@ func buildLaunchTemplateData(ctx context.Context, ec2Service services.EC2Service
 		imageID = group.ImageID
 	}
 
-	userdata := group.UserData
-	if aws.ToString(userdata) != "" {
-		if !strings.Contains(*userdata, "Content-Type: multipart/mixed") {
+	// the userdata is encoded into a new string so that the node group spec it came from is left untouched
+	var userdata *string
+	if aws.ToString(group.UserData) != "" {
+		if !strings.Contains(*group.UserData, "Content-Type: multipart/mixed") {
 			return nil, fmt.Errorf("userdata for nodegroup [%s] is not of mime time multipart/mixed", aws.ToString(group.NodegroupName))
 		}
-		*userdata = base64.StdEncoding.EncodeToString([]byte(*userdata))
+		userdata = aws.String(base64.StdEncoding.EncodeToString([]byte(*group.UserData)))
 	}
 
 	deviceName := aws.String(defaultStorageDeviceName)
@@ -386,18 +715,96 @@ func buildLaunchTemplateData(ctx context.Context, ec2Service services.EC2Service
 				DeviceName: deviceName,
 				Ebs: &ec2types.LaunchTemplateEbsBlockDeviceRequest{
 					VolumeSize: group.DiskSize,
+					VolumeType: ec2types.VolumeType(aws.ToString(group.VolumeType)),
+					Iops:       group.Iops,
+					Throughput: group.Throughput,
+					Encrypted:  group.Encrypted,
+					KmsKeyId:   group.KmsKeyID,
 				},
 			},
 		},
 		TagSpecifications: utils.CreateTagSpecs(group.ResourceTags),
 	}
+	for _, mapping := range group.BlockDeviceMappings {
+		if aws.ToString(mapping.DeviceName) == aws.ToString(deviceName) {
+			return nil, fmt.Errorf("block device [%s] of nodegroup [%s] is the root device, set diskSize to change the root volume",
+				aws.ToString(deviceName), aws.ToString(group.NodegroupName))
+		}
+		launchTemplateData.BlockDeviceMappings = append(launchTemplateData.BlockDeviceMappings, ec2types.LaunchTemplateBlockDeviceMappingRequest{
+			DeviceName: mapping.DeviceName,
+			Ebs: &ec2types.LaunchTemplateEbsBlockDeviceRequest{
+				VolumeSize:          mapping.VolumeSize,
+				VolumeType:          ec2types.VolumeType(aws.ToString(mapping.VolumeType)),
+				Iops:                mapping.Iops,
+				Throughput:          mapping.Throughput,
+				Encrypted:           mapping.Encrypted,
+				KmsKeyId:            mapping.KmsKeyID,
+				DeleteOnTermination: mapping.DeleteOnTermination,
+			},
+		})
+	}
 	if !aws.ToBool(group.RequestSpotInstances) {
 		launchTemplateData.InstanceType = ec2types.InstanceType(group.InstanceType)
 	}
+	if metadataOptions := group.MetadataOptions; metadataOptions != nil {
+		launchTemplateData.MetadataOptions = &ec2types.LaunchTemplateInstanceMetadataOptionsRequest{
+			HttpTokens:              ec2types.LaunchTemplateHttpTokensState(aws.ToString(metadataOptions.HTTPTokens)),
+			HttpPutResponseHopLimit: metadataOptions.HTTPPutResponseHopLimit,
+			InstanceMetadataTags:    ec2types.LaunchTemplateInstanceMetadataTagsState(aws.ToString(metadataOptions.InstanceMetadataTags)),
+		}
+	}
+	for _, networkInterface := range group.NetworkInterfaces {
+		launchTemplateData.NetworkInterfaces = append(launchTemplateData.NetworkInterfaces, ec2types.LaunchTemplateInstanceNetworkInterfaceSpecificationRequest{
+			NetworkCardIndex:         networkInterface.NetworkCardIndex,
+			DeviceIndex:              aws.Int32(aws.ToInt32(networkInterface.DeviceIndex)),
+			AssociatePublicIpAddress: networkInterface.AssociatePublicIPAddress,
+			Groups:                   networkInterface.SecurityGroups,
+			InterfaceType:            networkInterface.InterfaceType,
+			DeleteOnTermination:      aws.Bool(true),
+		})
+	}
+	if placement := group.Placement; placement != nil {
+		if aws.ToString(placement.Strategy) != "" {
+			if err := ensurePlacementGroup(ctx, ec2Service, placement); err != nil {
+				return nil, fmt.Errorf("error creating placement group [%s] for nodegroup [%s]: %w",
+					aws.ToString(placement.GroupName), aws.ToString(group.NodegroupName), err)
+			}
+		}
+		launchTemplateData.Placement = &ec2types.LaunchTemplatePlacementRequest{
+			GroupName:       placement.GroupName,
+			PartitionNumber: placement.PartitionNumber,
+			Tenancy:         ec2types.Tenancy(aws.ToString(placement.Tenancy)),
+		}
+	}
 
 	return launchTemplateData, nil
 }
 
+// ensurePlacementGroup creates the placement group with its strategy and partition count unless it exists already, in
+// which case its strategy has to match.
+func ensurePlacementGroup(ctx context.Context, ec2Service services.EC2ServiceInterface, placement *eksv1.Placement) error {
+	strategy := ec2types.PlacementStrategy(aws.ToString(placement.Strategy))
+	output, err := ec2Service.DescribePlacementGroups(ctx, &ec2.DescribePlacementGroupsInput{
+		Filters: []ec2types.Filter{{Name: aws.String("group-name"), Values: []string{aws.ToString(placement.GroupName)}}},
+	})
+	if err != nil {
+		return err
+	}
+	if len(output.PlacementGroups) > 0 {
+		if existing := output.PlacementGroups[0].Strategy; existing != strategy {
+			return fmt.Errorf("placement group exists with strategy [%s] instead of [%s]", existing, strategy)
+		}
+		return nil
+	}
+
+	_, err = ec2Service.CreatePlacementGroup(ctx, &ec2.CreatePlacementGroupInput{
+		GroupName:      placement.GroupName,
+		Strategy:       strategy,
+		PartitionCount: placement.PartitionCount,
+	})
+	return err
+}
+
 func getImageRootDeviceName(ctx context.Context, ec2Service services.EC2ServiceInterface, imageID *string) (*string, error) {
 	if imageID == nil {
 		return nil, fmt.Errorf("imageID is nil")
@@ -413,14 +820,6 @@ func getImageRootDeviceName(ctx context.Context, ec2Service services.EC2ServiceI
 	return describeOutput.Images[0].RootDeviceName, nil
 }
 
-func getTags(tags map[string]string) map[string]string {
-	if len(tags) == 0 {
-		return nil
-	}
-
-	return tags
-}
-
 func getLogging(loggingTypes []string) *ekstypes.Logging {
 	if len(loggingTypes) == 0 {
 		return &ekstypes.Logging{
@@ -455,6 +854,41 @@ func alreadyExistsInCloudFormationError(err error) bool {
 	return errors.As(err, &aee)
 }
 
+// CloudFormationUnavailable returns whether the error of a stack operation means that CloudFormation can't be used,
+// because it isn't supported in the region or the credentials are denied access to it, rather than the stack failing.
+func CloudFormationUnavailable(err error) bool {
+	return CloudFormationUnsupported(err) || CloudFormationDenied(err)
+}
+
+// CloudFormationUnsupported returns whether the error of a stack operation means that CloudFormation isn't supported in
+// the region or isn't enabled for the account.
+func CloudFormationUnsupported(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.ErrorCode() {
+	case "OptInRequired", "UnsupportedOperation":
+		return true
+	}
+	return false
+}
+
+// CloudFormationDenied returns whether the error of a stack operation means that the credentials are denied a
+// CloudFormation action. Errors of the credentials themselves, like invalid or unrecognized keys, and denied actions of
+// other services aren't CloudFormation denials.
+func CloudFormationDenied(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.ErrorCode() {
+	case "AccessDenied", "AccessDeniedException":
+		return strings.Contains(apiErr.ErrorMessage(), "cloudformation:")
+	}
+	return false
+}
+
 func doesNotExist(err error) bool {
 	// There is no better way of doing this because AWS API does not distinguish between a attempt to delete a stack
 	// (or key pair) that does not exist, and, for example, a malformed delete request, so we have to parse the error
@@ -466,17 +900,31 @@ func doesNotExist(err error) bool {
 	return false
 }
 
+// getEC2ServiceEndpoint returns the service principal of EC2 in the partition of the region.
 func getEC2ServiceEndpoint(region string) string {
-	if p, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), region); ok {
-		return fmt.Sprintf("ec2.%s", p.DNSSuffix())
+	return fmt.Sprintf("ec2.%s", services.DNSSuffix(region))
+}
+
+// validateStackOutputs returns a *MissingStackOutputError for the first output the stack created from the template
+// must return but didn't.
+func validateStackOutputs(template string, stack cftypes.Stack) error {
+	for _, key := range TemplateOutputs[template] {
+		if getParameterValueFromOutput(key, stack.Outputs) == "" {
+			return &MissingStackOutputError{
+				StackName: aws.ToString(stack.StackName),
+				StackID:   aws.ToString(stack.StackId),
+				Template:  template,
+				OutputKey: key,
+			}
+		}
 	}
-	return "ec2.amazonaws.com"
+	return nil
 }
 
 func getParameterValueFromOutput(key string, outputs []cftypes.Output) string {
 	for _, output := range outputs {
-		if *output.OutputKey == key {
-			return *output.OutputValue
+		if aws.ToString(output.OutputKey) == key {
+			return aws.ToString(output.OutputValue)
 		}
 	}
 
@@ -490,67 +938,127 @@ type EnableEBSCSIDriverInput struct {
 	CFService    services.CloudFormationServiceInterface
 	Config       *eksv1.EKSClusterConfig
 	AddonVersion string
+	// PodIdentity makes the add-on assume its role through a pod identity association instead of the OIDC provider of
+	// the cluster, which requires the EKS Pod Identity agent add-on
+	PodIdentity bool
+	// TemplateOverrides replace the built-in CloudFormation templates, keyed by template name
+	TemplateOverrides map[string]string
 }
 
 // EnableEBSCSIDriver manages the installation of the EBS CSI driver for EKS, including the
-// creation of the OIDC Provider, the IAM role and the validation and installation of the EKS add-on
-func EnableEBSCSIDriver(ctx context.Context, opts *EnableEBSCSIDriverInput) error {
-	oidcID, err := configureOIDCProvider(ctx, opts.IAMService, opts.EKSService, opts.Config)
+// creation of the OIDC Provider, the IAM role and the validation and installation of the EKS add-on.
+// It returns the ARN of the OIDC provider if it was created for the cluster, also when a later step fails,
+// so that the provider can be deleted along with the cluster. The OIDC provider in the spec is used as is if there is one.
+// No OIDC provider is needed if the add-on uses pod identity.
+func EnableEBSCSIDriver(ctx context.Context, opts *EnableEBSCSIDriverInput) (string, error) {
+	if opts.PodIdentity {
+		roleArn, err := createEBSCSIDriverPodIdentityRole(ctx, opts.CFService, opts.Config, opts.TemplateOverrides)
+		if err != nil {
+			return "", fmt.Errorf("could not create ebs csi driver pod identity role: %w", err)
+		}
+		if _, err := installEBSAddonWithPodIdentity(ctx, opts.EKSService, opts.Config, roleArn, opts.AddonVersion); err != nil {
+			return "", fmt.Errorf("failed to install ebs csi driver addon: %w", err)
+		}
+		return "", nil
+	}
+
+	var oidcID, oidcProviderARN string
+	var err error
+	if aws.ToString(opts.Config.Spec.OIDCProviderARN) != "" {
+		oidcID, err = getSuppliedOIDCProviderID(ctx, opts.EKSService, opts.Config)
+	} else {
+		oidcID, oidcProviderARN, err = configureOIDCProvider(ctx, opts.IAMService, opts.EKSService, opts.Config)
+	}
 	if err != nil {
-		return fmt.Errorf("could not configure oidc provider: %w", err)
+		return "", fmt.Errorf("could not configure oidc provider: %w", err)
 	}
-	roleArn, err := createEBSCSIDriverRole(ctx, opts.CFService, opts.Config, oidcID)
+	roleArn, err := createEBSCSIDriverRole(ctx, opts.CFService, opts.Config, oidcID, opts.TemplateOverrides)
 	if err != nil {
-		return fmt.Errorf("could not create ebs csi driver role: %w", err)
+		return oidcProviderARN, fmt.Errorf("could not create ebs csi driver role: %w", err)
 	}
 	if _, err := installEBSAddon(ctx, opts.EKSService, opts.Config, roleArn, opts.AddonVersion); err != nil {
-		return fmt.Errorf("failed to install ebs csi driver addon: %w", err)
+		return oidcProviderARN, fmt.Errorf("failed to install ebs csi driver addon: %w", err)
 	}
 
-	return nil
+	return oidcProviderARN, nil
 }
 
-func configureOIDCProvider(ctx context.Context, iamService services.IAMServiceInterface, eksService services.EKSServiceInterface, config *eksv1.EKSClusterConfig) (string, error) {
+// configureOIDCProvider returns the ID of the IAM OIDC provider for the issuer of the cluster, creating the provider
+// if there is none. The ARN of the provider is returned as well if it was created.
+func configureOIDCProvider(ctx context.Context, iamService services.IAMServiceInterface, eksService services.EKSServiceInterface, config *eksv1.EKSClusterConfig) (string, string, error) {
 	output, err := iamService.ListOIDCProviders(ctx, &iam.ListOpenIDConnectProvidersInput{})
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 	clusterOutput, err := eksService.DescribeCluster(ctx, &eks.DescribeClusterInput{
 		Name: aws.String(config.Spec.DisplayName),
 	})
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 	if clusterOutput == nil {
-		return "", fmt.Errorf("could not find cluster [%s (id: %s)]", config.Spec.DisplayName, config.Name)
+		return "", "", fmt.Errorf("could not find cluster [%s (id: %s)]", config.Spec.DisplayName, config.Name)
 	}
 	id := path.Base(*clusterOutput.Cluster.Identity.Oidc.Issuer)
 
 	for _, prov := range output.OpenIDConnectProviderList {
 		if strings.Contains(*prov.Arn, id) {
-			return "", nil
+			return id, "", nil
 		}
 	}
 
-	thumbprint, err := getIssuerThumbprint(*clusterOutput.Cluster.Identity.Oidc.Issuer)
-	if err != nil {
-		return "", err
+	thumbprints := config.Spec.OIDCThumbprints
+	if len(thumbprints) == 0 {
+		transport, err := services.NewOIDCIssuerTransport(config.Spec.Region)
+		if err != nil {
+			return "", "", err
+		}
+		thumbprint, err := getIssuerThumbprint(ctx, *clusterOutput.Cluster.Identity.Oidc.Issuer, transport)
+		if err != nil {
+			return "", "", fmt.Errorf("error getting thumbprint of oidc issuer, it can be set in oidcThumbprints instead: %w", err)
+		}
+		thumbprints = []string{thumbprint}
 	}
 	input := &iam.CreateOpenIDConnectProviderInput{
 		ClientIDList:   []string{string(defaultAudienceOpenIDConnect)},
-		ThumbprintList: []string{thumbprint},
+		ThumbprintList: thumbprints,
 		Url:            clusterOutput.Cluster.Identity.Oidc.Issuer,
 		Tags:           []iamtypes.Tag{},
 	}
 	newOIDC, err := iamService.CreateOIDCProvider(ctx, input)
+	if err != nil {
+		return "", "", err
+	}
+
+	return path.Base(*newOIDC.OpenIDConnectProviderArn), aws.ToString(newOIDC.OpenIDConnectProviderArn), nil
+}
+
+// getSuppliedOIDCProviderID returns the ID of the OIDC provider in the spec after checking that it is the provider for
+// the issuer of the cluster, since a role trusting the provider of another cluster would never be assumable.
+func getSuppliedOIDCProviderID(ctx context.Context, eksService services.EKSServiceInterface, config *eksv1.EKSClusterConfig) (string, error) {
+	clusterOutput, err := eksService.DescribeCluster(ctx, &eks.DescribeClusterInput{
+		Name: aws.String(config.Spec.DisplayName),
+	})
 	if err != nil {
 		return "", err
 	}
+	if clusterOutput.Cluster == nil || clusterOutput.Cluster.Identity == nil || clusterOutput.Cluster.Identity.Oidc == nil {
+		return "", fmt.Errorf("no oidc issuer was returned for cluster [%s (id: %s)]", config.Spec.DisplayName, config.Name)
+	}
 
-	return path.Base(*newOIDC.OpenIDConnectProviderArn), nil
+	providerARN := aws.ToString(config.Spec.OIDCProviderARN)
+	issuer := strings.TrimPrefix(aws.ToString(clusterOutput.Cluster.Identity.Oidc.Issuer), "https://")
+	if !strings.HasSuffix(providerARN, ":oidc-provider/"+issuer) {
+		return "", fmt.Errorf("oidc provider [%s] is not the provider for issuer [%s] of cluster [%s (id: %s)]",
+			providerARN, issuer, config.Spec.DisplayName, config.Name)
+	}
+
+	return path.Base(providerARN), nil
 }
 
-func getIssuerThumbprint(issuer string) (string, error) {
+// getIssuerThumbprint returns the sha1 thumbprint of the last certificate in the chain the issuer presents, after
+// verifying the chain against the root certificate authorities of the transport.
+func getIssuerThumbprint(ctx context.Context, issuer string, transport *http.Transport) (string, error) {
 	issuerURL, err := url.Parse(issuer)
 	if err != nil {
 		return "", err
@@ -559,23 +1067,19 @@ func getIssuerThumbprint(issuer string) (string, error) {
 		issuerURL.Host += ":443"
 	}
 
-	client := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-				MinVersion:         tls.VersionTLS12,
-			},
-			Proxy: http.ProxyFromEnvironment,
-		},
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, issuerURL.String(), nil)
+	if err != nil {
+		return "", err
 	}
-	resp, err := client.Get(issuerURL.String())
+	client := &http.Client{Transport: transport, Timeout: issuerRequestTimeout}
+	resp, err := client.Do(req)
 	if err != nil {
 		return "", err
 	}
 	defer resp.Body.Close()
 
 	if resp.TLS == nil || len(resp.TLS.PeerCertificates) == 0 {
-		return "", err
+		return "", fmt.Errorf("oidc issuer [%s] didn't present any certificates", issuer)
 	}
 
 	root := resp.TLS.PeerCertificates[len(resp.TLS.PeerCertificates)-1]
@@ -583,23 +1087,17 @@ func getIssuerThumbprint(issuer string) (string, error) {
 	return fmt.Sprintf("%x", sha1.Sum(root.Raw)), nil
 }
 
-func createEBSCSIDriverRole(ctx context.Context, cfService services.CloudFormationServiceInterface, config *eksv1.EKSClusterConfig, oidcID string) (string, error) {
-	templateData := struct {
-		Region     string
-		ProviderID string
-	}{
-		Region:     config.Spec.Region,
-		ProviderID: oidcID,
+func createEBSCSIDriverRole(ctx context.Context, cfService services.CloudFormationServiceInterface, config *eksv1.EKSClusterConfig, oidcID string, templateOverrides map[string]string) (string, error) {
+	params := NewTemplateParameters(config)
+	params.ProviderID = oidcID
+	templateBody := templates.EBSCSIDriverTemplate
+	if override, ok := templateOverrides[TemplateEBSCSIDriver]; ok {
+		templateBody = override
 	}
-	tmpl, err := template.New("ebsrole").Parse(templates.EBSCSIDriverTemplate)
+	finalTemplate, err := executeTemplate(TemplateEBSCSIDriver, templateBody, params)
 	if err != nil {
 		return "", err
 	}
-	buf := &bytes.Buffer{}
-	if execErr := tmpl.Execute(buf, templateData); execErr != nil {
-		return "", err
-	}
-	finalTemplate := buf.String()
 
 	output, err := CreateStack(ctx, &CreateStackOptions{
 		CloudFormationService: cfService,
@@ -608,26 +1106,65 @@ func createEBSCSIDriverRole(ctx context.Context, cfService services.CloudFormati
 		TemplateBody:          finalTemplate,
 		Capabilities:          []cftypes.Capability{cftypes.CapabilityCapabilityIam},
 		Parameters:            []cftypes.Parameter{},
+		Tags:                  OwnershipTags(config),
+		Template:              TemplateEBSCSIDriver,
 	})
 	if err != nil {
 		return "", err
 	}
-	createdRoleArn := getParameterValueFromOutput("EBSCSIDriverRole", output.Stacks[0].Outputs)
+	return getParameterValueFromOutput("EBSCSIDriverRole", output.Stacks[0].Outputs), nil
+}
 
-	return createdRoleArn, nil
+// createEBSCSIDriverPodIdentityRole creates the stack of the role the EBS CSI driver assumes through pod identity and
+// returns the ARN of the role. The role can be assumed by any pod identity association that refers to it, so it
+// doesn't depend on the OIDC provider of the cluster.
+func createEBSCSIDriverPodIdentityRole(ctx context.Context, cfService services.CloudFormationServiceInterface, config *eksv1.EKSClusterConfig, templateOverrides map[string]string) (string, error) {
+	templateBody, err := RenderTemplate(templateOverrides, TemplateEBSCSIDriverPodIdentity, templates.EBSCSIDriverPodIdentityTemplate, NewTemplateParameters(config))
+	if err != nil {
+		return "", err
+	}
+	output, err := CreateStack(ctx, &CreateStackOptions{
+		CloudFormationService: cfService,
+		StackName:             fmt.Sprintf("%s-ebs-csi-driver-pod-identity-role", config.Spec.DisplayName),
+		DisplayName:           config.Spec.DisplayName,
+		TemplateBody:          templateBody,
+		Capabilities:          []cftypes.Capability{cftypes.CapabilityCapabilityIam},
+		Parameters:            []cftypes.Parameter{},
+		Tags:                  OwnershipTags(config),
+		Template:              TemplateEBSCSIDriverPodIdentity,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return getParameterValueFromOutput("EBSCSIDriverPodIdentityRole", output.Stacks[0].Outputs), nil
 }
 
 func installEBSAddon(ctx context.Context, eksService services.EKSServiceInterface, config *eksv1.EKSClusterConfig, roleArn, version string) (string, error) {
-	input := eks.CreateAddonInput{
-		AddonName:             aws.String(ebsCSIAddonName),
-		ClusterName:           aws.String(config.Spec.DisplayName),
+	return createEBSAddon(ctx, eksService, config, version, &eks.CreateAddonInput{
 		ServiceAccountRoleArn: aws.String(roleArn),
-	}
+	})
+}
+
+func installEBSAddonWithPodIdentity(ctx context.Context, eksService services.EKSServiceInterface, config *eksv1.EKSClusterConfig, roleArn, version string) (string, error) {
+	return createEBSAddon(ctx, eksService, config, version, &eks.CreateAddonInput{
+		PodIdentityAssociations: []ekstypes.AddonPodIdentityAssociations{
+			{
+				RoleArn:        aws.String(roleArn),
+				ServiceAccount: aws.String(ebsCSIServiceAccount),
+			},
+		},
+	})
+}
+
+func createEBSAddon(ctx context.Context, eksService services.EKSServiceInterface, config *eksv1.EKSClusterConfig, version string, input *eks.CreateAddonInput) (string, error) {
+	input.AddonName = aws.String(ebsCSIAddonName)
+	input.ClusterName = aws.String(config.Spec.DisplayName)
 	if version != "latest" {
 		input.AddonVersion = aws.String(version)
 	}
 
-	addonOutput, err := eksService.CreateAddon(ctx, &input)
+	addonOutput, err := eksService.CreateAddon(ctx, input)
 	if err != nil {
 		return "", err
 	}
@@ -637,3 +1174,29 @@ func installEBSAddon(ctx context.Context, eksService services.EKSServiceInterfac
 
 	return *addonOutput.Addon.AddonArn, nil
 }
+
+// InstallContainerInsightsAddon installs the latest version of the CloudWatch observability add-on, which sends the
+// metrics and logs of Container Insights to CloudWatch with the permissions of the node instance role.
+func InstallContainerInsightsAddon(ctx context.Context, eksService services.EKSServiceInterface, config *eksv1.EKSClusterConfig) error {
+	if _, err := eksService.CreateAddon(ctx, &eks.CreateAddonInput{
+		AddonName:   aws.String(containerInsightsAddonName),
+		ClusterName: aws.String(config.Spec.DisplayName),
+	}); err != nil {
+		return fmt.Errorf("could not create addon [%s] for cluster [%s (id: %s)]: %w", containerInsightsAddonName, config.Spec.DisplayName, config.Name, err)
+	}
+
+	return nil
+}
+
+// InstallPodIdentityAgentAddon installs the latest version of the EKS Pod Identity agent add-on, which is needed by
+// pods to assume the roles of their pod identity associations.
+func InstallPodIdentityAgentAddon(ctx context.Context, eksService services.EKSServiceInterface, config *eksv1.EKSClusterConfig) error {
+	if _, err := eksService.CreateAddon(ctx, &eks.CreateAddonInput{
+		AddonName:   aws.String(podIdentityAgentAddonName),
+		ClusterName: aws.String(config.Spec.DisplayName),
+	}); err != nil {
+		return fmt.Errorf("could not create addon [%s] for cluster [%s (id: %s)]: %w", podIdentityAgentAddonName, config.Spec.DisplayName, config.Name, err)
+	}
+
+	return nil
+}
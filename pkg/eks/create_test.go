@@ -1,8 +1,13 @@
 package eks
 
 import (
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
@@ -13,6 +18,7 @@ import (
 	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
 	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/smithy-go"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -92,7 +98,7 @@ var _ = Describe("newClusterInput", func() {
 		Expect(clusterInput.ResourcesVpcConfig.EndpointPrivateAccess).To(Equal(config.Spec.PrivateAccess))
 		Expect(clusterInput.ResourcesVpcConfig.EndpointPublicAccess).To(Equal(config.Spec.PublicAccess))
 		Expect(clusterInput.ResourcesVpcConfig.PublicAccessCidrs).To(Equal(config.Spec.PublicAccessSources))
-		Expect(clusterInput.Tags).To(Equal(config.Spec.Tags))
+		Expect(clusterInput.Tags).To(Equal(map[string]string{"test": "test", ManagedTagKey: "true", ClusterNameTagKey: "test"}))
 		Expect(clusterInput.Logging.ClusterLogging).To(HaveLen(1))
 		Expect(clusterInput.Logging.ClusterLogging[0].Enabled).To(Equal(aws.Bool(true)))
 		Expect(clusterInput.Logging.ClusterLogging[0].Types).To(Equal(utils.ConvertToLogTypes(config.Spec.LoggingTypes)))
@@ -111,12 +117,12 @@ var _ = Describe("newClusterInput", func() {
 		Expect(clusterInput.ResourcesVpcConfig.PublicAccessCidrs).To(Equal([]string{"0.0.0.0/0"}))
 	})
 
-	It("should successfully create a cluster with no tags set", func() {
+	It("should successfully create a cluster with only the ownership tags if no tags are set", func() {
 		config.Spec.Tags = map[string]string{}
 		clusterInput := newClusterInput(config, roleARN)
 		Expect(clusterInput).ToNot(BeNil())
 
-		Expect(clusterInput.Tags).To(BeNil())
+		Expect(clusterInput.Tags).To(Equal(map[string]string{ManagedTagKey: "true", ClusterNameTagKey: "test"}))
 	})
 
 	It("should successfully create a cluster with no logging types set", func() {
@@ -136,6 +142,47 @@ var _ = Describe("newClusterInput", func() {
 
 		Expect(clusterInput.EncryptionConfig).To(BeNil())
 	})
+
+	It("should successfully create a cluster input with outpost config", func() {
+		config.Spec.OutpostConfig = &eksv1.OutpostConfig{
+			OutpostARNs:              []string{"arn:aws:outposts:us-west-2:123456789012:outpost/op-test"},
+			ControlPlaneInstanceType: "m5.large",
+		}
+		clusterInput := newClusterInput(config, roleARN)
+		Expect(clusterInput).ToNot(BeNil())
+
+		Expect(clusterInput.OutpostConfig).ToNot(BeNil())
+		Expect(clusterInput.OutpostConfig.OutpostArns).To(Equal(config.Spec.OutpostConfig.OutpostARNs))
+		Expect(clusterInput.OutpostConfig.ControlPlaneInstanceType).To(Equal(aws.String("m5.large")))
+		Expect(clusterInput.OutpostConfig.ControlPlanePlacement).To(BeNil())
+	})
+
+	It("should successfully create a cluster input with a control plane placement group", func() {
+		config.Spec.OutpostConfig = &eksv1.OutpostConfig{
+			OutpostARNs:                    []string{"arn:aws:outposts:us-west-2:123456789012:outpost/op-test"},
+			ControlPlaneInstanceType:       "m5.large",
+			ControlPlanePlacementGroupName: "control-plane",
+		}
+		clusterInput := newClusterInput(config, roleARN)
+
+		Expect(clusterInput.OutpostConfig.ControlPlanePlacement).To(Equal(&ekstypes.ControlPlanePlacementRequest{GroupName: aws.String("control-plane")}))
+	})
+
+	It("should successfully create a cluster input without outpost config", func() {
+		clusterInput := newClusterInput(config, roleARN)
+		Expect(clusterInput).ToNot(BeNil())
+
+		Expect(clusterInput.OutpostConfig).To(BeNil())
+	})
+
+	It("should enable access entries for cluster admin principals", func() {
+		Expect(newClusterInput(config, roleARN).AccessConfig).To(BeNil())
+
+		config.Spec.ClusterAdminPrincipals = []string{"arn:aws:iam::123456789012:role/break-glass"}
+		clusterInput := newClusterInput(config, roleARN)
+		Expect(clusterInput.AccessConfig).ToNot(BeNil())
+		Expect(clusterInput.AccessConfig.AuthenticationMode).To(Equal(ekstypes.AuthenticationModeApiAndConfigMap))
+	})
 })
 
 var _ = Describe("CreateStack", func() {
@@ -195,6 +242,87 @@ var _ = Describe("CreateStack", func() {
 		Expect(describeStacksOutput).ToNot(BeNil())
 	})
 
+	It("should set the given tags along with the displayName tag", func() {
+		stackCreationOptions.Tags = map[string]string{ManagedTagKey: "true", ClusterNameTagKey: "test"}
+		cloudFormationServiceMock.EXPECT().CreateStack(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, input *cloudformation.CreateStackInput) (*cloudformation.CreateStackOutput, error) {
+				Expect(input.Tags).To(Equal([]cftypes.Tag{
+					{Key: aws.String("displayName"), Value: aws.String("test")},
+					{Key: aws.String(ClusterNameTagKey), Value: aws.String("test")},
+					{Key: aws.String(ManagedTagKey), Value: aws.String("true")},
+				}))
+				return nil, nil
+			})
+		cloudFormationServiceMock.EXPECT().DescribeStacks(ctx, gomock.Any()).Return(
+			&cloudformation.DescribeStacksOutput{
+				Stacks: []cftypes.Stack{{StackStatus: createCompleteStatus}},
+			}, nil)
+
+		_, err := CreateStack(ctx, stackCreationOptions)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should return an in progress error if the stack is still being created", func() {
+		cloudFormationServiceMock.EXPECT().CreateStack(ctx, gomock.Any()).Return(nil, nil)
+		cloudFormationServiceMock.EXPECT().DescribeStacks(ctx, gomock.Any()).Return(
+			&cloudformation.DescribeStacksOutput{
+				Stacks: []cftypes.Stack{
+					{
+						StackId:     aws.String("test-id"),
+						StackStatus: createInProgressStatus,
+					},
+				},
+			}, nil)
+
+		describeStacksOutput, err := CreateStack(ctx, stackCreationOptions)
+		Expect(describeStacksOutput).To(BeNil())
+		var inProgress *StackCreationInProgressError
+		Expect(errors.As(err, &inProgress)).To(BeTrue())
+		Expect(inProgress.StackName).To(Equal(stackCreationOptions.StackName))
+		Expect(inProgress.StackID).To(Equal("test-id"))
+	})
+
+	It("should return a missing output error if the stack didn't return an output of its template", func() {
+		stackCreationOptions.Template = TemplateVPC
+		cloudFormationServiceMock.EXPECT().CreateStack(ctx, gomock.Any()).Return(nil, nil)
+		cloudFormationServiceMock.EXPECT().DescribeStacks(ctx, gomock.Any()).Return(
+			&cloudformation.DescribeStacksOutput{
+				Stacks: []cftypes.Stack{
+					{
+						StackName:   aws.String("test"),
+						StackId:     aws.String("test-id"),
+						StackStatus: createCompleteStatus,
+						Outputs:     []cftypes.Output{{OutputKey: aws.String("VpcId"), OutputValue: aws.String("vpc-1")}},
+					},
+				},
+			}, nil)
+
+		describeStacksOutput, err := CreateStack(ctx, stackCreationOptions)
+		Expect(describeStacksOutput).To(BeNil())
+		var missing *MissingStackOutputError
+		Expect(errors.As(err, &missing)).To(BeTrue())
+		Expect(*missing).To(Equal(MissingStackOutputError{StackName: "test", StackID: "test-id", Template: TemplateVPC, OutputKey: "SubnetIds"}))
+		Expect(err.Error()).To(ContainSubstring("test-id"))
+	})
+
+	It("should return the stack if it returned all outputs of its template", func() {
+		stackCreationOptions.Template = TemplateServiceRole
+		cloudFormationServiceMock.EXPECT().CreateStack(ctx, gomock.Any()).Return(nil, nil)
+		cloudFormationServiceMock.EXPECT().DescribeStacks(ctx, gomock.Any()).Return(
+			&cloudformation.DescribeStacksOutput{
+				Stacks: []cftypes.Stack{
+					{
+						StackStatus: createCompleteStatus,
+						Outputs:     []cftypes.Output{{OutputKey: aws.String("RoleArn"), OutputValue: aws.String("arn:aws:iam::123456789012:role/test")}},
+					},
+				},
+			}, nil)
+
+		describeStacksOutput, err := CreateStack(ctx, stackCreationOptions)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(describeStacksOutput.Stacks).To(HaveLen(1))
+	})
+
 	It("should fail to create a stack if CreateStack returns error", func() {
 		cloudFormationServiceMock.EXPECT().CreateStack(ctx, gomock.Any()).Return(nil, errors.New("error"))
 
@@ -290,6 +418,115 @@ var _ = Describe("CreateStack", func() {
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring(rollbackInProgressStatus))
 	})
+
+	It("should aggregate the failure reasons of all stack resources", func() {
+		cloudFormationServiceMock.EXPECT().CreateStack(ctx, gomock.Any()).Return(nil, nil)
+		cloudFormationServiceMock.EXPECT().DescribeStacks(ctx, gomock.Any()).Return(
+			&cloudformation.DescribeStacksOutput{
+				Stacks: []cftypes.Stack{
+					{
+						StackStatus: rollbackInProgressStatus,
+					},
+				},
+			}, nil)
+		cloudFormationServiceMock.EXPECT().DescribeStackEvents(ctx,
+			&cloudformation.DescribeStackEventsInput{
+				StackName: &stackCreationOptions.StackName,
+			},
+		).Return(
+			&cloudformation.DescribeStackEventsOutput{
+				StackEvents: []cftypes.StackEvent{
+					{
+						ResourceStatus:       rollbackInProgressStatus,
+						ResourceStatusReason: aws.String("The following resource(s) failed to create: [NodeInstanceRole, InstanceProfile]"),
+						LogicalResourceId:    aws.String("test"),
+					},
+					{
+						ResourceStatus:       createFailedStatus,
+						ResourceStatusReason: aws.String("Policy arn:aws:iam::aws:policy/Missing does not exist"),
+						LogicalResourceId:    aws.String("NodeInstanceRole"),
+						ResourceType:         aws.String("AWS::IAM::Role"),
+					},
+					{
+						ResourceStatus:       createFailedStatus,
+						ResourceStatusReason: aws.String("Resource creation cancelled"),
+						LogicalResourceId:    aws.String("OtherRole"),
+					},
+				},
+				NextToken: aws.String("next"),
+			}, nil)
+		cloudFormationServiceMock.EXPECT().DescribeStackEvents(ctx,
+			&cloudformation.DescribeStackEventsInput{
+				StackName: &stackCreationOptions.StackName,
+				NextToken: aws.String("next"),
+			},
+		).Return(
+			&cloudformation.DescribeStackEventsOutput{
+				StackEvents: []cftypes.StackEvent{
+					{
+						ResourceStatus:       createFailedStatus,
+						ResourceStatusReason: aws.String("test-profile already exists"),
+						LogicalResourceId:    aws.String("InstanceProfile"),
+						ResourceType:         aws.String("AWS::IAM::InstanceProfile"),
+					},
+				},
+			}, nil)
+
+		_, err := CreateStack(ctx, stackCreationOptions)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal(fmt.Sprintf("stack [%s] failed to create:\n"+
+			"- InstanceProfile (AWS::IAM::InstanceProfile): test-profile already exists\n"+
+			"- NodeInstanceRole (AWS::IAM::Role): Policy arn:aws:iam::aws:policy/Missing does not exist", stackCreationOptions.StackName)))
+	})
+})
+
+var _ = Describe("TagSubnets", func() {
+	var (
+		mockController *gomock.Controller
+		ec2ServiceMock *mock_services.MockEC2ServiceInterface
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		ec2ServiceMock = mock_services.NewMockEC2ServiceInterface(mockController)
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should tag subnets", func() {
+		ec2ServiceMock.EXPECT().CreateTags(ctx, &ec2.CreateTagsInput{
+			Resources: []string{"subnet-a", "subnet-b"},
+			Tags:      []ec2types.Tag{{Key: aws.String("kubernetes.io/cluster/test"), Value: aws.String("shared")}},
+		}).Return(&ec2.CreateTagsOutput{}, nil)
+
+		err := TagSubnets(ctx, &TagSubnetsOptions{
+			EC2Service: ec2ServiceMock,
+			SubnetIDs:  []string{"subnet-a", "subnet-b"},
+			Tags:       map[string]string{"kubernetes.io/cluster/test": "shared"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should not tag if there are no subnets", func() {
+		err := TagSubnets(ctx, &TagSubnetsOptions{
+			EC2Service: ec2ServiceMock,
+			Tags:       map[string]string{"kubernetes.io/cluster/test": "shared"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should fail to tag subnets", func() {
+		ec2ServiceMock.EXPECT().CreateTags(ctx, gomock.Any()).Return(nil, errors.New("error"))
+
+		err := TagSubnets(ctx, &TagSubnetsOptions{
+			EC2Service: ec2ServiceMock,
+			SubnetIDs:  []string{"subnet-a"},
+			Tags:       map[string]string{"kubernetes.io/cluster/test": "shared"},
+		})
+		Expect(err).To(HaveOccurred())
+	})
 })
 
 var _ = Describe("createLaunchTemplate", func() {
@@ -328,12 +565,21 @@ var _ = Describe("createLaunchTemplate", func() {
 								Key:   aws.String(launchTemplateTagKey),
 								Value: aws.String(launchTemplateTagValue),
 							},
+							{
+								Key:   aws.String(ClusterNameTagKey),
+								Value: aws.String(clusterDisplayName),
+							},
+							{
+								Key:   aws.String(ManagedTagKey),
+								Value: aws.String("true"),
+							},
 						},
 					},
 				},
 			},
 		).Return(expectedOutput, nil)
-		launchTemplate, err := createLaunchTemplate(ctx, ec2ServiceMock, clusterDisplayName)
+		launchTemplate, err := createLaunchTemplate(ctx, ec2ServiceMock, clusterDisplayName,
+			map[string]string{ManagedTagKey: "true", ClusterNameTagKey: clusterDisplayName})
 		Expect(err).ToNot(HaveOccurred())
 		Expect(launchTemplate).ToNot(BeNil())
 
@@ -344,7 +590,7 @@ var _ = Describe("createLaunchTemplate", func() {
 
 	It("should fail to create a launch template", func() {
 		ec2ServiceMock.EXPECT().CreateLaunchTemplate(ctx, gomock.Any()).Return(nil, errors.New("error"))
-		_, err := createLaunchTemplate(ctx, ec2ServiceMock, clusterDisplayName)
+		_, err := createLaunchTemplate(ctx, ec2ServiceMock, clusterDisplayName, nil)
 		Expect(err).To(HaveOccurred())
 	})
 })
@@ -536,7 +782,8 @@ var _ = Describe("buildLaunchTemplateData", func() {
 		Expect(launchTemplateData).ToNot(BeNil())
 		Expect(launchTemplateData.ImageId).To(Equal(group.ImageID))
 		Expect(launchTemplateData.KeyName).To(Equal(group.Ec2SshKey))
-		Expect(launchTemplateData.UserData).To(Equal(group.UserData))
+		Expect(launchTemplateData.UserData).To(Equal(aws.String(base64.StdEncoding.EncodeToString([]byte("Content-Type: multipart/mixed ...")))))
+		Expect(group.UserData).To(Equal(aws.String("Content-Type: multipart/mixed ...")))
 		Expect(launchTemplateData.BlockDeviceMappings).To(HaveLen(1))
 		Expect(launchTemplateData.BlockDeviceMappings[0].DeviceName).To(Equal(&exptectedRootDeviceName))
 		Expect(launchTemplateData.BlockDeviceMappings[0].Ebs.VolumeSize).To(Equal(group.DiskSize))
@@ -544,6 +791,141 @@ var _ = Describe("buildLaunchTemplateData", func() {
 		Expect(string(launchTemplateData.InstanceType)).To(Equal(group.InstanceType))
 	})
 
+	It("should build a launch template data with instance metadata options", func() {
+		group.ImageID = nil
+		group.MetadataOptions = &eksv1.MetadataOptions{
+			HTTPTokens:              aws.String("required"),
+			HTTPPutResponseHopLimit: aws.Int32(2),
+			InstanceMetadataTags:    aws.String("enabled"),
+		}
+
+		launchTemplateData, err := buildLaunchTemplateData(ctx, ec2ServiceMock, *group)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(launchTemplateData.MetadataOptions).To(Equal(&ec2types.LaunchTemplateInstanceMetadataOptionsRequest{
+			HttpTokens:              ec2types.LaunchTemplateHttpTokensStateRequired,
+			HttpPutResponseHopLimit: aws.Int32(2),
+			InstanceMetadataTags:    ec2types.LaunchTemplateInstanceMetadataTagsStateEnabled,
+		}))
+	})
+
+	It("should build a launch template data with a placement", func() {
+		group.ImageID = nil
+		group.Placement = &eksv1.Placement{
+			GroupName:       aws.String("hpc"),
+			Strategy:        aws.String("partition"),
+			PartitionCount:  aws.Int32(3),
+			PartitionNumber: aws.Int32(2),
+			Tenancy:         aws.String("dedicated"),
+		}
+		ec2ServiceMock.EXPECT().DescribePlacementGroups(ctx, gomock.Any()).Return(&ec2.DescribePlacementGroupsOutput{}, nil)
+		ec2ServiceMock.EXPECT().CreatePlacementGroup(ctx, &ec2.CreatePlacementGroupInput{
+			GroupName:      aws.String("hpc"),
+			Strategy:       ec2types.PlacementStrategyPartition,
+			PartitionCount: aws.Int32(3),
+		}).Return(&ec2.CreatePlacementGroupOutput{}, nil)
+
+		launchTemplateData, err := buildLaunchTemplateData(ctx, ec2ServiceMock, *group)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(launchTemplateData.Placement).To(Equal(&ec2types.LaunchTemplatePlacementRequest{
+			GroupName:       aws.String("hpc"),
+			PartitionNumber: aws.Int32(2),
+			Tenancy:         ec2types.TenancyDedicated,
+		}))
+	})
+
+	It("should fail to build a launch template data if the placement group has another strategy", func() {
+		group.ImageID = nil
+		group.Placement = &eksv1.Placement{GroupName: aws.String("hpc"), Strategy: aws.String("cluster")}
+		ec2ServiceMock.EXPECT().DescribePlacementGroups(ctx, gomock.Any()).Return(&ec2.DescribePlacementGroupsOutput{
+			PlacementGroups: []ec2types.PlacementGroup{{GroupName: aws.String("hpc"), Strategy: ec2types.PlacementStrategySpread}},
+		}, nil)
+
+		_, err := buildLaunchTemplateData(ctx, ec2ServiceMock, *group)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should build a launch template data with additional block device mappings", func() {
+		group.ImageID = nil
+		group.BlockDeviceMappings = []eksv1.BlockDeviceMapping{{
+			DeviceName:          aws.String("/dev/xvdb"),
+			VolumeSize:          aws.Int32(100),
+			VolumeType:          aws.String("gp3"),
+			Throughput:          aws.Int32(500),
+			DeleteOnTermination: aws.Bool(true),
+		}}
+
+		launchTemplateData, err := buildLaunchTemplateData(ctx, ec2ServiceMock, *group)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(launchTemplateData.BlockDeviceMappings).To(HaveLen(2))
+		Expect(launchTemplateData.BlockDeviceMappings[0].DeviceName).To(Equal(aws.String(defaultStorageDeviceName)))
+		Expect(launchTemplateData.BlockDeviceMappings[1]).To(Equal(ec2types.LaunchTemplateBlockDeviceMappingRequest{
+			DeviceName: aws.String("/dev/xvdb"),
+			Ebs: &ec2types.LaunchTemplateEbsBlockDeviceRequest{
+				VolumeSize:          aws.Int32(100),
+				VolumeType:          ec2types.VolumeTypeGp3,
+				Throughput:          aws.Int32(500),
+				DeleteOnTermination: aws.Bool(true),
+			},
+		}))
+	})
+
+	It("should fail to build a launch template data if a block device mapping replaces the root device", func() {
+		group.ImageID = nil
+		group.BlockDeviceMappings = []eksv1.BlockDeviceMapping{{DeviceName: aws.String(defaultStorageDeviceName), VolumeSize: aws.Int32(100)}}
+
+		_, err := buildLaunchTemplateData(ctx, ec2ServiceMock, *group)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should build a launch template data with network interfaces", func() {
+		group.ImageID = nil
+		group.NetworkInterfaces = []eksv1.NetworkInterface{{
+			AssociatePublicIPAddress: aws.Bool(false),
+			SecurityGroups:           []string{"sg-nodes"},
+		}}
+		config := &eksv1.EKSClusterConfig{Status: eksv1.EKSClusterConfigStatus{ClusterSecurityGroup: "sg-cluster"}}
+
+		groupWithClusterSecurityGroup, err := addClusterSecurityGroup(config, *group)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(group.NetworkInterfaces[0].SecurityGroups).To(Equal([]string{"sg-nodes"}))
+		launchTemplateData, err := buildLaunchTemplateData(ctx, ec2ServiceMock, groupWithClusterSecurityGroup)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(launchTemplateData.NetworkInterfaces).To(Equal([]ec2types.LaunchTemplateInstanceNetworkInterfaceSpecificationRequest{{
+			DeviceIndex:              aws.Int32(0),
+			AssociatePublicIpAddress: aws.Bool(false),
+			Groups:                   []string{"sg-cluster", "sg-nodes"},
+			DeleteOnTermination:      aws.Bool(true),
+		}}))
+	})
+
+	It("should fail to add the cluster security group before it is known", func() {
+		group.NetworkInterfaces = []eksv1.NetworkInterface{{AssociatePublicIPAddress: aws.Bool(false)}}
+
+		_, err := addClusterSecurityGroup(&eksv1.EKSClusterConfig{}, *group)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should build a launch template data with volume options", func() {
+		group.ImageID = nil
+		group.VolumeType = aws.String("gp3")
+		group.Iops = aws.Int32(4000)
+		group.Throughput = aws.Int32(250)
+		group.Encrypted = aws.Bool(true)
+		group.KmsKeyID = aws.String("test-kms-key")
+
+		launchTemplateData, err := buildLaunchTemplateData(ctx, ec2ServiceMock, *group)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(launchTemplateData.BlockDeviceMappings).To(HaveLen(1))
+		Expect(launchTemplateData.BlockDeviceMappings[0].Ebs).To(Equal(&ec2types.LaunchTemplateEbsBlockDeviceRequest{
+			VolumeSize: group.DiskSize,
+			VolumeType: ec2types.VolumeTypeGp3,
+			Iops:       aws.Int32(4000),
+			Throughput: aws.Int32(250),
+			Encrypted:  aws.Bool(true),
+			KmsKeyId:   aws.String("test-kms-key"),
+		}))
+	})
+
 	It("should fail to build a launch template data if userdata is invalid", func() {
 		group.UserData = aws.String("invalid-user-data")
 		_, err := buildLaunchTemplateData(ctx, ec2ServiceMock, *group)
@@ -610,6 +992,56 @@ var _ = Describe("createNewLaunchTemplateVersion", func() {
 		_, err := CreateNewLaunchTemplateVersion(ctx, ec2ServiceMock, templateID, *group)
 		Expect(err).To(HaveOccurred())
 	})
+
+	It("should delete unused versions and retry when the version limit is reached", func() {
+		config := &eksv1.EKSClusterConfig{Status: eksv1.EKSClusterConfigStatus{
+			ManagedLaunchTemplateID:       templateID,
+			ManagedLaunchTemplateVersions: map[string]string{"ng1": "3", "ng2": "5"},
+		}}
+		limitErr := &smithy.GenericAPIError{Code: "VersionLimitExceeded"}
+		output := &ec2.CreateLaunchTemplateVersionOutput{
+			LaunchTemplateVersion: &ec2types.LaunchTemplateVersion{LaunchTemplateId: aws.String(templateID), VersionNumber: aws.Int64(7)},
+		}
+		gomock.InOrder(
+			ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(ctx, gomock.Any()).Return(nil, limitErr),
+			ec2ServiceMock.EXPECT().DescribeLaunchTemplateVersions(ctx, gomock.Any()).Return(&ec2.DescribeLaunchTemplateVersionsOutput{
+				LaunchTemplateVersions: []ec2types.LaunchTemplateVersion{
+					{VersionNumber: aws.Int64(1), DefaultVersion: aws.Bool(true)},
+					{VersionNumber: aws.Int64(2)},
+					{VersionNumber: aws.Int64(3)},
+					{VersionNumber: aws.Int64(4)},
+					{VersionNumber: aws.Int64(5)},
+					{VersionNumber: aws.Int64(6)},
+				},
+			}, nil),
+			ec2ServiceMock.EXPECT().DeleteLaunchTemplateVersions(ctx, &ec2.DeleteLaunchTemplateVersionsInput{
+				LaunchTemplateId: aws.String(templateID),
+				Versions:         []string{"2", "4"},
+			}).Return(&ec2.DeleteLaunchTemplateVersionsOutput{}, nil),
+			ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(ctx, gomock.Any()).Return(output, nil),
+		)
+
+		launchTemplate, err := CreateManagedLaunchTemplateVersion(ctx, ec2ServiceMock, config, *group)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(launchTemplate.Version).To(Equal(aws.Int64(7)))
+	})
+
+	It("should fail when the version limit is reached and no versions are unused", func() {
+		config := &eksv1.EKSClusterConfig{Status: eksv1.EKSClusterConfigStatus{
+			ManagedLaunchTemplateID:       templateID,
+			ManagedLaunchTemplateVersions: map[string]string{"ng1": "2"},
+		}}
+		ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(ctx, gomock.Any()).Return(nil, &smithy.GenericAPIError{Code: "ResourceLimitExceeded"})
+		ec2ServiceMock.EXPECT().DescribeLaunchTemplateVersions(ctx, gomock.Any()).Return(&ec2.DescribeLaunchTemplateVersionsOutput{
+			LaunchTemplateVersions: []ec2types.LaunchTemplateVersion{
+				{VersionNumber: aws.Int64(1), DefaultVersion: aws.Bool(true)},
+				{VersionNumber: aws.Int64(2)},
+			},
+		}, nil)
+
+		_, err := CreateManagedLaunchTemplateVersion(ctx, ec2ServiceMock, config, *group)
+		Expect(err).To(HaveOccurred())
+	})
 })
 
 var _ = Describe("CreateNodeGroup", func() {
@@ -724,6 +1156,7 @@ var _ = Describe("CreateNodeGroup", func() {
 			InstanceTypes: createNodeGroupOpts.NodeGroup.SpotInstanceTypes,
 			Subnets:       createNodeGroupOpts.NodeGroup.Subnets,
 			NodeRole:      aws.String("test"),
+			Tags:          OwnershipTags(createNodeGroupOpts.Config),
 		}).Return(nil, nil)
 
 		launchTemplateVersion, generatedNodeRole, err := CreateNodeGroup(ctx, createNodeGroupOpts)
@@ -733,6 +1166,29 @@ var _ = Describe("CreateNodeGroup", func() {
 		Expect(generatedNodeRole).To(Equal("test"))
 	})
 
+	It("should pin the AMI release version of the node group", func() {
+		createNodeGroupOpts.NodeGroup.ImageID = nil
+		createNodeGroupOpts.NodeGroup.NodeRole = aws.String("test")
+		createNodeGroupOpts.NodeGroup.ReleaseVersion = aws.String("1.30.0-20240703")
+
+		ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(ctx, gomock.Any()).Return(&ec2.CreateLaunchTemplateVersionOutput{
+			LaunchTemplateVersion: &ec2types.LaunchTemplateVersion{
+				LaunchTemplateName: aws.String("test"),
+				LaunchTemplateId:   aws.String("test"),
+				VersionNumber:      aws.Int64(1),
+			},
+		}, nil)
+		eksServiceMock.EXPECT().CreateNodegroup(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, input *eks.CreateNodegroupInput) (*eks.CreateNodegroupOutput, error) {
+				Expect(input.AmiType).To(Equal(ekstypes.AMITypesAl2023X8664Standard))
+				Expect(aws.ToString(input.ReleaseVersion)).To(Equal("1.30.0-20240703"))
+				return &eks.CreateNodegroupOutput{}, nil
+			})
+
+		_, _, err := CreateNodeGroup(ctx, createNodeGroupOpts)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
 	It("shouldn't create launch template if it exists", func() {
 		createNodeGroupOpts.NodeGroup.LaunchTemplate = &eksv1.LaunchTemplate{
 			ID:      aws.String("test"),
@@ -793,6 +1249,35 @@ var _ = Describe("CreateNodeGroup", func() {
 		Expect(generatedNodeRole).To(Equal("test"))
 	})
 
+	It("should use the shared node role of the config instead of creating one", func() {
+		createNodeGroupOpts.Config.Spec.NodeRole = aws.String("arn:aws:iam::123456789012:role/shared-nodes")
+		ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(ctx, gomock.Any()).Return(&ec2.CreateLaunchTemplateVersionOutput{
+			LaunchTemplateVersion: &ec2types.LaunchTemplateVersion{
+				LaunchTemplateName: aws.String("test"),
+				LaunchTemplateId:   aws.String("test"),
+				VersionNumber:      aws.Int64(1),
+			},
+		}, nil)
+
+		ec2ServiceMock.EXPECT().DescribeImages(ctx, gomock.Any()).Return(&ec2.DescribeImagesOutput{
+			Images: []ec2types.Image{
+				{
+					RootDeviceName: aws.String("test"),
+				},
+			},
+		}, nil)
+
+		eksServiceMock.EXPECT().CreateNodegroup(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, input *eks.CreateNodegroupInput) (*eks.CreateNodegroupOutput, error) {
+				Expect(aws.ToString(input.NodeRole)).To(Equal("arn:aws:iam::123456789012:role/shared-nodes"))
+				return &eks.CreateNodegroupOutput{}, nil
+			})
+
+		_, generatedNodeRole, err := CreateNodeGroup(ctx, createNodeGroupOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(generatedNodeRole).To(BeEmpty())
+	})
+
 	It("delete launch template versions if creating node group fails", func() {
 		ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(ctx, gomock.Any()).Return(&ec2.CreateLaunchTemplateVersionOutput{
 			LaunchTemplateVersion: &ec2types.LaunchTemplateVersion{
@@ -833,6 +1318,37 @@ var _ = Describe("CreateNodeGroup", func() {
 		Expect(generatedNodeRole).To(Equal("test"))
 	})
 
+	It("delete launch template versions if node role stack is still being created", func() {
+		ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(ctx, gomock.Any()).Return(&ec2.CreateLaunchTemplateVersionOutput{
+			LaunchTemplateVersion: &ec2types.LaunchTemplateVersion{
+				LaunchTemplateName: aws.String("test"),
+				LaunchTemplateId:   aws.String("test"),
+				VersionNumber:      aws.Int64(1),
+			},
+		}, nil)
+		ec2ServiceMock.EXPECT().DescribeImages(ctx, gomock.Any()).Return(&ec2.DescribeImagesOutput{
+			Images: []ec2types.Image{
+				{
+					RootDeviceName: aws.String("test"),
+				},
+			},
+		}, nil)
+		cloudFormationServiceMock.EXPECT().CreateStack(ctx, gomock.Any()).Return(nil, nil)
+		cloudFormationServiceMock.EXPECT().DescribeStacks(ctx, gomock.Any()).Return(
+			&cloudformation.DescribeStacksOutput{
+				Stacks: []cftypes.Stack{
+					{
+						StackStatus: createInProgressStatus,
+					},
+				},
+			}, nil)
+		ec2ServiceMock.EXPECT().DeleteLaunchTemplateVersions(ctx, gomock.Any()).Return(nil, nil)
+
+		_, _, err := CreateNodeGroup(ctx, createNodeGroupOpts)
+		var inProgress *StackCreationInProgressError
+		Expect(errors.As(err, &inProgress)).To(BeTrue())
+	})
+
 	It("should fail to create node group if creating launch template return error", func() {
 		ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(ctx, gomock.Any()).Return(nil, errors.New("error"))
 
@@ -901,6 +1417,7 @@ var _ = Describe("CreateNodeGroup", func() {
 			InstanceTypes: createNodeGroupOpts.NodeGroup.SpotInstanceTypes,
 			Subnets:       createNodeGroupOpts.Config.Status.Subnets,
 			NodeRole:      aws.String("test"),
+			Tags:          OwnershipTags(createNodeGroupOpts.Config),
 		}).Return(nil, nil)
 
 		launchTemplateVersion, generatedNodeRole, err := CreateNodeGroup(ctx, createNodeGroupOpts)
@@ -957,6 +1474,7 @@ var _ = Describe("CreateNodeGroup", func() {
 			Subnets:       createNodeGroupOpts.NodeGroup.Subnets,
 			NodeRole:      aws.String("test"),
 			AmiType:       ekstypes.AMITypesAl2023X8664Nvidia,
+			Tags:          OwnershipTags(createNodeGroupOpts.Config),
 		}).Return(nil, nil)
 
 		launchTemplateVersion, generatedNodeRole, err := CreateNodeGroup(ctx, createNodeGroupOpts)
@@ -1013,6 +1531,7 @@ var _ = Describe("CreateNodeGroup", func() {
 			Subnets:       createNodeGroupOpts.NodeGroup.Subnets,
 			NodeRole:      aws.String("test"),
 			AmiType:       ekstypes.AMITypesAl2023Arm64Standard,
+			Tags:          OwnershipTags(createNodeGroupOpts.Config),
 		}).Return(nil, nil)
 
 		launchTemplateVersion, generatedNodeRole, err := CreateNodeGroup(ctx, createNodeGroupOpts)
@@ -1067,6 +1586,7 @@ var _ = Describe("CreateNodeGroup", func() {
 			Subnets:       createNodeGroupOpts.NodeGroup.Subnets,
 			NodeRole:      aws.String("test"),
 			AmiType:       ekstypes.AMITypesAl2023X8664Standard,
+			Tags:          OwnershipTags(createNodeGroupOpts.Config),
 		}).Return(nil, nil)
 
 		launchTemplateVersion, generatedNodeRole, err := CreateNodeGroup(ctx, createNodeGroupOpts)
@@ -1121,6 +1641,7 @@ var _ = Describe("CreateNodeGroup", func() {
 			Subnets:       createNodeGroupOpts.NodeGroup.Subnets,
 			NodeRole:      aws.String("test"),
 			AmiType:       ekstypes.AMITypesAl2023X8664Standard,
+			Tags:          OwnershipTags(createNodeGroupOpts.Config),
 		}).Return(nil, errors.New("error"))
 
 		_, _, err := CreateNodeGroup(ctx, createNodeGroupOpts)
@@ -1183,7 +1704,9 @@ var _ = Describe("CreateNodeGroup", func() {
 			Subnets:       createNodeGroupOpts.NodeGroup.Subnets,
 			NodeRole:      aws.String("test"),
 			Tags: map[string]string{
-				"tag1": "val1",
+				"tag1":            "val1",
+				ManagedTagKey:     "true",
+				ClusterNameTagKey: createNodeGroupOpts.Config.Spec.DisplayName,
 			},
 		}).Return(nil, nil)
 
@@ -1247,7 +1770,7 @@ var _ = Describe("installEBSCSIDriver", func() {
 		iamServiceMock.EXPECT().ListOIDCProviders(ctx, gomock.Any()).Return(oidcListProvidersOutput, nil)
 		eksServiceMock.EXPECT().DescribeCluster(ctx, gomock.Any()).Return(eksClusterOutput, nil)
 		iamServiceMock.EXPECT().CreateOIDCProvider(ctx, gomock.Any()).Return(oidcCreateProviderOutput, nil)
-		_, err := configureOIDCProvider(ctx, enableEBSCSIDriverInput.IAMService, enableEBSCSIDriverInput.EKSService, enableEBSCSIDriverInput.Config)
+		_, _, err := configureOIDCProvider(ctx, enableEBSCSIDriverInput.IAMService, enableEBSCSIDriverInput.EKSService, enableEBSCSIDriverInput.Config)
 		Expect(err).To(Succeed())
 	})
 
@@ -1257,13 +1780,13 @@ var _ = Describe("installEBSCSIDriver", func() {
 		}
 		eksServiceMock.EXPECT().DescribeCluster(ctx, gomock.Any()).Return(eksClusterOutput, nil)
 		iamServiceMock.EXPECT().ListOIDCProviders(ctx, gomock.Any()).Return(oidcListProvidersOutput, nil)
-		_, err := configureOIDCProvider(ctx, enableEBSCSIDriverInput.IAMService, enableEBSCSIDriverInput.EKSService, enableEBSCSIDriverInput.Config)
+		_, _, err := configureOIDCProvider(ctx, enableEBSCSIDriverInput.IAMService, enableEBSCSIDriverInput.EKSService, enableEBSCSIDriverInput.Config)
 		Expect(err).To(Succeed())
 	})
 
 	It("should fail to list oidc providers", func() {
 		iamServiceMock.EXPECT().ListOIDCProviders(ctx, gomock.Any()).Return(nil, fmt.Errorf("failed to list oidc providers"))
-		_, err := configureOIDCProvider(ctx, enableEBSCSIDriverInput.IAMService, enableEBSCSIDriverInput.EKSService, enableEBSCSIDriverInput.Config)
+		_, _, err := configureOIDCProvider(ctx, enableEBSCSIDriverInput.IAMService, enableEBSCSIDriverInput.EKSService, enableEBSCSIDriverInput.Config)
 		Expect(err).ToNot(Succeed())
 	})
 
@@ -1274,7 +1797,49 @@ var _ = Describe("installEBSCSIDriver", func() {
 		iamServiceMock.EXPECT().ListOIDCProviders(ctx, gomock.Any()).Return(oidcListProvidersOutput, nil)
 		eksServiceMock.EXPECT().DescribeCluster(ctx, gomock.Any()).Return(eksClusterOutput, nil)
 		iamServiceMock.EXPECT().CreateOIDCProvider(ctx, gomock.Any()).Return(nil, fmt.Errorf("failed to create oidc provider"))
-		_, err := configureOIDCProvider(ctx, enableEBSCSIDriverInput.IAMService, enableEBSCSIDriverInput.EKSService, enableEBSCSIDriverInput.Config)
+		_, _, err := configureOIDCProvider(ctx, enableEBSCSIDriverInput.IAMService, enableEBSCSIDriverInput.EKSService, enableEBSCSIDriverInput.Config)
+		Expect(err).ToNot(Succeed())
+	})
+
+	It("should create the oidc provider with the thumbprints in the spec", func() {
+		enableEBSCSIDriverInput.Config.Spec.OIDCThumbprints = []string{"9e99a48a9960b14926bb7f3b02e22da2b0ab7280"}
+		iamServiceMock.EXPECT().ListOIDCProviders(ctx, gomock.Any()).Return(oidcListProvidersOutput, nil)
+		eksServiceMock.EXPECT().DescribeCluster(ctx, gomock.Any()).Return(eksClusterOutput, nil)
+		iamServiceMock.EXPECT().CreateOIDCProvider(ctx, &iam.CreateOpenIDConnectProviderInput{
+			ClientIDList:   []string{string(defaultAudienceOpenIDConnect)},
+			ThumbprintList: []string{"9e99a48a9960b14926bb7f3b02e22da2b0ab7280"},
+			Url:            eksClusterOutput.Cluster.Identity.Oidc.Issuer,
+			Tags:           []iamtypes.Tag{},
+		}).Return(oidcCreateProviderOutput, nil)
+		_, providerARN, err := configureOIDCProvider(ctx, enableEBSCSIDriverInput.IAMService, enableEBSCSIDriverInput.EKSService, enableEBSCSIDriverInput.Config)
+		Expect(err).To(Succeed())
+		Expect(providerARN).To(Equal(aws.ToString(oidcCreateProviderOutput.OpenIDConnectProviderArn)))
+	})
+
+	It("should verify the certificate of the oidc issuer", func() {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+		defer server.Close()
+
+		thumbprint, err := getIssuerThumbprint(ctx, server.URL, server.Client().Transport.(*http.Transport))
+		Expect(err).To(Succeed())
+		Expect(thumbprint).To(Equal(fmt.Sprintf("%x", sha1.Sum(server.Certificate().Raw))))
+
+		_, err = getIssuerThumbprint(ctx, server.URL, http.DefaultTransport.(*http.Transport).Clone())
+		Expect(err).To(MatchError(ContainSubstring("certificate")))
+	})
+
+	It("should use the supplied oidc provider", func() {
+		enableEBSCSIDriverInput.Config.Spec.OIDCProviderARN = aws.String(fmt.Sprintf("arn:aws:iam::account:oidc-provider/oidc.eks.%v.amazonaws.com/id/AAABBBCCCDDDEEEFFF11122233344455", defaultAWSRegion))
+		eksServiceMock.EXPECT().DescribeCluster(ctx, gomock.Any()).Return(eksClusterOutput, nil)
+		id, err := getSuppliedOIDCProviderID(ctx, enableEBSCSIDriverInput.EKSService, enableEBSCSIDriverInput.Config)
+		Expect(err).To(Succeed())
+		Expect(id).To(Equal("AAABBBCCCDDDEEEFFF11122233344455"))
+	})
+
+	It("should fail to use a supplied oidc provider for another issuer", func() {
+		enableEBSCSIDriverInput.Config.Spec.OIDCProviderARN = aws.String(fmt.Sprintf("arn:aws:iam::account:oidc-provider/oidc.eks.%v.amazonaws.com/id/BBBAAACCCDDDEEEFFF11122233344455", defaultAWSRegion))
+		eksServiceMock.EXPECT().DescribeCluster(ctx, gomock.Any()).Return(eksClusterOutput, nil)
+		_, err := getSuppliedOIDCProviderID(ctx, enableEBSCSIDriverInput.EKSService, enableEBSCSIDriverInput.Config)
 		Expect(err).ToNot(Succeed())
 	})
 
@@ -1294,14 +1859,35 @@ var _ = Describe("installEBSCSIDriver", func() {
 					},
 				},
 			}, nil)
-		_, err := createEBSCSIDriverRole(ctx, enableEBSCSIDriverInput.CFService, enableEBSCSIDriverInput.Config, "")
+		_, err := createEBSCSIDriverRole(ctx, enableEBSCSIDriverInput.CFService, enableEBSCSIDriverInput.Config, "", nil)
+		Expect(err).To(Succeed())
+	})
+
+	It("should create driver iam role with the template override", func() {
+		cloudFormationServiceMock.EXPECT().CreateStack(ctx, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, input *cloudformation.CreateStackInput) (*cloudformation.CreateStackOutput, error) {
+				Expect(aws.ToString(input.TemplateBody)).To(Equal("eu-west-1 AAA"))
+				return nil, nil
+			})
+		cloudFormationServiceMock.EXPECT().DescribeStacks(ctx, gomock.Any()).Return(
+			&cloudformation.DescribeStacksOutput{
+				Stacks: []cftypes.Stack{
+					{
+						StackStatus: createCompleteStatus,
+						Outputs:     []cftypes.Output{{OutputKey: aws.String("EBSCSIDriverRole"), OutputValue: aws.String("test")}},
+					},
+				},
+			}, nil)
+		enableEBSCSIDriverInput.Config.Spec.Region = "eu-west-1"
+		_, err := createEBSCSIDriverRole(ctx, enableEBSCSIDriverInput.CFService, enableEBSCSIDriverInput.Config, "AAA",
+			map[string]string{TemplateEBSCSIDriver: "{{.Region}} {{.ProviderID}}"})
 		Expect(err).To(Succeed())
 	})
 
 	It("should fail to create driver iam role", func() {
 		cloudFormationServiceMock.EXPECT().CreateStack(ctx, gomock.Any()).Return(nil, nil)
 		cloudFormationServiceMock.EXPECT().DescribeStacks(ctx, gomock.Any()).Return(nil, fmt.Errorf("failed to describe stack"))
-		_, err := createEBSCSIDriverRole(ctx, enableEBSCSIDriverInput.CFService, enableEBSCSIDriverInput.Config, "")
+		_, err := createEBSCSIDriverRole(ctx, enableEBSCSIDriverInput.CFService, enableEBSCSIDriverInput.Config, "", nil)
 		Expect(err).ToNot(Succeed())
 	})
 
@@ -1323,4 +1909,214 @@ var _ = Describe("installEBSCSIDriver", func() {
 		_, err := installEBSAddon(ctx, enableEBSCSIDriverInput.EKSService, enableEBSCSIDriverInput.Config, "roleArn", "latest")
 		Expect(err).ToNot(Succeed())
 	})
+
+	It("should install addon with pod identity without an oidc provider", func() {
+		enableEBSCSIDriverInput.Config.Spec.DisplayName = "test"
+		enableEBSCSIDriverInput.AddonVersion = "latest"
+		enableEBSCSIDriverInput.PodIdentity = true
+		cloudFormationServiceMock.EXPECT().CreateStack(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, input *cloudformation.CreateStackInput) (*cloudformation.CreateStackOutput, error) {
+				Expect(aws.ToString(input.StackName)).To(Equal("test-ebs-csi-driver-pod-identity-role"))
+				Expect(aws.ToString(input.TemplateBody)).To(ContainSubstring("pods.eks.amazonaws.com"))
+				return nil, nil
+			})
+		cloudFormationServiceMock.EXPECT().DescribeStacks(ctx, gomock.Any()).Return(
+			&cloudformation.DescribeStacksOutput{
+				Stacks: []cftypes.Stack{
+					{
+						StackStatus: createCompleteStatus,
+						Outputs: []cftypes.Output{
+							{
+								OutputKey:   aws.String("EBSCSIDriverPodIdentityRole"),
+								OutputValue: aws.String("roleArn"),
+							},
+						},
+					},
+				},
+			}, nil)
+		eksServiceMock.EXPECT().CreateAddon(ctx, &eks.CreateAddonInput{
+			AddonName:   aws.String("aws-ebs-csi-driver"),
+			ClusterName: aws.String("test"),
+			PodIdentityAssociations: []ekstypes.AddonPodIdentityAssociations{
+				{RoleArn: aws.String("roleArn"), ServiceAccount: aws.String("ebs-csi-controller-sa")},
+			},
+		}).Return(&eks.CreateAddonOutput{Addon: &ekstypes.Addon{AddonArn: aws.String("arn:aws::ebs-csi-driver")}}, nil)
+		oidcProviderARN, err := EnableEBSCSIDriver(ctx, enableEBSCSIDriverInput)
+		Expect(err).To(Succeed())
+		Expect(oidcProviderARN).To(BeEmpty())
+	})
+})
+
+var _ = Describe("CreateServiceRole", func() {
+	var (
+		mockController *gomock.Controller
+		iamServiceMock *mock_services.MockIAMServiceInterface
+		opts           *CreateServiceRoleOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		iamServiceMock = mock_services.NewMockIAMServiceInterface(mockController)
+		opts = &CreateServiceRoleOpts{
+			IAMService: iamServiceMock,
+			Config:     &eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{DisplayName: "test"}},
+			RoleName:   "test-eks-service-role",
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	expectPolicies := func() {
+		for _, policyARN := range serviceRolePolicyARNs {
+			iamServiceMock.EXPECT().AttachRolePolicy(ctx, &iam.AttachRolePolicyInput{
+				RoleName:  aws.String("test-eks-service-role"),
+				PolicyArn: aws.String(policyARN),
+			}).Return(&iam.AttachRolePolicyOutput{}, nil)
+		}
+	}
+
+	It("should create the role and attach its policies", func() {
+		iamServiceMock.EXPECT().CreateRole(ctx, &iam.CreateRoleInput{
+			RoleName:                 aws.String("test-eks-service-role"),
+			AssumeRolePolicyDocument: aws.String(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"Service":"eks.amazonaws.com"},"Action":"sts:AssumeRole"}]}`),
+			Tags:                     []iamtypes.Tag{{Key: aws.String("displayName"), Value: aws.String("test")}},
+		}).Return(&iam.CreateRoleOutput{Role: &iamtypes.Role{Arn: aws.String("arn:aws:iam::123456789012:role/test-eks-service-role")}}, nil)
+		expectPolicies()
+
+		roleARN, err := CreateServiceRole(ctx, opts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(roleARN).To(Equal("arn:aws:iam::123456789012:role/test-eks-service-role"))
+	})
+
+	It("should reuse a role created by a previous attempt", func() {
+		iamServiceMock.EXPECT().CreateRole(ctx, gomock.Any()).Return(nil, &iamtypes.EntityAlreadyExistsException{})
+		iamServiceMock.EXPECT().GetRole(ctx, &iam.GetRoleInput{RoleName: aws.String("test-eks-service-role")}).Return(
+			&iam.GetRoleOutput{Role: &iamtypes.Role{Arn: aws.String("arn:aws:iam::123456789012:role/test-eks-service-role")}}, nil)
+		expectPolicies()
+
+		roleARN, err := CreateServiceRole(ctx, opts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(roleARN).To(Equal("arn:aws:iam::123456789012:role/test-eks-service-role"))
+	})
+
+	It("should create the role with the permissions boundary", func() {
+		opts.Config.Spec.IAMPermissionsBoundary = "arn:aws:iam::123456789012:policy/boundary"
+		iamServiceMock.EXPECT().CreateRole(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, input *iam.CreateRoleInput) (*iam.CreateRoleOutput, error) {
+				Expect(input.PermissionsBoundary).To(Equal(aws.String("arn:aws:iam::123456789012:policy/boundary")))
+				return &iam.CreateRoleOutput{Role: &iamtypes.Role{Arn: aws.String("arn:aws:iam::123456789012:role/test-eks-service-role")}}, nil
+			})
+		expectPolicies()
+
+		_, err := CreateServiceRole(ctx, opts)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should attach the additional policies of the spec", func() {
+		opts.Config.Spec.ServiceRolePolicyARNs = []string{"arn:aws:iam::aws:policy/AmazonEKSComputePolicy"}
+		iamServiceMock.EXPECT().CreateRole(ctx, gomock.Any()).Return(
+			&iam.CreateRoleOutput{Role: &iamtypes.Role{Arn: aws.String("arn:aws:iam::123456789012:role/test-eks-service-role")}}, nil)
+		expectPolicies()
+		iamServiceMock.EXPECT().AttachRolePolicy(ctx, &iam.AttachRolePolicyInput{
+			RoleName:  aws.String("test-eks-service-role"),
+			PolicyArn: aws.String("arn:aws:iam::aws:policy/AmazonEKSComputePolicy"),
+		}).Return(&iam.AttachRolePolicyOutput{}, nil)
+
+		_, err := CreateServiceRole(ctx, opts)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should fail if attaching a policy fails", func() {
+		iamServiceMock.EXPECT().CreateRole(ctx, gomock.Any()).Return(&iam.CreateRoleOutput{Role: &iamtypes.Role{}}, nil)
+		iamServiceMock.EXPECT().AttachRolePolicy(ctx, gomock.Any()).Return(nil, errors.New("error"))
+
+		_, err := CreateServiceRole(ctx, opts)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("CloudFormationUnavailable", func() {
+	It("should detect unsupported and denied stack operations", func() {
+		denied := &smithy.GenericAPIError{
+			Code:    "AccessDenied",
+			Message: "User: arn:aws:iam::123456789012:user/test is not authorized to perform: cloudformation:CreateStack",
+		}
+		Expect(CloudFormationUnavailable(fmt.Errorf("error creating master: %w", denied))).To(BeTrue())
+		Expect(CloudFormationDenied(denied)).To(BeTrue())
+		Expect(CloudFormationUnsupported(denied)).To(BeFalse())
+		Expect(CloudFormationUnavailable(&smithy.GenericAPIError{Code: "OptInRequired"})).To(BeTrue())
+		Expect(CloudFormationUnsupported(&smithy.GenericAPIError{Code: "OptInRequired"})).To(BeTrue())
+	})
+
+	It("should not detect credential errors and denials of other services", func() {
+		Expect(CloudFormationUnavailable(&smithy.GenericAPIError{Code: "UnrecognizedClientException"})).To(BeFalse())
+		Expect(CloudFormationUnavailable(&smithy.GenericAPIError{Code: "InvalidClientTokenId"})).To(BeFalse())
+		Expect(CloudFormationUnavailable(&smithy.GenericAPIError{Code: "AccessDenied"})).To(BeFalse())
+		Expect(CloudFormationUnavailable(&smithy.GenericAPIError{
+			Code:    "AccessDenied",
+			Message: "User: arn:aws:iam::123456789012:user/test is not authorized to perform: iam:CreateRole",
+		})).To(BeFalse())
+	})
+
+	It("should not detect stacks that failed", func() {
+		Expect(CloudFormationUnavailable(nil)).To(BeFalse())
+		Expect(CloudFormationUnavailable(&smithy.GenericAPIError{Code: "ValidationError"})).To(BeFalse())
+		Expect(CloudFormationUnavailable(errors.New("stack [test] failed to create"))).To(BeFalse())
+	})
+})
+
+var _ = Describe("getEC2ServiceEndpoint", func() {
+	It("should return the service principal of EC2 in the partition of the region", func() {
+		Expect(getEC2ServiceEndpoint("us-west-2")).To(Equal("ec2.amazonaws.com"))
+		Expect(getEC2ServiceEndpoint("us-gov-east-1")).To(Equal("ec2.amazonaws.com"))
+		Expect(getEC2ServiceEndpoint("cn-northwest-1")).To(Equal("ec2.amazonaws.com.cn"))
+		Expect(getEC2ServiceEndpoint("us-iso-west-1")).To(Equal("ec2.c2s.ic.gov"))
+		Expect(getEC2ServiceEndpoint("us-isob-east-1")).To(Equal("ec2.sc2s.sgov.gov"))
+		Expect(getEC2ServiceEndpoint("eu-isoe-west-1")).To(Equal("ec2.cloud.adc-e.uk"))
+	})
+})
+
+var _ = Describe("NodeInstanceRoleAdditionalPolicyARNs", func() {
+	It("should add the policy of the CloudWatch agent while Container Insights is enabled", func() {
+		config := &eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{
+			NodeInstanceRolePolicyARNs: []string{"arn:aws:iam::aws:policy/AmazonSSMManagedInstanceCore"},
+		}}
+		Expect(NodeInstanceRoleAdditionalPolicyARNs(config)).To(Equal([]string{"arn:aws:iam::aws:policy/AmazonSSMManagedInstanceCore"}))
+
+		config.Spec.ContainerInsights = aws.Bool(true)
+		Expect(NodeInstanceRoleAdditionalPolicyARNs(config)).To(Equal([]string{"arn:aws:iam::aws:policy/AmazonSSMManagedInstanceCore", ContainerInsightsPolicyARN}))
+
+		config.Spec.ContainerInsights = aws.Bool(false)
+		Expect(NodeInstanceRoleAdditionalPolicyARNs(config)).To(Equal([]string{"arn:aws:iam::aws:policy/AmazonSSMManagedInstanceCore"}))
+	})
+})
+
+var _ = Describe("RoleStackParameters", func() {
+	It("should only return the parameters that are set", func() {
+		config := &eksv1.EKSClusterConfig{}
+		Expect(RoleStackParameters(config, nil)).To(BeEmpty())
+
+		config.Spec.IAMPermissionsBoundary = "arn:aws:iam::123456789012:policy/boundary"
+		Expect(RoleStackParameters(config, []string{"arn:aws:iam::aws:policy/a", "arn:aws:iam::aws:policy/b"})).To(Equal([]cftypes.Parameter{
+			{ParameterKey: aws.String("PermissionsBoundary"), ParameterValue: aws.String("arn:aws:iam::123456789012:policy/boundary")},
+			{ParameterKey: aws.String("AdditionalPolicyArns"), ParameterValue: aws.String("arn:aws:iam::aws:policy/a,arn:aws:iam::aws:policy/b")},
+		}))
+	})
+})
+
+var _ = Describe("getParameterValueFromOutput", func() {
+	It("should return the value of the output with the key", func() {
+		outputs := []cftypes.Output{
+			{OutputKey: aws.String("VpcId"), OutputValue: aws.String("vpc-1")},
+			{OutputKey: aws.String("SubnetIds"), OutputValue: aws.String("subnet-1,subnet-2")},
+		}
+		Expect(getParameterValueFromOutput("SubnetIds", outputs)).To(Equal("subnet-1,subnet-2"))
+	})
+
+	It("should return an empty string if the output is missing or has no value", func() {
+		Expect(getParameterValueFromOutput("VpcId", nil)).To(BeEmpty())
+		Expect(getParameterValueFromOutput("VpcId", []cftypes.Output{{OutputKey: aws.String("VpcId")}, {}})).To(BeEmpty())
+	})
 })
@@ -2,17 +2,28 @@ package eks
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strconv"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/smithy-go"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
 	"github.com/rancher/eks-operator/pkg/eks/services"
 	"github.com/sirupsen/logrus"
 )
 
-func DeleteLaunchTemplateVersions(ctx context.Context, ec2Service services.EC2ServiceInterface, templateID string, templateVersions []*string) {
+// DeleteLaunchTemplateVersions deletes the versions of the launch template, retrying the ones that fail to delete a few
+// times. Versions that can't be deleted are only logged, except in dry run mode, whose DryRunError is returned without
+// retrying, so that callers don't record the versions as deleted.
+func DeleteLaunchTemplateVersions(ctx context.Context, ec2Service services.EC2ServiceInterface, templateID string, templateVersions []*string) error {
 	launchTemplateDeleteVersionInput := &ec2.DeleteLaunchTemplateVersionsInput{
 		LaunchTemplateId: aws.String(templateID),
 		Versions:         aws.ToStringSlice(templateVersions),
@@ -33,7 +44,11 @@ func DeleteLaunchTemplateVersions(ctx context.Context, ec2Service services.EC2Se
 		}
 
 		if err == nil || len(templateVersions) == 0 {
-			return
+			return nil
+		}
+		var dryRunErr *services.DryRunError
+		if errors.As(err, &dryRunErr) {
+			return err
 		}
 
 		launchTemplateDeleteVersionInput.Versions = aws.ToStringSlice(templateVersions)
@@ -45,9 +60,277 @@ func DeleteLaunchTemplateVersions(ctx context.Context, ec2Service services.EC2Se
 		*launchTemplateDeleteVersionInput.LaunchTemplateId,
 		err,
 	)
+	return nil
+}
+
+// maxLaunchTemplateVersionsPerRequest is the number of launch template versions that can be described or deleted in a
+// single request
+const maxLaunchTemplateVersionsPerRequest = 200
+
+// DeleteUnusedLaunchTemplateVersions deletes the versions of the managed launch template of the config that are older
+// than the newest version in use by its node groups, except the versions in use and the default version, and returns
+// how many were deleted. Newer versions are kept since they may have been created for node groups whose creation or
+// update hasn't been recorded on the status yet.
+func DeleteUnusedLaunchTemplateVersions(ctx context.Context, ec2Service services.EC2ServiceInterface, config *eksv1.EKSClusterConfig) (int, error) {
+	inUse := make(map[int64]struct{}, len(config.Status.ManagedLaunchTemplateVersions))
+	var newestInUse int64
+	for _, version := range config.Status.ManagedLaunchTemplateVersions {
+		number, err := strconv.ParseInt(version, 10, 64)
+		if err != nil {
+			continue
+		}
+		inUse[number] = struct{}{}
+		newestInUse = max(newestInUse, number)
+	}
+
+	var unused []*string
+	input := &ec2.DescribeLaunchTemplateVersionsInput{
+		LaunchTemplateId: aws.String(config.Status.ManagedLaunchTemplateID),
+		MaxResults:       aws.Int32(maxLaunchTemplateVersionsPerRequest),
+	}
+	for {
+		output, err := ec2Service.DescribeLaunchTemplateVersions(ctx, input)
+		if err != nil {
+			return 0, fmt.Errorf("error describing launch template versions: %w", err)
+		}
+		for _, version := range output.LaunchTemplateVersions {
+			number := aws.ToInt64(version.VersionNumber)
+			if _, ok := inUse[number]; ok || aws.ToBool(version.DefaultVersion) || number >= newestInUse {
+				continue
+			}
+			unused = append(unused, aws.String(strconv.FormatInt(number, 10)))
+		}
+		if output.NextToken == nil {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+
+	for start := 0; start < len(unused); start += maxLaunchTemplateVersionsPerRequest {
+		end := min(start+maxLaunchTemplateVersionsPerRequest, len(unused))
+		if err := DeleteLaunchTemplateVersions(ctx, ec2Service, config.Status.ManagedLaunchTemplateID, unused[start:end]); err != nil {
+			return 0, err
+		}
+	}
+	return len(unused), nil
+}
+
+type DeleteResourceTagOpts struct {
+	EC2Service  services.EC2ServiceInterface
+	ResourceIDs []string
+	TagKey      string
+}
+
+// DeleteResourceTag removes the tag with the given key from the resources that have it and returns their IDs. Tags
+// of resources shared from other accounts aren't visible to the account, so they are left untouched.
+func DeleteResourceTag(ctx context.Context, opts *DeleteResourceTagOpts) ([]string, error) {
+	if len(opts.ResourceIDs) == 0 {
+		return nil, nil
+	}
+
+	input := &ec2.DescribeTagsInput{
+		Filters: []ec2types.Filter{
+			{Name: aws.String("resource-id"), Values: opts.ResourceIDs},
+			{Name: aws.String("key"), Values: []string{opts.TagKey}},
+		},
+	}
+	var taggedResourceIDs []string
+	for {
+		output, err := opts.EC2Service.DescribeTags(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range output.Tags {
+			taggedResourceIDs = append(taggedResourceIDs, aws.ToString(tag.ResourceId))
+		}
+		if aws.ToString(output.NextToken) == "" {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+
+	if len(taggedResourceIDs) == 0 {
+		return nil, nil
+	}
+
+	_, err := opts.EC2Service.DeleteTags(ctx, &ec2.DeleteTagsInput{
+		Resources: taggedResourceIDs,
+		Tags:      []ec2types.Tag{{Key: aws.String(opts.TagKey)}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return taggedResourceIDs, nil
+}
+
+// DeleteEBSAddon deletes the EBS CSI driver add-on of the cluster if it is installed and returns whether it is gone.
+func DeleteEBSAddon(ctx context.Context, clusterName string, eksService services.EKSServiceInterface) (bool, error) {
+	return deleteAddon(ctx, clusterName, ebsCSIAddonName, eksService)
+}
+
+// DeleteContainerInsightsAddon deletes the CloudWatch observability add-on of the cluster if it is installed and
+// returns whether it is gone.
+func DeleteContainerInsightsAddon(ctx context.Context, clusterName string, eksService services.EKSServiceInterface) (bool, error) {
+	return deleteAddon(ctx, clusterName, containerInsightsAddonName, eksService)
+}
+
+func deleteAddon(ctx context.Context, clusterName, addonName string, eksService services.EKSServiceInterface) (bool, error) {
+	output, err := eksService.DescribeAddon(ctx, &eks.DescribeAddonInput{
+		AddonName:   aws.String(addonName),
+		ClusterName: aws.String(clusterName),
+	})
+	if err != nil {
+		var rnf *ekstypes.ResourceNotFoundException
+		if errors.As(err, &rnf) {
+			return true, nil
+		}
+		return false, err
+	}
+	if output.Addon == nil {
+		return true, nil
+	}
+	if output.Addon.Status == ekstypes.AddonStatusDeleting {
+		return false, nil
+	}
+
+	if _, err := eksService.DeleteAddon(ctx, &eks.DeleteAddonInput{
+		AddonName:   aws.String(addonName),
+		ClusterName: aws.String(clusterName),
+	}); err != nil {
+		var rnf *ekstypes.ResourceNotFoundException
+		if errors.As(err, &rnf) {
+			return true, nil
+		}
+		return false, err
+	}
+
+	return false, nil
+}
+
+// DeleteOIDCProvider deletes the IAM OIDC provider with the given ARN, a provider that doesn't exist is ignored.
+func DeleteOIDCProvider(ctx context.Context, iamService services.IAMServiceInterface, providerARN string) error {
+	_, err := iamService.DeleteOIDCProvider(ctx, &iam.DeleteOpenIDConnectProviderInput{
+		OpenIDConnectProviderArn: aws.String(providerARN),
+	})
+	var noSuchEntity *iamtypes.NoSuchEntityException
+	if errors.As(err, &noSuchEntity) {
+		return nil
+	}
+	return err
+}
+
+type DeleteRoleOpts struct {
+	IAMService services.IAMServiceInterface
+	RoleName   string
+}
+
+// DeleteRole detaches the policies of a role created through IAM and deletes it, a role that doesn't exist is ignored.
+func DeleteRole(ctx context.Context, opts *DeleteRoleOpts) error {
+	var noSuchEntity *iamtypes.NoSuchEntityException
+	input := &iam.ListAttachedRolePoliciesInput{
+		RoleName: aws.String(opts.RoleName),
+	}
+	for {
+		output, err := opts.IAMService.ListAttachedRolePolicies(ctx, input)
+		if errors.As(err, &noSuchEntity) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error listing policies of role [%s]: %w", opts.RoleName, err)
+		}
+		for _, policy := range output.AttachedPolicies {
+			if _, err := opts.IAMService.DetachRolePolicy(ctx, &iam.DetachRolePolicyInput{
+				RoleName:  aws.String(opts.RoleName),
+				PolicyArn: policy.PolicyArn,
+			}); err != nil && !errors.As(err, &noSuchEntity) {
+				return fmt.Errorf("error detaching policy [%s] from role [%s]: %w", aws.ToString(policy.PolicyArn), opts.RoleName, err)
+			}
+		}
+		if !output.IsTruncated {
+			break
+		}
+		input.Marker = output.Marker
+	}
+
+	_, err := opts.IAMService.DeleteRole(ctx, &iam.DeleteRoleInput{
+		RoleName: aws.String(opts.RoleName),
+	})
+	if err != nil && !errors.As(err, &noSuchEntity) {
+		return fmt.Errorf("error deleting role [%s]: %w", opts.RoleName, err)
+	}
+	return nil
+}
+
+// ClusterLogGroupName returns the name of the CloudWatch log group EKS sends the control plane logs of the cluster to.
+func ClusterLogGroupName(clusterName string) string {
+	return fmt.Sprintf("/aws/eks/%s/cluster", clusterName)
+}
+
+// DeleteClusterLogGroup deletes the CloudWatch log group of the control plane logs of the cluster, a log group that
+// doesn't exist is ignored.
+func DeleteClusterLogGroup(ctx context.Context, logsService services.CloudWatchLogsServiceInterface, clusterName string) error {
+	_, err := logsService.DeleteLogGroup(ctx, &services.DeleteLogGroupInput{
+		LogGroupName: aws.String(ClusterLogGroupName(clusterName)),
+	})
+	if isCloudWatchLogsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// isLaunchTemplateVersionLimitExceeded returns whether the error is returned because a launch template has reached the
+// maximum number of versions.
+func isLaunchTemplateVersionLimitExceeded(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.ErrorCode() == "VersionLimitExceeded" || apiErr.ErrorCode() == "ResourceLimitExceeded"
 }
 
 func launchTemplateVersionDoesNotExist(errorCode string) bool {
 	return errorCode == string(ec2types.LaunchTemplateErrorCodeLaunchTemplateVersionDoesNotExist) ||
 		errorCode == string(ec2types.LaunchTemplateErrorCodeLaunchTemplateIdDoesNotExist)
 }
+
+type DeleteAuditLoggingOpts struct {
+	CloudWatchLogsService services.CloudWatchLogsServiceInterface
+	CloudWatchService     services.CloudWatchServiceInterface
+	ClusterName           string
+}
+
+// DeleteAuditLogging deletes the alarm and metric filter on the audit events of the cluster, the retention of its log
+// group is left as it is. Resources that don't exist are ignored.
+func DeleteAuditLogging(ctx context.Context, opts *DeleteAuditLoggingOpts) error {
+	if err := deleteAuditAlarm(ctx, opts.CloudWatchService, opts.ClusterName); err != nil {
+		return err
+	}
+
+	logGroupName := ClusterLogGroupName(opts.ClusterName)
+	logrus.Infof("Deleting audit metric filter [%s] of log group [%s]", AuditResourceName(opts.ClusterName), logGroupName)
+	_, err := opts.CloudWatchLogsService.DeleteMetricFilter(ctx, &services.DeleteMetricFilterInput{
+		LogGroupName: aws.String(logGroupName),
+		FilterName:   aws.String(AuditResourceName(opts.ClusterName)),
+	})
+	if err != nil && !isCloudWatchLogsNotFound(err) {
+		return fmt.Errorf("error deleting audit metric filter of log group [%s]: %w", logGroupName, err)
+	}
+	return nil
+}
+
+func deleteAuditAlarm(ctx context.Context, cloudWatchService services.CloudWatchServiceInterface, clusterName string) error {
+	logrus.Infof("Deleting audit alarm [%s]", AuditResourceName(clusterName))
+	if _, err := cloudWatchService.DeleteAlarms(ctx, &services.DeleteAlarmsInput{
+		AlarmNames: []string{AuditResourceName(clusterName)},
+	}); err != nil {
+		return fmt.Errorf("error deleting audit alarm [%s]: %w", AuditResourceName(clusterName), err)
+	}
+	return nil
+}
+
+// isCloudWatchLogsNotFound returns whether the error is returned for a log group or metric filter that doesn't exist.
+func isCloudWatchLogsNotFound(err error) bool {
+	var apiErr *services.APIError
+	return errors.As(err, &apiErr) && apiErr.Code == services.CloudWatchLogsResourceNotFound
+}
@@ -1,10 +1,19 @@
 package eks
 
 import (
+	"errors"
+
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher/eks-operator/pkg/eks/services"
 	"github.com/rancher/eks-operator/pkg/eks/services/mock_services"
 )
 
@@ -32,6 +41,304 @@ var _ = Describe("deleteLaunchTemplateVersions", func() {
 			Versions:         templateVersions,
 		}).Return(nil, nil)
 
-		DeleteLaunchTemplateVersions(ctx, ec2ServiceMock, templateID, aws.StringSlice(templateVersions))
+		Expect(DeleteLaunchTemplateVersions(ctx, ec2ServiceMock, templateID, aws.StringSlice(templateVersions))).To(Succeed())
+	})
+
+	It("should not retry requests skipped in dry run mode", func() {
+		ec2ServiceMock.EXPECT().DeleteLaunchTemplateVersions(ctx, gomock.Any()).Return(nil, &services.DryRunError{Operation: "EC2 DeleteLaunchTemplateVersions"})
+
+		err := DeleteLaunchTemplateVersions(ctx, ec2ServiceMock, "templateID", aws.StringSlice([]string{"1"}))
+		Expect(err).To(MatchError(&services.DryRunError{Operation: "EC2 DeleteLaunchTemplateVersions"}))
+	})
+})
+
+var _ = Describe("DeleteResourceTag", func() {
+	var (
+		mockController *gomock.Controller
+		ec2ServiceMock *mock_services.MockEC2ServiceInterface
+		opts           *DeleteResourceTagOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		ec2ServiceMock = mock_services.NewMockEC2ServiceInterface(mockController)
+		opts = &DeleteResourceTagOpts{
+			EC2Service:  ec2ServiceMock,
+			ResourceIDs: []string{"subnet-a", "subnet-b", "sg-a"},
+			TagKey:      "kubernetes.io/cluster/test",
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should delete the tag from resources that have it", func() {
+		ec2ServiceMock.EXPECT().DescribeTags(ctx, gomock.Any()).Return(&ec2.DescribeTagsOutput{
+			Tags:      []ec2types.TagDescription{{ResourceId: aws.String("subnet-a")}},
+			NextToken: aws.String("next"),
+		}, nil)
+		ec2ServiceMock.EXPECT().DescribeTags(ctx, gomock.Any()).Return(&ec2.DescribeTagsOutput{
+			Tags: []ec2types.TagDescription{{ResourceId: aws.String("sg-a")}},
+		}, nil)
+		ec2ServiceMock.EXPECT().DeleteTags(ctx, &ec2.DeleteTagsInput{
+			Resources: []string{"subnet-a", "sg-a"},
+			Tags:      []ec2types.Tag{{Key: aws.String("kubernetes.io/cluster/test")}},
+		}).Return(&ec2.DeleteTagsOutput{}, nil)
+
+		removed, err := DeleteResourceTag(ctx, opts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(removed).To(Equal([]string{"subnet-a", "sg-a"}))
+	})
+
+	It("should not delete tags if no resource has the tag", func() {
+		ec2ServiceMock.EXPECT().DescribeTags(ctx, gomock.Any()).Return(&ec2.DescribeTagsOutput{}, nil)
+
+		removed, err := DeleteResourceTag(ctx, opts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(removed).To(BeEmpty())
+	})
+
+	It("should fail if tags can't be described", func() {
+		ec2ServiceMock.EXPECT().DescribeTags(ctx, gomock.Any()).Return(nil, errors.New("error"))
+
+		_, err := DeleteResourceTag(ctx, opts)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("DeleteEBSAddon", func() {
+	var (
+		mockController *gomock.Controller
+		eksServiceMock *mock_services.MockEKSServiceInterface
+		describeInput  *eks.DescribeAddonInput
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		describeInput = &eks.DescribeAddonInput{
+			AddonName:   aws.String(ebsCSIAddonName),
+			ClusterName: aws.String("test"),
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should delete an installed addon", func() {
+		eksServiceMock.EXPECT().DescribeAddon(ctx, describeInput).Return(&eks.DescribeAddonOutput{
+			Addon: &ekstypes.Addon{Status: ekstypes.AddonStatusActive},
+		}, nil)
+		eksServiceMock.EXPECT().DeleteAddon(ctx, &eks.DeleteAddonInput{
+			AddonName:   aws.String(ebsCSIAddonName),
+			ClusterName: aws.String("test"),
+		}).Return(&eks.DeleteAddonOutput{}, nil)
+
+		deleted, err := DeleteEBSAddon(ctx, "test", eksServiceMock)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleted).To(BeFalse())
+	})
+
+	It("should wait for a deleting addon", func() {
+		eksServiceMock.EXPECT().DescribeAddon(ctx, describeInput).Return(&eks.DescribeAddonOutput{
+			Addon: &ekstypes.Addon{Status: ekstypes.AddonStatusDeleting},
+		}, nil)
+
+		deleted, err := DeleteEBSAddon(ctx, "test", eksServiceMock)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleted).To(BeFalse())
+	})
+
+	It("should return that a missing addon is deleted", func() {
+		eksServiceMock.EXPECT().DescribeAddon(ctx, describeInput).Return(nil, &ekstypes.ResourceNotFoundException{})
+
+		deleted, err := DeleteEBSAddon(ctx, "test", eksServiceMock)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleted).To(BeTrue())
+	})
+
+	It("should fail if DescribeAddon returns error", func() {
+		eksServiceMock.EXPECT().DescribeAddon(ctx, describeInput).Return(nil, errors.New("error"))
+
+		_, err := DeleteEBSAddon(ctx, "test", eksServiceMock)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("DeleteOIDCProvider", func() {
+	var (
+		mockController *gomock.Controller
+		iamServiceMock *mock_services.MockIAMServiceInterface
+		providerARN    = "arn:aws:iam::account:oidc-provider/oidc.eks.us-east-1.amazonaws.com/id/AAABBBCCC"
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		iamServiceMock = mock_services.NewMockIAMServiceInterface(mockController)
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should delete the provider", func() {
+		iamServiceMock.EXPECT().DeleteOIDCProvider(ctx, &iam.DeleteOpenIDConnectProviderInput{
+			OpenIDConnectProviderArn: aws.String(providerARN),
+		}).Return(&iam.DeleteOpenIDConnectProviderOutput{}, nil)
+
+		Expect(DeleteOIDCProvider(ctx, iamServiceMock, providerARN)).To(Succeed())
+	})
+
+	It("should ignore a provider that doesn't exist", func() {
+		iamServiceMock.EXPECT().DeleteOIDCProvider(ctx, gomock.Any()).Return(nil, &iamtypes.NoSuchEntityException{})
+
+		Expect(DeleteOIDCProvider(ctx, iamServiceMock, providerARN)).To(Succeed())
+	})
+
+	It("should fail if DeleteOIDCProvider returns error", func() {
+		iamServiceMock.EXPECT().DeleteOIDCProvider(ctx, gomock.Any()).Return(nil, errors.New("error"))
+
+		Expect(DeleteOIDCProvider(ctx, iamServiceMock, providerARN)).ToNot(Succeed())
+	})
+})
+
+var _ = Describe("DeleteRole", func() {
+	var (
+		mockController *gomock.Controller
+		iamServiceMock *mock_services.MockIAMServiceInterface
+		opts           *DeleteRoleOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		iamServiceMock = mock_services.NewMockIAMServiceInterface(mockController)
+		opts = &DeleteRoleOpts{IAMService: iamServiceMock, RoleName: "test-node-instance-role"}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should detach the policies and delete the role", func() {
+		iamServiceMock.EXPECT().ListAttachedRolePolicies(ctx, &iam.ListAttachedRolePoliciesInput{
+			RoleName: aws.String("test-node-instance-role"),
+		}).Return(&iam.ListAttachedRolePoliciesOutput{
+			AttachedPolicies: []iamtypes.AttachedPolicy{{PolicyArn: aws.String("arn:aws:iam::aws:policy/AmazonEKSWorkerNodePolicy")}},
+		}, nil)
+		iamServiceMock.EXPECT().DetachRolePolicy(ctx, &iam.DetachRolePolicyInput{
+			RoleName:  aws.String("test-node-instance-role"),
+			PolicyArn: aws.String("arn:aws:iam::aws:policy/AmazonEKSWorkerNodePolicy"),
+		}).Return(&iam.DetachRolePolicyOutput{}, nil)
+		iamServiceMock.EXPECT().DeleteRole(ctx, &iam.DeleteRoleInput{
+			RoleName: aws.String("test-node-instance-role"),
+		}).Return(&iam.DeleteRoleOutput{}, nil)
+
+		Expect(DeleteRole(ctx, opts)).To(Succeed())
+	})
+
+	It("should ignore a role that doesn't exist", func() {
+		iamServiceMock.EXPECT().ListAttachedRolePolicies(ctx, gomock.Any()).Return(nil, &iamtypes.NoSuchEntityException{})
+
+		Expect(DeleteRole(ctx, opts)).To(Succeed())
+	})
+
+	It("should fail if DeleteRole returns error", func() {
+		iamServiceMock.EXPECT().ListAttachedRolePolicies(ctx, gomock.Any()).Return(&iam.ListAttachedRolePoliciesOutput{}, nil)
+		iamServiceMock.EXPECT().DeleteRole(ctx, gomock.Any()).Return(nil, &iamtypes.DeleteConflictException{})
+
+		Expect(DeleteRole(ctx, opts)).ToNot(Succeed())
+	})
+})
+
+var _ = Describe("DeleteClusterLogGroup", func() {
+	var (
+		mockController  *gomock.Controller
+		logsServiceMock *mock_services.MockCloudWatchLogsServiceInterface
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		logsServiceMock = mock_services.NewMockCloudWatchLogsServiceInterface(mockController)
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should delete the log group", func() {
+		logsServiceMock.EXPECT().DeleteLogGroup(ctx, &services.DeleteLogGroupInput{
+			LogGroupName: aws.String("/aws/eks/test/cluster"),
+		}).Return(&services.DeleteLogGroupOutput{}, nil)
+
+		Expect(DeleteClusterLogGroup(ctx, logsServiceMock, "test")).To(Succeed())
+	})
+
+	It("should ignore a log group that doesn't exist", func() {
+		logsServiceMock.EXPECT().DeleteLogGroup(ctx, gomock.Any()).Return(nil, &services.APIError{
+			StatusCode: 400,
+			Code:       services.CloudWatchLogsResourceNotFound,
+		})
+
+		Expect(DeleteClusterLogGroup(ctx, logsServiceMock, "test")).To(Succeed())
+	})
+
+	It("should fail if DeleteLogGroup returns error", func() {
+		logsServiceMock.EXPECT().DeleteLogGroup(ctx, gomock.Any()).Return(nil, errors.New("error"))
+
+		Expect(DeleteClusterLogGroup(ctx, logsServiceMock, "test")).ToNot(Succeed())
+	})
+})
+
+var _ = Describe("DeleteAuditLogging", func() {
+	var (
+		mockController        *gomock.Controller
+		logsServiceMock       *mock_services.MockCloudWatchLogsServiceInterface
+		cloudWatchServiceMock *mock_services.MockCloudWatchServiceInterface
+		opts                  *DeleteAuditLoggingOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		logsServiceMock = mock_services.NewMockCloudWatchLogsServiceInterface(mockController)
+		cloudWatchServiceMock = mock_services.NewMockCloudWatchServiceInterface(mockController)
+		opts = &DeleteAuditLoggingOpts{
+			CloudWatchLogsService: logsServiceMock,
+			CloudWatchService:     cloudWatchServiceMock,
+			ClusterName:           "test",
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should delete the alarm and the metric filter", func() {
+		cloudWatchServiceMock.EXPECT().DeleteAlarms(ctx, &services.DeleteAlarmsInput{
+			AlarmNames: []string{"test-audit-events"},
+		}).Return(&services.DeleteAlarmsOutput{}, nil)
+		logsServiceMock.EXPECT().DeleteMetricFilter(ctx, &services.DeleteMetricFilterInput{
+			LogGroupName: aws.String("/aws/eks/test/cluster"),
+			FilterName:   aws.String("test-audit-events"),
+		}).Return(&services.DeleteMetricFilterOutput{}, nil)
+
+		Expect(DeleteAuditLogging(ctx, opts)).To(Succeed())
+	})
+
+	It("should ignore a metric filter that doesn't exist", func() {
+		cloudWatchServiceMock.EXPECT().DeleteAlarms(ctx, gomock.Any()).Return(&services.DeleteAlarmsOutput{}, nil)
+		logsServiceMock.EXPECT().DeleteMetricFilter(ctx, gomock.Any()).Return(nil, &services.APIError{
+			StatusCode: 400,
+			Code:       services.CloudWatchLogsResourceNotFound,
+		})
+
+		Expect(DeleteAuditLogging(ctx, opts)).To(Succeed())
+	})
+
+	It("should fail if DeleteAlarms returns error", func() {
+		cloudWatchServiceMock.EXPECT().DeleteAlarms(ctx, gomock.Any()).Return(nil, errors.New("error"))
+
+		Expect(DeleteAuditLogging(ctx, opts)).ToNot(Succeed())
 	})
 })
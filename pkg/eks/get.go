@@ -2,17 +2,32 @@ package eks
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	cftypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/aws/aws-sdk-go-v2/service/eks"
 	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
 	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
 	"github.com/rancher/eks-operator/pkg/eks/services"
 )
 
+const (
+	clusterTokenPrefix = "k8s-aws-v1."
+	clusterIDHeader    = "x-k8s-aws-id"
+	// expiry of the presigned request in seconds, EKS accepts the token for 15 minutes regardless
+	clusterTokenExpiry = "60"
+)
+
 type GetClusterStatusOpts struct {
 	EKSService services.EKSServiceInterface
 	Config     *eksv1.EKSClusterConfig
@@ -47,11 +62,43 @@ func GetLaunchTemplateVersions(ctx context.Context, opts *GetLaunchTemplateVersi
 		})
 }
 
+type GetSubnetsOpts struct {
+	EC2Service services.EC2ServiceInterface
+	SubnetIDs  []string
+}
+
+// GetSubnets returns the subnets with the given IDs, including the account that owns each of them.
+func GetSubnets(ctx context.Context, opts *GetSubnetsOpts) ([]ec2types.Subnet, error) {
+	output, err := opts.EC2Service.DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{
+		SubnetIds: opts.SubnetIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Subnets, nil
+}
+
 // CheckEBSAddon checks if the EBS CSI driver add-on is installed. If it is, it will return
 // the ARN of the add-on. If it is not, it will return an empty string. Otherwise, it will return an error
 func CheckEBSAddon(ctx context.Context, clusterName string, eksService services.EKSServiceInterface) (string, error) {
+	return checkAddon(ctx, clusterName, ebsCSIAddonName, eksService)
+}
+
+// CheckPodIdentityAgentAddon returns the ARN of the EKS Pod Identity agent add-on, or an empty string if it isn't installed.
+func CheckPodIdentityAgentAddon(ctx context.Context, clusterName string, eksService services.EKSServiceInterface) (string, error) {
+	return checkAddon(ctx, clusterName, podIdentityAgentAddonName, eksService)
+}
+
+// CheckContainerInsightsAddon returns the ARN of the CloudWatch observability add-on, which provides Container
+// Insights, or an empty string if it isn't installed.
+func CheckContainerInsightsAddon(ctx context.Context, clusterName string, eksService services.EKSServiceInterface) (string, error) {
+	return checkAddon(ctx, clusterName, containerInsightsAddonName, eksService)
+}
+
+func checkAddon(ctx context.Context, clusterName, addonName string, eksService services.EKSServiceInterface) (string, error) {
 	input := eks.DescribeAddonInput{
-		AddonName:   aws.String(ebsCSIAddonName),
+		AddonName:   aws.String(addonName),
 		ClusterName: aws.String(clusterName),
 	}
 
@@ -69,3 +116,530 @@ func CheckEBSAddon(ctx context.Context, clusterName string, eksService services.
 
 	return *output.Addon.AddonArn, nil
 }
+
+type GetPodIdentityAssociationsOpts struct {
+	EKSService  services.EKSServiceInterface
+	ClusterName string
+}
+
+// GetPodIdentityAssociations returns the pod identity associations of the cluster, except the ones owned by add-ons,
+// which are managed along with their add-on.
+func GetPodIdentityAssociations(ctx context.Context, opts *GetPodIdentityAssociationsOpts) ([]ekstypes.PodIdentityAssociation, error) {
+	var associations []ekstypes.PodIdentityAssociation
+	input := &eks.ListPodIdentityAssociationsInput{
+		ClusterName: aws.String(opts.ClusterName),
+	}
+	for {
+		output, err := opts.EKSService.ListPodIdentityAssociations(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, summary := range output.Associations {
+			if summary.OwnerArn != nil {
+				continue
+			}
+			association, err := opts.EKSService.DescribePodIdentityAssociation(ctx, &eks.DescribePodIdentityAssociationInput{
+				ClusterName:   aws.String(opts.ClusterName),
+				AssociationId: summary.AssociationId,
+			})
+			if err != nil {
+				return nil, err
+			}
+			associations = append(associations, *association.Association)
+		}
+		if output.NextToken == nil {
+			return associations, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+type GetIdentityProviderConfigsOpts struct {
+	EKSService  services.EKSServiceInterface
+	ClusterName string
+}
+
+// GetIdentityProviderConfigs returns the OIDC identity provider configs associated with the cluster.
+func GetIdentityProviderConfigs(ctx context.Context, opts *GetIdentityProviderConfigsOpts) ([]ekstypes.OidcIdentityProviderConfig, error) {
+	var configs []ekstypes.OidcIdentityProviderConfig
+	input := &eks.ListIdentityProviderConfigsInput{
+		ClusterName: aws.String(opts.ClusterName),
+	}
+	for {
+		output, err := opts.EKSService.ListIdentityProviderConfigs(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, idpConfig := range output.IdentityProviderConfigs {
+			if aws.ToString(idpConfig.Type) != oidcIdentityProviderType {
+				continue
+			}
+			describeOutput, err := opts.EKSService.DescribeIdentityProviderConfig(ctx, &eks.DescribeIdentityProviderConfigInput{
+				ClusterName:            aws.String(opts.ClusterName),
+				IdentityProviderConfig: &ekstypes.IdentityProviderConfig{Name: idpConfig.Name, Type: idpConfig.Type},
+			})
+			if err != nil {
+				return nil, err
+			}
+			if describeOutput.IdentityProviderConfig != nil && describeOutput.IdentityProviderConfig.Oidc != nil {
+				configs = append(configs, *describeOutput.IdentityProviderConfig.Oidc)
+			}
+		}
+		if output.NextToken == nil {
+			return configs, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+type GetAddonsOpts struct {
+	EKSService  services.EKSServiceInterface
+	ClusterName string
+}
+
+// GetAddons returns the add-ons installed on the cluster.
+func GetAddons(ctx context.Context, opts *GetAddonsOpts) ([]ekstypes.Addon, error) {
+	var addons []ekstypes.Addon
+	input := &eks.ListAddonsInput{
+		ClusterName: aws.String(opts.ClusterName),
+	}
+	for {
+		output, err := opts.EKSService.ListAddons(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("error listing add-ons: %w", err)
+		}
+		for _, name := range output.Addons {
+			describeOutput, err := opts.EKSService.DescribeAddon(ctx, &eks.DescribeAddonInput{
+				ClusterName: aws.String(opts.ClusterName),
+				AddonName:   aws.String(name),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error describing add-on [%s]: %w", name, err)
+			}
+			if describeOutput.Addon != nil {
+				addons = append(addons, *describeOutput.Addon)
+			}
+		}
+		if output.NextToken == nil {
+			return addons, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+type GetInsightWarningsOpts struct {
+	EKSService  services.EKSServiceInterface
+	ClusterName string
+}
+
+// GetInsightWarnings returns the insights EKS reports with warnings or errors for the cluster, e.g. deprecated APIs
+// in use ahead of an upgrade or admission webhooks slowing down the control plane, as sorted "name (status): reason"
+// descriptions.
+func GetInsightWarnings(ctx context.Context, opts *GetInsightWarningsOpts) ([]string, error) {
+	var warnings []string
+	input := &eks.ListInsightsInput{
+		ClusterName: aws.String(opts.ClusterName),
+		Filter: &ekstypes.InsightsFilter{
+			Statuses: []ekstypes.InsightStatusValue{ekstypes.InsightStatusValueWarning, ekstypes.InsightStatusValueError},
+		},
+	}
+	for {
+		output, err := opts.EKSService.ListInsights(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("error listing insights: %w", err)
+		}
+		for _, insight := range output.Insights {
+			if insight.InsightStatus == nil {
+				continue
+			}
+			warning := fmt.Sprintf("%s (%s)", aws.ToString(insight.Name), insight.InsightStatus.Status)
+			if reason := aws.ToString(insight.InsightStatus.Reason); reason != "" {
+				warning += ": " + reason
+			}
+			warnings = append(warnings, warning)
+		}
+		if output.NextToken == nil {
+			sort.Strings(warnings)
+			return warnings, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+type GetFargateProfilesOpts struct {
+	EKSService  services.EKSServiceInterface
+	ClusterName string
+}
+
+// GetFargateProfiles returns the Fargate profiles of the cluster.
+func GetFargateProfiles(ctx context.Context, opts *GetFargateProfilesOpts) ([]ekstypes.FargateProfile, error) {
+	var profiles []ekstypes.FargateProfile
+	input := &eks.ListFargateProfilesInput{
+		ClusterName: aws.String(opts.ClusterName),
+	}
+	for {
+		output, err := opts.EKSService.ListFargateProfiles(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("error listing Fargate profiles: %w", err)
+		}
+		for _, name := range output.FargateProfileNames {
+			describeOutput, err := opts.EKSService.DescribeFargateProfile(ctx, &eks.DescribeFargateProfileInput{
+				ClusterName:        aws.String(opts.ClusterName),
+				FargateProfileName: aws.String(name),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error describing Fargate profile [%s]: %w", name, err)
+			}
+			if describeOutput.FargateProfile != nil {
+				profiles = append(profiles, *describeOutput.FargateProfile)
+			}
+		}
+		if output.NextToken == nil {
+			return profiles, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+type GetClustersOpts struct {
+	EKSService services.EKSServiceInterface
+}
+
+// GetClusters returns all clusters in the region of the EKS service.
+func GetClusters(ctx context.Context, opts *GetClustersOpts) ([]ekstypes.Cluster, error) {
+	var clusters []ekstypes.Cluster
+	input := &eks.ListClustersInput{}
+	for {
+		output, err := opts.EKSService.ListClusters(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("error listing clusters: %w", err)
+		}
+		for _, name := range output.Clusters {
+			describeOutput, err := opts.EKSService.DescribeCluster(ctx, &eks.DescribeClusterInput{
+				Name: aws.String(name),
+			})
+			if err != nil {
+				var rnf *ekstypes.ResourceNotFoundException
+				if errors.As(err, &rnf) {
+					// deleted since it was listed
+					continue
+				}
+				return nil, fmt.Errorf("error describing cluster [%s]: %w", name, err)
+			}
+			if describeOutput.Cluster != nil {
+				clusters = append(clusters, *describeOutput.Cluster)
+			}
+		}
+		if output.NextToken == nil {
+			return clusters, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+type GetUpdateOpts struct {
+	EKSService  services.EKSServiceInterface
+	ClusterName string
+	Update      eksv1.EKSUpdate
+}
+
+// GetUpdate returns the upstream state of an update sent to EKS, or nil if it no longer exists, e.g. because the node
+// group or add-on it was sent for was deleted.
+func GetUpdate(ctx context.Context, opts *GetUpdateOpts) (*ekstypes.Update, error) {
+	input := &eks.DescribeUpdateInput{
+		Name:     aws.String(opts.ClusterName),
+		UpdateId: aws.String(opts.Update.ID),
+	}
+	if opts.Update.NodegroupName != "" {
+		input.NodegroupName = aws.String(opts.Update.NodegroupName)
+	}
+	if opts.Update.AddonName != "" {
+		input.AddonName = aws.String(opts.Update.AddonName)
+	}
+
+	output, err := opts.EKSService.DescribeUpdate(ctx, input)
+	if err != nil {
+		var rnf *ekstypes.ResourceNotFoundException
+		if errors.As(err, &rnf) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error describing update [%s]: %w", opts.Update.ID, err)
+	}
+	return output.Update, nil
+}
+
+type GetResourceUsageOpts struct {
+	EC2Service services.EC2ServiceInterface
+	Config     *eksv1.EKSClusterConfig
+	Nodegroups []*ekstypes.Nodegroup
+}
+
+// GetResourceUsage counts the AWS resources with quotas that the cluster of the config consumes. Stacks are counted
+// from the status, elastic IPs only for VPCs the operator created.
+func GetResourceUsage(ctx context.Context, opts *GetResourceUsageOpts) (*eksv1.ResourceUsage, error) {
+	usage := &eksv1.ResourceUsage{
+		CloudFormationStacks: int32(len(opts.Config.Status.CloudFormationStacks)),
+	}
+
+	launchTemplates := make(map[string]struct{})
+	if opts.Config.Status.ManagedLaunchTemplateID != "" {
+		launchTemplates[opts.Config.Status.ManagedLaunchTemplateID] = struct{}{}
+	}
+	for _, ng := range opts.Nodegroups {
+		if ng != nil && ng.LaunchTemplate != nil && aws.ToString(ng.LaunchTemplate.Id) != "" {
+			launchTemplates[aws.ToString(ng.LaunchTemplate.Id)] = struct{}{}
+		}
+	}
+	usage.LaunchTemplates = int32(len(launchTemplates))
+
+	if opts.Config.Status.NetworkFieldsSource == "generated" && opts.Config.Status.VirtualNetwork != "" {
+		input := &ec2.DescribeNatGatewaysInput{
+			Filter: []ec2types.Filter{
+				{Name: aws.String("vpc-id"), Values: []string{opts.Config.Status.VirtualNetwork}},
+				{Name: aws.String("state"), Values: []string{string(ec2types.NatGatewayStatePending), string(ec2types.NatGatewayStateAvailable)}},
+			},
+		}
+		for {
+			output, err := opts.EC2Service.DescribeNatGateways(ctx, input)
+			if err != nil {
+				return nil, fmt.Errorf("error describing NAT gateways of VPC [%s]: %w", opts.Config.Status.VirtualNetwork, err)
+			}
+			for _, natGateway := range output.NatGateways {
+				for _, address := range natGateway.NatGatewayAddresses {
+					if address.AllocationId != nil {
+						usage.ElasticIPs++
+					}
+				}
+			}
+			if output.NextToken == nil {
+				break
+			}
+			input.NextToken = output.NextToken
+		}
+	}
+
+	if opts.Config.Status.ClusterSecurityGroup != "" {
+		input := &ec2.DescribeNetworkInterfacesInput{
+			Filters: []ec2types.Filter{
+				{Name: aws.String("group-id"), Values: []string{opts.Config.Status.ClusterSecurityGroup}},
+			},
+		}
+		for {
+			output, err := opts.EC2Service.DescribeNetworkInterfaces(ctx, input)
+			if err != nil {
+				return nil, fmt.Errorf("error describing network interfaces of security group [%s]: %w", opts.Config.Status.ClusterSecurityGroup, err)
+			}
+			usage.NetworkInterfaces += int32(len(output.NetworkInterfaces))
+			if output.NextToken == nil {
+				break
+			}
+			input.NextToken = output.NextToken
+		}
+	}
+
+	return usage, nil
+}
+
+type GetNodegroupRolloutOpts struct {
+	EKSService  services.EKSServiceInterface
+	EC2Service  services.EC2ServiceInterface
+	ClusterName string
+	Nodegroup   *ekstypes.Nodegroup
+	// ID of the update the rollout was last recorded for, it is checked before the other updates of the node group
+	UpdateID string
+}
+
+// GetNodegroupRollout returns the progress of the version update in progress on the node group, or nil if there is
+// none. The running instances of the auto scaling groups of the node group that were launched since the update started
+// are counted as updated nodes, out of the desired size of the node group.
+func GetNodegroupRollout(ctx context.Context, opts *GetNodegroupRolloutOpts) (*eksv1.NodeGroupRollout, error) {
+	update, err := getVersionUpdateInProgress(ctx, opts)
+	if err != nil || update == nil {
+		return nil, err
+	}
+	startTime := aws.ToTime(update.CreatedAt)
+
+	var autoScalingGroups []string
+	if opts.Nodegroup.Resources != nil {
+		for _, group := range opts.Nodegroup.Resources.AutoScalingGroups {
+			autoScalingGroups = append(autoScalingGroups, aws.ToString(group.Name))
+		}
+	}
+	updatedNodes, err := countInstancesLaunchedSince(ctx, opts.EC2Service, autoScalingGroups, startTime)
+	if err != nil {
+		return nil, err
+	}
+
+	var totalNodes int32
+	if opts.Nodegroup.ScalingConfig != nil {
+		totalNodes = aws.ToInt32(opts.Nodegroup.ScalingConfig.DesiredSize)
+	}
+	// surge instances are launched ahead of the ones they replace
+	updatedNodes = min(updatedNodes, totalNodes)
+
+	rollout := &eksv1.NodeGroupRollout{
+		UpdateID:     aws.ToString(update.Id),
+		StartTime:    startTime.UTC().Format(time.RFC3339),
+		UpdatedNodes: updatedNodes,
+		TotalNodes:   totalNodes,
+	}
+	if totalNodes > 0 {
+		batchSize := getRolloutBatchSize(opts.Nodegroup.UpdateConfig, totalNodes)
+		rollout.TotalBatches = (totalNodes + batchSize - 1) / batchSize
+		rollout.CurrentBatch = min(updatedNodes/batchSize+1, rollout.TotalBatches)
+	}
+
+	return rollout, nil
+}
+
+// getVersionUpdateInProgress returns the version update in progress on the node group, or nil if there is none.
+func getVersionUpdateInProgress(ctx context.Context, opts *GetNodegroupRolloutOpts) (*ekstypes.Update, error) {
+	describe := func(updateID string) (*ekstypes.Update, error) {
+		output, err := opts.EKSService.DescribeUpdate(ctx, &eks.DescribeUpdateInput{
+			Name:          aws.String(opts.ClusterName),
+			NodegroupName: opts.Nodegroup.NodegroupName,
+			UpdateId:      aws.String(updateID),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if output.Update == nil || output.Update.Type != ekstypes.UpdateTypeVersionUpdate ||
+			output.Update.Status != ekstypes.UpdateStatusInProgress {
+			return nil, nil
+		}
+		return output.Update, nil
+	}
+
+	if opts.UpdateID != "" {
+		update, err := describe(opts.UpdateID)
+		if err != nil || update != nil {
+			return update, err
+		}
+	}
+
+	input := &eks.ListUpdatesInput{
+		Name:          aws.String(opts.ClusterName),
+		NodegroupName: opts.Nodegroup.NodegroupName,
+	}
+	for {
+		output, err := opts.EKSService.ListUpdates(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, updateID := range output.UpdateIds {
+			if updateID == opts.UpdateID {
+				continue
+			}
+			update, err := describe(updateID)
+			if err != nil || update != nil {
+				return update, err
+			}
+		}
+		if output.NextToken == nil {
+			return nil, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+// countInstancesLaunchedSince returns the number of pending and running instances of the auto scaling groups that were
+// launched at or after the given time.
+func countInstancesLaunchedSince(ctx context.Context, ec2Service services.EC2ServiceInterface, autoScalingGroups []string, since time.Time) (int32, error) {
+	if len(autoScalingGroups) == 0 {
+		return 0, nil
+	}
+
+	var count int32
+	input := &ec2.DescribeInstancesInput{
+		Filters: []ec2types.Filter{
+			{Name: aws.String("tag:aws:autoscaling:groupName"), Values: autoScalingGroups},
+			{Name: aws.String("instance-state-name"), Values: []string{string(ec2types.InstanceStateNamePending), string(ec2types.InstanceStateNameRunning)}},
+		},
+	}
+	for {
+		output, err := ec2Service.DescribeInstances(ctx, input)
+		if err != nil {
+			return 0, err
+		}
+		for _, reservation := range output.Reservations {
+			for _, instance := range reservation.Instances {
+				if !aws.ToTime(instance.LaunchTime).Before(since) {
+					count++
+				}
+			}
+		}
+		if output.NextToken == nil {
+			return count, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+// getRolloutBatchSize returns the number of nodes that are replaced at once, which is the maximum number of unavailable
+// nodes of the node group, one by default.
+func getRolloutBatchSize(updateConfig *ekstypes.NodegroupUpdateConfig, totalNodes int32) int32 {
+	if updateConfig == nil {
+		return 1
+	}
+	if updateConfig.MaxUnavailable != nil {
+		return max(aws.ToInt32(updateConfig.MaxUnavailable), 1)
+	}
+	if updateConfig.MaxUnavailablePercentage != nil {
+		return max(totalNodes*aws.ToInt32(updateConfig.MaxUnavailablePercentage)/100, 1)
+	}
+	return 1
+}
+
+type GetClusterStacksOpts struct {
+	CloudFormationService services.CloudFormationServiceInterface
+	DisplayName           string
+}
+
+// GetClusterStacks returns the CloudFormation stacks that were created for the cluster with the given display name,
+// found by the displayName tag every stack created for a cluster is tagged with, regardless of the stack name.
+func GetClusterStacks(ctx context.Context, opts *GetClusterStacksOpts) ([]cftypes.Stack, error) {
+	var stacks []cftypes.Stack
+	input := &cloudformation.DescribeStacksInput{}
+	for {
+		output, err := opts.CloudFormationService.DescribeStacks(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("error describing stacks: %w", err)
+		}
+
+		for _, stack := range output.Stacks {
+			for _, tag := range stack.Tags {
+				if aws.ToString(tag.Key) == "displayName" && aws.ToString(tag.Value) == opts.DisplayName {
+					stacks = append(stacks, stack)
+					break
+				}
+			}
+		}
+
+		if output.NextToken == nil {
+			return stacks, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+type GetClusterTokenOpts struct {
+	STSService  services.STSServiceInterface
+	ClusterName string
+}
+
+// GetClusterToken returns a bearer token for the API server of the cluster, generated from a presigned
+// GetCallerIdentity request the same way aws eks get-token does, so it authenticates as the credentials of the STS
+// service.
+func GetClusterToken(ctx context.Context, opts *GetClusterTokenOpts) (string, error) {
+	request, err := opts.STSService.PresignGetCallerIdentity(ctx, &sts.GetCallerIdentityInput{}, func(po *sts.PresignOptions) {
+		po.ClientOptions = append(po.ClientOptions, sts.WithAPIOptions(
+			smithyhttp.SetHeaderValue(clusterIDHeader, opts.ClusterName),
+			smithyhttp.SetHeaderValue("X-Amz-Expires", clusterTokenExpiry),
+		))
+	})
+	if err != nil {
+		return "", fmt.Errorf("error presigning GetCallerIdentity request: %w", err)
+	}
+
+	return clusterTokenPrefix + base64.RawURLEncoding.EncodeToString([]byte(request.URL)), nil
+}
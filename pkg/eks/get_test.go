@@ -1,11 +1,17 @@
 package eks
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	cftypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/aws/aws-sdk-go-v2/service/eks"
 	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
 	"github.com/golang/mock/gomock"
@@ -162,3 +168,598 @@ var _ = Describe("GetLaunchTemplateVersions", func() {
 		})
 	})
 })
+
+var _ = Describe("GetClusterStacks", func() {
+	var (
+		mockController            *gomock.Controller
+		cloudFormationServiceMock *mock_services.MockCloudFormationServiceInterface
+		getClusterStacksOpts      *GetClusterStacksOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		cloudFormationServiceMock = mock_services.NewMockCloudFormationServiceInterface(mockController)
+		getClusterStacksOpts = &GetClusterStacksOpts{
+			CloudFormationService: cloudFormationServiceMock,
+			DisplayName:           "test",
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should return the stacks tagged with the display name from all pages", func() {
+		cloudFormationServiceMock.EXPECT().DescribeStacks(ctx, &cloudformation.DescribeStacksInput{}).Return(
+			&cloudformation.DescribeStacksOutput{
+				Stacks: []cftypes.Stack{
+					{
+						StackName: aws.String("test-eks-vpc"),
+						Tags:      []cftypes.Tag{{Key: aws.String("displayName"), Value: aws.String("test")}},
+					},
+					{
+						StackName: aws.String("other-eks-vpc"),
+						Tags:      []cftypes.Tag{{Key: aws.String("displayName"), Value: aws.String("other")}},
+					},
+				},
+				NextToken: aws.String("next"),
+			}, nil)
+		cloudFormationServiceMock.EXPECT().DescribeStacks(ctx, &cloudformation.DescribeStacksInput{NextToken: aws.String("next")}).Return(
+			&cloudformation.DescribeStacksOutput{
+				Stacks: []cftypes.Stack{
+					{
+						StackName: aws.String("legacy-service-role"),
+						Tags:      []cftypes.Tag{{Key: aws.String("displayName"), Value: aws.String("test")}},
+					},
+				},
+			}, nil)
+
+		stacks, err := GetClusterStacks(ctx, getClusterStacksOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stacks).To(HaveLen(2))
+		Expect(aws.ToString(stacks[0].StackName)).To(Equal("test-eks-vpc"))
+		Expect(aws.ToString(stacks[1].StackName)).To(Equal("legacy-service-role"))
+	})
+
+	It("should fail to get stacks if DescribeStacks returns error", func() {
+		cloudFormationServiceMock.EXPECT().DescribeStacks(ctx, gomock.Any()).Return(nil, errors.New("error"))
+
+		_, err := GetClusterStacks(ctx, getClusterStacksOpts)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("GetPodIdentityAssociations", func() {
+	var (
+		mockController *gomock.Controller
+		eksServiceMock *mock_services.MockEKSServiceInterface
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should get pod identity associations not owned by add-ons", func() {
+		eksServiceMock.EXPECT().ListPodIdentityAssociations(ctx, &eks.ListPodIdentityAssociationsInput{ClusterName: aws.String("test")}).Return(
+			&eks.ListPodIdentityAssociationsOutput{
+				Associations: []ekstypes.PodIdentityAssociationSummary{
+					{AssociationId: aws.String("a1")},
+					{AssociationId: aws.String("a2"), OwnerArn: aws.String("addon-arn")},
+				},
+				NextToken: aws.String("next"),
+			}, nil)
+		eksServiceMock.EXPECT().ListPodIdentityAssociations(ctx, &eks.ListPodIdentityAssociationsInput{ClusterName: aws.String("test"), NextToken: aws.String("next")}).Return(
+			&eks.ListPodIdentityAssociationsOutput{
+				Associations: []ekstypes.PodIdentityAssociationSummary{{AssociationId: aws.String("a3")}},
+			}, nil)
+		eksServiceMock.EXPECT().DescribePodIdentityAssociation(ctx, gomock.Any()).DoAndReturn(
+			func(_ interface{}, input *eks.DescribePodIdentityAssociationInput) (*eks.DescribePodIdentityAssociationOutput, error) {
+				return &eks.DescribePodIdentityAssociationOutput{
+					Association: &ekstypes.PodIdentityAssociation{AssociationId: input.AssociationId},
+				}, nil
+			}).Times(2)
+
+		associations, err := GetPodIdentityAssociations(ctx, &GetPodIdentityAssociationsOpts{EKSService: eksServiceMock, ClusterName: "test"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(associations).To(HaveLen(2))
+		Expect(associations[0].AssociationId).To(Equal(aws.String("a1")))
+		Expect(associations[1].AssociationId).To(Equal(aws.String("a3")))
+	})
+
+	It("should return error if listing pod identity associations failed", func() {
+		eksServiceMock.EXPECT().ListPodIdentityAssociations(ctx, gomock.Any()).Return(nil, errors.New("error listing"))
+		_, err := GetPodIdentityAssociations(ctx, &GetPodIdentityAssociationsOpts{EKSService: eksServiceMock, ClusterName: "test"})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("GetIdentityProviderConfigs", func() {
+	var (
+		mockController *gomock.Controller
+		eksServiceMock *mock_services.MockEKSServiceInterface
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should get oidc identity provider configs", func() {
+		eksServiceMock.EXPECT().ListIdentityProviderConfigs(ctx, &eks.ListIdentityProviderConfigsInput{ClusterName: aws.String("test")}).Return(
+			&eks.ListIdentityProviderConfigsOutput{
+				IdentityProviderConfigs: []ekstypes.IdentityProviderConfig{
+					{Name: aws.String("corp"), Type: aws.String("oidc")},
+					{Name: aws.String("other"), Type: aws.String("other")},
+				},
+			}, nil)
+		eksServiceMock.EXPECT().DescribeIdentityProviderConfig(ctx, &eks.DescribeIdentityProviderConfigInput{
+			ClusterName:            aws.String("test"),
+			IdentityProviderConfig: &ekstypes.IdentityProviderConfig{Name: aws.String("corp"), Type: aws.String("oidc")},
+		}).Return(&eks.DescribeIdentityProviderConfigOutput{
+			IdentityProviderConfig: &ekstypes.IdentityProviderConfigResponse{
+				Oidc: &ekstypes.OidcIdentityProviderConfig{IdentityProviderConfigName: aws.String("corp")},
+			},
+		}, nil)
+
+		configs, err := GetIdentityProviderConfigs(ctx, &GetIdentityProviderConfigsOpts{EKSService: eksServiceMock, ClusterName: "test"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(configs).To(HaveLen(1))
+		Expect(configs[0].IdentityProviderConfigName).To(Equal(aws.String("corp")))
+	})
+
+	It("should return error if listing identity provider configs failed", func() {
+		eksServiceMock.EXPECT().ListIdentityProviderConfigs(ctx, gomock.Any()).Return(nil, errors.New("error listing"))
+		_, err := GetIdentityProviderConfigs(ctx, &GetIdentityProviderConfigsOpts{EKSService: eksServiceMock, ClusterName: "test"})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("GetClusters", func() {
+	var (
+		mockController *gomock.Controller
+		eksServiceMock *mock_services.MockEKSServiceInterface
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should describe the clusters from all pages", func() {
+		eksServiceMock.EXPECT().ListClusters(ctx, &eks.ListClustersInput{}).Return(
+			&eks.ListClustersOutput{Clusters: []string{"test1", "deleted"}, NextToken: aws.String("next")}, nil)
+		eksServiceMock.EXPECT().ListClusters(ctx, &eks.ListClustersInput{NextToken: aws.String("next")}).Return(
+			&eks.ListClustersOutput{Clusters: []string{"test2"}}, nil)
+		eksServiceMock.EXPECT().DescribeCluster(ctx, &eks.DescribeClusterInput{Name: aws.String("test1")}).Return(
+			&eks.DescribeClusterOutput{Cluster: &ekstypes.Cluster{Name: aws.String("test1")}}, nil)
+		eksServiceMock.EXPECT().DescribeCluster(ctx, &eks.DescribeClusterInput{Name: aws.String("deleted")}).Return(
+			nil, &ekstypes.ResourceNotFoundException{})
+		eksServiceMock.EXPECT().DescribeCluster(ctx, &eks.DescribeClusterInput{Name: aws.String("test2")}).Return(
+			&eks.DescribeClusterOutput{Cluster: &ekstypes.Cluster{Name: aws.String("test2")}}, nil)
+
+		clusters, err := GetClusters(ctx, &GetClustersOpts{EKSService: eksServiceMock})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(clusters).To(HaveLen(2))
+		Expect(aws.ToString(clusters[0].Name)).To(Equal("test1"))
+		Expect(aws.ToString(clusters[1].Name)).To(Equal("test2"))
+	})
+
+	It("should fail if ListClusters returns error", func() {
+		eksServiceMock.EXPECT().ListClusters(ctx, gomock.Any()).Return(nil, errors.New("error"))
+
+		_, err := GetClusters(ctx, &GetClustersOpts{EKSService: eksServiceMock})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("GetAddons", func() {
+	var (
+		mockController *gomock.Controller
+		eksServiceMock *mock_services.MockEKSServiceInterface
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should describe the add-ons from all pages", func() {
+		eksServiceMock.EXPECT().ListAddons(ctx, &eks.ListAddonsInput{ClusterName: aws.String("test")}).Return(
+			&eks.ListAddonsOutput{Addons: []string{"vpc-cni"}, NextToken: aws.String("next")}, nil)
+		eksServiceMock.EXPECT().ListAddons(ctx, &eks.ListAddonsInput{ClusterName: aws.String("test"), NextToken: aws.String("next")}).Return(
+			&eks.ListAddonsOutput{Addons: []string{"coredns"}}, nil)
+		eksServiceMock.EXPECT().DescribeAddon(ctx, &eks.DescribeAddonInput{ClusterName: aws.String("test"), AddonName: aws.String("vpc-cni")}).Return(
+			&eks.DescribeAddonOutput{Addon: &ekstypes.Addon{AddonName: aws.String("vpc-cni")}}, nil)
+		eksServiceMock.EXPECT().DescribeAddon(ctx, &eks.DescribeAddonInput{ClusterName: aws.String("test"), AddonName: aws.String("coredns")}).Return(
+			&eks.DescribeAddonOutput{Addon: &ekstypes.Addon{AddonName: aws.String("coredns")}}, nil)
+
+		addons, err := GetAddons(ctx, &GetAddonsOpts{EKSService: eksServiceMock, ClusterName: "test"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(addons).To(HaveLen(2))
+		Expect(aws.ToString(addons[0].AddonName)).To(Equal("vpc-cni"))
+		Expect(aws.ToString(addons[1].AddonName)).To(Equal("coredns"))
+	})
+
+	It("should fail if ListAddons returns error", func() {
+		eksServiceMock.EXPECT().ListAddons(ctx, gomock.Any()).Return(nil, errors.New("error"))
+
+		_, err := GetAddons(ctx, &GetAddonsOpts{EKSService: eksServiceMock, ClusterName: "test"})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("GetInsightWarnings", func() {
+	var (
+		mockController *gomock.Controller
+		eksServiceMock *mock_services.MockEKSServiceInterface
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should list the insights with warnings from all pages", func() {
+		filter := &ekstypes.InsightsFilter{
+			Statuses: []ekstypes.InsightStatusValue{ekstypes.InsightStatusValueWarning, ekstypes.InsightStatusValueError},
+		}
+		eksServiceMock.EXPECT().ListInsights(ctx, &eks.ListInsightsInput{ClusterName: aws.String("test"), Filter: filter}).Return(
+			&eks.ListInsightsOutput{
+				Insights: []ekstypes.InsightSummary{{
+					Name:          aws.String("Kubelet version skew"),
+					InsightStatus: &ekstypes.InsightStatus{Status: ekstypes.InsightStatusValueWarning},
+				}},
+				NextToken: aws.String("next"),
+			}, nil)
+		eksServiceMock.EXPECT().ListInsights(ctx, &eks.ListInsightsInput{ClusterName: aws.String("test"), Filter: filter, NextToken: aws.String("next")}).Return(
+			&eks.ListInsightsOutput{
+				Insights: []ekstypes.InsightSummary{{
+					Name:          aws.String("Deprecated APIs removed in Kubernetes v1.32"),
+					InsightStatus: &ekstypes.InsightStatus{Status: ekstypes.InsightStatusValueError, Reason: aws.String("Deprecated API usage detected.")},
+				}},
+			}, nil)
+
+		warnings, err := GetInsightWarnings(ctx, &GetInsightWarningsOpts{EKSService: eksServiceMock, ClusterName: "test"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(warnings).To(Equal([]string{
+			"Deprecated APIs removed in Kubernetes v1.32 (ERROR): Deprecated API usage detected.",
+			"Kubelet version skew (WARNING)",
+		}))
+	})
+
+	It("should fail if ListInsights returns error", func() {
+		eksServiceMock.EXPECT().ListInsights(ctx, gomock.Any()).Return(nil, errors.New("error"))
+
+		_, err := GetInsightWarnings(ctx, &GetInsightWarningsOpts{EKSService: eksServiceMock, ClusterName: "test"})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("GetFargateProfiles", func() {
+	var (
+		mockController *gomock.Controller
+		eksServiceMock *mock_services.MockEKSServiceInterface
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should describe the Fargate profiles", func() {
+		eksServiceMock.EXPECT().ListFargateProfiles(ctx, &eks.ListFargateProfilesInput{ClusterName: aws.String("test")}).Return(
+			&eks.ListFargateProfilesOutput{FargateProfileNames: []string{"system"}}, nil)
+		eksServiceMock.EXPECT().DescribeFargateProfile(ctx, &eks.DescribeFargateProfileInput{ClusterName: aws.String("test"), FargateProfileName: aws.String("system")}).Return(
+			&eks.DescribeFargateProfileOutput{FargateProfile: &ekstypes.FargateProfile{FargateProfileName: aws.String("system")}}, nil)
+
+		profiles, err := GetFargateProfiles(ctx, &GetFargateProfilesOpts{EKSService: eksServiceMock, ClusterName: "test"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(profiles).To(HaveLen(1))
+		Expect(aws.ToString(profiles[0].FargateProfileName)).To(Equal("system"))
+	})
+
+	It("should fail if DescribeFargateProfile returns error", func() {
+		eksServiceMock.EXPECT().ListFargateProfiles(ctx, gomock.Any()).Return(
+			&eks.ListFargateProfilesOutput{FargateProfileNames: []string{"system"}}, nil)
+		eksServiceMock.EXPECT().DescribeFargateProfile(ctx, gomock.Any()).Return(nil, errors.New("error"))
+
+		_, err := GetFargateProfiles(ctx, &GetFargateProfilesOpts{EKSService: eksServiceMock, ClusterName: "test"})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("GetUpdate", func() {
+	var (
+		mockController *gomock.Controller
+		eksServiceMock *mock_services.MockEKSServiceInterface
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should describe node group updates by their node group", func() {
+		eksServiceMock.EXPECT().DescribeUpdate(ctx, &eks.DescribeUpdateInput{
+			Name:          aws.String("test"),
+			NodegroupName: aws.String("ng1"),
+			UpdateId:      aws.String("version-update"),
+		}).Return(&eks.DescribeUpdateOutput{Update: &ekstypes.Update{
+			Id:     aws.String("version-update"),
+			Status: ekstypes.UpdateStatusFailed,
+		}}, nil)
+
+		update, err := GetUpdate(ctx, &GetUpdateOpts{
+			EKSService:  eksServiceMock,
+			ClusterName: "test",
+			Update:      eksv1.EKSUpdate{ID: "version-update", NodegroupName: "ng1"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(update.Status).To(Equal(ekstypes.UpdateStatusFailed))
+	})
+
+	It("should return nil if the update no longer exists", func() {
+		eksServiceMock.EXPECT().DescribeUpdate(ctx, &eks.DescribeUpdateInput{
+			Name:      aws.String("test"),
+			AddonName: aws.String("aws-ebs-csi-driver"),
+			UpdateId:  aws.String("addon-update"),
+		}).Return(nil, &ekstypes.ResourceNotFoundException{})
+
+		update, err := GetUpdate(ctx, &GetUpdateOpts{
+			EKSService:  eksServiceMock,
+			ClusterName: "test",
+			Update:      eksv1.EKSUpdate{ID: "addon-update", AddonName: "aws-ebs-csi-driver"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(update).To(BeNil())
+	})
+
+	It("should fail if DescribeUpdate returns error", func() {
+		eksServiceMock.EXPECT().DescribeUpdate(ctx, gomock.Any()).Return(nil, errors.New("error"))
+
+		_, err := GetUpdate(ctx, &GetUpdateOpts{EKSService: eksServiceMock, ClusterName: "test", Update: eksv1.EKSUpdate{ID: "update"}})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("GetResourceUsage", func() {
+	var (
+		mockController *gomock.Controller
+		ec2ServiceMock *mock_services.MockEC2ServiceInterface
+		config         *eksv1.EKSClusterConfig
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		ec2ServiceMock = mock_services.NewMockEC2ServiceInterface(mockController)
+		config = &eksv1.EKSClusterConfig{Status: eksv1.EKSClusterConfigStatus{
+			ManagedLaunchTemplateID: "lt-managed",
+			CloudFormationStacks:    []eksv1.CloudFormationStack{{Name: "test-eks-service-role"}, {Name: "test-vpc"}},
+			NetworkFieldsSource:     "generated",
+			VirtualNetwork:          "vpc-1",
+			ClusterSecurityGroup:    "sg-1",
+		}}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should count the resources of the cluster", func() {
+		ec2ServiceMock.EXPECT().DescribeNatGateways(ctx, gomock.Any()).Return(&ec2.DescribeNatGatewaysOutput{
+			NatGateways: []ec2types.NatGateway{
+				{NatGatewayAddresses: []ec2types.NatGatewayAddress{{AllocationId: aws.String("eipalloc-1")}}},
+				{NatGatewayAddresses: []ec2types.NatGatewayAddress{{AllocationId: aws.String("eipalloc-2")}}},
+			},
+		}, nil)
+		ec2ServiceMock.EXPECT().DescribeNetworkInterfaces(ctx, &ec2.DescribeNetworkInterfacesInput{
+			Filters: []ec2types.Filter{{Name: aws.String("group-id"), Values: []string{"sg-1"}}},
+		}).Return(&ec2.DescribeNetworkInterfacesOutput{
+			NetworkInterfaces: []ec2types.NetworkInterface{{}, {}},
+			NextToken:         aws.String("next"),
+		}, nil)
+		ec2ServiceMock.EXPECT().DescribeNetworkInterfaces(ctx, &ec2.DescribeNetworkInterfacesInput{
+			Filters:   []ec2types.Filter{{Name: aws.String("group-id"), Values: []string{"sg-1"}}},
+			NextToken: aws.String("next"),
+		}).Return(&ec2.DescribeNetworkInterfacesOutput{NetworkInterfaces: []ec2types.NetworkInterface{{}}}, nil)
+
+		usage, err := GetResourceUsage(ctx, &GetResourceUsageOpts{
+			EC2Service: ec2ServiceMock,
+			Config:     config,
+			Nodegroups: []*ekstypes.Nodegroup{
+				{LaunchTemplate: &ekstypes.LaunchTemplateSpecification{Id: aws.String("lt-managed")}},
+				{LaunchTemplate: &ekstypes.LaunchTemplateSpecification{Id: aws.String("lt-custom")}},
+				{},
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*usage).To(Equal(eksv1.ResourceUsage{
+			LaunchTemplates:      2,
+			CloudFormationStacks: 2,
+			ElasticIPs:           2,
+			NetworkInterfaces:    3,
+		}))
+	})
+
+	It("should not count the elastic IPs of provided VPCs", func() {
+		config.Status.NetworkFieldsSource = "provided"
+		ec2ServiceMock.EXPECT().DescribeNetworkInterfaces(ctx, gomock.Any()).Return(&ec2.DescribeNetworkInterfacesOutput{}, nil)
+
+		usage, err := GetResourceUsage(ctx, &GetResourceUsageOpts{EC2Service: ec2ServiceMock, Config: config})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(usage.ElasticIPs).To(BeZero())
+	})
+
+	It("should fail if DescribeNetworkInterfaces returns error", func() {
+		config.Status.NetworkFieldsSource = "provided"
+		ec2ServiceMock.EXPECT().DescribeNetworkInterfaces(ctx, gomock.Any()).Return(nil, errors.New("error"))
+
+		_, err := GetResourceUsage(ctx, &GetResourceUsageOpts{EC2Service: ec2ServiceMock, Config: config})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("GetClusterToken", func() {
+	var (
+		mockController *gomock.Controller
+		stsServiceMock *mock_services.MockSTSServiceInterface
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		stsServiceMock = mock_services.NewMockSTSServiceInterface(mockController)
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should encode the presigned request", func() {
+		url := "https://sts.us-west-2.amazonaws.com/?Action=GetCallerIdentity&Version=2011-06-15"
+		stsServiceMock.EXPECT().PresignGetCallerIdentity(ctx, gomock.Any(), gomock.Any()).Return(&v4.PresignedHTTPRequest{URL: url}, nil)
+
+		token, err := GetClusterToken(ctx, &GetClusterTokenOpts{STSService: stsServiceMock, ClusterName: "test"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(token).To(HavePrefix(clusterTokenPrefix))
+		decoded, err := base64.RawURLEncoding.DecodeString(token[len(clusterTokenPrefix):])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(decoded)).To(Equal(url))
+	})
+
+	It("should fail if the request can't be presigned", func() {
+		stsServiceMock.EXPECT().PresignGetCallerIdentity(ctx, gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+		_, err := GetClusterToken(ctx, &GetClusterTokenOpts{STSService: stsServiceMock, ClusterName: "test"})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("GetNodegroupRollout", func() {
+	var (
+		mockController *gomock.Controller
+		eksServiceMock *mock_services.MockEKSServiceInterface
+		ec2ServiceMock *mock_services.MockEC2ServiceInterface
+		opts           *GetNodegroupRolloutOpts
+		startTime      = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		ec2ServiceMock = mock_services.NewMockEC2ServiceInterface(mockController)
+		opts = &GetNodegroupRolloutOpts{
+			EKSService:  eksServiceMock,
+			EC2Service:  ec2ServiceMock,
+			ClusterName: "test",
+			Nodegroup: &ekstypes.Nodegroup{
+				NodegroupName: aws.String("ng1"),
+				ScalingConfig: &ekstypes.NodegroupScalingConfig{DesiredSize: aws.Int32(4)},
+				UpdateConfig:  &ekstypes.NodegroupUpdateConfig{MaxUnavailablePercentage: aws.Int32(50)},
+				Resources: &ekstypes.NodegroupResources{
+					AutoScalingGroups: []ekstypes.AutoScalingGroup{{Name: aws.String("asg1")}},
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should count the nodes launched since the version update started", func() {
+		eksServiceMock.EXPECT().ListUpdates(ctx, &eks.ListUpdatesInput{Name: aws.String("test"), NodegroupName: aws.String("ng1")}).Return(
+			&eks.ListUpdatesOutput{UpdateIds: []string{"config-update", "version-update"}}, nil)
+		eksServiceMock.EXPECT().DescribeUpdate(ctx, &eks.DescribeUpdateInput{
+			Name: aws.String("test"), NodegroupName: aws.String("ng1"), UpdateId: aws.String("config-update"),
+		}).Return(&eks.DescribeUpdateOutput{Update: &ekstypes.Update{
+			Id: aws.String("config-update"), Type: ekstypes.UpdateTypeConfigUpdate, Status: ekstypes.UpdateStatusInProgress,
+		}}, nil)
+		eksServiceMock.EXPECT().DescribeUpdate(ctx, &eks.DescribeUpdateInput{
+			Name: aws.String("test"), NodegroupName: aws.String("ng1"), UpdateId: aws.String("version-update"),
+		}).Return(&eks.DescribeUpdateOutput{Update: &ekstypes.Update{
+			Id: aws.String("version-update"), Type: ekstypes.UpdateTypeVersionUpdate, Status: ekstypes.UpdateStatusInProgress, CreatedAt: aws.Time(startTime),
+		}}, nil)
+		ec2ServiceMock.EXPECT().DescribeInstances(ctx, gomock.Any()).Return(&ec2.DescribeInstancesOutput{
+			Reservations: []ec2types.Reservation{
+				{Instances: []ec2types.Instance{
+					{LaunchTime: aws.Time(startTime.Add(-time.Hour))},
+					{LaunchTime: aws.Time(startTime.Add(-time.Hour))},
+					{LaunchTime: aws.Time(startTime.Add(5 * time.Minute))},
+					{LaunchTime: aws.Time(startTime.Add(10 * time.Minute))},
+					{LaunchTime: aws.Time(startTime.Add(10 * time.Minute))},
+				}},
+			},
+		}, nil)
+
+		rollout, err := GetNodegroupRollout(ctx, opts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rollout).To(Equal(&eksv1.NodeGroupRollout{
+			UpdateID:     "version-update",
+			StartTime:    "2024-01-01T12:00:00Z",
+			UpdatedNodes: 3,
+			TotalNodes:   4,
+			CurrentBatch: 2,
+			TotalBatches: 2,
+		}))
+	})
+
+	It("should check the recorded update first", func() {
+		opts.UpdateID = "version-update"
+		eksServiceMock.EXPECT().DescribeUpdate(ctx, gomock.Any()).Return(&eks.DescribeUpdateOutput{Update: &ekstypes.Update{
+			Id: aws.String("version-update"), Type: ekstypes.UpdateTypeVersionUpdate, Status: ekstypes.UpdateStatusInProgress, CreatedAt: aws.Time(startTime),
+		}}, nil)
+		ec2ServiceMock.EXPECT().DescribeInstances(ctx, gomock.Any()).Return(&ec2.DescribeInstancesOutput{}, nil)
+
+		rollout, err := GetNodegroupRollout(ctx, opts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rollout.UpdatedNodes).To(BeEquivalentTo(0))
+		Expect(rollout.CurrentBatch).To(BeEquivalentTo(1))
+	})
+
+	It("should return nil if no version update is in progress", func() {
+		eksServiceMock.EXPECT().ListUpdates(ctx, gomock.Any()).Return(&eks.ListUpdatesOutput{UpdateIds: []string{"version-update"}}, nil)
+		eksServiceMock.EXPECT().DescribeUpdate(ctx, gomock.Any()).Return(&eks.DescribeUpdateOutput{Update: &ekstypes.Update{
+			Id: aws.String("version-update"), Type: ekstypes.UpdateTypeVersionUpdate, Status: ekstypes.UpdateStatusSuccessful,
+		}}, nil)
+
+		rollout, err := GetNodegroupRollout(ctx, opts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rollout).To(BeNil())
+	})
+
+	It("should fail if ListUpdates returns error", func() {
+		eksServiceMock.EXPECT().ListUpdates(ctx, gomock.Any()).Return(nil, errors.New("error"))
+
+		_, err := GetNodegroupRollout(ctx, opts)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -11,7 +11,9 @@ type CloudFormationServiceInterface interface {
 	DescribeStacks(ctx context.Context, input *cloudformation.DescribeStacksInput) (*cloudformation.DescribeStacksOutput, error)
 	DeleteStack(ctx context.Context, input *cloudformation.DeleteStackInput) (*cloudformation.DeleteStackOutput, error)
 	CreateStack(ctx context.Context, input *cloudformation.CreateStackInput) (*cloudformation.CreateStackOutput, error)
+	UpdateStack(ctx context.Context, input *cloudformation.UpdateStackInput) (*cloudformation.UpdateStackOutput, error)
 	DescribeStackEvents(ctx context.Context, input *cloudformation.DescribeStackEventsInput) (*cloudformation.DescribeStackEventsOutput, error)
+	DescribeStackResources(ctx context.Context, input *cloudformation.DescribeStackResourcesInput) (*cloudformation.DescribeStackResourcesOutput, error)
 }
 
 type cloudFormationService struct {
@@ -20,7 +22,9 @@ type cloudFormationService struct {
 
 func NewCloudFormationService(cfg aws.Config) CloudFormationServiceInterface {
 	return &cloudFormationService{
-		svc: cloudformation.NewFromConfig(cfg),
+		svc: cloudformation.NewFromConfig(cfg, func(o *cloudformation.Options) {
+			applyEndpoint("cloudformation", cfg.Region, &o.BaseEndpoint, &o.EndpointOptions.UseFIPSEndpoint)
+		}),
 	}
 }
 
@@ -36,6 +40,14 @@ func (c *cloudFormationService) CreateStack(ctx context.Context, input *cloudfor
 	return c.svc.CreateStack(ctx, input)
 }
 
+func (c *cloudFormationService) UpdateStack(ctx context.Context, input *cloudformation.UpdateStackInput) (*cloudformation.UpdateStackOutput, error) {
+	return c.svc.UpdateStack(ctx, input)
+}
+
 func (c *cloudFormationService) DescribeStackEvents(ctx context.Context, input *cloudformation.DescribeStackEventsInput) (*cloudformation.DescribeStackEventsOutput, error) {
 	return c.svc.DescribeStackEvents(ctx, input)
 }
+
+func (c *cloudFormationService) DescribeStackResources(ctx context.Context, input *cloudformation.DescribeStackResourcesInput) (*cloudformation.DescribeStackResourcesOutput, error) {
+	return c.svc.DescribeStackResources(ctx, input)
+}
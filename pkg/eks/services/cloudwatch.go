@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+const (
+	cloudWatchPutMetricAlarmTarget = "GraniteServiceVersion20100801.PutMetricAlarm"
+	cloudWatchDeleteAlarmsTarget   = "GraniteServiceVersion20100801.DeleteAlarms"
+)
+
+type CloudWatchServiceInterface interface {
+	PutMetricAlarm(ctx context.Context, input *PutMetricAlarmInput) (*PutMetricAlarmOutput, error)
+	DeleteAlarms(ctx context.Context, input *DeleteAlarmsInput) (*DeleteAlarmsOutput, error)
+}
+
+// PutMetricAlarmInput mirrors the input of the PutMetricAlarm operation of the CloudWatch API.
+type PutMetricAlarmInput struct {
+	AlarmName          *string  `json:"AlarmName"`
+	AlarmDescription   *string  `json:"AlarmDescription,omitempty"`
+	Namespace          *string  `json:"Namespace"`
+	MetricName         *string  `json:"MetricName"`
+	Statistic          string   `json:"Statistic"`
+	Period             *int32   `json:"Period"`
+	EvaluationPeriods  *int32   `json:"EvaluationPeriods"`
+	Threshold          *float64 `json:"Threshold"`
+	ComparisonOperator string   `json:"ComparisonOperator"`
+	TreatMissingData   *string  `json:"TreatMissingData,omitempty"`
+	AlarmActions       []string `json:"AlarmActions,omitempty"`
+	Tags               []Tag    `json:"Tags,omitempty"`
+}
+
+// Tag is a key value pair tagging a CloudWatch resource.
+type Tag struct {
+	Key   *string `json:"Key"`
+	Value *string `json:"Value"`
+}
+
+// PutMetricAlarmOutput mirrors the output of the PutMetricAlarm operation, which is empty.
+type PutMetricAlarmOutput struct{}
+
+// DeleteAlarmsInput mirrors the input of the DeleteAlarms operation of the CloudWatch API. Alarms that don't exist are
+// ignored.
+type DeleteAlarmsInput struct {
+	AlarmNames []string `json:"AlarmNames"`
+}
+
+// DeleteAlarmsOutput mirrors the output of the DeleteAlarms operation, which is empty.
+type DeleteAlarmsOutput struct{}
+
+type cloudWatchService struct {
+	api *jsonAPI
+}
+
+func NewCloudWatchService(cfg aws.Config) CloudWatchServiceInterface {
+	return &cloudWatchService{
+		api: &jsonAPI{
+			cfg:         cfg,
+			signer:      v4.NewSigner(),
+			service:     "monitoring",
+			region:      cfg.Region,
+			endpoint:    jsonAPIEndpoint("monitoring", cfg.Region),
+			contentType: "application/x-amz-json-1.0",
+		},
+	}
+}
+
+func (c *cloudWatchService) PutMetricAlarm(ctx context.Context, input *PutMetricAlarmInput) (*PutMetricAlarmOutput, error) {
+	output := &PutMetricAlarmOutput{}
+	if err := c.api.call(ctx, cloudWatchPutMetricAlarmTarget, input, output); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+func (c *cloudWatchService) DeleteAlarms(ctx context.Context, input *DeleteAlarmsInput) (*DeleteAlarmsOutput, error) {
+	output := &DeleteAlarmsOutput{}
+	if err := c.api.call(ctx, cloudWatchDeleteAlarmsTarget, input, output); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
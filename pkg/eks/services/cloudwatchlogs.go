@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+const (
+	cloudWatchLogsDeleteLogGroupTarget        = "Logs_20140328.DeleteLogGroup"
+	cloudWatchLogsPutRetentionPolicyTarget    = "Logs_20140328.PutRetentionPolicy"
+	cloudWatchLogsDeleteRetentionPolicyTarget = "Logs_20140328.DeleteRetentionPolicy"
+	cloudWatchLogsPutMetricFilterTarget       = "Logs_20140328.PutMetricFilter"
+	cloudWatchLogsDeleteMetricFilterTarget    = "Logs_20140328.DeleteMetricFilter"
+
+	// CloudWatchLogsResourceNotFound is the error code returned for log groups that don't exist
+	CloudWatchLogsResourceNotFound = "ResourceNotFoundException"
+)
+
+type CloudWatchLogsServiceInterface interface {
+	DeleteLogGroup(ctx context.Context, input *DeleteLogGroupInput) (*DeleteLogGroupOutput, error)
+	PutRetentionPolicy(ctx context.Context, input *PutRetentionPolicyInput) (*PutRetentionPolicyOutput, error)
+	DeleteRetentionPolicy(ctx context.Context, input *DeleteRetentionPolicyInput) (*DeleteRetentionPolicyOutput, error)
+	PutMetricFilter(ctx context.Context, input *PutMetricFilterInput) (*PutMetricFilterOutput, error)
+	DeleteMetricFilter(ctx context.Context, input *DeleteMetricFilterInput) (*DeleteMetricFilterOutput, error)
+}
+
+// DeleteLogGroupInput mirrors the input of the DeleteLogGroup operation of the CloudWatch Logs API.
+type DeleteLogGroupInput struct {
+	LogGroupName *string `json:"logGroupName"`
+}
+
+// DeleteLogGroupOutput mirrors the output of the DeleteLogGroup operation, which is empty.
+type DeleteLogGroupOutput struct{}
+
+// PutRetentionPolicyInput mirrors the input of the PutRetentionPolicy operation of the CloudWatch Logs API.
+type PutRetentionPolicyInput struct {
+	LogGroupName    *string `json:"logGroupName"`
+	RetentionInDays *int32  `json:"retentionInDays"`
+}
+
+// PutRetentionPolicyOutput mirrors the output of the PutRetentionPolicy operation, which is empty.
+type PutRetentionPolicyOutput struct{}
+
+// DeleteRetentionPolicyInput mirrors the input of the DeleteRetentionPolicy operation of the CloudWatch Logs API.
+type DeleteRetentionPolicyInput struct {
+	LogGroupName *string `json:"logGroupName"`
+}
+
+// DeleteRetentionPolicyOutput mirrors the output of the DeleteRetentionPolicy operation, which is empty.
+type DeleteRetentionPolicyOutput struct{}
+
+// PutMetricFilterInput mirrors the input of the PutMetricFilter operation of the CloudWatch Logs API.
+type PutMetricFilterInput struct {
+	LogGroupName          *string                `json:"logGroupName"`
+	FilterName            *string                `json:"filterName"`
+	FilterPattern         *string                `json:"filterPattern"`
+	MetricTransformations []MetricTransformation `json:"metricTransformations"`
+}
+
+// MetricTransformation publishes the log events matched by a metric filter as a metric.
+type MetricTransformation struct {
+	MetricName      *string  `json:"metricName"`
+	MetricNamespace *string  `json:"metricNamespace"`
+	MetricValue     *string  `json:"metricValue"`
+	DefaultValue    *float64 `json:"defaultValue,omitempty"`
+}
+
+// PutMetricFilterOutput mirrors the output of the PutMetricFilter operation, which is empty.
+type PutMetricFilterOutput struct{}
+
+// DeleteMetricFilterInput mirrors the input of the DeleteMetricFilter operation of the CloudWatch Logs API.
+type DeleteMetricFilterInput struct {
+	LogGroupName *string `json:"logGroupName"`
+	FilterName   *string `json:"filterName"`
+}
+
+// DeleteMetricFilterOutput mirrors the output of the DeleteMetricFilter operation, which is empty.
+type DeleteMetricFilterOutput struct{}
+
+type cloudWatchLogsService struct {
+	api *jsonAPI
+}
+
+func NewCloudWatchLogsService(cfg aws.Config) CloudWatchLogsServiceInterface {
+	return &cloudWatchLogsService{
+		api: &jsonAPI{
+			cfg:      cfg,
+			signer:   v4.NewSigner(),
+			service:  "logs",
+			region:   cfg.Region,
+			endpoint: jsonAPIEndpoint("logs", cfg.Region),
+		},
+	}
+}
+
+func (c *cloudWatchLogsService) DeleteLogGroup(ctx context.Context, input *DeleteLogGroupInput) (*DeleteLogGroupOutput, error) {
+	output := &DeleteLogGroupOutput{}
+	if err := c.api.call(ctx, cloudWatchLogsDeleteLogGroupTarget, input, output); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+func (c *cloudWatchLogsService) PutRetentionPolicy(ctx context.Context, input *PutRetentionPolicyInput) (*PutRetentionPolicyOutput, error) {
+	output := &PutRetentionPolicyOutput{}
+	if err := c.api.call(ctx, cloudWatchLogsPutRetentionPolicyTarget, input, output); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+func (c *cloudWatchLogsService) DeleteRetentionPolicy(ctx context.Context, input *DeleteRetentionPolicyInput) (*DeleteRetentionPolicyOutput, error) {
+	output := &DeleteRetentionPolicyOutput{}
+	if err := c.api.call(ctx, cloudWatchLogsDeleteRetentionPolicyTarget, input, output); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+func (c *cloudWatchLogsService) PutMetricFilter(ctx context.Context, input *PutMetricFilterInput) (*PutMetricFilterOutput, error) {
+	output := &PutMetricFilterOutput{}
+	if err := c.api.call(ctx, cloudWatchLogsPutMetricFilterTarget, input, output); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+func (c *cloudWatchLogsService) DeleteMetricFilter(ctx context.Context, input *DeleteMetricFilterInput) (*DeleteMetricFilterOutput, error) {
+	output := &DeleteMetricFilterOutput{}
+	if err := c.api.call(ctx, cloudWatchLogsDeleteMetricFilterTarget, input, output); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
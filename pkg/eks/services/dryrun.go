@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/sirupsen/logrus"
+)
+
+// readOnlyOperationPrefixes are the prefixes of the names of AWS operations that don't change anything, all other
+// operations are skipped in dry run mode
+var readOnlyOperationPrefixes = []string{"Describe", "Get", "List"}
+
+var dryRun atomic.Bool
+
+// DryRunError is returned instead of sending a request that would change something in AWS while the operator runs in dry
+// run mode.
+type DryRunError struct {
+	// Operation is the service and name of the skipped operation, e.g. EKS CreateCluster
+	Operation string
+}
+
+func (e *DryRunError) Error() string {
+	return fmt.Sprintf("dry run, skipped AWS request [%s]", e.Operation)
+}
+
+// SetDryRun configures whether the services created from configs returned by WithDryRun skip the requests that would
+// change something in AWS.
+func SetDryRun(enabled bool) {
+	dryRun.Store(enabled)
+}
+
+// DryRunEnabled returns whether the operator runs in dry run mode, for changes it makes to clusters other than through
+// AWS requests.
+func DryRunEnabled() bool {
+	return dryRun.Load()
+}
+
+// WithDryRun returns the config with the services created from it logging and skipping the requests that would change
+// something in AWS if dry run mode is enabled, these requests fail with a DryRunError. Read only requests are sent as
+// they are.
+func WithDryRun(cfg aws.Config) aws.Config {
+	if !dryRun.Load() {
+		return cfg
+	}
+	cfg.APIOptions = append(cfg.APIOptions[:len(cfg.APIOptions):len(cfg.APIOptions)], func(stack *middleware.Stack) error {
+		return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("DryRun", skipMutatingRequest), middleware.After)
+	})
+	return cfg
+}
+
+func skipMutatingRequest(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+	if err := skipMutation(awsmiddleware.GetServiceID(ctx), awsmiddleware.GetOperationName(ctx), in.Parameters); err != nil {
+		return middleware.InitializeOutput{}, middleware.Metadata{}, err
+	}
+	return next.HandleInitialize(ctx, in)
+}
+
+// skipMutation logs the operation and returns a DryRunError if dry run mode is enabled and the operation would change
+// something in AWS, it returns nil for operations that can be sent.
+func skipMutation(service, operationName string, params interface{}) error {
+	if !dryRun.Load() {
+		return nil
+	}
+	for _, prefix := range readOnlyOperationPrefixes {
+		if strings.HasPrefix(operationName, prefix) {
+			return nil
+		}
+	}
+
+	operation := fmt.Sprintf("%s %s", service, operationName)
+	payload, err := json.Marshal(params)
+	if err != nil {
+		payload = []byte(fmt.Sprintf("%+v", params))
+	}
+	logrus.Infof("Dry run, skipping AWS request [%s]: %s", operation, redactPayload(payload))
+	return &DryRunError{Operation: operation}
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDryRun(t *testing.T) {
+	t.Cleanup(func() { SetDryRun(false) })
+
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"cluster":{"name":"test"}}`))
+	}))
+	defer server.Close()
+
+	cfg := aws.Config{
+		Region:      "us-east-1",
+		Credentials: credentials.NewStaticCredentialsProvider("AKIA", "secret", ""),
+	}
+	assert.Empty(t, WithDryRun(cfg).APIOptions, "requests are sent as they are unless dry run mode is enabled")
+
+	SetDryRun(true)
+	hook := test.NewGlobal()
+	defer hook.Reset()
+	client := eks.NewFromConfig(WithDryRun(cfg), func(o *eks.Options) { o.BaseEndpoint = aws.String(server.URL) })
+
+	_, err := client.UpdateClusterConfig(context.Background(), &eks.UpdateClusterConfigInput{Name: aws.String("test")})
+	var dryRunErr *DryRunError
+	require.True(t, errors.As(err, &dryRunErr))
+	assert.Equal(t, "EKS UpdateClusterConfig", dryRunErr.Operation)
+	assert.Empty(t, requests)
+	require.Len(t, hook.AllEntries(), 1)
+	assert.Contains(t, hook.LastEntry().Message, `Dry run, skipping AWS request [EKS UpdateClusterConfig]: {"Name":"test",`)
+
+	logs := &cloudWatchLogsService{api: &jsonAPI{cfg: cfg, service: "logs", region: "us-east-1", endpoint: server.URL}}
+	_, err = logs.DeleteLogGroup(context.Background(), &DeleteLogGroupInput{LogGroupName: aws.String("/aws/eks/test/cluster")})
+	require.True(t, errors.As(err, &dryRunErr), "requests of APIs called through jsonAPI are skipped as well")
+	assert.Equal(t, "logs DeleteLogGroup", dryRunErr.Operation)
+	assert.Empty(t, requests)
+
+	output, err := client.DescribeCluster(context.Background(), &eks.DescribeClusterInput{Name: aws.String("test")})
+	require.NoError(t, err)
+	assert.Equal(t, "test", aws.ToString(output.Cluster.Name))
+	assert.Equal(t, []string{"GET /clusters/test"}, requests)
+}
@@ -15,6 +15,15 @@ type EC2ServiceInterface interface {
 	DeleteLaunchTemplateVersions(ctx context.Context, input *ec2.DeleteLaunchTemplateVersionsInput) (*ec2.DeleteLaunchTemplateVersionsOutput, error)
 	DescribeLaunchTemplateVersions(ctx context.Context, input *ec2.DescribeLaunchTemplateVersionsInput) (*ec2.DescribeLaunchTemplateVersionsOutput, error)
 	DescribeImages(ctx context.Context, input *ec2.DescribeImagesInput) (*ec2.DescribeImagesOutput, error)
+	DescribeSubnets(ctx context.Context, input *ec2.DescribeSubnetsInput) (*ec2.DescribeSubnetsOutput, error)
+	DescribeInstances(ctx context.Context, input *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error)
+	CreateTags(ctx context.Context, input *ec2.CreateTagsInput) (*ec2.CreateTagsOutput, error)
+	DescribeTags(ctx context.Context, input *ec2.DescribeTagsInput) (*ec2.DescribeTagsOutput, error)
+	DeleteTags(ctx context.Context, input *ec2.DeleteTagsInput) (*ec2.DeleteTagsOutput, error)
+	DescribeNatGateways(ctx context.Context, input *ec2.DescribeNatGatewaysInput) (*ec2.DescribeNatGatewaysOutput, error)
+	DescribeNetworkInterfaces(ctx context.Context, input *ec2.DescribeNetworkInterfacesInput) (*ec2.DescribeNetworkInterfacesOutput, error)
+	CreatePlacementGroup(ctx context.Context, input *ec2.CreatePlacementGroupInput) (*ec2.CreatePlacementGroupOutput, error)
+	DescribePlacementGroups(ctx context.Context, input *ec2.DescribePlacementGroupsInput) (*ec2.DescribePlacementGroupsOutput, error)
 }
 
 type ec2Service struct {
@@ -23,7 +32,9 @@ type ec2Service struct {
 
 func NewEC2Service(cfg aws.Config) EC2ServiceInterface {
 	return &ec2Service{
-		svc: ec2.NewFromConfig(cfg),
+		svc: ec2.NewFromConfig(cfg, func(o *ec2.Options) {
+			applyEndpoint("ec2", cfg.Region, &o.BaseEndpoint, &o.EndpointOptions.UseFIPSEndpoint)
+		}),
 	}
 }
 
@@ -54,3 +65,39 @@ func (c *ec2Service) DeleteLaunchTemplateVersions(ctx context.Context, input *ec
 func (c *ec2Service) DescribeImages(ctx context.Context, input *ec2.DescribeImagesInput) (*ec2.DescribeImagesOutput, error) {
 	return c.svc.DescribeImages(ctx, input)
 }
+
+func (c *ec2Service) DescribeSubnets(ctx context.Context, input *ec2.DescribeSubnetsInput) (*ec2.DescribeSubnetsOutput, error) {
+	return c.svc.DescribeSubnets(ctx, input)
+}
+
+func (c *ec2Service) DescribeInstances(ctx context.Context, input *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error) {
+	return c.svc.DescribeInstances(ctx, input)
+}
+
+func (c *ec2Service) CreateTags(ctx context.Context, input *ec2.CreateTagsInput) (*ec2.CreateTagsOutput, error) {
+	return c.svc.CreateTags(ctx, input)
+}
+
+func (c *ec2Service) DescribeTags(ctx context.Context, input *ec2.DescribeTagsInput) (*ec2.DescribeTagsOutput, error) {
+	return c.svc.DescribeTags(ctx, input)
+}
+
+func (c *ec2Service) DeleteTags(ctx context.Context, input *ec2.DeleteTagsInput) (*ec2.DeleteTagsOutput, error) {
+	return c.svc.DeleteTags(ctx, input)
+}
+
+func (c *ec2Service) DescribeNatGateways(ctx context.Context, input *ec2.DescribeNatGatewaysInput) (*ec2.DescribeNatGatewaysOutput, error) {
+	return c.svc.DescribeNatGateways(ctx, input)
+}
+
+func (c *ec2Service) DescribeNetworkInterfaces(ctx context.Context, input *ec2.DescribeNetworkInterfacesInput) (*ec2.DescribeNetworkInterfacesOutput, error) {
+	return c.svc.DescribeNetworkInterfaces(ctx, input)
+}
+
+func (c *ec2Service) CreatePlacementGroup(ctx context.Context, input *ec2.CreatePlacementGroupInput) (*ec2.CreatePlacementGroupOutput, error) {
+	return c.svc.CreatePlacementGroup(ctx, input)
+}
+
+func (c *ec2Service) DescribePlacementGroups(ctx context.Context, input *ec2.DescribePlacementGroupsInput) (*ec2.DescribePlacementGroupsOutput, error) {
+	return c.svc.DescribePlacementGroups(ctx, input)
+}
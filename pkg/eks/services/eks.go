@@ -20,10 +20,34 @@ type EKSServiceInterface interface {
 	DeleteNodegroup(ctx context.Context, input *eks.DeleteNodegroupInput) (*eks.DeleteNodegroupOutput, error)
 	DescribeNodegroup(ctx context.Context, input *eks.DescribeNodegroupInput) (*eks.DescribeNodegroupOutput, error)
 	UpdateNodegroupVersion(ctx context.Context, input *eks.UpdateNodegroupVersionInput) (*eks.UpdateNodegroupVersionOutput, error)
+	ListUpdates(ctx context.Context, input *eks.ListUpdatesInput) (*eks.ListUpdatesOutput, error)
+	DescribeUpdate(ctx context.Context, input *eks.DescribeUpdateInput) (*eks.DescribeUpdateOutput, error)
 	TagResource(ctx context.Context, input *eks.TagResourceInput) (*eks.TagResourceOutput, error)
 	UntagResource(ctx context.Context, input *eks.UntagResourceInput) (*eks.UntagResourceOutput, error)
 	CreateAddon(ctx context.Context, input *eks.CreateAddonInput) (*eks.CreateAddonOutput, error)
 	DescribeAddon(ctx context.Context, input *eks.DescribeAddonInput) (*eks.DescribeAddonOutput, error)
+	DeleteAddon(ctx context.Context, input *eks.DeleteAddonInput) (*eks.DeleteAddonOutput, error)
+	UpdateAddon(ctx context.Context, input *eks.UpdateAddonInput) (*eks.UpdateAddonOutput, error)
+	ListAddons(ctx context.Context, input *eks.ListAddonsInput) (*eks.ListAddonsOutput, error)
+	ListFargateProfiles(ctx context.Context, input *eks.ListFargateProfilesInput) (*eks.ListFargateProfilesOutput, error)
+	DescribeFargateProfile(ctx context.Context, input *eks.DescribeFargateProfileInput) (*eks.DescribeFargateProfileOutput, error)
+	CreatePodIdentityAssociation(ctx context.Context, input *eks.CreatePodIdentityAssociationInput) (*eks.CreatePodIdentityAssociationOutput, error)
+	ListPodIdentityAssociations(ctx context.Context, input *eks.ListPodIdentityAssociationsInput) (*eks.ListPodIdentityAssociationsOutput, error)
+	DescribePodIdentityAssociation(ctx context.Context, input *eks.DescribePodIdentityAssociationInput) (*eks.DescribePodIdentityAssociationOutput, error)
+	UpdatePodIdentityAssociation(ctx context.Context, input *eks.UpdatePodIdentityAssociationInput) (*eks.UpdatePodIdentityAssociationOutput, error)
+	DeletePodIdentityAssociation(ctx context.Context, input *eks.DeletePodIdentityAssociationInput) (*eks.DeletePodIdentityAssociationOutput, error)
+	AssociateIdentityProviderConfig(ctx context.Context, input *eks.AssociateIdentityProviderConfigInput) (*eks.AssociateIdentityProviderConfigOutput, error)
+	DisassociateIdentityProviderConfig(ctx context.Context, input *eks.DisassociateIdentityProviderConfigInput) (*eks.DisassociateIdentityProviderConfigOutput, error)
+	ListIdentityProviderConfigs(ctx context.Context, input *eks.ListIdentityProviderConfigsInput) (*eks.ListIdentityProviderConfigsOutput, error)
+	DescribeIdentityProviderConfig(ctx context.Context, input *eks.DescribeIdentityProviderConfigInput) (*eks.DescribeIdentityProviderConfigOutput, error)
+	AssociateEncryptionConfig(ctx context.Context, input *eks.AssociateEncryptionConfigInput) (*eks.AssociateEncryptionConfigOutput, error)
+	ListInsights(ctx context.Context, input *eks.ListInsightsInput) (*eks.ListInsightsOutput, error)
+	CreateAccessEntry(ctx context.Context, input *eks.CreateAccessEntryInput) (*eks.CreateAccessEntryOutput, error)
+	DeleteAccessEntry(ctx context.Context, input *eks.DeleteAccessEntryInput) (*eks.DeleteAccessEntryOutput, error)
+	DescribeAccessEntry(ctx context.Context, input *eks.DescribeAccessEntryInput) (*eks.DescribeAccessEntryOutput, error)
+	ListAccessEntries(ctx context.Context, input *eks.ListAccessEntriesInput) (*eks.ListAccessEntriesOutput, error)
+	AssociateAccessPolicy(ctx context.Context, input *eks.AssociateAccessPolicyInput) (*eks.AssociateAccessPolicyOutput, error)
+	ListAssociatedAccessPolicies(ctx context.Context, input *eks.ListAssociatedAccessPoliciesInput) (*eks.ListAssociatedAccessPoliciesOutput, error)
 }
 
 type eksService struct {
@@ -32,7 +56,9 @@ type eksService struct {
 
 func NewEKSService(cfg aws.Config) EKSServiceInterface {
 	return &eksService{
-		svc: eks.NewFromConfig(cfg),
+		svc: eks.NewFromConfig(cfg, func(o *eks.Options) {
+			applyEndpoint("eks", cfg.Region, &o.BaseEndpoint, &o.EndpointOptions.UseFIPSEndpoint)
+		}),
 	}
 }
 
@@ -92,6 +118,14 @@ func (c *eksService) UpdateNodegroupVersion(ctx context.Context, input *eks.Upda
 	return c.svc.UpdateNodegroupVersion(ctx, input)
 }
 
+func (c *eksService) ListUpdates(ctx context.Context, input *eks.ListUpdatesInput) (*eks.ListUpdatesOutput, error) {
+	return c.svc.ListUpdates(ctx, input)
+}
+
+func (c *eksService) DescribeUpdate(ctx context.Context, input *eks.DescribeUpdateInput) (*eks.DescribeUpdateOutput, error) {
+	return c.svc.DescribeUpdate(ctx, input)
+}
+
 func (c *eksService) CreateAddon(ctx context.Context, input *eks.CreateAddonInput) (*eks.CreateAddonOutput, error) {
 	return c.svc.CreateAddon(ctx, input)
 }
@@ -99,3 +133,91 @@ func (c *eksService) CreateAddon(ctx context.Context, input *eks.CreateAddonInpu
 func (c *eksService) DescribeAddon(ctx context.Context, input *eks.DescribeAddonInput) (*eks.DescribeAddonOutput, error) {
 	return c.svc.DescribeAddon(ctx, input)
 }
+
+func (c *eksService) DeleteAddon(ctx context.Context, input *eks.DeleteAddonInput) (*eks.DeleteAddonOutput, error) {
+	return c.svc.DeleteAddon(ctx, input)
+}
+
+func (c *eksService) UpdateAddon(ctx context.Context, input *eks.UpdateAddonInput) (*eks.UpdateAddonOutput, error) {
+	return c.svc.UpdateAddon(ctx, input)
+}
+
+func (c *eksService) ListAddons(ctx context.Context, input *eks.ListAddonsInput) (*eks.ListAddonsOutput, error) {
+	return c.svc.ListAddons(ctx, input)
+}
+
+func (c *eksService) ListFargateProfiles(ctx context.Context, input *eks.ListFargateProfilesInput) (*eks.ListFargateProfilesOutput, error) {
+	return c.svc.ListFargateProfiles(ctx, input)
+}
+
+func (c *eksService) DescribeFargateProfile(ctx context.Context, input *eks.DescribeFargateProfileInput) (*eks.DescribeFargateProfileOutput, error) {
+	return c.svc.DescribeFargateProfile(ctx, input)
+}
+
+func (c *eksService) CreatePodIdentityAssociation(ctx context.Context, input *eks.CreatePodIdentityAssociationInput) (*eks.CreatePodIdentityAssociationOutput, error) {
+	return c.svc.CreatePodIdentityAssociation(ctx, input)
+}
+
+func (c *eksService) ListPodIdentityAssociations(ctx context.Context, input *eks.ListPodIdentityAssociationsInput) (*eks.ListPodIdentityAssociationsOutput, error) {
+	return c.svc.ListPodIdentityAssociations(ctx, input)
+}
+
+func (c *eksService) DescribePodIdentityAssociation(ctx context.Context, input *eks.DescribePodIdentityAssociationInput) (*eks.DescribePodIdentityAssociationOutput, error) {
+	return c.svc.DescribePodIdentityAssociation(ctx, input)
+}
+
+func (c *eksService) UpdatePodIdentityAssociation(ctx context.Context, input *eks.UpdatePodIdentityAssociationInput) (*eks.UpdatePodIdentityAssociationOutput, error) {
+	return c.svc.UpdatePodIdentityAssociation(ctx, input)
+}
+
+func (c *eksService) DeletePodIdentityAssociation(ctx context.Context, input *eks.DeletePodIdentityAssociationInput) (*eks.DeletePodIdentityAssociationOutput, error) {
+	return c.svc.DeletePodIdentityAssociation(ctx, input)
+}
+
+func (c *eksService) AssociateIdentityProviderConfig(ctx context.Context, input *eks.AssociateIdentityProviderConfigInput) (*eks.AssociateIdentityProviderConfigOutput, error) {
+	return c.svc.AssociateIdentityProviderConfig(ctx, input)
+}
+
+func (c *eksService) DisassociateIdentityProviderConfig(ctx context.Context, input *eks.DisassociateIdentityProviderConfigInput) (*eks.DisassociateIdentityProviderConfigOutput, error) {
+	return c.svc.DisassociateIdentityProviderConfig(ctx, input)
+}
+
+func (c *eksService) ListIdentityProviderConfigs(ctx context.Context, input *eks.ListIdentityProviderConfigsInput) (*eks.ListIdentityProviderConfigsOutput, error) {
+	return c.svc.ListIdentityProviderConfigs(ctx, input)
+}
+
+func (c *eksService) DescribeIdentityProviderConfig(ctx context.Context, input *eks.DescribeIdentityProviderConfigInput) (*eks.DescribeIdentityProviderConfigOutput, error) {
+	return c.svc.DescribeIdentityProviderConfig(ctx, input)
+}
+
+func (c *eksService) AssociateEncryptionConfig(ctx context.Context, input *eks.AssociateEncryptionConfigInput) (*eks.AssociateEncryptionConfigOutput, error) {
+	return c.svc.AssociateEncryptionConfig(ctx, input)
+}
+
+func (c *eksService) ListInsights(ctx context.Context, input *eks.ListInsightsInput) (*eks.ListInsightsOutput, error) {
+	return c.svc.ListInsights(ctx, input)
+}
+
+func (c *eksService) CreateAccessEntry(ctx context.Context, input *eks.CreateAccessEntryInput) (*eks.CreateAccessEntryOutput, error) {
+	return c.svc.CreateAccessEntry(ctx, input)
+}
+
+func (c *eksService) DeleteAccessEntry(ctx context.Context, input *eks.DeleteAccessEntryInput) (*eks.DeleteAccessEntryOutput, error) {
+	return c.svc.DeleteAccessEntry(ctx, input)
+}
+
+func (c *eksService) DescribeAccessEntry(ctx context.Context, input *eks.DescribeAccessEntryInput) (*eks.DescribeAccessEntryOutput, error) {
+	return c.svc.DescribeAccessEntry(ctx, input)
+}
+
+func (c *eksService) ListAccessEntries(ctx context.Context, input *eks.ListAccessEntriesInput) (*eks.ListAccessEntriesOutput, error) {
+	return c.svc.ListAccessEntries(ctx, input)
+}
+
+func (c *eksService) AssociateAccessPolicy(ctx context.Context, input *eks.AssociateAccessPolicyInput) (*eks.AssociateAccessPolicyOutput, error) {
+	return c.svc.AssociateAccessPolicy(ctx, input)
+}
+
+func (c *eksService) ListAssociatedAccessPolicies(ctx context.Context, input *eks.ListAssociatedAccessPoliciesInput) (*eks.ListAssociatedAccessPoliciesOutput, error) {
+	return c.svc.ListAssociatedAccessPolicies(ctx, input)
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// endpointServices are the services whose endpoints can be overridden, oidc being the OIDC issuers of the clusters
+var endpointServices = []string{"eks", "ec2", "iam", "cloudformation", "sts", "logs", "monitoring", "pricing", "oidc"}
+
+// EndpointOpts overrides the endpoints the services send their requests to, for environments that can only reach AWS
+// through VPC endpoints or proxies, or have to use FIPS endpoints. It applies to the services of all clusters.
+type EndpointOpts struct {
+	// URLs of the services keyed by service name, {region} in a URL is replaced with the region of the cluster
+	URLs map[string]string
+	// FIPS sends the requests of the services without a URL to their FIPS endpoints
+	FIPS bool
+}
+
+var endpoints = struct {
+	sync.RWMutex
+	opts EndpointOpts
+}{}
+
+// ParseEndpointURLs parses a comma separated list of service=URL pairs, e.g.
+// eks=https://eks.{region}.example.com,sts=https://sts.example.com, into the URLs of EndpointOpts.
+func ParseEndpointURLs(value string) (map[string]string, error) {
+	urls := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		service, endpoint, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid endpoint [%s], must be in the format service=URL", pair)
+		}
+		urls[strings.TrimSpace(service)] = strings.TrimSpace(endpoint)
+	}
+	return urls, nil
+}
+
+// SetEndpoints configures the endpoints used by the services created afterwards.
+func SetEndpoints(opts EndpointOpts) error {
+	for service, endpoint := range opts.URLs {
+		if !slices.Contains(endpointServices, service) {
+			return fmt.Errorf("endpoints of service [%s] can't be overridden, supported services are %s", service, strings.Join(endpointServices, ", "))
+		}
+		parsed, err := url.Parse(strings.ReplaceAll(endpoint, "{region}", "region"))
+		if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			return fmt.Errorf("invalid endpoint [%s] of service [%s], must be an http or https URL", endpoint, service)
+		}
+	}
+
+	endpoints.Lock()
+	defer endpoints.Unlock()
+	endpoints.opts = opts
+	return nil
+}
+
+// endpointURL returns the URL configured for the service in the region, or an empty string if it isn't overridden.
+func endpointURL(service, region string) string {
+	endpoints.RLock()
+	defer endpoints.RUnlock()
+	return strings.ReplaceAll(endpoints.opts.URLs[service], "{region}", region)
+}
+
+// applyEndpoint sets the configured URL of the service as the base endpoint of a client, or enables its FIPS endpoint
+// if FIPS endpoints are configured. The SDK doesn't support FIPS for custom endpoints, so overridden services don't use
+// it. The options are left alone otherwise, so that endpoints configured through the environment still apply.
+func applyEndpoint(service, region string, baseEndpoint **string, fips *aws.FIPSEndpointState) {
+	if endpoint := endpointURL(service, region); endpoint != "" {
+		*baseEndpoint = aws.String(endpoint)
+		return
+	}
+
+	endpoints.RLock()
+	defer endpoints.RUnlock()
+	if endpoints.opts.FIPS {
+		*fips = aws.FIPSEndpointStateEnabled
+	}
+}
+
+// jsonAPIEndpoint returns the endpoint of a service called through jsonAPI in the region, which is the configured URL
+// or the regional endpoint, its FIPS variant if FIPS endpoints are configured.
+func jsonAPIEndpoint(service, region string) string {
+	if endpoint := endpointURL(service, region); endpoint != "" {
+		return endpoint
+	}
+
+	endpoints.RLock()
+	defer endpoints.RUnlock()
+	if endpoints.opts.FIPS {
+		return regionalEndpoint(service+"-fips", region)
+	}
+	return regionalEndpoint(service, region)
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEndpointURLs(t *testing.T) {
+	urls, err := ParseEndpointURLs(" eks=https://eks.{region}.example.com, sts=https://sts.example.com,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"eks": "https://eks.{region}.example.com", "sts": "https://sts.example.com"}, urls)
+
+	urls, err = ParseEndpointURLs("")
+	require.NoError(t, err)
+	assert.Empty(t, urls)
+
+	_, err = ParseEndpointURLs("eks")
+	assert.Error(t, err)
+}
+
+func TestSetEndpoints(t *testing.T) {
+	t.Cleanup(func() { require.NoError(t, SetEndpoints(EndpointOpts{})) })
+
+	assert.Error(t, SetEndpoints(EndpointOpts{URLs: map[string]string{"s3": "https://s3.example.com"}}))
+	assert.Error(t, SetEndpoints(EndpointOpts{URLs: map[string]string{"eks": "eks.example.com"}}))
+	assert.Error(t, SetEndpoints(EndpointOpts{URLs: map[string]string{"eks": "ftp://eks.example.com"}}))
+
+	var baseEndpoint *string
+	fips := aws.FIPSEndpointStateUnset
+	applyEndpoint("eks", "us-west-2", &baseEndpoint, &fips)
+	assert.Nil(t, baseEndpoint)
+	assert.Equal(t, aws.FIPSEndpointStateUnset, fips)
+	assert.Equal(t, "https://logs.us-west-2.amazonaws.com/", jsonAPIEndpoint("logs", "us-west-2"))
+
+	require.NoError(t, SetEndpoints(EndpointOpts{
+		URLs: map[string]string{"eks": "https://vpce-123.eks.{region}.vpce.amazonaws.com", "logs": "http://logs.example.com"},
+		FIPS: true,
+	}))
+	applyEndpoint("eks", "us-west-2", &baseEndpoint, &fips)
+	assert.Equal(t, "https://vpce-123.eks.us-west-2.vpce.amazonaws.com", aws.ToString(baseEndpoint))
+	assert.Equal(t, aws.FIPSEndpointStateUnset, fips)
+
+	baseEndpoint = nil
+	applyEndpoint("ec2", "us-west-2", &baseEndpoint, &fips)
+	assert.Nil(t, baseEndpoint)
+	assert.Equal(t, aws.FIPSEndpointStateEnabled, fips)
+
+	assert.Equal(t, "http://logs.example.com", jsonAPIEndpoint("logs", "us-west-2"))
+	// the Price List API has no FIPS endpoint
+	assert.Equal(t, "https://api.pricing.us-east-1.amazonaws.com/", NewPricingService(aws.Config{Region: "us-west-2"}).(*pricingService).api.endpoint)
+}
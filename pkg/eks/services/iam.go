@@ -9,8 +9,14 @@ import (
 
 type IAMServiceInterface interface {
 	GetRole(ctx context.Context, input *iam.GetRoleInput) (*iam.GetRoleOutput, error)
+	CreateRole(ctx context.Context, input *iam.CreateRoleInput) (*iam.CreateRoleOutput, error)
+	DeleteRole(ctx context.Context, input *iam.DeleteRoleInput) (*iam.DeleteRoleOutput, error)
 	ListOIDCProviders(ctx context.Context, input *iam.ListOpenIDConnectProvidersInput) (*iam.ListOpenIDConnectProvidersOutput, error)
 	CreateOIDCProvider(ctx context.Context, input *iam.CreateOpenIDConnectProviderInput) (*iam.CreateOpenIDConnectProviderOutput, error)
+	DeleteOIDCProvider(ctx context.Context, input *iam.DeleteOpenIDConnectProviderInput) (*iam.DeleteOpenIDConnectProviderOutput, error)
+	ListAttachedRolePolicies(ctx context.Context, input *iam.ListAttachedRolePoliciesInput) (*iam.ListAttachedRolePoliciesOutput, error)
+	AttachRolePolicy(ctx context.Context, input *iam.AttachRolePolicyInput) (*iam.AttachRolePolicyOutput, error)
+	DetachRolePolicy(ctx context.Context, input *iam.DetachRolePolicyInput) (*iam.DetachRolePolicyOutput, error)
 }
 
 type iamService struct {
@@ -19,7 +25,9 @@ type iamService struct {
 
 func NewIAMService(cfg aws.Config) IAMServiceInterface {
 	return &iamService{
-		svc: iam.NewFromConfig(cfg),
+		svc: iam.NewFromConfig(cfg, func(o *iam.Options) {
+			applyEndpoint("iam", cfg.Region, &o.BaseEndpoint, &o.EndpointOptions.UseFIPSEndpoint)
+		}),
 	}
 }
 
@@ -27,6 +35,14 @@ func (c *iamService) GetRole(ctx context.Context, input *iam.GetRoleInput) (*iam
 	return c.svc.GetRole(ctx, input)
 }
 
+func (c *iamService) CreateRole(ctx context.Context, input *iam.CreateRoleInput) (*iam.CreateRoleOutput, error) {
+	return c.svc.CreateRole(ctx, input)
+}
+
+func (c *iamService) DeleteRole(ctx context.Context, input *iam.DeleteRoleInput) (*iam.DeleteRoleOutput, error) {
+	return c.svc.DeleteRole(ctx, input)
+}
+
 func (c *iamService) ListOIDCProviders(ctx context.Context, input *iam.ListOpenIDConnectProvidersInput) (*iam.ListOpenIDConnectProvidersOutput, error) {
 	return c.svc.ListOpenIDConnectProviders(ctx, input)
 }
@@ -34,3 +50,19 @@ func (c *iamService) ListOIDCProviders(ctx context.Context, input *iam.ListOpenI
 func (c *iamService) CreateOIDCProvider(ctx context.Context, input *iam.CreateOpenIDConnectProviderInput) (*iam.CreateOpenIDConnectProviderOutput, error) {
 	return c.svc.CreateOpenIDConnectProvider(ctx, input)
 }
+
+func (c *iamService) DeleteOIDCProvider(ctx context.Context, input *iam.DeleteOpenIDConnectProviderInput) (*iam.DeleteOpenIDConnectProviderOutput, error) {
+	return c.svc.DeleteOpenIDConnectProvider(ctx, input)
+}
+
+func (c *iamService) ListAttachedRolePolicies(ctx context.Context, input *iam.ListAttachedRolePoliciesInput) (*iam.ListAttachedRolePoliciesOutput, error) {
+	return c.svc.ListAttachedRolePolicies(ctx, input)
+}
+
+func (c *iamService) AttachRolePolicy(ctx context.Context, input *iam.AttachRolePolicyInput) (*iam.AttachRolePolicyOutput, error) {
+	return c.svc.AttachRolePolicy(ctx, input)
+}
+
+func (c *iamService) DetachRolePolicy(ctx context.Context, input *iam.DetachRolePolicyInput) (*iam.DetachRolePolicyOutput, error) {
+	return c.svc.DetachRolePolicy(ctx, input)
+}
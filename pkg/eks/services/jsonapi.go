@@ -0,0 +1,127 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+// APIError is an error returned by an AWS API that is called with the JSON protocol.
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s (status code %d): %s", e.Code, e.StatusCode, e.Message)
+}
+
+// jsonAPI calls the operations of an AWS API that uses the JSON protocol and has no client in the vendored SDK.
+type jsonAPI struct {
+	cfg      aws.Config
+	signer   *v4.Signer
+	service  string
+	region   string
+	endpoint string
+	// contentType is the content type of the version of the JSON protocol the API uses, application/x-amz-json-1.1 if
+	// empty
+	contentType string
+}
+
+// call invokes the operation identified by target with the input and decodes the response into the output.
+func (c *jsonAPI) call(ctx context.Context, target string, input, output interface{}) error {
+	// the requests aren't sent by SDK clients, so dry run mode is applied here instead of by WithDryRun
+	if err := skipMutation(c.service, target[strings.LastIndex(target, ".")+1:], input); err != nil {
+		return err
+	}
+	if c.cfg.Credentials == nil {
+		return fmt.Errorf("no credentials configured for the %s API", c.service)
+	}
+
+	body, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	contentType := c.contentType
+	if contentType == "" {
+		contentType = "application/x-amz-json-1.1"
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-Amz-Target", target)
+
+	creds, err := c.cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("error retrieving credentials: %w", err)
+	}
+	payloadHash := sha256.Sum256(body)
+	if err := c.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(payloadHash[:]), c.service, c.region, time.Now()); err != nil {
+		return fmt.Errorf("error signing request: %w", err)
+	}
+
+	var httpClient aws.HTTPClient = http.DefaultClient
+	if c.cfg.HTTPClient != nil {
+		httpClient = c.cfg.HTTPClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError(resp.StatusCode, respBody)
+	}
+
+	if output == nil || len(respBody) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, output); err != nil {
+		return fmt.Errorf("error decoding %s API response: %w", c.service, err)
+	}
+	return nil
+}
+
+// newAPIError decodes the error document of a JSON protocol response. The error type may be prefixed with the
+// namespace of the API, e.g. com.amazonaws.logs#ResourceNotFoundException.
+func newAPIError(statusCode int, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: statusCode, Message: string(body)}
+	document := struct {
+		Type         string `json:"__type"`
+		Message      string `json:"message"`
+		MessageUpper string `json:"Message"`
+	}{}
+	if err := json.Unmarshal(body, &document); err != nil {
+		return apiErr
+	}
+	apiErr.Code = document.Type[strings.LastIndex(document.Type, "#")+1:]
+	if document.Message != "" {
+		apiErr.Message = document.Message
+	} else if document.MessageUpper != "" {
+		apiErr.Message = document.MessageUpper
+	}
+	return apiErr
+}
+
+// regionalEndpoint returns the endpoint of the service in the region, taking the partition of the region into account.
+func regionalEndpoint(service, region string) string {
+	return fmt.Sprintf("https://%s.%s.%s/", service, region, DNSSuffix(region))
+}
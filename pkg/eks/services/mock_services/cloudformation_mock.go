@@ -80,6 +80,21 @@ func (mr *MockCloudFormationServiceInterfaceMockRecorder) DescribeStackEvents(ct
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeStackEvents", reflect.TypeOf((*MockCloudFormationServiceInterface)(nil).DescribeStackEvents), ctx, input)
 }
 
+// DescribeStackResources mocks base method.
+func (m *MockCloudFormationServiceInterface) DescribeStackResources(ctx context.Context, input *cloudformation.DescribeStackResourcesInput) (*cloudformation.DescribeStackResourcesOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeStackResources", ctx, input)
+	ret0, _ := ret[0].(*cloudformation.DescribeStackResourcesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeStackResources indicates an expected call of DescribeStackResources.
+func (mr *MockCloudFormationServiceInterfaceMockRecorder) DescribeStackResources(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeStackResources", reflect.TypeOf((*MockCloudFormationServiceInterface)(nil).DescribeStackResources), ctx, input)
+}
+
 // DescribeStacks mocks base method.
 func (m *MockCloudFormationServiceInterface) DescribeStacks(ctx context.Context, input *cloudformation.DescribeStacksInput) (*cloudformation.DescribeStacksOutput, error) {
 	m.ctrl.T.Helper()
@@ -94,3 +109,18 @@ func (mr *MockCloudFormationServiceInterfaceMockRecorder) DescribeStacks(ctx, in
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeStacks", reflect.TypeOf((*MockCloudFormationServiceInterface)(nil).DescribeStacks), ctx, input)
 }
+
+// UpdateStack mocks base method.
+func (m *MockCloudFormationServiceInterface) UpdateStack(ctx context.Context, input *cloudformation.UpdateStackInput) (*cloudformation.UpdateStackOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStack", ctx, input)
+	ret0, _ := ret[0].(*cloudformation.UpdateStackOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateStack indicates an expected call of UpdateStack.
+func (mr *MockCloudFormationServiceInterfaceMockRecorder) UpdateStack(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStack", reflect.TypeOf((*MockCloudFormationServiceInterface)(nil).UpdateStack), ctx, input)
+}
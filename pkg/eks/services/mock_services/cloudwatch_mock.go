@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../cloudwatch.go
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	services "github.com/rancher/eks-operator/pkg/eks/services"
+)
+
+// MockCloudWatchServiceInterface is a mock of CloudWatchServiceInterface interface.
+type MockCloudWatchServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockCloudWatchServiceInterfaceMockRecorder
+}
+
+// MockCloudWatchServiceInterfaceMockRecorder is the mock recorder for MockCloudWatchServiceInterface.
+type MockCloudWatchServiceInterfaceMockRecorder struct {
+	mock *MockCloudWatchServiceInterface
+}
+
+// NewMockCloudWatchServiceInterface creates a new mock instance.
+func NewMockCloudWatchServiceInterface(ctrl *gomock.Controller) *MockCloudWatchServiceInterface {
+	mock := &MockCloudWatchServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockCloudWatchServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCloudWatchServiceInterface) EXPECT() *MockCloudWatchServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// DeleteAlarms mocks base method.
+func (m *MockCloudWatchServiceInterface) DeleteAlarms(ctx context.Context, input *services.DeleteAlarmsInput) (*services.DeleteAlarmsOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAlarms", ctx, input)
+	ret0, _ := ret[0].(*services.DeleteAlarmsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAlarms indicates an expected call of DeleteAlarms.
+func (mr *MockCloudWatchServiceInterfaceMockRecorder) DeleteAlarms(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAlarms", reflect.TypeOf((*MockCloudWatchServiceInterface)(nil).DeleteAlarms), ctx, input)
+}
+
+// PutMetricAlarm mocks base method.
+func (m *MockCloudWatchServiceInterface) PutMetricAlarm(ctx context.Context, input *services.PutMetricAlarmInput) (*services.PutMetricAlarmOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutMetricAlarm", ctx, input)
+	ret0, _ := ret[0].(*services.PutMetricAlarmOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutMetricAlarm indicates an expected call of PutMetricAlarm.
+func (mr *MockCloudWatchServiceInterfaceMockRecorder) PutMetricAlarm(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutMetricAlarm", reflect.TypeOf((*MockCloudWatchServiceInterface)(nil).PutMetricAlarm), ctx, input)
+}
@@ -0,0 +1,111 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../cloudwatchlogs.go
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	services "github.com/rancher/eks-operator/pkg/eks/services"
+)
+
+// MockCloudWatchLogsServiceInterface is a mock of CloudWatchLogsServiceInterface interface.
+type MockCloudWatchLogsServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockCloudWatchLogsServiceInterfaceMockRecorder
+}
+
+// MockCloudWatchLogsServiceInterfaceMockRecorder is the mock recorder for MockCloudWatchLogsServiceInterface.
+type MockCloudWatchLogsServiceInterfaceMockRecorder struct {
+	mock *MockCloudWatchLogsServiceInterface
+}
+
+// NewMockCloudWatchLogsServiceInterface creates a new mock instance.
+func NewMockCloudWatchLogsServiceInterface(ctrl *gomock.Controller) *MockCloudWatchLogsServiceInterface {
+	mock := &MockCloudWatchLogsServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockCloudWatchLogsServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCloudWatchLogsServiceInterface) EXPECT() *MockCloudWatchLogsServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// DeleteLogGroup mocks base method.
+func (m *MockCloudWatchLogsServiceInterface) DeleteLogGroup(ctx context.Context, input *services.DeleteLogGroupInput) (*services.DeleteLogGroupOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLogGroup", ctx, input)
+	ret0, _ := ret[0].(*services.DeleteLogGroupOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteLogGroup indicates an expected call of DeleteLogGroup.
+func (mr *MockCloudWatchLogsServiceInterfaceMockRecorder) DeleteLogGroup(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLogGroup", reflect.TypeOf((*MockCloudWatchLogsServiceInterface)(nil).DeleteLogGroup), ctx, input)
+}
+
+// DeleteMetricFilter mocks base method.
+func (m *MockCloudWatchLogsServiceInterface) DeleteMetricFilter(ctx context.Context, input *services.DeleteMetricFilterInput) (*services.DeleteMetricFilterOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMetricFilter", ctx, input)
+	ret0, _ := ret[0].(*services.DeleteMetricFilterOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteMetricFilter indicates an expected call of DeleteMetricFilter.
+func (mr *MockCloudWatchLogsServiceInterfaceMockRecorder) DeleteMetricFilter(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMetricFilter", reflect.TypeOf((*MockCloudWatchLogsServiceInterface)(nil).DeleteMetricFilter), ctx, input)
+}
+
+// DeleteRetentionPolicy mocks base method.
+func (m *MockCloudWatchLogsServiceInterface) DeleteRetentionPolicy(ctx context.Context, input *services.DeleteRetentionPolicyInput) (*services.DeleteRetentionPolicyOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRetentionPolicy", ctx, input)
+	ret0, _ := ret[0].(*services.DeleteRetentionPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteRetentionPolicy indicates an expected call of DeleteRetentionPolicy.
+func (mr *MockCloudWatchLogsServiceInterfaceMockRecorder) DeleteRetentionPolicy(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRetentionPolicy", reflect.TypeOf((*MockCloudWatchLogsServiceInterface)(nil).DeleteRetentionPolicy), ctx, input)
+}
+
+// PutMetricFilter mocks base method.
+func (m *MockCloudWatchLogsServiceInterface) PutMetricFilter(ctx context.Context, input *services.PutMetricFilterInput) (*services.PutMetricFilterOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutMetricFilter", ctx, input)
+	ret0, _ := ret[0].(*services.PutMetricFilterOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutMetricFilter indicates an expected call of PutMetricFilter.
+func (mr *MockCloudWatchLogsServiceInterfaceMockRecorder) PutMetricFilter(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutMetricFilter", reflect.TypeOf((*MockCloudWatchLogsServiceInterface)(nil).PutMetricFilter), ctx, input)
+}
+
+// PutRetentionPolicy mocks base method.
+func (m *MockCloudWatchLogsServiceInterface) PutRetentionPolicy(ctx context.Context, input *services.PutRetentionPolicyInput) (*services.PutRetentionPolicyOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutRetentionPolicy", ctx, input)
+	ret0, _ := ret[0].(*services.PutRetentionPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutRetentionPolicy indicates an expected call of PutRetentionPolicy.
+func (mr *MockCloudWatchLogsServiceInterfaceMockRecorder) PutRetentionPolicy(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutRetentionPolicy", reflect.TypeOf((*MockCloudWatchLogsServiceInterface)(nil).PutRetentionPolicy), ctx, input)
+}
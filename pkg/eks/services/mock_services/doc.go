@@ -6,3 +6,7 @@ package mock_services
 //go:generate ../../../../bin/mockgen -destination eks_mock.go -package mock_services -source ../eks.go EKSServiceInterface
 //go:generate ../../../../bin/mockgen -destination iam_mock.go -package mock_services -source ../iam.go IAMServiceInterface
 //go:generate ../../../../bin/mockgen -destination ec2_mock.go -package mock_services -source ../ec2.go EC2ServiceInterface
+//go:generate ../../../../bin/mockgen -destination sts_mock.go -package mock_services -source ../sts.go STSServiceInterface
+//go:generate ../../../../bin/mockgen -destination pricing_mock.go -package mock_services -source ../pricing.go PricingServiceInterface
+//go:generate ../../../../bin/mockgen -destination cloudwatchlogs_mock.go -package mock_services -source ../cloudwatchlogs.go CloudWatchLogsServiceInterface
+//go:generate ../../../../bin/mockgen -destination cloudwatch_mock.go -package mock_services -source ../cloudwatch.go CloudWatchServiceInterface
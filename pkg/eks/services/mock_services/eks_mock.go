@@ -35,6 +35,66 @@ func (m *MockEKSServiceInterface) EXPECT() *MockEKSServiceInterfaceMockRecorder
 	return m.recorder
 }
 
+// AssociateAccessPolicy mocks base method.
+func (m *MockEKSServiceInterface) AssociateAccessPolicy(ctx context.Context, input *eks.AssociateAccessPolicyInput) (*eks.AssociateAccessPolicyOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssociateAccessPolicy", ctx, input)
+	ret0, _ := ret[0].(*eks.AssociateAccessPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssociateAccessPolicy indicates an expected call of AssociateAccessPolicy.
+func (mr *MockEKSServiceInterfaceMockRecorder) AssociateAccessPolicy(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssociateAccessPolicy", reflect.TypeOf((*MockEKSServiceInterface)(nil).AssociateAccessPolicy), ctx, input)
+}
+
+// AssociateEncryptionConfig mocks base method.
+func (m *MockEKSServiceInterface) AssociateEncryptionConfig(ctx context.Context, input *eks.AssociateEncryptionConfigInput) (*eks.AssociateEncryptionConfigOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssociateEncryptionConfig", ctx, input)
+	ret0, _ := ret[0].(*eks.AssociateEncryptionConfigOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssociateEncryptionConfig indicates an expected call of AssociateEncryptionConfig.
+func (mr *MockEKSServiceInterfaceMockRecorder) AssociateEncryptionConfig(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssociateEncryptionConfig", reflect.TypeOf((*MockEKSServiceInterface)(nil).AssociateEncryptionConfig), ctx, input)
+}
+
+// AssociateIdentityProviderConfig mocks base method.
+func (m *MockEKSServiceInterface) AssociateIdentityProviderConfig(ctx context.Context, input *eks.AssociateIdentityProviderConfigInput) (*eks.AssociateIdentityProviderConfigOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssociateIdentityProviderConfig", ctx, input)
+	ret0, _ := ret[0].(*eks.AssociateIdentityProviderConfigOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssociateIdentityProviderConfig indicates an expected call of AssociateIdentityProviderConfig.
+func (mr *MockEKSServiceInterfaceMockRecorder) AssociateIdentityProviderConfig(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssociateIdentityProviderConfig", reflect.TypeOf((*MockEKSServiceInterface)(nil).AssociateIdentityProviderConfig), ctx, input)
+}
+
+// CreateAccessEntry mocks base method.
+func (m *MockEKSServiceInterface) CreateAccessEntry(ctx context.Context, input *eks.CreateAccessEntryInput) (*eks.CreateAccessEntryOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccessEntry", ctx, input)
+	ret0, _ := ret[0].(*eks.CreateAccessEntryOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccessEntry indicates an expected call of CreateAccessEntry.
+func (mr *MockEKSServiceInterfaceMockRecorder) CreateAccessEntry(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccessEntry", reflect.TypeOf((*MockEKSServiceInterface)(nil).CreateAccessEntry), ctx, input)
+}
+
 // CreateAddon mocks base method.
 func (m *MockEKSServiceInterface) CreateAddon(ctx context.Context, input *eks.CreateAddonInput) (*eks.CreateAddonOutput, error) {
 	m.ctrl.T.Helper()
@@ -80,6 +140,51 @@ func (mr *MockEKSServiceInterfaceMockRecorder) CreateNodegroup(ctx, input interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNodegroup", reflect.TypeOf((*MockEKSServiceInterface)(nil).CreateNodegroup), ctx, input)
 }
 
+// CreatePodIdentityAssociation mocks base method.
+func (m *MockEKSServiceInterface) CreatePodIdentityAssociation(ctx context.Context, input *eks.CreatePodIdentityAssociationInput) (*eks.CreatePodIdentityAssociationOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePodIdentityAssociation", ctx, input)
+	ret0, _ := ret[0].(*eks.CreatePodIdentityAssociationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePodIdentityAssociation indicates an expected call of CreatePodIdentityAssociation.
+func (mr *MockEKSServiceInterfaceMockRecorder) CreatePodIdentityAssociation(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePodIdentityAssociation", reflect.TypeOf((*MockEKSServiceInterface)(nil).CreatePodIdentityAssociation), ctx, input)
+}
+
+// DeleteAccessEntry mocks base method.
+func (m *MockEKSServiceInterface) DeleteAccessEntry(ctx context.Context, input *eks.DeleteAccessEntryInput) (*eks.DeleteAccessEntryOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccessEntry", ctx, input)
+	ret0, _ := ret[0].(*eks.DeleteAccessEntryOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAccessEntry indicates an expected call of DeleteAccessEntry.
+func (mr *MockEKSServiceInterfaceMockRecorder) DeleteAccessEntry(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccessEntry", reflect.TypeOf((*MockEKSServiceInterface)(nil).DeleteAccessEntry), ctx, input)
+}
+
+// DeleteAddon mocks base method.
+func (m *MockEKSServiceInterface) DeleteAddon(ctx context.Context, input *eks.DeleteAddonInput) (*eks.DeleteAddonOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAddon", ctx, input)
+	ret0, _ := ret[0].(*eks.DeleteAddonOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAddon indicates an expected call of DeleteAddon.
+func (mr *MockEKSServiceInterfaceMockRecorder) DeleteAddon(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAddon", reflect.TypeOf((*MockEKSServiceInterface)(nil).DeleteAddon), ctx, input)
+}
+
 // DeleteCluster mocks base method.
 func (m *MockEKSServiceInterface) DeleteCluster(ctx context.Context, input *eks.DeleteClusterInput) (*eks.DeleteClusterOutput, error) {
 	m.ctrl.T.Helper()
@@ -110,6 +215,36 @@ func (mr *MockEKSServiceInterfaceMockRecorder) DeleteNodegroup(ctx, input interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNodegroup", reflect.TypeOf((*MockEKSServiceInterface)(nil).DeleteNodegroup), ctx, input)
 }
 
+// DeletePodIdentityAssociation mocks base method.
+func (m *MockEKSServiceInterface) DeletePodIdentityAssociation(ctx context.Context, input *eks.DeletePodIdentityAssociationInput) (*eks.DeletePodIdentityAssociationOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePodIdentityAssociation", ctx, input)
+	ret0, _ := ret[0].(*eks.DeletePodIdentityAssociationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeletePodIdentityAssociation indicates an expected call of DeletePodIdentityAssociation.
+func (mr *MockEKSServiceInterfaceMockRecorder) DeletePodIdentityAssociation(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePodIdentityAssociation", reflect.TypeOf((*MockEKSServiceInterface)(nil).DeletePodIdentityAssociation), ctx, input)
+}
+
+// DescribeAccessEntry mocks base method.
+func (m *MockEKSServiceInterface) DescribeAccessEntry(ctx context.Context, input *eks.DescribeAccessEntryInput) (*eks.DescribeAccessEntryOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeAccessEntry", ctx, input)
+	ret0, _ := ret[0].(*eks.DescribeAccessEntryOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeAccessEntry indicates an expected call of DescribeAccessEntry.
+func (mr *MockEKSServiceInterfaceMockRecorder) DescribeAccessEntry(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeAccessEntry", reflect.TypeOf((*MockEKSServiceInterface)(nil).DescribeAccessEntry), ctx, input)
+}
+
 // DescribeAddon mocks base method.
 func (m *MockEKSServiceInterface) DescribeAddon(ctx context.Context, input *eks.DescribeAddonInput) (*eks.DescribeAddonOutput, error) {
 	m.ctrl.T.Helper()
@@ -140,6 +275,36 @@ func (mr *MockEKSServiceInterfaceMockRecorder) DescribeCluster(ctx, input interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeCluster", reflect.TypeOf((*MockEKSServiceInterface)(nil).DescribeCluster), ctx, input)
 }
 
+// DescribeFargateProfile mocks base method.
+func (m *MockEKSServiceInterface) DescribeFargateProfile(ctx context.Context, input *eks.DescribeFargateProfileInput) (*eks.DescribeFargateProfileOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeFargateProfile", ctx, input)
+	ret0, _ := ret[0].(*eks.DescribeFargateProfileOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeFargateProfile indicates an expected call of DescribeFargateProfile.
+func (mr *MockEKSServiceInterfaceMockRecorder) DescribeFargateProfile(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeFargateProfile", reflect.TypeOf((*MockEKSServiceInterface)(nil).DescribeFargateProfile), ctx, input)
+}
+
+// DescribeIdentityProviderConfig mocks base method.
+func (m *MockEKSServiceInterface) DescribeIdentityProviderConfig(ctx context.Context, input *eks.DescribeIdentityProviderConfigInput) (*eks.DescribeIdentityProviderConfigOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeIdentityProviderConfig", ctx, input)
+	ret0, _ := ret[0].(*eks.DescribeIdentityProviderConfigOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeIdentityProviderConfig indicates an expected call of DescribeIdentityProviderConfig.
+func (mr *MockEKSServiceInterfaceMockRecorder) DescribeIdentityProviderConfig(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeIdentityProviderConfig", reflect.TypeOf((*MockEKSServiceInterface)(nil).DescribeIdentityProviderConfig), ctx, input)
+}
+
 // DescribeNodegroup mocks base method.
 func (m *MockEKSServiceInterface) DescribeNodegroup(ctx context.Context, input *eks.DescribeNodegroupInput) (*eks.DescribeNodegroupOutput, error) {
 	m.ctrl.T.Helper()
@@ -155,6 +320,96 @@ func (mr *MockEKSServiceInterfaceMockRecorder) DescribeNodegroup(ctx, input inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeNodegroup", reflect.TypeOf((*MockEKSServiceInterface)(nil).DescribeNodegroup), ctx, input)
 }
 
+// DescribePodIdentityAssociation mocks base method.
+func (m *MockEKSServiceInterface) DescribePodIdentityAssociation(ctx context.Context, input *eks.DescribePodIdentityAssociationInput) (*eks.DescribePodIdentityAssociationOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribePodIdentityAssociation", ctx, input)
+	ret0, _ := ret[0].(*eks.DescribePodIdentityAssociationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribePodIdentityAssociation indicates an expected call of DescribePodIdentityAssociation.
+func (mr *MockEKSServiceInterfaceMockRecorder) DescribePodIdentityAssociation(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribePodIdentityAssociation", reflect.TypeOf((*MockEKSServiceInterface)(nil).DescribePodIdentityAssociation), ctx, input)
+}
+
+// DescribeUpdate mocks base method.
+func (m *MockEKSServiceInterface) DescribeUpdate(ctx context.Context, input *eks.DescribeUpdateInput) (*eks.DescribeUpdateOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeUpdate", ctx, input)
+	ret0, _ := ret[0].(*eks.DescribeUpdateOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeUpdate indicates an expected call of DescribeUpdate.
+func (mr *MockEKSServiceInterfaceMockRecorder) DescribeUpdate(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeUpdate", reflect.TypeOf((*MockEKSServiceInterface)(nil).DescribeUpdate), ctx, input)
+}
+
+// DisassociateIdentityProviderConfig mocks base method.
+func (m *MockEKSServiceInterface) DisassociateIdentityProviderConfig(ctx context.Context, input *eks.DisassociateIdentityProviderConfigInput) (*eks.DisassociateIdentityProviderConfigOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisassociateIdentityProviderConfig", ctx, input)
+	ret0, _ := ret[0].(*eks.DisassociateIdentityProviderConfigOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisassociateIdentityProviderConfig indicates an expected call of DisassociateIdentityProviderConfig.
+func (mr *MockEKSServiceInterfaceMockRecorder) DisassociateIdentityProviderConfig(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisassociateIdentityProviderConfig", reflect.TypeOf((*MockEKSServiceInterface)(nil).DisassociateIdentityProviderConfig), ctx, input)
+}
+
+// ListAccessEntries mocks base method.
+func (m *MockEKSServiceInterface) ListAccessEntries(ctx context.Context, input *eks.ListAccessEntriesInput) (*eks.ListAccessEntriesOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccessEntries", ctx, input)
+	ret0, _ := ret[0].(*eks.ListAccessEntriesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccessEntries indicates an expected call of ListAccessEntries.
+func (mr *MockEKSServiceInterfaceMockRecorder) ListAccessEntries(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccessEntries", reflect.TypeOf((*MockEKSServiceInterface)(nil).ListAccessEntries), ctx, input)
+}
+
+// ListAddons mocks base method.
+func (m *MockEKSServiceInterface) ListAddons(ctx context.Context, input *eks.ListAddonsInput) (*eks.ListAddonsOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAddons", ctx, input)
+	ret0, _ := ret[0].(*eks.ListAddonsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAddons indicates an expected call of ListAddons.
+func (mr *MockEKSServiceInterfaceMockRecorder) ListAddons(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAddons", reflect.TypeOf((*MockEKSServiceInterface)(nil).ListAddons), ctx, input)
+}
+
+// ListAssociatedAccessPolicies mocks base method.
+func (m *MockEKSServiceInterface) ListAssociatedAccessPolicies(ctx context.Context, input *eks.ListAssociatedAccessPoliciesInput) (*eks.ListAssociatedAccessPoliciesOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAssociatedAccessPolicies", ctx, input)
+	ret0, _ := ret[0].(*eks.ListAssociatedAccessPoliciesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAssociatedAccessPolicies indicates an expected call of ListAssociatedAccessPolicies.
+func (mr *MockEKSServiceInterfaceMockRecorder) ListAssociatedAccessPolicies(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAssociatedAccessPolicies", reflect.TypeOf((*MockEKSServiceInterface)(nil).ListAssociatedAccessPolicies), ctx, input)
+}
+
 // ListClusters mocks base method.
 func (m *MockEKSServiceInterface) ListClusters(ctx context.Context, input *eks.ListClustersInput) (*eks.ListClustersOutput, error) {
 	m.ctrl.T.Helper()
@@ -170,6 +425,51 @@ func (mr *MockEKSServiceInterfaceMockRecorder) ListClusters(ctx, input interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClusters", reflect.TypeOf((*MockEKSServiceInterface)(nil).ListClusters), ctx, input)
 }
 
+// ListFargateProfiles mocks base method.
+func (m *MockEKSServiceInterface) ListFargateProfiles(ctx context.Context, input *eks.ListFargateProfilesInput) (*eks.ListFargateProfilesOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFargateProfiles", ctx, input)
+	ret0, _ := ret[0].(*eks.ListFargateProfilesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFargateProfiles indicates an expected call of ListFargateProfiles.
+func (mr *MockEKSServiceInterfaceMockRecorder) ListFargateProfiles(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFargateProfiles", reflect.TypeOf((*MockEKSServiceInterface)(nil).ListFargateProfiles), ctx, input)
+}
+
+// ListIdentityProviderConfigs mocks base method.
+func (m *MockEKSServiceInterface) ListIdentityProviderConfigs(ctx context.Context, input *eks.ListIdentityProviderConfigsInput) (*eks.ListIdentityProviderConfigsOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIdentityProviderConfigs", ctx, input)
+	ret0, _ := ret[0].(*eks.ListIdentityProviderConfigsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIdentityProviderConfigs indicates an expected call of ListIdentityProviderConfigs.
+func (mr *MockEKSServiceInterfaceMockRecorder) ListIdentityProviderConfigs(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIdentityProviderConfigs", reflect.TypeOf((*MockEKSServiceInterface)(nil).ListIdentityProviderConfigs), ctx, input)
+}
+
+// ListInsights mocks base method.
+func (m *MockEKSServiceInterface) ListInsights(ctx context.Context, input *eks.ListInsightsInput) (*eks.ListInsightsOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInsights", ctx, input)
+	ret0, _ := ret[0].(*eks.ListInsightsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInsights indicates an expected call of ListInsights.
+func (mr *MockEKSServiceInterfaceMockRecorder) ListInsights(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInsights", reflect.TypeOf((*MockEKSServiceInterface)(nil).ListInsights), ctx, input)
+}
+
 // ListNodegroups mocks base method.
 func (m *MockEKSServiceInterface) ListNodegroups(ctx context.Context, input *eks.ListNodegroupsInput) (*eks.ListNodegroupsOutput, error) {
 	m.ctrl.T.Helper()
@@ -185,6 +485,36 @@ func (mr *MockEKSServiceInterfaceMockRecorder) ListNodegroups(ctx, input interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNodegroups", reflect.TypeOf((*MockEKSServiceInterface)(nil).ListNodegroups), ctx, input)
 }
 
+// ListPodIdentityAssociations mocks base method.
+func (m *MockEKSServiceInterface) ListPodIdentityAssociations(ctx context.Context, input *eks.ListPodIdentityAssociationsInput) (*eks.ListPodIdentityAssociationsOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPodIdentityAssociations", ctx, input)
+	ret0, _ := ret[0].(*eks.ListPodIdentityAssociationsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPodIdentityAssociations indicates an expected call of ListPodIdentityAssociations.
+func (mr *MockEKSServiceInterfaceMockRecorder) ListPodIdentityAssociations(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPodIdentityAssociations", reflect.TypeOf((*MockEKSServiceInterface)(nil).ListPodIdentityAssociations), ctx, input)
+}
+
+// ListUpdates mocks base method.
+func (m *MockEKSServiceInterface) ListUpdates(ctx context.Context, input *eks.ListUpdatesInput) (*eks.ListUpdatesOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUpdates", ctx, input)
+	ret0, _ := ret[0].(*eks.ListUpdatesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUpdates indicates an expected call of ListUpdates.
+func (mr *MockEKSServiceInterfaceMockRecorder) ListUpdates(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUpdates", reflect.TypeOf((*MockEKSServiceInterface)(nil).ListUpdates), ctx, input)
+}
+
 // TagResource mocks base method.
 func (m *MockEKSServiceInterface) TagResource(ctx context.Context, input *eks.TagResourceInput) (*eks.TagResourceOutput, error) {
 	m.ctrl.T.Helper()
@@ -215,6 +545,21 @@ func (mr *MockEKSServiceInterfaceMockRecorder) UntagResource(ctx, input interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UntagResource", reflect.TypeOf((*MockEKSServiceInterface)(nil).UntagResource), ctx, input)
 }
 
+// UpdateAddon mocks base method.
+func (m *MockEKSServiceInterface) UpdateAddon(ctx context.Context, input *eks.UpdateAddonInput) (*eks.UpdateAddonOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAddon", ctx, input)
+	ret0, _ := ret[0].(*eks.UpdateAddonOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAddon indicates an expected call of UpdateAddon.
+func (mr *MockEKSServiceInterfaceMockRecorder) UpdateAddon(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAddon", reflect.TypeOf((*MockEKSServiceInterface)(nil).UpdateAddon), ctx, input)
+}
+
 // UpdateClusterConfig mocks base method.
 func (m *MockEKSServiceInterface) UpdateClusterConfig(ctx context.Context, input *eks.UpdateClusterConfigInput) (*eks.UpdateClusterConfigOutput, error) {
 	m.ctrl.T.Helper()
@@ -274,3 +619,18 @@ func (mr *MockEKSServiceInterfaceMockRecorder) UpdateNodegroupVersion(ctx, input
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNodegroupVersion", reflect.TypeOf((*MockEKSServiceInterface)(nil).UpdateNodegroupVersion), ctx, input)
 }
+
+// UpdatePodIdentityAssociation mocks base method.
+func (m *MockEKSServiceInterface) UpdatePodIdentityAssociation(ctx context.Context, input *eks.UpdatePodIdentityAssociationInput) (*eks.UpdatePodIdentityAssociationOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePodIdentityAssociation", ctx, input)
+	ret0, _ := ret[0].(*eks.UpdatePodIdentityAssociationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePodIdentityAssociation indicates an expected call of UpdatePodIdentityAssociation.
+func (mr *MockEKSServiceInterfaceMockRecorder) UpdatePodIdentityAssociation(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePodIdentityAssociation", reflect.TypeOf((*MockEKSServiceInterface)(nil).UpdatePodIdentityAssociation), ctx, input)
+}
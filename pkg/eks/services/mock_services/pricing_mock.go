@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../pricing.go
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	services "github.com/rancher/eks-operator/pkg/eks/services"
+)
+
+// MockPricingServiceInterface is a mock of PricingServiceInterface interface.
+type MockPricingServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockPricingServiceInterfaceMockRecorder
+}
+
+// MockPricingServiceInterfaceMockRecorder is the mock recorder for MockPricingServiceInterface.
+type MockPricingServiceInterfaceMockRecorder struct {
+	mock *MockPricingServiceInterface
+}
+
+// NewMockPricingServiceInterface creates a new mock instance.
+func NewMockPricingServiceInterface(ctrl *gomock.Controller) *MockPricingServiceInterface {
+	mock := &MockPricingServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockPricingServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPricingServiceInterface) EXPECT() *MockPricingServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GetProducts mocks base method.
+func (m *MockPricingServiceInterface) GetProducts(ctx context.Context, input *services.GetProductsInput) (*services.GetProductsOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProducts", ctx, input)
+	ret0, _ := ret[0].(*services.GetProductsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProducts indicates an expected call of GetProducts.
+func (mr *MockPricingServiceInterfaceMockRecorder) GetProducts(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProducts", reflect.TypeOf((*MockPricingServiceInterface)(nil).GetProducts), ctx, input)
+}
@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../sts.go
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	context "context"
+	reflect "reflect"
+
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	sts "github.com/aws/aws-sdk-go-v2/service/sts"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSTSServiceInterface is a mock of STSServiceInterface interface.
+type MockSTSServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockSTSServiceInterfaceMockRecorder
+}
+
+// MockSTSServiceInterfaceMockRecorder is the mock recorder for MockSTSServiceInterface.
+type MockSTSServiceInterfaceMockRecorder struct {
+	mock *MockSTSServiceInterface
+}
+
+// NewMockSTSServiceInterface creates a new mock instance.
+func NewMockSTSServiceInterface(ctrl *gomock.Controller) *MockSTSServiceInterface {
+	mock := &MockSTSServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockSTSServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSTSServiceInterface) EXPECT() *MockSTSServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GetCallerIdentity mocks base method.
+func (m *MockSTSServiceInterface) GetCallerIdentity(ctx context.Context, input *sts.GetCallerIdentityInput) (*sts.GetCallerIdentityOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCallerIdentity", ctx, input)
+	ret0, _ := ret[0].(*sts.GetCallerIdentityOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCallerIdentity indicates an expected call of GetCallerIdentity.
+func (mr *MockSTSServiceInterfaceMockRecorder) GetCallerIdentity(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCallerIdentity", reflect.TypeOf((*MockSTSServiceInterface)(nil).GetCallerIdentity), ctx, input)
+}
+
+// PresignGetCallerIdentity mocks base method.
+func (m *MockSTSServiceInterface) PresignGetCallerIdentity(ctx context.Context, input *sts.GetCallerIdentityInput, optFns ...func(*sts.PresignOptions)) (*v4.PresignedHTTPRequest, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, input}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PresignGetCallerIdentity", varargs...)
+	ret0, _ := ret[0].(*v4.PresignedHTTPRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PresignGetCallerIdentity indicates an expected call of PresignGetCallerIdentity.
+func (mr *MockSTSServiceInterfaceMockRecorder) PresignGetCallerIdentity(ctx, input interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, input}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PresignGetCallerIdentity", reflect.TypeOf((*MockSTSServiceInterface)(nil).PresignGetCallerIdentity), varargs...)
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+)
+
+const defaultDNSSuffix = "amazonaws.com"
+
+// DNSSuffix returns the DNS suffix of the partition of the region, e.g. amazonaws.com.cn for the China regions and
+// c2s.ic.gov for the ISO regions. It is taken from the partition metadata of the SDK, which matches regions by the
+// pattern of their partition, so that regions the SDK doesn't list yet get the suffix of their partition as well. The
+// commercial suffix is returned if the region is empty or can't be resolved.
+func DNSSuffix(region string) string {
+	if region == "" {
+		return defaultDNSSuffix
+	}
+	// the partition metadata of the SDK is only exposed through the endpoint rules of the services, the regional
+	// endpoint of EC2 is ec2.<region>.<dnsSuffix> in all partitions
+	endpoint, err := ec2.NewDefaultEndpointResolverV2().ResolveEndpoint(context.Background(), ec2.EndpointParameters{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		return defaultDNSSuffix
+	}
+	suffix, ok := strings.CutPrefix(endpoint.URI.Host, "ec2."+region+".")
+	if !ok || suffix == "" {
+		return defaultDNSSuffix
+	}
+	return suffix
+}
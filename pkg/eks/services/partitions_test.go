@@ -0,0 +1,25 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDNSSuffix(t *testing.T) {
+	for region, suffix := range map[string]string{
+		"":               "amazonaws.com",
+		"us-east-1":      "amazonaws.com",
+		"ap-southeast-7": "amazonaws.com",
+		"us-gov-west-1":  "amazonaws.com",
+		"cn-north-1":     "amazonaws.com.cn",
+		"us-iso-east-1":  "c2s.ic.gov",
+		"us-isob-east-1": "sc2s.sgov.gov",
+		"eu-isoe-west-1": "cloud.adc-e.uk",
+		// regions that aren't listed yet get the suffix of the partition whose pattern they match
+		"cn-south-1":      "amazonaws.com.cn",
+		"us-isof-south-1": "csp.hci.ic.gov",
+	} {
+		assert.Equal(t, suffix, DNSSuffix(region), region)
+	}
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/sirupsen/logrus"
+)
+
+const redacted = "REDACTED"
+
+// sensitiveField matches the names of fields whose values are never logged, e.g. the user data of launch templates and
+// the credentials returned by STS. Names are matched in the JSON, query and XML payloads of all services.
+const sensitiveField = `[\w.]*(?i:userdata|secret|sessiontoken|password|privatekey|authorization)[\w.]*`
+
+var (
+	sensitiveJSONField  = regexp.MustCompile(`("` + sensitiveField + `"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	sensitiveQueryField = regexp.MustCompile(`((?:^|&)` + sensitiveField + `=)[^&]*`)
+	sensitiveXMLField   = regexp.MustCompile(`(<` + sensitiveField + `>)[^<]*`)
+)
+
+type payloadLoggingKey struct{}
+
+// LogPayloads returns a context that logs the payloads of the AWS requests sent with it and of their responses, with
+// sensitive fields redacted. The name identifies the cluster in the logs.
+func LogPayloads(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, payloadLoggingKey{}, name)
+}
+
+// WithPayloadLogging returns the config with the services created from it logging the payloads of requests sent with a
+// context returned by LogPayloads. Other requests are sent as they are.
+func WithPayloadLogging(cfg aws.Config) aws.Config {
+	client := cfg.HTTPClient
+	if client == nil {
+		client = awshttp.NewBuildableClient()
+	}
+	cfg.HTTPClient = &payloadLoggingHTTPClient{client: client}
+	return cfg
+}
+
+type payloadLoggingHTTPClient struct {
+	client aws.HTTPClient
+}
+
+func (c *payloadLoggingHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	name, ok := ctx.Value(payloadLoggingKey{}).(string)
+	if !ok {
+		return c.client.Do(req)
+	}
+
+	operation := fmt.Sprintf("%s %s", awsmiddleware.GetServiceID(ctx), awsmiddleware.GetOperationName(ctx))
+	if target := req.Header.Get("X-Amz-Target"); target != "" && awsmiddleware.GetOperationName(ctx) == "" {
+		// requests of the APIs called through jsonAPI identify their operation by the target header only
+		operation = target
+	}
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error reading body of request [%s]: %w", operation, err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		logrus.Infof("AWS request [%s] of cluster [%s]: %s", operation, name, redactPayload(body))
+	} else {
+		logrus.Infof("AWS request [%s] of cluster [%s] without body", operation, name)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		logrus.Infof("AWS request [%s] of cluster [%s] failed: %v", operation, name, err)
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error reading body of response to [%s]: %w", operation, err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	logrus.Infof("AWS response [%s] of cluster [%s] with status [%d]: %s", operation, name, resp.StatusCode, redactPayload(body))
+	return resp, nil
+}
+
+// redactPayload returns the JSON, query or XML payload of an AWS request or response with the values of sensitive fields
+// replaced.
+func redactPayload(payload []byte) string {
+	payload = sensitiveJSONField.ReplaceAll(payload, []byte(`$1"`+redacted+`"`))
+	payload = sensitiveQueryField.ReplaceAll(payload, []byte("${1}"+redacted))
+	payload = sensitiveXMLField.ReplaceAll(payload, []byte("${1}"+redacted))
+	return string(payload)
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactPayload(t *testing.T) {
+	assert.Equal(t, `{"name":"test","userData":"REDACTED","nested":{"SecretAccessKey":"REDACTED"}}`,
+		redactPayload([]byte(`{"name":"test","userData":"IyEvYmluL2Jhc2g=","nested":{"SecretAccessKey":"abc\"def"}}`)))
+	assert.Equal(t, "Action=CreateLaunchTemplateVersion&LaunchTemplateData.UserData=REDACTED&Version=2016-11-15",
+		redactPayload([]byte("Action=CreateLaunchTemplateVersion&LaunchTemplateData.UserData=IyEvYmluL2Jhc2g%3D&Version=2016-11-15")))
+	assert.Equal(t, "<Credentials><AccessKeyId>AKIA</AccessKeyId><SecretAccessKey>REDACTED</SecretAccessKey><SessionToken>REDACTED</SessionToken></Credentials>",
+		redactPayload([]byte("<Credentials><AccessKeyId>AKIA</AccessKeyId><SecretAccessKey>secret</SecretAccessKey><SessionToken>token</SessionToken></Credentials>")))
+}
+
+func TestWithPayloadLogging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"nodegroup":{"nodegroupName":"ng1","launchTemplate":{"name":"lt"}}}`))
+	}))
+	defer server.Close()
+
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	cfg := WithPayloadLogging(aws.Config{
+		Region:      "us-east-1",
+		Credentials: credentials.NewStaticCredentialsProvider("AKIA", "secret", ""),
+	})
+	client := eks.NewFromConfig(cfg, func(o *eks.Options) { o.BaseEndpoint = aws.String(server.URL) })
+	input := &eks.CreateNodegroupInput{
+		ClusterName:   aws.String("test"),
+		NodegroupName: aws.String("ng1"),
+		NodeRole:      aws.String("arn:aws:iam::123456789012:role/node"),
+		Subnets:       []string{"subnet-a"},
+		Labels:        map[string]string{"userData": base64.StdEncoding.EncodeToString([]byte("#!/bin/bash"))},
+	}
+
+	_, err := client.CreateNodegroup(context.Background(), input)
+	require.NoError(t, err)
+	assert.Empty(t, hook.AllEntries(), "payloads are only logged for requests with a LogPayloads context")
+
+	output, err := client.CreateNodegroup(LogPayloads(context.Background(), "test (id: c-abc)"), input)
+	require.NoError(t, err)
+	assert.Equal(t, "lt", aws.ToString(output.Nodegroup.LaunchTemplate.Name), "the response is still decoded")
+	entries := hook.AllEntries()
+	require.Len(t, entries, 2)
+	assert.Equal(t, logrus.InfoLevel, entries[0].Level)
+	assert.Contains(t, entries[0].Message, "AWS request [EKS CreateNodegroup] of cluster [test (id: c-abc)]")
+	assert.Contains(t, entries[0].Message, `"userData":"REDACTED"`)
+	assert.NotContains(t, entries[0].Message, "secret")
+	assert.Contains(t, entries[1].Message, "AWS response [EKS CreateNodegroup] of cluster [test (id: c-abc)] with status [200]")
+	assert.Contains(t, entries[1].Message, `"nodegroupName":"ng1"`)
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+const (
+	pricingTarget = "AWSPriceListService.GetProducts"
+	// DNS suffix of the China partition, whose prices are served from a region of their own
+	chinaDNSSuffix = "amazonaws.com.cn"
+)
+
+type PricingServiceInterface interface {
+	GetProducts(ctx context.Context, input *GetProductsInput) (*GetProductsOutput, error)
+}
+
+// GetProductsInput mirrors the input of the GetProducts operation of the AWS Price List API.
+type GetProductsInput struct {
+	ServiceCode string          `json:"ServiceCode"`
+	Filters     []PricingFilter `json:"Filters,omitempty"`
+	NextToken   *string         `json:"NextToken,omitempty"`
+	MaxResults  *int32          `json:"MaxResults,omitempty"`
+}
+
+// PricingFilter matches products whose attribute Field equals Value.
+type PricingFilter struct {
+	Type  string `json:"Type"`
+	Field string `json:"Field"`
+	Value string `json:"Value"`
+}
+
+// GetProductsOutput mirrors the output of the GetProducts operation, each price list entry is a JSON document
+// describing a product and its terms.
+type GetProductsOutput struct {
+	PriceList []string `json:"PriceList"`
+	NextToken *string  `json:"NextToken"`
+}
+
+type pricingService struct {
+	api *jsonAPI
+}
+
+func NewPricingService(cfg aws.Config) PricingServiceInterface {
+	region := pricingRegion(cfg.Region)
+	// the Price List API has no FIPS endpoint
+	endpoint := endpointURL("pricing", region)
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://api.pricing.%s.%s/", region, DNSSuffix(region))
+	}
+	return &pricingService{
+		api: &jsonAPI{
+			cfg:      cfg,
+			signer:   v4.NewSigner(),
+			service:  "pricing",
+			region:   region,
+			endpoint: endpoint,
+		},
+	}
+}
+
+// pricingRegion returns the region the AWS Price List API serves the prices of the partition of the given region from.
+// It is only served from a few regions, the prices it returns cover all regions of their partition. The other
+// partitions have no Price List API, so cost estimation fails there.
+func pricingRegion(region string) string {
+	if DNSSuffix(region) == chinaDNSSuffix {
+		return "cn-northwest-1"
+	}
+	return "us-east-1"
+}
+
+func (c *pricingService) GetProducts(ctx context.Context, input *GetProductsInput) (*GetProductsOutput, error) {
+	output := &GetProductsOutput{}
+	if err := c.api.call(ctx, pricingTarget, input, output); err != nil {
+		return nil, fmt.Errorf("error calling price list API: %w", err)
+	}
+	return output, nil
+}
@@ -0,0 +1,25 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPricingService(t *testing.T) {
+	tests := []struct {
+		region   string
+		expected string
+	}{
+		{region: "eu-west-1", expected: "https://api.pricing.us-east-1.amazonaws.com/"},
+		{region: "cn-north-1", expected: "https://api.pricing.cn-northwest-1.amazonaws.com.cn/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.region, func(t *testing.T) {
+			api := NewPricingService(aws.Config{Region: tt.region}).(*pricingService).api
+			assert.Equal(t, tt.expected, api.endpoint)
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"golang.org/x/time/rate"
+)
+
+// RateLimitOpts configures the client-side throttling of the requests sent to AWS. It is shared by the services of all
+// clusters, so that a large number of clusters doesn't exceed the API request limits of an account.
+type RateLimitOpts struct {
+	// QPS is the number of requests per second that are sent to AWS, requests aren't limited if it is zero
+	QPS float64
+	// Burst is the number of requests that can be sent at once, it defaults to QPS rounded up
+	Burst int
+	// RetryMode is standard or adaptive, adaptive additionally slows down all requests once AWS throttles them, the
+	// SDK default is used if it is empty
+	RetryMode string
+	// MaxAttempts and MaxBackoff override the number of attempts of a request and the longest wait between its retries
+	MaxAttempts int
+	MaxBackoff  time.Duration
+}
+
+var rateLimiting = struct {
+	sync.RWMutex
+	opts     RateLimitOpts
+	limiter  *rate.Limiter
+	adaptive aws.Retryer
+}{}
+
+// SetRateLimiting configures the throttling applied by WithRateLimiting.
+func SetRateLimiting(opts RateLimitOpts) error {
+	if opts.QPS < 0 || opts.Burst < 0 || opts.MaxAttempts < 0 || opts.MaxBackoff < 0 {
+		return fmt.Errorf("rate limiting options can't be negative")
+	}
+
+	var retryMode aws.RetryMode
+	if opts.RetryMode != "" {
+		mode, err := aws.ParseRetryMode(opts.RetryMode)
+		if err != nil {
+			return err
+		}
+		retryMode = mode
+	}
+
+	var limiter *rate.Limiter
+	if opts.QPS > 0 {
+		burst := opts.Burst
+		if burst == 0 {
+			burst = int(math.Ceil(opts.QPS))
+		}
+		limiter = rate.NewLimiter(rate.Limit(opts.QPS), burst)
+	}
+
+	var adaptive aws.Retryer
+	if retryMode == aws.RetryModeAdaptive {
+		// a single retryer is shared so that throttling of any request slows down all of them
+		adaptive = retry.NewAdaptiveMode(func(o *retry.AdaptiveModeOptions) {
+			o.StandardOptions = append(o.StandardOptions, standardRetryOptions(opts))
+		})
+	}
+
+	rateLimiting.Lock()
+	defer rateLimiting.Unlock()
+	rateLimiting.opts = opts
+	rateLimiting.limiter = limiter
+	rateLimiting.adaptive = adaptive
+	return nil
+}
+
+// WithRateLimiting returns the config with the configured throttling applied to the services created from it.
+func WithRateLimiting(cfg aws.Config) aws.Config {
+	rateLimiting.RLock()
+	defer rateLimiting.RUnlock()
+
+	if limiter := rateLimiting.limiter; limiter != nil {
+		client := cfg.HTTPClient
+		if client == nil {
+			client = awshttp.NewBuildableClient()
+		}
+		cfg.HTTPClient = &rateLimitedHTTPClient{client: client, limiter: limiter}
+	}
+
+	opts := rateLimiting.opts
+	switch {
+	case rateLimiting.adaptive != nil:
+		adaptive := rateLimiting.adaptive
+		cfg.Retryer = func() aws.Retryer { return adaptive }
+	case opts.RetryMode != "" || opts.MaxAttempts > 0 || opts.MaxBackoff > 0:
+		cfg.Retryer = func() aws.Retryer { return retry.NewStandard(standardRetryOptions(opts)) }
+	}
+
+	return cfg
+}
+
+func standardRetryOptions(opts RateLimitOpts) func(*retry.StandardOptions) {
+	return func(o *retry.StandardOptions) {
+		if opts.MaxAttempts > 0 {
+			o.MaxAttempts = opts.MaxAttempts
+		}
+		if opts.MaxBackoff > 0 {
+			o.MaxBackoff = opts.MaxBackoff
+			o.Backoff = retry.NewExponentialJitterBackoff(opts.MaxBackoff)
+		}
+	}
+}
+
+// rateLimitedHTTPClient waits for the limiter before sending each request, including retries.
+type rateLimitedHTTPClient struct {
+	client  aws.HTTPClient
+	limiter *rate.Limiter
+}
+
+func (c *rateLimitedHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	if err := c.limiter.Wait(req.Context()); err != nil {
+		return nil, fmt.Errorf("error waiting for rate limiter: %w", err)
+	}
+	return c.client.Do(req)
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRateLimiting(t *testing.T) {
+	t.Cleanup(func() { require.NoError(t, SetRateLimiting(RateLimitOpts{})) })
+
+	cfg := WithRateLimiting(aws.Config{})
+	assert.Nil(t, cfg.HTTPClient)
+	assert.Nil(t, cfg.Retryer)
+
+	require.NoError(t, SetRateLimiting(RateLimitOpts{QPS: 2.5, MaxAttempts: 5, MaxBackoff: time.Minute}))
+	cfg = WithRateLimiting(aws.Config{HTTPClient: http.DefaultClient})
+	client, ok := cfg.HTTPClient.(*rateLimitedHTTPClient)
+	require.True(t, ok)
+	assert.Equal(t, http.DefaultClient, client.client)
+	assert.Equal(t, 3, client.limiter.Burst())
+	retryer := cfg.Retryer()
+	assert.Equal(t, 5, retryer.MaxAttempts())
+	assert.NotSame(t, retryer, cfg.Retryer())
+
+	require.NoError(t, SetRateLimiting(RateLimitOpts{RetryMode: "adaptive"}))
+	cfg = WithRateLimiting(aws.Config{})
+	assert.Nil(t, cfg.HTTPClient)
+	assert.IsType(t, &retry.AdaptiveMode{}, cfg.Retryer())
+	// the adaptive retryer is shared by all configs
+	assert.Same(t, cfg.Retryer(), WithRateLimiting(aws.Config{}).Retryer())
+
+	assert.Error(t, SetRateLimiting(RateLimitOpts{RetryMode: "fast"}))
+	assert.Error(t, SetRateLimiting(RateLimitOpts{QPS: -1}))
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+)
+
+// stackCache is a CloudFormationServiceInterface that caches the stacks described by name or ID, so that a stack is
+// described once per reconcile however many times it is created, polled or has its outputs read. Creating, updating or
+// deleting a stack drops it from the cache.
+type stackCache struct {
+	CloudFormationServiceInterface
+
+	mu sync.Mutex
+	// stacks maps the names and IDs stacks were described by, and the IDs of the described stacks, to the results
+	stacks map[string]*cloudformation.DescribeStacksOutput
+}
+
+// NewStackCache returns a CloudFormation service that caches the results of DescribeStacks calls for a single stack.
+// The cache is never refreshed, so it is meant to live for a single reconcile, and the returned outputs are shared and
+// must not be modified.
+func NewStackCache(svc CloudFormationServiceInterface) CloudFormationServiceInterface {
+	return &stackCache{
+		CloudFormationServiceInterface: svc,
+		stacks:                         map[string]*cloudformation.DescribeStacksOutput{},
+	}
+}
+
+func (c *stackCache) DescribeStacks(ctx context.Context, input *cloudformation.DescribeStacksInput) (*cloudformation.DescribeStacksOutput, error) {
+	// listing all stacks isn't cached
+	stackName := aws.ToString(input.StackName)
+	if stackName == "" || input.NextToken != nil {
+		return c.CloudFormationServiceInterface.DescribeStacks(ctx, input)
+	}
+
+	c.mu.Lock()
+	output, ok := c.stacks[stackName]
+	c.mu.Unlock()
+	if ok {
+		return output, nil
+	}
+
+	output, err := c.CloudFormationServiceInterface.DescribeStacks(ctx, input)
+	if err != nil {
+		return output, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stacks[stackName] = output
+	for _, stack := range output.Stacks {
+		if stackID := aws.ToString(stack.StackId); stackID != "" {
+			c.stacks[stackID] = output
+		}
+	}
+	return output, nil
+}
+
+func (c *stackCache) CreateStack(ctx context.Context, input *cloudformation.CreateStackInput) (*cloudformation.CreateStackOutput, error) {
+	c.invalidate(aws.ToString(input.StackName))
+	return c.CloudFormationServiceInterface.CreateStack(ctx, input)
+}
+
+func (c *stackCache) UpdateStack(ctx context.Context, input *cloudformation.UpdateStackInput) (*cloudformation.UpdateStackOutput, error) {
+	c.invalidate(aws.ToString(input.StackName))
+	return c.CloudFormationServiceInterface.UpdateStack(ctx, input)
+}
+
+func (c *stackCache) DeleteStack(ctx context.Context, input *cloudformation.DeleteStackInput) (*cloudformation.DeleteStackOutput, error) {
+	c.invalidate(aws.ToString(input.StackName))
+	return c.CloudFormationServiceInterface.DeleteStack(ctx, input)
+}
+
+// invalidate drops the stack with the given name or ID from the cache, along with the other keys it was cached under.
+func (c *stackCache) invalidate(stackName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	output, ok := c.stacks[stackName]
+	if !ok {
+		return
+	}
+	for key, cached := range c.stacks {
+		if cached == output {
+			delete(c.stacks, key)
+		}
+	}
+}
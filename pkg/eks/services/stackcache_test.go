@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	cftypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCloudFormationService struct {
+	CloudFormationServiceInterface
+	describeCalls int
+}
+
+func (f *fakeCloudFormationService) DescribeStacks(_ context.Context, input *cloudformation.DescribeStacksInput) (*cloudformation.DescribeStacksOutput, error) {
+	f.describeCalls++
+	if input.StackName == nil {
+		return &cloudformation.DescribeStacksOutput{}, nil
+	}
+	return &cloudformation.DescribeStacksOutput{
+		Stacks: []cftypes.Stack{{StackName: aws.String("stack"), StackId: aws.String("stack-id")}},
+	}, nil
+}
+
+func (f *fakeCloudFormationService) CreateStack(context.Context, *cloudformation.CreateStackInput) (*cloudformation.CreateStackOutput, error) {
+	return &cloudformation.CreateStackOutput{}, nil
+}
+
+func (f *fakeCloudFormationService) DeleteStack(context.Context, *cloudformation.DeleteStackInput) (*cloudformation.DeleteStackOutput, error) {
+	return &cloudformation.DeleteStackOutput{}, nil
+}
+
+func TestStackCache(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeCloudFormationService{}
+	svc := NewStackCache(fake)
+
+	output, err := svc.DescribeStacks(ctx, &cloudformation.DescribeStacksInput{StackName: aws.String("stack")})
+	require.NoError(t, err)
+	cached, err := svc.DescribeStacks(ctx, &cloudformation.DescribeStacksInput{StackName: aws.String("stack")})
+	require.NoError(t, err)
+	assert.Same(t, output, cached)
+	// the stack is cached under its ID as well
+	cached, err = svc.DescribeStacks(ctx, &cloudformation.DescribeStacksInput{StackName: aws.String("stack-id")})
+	require.NoError(t, err)
+	assert.Same(t, output, cached)
+	assert.Equal(t, 1, fake.describeCalls)
+
+	// listing stacks isn't cached
+	_, err = svc.DescribeStacks(ctx, &cloudformation.DescribeStacksInput{})
+	require.NoError(t, err)
+	_, err = svc.DescribeStacks(ctx, &cloudformation.DescribeStacksInput{})
+	require.NoError(t, err)
+	assert.Equal(t, 3, fake.describeCalls)
+
+	_, err = svc.CreateStack(ctx, &cloudformation.CreateStackInput{StackName: aws.String("stack")})
+	require.NoError(t, err)
+	_, err = svc.DescribeStacks(ctx, &cloudformation.DescribeStacksInput{StackName: aws.String("stack-id")})
+	require.NoError(t, err)
+	assert.Equal(t, 4, fake.describeCalls)
+
+	_, err = svc.DeleteStack(ctx, &cloudformation.DeleteStackInput{StackName: aws.String("stack-id")})
+	require.NoError(t, err)
+	_, err = svc.DescribeStacks(ctx, &cloudformation.DescribeStacksInput{StackName: aws.String("stack")})
+	require.NoError(t, err)
+	assert.Equal(t, 5, fake.describeCalls)
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+type STSServiceInterface interface {
+	GetCallerIdentity(ctx context.Context, input *sts.GetCallerIdentityInput) (*sts.GetCallerIdentityOutput, error)
+	PresignGetCallerIdentity(ctx context.Context, input *sts.GetCallerIdentityInput, optFns ...func(*sts.PresignOptions)) (*v4.PresignedHTTPRequest, error)
+}
+
+type stsService struct {
+	svc     *sts.Client
+	presign *sts.PresignClient
+}
+
+func NewSTSService(cfg aws.Config) STSServiceInterface {
+	svc := sts.NewFromConfig(cfg, func(o *sts.Options) {
+		applyEndpoint("sts", cfg.Region, &o.BaseEndpoint, &o.EndpointOptions.UseFIPSEndpoint)
+	})
+	return &stsService{
+		svc:     svc,
+		presign: sts.NewPresignClient(svc),
+	}
+}
+
+func (c *stsService) GetCallerIdentity(ctx context.Context, input *sts.GetCallerIdentityInput) (*sts.GetCallerIdentityOutput, error) {
+	return c.svc.GetCallerIdentity(ctx, input)
+}
+
+func (c *stsService) PresignGetCallerIdentity(ctx context.Context, input *sts.GetCallerIdentityInput, optFns ...func(*sts.PresignOptions)) (*v4.PresignedHTTPRequest, error) {
+	return c.presign.PresignGetCallerIdentity(ctx, input, optFns...)
+}
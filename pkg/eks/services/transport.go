@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// TransportOpts configures the proxy and the trusted certificate authorities of the requests sent to AWS, for
+// environments that reach AWS through a proxy, possibly one that intercepts TLS. It is shared by the services of all
+// clusters.
+type TransportOpts struct {
+	// Proxy is the URL of the proxy requests are sent through, the HTTP_PROXY and HTTPS_PROXY environment variables are
+	// used if both Proxy and NoProxy are empty
+	Proxy string
+	// NoProxy is a comma separated list of hosts, domains and CIDRs that are reached without the proxy, in the format
+	// of the NO_PROXY environment variable
+	NoProxy string
+	// CABundle holds PEM encoded certificate authorities that are trusted in addition to the system ones
+	CABundle []byte
+}
+
+var transport = struct {
+	sync.RWMutex
+	proxy   func(*http.Request) (*url.URL, error)
+	rootCAs *x509.CertPool
+}{}
+
+// SetTransport configures the transport applied by WithTransport and NewHTTPTransport.
+func SetTransport(opts TransportOpts) error {
+	var proxy func(*http.Request) (*url.URL, error)
+	if opts.Proxy != "" || opts.NoProxy != "" {
+		if opts.Proxy != "" {
+			proxyURL, err := url.Parse(opts.Proxy)
+			if err != nil || proxyURL.Host == "" {
+				return fmt.Errorf("invalid proxy [%s], must be a URL", opts.Proxy)
+			}
+		}
+		proxyFunc := (&httpproxy.Config{HTTPProxy: opts.Proxy, HTTPSProxy: opts.Proxy, NoProxy: opts.NoProxy}).ProxyFunc()
+		proxy = func(req *http.Request) (*url.URL, error) {
+			return proxyFunc(req.URL)
+		}
+	}
+
+	var rootCAs *x509.CertPool
+	if len(opts.CABundle) != 0 {
+		var err error
+		rootCAs, err = x509.SystemCertPool()
+		if err != nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if !rootCAs.AppendCertsFromPEM(opts.CABundle) {
+			return fmt.Errorf("CA bundle doesn't contain any PEM encoded certificates")
+		}
+	}
+
+	transport.Lock()
+	defer transport.Unlock()
+	transport.proxy = proxy
+	transport.rootCAs = rootCAs
+	return nil
+}
+
+// WithTransport returns the config with the configured proxy and certificate authorities applied to the services
+// created from it. The config is left alone if neither is configured, so that the environment still applies.
+func WithTransport(cfg aws.Config) aws.Config {
+	transport.RLock()
+	defer transport.RUnlock()
+	if transport.proxy == nil && transport.rootCAs == nil {
+		return cfg
+	}
+
+	client, ok := cfg.HTTPClient.(*awshttp.BuildableClient)
+	if !ok || client == nil {
+		client = awshttp.NewBuildableClient()
+	}
+	proxy, rootCAs := transport.proxy, transport.rootCAs
+	cfg.HTTPClient = client.WithTransportOptions(func(t *http.Transport) {
+		applyTransport(t, proxy, rootCAs)
+	})
+	return cfg
+}
+
+// NewHTTPTransport returns a transport for requests sent outside of the AWS services, like fetching the certificates of
+// OIDC issuers, with the configured proxy and certificate authorities applied.
+func NewHTTPTransport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+
+	transport.RLock()
+	defer transport.RUnlock()
+	applyTransport(t, transport.proxy, transport.rootCAs)
+	return t
+}
+
+// NewOIDCIssuerTransport returns the transport for requests to the OIDC issuers of clusters in the region. If the oidc
+// endpoint is overridden, connections are made to its host instead of the issuer, without the proxy, while TLS is still
+// verified against the host name of the issuer, so that issuers can be reached through a TLS passthrough proxy or load
+// balancer in networks without internet access.
+func NewOIDCIssuerTransport(region string) (*http.Transport, error) {
+	t := NewHTTPTransport()
+	endpoint := endpointURL("oidc", region)
+	if endpoint == "" {
+		return t, nil
+	}
+
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid oidc endpoint [%s]: %w", endpoint, err)
+	}
+	address := parsed.Host
+	if parsed.Port() == "" {
+		address = net.JoinHostPort(parsed.Hostname(), "443")
+	}
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	t.Proxy = nil
+	t.DialContext = func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return dialer.DialContext(ctx, network, address)
+	}
+	return t, nil
+}
+
+func applyTransport(t *http.Transport, proxy func(*http.Request) (*url.URL, error), rootCAs *x509.CertPool) {
+	if proxy != nil {
+		t.Proxy = proxy
+	}
+	if rootCAs != nil {
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		t.TLSClientConfig.RootCAs = rootCAs
+	}
+}
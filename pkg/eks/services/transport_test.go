@@ -0,0 +1,97 @@
+package services
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTransport(t *testing.T) {
+	t.Cleanup(func() { require.NoError(t, SetTransport(TransportOpts{})) })
+
+	cfg := WithTransport(aws.Config{})
+	assert.Nil(t, cfg.HTTPClient)
+
+	assert.Error(t, SetTransport(TransportOpts{Proxy: "proxy"}))
+	assert.Error(t, SetTransport(TransportOpts{CABundle: []byte("not a certificate")}))
+
+	require.NoError(t, SetTransport(TransportOpts{
+		Proxy:    "http://proxy.example.com:3128",
+		NoProxy:  ".internal.example.com",
+		CABundle: testCertificate(t),
+	}))
+	cfg = WithTransport(aws.Config{})
+	client, ok := cfg.HTTPClient.(*awshttp.BuildableClient)
+	require.True(t, ok)
+	transport := client.GetTransport()
+	assert.NotNil(t, transport.TLSClientConfig.RootCAs)
+
+	for _, transport := range []*http.Transport{transport, NewHTTPTransport()} {
+		req, err := http.NewRequest(http.MethodGet, "https://eks.us-west-2.amazonaws.com", nil)
+		require.NoError(t, err)
+		proxyURL, err := transport.Proxy(req)
+		require.NoError(t, err)
+		assert.Equal(t, "http://proxy.example.com:3128", proxyURL.String())
+
+		req, err = http.NewRequest(http.MethodGet, "https://oidc.internal.example.com", nil)
+		require.NoError(t, err)
+		proxyURL, err = transport.Proxy(req)
+		require.NoError(t, err)
+		assert.Nil(t, proxyURL)
+	}
+}
+
+func TestNewOIDCIssuerTransport(t *testing.T) {
+	t.Cleanup(func() { require.NoError(t, SetEndpoints(EndpointOpts{})) })
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "example.com", r.Host)
+		assert.Equal(t, "/id/abc", r.URL.Path)
+	}))
+	defer server.Close()
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(server.Certificate())
+
+	transport, err := NewOIDCIssuerTransport("us-west-2")
+	require.NoError(t, err)
+	assert.NotNil(t, transport.Proxy, "issuers are reached through the proxy of the environment by default")
+
+	// the certificate of the test server is valid for example.com, which is only reachable through the endpoint
+	require.NoError(t, SetEndpoints(EndpointOpts{URLs: map[string]string{"oidc": server.URL}}))
+	transport, err = NewOIDCIssuerTransport("us-west-2")
+	require.NoError(t, err)
+	assert.Nil(t, transport.Proxy)
+	transport.TLSClientConfig.RootCAs = rootCAs
+	resp, err := (&http.Client{Transport: transport}).Get("https://example.com/id/abc")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func testCertificate(t *testing.T) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
@@ -0,0 +1,78 @@
+package eks
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/version"
+)
+
+// keys of the ownership tags set on the AWS resources the operator creates
+const (
+	ManagedTagKey         = "rancher.io/managed"
+	ClusterNameTagKey     = "eks.cattle.io/cluster-name"
+	NamespaceTagKey       = "eks.cattle.io/namespace"
+	UIDTagKey             = "eks.cattle.io/uid"
+	OperatorVersionTagKey = "eks.cattle.io/operator-version"
+)
+
+// resourceTags are set on all AWS resources the operator creates, e.g. for chargeback
+var resourceTags map[string]string
+
+// SetResourceTags sets the tags that are added to all AWS resources the operator creates. Tags set in the spec of a
+// cluster take precedence over them.
+func SetResourceTags(tags map[string]string) error {
+	for key := range tags {
+		if isOwnershipTagKey(key) {
+			return fmt.Errorf("resource tag [%s] is set by the operator", key)
+		}
+		if strings.HasPrefix(key, "aws:") {
+			return fmt.Errorf("resource tag [%s] uses the reserved aws: prefix", key)
+		}
+	}
+	resourceTags = maps.Clone(tags)
+	return nil
+}
+
+// OwnershipTags returns the tags set on the AWS resources the operator creates for the cluster, the resource tags of
+// the operator along with the ownership tags identifying the config and the operator.
+func OwnershipTags(config *eksv1.EKSClusterConfig) map[string]string {
+	return WithOwnershipTags(config, nil)
+}
+
+// WithOwnershipTags returns the given tags with the resource tags of the operator and the ownership tags of the
+// config added. The given tags take precedence over the resource tags, the ownership tags can't be overridden.
+func WithOwnershipTags(config *eksv1.EKSClusterConfig, tags map[string]string) map[string]string {
+	result := make(map[string]string, len(resourceTags)+len(tags)+5)
+	maps.Copy(result, resourceTags)
+	maps.Copy(result, tags)
+	result[ManagedTagKey] = "true"
+	result[ClusterNameTagKey] = config.Spec.DisplayName
+	if config.Namespace != "" {
+		result[NamespaceTagKey] = config.Namespace
+	}
+	if config.UID != "" {
+		result[UIDTagKey] = string(config.UID)
+	}
+	if version.Version != "" {
+		result[OperatorVersionTagKey] = version.Version
+	}
+	return result
+}
+
+func isOwnershipTagKey(key string) bool {
+	return slices.Contains([]string{ManagedTagKey, ClusterNameTagKey, NamespaceTagKey, UIDTagKey, OperatorVersionTagKey}, key)
+}
+
+// sortedTagKeys returns the keys of the tags in order, so that requests are built the same way every time.
+func sortedTagKeys(tags map[string]string) []string {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,51 @@
+package eks
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/version"
+)
+
+var _ = Describe("WithOwnershipTags", func() {
+	config := &eksv1.EKSClusterConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "c-abc", Namespace: "cattle-global-data", UID: "1234"},
+		Spec:       eksv1.EKSClusterConfigSpec{DisplayName: "test"},
+	}
+
+	BeforeEach(func() {
+		version.Version = "v1.2.3"
+		Expect(SetResourceTags(map[string]string{"cost-center": "42", "team": "platform"})).To(Succeed())
+	})
+
+	AfterEach(func() {
+		version.Version = ""
+		Expect(SetResourceTags(nil)).To(Succeed())
+	})
+
+	It("should add the resource tags of the operator and the ownership tags of the config", func() {
+		Expect(OwnershipTags(config)).To(Equal(map[string]string{
+			"cost-center":         "42",
+			"team":                "platform",
+			ManagedTagKey:         "true",
+			ClusterNameTagKey:     "test",
+			NamespaceTagKey:       "cattle-global-data",
+			UIDTagKey:             "1234",
+			OperatorVersionTagKey: "v1.2.3",
+		}))
+	})
+
+	It("should prefer the given tags over the resource tags but not over the ownership tags", func() {
+		tags := WithOwnershipTags(config, map[string]string{"team": "data", ManagedTagKey: "false"})
+		Expect(tags).To(HaveKeyWithValue("team", "data"))
+		Expect(tags).To(HaveKeyWithValue("cost-center", "42"))
+		Expect(tags).To(HaveKeyWithValue(ManagedTagKey, "true"))
+	})
+
+	It("should reject resource tags the operator sets or AWS reserves", func() {
+		Expect(SetResourceTags(map[string]string{UIDTagKey: "1"})).ToNot(Succeed())
+		Expect(SetResourceTags(map[string]string{"aws:cloudformation:stack-name": "test"})).ToNot(Succeed())
+	})
+})
@@ -0,0 +1,82 @@
+package eks
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+)
+
+// names of the CloudFormation templates that can be overridden, which are the keys of the ConfigMap referenced by the
+// cloudFormationTemplates field of the spec
+const (
+	TemplateVPC                     = "vpc"
+	TemplatePrivateVPC              = "privateVpc"
+	TemplateServiceRole             = "serviceRole"
+	TemplateNodeInstanceRole        = "nodeInstanceRole"
+	TemplateEBSCSIDriver            = "ebsCsiDriver"
+	TemplateEBSCSIDriverPodIdentity = "ebsCsiDriverPodIdentity"
+)
+
+// TemplateNames are the names of all CloudFormation templates that can be overridden.
+var TemplateNames = []string{
+	TemplateVPC,
+	TemplatePrivateVPC,
+	TemplateServiceRole,
+	TemplateNodeInstanceRole,
+	TemplateEBSCSIDriver,
+	TemplateEBSCSIDriverPodIdentity,
+}
+
+// TemplateOutputs are the outputs the stacks created from each CloudFormation template must return, overrides of the
+// templates have to return them as well.
+var TemplateOutputs = map[string][]string{
+	TemplateVPC:                     {"VpcId", "SubnetIds"},
+	TemplatePrivateVPC:              {"VpcId", "SubnetIds"},
+	TemplateServiceRole:             {"RoleArn"},
+	TemplateNodeInstanceRole:        {"NodeInstanceRole"},
+	TemplateEBSCSIDriver:            {"EBSCSIDriverRole"},
+	TemplateEBSCSIDriverPodIdentity: {"EBSCSIDriverPodIdentityRole"},
+}
+
+// TemplateParameters are the parameters CloudFormation template overrides are executed with.
+type TemplateParameters struct {
+	ClusterName string
+	Region      string
+	// service principal of EC2 in the partition of the region, e.g. ec2.amazonaws.com
+	EC2ServicePrincipal string
+	// ID of the OIDC provider of the cluster, only set for the ebsCsiDriver template
+	ProviderID string
+}
+
+// RenderTemplate returns the override of the template with the given name executed with the parameters, or the
+// built-in template if it isn't overridden.
+func RenderTemplate(overrides map[string]string, name, builtIn string, params TemplateParameters) (string, error) {
+	override, ok := overrides[name]
+	if !ok {
+		return builtIn, nil
+	}
+	return executeTemplate(name, override, params)
+}
+
+// NewTemplateParameters returns the parameters of the CloudFormation templates of the cluster.
+func NewTemplateParameters(config *eksv1.EKSClusterConfig) TemplateParameters {
+	return TemplateParameters{
+		ClusterName:         config.Spec.DisplayName,
+		Region:              config.Spec.Region,
+		EC2ServicePrincipal: getEC2ServiceEndpoint(config.Spec.Region),
+	}
+}
+
+func executeTemplate(name, body string, params TemplateParameters) (string, error) {
+	tmpl, err := template.New(name).Parse(body)
+	if err != nil {
+		return "", fmt.Errorf("error parsing CloudFormation template [%s]: %w", name, err)
+	}
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, params); err != nil {
+		return "", fmt.Errorf("error executing CloudFormation template [%s]: %w", name, err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,64 @@
+package eks
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/templates"
+)
+
+var _ = Describe("RenderTemplate", func() {
+	params := NewTemplateParameters(&eksv1.EKSClusterConfig{
+		Spec: eksv1.EKSClusterConfigSpec{DisplayName: "test", Region: "cn-north-1"},
+	})
+
+	It("should return the built-in template if it isn't overridden", func() {
+		body, err := RenderTemplate(map[string]string{TemplateVPC: "vpc"}, TemplateServiceRole, "built-in {{.Region}}", params)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(body).To(Equal("built-in {{.Region}}"))
+	})
+
+	It("should execute the override with the parameters of the cluster", func() {
+		body, err := RenderTemplate(map[string]string{TemplateNodeInstanceRole: "{{.ClusterName}} {{.Region}} {{.EC2ServicePrincipal}}"},
+			TemplateNodeInstanceRole, "built-in", params)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(body).To(Equal("test cn-north-1 ec2.amazonaws.com.cn"))
+	})
+
+	It("should fail for overrides with unknown parameters", func() {
+		_, err := RenderTemplate(map[string]string{TemplateVPC: "{{.VpcID}}"}, TemplateVPC, "built-in", params)
+		Expect(err).To(MatchError(ContainSubstring("error executing CloudFormation template [vpc]")))
+
+		_, err = RenderTemplate(map[string]string{TemplateVPC: "{{.Region"}, TemplateVPC, "built-in", params)
+		Expect(err).To(MatchError(ContainSubstring("error parsing CloudFormation template [vpc]")))
+	})
+})
+
+var _ = Describe("TemplateOutputs", func() {
+	It("should declare the outputs of all templates", func() {
+		for _, name := range TemplateNames {
+			Expect(TemplateOutputs[name]).ToNot(BeEmpty(), name)
+		}
+	})
+
+	It("should only require outputs the built-in templates return", func() {
+		builtIn := map[string]string{
+			TemplateVPC:                     templates.VpcTemplate,
+			TemplatePrivateVPC:              templates.PrivateVpcTemplate,
+			TemplateServiceRole:             templates.ServiceRoleTemplate,
+			TemplateNodeInstanceRole:        templates.NodeInstanceRoleTemplate,
+			TemplateEBSCSIDriver:            templates.EBSCSIDriverTemplate,
+			TemplateEBSCSIDriverPodIdentity: templates.EBSCSIDriverPodIdentityTemplate,
+		}
+		for name, outputs := range TemplateOutputs {
+			_, declared, found := strings.Cut(builtIn[name], "\nOutputs:\n")
+			Expect(found).To(BeTrue(), name)
+			for _, key := range outputs {
+				Expect(declared).To(ContainSubstring("\n  "+key+":\n"), name)
+			}
+		}
+	})
+})
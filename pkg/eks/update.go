@@ -2,20 +2,34 @@ package eks
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	cftypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/aws/aws-sdk-go-v2/service/eks"
 	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/smithy-go"
 	"github.com/sirupsen/logrus"
 
 	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
 	"github.com/rancher/eks-operator/pkg/eks/services"
+	"github.com/rancher/eks-operator/templates"
 	"github.com/rancher/eks-operator/utils"
 )
 
 const (
 	allOpen = "0.0.0.0/0"
+
+	oidcIdentityProviderType = "oidc"
 )
 
 type UpdateClusterVersionOpts struct {
@@ -50,8 +64,14 @@ type UpdateResourceTagsOpts struct {
 	ResourceARN  string
 }
 
+// UpdateResourceTags updates the tags of the resource to match the given tags. Tags are left untouched if the given
+// tags are nil, while an empty map removes all of them.
 func UpdateResourceTags(ctx context.Context, opts *UpdateResourceTagsOpts) (bool, error) {
 	updated := false
+	if opts.Tags == nil {
+		return updated, nil
+	}
+
 	if updateTags := utils.GetKeyValuesToUpdate(opts.Tags, opts.UpstreamTags); updateTags != nil {
 		logrus.Infof("Updating resource tags to %v for cluster [%s]", opts.Tags, opts.ClusterName)
 		logrus.Debugf("config: %v, upstream: %v", opts.Tags, opts.UpstreamTags)
@@ -85,12 +105,73 @@ func UpdateResourceTags(ctx context.Context, opts *UpdateResourceTagsOpts) (bool
 	return updated, nil
 }
 
+type UpdateSecurityGroupTagsOpts struct {
+	EC2Service      services.EC2ServiceInterface
+	SecurityGroupID string
+	Tags            map[string]string
+}
+
+// UpdateSecurityGroupTags adds the tags that are missing from the security group or have a different value and returns
+// whether any were added. Other tags of the security group are left untouched, since EKS and other tools tag it as
+// well, and tags with the reserved aws: prefix are skipped.
+func UpdateSecurityGroupTags(ctx context.Context, opts *UpdateSecurityGroupTagsOpts) (bool, error) {
+	if opts.SecurityGroupID == "" || len(opts.Tags) == 0 {
+		return false, nil
+	}
+
+	input := &ec2.DescribeTagsInput{
+		Filters: []ec2types.Filter{{Name: aws.String("resource-id"), Values: []string{opts.SecurityGroupID}}},
+	}
+	upstreamTags := map[string]string{}
+	for {
+		output, err := opts.EC2Service.DescribeTags(ctx, input)
+		if err != nil {
+			return false, fmt.Errorf("error describing tags of security group [%s]: %w", opts.SecurityGroupID, err)
+		}
+		for _, tag := range output.Tags {
+			upstreamTags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+		if aws.ToString(output.NextToken) == "" {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+
+	var keys []string
+	for key, value := range opts.Tags {
+		if upstreamValue, ok := upstreamTags[key]; (ok && upstreamValue == value) || strings.HasPrefix(key, "aws:") {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return false, nil
+	}
+	slices.Sort(keys)
+	tags := make([]ec2types.Tag, 0, len(keys))
+	for _, key := range keys {
+		tags = append(tags, ec2types.Tag{Key: aws.String(key), Value: aws.String(opts.Tags[key])})
+	}
+
+	logrus.Infof("Adding %d tags to security group [%s]", len(tags), opts.SecurityGroupID)
+	_, err := opts.EC2Service.CreateTags(ctx, &ec2.CreateTagsInput{
+		Resources: []string{opts.SecurityGroupID},
+		Tags:      tags,
+	})
+	if err != nil {
+		return false, fmt.Errorf("error tagging security group [%s]: %w", opts.SecurityGroupID, err)
+	}
+	return true, nil
+}
+
 type UpdateLoggingTypesOpts struct {
 	EKSService          services.EKSServiceInterface
 	Config              *eksv1.EKSClusterConfig
 	UpstreamClusterSpec *eksv1.EKSClusterConfigSpec
 }
 
+// UpdateClusterLoggingTypes updates the logging types of the cluster to match the spec. Logging is left untouched if
+// the logging types are unset in the spec, while an empty list disables all logging types.
 func UpdateClusterLoggingTypes(ctx context.Context, opts *UpdateLoggingTypesOpts) (bool, error) {
 	updated := false
 	if loggingTypesUpdate := getLoggingTypesUpdate(opts.Config.Spec.LoggingTypes, opts.UpstreamClusterSpec.LoggingTypes); loggingTypesUpdate != nil {
@@ -154,8 +235,13 @@ type UpdateClusterPublicAccessSourcesOpts struct {
 	UpstreamClusterSpec *eksv1.EKSClusterConfigSpec
 }
 
+// UpdateClusterPublicAccessSources updates the public access CIDRs of the cluster to match the spec. They are left
+// untouched if the public access sources are unset in the spec, while an empty list opens public access to all sources.
 func UpdateClusterPublicAccessSources(ctx context.Context, opts *UpdateClusterPublicAccessSourcesOpts) (bool, error) {
 	updated := false
+	if opts.Config.Spec.PublicAccessSources == nil {
+		return updated, nil
+	}
 	// check public access CIDRs for update (public access sources)
 
 	filteredSpecPublicAccessSources := filterPublicAccessSources(opts.Config.Spec.PublicAccessSources)
@@ -181,6 +267,585 @@ func UpdateClusterPublicAccessSources(ctx context.Context, opts *UpdateClusterPu
 	return updated, nil
 }
 
+type UpdateClusterSecurityGroupsOpts struct {
+	EKSService          services.EKSServiceInterface
+	Config              *eksv1.EKSClusterConfig
+	UpstreamClusterSpec *eksv1.EKSClusterConfigSpec
+}
+
+// UpdateClusterSecurityGroups updates the additional security groups of the control plane to match the spec. They are
+// left untouched if the security groups are unset in the spec, while an empty list removes all of them, except on
+// clusters in a generated VPC, whose specs list no security groups. The security group EKS created for the cluster isn't
+// affected.
+func UpdateClusterSecurityGroups(ctx context.Context, opts *UpdateClusterSecurityGroupsOpts) (bool, error) {
+	if opts.Config.Spec.SecurityGroups == nil {
+		return false, nil
+	}
+	if len(opts.Config.Spec.SecurityGroups) == 0 && opts.Config.Status.NetworkFieldsSource == "generated" {
+		return false, nil
+	}
+	if utils.CompareStringSliceElements(opts.Config.Spec.SecurityGroups, opts.UpstreamClusterSpec.SecurityGroups) {
+		return false, nil
+	}
+
+	logrus.Infof("Updating security groups to %v for cluster [%s (id: %s)]", opts.Config.Spec.SecurityGroups, opts.Config.Spec.DisplayName, opts.Config.Name)
+	logrus.Debugf("config: %v, upstream: %v", opts.Config.Spec.SecurityGroups, opts.UpstreamClusterSpec.SecurityGroups)
+	_, err := opts.EKSService.UpdateClusterConfig(ctx,
+		&eks.UpdateClusterConfigInput{
+			Name: aws.String(opts.Config.Spec.DisplayName),
+			ResourcesVpcConfig: &ekstypes.VpcConfigRequest{
+				SecurityGroupIds: opts.Config.Spec.SecurityGroups,
+			},
+		},
+	)
+	if err != nil {
+		return false, fmt.Errorf("error updating cluster [%s (id: %s)] security groups: %w", opts.Config.Spec.DisplayName, opts.Config.Name, err)
+	}
+	return true, nil
+}
+
+type UpdateSecretsEncryptionOpts struct {
+	EKSService          services.EKSServiceInterface
+	Config              *eksv1.EKSClusterConfig
+	UpstreamClusterSpec *eksv1.EKSClusterConfigSpec
+}
+
+// UpdateSecretsEncryption enables the encryption of secrets with the KMS key in the spec if it is enabled in the spec
+// but not upstream. Encryption can't be disabled or moved to another key once it is enabled, so those changes are only
+// logged.
+func UpdateSecretsEncryption(ctx context.Context, opts *UpdateSecretsEncryptionOpts) (bool, error) {
+	if opts.Config.Spec.SecretsEncryption == nil {
+		return false, nil
+	}
+
+	enabled, upstreamEnabled := aws.ToBool(opts.Config.Spec.SecretsEncryption), aws.ToBool(opts.UpstreamClusterSpec.SecretsEncryption)
+	if upstreamEnabled {
+		if !enabled {
+			logrus.Warnf("Secrets encryption of cluster [%s (id: %s)] can't be disabled once it is enabled", opts.Config.Spec.DisplayName, opts.Config.Name)
+		} else if opts.Config.Spec.KmsKey != nil && aws.ToString(opts.Config.Spec.KmsKey) != aws.ToString(opts.UpstreamClusterSpec.KmsKey) {
+			logrus.Warnf("Secrets of cluster [%s (id: %s)] are encrypted with KMS key [%s], the key can't be changed",
+				opts.Config.Spec.DisplayName, opts.Config.Name, aws.ToString(opts.UpstreamClusterSpec.KmsKey))
+		}
+		return false, nil
+	}
+	if !enabled {
+		return false, nil
+	}
+
+	logrus.Infof("Enabling secrets encryption with KMS key [%s] for cluster [%s (id: %s)]", aws.ToString(opts.Config.Spec.KmsKey), opts.Config.Spec.DisplayName, opts.Config.Name)
+	_, err := opts.EKSService.AssociateEncryptionConfig(ctx, &eks.AssociateEncryptionConfigInput{
+		ClusterName: aws.String(opts.Config.Spec.DisplayName),
+		EncryptionConfig: []ekstypes.EncryptionConfig{
+			{
+				Provider: &ekstypes.Provider{
+					KeyArn: opts.Config.Spec.KmsKey,
+				},
+				Resources: []string{"secrets"},
+			},
+		},
+	})
+	if err != nil {
+		return false, fmt.Errorf("error enabling secrets encryption for cluster [%s (id: %s)]: %w", opts.Config.Spec.DisplayName, opts.Config.Name, err)
+	}
+	return true, nil
+}
+
+type UpdatePodIdentityAssociationsOpts struct {
+	EKSService services.EKSServiceInterface
+	Config     *eksv1.EKSClusterConfig
+	// UpstreamAssociations are the pod identity associations of the cluster that aren't owned by add-ons
+	UpstreamAssociations []ekstypes.PodIdentityAssociation
+}
+
+// UpdatePodIdentityAssociations creates, updates and deletes the pod identity associations of the cluster to match the
+// spec. Associations are left untouched if they are unset in the spec.
+func UpdatePodIdentityAssociations(ctx context.Context, opts *UpdatePodIdentityAssociationsOpts) (bool, error) {
+	updated := false
+	if opts.Config.Spec.PodIdentityAssociations == nil {
+		return updated, nil
+	}
+
+	upstream := make(map[string]ekstypes.PodIdentityAssociation, len(opts.UpstreamAssociations))
+	for _, association := range opts.UpstreamAssociations {
+		upstream[aws.ToString(association.Namespace)+"/"+aws.ToString(association.ServiceAccount)] = association
+	}
+
+	for _, association := range opts.Config.Spec.PodIdentityAssociations {
+		key := association.Namespace + "/" + association.ServiceAccount
+		upstreamAssociation, ok := upstream[key]
+		delete(upstream, key)
+		switch {
+		case !ok:
+			logrus.Infof("Creating pod identity association for service account [%s] for cluster [%s (id: %s)]", key, opts.Config.Spec.DisplayName, opts.Config.Name)
+			if _, err := opts.EKSService.CreatePodIdentityAssociation(ctx, &eks.CreatePodIdentityAssociationInput{
+				ClusterName:    aws.String(opts.Config.Spec.DisplayName),
+				Namespace:      aws.String(association.Namespace),
+				ServiceAccount: aws.String(association.ServiceAccount),
+				RoleArn:        aws.String(association.RoleARN),
+			}); err != nil {
+				return false, fmt.Errorf("error creating pod identity association for service account [%s] for cluster [%s (id: %s)]: %w",
+					key, opts.Config.Spec.DisplayName, opts.Config.Name, err)
+			}
+			updated = true
+		case aws.ToString(upstreamAssociation.RoleArn) != association.RoleARN:
+			logrus.Infof("Updating pod identity association for service account [%s] for cluster [%s (id: %s)]", key, opts.Config.Spec.DisplayName, opts.Config.Name)
+			if _, err := opts.EKSService.UpdatePodIdentityAssociation(ctx, &eks.UpdatePodIdentityAssociationInput{
+				ClusterName:   aws.String(opts.Config.Spec.DisplayName),
+				AssociationId: upstreamAssociation.AssociationId,
+				RoleArn:       aws.String(association.RoleARN),
+			}); err != nil {
+				return false, fmt.Errorf("error updating pod identity association for service account [%s] for cluster [%s (id: %s)]: %w",
+					key, opts.Config.Spec.DisplayName, opts.Config.Name, err)
+			}
+			updated = true
+		}
+	}
+
+	for key, association := range upstream {
+		logrus.Infof("Deleting pod identity association for service account [%s] for cluster [%s (id: %s)]", key, opts.Config.Spec.DisplayName, opts.Config.Name)
+		if _, err := opts.EKSService.DeletePodIdentityAssociation(ctx, &eks.DeletePodIdentityAssociationInput{
+			ClusterName:   aws.String(opts.Config.Spec.DisplayName),
+			AssociationId: association.AssociationId,
+		}); err != nil {
+			return false, fmt.Errorf("error deleting pod identity association for service account [%s] for cluster [%s (id: %s)]: %w",
+				key, opts.Config.Spec.DisplayName, opts.Config.Name, err)
+		}
+		updated = true
+	}
+
+	return updated, nil
+}
+
+type UpdateEBSAddonPodIdentityOpts struct {
+	EKSService services.EKSServiceInterface
+	CFService  services.CloudFormationServiceInterface
+	Config     *eksv1.EKSClusterConfig
+	// TemplateOverrides replace the built-in CloudFormation templates, keyed by template name
+	TemplateOverrides map[string]string
+}
+
+// UpdateEBSAddonPodIdentity restores the pod identity association of the EBS CSI driver add-on if it was deleted or
+// changed to another role outside of the operator. Add-ons that use the OIDC provider of the cluster are left alone.
+func UpdateEBSAddonPodIdentity(ctx context.Context, opts *UpdateEBSAddonPodIdentityOpts) (bool, error) {
+	output, err := opts.EKSService.DescribeAddon(ctx, &eks.DescribeAddonInput{
+		AddonName:   aws.String(ebsCSIAddonName),
+		ClusterName: aws.String(opts.Config.Spec.DisplayName),
+	})
+	if err != nil {
+		return false, fmt.Errorf("error describing addon [%s] of cluster [%s (id: %s)]: %w", ebsCSIAddonName, opts.Config.Spec.DisplayName, opts.Config.Name, err)
+	}
+	if aws.ToString(output.Addon.ServiceAccountRoleArn) != "" {
+		return false, nil
+	}
+
+	roleArn, err := createEBSCSIDriverPodIdentityRole(ctx, opts.CFService, opts.Config, opts.TemplateOverrides)
+	if err != nil {
+		return false, fmt.Errorf("could not get ebs csi driver pod identity role: %w", err)
+	}
+
+	for _, associationARN := range output.Addon.PodIdentityAssociations {
+		association, err := opts.EKSService.DescribePodIdentityAssociation(ctx, &eks.DescribePodIdentityAssociationInput{
+			ClusterName:   aws.String(opts.Config.Spec.DisplayName),
+			AssociationId: aws.String(associationARN[strings.LastIndex(associationARN, "/")+1:]),
+		})
+		if err != nil {
+			return false, fmt.Errorf("error describing pod identity association [%s] of cluster [%s (id: %s)]: %w", associationARN, opts.Config.Spec.DisplayName, opts.Config.Name, err)
+		}
+		if aws.ToString(association.Association.ServiceAccount) == ebsCSIServiceAccount && aws.ToString(association.Association.RoleArn) == roleArn {
+			return false, nil
+		}
+	}
+
+	logrus.Infof("Restoring pod identity association of addon [%s] for cluster [%s (id: %s)]", ebsCSIAddonName, opts.Config.Spec.DisplayName, opts.Config.Name)
+	if _, err := opts.EKSService.UpdateAddon(ctx, &eks.UpdateAddonInput{
+		AddonName:   aws.String(ebsCSIAddonName),
+		ClusterName: aws.String(opts.Config.Spec.DisplayName),
+		PodIdentityAssociations: []ekstypes.AddonPodIdentityAssociations{
+			{
+				RoleArn:        aws.String(roleArn),
+				ServiceAccount: aws.String(ebsCSIServiceAccount),
+			},
+		},
+	}); err != nil {
+		return false, fmt.Errorf("error updating addon [%s] of cluster [%s (id: %s)]: %w", ebsCSIAddonName, opts.Config.Spec.DisplayName, opts.Config.Name, err)
+	}
+	return true, nil
+}
+
+type UpdateRolePoliciesOpts struct {
+	IAMService services.IAMServiceInterface
+	RoleARN    string
+	// policies that must be attached to the role
+	PolicyARNs []string
+	// policies the operator attached to the role before, the ones that aren't in PolicyARNs anymore are detached
+	AttachedPolicyARNs []string
+}
+
+// UpdateRolePolicies attaches the policies to the role that aren't attached yet and detaches the previously
+// attached ones that were removed. Policies attached by other means are left alone. It returns whether the role was
+// changed.
+func UpdateRolePolicies(ctx context.Context, opts *UpdateRolePoliciesOpts) (bool, error) {
+	roleName := opts.RoleARN[strings.LastIndex(opts.RoleARN, "/")+1:]
+	if roleName == "" {
+		return false, fmt.Errorf("invalid role ARN [%s]", opts.RoleARN)
+	}
+
+	attached := make(map[string]struct{})
+	input := &iam.ListAttachedRolePoliciesInput{RoleName: aws.String(roleName)}
+	for {
+		output, err := opts.IAMService.ListAttachedRolePolicies(ctx, input)
+		if err != nil {
+			return false, fmt.Errorf("error listing policies of role [%s]: %w", roleName, err)
+		}
+		for _, policy := range output.AttachedPolicies {
+			attached[aws.ToString(policy.PolicyArn)] = struct{}{}
+		}
+		if !output.IsTruncated {
+			break
+		}
+		input.Marker = output.Marker
+	}
+
+	var updated bool
+	for _, policyARN := range opts.PolicyARNs {
+		if _, ok := attached[policyARN]; ok {
+			continue
+		}
+		logrus.Infof("Attaching policy [%s] to role [%s]", policyARN, roleName)
+		if _, err := opts.IAMService.AttachRolePolicy(ctx, &iam.AttachRolePolicyInput{
+			RoleName:  aws.String(roleName),
+			PolicyArn: aws.String(policyARN),
+		}); err != nil {
+			return updated, fmt.Errorf("error attaching policy [%s] to role [%s]: %w", policyARN, roleName, err)
+		}
+		updated = true
+	}
+
+	for _, policyARN := range opts.AttachedPolicyARNs {
+		if _, ok := attached[policyARN]; !ok || slices.Contains(opts.PolicyARNs, policyARN) {
+			continue
+		}
+		logrus.Infof("Detaching policy [%s] from role [%s]", policyARN, roleName)
+		if _, err := opts.IAMService.DetachRolePolicy(ctx, &iam.DetachRolePolicyInput{
+			RoleName:  aws.String(roleName),
+			PolicyArn: aws.String(policyARN),
+		}); err != nil {
+			var nse *iamtypes.NoSuchEntityException
+			if errors.As(err, &nse) {
+				continue
+			}
+			return updated, fmt.Errorf("error detaching policy [%s] from role [%s]: %w", policyARN, roleName, err)
+		}
+		updated = true
+	}
+
+	return updated, nil
+}
+
+type UpdateAuthenticationModeOpts struct {
+	EKSService services.EKSServiceInterface
+	Config     *eksv1.EKSClusterConfig
+}
+
+// UpdateAuthenticationMode changes the authentication mode of the cluster from CONFIG_MAP to API_AND_CONFIG_MAP, so
+// that access entries can be created. EKS can't change it back, clusters that already support access entries are left
+// untouched. It returns whether the cluster is being updated.
+func UpdateAuthenticationMode(ctx context.Context, opts *UpdateAuthenticationModeOpts) (bool, error) {
+	output, err := opts.EKSService.DescribeCluster(ctx, &eks.DescribeClusterInput{
+		Name: aws.String(opts.Config.Spec.DisplayName),
+	})
+	if err != nil {
+		return false, fmt.Errorf("error describing cluster [%s (id: %s)]: %w", opts.Config.Spec.DisplayName, opts.Config.Name, err)
+	}
+	if output.Cluster == nil || output.Cluster.AccessConfig == nil ||
+		output.Cluster.AccessConfig.AuthenticationMode != ekstypes.AuthenticationModeConfigMap {
+		return false, nil
+	}
+
+	logrus.Infof("Updating authentication mode to %s for cluster [%s (id: %s)]", ekstypes.AuthenticationModeApiAndConfigMap, opts.Config.Spec.DisplayName, opts.Config.Name)
+	_, err = opts.EKSService.UpdateClusterConfig(ctx, &eks.UpdateClusterConfigInput{
+		Name: aws.String(opts.Config.Spec.DisplayName),
+		AccessConfig: &ekstypes.UpdateAccessConfigRequest{
+			AuthenticationMode: ekstypes.AuthenticationModeApiAndConfigMap,
+		},
+	})
+	if err != nil {
+		return false, fmt.Errorf("error updating authentication mode of cluster [%s (id: %s)]: %w", opts.Config.Spec.DisplayName, opts.Config.Name, err)
+	}
+	return true, nil
+}
+
+type UpdateClusterAdminPrincipalsOpts struct {
+	EKSService services.EKSServiceInterface
+	Config     *eksv1.EKSClusterConfig
+	// principals the operator granted cluster admin access to before, the access entries of the ones that aren't in the
+	// spec anymore are deleted
+	GrantedPrincipals []string
+}
+
+// UpdateClusterAdminPrincipals creates the access entries of the cluster admin principals of the spec that don't exist
+// yet, associates the cluster admin policy with the ones it isn't associated with, and deletes the access entries of
+// the previously granted principals that were removed if the operator created them. Other access entries, e.g. the ones
+// of the cluster creator and the node roles, are left alone. It returns whether anything was changed.
+func UpdateClusterAdminPrincipals(ctx context.Context, opts *UpdateClusterAdminPrincipalsOpts) (bool, error) {
+	clusterName := opts.Config.Spec.DisplayName
+	entries := make(map[string]struct{})
+	input := &eks.ListAccessEntriesInput{ClusterName: aws.String(clusterName)}
+	for {
+		output, err := opts.EKSService.ListAccessEntries(ctx, input)
+		if err != nil {
+			return false, fmt.Errorf("error listing access entries of cluster [%s (id: %s)]: %w", clusterName, opts.Config.Name, err)
+		}
+		for _, principal := range output.AccessEntries {
+			entries[principal] = struct{}{}
+		}
+		if output.NextToken == nil {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+
+	var updated bool
+	for _, principal := range opts.Config.Spec.ClusterAdminPrincipals {
+		associated := false
+		if _, ok := entries[principal]; ok {
+			var err error
+			associated, err = isClusterAdminPolicyAssociated(ctx, opts.EKSService, clusterName, principal)
+			if err != nil {
+				return updated, fmt.Errorf("error listing access policies of principal [%s] of cluster [%s (id: %s)]: %w", principal, clusterName, opts.Config.Name, err)
+			}
+		} else {
+			logrus.Infof("Creating access entry for principal [%s] for cluster [%s (id: %s)]", principal, clusterName, opts.Config.Name)
+			if _, err := opts.EKSService.CreateAccessEntry(ctx, &eks.CreateAccessEntryInput{
+				ClusterName:  aws.String(clusterName),
+				PrincipalArn: aws.String(principal),
+				Tags:         OwnershipTags(opts.Config),
+			}); err != nil {
+				return updated, fmt.Errorf("error creating access entry for principal [%s] for cluster [%s (id: %s)]: %w", principal, clusterName, opts.Config.Name, err)
+			}
+			updated = true
+		}
+		if associated {
+			continue
+		}
+
+		logrus.Infof("Associating cluster admin policy with principal [%s] for cluster [%s (id: %s)]", principal, clusterName, opts.Config.Name)
+		if _, err := opts.EKSService.AssociateAccessPolicy(ctx, &eks.AssociateAccessPolicyInput{
+			ClusterName:  aws.String(clusterName),
+			PrincipalArn: aws.String(principal),
+			PolicyArn:    aws.String(clusterAdminPolicyARN(principal)),
+			AccessScope:  &ekstypes.AccessScope{Type: ekstypes.AccessScopeTypeCluster},
+		}); err != nil {
+			return updated, fmt.Errorf("error associating cluster admin policy with principal [%s] for cluster [%s (id: %s)]: %w", principal, clusterName, opts.Config.Name, err)
+		}
+		updated = true
+	}
+
+	for _, principal := range opts.GrantedPrincipals {
+		if _, ok := entries[principal]; !ok || slices.Contains(opts.Config.Spec.ClusterAdminPrincipals, principal) {
+			continue
+		}
+		output, err := opts.EKSService.DescribeAccessEntry(ctx, &eks.DescribeAccessEntryInput{
+			ClusterName:  aws.String(clusterName),
+			PrincipalArn: aws.String(principal),
+		})
+		if err != nil {
+			return updated, fmt.Errorf("error describing access entry for principal [%s] for cluster [%s (id: %s)]: %w", principal, clusterName, opts.Config.Name, err)
+		}
+		if output.AccessEntry == nil || output.AccessEntry.Tags[ManagedTagKey] != "true" {
+			// the access entry existed before the principal was granted cluster admin access
+			logrus.Infof("Keeping access entry for principal [%s] for cluster [%s (id: %s)], it wasn't created by the operator", principal, clusterName, opts.Config.Name)
+			continue
+		}
+		logrus.Infof("Deleting access entry for principal [%s] for cluster [%s (id: %s)]", principal, clusterName, opts.Config.Name)
+		if _, err := opts.EKSService.DeleteAccessEntry(ctx, &eks.DeleteAccessEntryInput{
+			ClusterName:  aws.String(clusterName),
+			PrincipalArn: aws.String(principal),
+		}); err != nil {
+			var nfe *ekstypes.ResourceNotFoundException
+			if errors.As(err, &nfe) {
+				continue
+			}
+			return updated, fmt.Errorf("error deleting access entry for principal [%s] for cluster [%s (id: %s)]: %w", principal, clusterName, opts.Config.Name, err)
+		}
+		updated = true
+	}
+
+	return updated, nil
+}
+
+// isClusterAdminPolicyAssociated returns whether the cluster admin policy is associated with the access entry of the
+// principal for the whole cluster.
+func isClusterAdminPolicyAssociated(ctx context.Context, eksService services.EKSServiceInterface, clusterName, principal string) (bool, error) {
+	input := &eks.ListAssociatedAccessPoliciesInput{
+		ClusterName:  aws.String(clusterName),
+		PrincipalArn: aws.String(principal),
+	}
+	for {
+		output, err := eksService.ListAssociatedAccessPolicies(ctx, input)
+		if err != nil {
+			return false, err
+		}
+		for _, policy := range output.AssociatedAccessPolicies {
+			if aws.ToString(policy.PolicyArn) == clusterAdminPolicyARN(principal) &&
+				policy.AccessScope != nil && policy.AccessScope.Type == ekstypes.AccessScopeTypeCluster {
+				return true, nil
+			}
+		}
+		if output.NextToken == nil {
+			return false, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+// clusterAdminPolicyARN returns the ARN of the AmazonEKSClusterAdminPolicy access policy in the partition of the
+// principal.
+func clusterAdminPolicyARN(principal string) string {
+	partition := "aws"
+	if parsed, err := arn.Parse(principal); err == nil {
+		partition = parsed.Partition
+	}
+	return fmt.Sprintf("arn:%s:eks::aws:cluster-access-policy/AmazonEKSClusterAdminPolicy", partition)
+}
+
+type UpdateIdentityProviderConfigsOpts struct {
+	EKSService      services.EKSServiceInterface
+	Config          *eksv1.EKSClusterConfig
+	UpstreamConfigs []ekstypes.OidcIdentityProviderConfig
+}
+
+// UpdateIdentityProviderConfigs associates and disassociates the OIDC identity provider configs of the cluster to match
+// the spec. Identity provider configs can't be modified and EKS only allows one per cluster, so configs that changed or
+// were removed are disassociated first, and configs are only associated once nothing is being disassociated. Nothing is
+// done while configs are being associated or disassociated upstream. Configs are left untouched if they are unset in
+// the spec.
+func UpdateIdentityProviderConfigs(ctx context.Context, opts *UpdateIdentityProviderConfigsOpts) (bool, error) {
+	updated := false
+	if opts.Config.Spec.IdentityProviderConfigs == nil || IdentityProviderConfigsPending(opts.UpstreamConfigs) {
+		return updated, nil
+	}
+
+	desired := make(map[string]eksv1.IdentityProviderConfig, len(opts.Config.Spec.IdentityProviderConfigs))
+	for _, idpConfig := range opts.Config.Spec.IdentityProviderConfigs {
+		desired[idpConfig.Name] = idpConfig
+	}
+
+	upstream := make(map[string]struct{}, len(opts.UpstreamConfigs))
+	for _, upstreamConfig := range opts.UpstreamConfigs {
+		name := aws.ToString(upstreamConfig.IdentityProviderConfigName)
+		if idpConfig, ok := desired[name]; ok && oidcIdentityProviderConfigMatches(idpConfig, upstreamConfig) {
+			upstream[name] = struct{}{}
+			continue
+		}
+		if err := disassociateIdentityProviderConfig(ctx, opts, name); err != nil {
+			return false, err
+		}
+		updated = true
+	}
+	if updated {
+		return updated, nil
+	}
+
+	for _, idpConfig := range opts.Config.Spec.IdentityProviderConfigs {
+		if _, ok := upstream[idpConfig.Name]; ok {
+			continue
+		}
+		logrus.Infof("Associating identity provider config [%s] with cluster [%s (id: %s)]", idpConfig.Name, opts.Config.Spec.DisplayName, opts.Config.Name)
+		if _, err := opts.EKSService.AssociateIdentityProviderConfig(ctx, &eks.AssociateIdentityProviderConfigInput{
+			ClusterName: aws.String(opts.Config.Spec.DisplayName),
+			Oidc:        getOIDCIdentityProviderConfigRequest(idpConfig),
+		}); err != nil {
+			return false, fmt.Errorf("error associating identity provider config [%s] with cluster [%s (id: %s)]: %w",
+				idpConfig.Name, opts.Config.Spec.DisplayName, opts.Config.Name, err)
+		}
+		updated = true
+	}
+
+	return updated, nil
+}
+
+// IdentityProviderConfigsPending returns whether any of the given identity provider configs is being associated or
+// disassociated.
+func IdentityProviderConfigsPending(configs []ekstypes.OidcIdentityProviderConfig) bool {
+	for _, idpConfig := range configs {
+		if idpConfig.Status != ekstypes.ConfigStatusActive {
+			return true
+		}
+	}
+
+	return false
+}
+
+func disassociateIdentityProviderConfig(ctx context.Context, opts *UpdateIdentityProviderConfigsOpts, name string) error {
+	logrus.Infof("Disassociating identity provider config [%s] from cluster [%s (id: %s)]", name, opts.Config.Spec.DisplayName, opts.Config.Name)
+	if _, err := opts.EKSService.DisassociateIdentityProviderConfig(ctx, &eks.DisassociateIdentityProviderConfigInput{
+		ClusterName: aws.String(opts.Config.Spec.DisplayName),
+		IdentityProviderConfig: &ekstypes.IdentityProviderConfig{
+			Name: aws.String(name),
+			Type: aws.String(oidcIdentityProviderType),
+		},
+	}); err != nil {
+		return fmt.Errorf("error disassociating identity provider config [%s] from cluster [%s (id: %s)]: %w",
+			name, opts.Config.Spec.DisplayName, opts.Config.Name, err)
+	}
+
+	return nil
+}
+
+func getOIDCIdentityProviderConfigRequest(idpConfig eksv1.IdentityProviderConfig) *ekstypes.OidcIdentityProviderConfigRequest {
+	request := &ekstypes.OidcIdentityProviderConfigRequest{
+		IdentityProviderConfigName: aws.String(idpConfig.Name),
+		IssuerUrl:                  aws.String(idpConfig.IssuerURL),
+		ClientId:                   aws.String(idpConfig.ClientID),
+		RequiredClaims:             idpConfig.RequiredClaims,
+	}
+	if idpConfig.UsernameClaim != "" {
+		request.UsernameClaim = aws.String(idpConfig.UsernameClaim)
+	}
+	if idpConfig.UsernamePrefix != "" {
+		request.UsernamePrefix = aws.String(idpConfig.UsernamePrefix)
+	}
+	if idpConfig.GroupsClaim != "" {
+		request.GroupsClaim = aws.String(idpConfig.GroupsClaim)
+	}
+	if idpConfig.GroupsPrefix != "" {
+		request.GroupsPrefix = aws.String(idpConfig.GroupsPrefix)
+	}
+
+	return request
+}
+
+// oidcIdentityProviderConfigMatches compares the identity provider config in the spec with the upstream one. Optional
+// fields that are empty in the spec are not compared, since EKS fills in defaults for some of them.
+func oidcIdentityProviderConfigMatches(idpConfig eksv1.IdentityProviderConfig, upstreamConfig ekstypes.OidcIdentityProviderConfig) bool {
+	optionalMatches := func(value string, upstreamValue *string) bool {
+		return value == "" || value == aws.ToString(upstreamValue)
+	}
+	if idpConfig.IssuerURL != aws.ToString(upstreamConfig.IssuerUrl) || idpConfig.ClientID != aws.ToString(upstreamConfig.ClientId) {
+		return false
+	}
+	if !optionalMatches(idpConfig.UsernameClaim, upstreamConfig.UsernameClaim) ||
+		!optionalMatches(idpConfig.UsernamePrefix, upstreamConfig.UsernamePrefix) ||
+		!optionalMatches(idpConfig.GroupsClaim, upstreamConfig.GroupsClaim) ||
+		!optionalMatches(idpConfig.GroupsPrefix, upstreamConfig.GroupsPrefix) {
+		return false
+	}
+	if idpConfig.RequiredClaims == nil {
+		return true
+	}
+	if len(idpConfig.RequiredClaims) != len(upstreamConfig.RequiredClaims) {
+		return false
+	}
+	for key, value := range idpConfig.RequiredClaims {
+		if upstreamValue, ok := upstreamConfig.RequiredClaims[key]; !ok || upstreamValue != value {
+			return false
+		}
+	}
+
+	return true
+}
+
 type UpdateNodegroupVersionOpts struct {
 	EKSService     services.EKSServiceInterface
 	EC2Service     services.EC2ServiceInterface
@@ -205,18 +870,21 @@ func UpdateNodegroupVersion(ctx context.Context, opts *UpdateNodegroupVersionOpt
 }
 
 func getLoggingTypesUpdate(loggingTypes []string, upstreamLoggingTypes []string) *ekstypes.Logging {
+	if loggingTypes == nil {
+		// unset logging types leave the upstream logging as it is, only an empty list disables it
+		return nil
+	}
+
 	loggingUpdate := &ekstypes.Logging{}
 
-	if len(loggingTypes) >= 0 {
-		loggingTypesToDisable := getLoggingTypesToDisable(loggingTypes, upstreamLoggingTypes)
-		if loggingTypesToDisable.Enabled != nil {
-			loggingUpdate.ClusterLogging = append(loggingUpdate.ClusterLogging, loggingTypesToDisable)
-		}
+	loggingTypesToDisable := getLoggingTypesToDisable(loggingTypes, upstreamLoggingTypes)
+	if loggingTypesToDisable.Enabled != nil {
+		loggingUpdate.ClusterLogging = append(loggingUpdate.ClusterLogging, loggingTypesToDisable)
+	}
 
-		loggingTypesToEnable := getLoggingTypesToEnable(loggingTypes, upstreamLoggingTypes)
-		if loggingTypesToEnable.Enabled != nil {
-			loggingUpdate.ClusterLogging = append(loggingUpdate.ClusterLogging, loggingTypesToEnable)
-		}
+	loggingTypesToEnable := getLoggingTypesToEnable(loggingTypes, upstreamLoggingTypes)
+	if loggingTypesToEnable.Enabled != nil {
+		loggingUpdate.ClusterLogging = append(loggingUpdate.ClusterLogging, loggingTypesToEnable)
 	}
 
 	if len(loggingUpdate.ClusterLogging) > 0 {
@@ -283,3 +951,169 @@ func filterPublicAccessSources(sources []string) []string {
 	}
 	return sources
 }
+
+const (
+	// DefaultAuditFilterPattern matches the audit events of requests that were forbidden
+	DefaultAuditFilterPattern = "{ $.responseStatus.code = 403 }"
+
+	auditMetricNamespace    = "EKS/AuditEvents"
+	defaultAuditAlarmPeriod = 300
+)
+
+// AuditResourceName returns the name of the metric filter, metric and alarm on the audit events of the cluster.
+func AuditResourceName(clusterName string) string {
+	return clusterName + "-audit-events"
+}
+
+type UpdateAuditLoggingOpts struct {
+	CloudWatchLogsService services.CloudWatchLogsServiceInterface
+	CloudWatchService     services.CloudWatchServiceInterface
+	Config                *eksv1.EKSClusterConfig
+}
+
+// UpdateAuditLogging applies the audit logging of the spec to the log group of the cluster, removing the retention
+// policy and alarm that were last applied according to the status if they were removed from the spec. The log group
+// has to exist, i.e. a logging type has to have been enabled.
+func UpdateAuditLogging(ctx context.Context, opts *UpdateAuditLoggingOpts) error {
+	name := opts.Config.Spec.DisplayName
+	logGroupName := aws.String(ClusterLogGroupName(name))
+	auditLogging := opts.Config.Spec.AuditLogging
+	if auditLogging == nil {
+		auditLogging = &eksv1.AuditLogging{}
+	}
+	applied := opts.Config.Status.AuditLogging
+	if applied == nil {
+		applied = &eksv1.AuditLogging{}
+	}
+
+	if auditLogging.RetentionInDays != 0 {
+		logrus.Infof("Setting retention of log group [%s] to %d days", aws.ToString(logGroupName), auditLogging.RetentionInDays)
+		if _, err := opts.CloudWatchLogsService.PutRetentionPolicy(ctx, &services.PutRetentionPolicyInput{
+			LogGroupName:    logGroupName,
+			RetentionInDays: aws.Int32(auditLogging.RetentionInDays),
+		}); err != nil {
+			return fmt.Errorf("error setting retention of log group [%s]: %w", aws.ToString(logGroupName), err)
+		}
+	} else if applied.RetentionInDays != 0 {
+		logrus.Infof("Removing retention of log group [%s]", aws.ToString(logGroupName))
+		if _, err := opts.CloudWatchLogsService.DeleteRetentionPolicy(ctx, &services.DeleteRetentionPolicyInput{
+			LogGroupName: logGroupName,
+		}); err != nil && !isCloudWatchLogsNotFound(err) {
+			return fmt.Errorf("error removing retention of log group [%s]: %w", aws.ToString(logGroupName), err)
+		}
+	}
+
+	if opts.Config.Spec.AuditLogging == nil {
+		return DeleteAuditLogging(ctx, &DeleteAuditLoggingOpts{
+			CloudWatchLogsService: opts.CloudWatchLogsService,
+			CloudWatchService:     opts.CloudWatchService,
+			ClusterName:           name,
+		})
+	}
+
+	filterPattern := auditLogging.FilterPattern
+	if filterPattern == "" {
+		filterPattern = DefaultAuditFilterPattern
+	}
+	logrus.Infof("Putting audit metric filter [%s] on log group [%s]", AuditResourceName(name), aws.ToString(logGroupName))
+	if _, err := opts.CloudWatchLogsService.PutMetricFilter(ctx, &services.PutMetricFilterInput{
+		LogGroupName:  logGroupName,
+		FilterName:    aws.String(AuditResourceName(name)),
+		FilterPattern: aws.String(filterPattern),
+		MetricTransformations: []services.MetricTransformation{{
+			MetricName:      aws.String(AuditResourceName(name)),
+			MetricNamespace: aws.String(auditMetricNamespace),
+			MetricValue:     aws.String("1"),
+			DefaultValue:    aws.Float64(0),
+		}},
+	}); err != nil {
+		return fmt.Errorf("error putting audit metric filter of log group [%s]: %w", aws.ToString(logGroupName), err)
+	}
+
+	if auditLogging.AlarmThreshold == 0 {
+		if applied.AlarmThreshold != 0 {
+			return deleteAuditAlarm(ctx, opts.CloudWatchService, name)
+		}
+		return nil
+	}
+	period := auditLogging.AlarmPeriodSeconds
+	if period == 0 {
+		period = defaultAuditAlarmPeriod
+	}
+	tagKeys := make([]string, 0, len(opts.Config.Spec.Tags))
+	for key := range opts.Config.Spec.Tags {
+		tagKeys = append(tagKeys, key)
+	}
+	slices.Sort(tagKeys)
+	tags := make([]services.Tag, 0, len(tagKeys))
+	for _, key := range tagKeys {
+		tags = append(tags, services.Tag{Key: aws.String(key), Value: aws.String(opts.Config.Spec.Tags[key])})
+	}
+	logrus.Infof("Putting audit alarm [%s] of cluster [%s (id: %s)]", AuditResourceName(name), name, opts.Config.Name)
+	if _, err := opts.CloudWatchService.PutMetricAlarm(ctx, &services.PutMetricAlarmInput{
+		AlarmName:          aws.String(AuditResourceName(name)),
+		AlarmDescription:   aws.String(fmt.Sprintf("Audit events of EKS cluster %s matching %s", name, filterPattern)),
+		Namespace:          aws.String(auditMetricNamespace),
+		MetricName:         aws.String(AuditResourceName(name)),
+		Statistic:          "Sum",
+		Period:             aws.Int32(period),
+		EvaluationPeriods:  aws.Int32(1),
+		Threshold:          aws.Float64(float64(auditLogging.AlarmThreshold)),
+		ComparisonOperator: "GreaterThanOrEqualToThreshold",
+		TreatMissingData:   aws.String("notBreaching"),
+		AlarmActions:       auditLogging.AlarmActions,
+		Tags:               tags,
+	}); err != nil {
+		return fmt.Errorf("error putting audit alarm [%s]: %w", AuditResourceName(name), err)
+	}
+	return nil
+}
+
+type UpdateRoleStackPoliciesOpts struct {
+	CloudFormationService services.CloudFormationServiceInterface
+	Config                *eksv1.EKSClusterConfig
+	// name or ID of the stack of the role
+	StackName string
+	// TemplateServiceRole or TemplateNodeInstanceRole, the template the stack was created from
+	Template          string
+	TemplateOverrides map[string]string
+	// policies that must be attached to the role in addition to the ones of the template
+	PolicyARNs []string
+}
+
+// UpdateRoleStackPolicies updates the additional policies of a role the operator created through CloudFormation by
+// updating the AdditionalPolicyArns parameter of its stack, so that the policies stay owned by the stack instead of
+// drifting from it. The template is rendered again so that stacks created before the parameter existed get it. It
+// returns whether the stack was updated, false if it already had the policies.
+func UpdateRoleStackPolicies(ctx context.Context, opts *UpdateRoleStackPoliciesOpts) (bool, error) {
+	builtIn := templates.ServiceRoleTemplate
+	if opts.Template == TemplateNodeInstanceRole {
+		builtIn = fmt.Sprintf(templates.NodeInstanceRoleTemplate, getEC2ServiceEndpoint(opts.Config.Spec.Region))
+	}
+	templateBody, err := RenderTemplate(opts.TemplateOverrides, opts.Template, builtIn, NewTemplateParameters(opts.Config))
+	if err != nil {
+		return false, err
+	}
+
+	logrus.Infof("Updating policies of stack [%s] of cluster [%s (id: %s)]", opts.StackName, opts.Config.Spec.DisplayName, opts.Config.Name)
+	_, err = opts.CloudFormationService.UpdateStack(ctx, &cloudformation.UpdateStackInput{
+		StackName:    aws.String(opts.StackName),
+		TemplateBody: aws.String(templateBody),
+		Capabilities: []cftypes.Capability{cftypes.CapabilityCapabilityIam},
+		Parameters:   RoleStackParameters(opts.Config, opts.PolicyARNs),
+	})
+	if noStackUpdates(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error updating stack [%s]: %w", opts.StackName, err)
+	}
+	return true, nil
+}
+
+// noStackUpdates returns whether the error of a stack update means that the stack already matches the update.
+func noStackUpdates(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == "ValidationError" &&
+		strings.Contains(apiErr.ErrorMessage(), "No updates are to be performed")
+}
@@ -1,16 +1,27 @@
 package eks
 
 import (
+	"context"
 	"errors"
+	"fmt"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	cftypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/aws/aws-sdk-go-v2/service/eks"
 	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/smithy-go"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services"
 	"github.com/rancher/eks-operator/pkg/eks/services/mock_services"
+	"github.com/rancher/eks-operator/templates"
 	"github.com/rancher/eks-operator/utils"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -156,6 +167,13 @@ var _ = Describe("UpdateResourceTags", func() {
 		Expect(err).NotTo(HaveOccurred())
 	})
 
+	It("should not update cluster tags if tags are unset", func() {
+		updateResourceTagsOpts.Tags = nil
+		updated, err := UpdateResourceTags(ctx, updateResourceTagsOpts)
+		Expect(updated).To(BeFalse())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
 	It("should not update cluster tags if tags didn't change", func() {
 		updateResourceTagsOpts.UpstreamTags = map[string]string{
 			"test1": "test1",
@@ -186,6 +204,83 @@ var _ = Describe("UpdateResourceTags", func() {
 	})
 })
 
+var _ = Describe("UpdateSecurityGroupTags", func() {
+	var (
+		mockController *gomock.Controller
+		ec2ServiceMock *mock_services.MockEC2ServiceInterface
+		opts           *UpdateSecurityGroupTagsOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		ec2ServiceMock = mock_services.NewMockEC2ServiceInterface(mockController)
+		opts = &UpdateSecurityGroupTagsOpts{
+			EC2Service:      ec2ServiceMock,
+			SecurityGroupID: "sg-123",
+			Tags: map[string]string{
+				"test1":                  "test1",
+				"test2":                  "changed",
+				"karpenter.sh/discovery": "test-cluster",
+				"aws:reserved":           "skipped",
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should add missing and changed tags", func() {
+		ec2ServiceMock.EXPECT().DescribeTags(ctx, &ec2.DescribeTagsInput{
+			Filters: []ec2types.Filter{{Name: aws.String("resource-id"), Values: []string{"sg-123"}}},
+		}).Return(&ec2.DescribeTagsOutput{
+			Tags: []ec2types.TagDescription{
+				{Key: aws.String("test1"), Value: aws.String("test1")},
+				{Key: aws.String("test2"), Value: aws.String("test2")},
+				{Key: aws.String("aws:eks:cluster-name"), Value: aws.String("test-cluster")},
+			},
+		}, nil)
+		ec2ServiceMock.EXPECT().CreateTags(ctx, &ec2.CreateTagsInput{
+			Resources: []string{"sg-123"},
+			Tags: []ec2types.Tag{
+				{Key: aws.String("karpenter.sh/discovery"), Value: aws.String("test-cluster")},
+				{Key: aws.String("test2"), Value: aws.String("changed")},
+			},
+		}).Return(nil, nil)
+		updated, err := UpdateSecurityGroupTags(ctx, opts)
+		Expect(updated).To(BeTrue())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should not update tags that are already set", func() {
+		ec2ServiceMock.EXPECT().DescribeTags(ctx, gomock.Any()).Return(&ec2.DescribeTagsOutput{
+			Tags: []ec2types.TagDescription{
+				{Key: aws.String("test1"), Value: aws.String("test1")},
+				{Key: aws.String("test2"), Value: aws.String("changed")},
+				{Key: aws.String("karpenter.sh/discovery"), Value: aws.String("test-cluster")},
+			},
+		}, nil)
+		updated, err := UpdateSecurityGroupTags(ctx, opts)
+		Expect(updated).To(BeFalse())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should not describe tags without a security group", func() {
+		opts.SecurityGroupID = ""
+		updated, err := UpdateSecurityGroupTags(ctx, opts)
+		Expect(updated).To(BeFalse())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should return error if tagging the security group failed", func() {
+		ec2ServiceMock.EXPECT().DescribeTags(ctx, gomock.Any()).Return(&ec2.DescribeTagsOutput{}, nil)
+		ec2ServiceMock.EXPECT().CreateTags(ctx, gomock.Any()).Return(nil, errors.New("error tagging security group"))
+		updated, err := UpdateSecurityGroupTags(ctx, opts)
+		Expect(updated).To(BeFalse())
+		Expect(err).To(HaveOccurred())
+	})
+})
+
 var _ = Describe("UpdateLoggingTypes", func() {
 	var (
 		mockController         *gomock.Controller
@@ -294,6 +389,13 @@ var _ = Describe("UpdateLoggingTypes", func() {
 		Expect(err).NotTo(HaveOccurred())
 	})
 
+	It("shouldn't update cluster logging types when they are unset", func() {
+		updateLoggingTypesOpts.Config.Spec.LoggingTypes = nil
+		updated, err := UpdateClusterLoggingTypes(ctx, updateLoggingTypesOpts)
+		Expect(updated).To(BeFalse())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
 	It("shouldn't update cluster logging types when no changes", func() {
 		updateLoggingTypesOpts = &UpdateLoggingTypesOpts{
 			EKSService: eksServiceMock,
@@ -427,6 +529,13 @@ var _ = Describe("UpdateClusterPublicAccessSources", func() {
 		Expect(err).NotTo(HaveOccurred())
 	})
 
+	It("should not update cluster public access sources if public access sources are unset", func() {
+		updateClusterPublicAccessSourcesOpts.Config.Spec.PublicAccessSources = nil
+		updated, err := UpdateClusterPublicAccessSources(ctx, updateClusterPublicAccessSourcesOpts)
+		Expect(updated).To(BeFalse())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
 	It("should return error if update cluster public access sources failed", func() {
 		eksServiceMock.EXPECT().UpdateClusterConfig(ctx, gomock.Any()).Return(nil, errors.New("error updating cluster config"))
 		updated, err := UpdateClusterPublicAccessSources(ctx, updateClusterPublicAccessSourcesOpts)
@@ -477,3 +586,889 @@ var _ = Describe("UpdateNodegroupVersion", func() {
 		Expect(UpdateNodegroupVersion(ctx, updateNodegroupVersionOpts)).To(HaveOccurred())
 	})
 })
+
+var _ = Describe("UpdateClusterSecurityGroups", func() {
+	var (
+		mockController *gomock.Controller
+		eksServiceMock *mock_services.MockEKSServiceInterface
+		opts           *UpdateClusterSecurityGroupsOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		opts = &UpdateClusterSecurityGroupsOpts{
+			EKSService: eksServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName:    "test",
+					SecurityGroups: []string{"sg-1", "sg-2"},
+				},
+			},
+			UpstreamClusterSpec: &eksv1.EKSClusterConfigSpec{
+				SecurityGroups: []string{"sg-1"},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should update security groups", func() {
+		eksServiceMock.EXPECT().UpdateClusterConfig(ctx, &eks.UpdateClusterConfigInput{
+			Name: aws.String("test"),
+			ResourcesVpcConfig: &ekstypes.VpcConfigRequest{
+				SecurityGroupIds: []string{"sg-1", "sg-2"},
+			},
+		}).Return(&eks.UpdateClusterConfigOutput{}, nil)
+		updated, err := UpdateClusterSecurityGroups(ctx, opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeTrue())
+	})
+
+	It("should remove all security groups", func() {
+		opts.Config.Spec.SecurityGroups = []string{}
+		eksServiceMock.EXPECT().UpdateClusterConfig(ctx, &eks.UpdateClusterConfigInput{
+			Name: aws.String("test"),
+			ResourcesVpcConfig: &ekstypes.VpcConfigRequest{
+				SecurityGroupIds: []string{},
+			},
+		}).Return(&eks.UpdateClusterConfigOutput{}, nil)
+		updated, err := UpdateClusterSecurityGroups(ctx, opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeTrue())
+	})
+
+	It("should not update security groups that match in any order", func() {
+		opts.UpstreamClusterSpec.SecurityGroups = []string{"sg-2", "sg-1"}
+		updated, err := UpdateClusterSecurityGroups(ctx, opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should not remove security groups of clusters in a generated VPC", func() {
+		opts.Config.Spec.SecurityGroups = []string{}
+		opts.Config.Status.NetworkFieldsSource = "generated"
+		updated, err := UpdateClusterSecurityGroups(ctx, opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should not update security groups that are unset", func() {
+		opts.Config.Spec.SecurityGroups = nil
+		updated, err := UpdateClusterSecurityGroups(ctx, opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should return error if updating security groups failed", func() {
+		eksServiceMock.EXPECT().UpdateClusterConfig(ctx, gomock.Any()).Return(nil, errors.New("error updating cluster config"))
+		updated, err := UpdateClusterSecurityGroups(ctx, opts)
+		Expect(err).To(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+})
+
+var _ = Describe("UpdateSecretsEncryption", func() {
+	var (
+		mockController *gomock.Controller
+		eksServiceMock *mock_services.MockEKSServiceInterface
+		opts           *UpdateSecretsEncryptionOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		opts = &UpdateSecretsEncryptionOpts{
+			EKSService: eksServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName:       "test",
+					SecretsEncryption: aws.Bool(true),
+					KmsKey:            aws.String("test-key"),
+				},
+			},
+			UpstreamClusterSpec: &eksv1.EKSClusterConfigSpec{
+				SecretsEncryption: aws.Bool(false),
+				KmsKey:            aws.String(""),
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should enable secrets encryption", func() {
+		eksServiceMock.EXPECT().AssociateEncryptionConfig(ctx, &eks.AssociateEncryptionConfigInput{
+			ClusterName: aws.String("test"),
+			EncryptionConfig: []ekstypes.EncryptionConfig{
+				{
+					Provider:  &ekstypes.Provider{KeyArn: aws.String("test-key")},
+					Resources: []string{"secrets"},
+				},
+			},
+		}).Return(&eks.AssociateEncryptionConfigOutput{}, nil)
+		updated, err := UpdateSecretsEncryption(ctx, opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeTrue())
+	})
+
+	It("should not update secrets encryption that is already enabled", func() {
+		opts.UpstreamClusterSpec.SecretsEncryption = aws.Bool(true)
+		opts.UpstreamClusterSpec.KmsKey = aws.String("other-key")
+		updated, err := UpdateSecretsEncryption(ctx, opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeFalse())
+
+		opts.Config.Spec.SecretsEncryption = aws.Bool(false)
+		updated, err = UpdateSecretsEncryption(ctx, opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should not update secrets encryption that is disabled", func() {
+		opts.Config.Spec.SecretsEncryption = aws.Bool(false)
+		updated, err := UpdateSecretsEncryption(ctx, opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should return error if enabling secrets encryption failed", func() {
+		eksServiceMock.EXPECT().AssociateEncryptionConfig(ctx, gomock.Any()).Return(nil, errors.New("error associating encryption config"))
+		updated, err := UpdateSecretsEncryption(ctx, opts)
+		Expect(err).To(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+})
+
+var _ = Describe("UpdateRolePolicies", func() {
+	var (
+		mockController *gomock.Controller
+		iamServiceMock *mock_services.MockIAMServiceInterface
+		opts           *UpdateRolePoliciesOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		iamServiceMock = mock_services.NewMockIAMServiceInterface(mockController)
+		opts = &UpdateRolePoliciesOpts{
+			IAMService: iamServiceMock,
+			RoleARN:    "arn:aws:iam::123456789012:role/test-eks-service-role-AWSServiceRoleForAmazonEKS",
+			PolicyARNs: []string{
+				"arn:aws:iam::aws:policy/AmazonEKSComputePolicy",
+				"arn:aws:iam::aws:policy/AmazonEKSNetworkingPolicy",
+			},
+			AttachedPolicyARNs: []string{
+				"arn:aws:iam::aws:policy/AmazonEKSComputePolicy",
+				"arn:aws:iam::aws:policy/CloudWatchAgentServerPolicy",
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should attach added policies and detach removed ones", func() {
+		iamServiceMock.EXPECT().ListAttachedRolePolicies(ctx, &iam.ListAttachedRolePoliciesInput{
+			RoleName: aws.String("test-eks-service-role-AWSServiceRoleForAmazonEKS"),
+		}).Return(&iam.ListAttachedRolePoliciesOutput{
+			AttachedPolicies: []iamtypes.AttachedPolicy{
+				{PolicyArn: aws.String("arn:aws:iam::aws:policy/AmazonEKSClusterPolicy")},
+				{PolicyArn: aws.String("arn:aws:iam::aws:policy/AmazonEKSComputePolicy")},
+				{PolicyArn: aws.String("arn:aws:iam::aws:policy/CloudWatchAgentServerPolicy")},
+			},
+		}, nil)
+		iamServiceMock.EXPECT().AttachRolePolicy(ctx, &iam.AttachRolePolicyInput{
+			RoleName:  aws.String("test-eks-service-role-AWSServiceRoleForAmazonEKS"),
+			PolicyArn: aws.String("arn:aws:iam::aws:policy/AmazonEKSNetworkingPolicy"),
+		}).Return(&iam.AttachRolePolicyOutput{}, nil)
+		iamServiceMock.EXPECT().DetachRolePolicy(ctx, &iam.DetachRolePolicyInput{
+			RoleName:  aws.String("test-eks-service-role-AWSServiceRoleForAmazonEKS"),
+			PolicyArn: aws.String("arn:aws:iam::aws:policy/CloudWatchAgentServerPolicy"),
+		}).Return(&iam.DetachRolePolicyOutput{}, nil)
+
+		updated, err := UpdateRolePolicies(ctx, opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeTrue())
+	})
+
+	It("should not update a role that has the policies attached", func() {
+		opts.AttachedPolicyARNs = opts.PolicyARNs
+		iamServiceMock.EXPECT().ListAttachedRolePolicies(ctx, gomock.Any()).Return(&iam.ListAttachedRolePoliciesOutput{
+			AttachedPolicies: []iamtypes.AttachedPolicy{
+				{PolicyArn: aws.String("arn:aws:iam::aws:policy/AmazonEKSComputePolicy")},
+			},
+			IsTruncated: true,
+			Marker:      aws.String("next"),
+		}, nil)
+		iamServiceMock.EXPECT().ListAttachedRolePolicies(ctx, &iam.ListAttachedRolePoliciesInput{
+			RoleName: aws.String("test-eks-service-role-AWSServiceRoleForAmazonEKS"),
+			Marker:   aws.String("next"),
+		}).Return(&iam.ListAttachedRolePoliciesOutput{
+			AttachedPolicies: []iamtypes.AttachedPolicy{
+				{PolicyArn: aws.String("arn:aws:iam::aws:policy/AmazonEKSNetworkingPolicy")},
+			},
+		}, nil)
+
+		updated, err := UpdateRolePolicies(ctx, opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should return error if attaching a policy failed", func() {
+		iamServiceMock.EXPECT().ListAttachedRolePolicies(ctx, gomock.Any()).Return(&iam.ListAttachedRolePoliciesOutput{}, nil)
+		iamServiceMock.EXPECT().AttachRolePolicy(ctx, gomock.Any()).Return(nil, errors.New("error attaching policy"))
+
+		_, err := UpdateRolePolicies(ctx, opts)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("UpdateRoleStackPolicies", func() {
+	var (
+		mockController   *gomock.Controller
+		cfnServiceMock   *mock_services.MockCloudFormationServiceInterface
+		opts             *UpdateRoleStackPoliciesOpts
+		expectedTemplate string
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		cfnServiceMock = mock_services.NewMockCloudFormationServiceInterface(mockController)
+		opts = &UpdateRoleStackPoliciesOpts{
+			CloudFormationService: cfnServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				Spec: eksv1.EKSClusterConfigSpec{DisplayName: "test", Region: "cn-northwest-1"},
+			},
+			StackName:  "test-node-instance-role",
+			Template:   TemplateNodeInstanceRole,
+			PolicyARNs: []string{"arn:aws:iam::aws:policy/AmazonSSMManagedInstanceCore", ContainerInsightsPolicyARN},
+		}
+		expectedTemplate = fmt.Sprintf(templates.NodeInstanceRoleTemplate, "ec2.amazonaws.com.cn")
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should update the policy parameter of the stack", func() {
+		cfnServiceMock.EXPECT().UpdateStack(ctx, &cloudformation.UpdateStackInput{
+			StackName:    aws.String("test-node-instance-role"),
+			TemplateBody: aws.String(expectedTemplate),
+			Capabilities: []cftypes.Capability{cftypes.CapabilityCapabilityIam},
+			Parameters: []cftypes.Parameter{{
+				ParameterKey:   aws.String("AdditionalPolicyArns"),
+				ParameterValue: aws.String("arn:aws:iam::aws:policy/AmazonSSMManagedInstanceCore," + ContainerInsightsPolicyARN),
+			}},
+		}).Return(&cloudformation.UpdateStackOutput{}, nil)
+
+		updated, err := UpdateRoleStackPolicies(ctx, opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeTrue())
+	})
+
+	It("should leave the parameter out to remove all additional policies", func() {
+		opts.Template = TemplateServiceRole
+		opts.StackName = "test-eks-service-role"
+		opts.PolicyARNs = nil
+		cfnServiceMock.EXPECT().UpdateStack(ctx, &cloudformation.UpdateStackInput{
+			StackName:    aws.String("test-eks-service-role"),
+			TemplateBody: aws.String(templates.ServiceRoleTemplate),
+			Capabilities: []cftypes.Capability{cftypes.CapabilityCapabilityIam},
+		}).Return(&cloudformation.UpdateStackOutput{}, nil)
+
+		updated, err := UpdateRoleStackPolicies(ctx, opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeTrue())
+	})
+
+	It("should not fail if the stack already has the policies", func() {
+		cfnServiceMock.EXPECT().UpdateStack(ctx, gomock.Any()).Return(nil, &smithy.GenericAPIError{
+			Code:    "ValidationError",
+			Message: "No updates are to be performed.",
+		})
+
+		updated, err := UpdateRoleStackPolicies(ctx, opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should return error if updating the stack failed", func() {
+		cfnServiceMock.EXPECT().UpdateStack(ctx, gomock.Any()).Return(nil, &smithy.GenericAPIError{
+			Code:    "ValidationError",
+			Message: "Stack:test-node-instance-role is in UPDATE_IN_PROGRESS state and can not be updated.",
+		})
+
+		_, err := UpdateRoleStackPolicies(ctx, opts)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("UpdateAuthenticationMode", func() {
+	var (
+		mockController *gomock.Controller
+		eksServiceMock *mock_services.MockEKSServiceInterface
+		opts           *UpdateAuthenticationModeOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		opts = &UpdateAuthenticationModeOpts{
+			EKSService: eksServiceMock,
+			Config:     &eksv1.EKSClusterConfig{Spec: eksv1.EKSClusterConfigSpec{DisplayName: "test"}},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should enable access entries on clusters that only use the aws-auth config map", func() {
+		eksServiceMock.EXPECT().DescribeCluster(ctx, gomock.Any()).Return(&eks.DescribeClusterOutput{
+			Cluster: &ekstypes.Cluster{AccessConfig: &ekstypes.AccessConfigResponse{AuthenticationMode: ekstypes.AuthenticationModeConfigMap}},
+		}, nil)
+		eksServiceMock.EXPECT().UpdateClusterConfig(ctx, &eks.UpdateClusterConfigInput{
+			Name:         aws.String("test"),
+			AccessConfig: &ekstypes.UpdateAccessConfigRequest{AuthenticationMode: ekstypes.AuthenticationModeApiAndConfigMap},
+		}).Return(&eks.UpdateClusterConfigOutput{}, nil)
+
+		updating, err := UpdateAuthenticationMode(ctx, opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updating).To(BeTrue())
+	})
+
+	It("should not update clusters that support access entries", func() {
+		eksServiceMock.EXPECT().DescribeCluster(ctx, gomock.Any()).Return(&eks.DescribeClusterOutput{
+			Cluster: &ekstypes.Cluster{AccessConfig: &ekstypes.AccessConfigResponse{AuthenticationMode: ekstypes.AuthenticationModeApi}},
+		}, nil)
+
+		updating, err := UpdateAuthenticationMode(ctx, opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updating).To(BeFalse())
+	})
+})
+
+var _ = Describe("UpdateClusterAdminPrincipals", func() {
+	const (
+		adminRole      = "arn:aws:iam::123456789012:role/break-glass"
+		adminUser      = "arn:aws:iam::123456789012:user/oncall"
+		removedRole    = "arn:aws:iam::123456789012:role/former-admin"
+		adminPolicyARN = "arn:aws:eks::aws:cluster-access-policy/AmazonEKSClusterAdminPolicy"
+	)
+
+	var (
+		mockController *gomock.Controller
+		eksServiceMock *mock_services.MockEKSServiceInterface
+		opts           *UpdateClusterAdminPrincipalsOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		opts = &UpdateClusterAdminPrincipalsOpts{
+			EKSService: eksServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "c-abc"},
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName:            "test",
+					ClusterAdminPrincipals: []string{adminRole, adminUser},
+				},
+			},
+			GrantedPrincipals: []string{adminRole, removedRole},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should create missing access entries and delete the ones of removed principals", func() {
+		eksServiceMock.EXPECT().ListAccessEntries(ctx, gomock.Any()).Return(&eks.ListAccessEntriesOutput{
+			AccessEntries: []string{adminRole, removedRole, "arn:aws:iam::123456789012:role/creator"},
+		}, nil)
+		eksServiceMock.EXPECT().ListAssociatedAccessPolicies(ctx, gomock.Any()).Return(&eks.ListAssociatedAccessPoliciesOutput{
+			AssociatedAccessPolicies: []ekstypes.AssociatedAccessPolicy{
+				{PolicyArn: aws.String(adminPolicyARN), AccessScope: &ekstypes.AccessScope{Type: ekstypes.AccessScopeTypeCluster}},
+			},
+		}, nil)
+		eksServiceMock.EXPECT().CreateAccessEntry(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, input *eks.CreateAccessEntryInput) (*eks.CreateAccessEntryOutput, error) {
+				Expect(aws.ToString(input.PrincipalArn)).To(Equal(adminUser))
+				Expect(input.Tags).To(HaveKeyWithValue(ManagedTagKey, "true"))
+				return &eks.CreateAccessEntryOutput{}, nil
+			})
+		eksServiceMock.EXPECT().AssociateAccessPolicy(ctx, &eks.AssociateAccessPolicyInput{
+			ClusterName:  aws.String("test"),
+			PrincipalArn: aws.String(adminUser),
+			PolicyArn:    aws.String(adminPolicyARN),
+			AccessScope:  &ekstypes.AccessScope{Type: ekstypes.AccessScopeTypeCluster},
+		}).Return(&eks.AssociateAccessPolicyOutput{}, nil)
+		eksServiceMock.EXPECT().DescribeAccessEntry(ctx, &eks.DescribeAccessEntryInput{
+			ClusterName:  aws.String("test"),
+			PrincipalArn: aws.String(removedRole),
+		}).Return(&eks.DescribeAccessEntryOutput{
+			AccessEntry: &ekstypes.AccessEntry{Tags: map[string]string{ManagedTagKey: "true"}},
+		}, nil)
+		eksServiceMock.EXPECT().DeleteAccessEntry(ctx, &eks.DeleteAccessEntryInput{
+			ClusterName:  aws.String("test"),
+			PrincipalArn: aws.String(removedRole),
+		}).Return(&eks.DeleteAccessEntryOutput{}, nil)
+
+		updated, err := UpdateClusterAdminPrincipals(ctx, opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeTrue())
+	})
+
+	It("should keep the access entries of removed principals the operator didn't create", func() {
+		opts.Config.Spec.ClusterAdminPrincipals = nil
+		eksServiceMock.EXPECT().ListAccessEntries(ctx, gomock.Any()).Return(&eks.ListAccessEntriesOutput{
+			AccessEntries: []string{adminRole, removedRole},
+		}, nil)
+		eksServiceMock.EXPECT().DescribeAccessEntry(ctx, gomock.Any()).Return(&eks.DescribeAccessEntryOutput{
+			AccessEntry: &ekstypes.AccessEntry{Tags: map[string]string{"team": "platform"}},
+		}, nil).Times(2)
+
+		updated, err := UpdateClusterAdminPrincipals(ctx, opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should associate the cluster admin policy with existing access entries", func() {
+		opts.Config.Spec.ClusterAdminPrincipals = []string{"arn:aws-us-gov:iam::123456789012:role/break-glass"}
+		opts.GrantedPrincipals = nil
+		eksServiceMock.EXPECT().ListAccessEntries(ctx, gomock.Any()).Return(&eks.ListAccessEntriesOutput{
+			AccessEntries: []string{"arn:aws-us-gov:iam::123456789012:role/break-glass"},
+		}, nil)
+		eksServiceMock.EXPECT().ListAssociatedAccessPolicies(ctx, gomock.Any()).Return(&eks.ListAssociatedAccessPoliciesOutput{
+			AssociatedAccessPolicies: []ekstypes.AssociatedAccessPolicy{
+				{
+					PolicyArn:   aws.String("arn:aws-us-gov:eks::aws:cluster-access-policy/AmazonEKSClusterAdminPolicy"),
+					AccessScope: &ekstypes.AccessScope{Type: ekstypes.AccessScopeTypeNamespace, Namespaces: []string{"default"}},
+				},
+			},
+		}, nil)
+		eksServiceMock.EXPECT().AssociateAccessPolicy(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, input *eks.AssociateAccessPolicyInput) (*eks.AssociateAccessPolicyOutput, error) {
+				Expect(aws.ToString(input.PolicyArn)).To(Equal("arn:aws-us-gov:eks::aws:cluster-access-policy/AmazonEKSClusterAdminPolicy"))
+				return &eks.AssociateAccessPolicyOutput{}, nil
+			})
+
+		updated, err := UpdateClusterAdminPrincipals(ctx, opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeTrue())
+	})
+
+	It("should not update access entries that match the spec", func() {
+		opts.GrantedPrincipals = []string{adminRole, adminUser}
+		eksServiceMock.EXPECT().ListAccessEntries(ctx, gomock.Any()).Return(&eks.ListAccessEntriesOutput{
+			AccessEntries: []string{adminRole, adminUser},
+		}, nil)
+		eksServiceMock.EXPECT().ListAssociatedAccessPolicies(ctx, gomock.Any()).Return(&eks.ListAssociatedAccessPoliciesOutput{
+			AssociatedAccessPolicies: []ekstypes.AssociatedAccessPolicy{
+				{PolicyArn: aws.String(adminPolicyARN), AccessScope: &ekstypes.AccessScope{Type: ekstypes.AccessScopeTypeCluster}},
+			},
+		}, nil).Times(2)
+
+		updated, err := UpdateClusterAdminPrincipals(ctx, opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+})
+
+var _ = Describe("UpdateEBSAddonPodIdentity", func() {
+	var (
+		mockController            *gomock.Controller
+		eksServiceMock            *mock_services.MockEKSServiceInterface
+		cloudFormationServiceMock *mock_services.MockCloudFormationServiceInterface
+		opts                      *UpdateEBSAddonPodIdentityOpts
+		associationARN            string
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		cloudFormationServiceMock = mock_services.NewMockCloudFormationServiceInterface(mockController)
+		opts = &UpdateEBSAddonPodIdentityOpts{
+			EKSService: eksServiceMock,
+			CFService:  cloudFormationServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName: "test",
+				},
+			},
+		}
+		associationARN = "arn:aws:eks:us-east-1:123456789012:podidentityassociation/test/a-1"
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	expectRole := func() {
+		cloudFormationServiceMock.EXPECT().CreateStack(ctx, gomock.Any()).Return(nil, nil)
+		cloudFormationServiceMock.EXPECT().DescribeStacks(ctx, gomock.Any()).Return(
+			&cloudformation.DescribeStacksOutput{
+				Stacks: []cftypes.Stack{
+					{
+						StackStatus: createCompleteStatus,
+						Outputs: []cftypes.Output{
+							{
+								OutputKey:   aws.String("EBSCSIDriverPodIdentityRole"),
+								OutputValue: aws.String("roleArn"),
+							},
+						},
+					},
+				},
+			}, nil)
+	}
+
+	It("should not update addon with a matching pod identity association", func() {
+		eksServiceMock.EXPECT().DescribeAddon(ctx, gomock.Any()).Return(&eks.DescribeAddonOutput{
+			Addon: &ekstypes.Addon{PodIdentityAssociations: []string{associationARN}},
+		}, nil)
+		expectRole()
+		eksServiceMock.EXPECT().DescribePodIdentityAssociation(ctx, &eks.DescribePodIdentityAssociationInput{
+			ClusterName:   aws.String("test"),
+			AssociationId: aws.String("a-1"),
+		}).Return(&eks.DescribePodIdentityAssociationOutput{
+			Association: &ekstypes.PodIdentityAssociation{
+				ServiceAccount: aws.String("ebs-csi-controller-sa"),
+				RoleArn:        aws.String("roleArn"),
+			},
+		}, nil)
+		updated, err := UpdateEBSAddonPodIdentity(ctx, opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should restore a deleted pod identity association", func() {
+		eksServiceMock.EXPECT().DescribeAddon(ctx, gomock.Any()).Return(&eks.DescribeAddonOutput{
+			Addon: &ekstypes.Addon{},
+		}, nil)
+		expectRole()
+		eksServiceMock.EXPECT().UpdateAddon(ctx, &eks.UpdateAddonInput{
+			AddonName:   aws.String("aws-ebs-csi-driver"),
+			ClusterName: aws.String("test"),
+			PodIdentityAssociations: []ekstypes.AddonPodIdentityAssociations{
+				{RoleArn: aws.String("roleArn"), ServiceAccount: aws.String("ebs-csi-controller-sa")},
+			},
+		}).Return(&eks.UpdateAddonOutput{}, nil)
+		updated, err := UpdateEBSAddonPodIdentity(ctx, opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeTrue())
+	})
+
+	It("should restore a pod identity association with another role", func() {
+		eksServiceMock.EXPECT().DescribeAddon(ctx, gomock.Any()).Return(&eks.DescribeAddonOutput{
+			Addon: &ekstypes.Addon{PodIdentityAssociations: []string{associationARN}},
+		}, nil)
+		expectRole()
+		eksServiceMock.EXPECT().DescribePodIdentityAssociation(ctx, gomock.Any()).Return(&eks.DescribePodIdentityAssociationOutput{
+			Association: &ekstypes.PodIdentityAssociation{
+				ServiceAccount: aws.String("ebs-csi-controller-sa"),
+				RoleArn:        aws.String("otherRoleArn"),
+			},
+		}, nil)
+		eksServiceMock.EXPECT().UpdateAddon(ctx, gomock.Any()).Return(&eks.UpdateAddonOutput{}, nil)
+		updated, err := UpdateEBSAddonPodIdentity(ctx, opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeTrue())
+	})
+
+	It("should not update addon that uses the oidc provider", func() {
+		eksServiceMock.EXPECT().DescribeAddon(ctx, gomock.Any()).Return(&eks.DescribeAddonOutput{
+			Addon: &ekstypes.Addon{ServiceAccountRoleArn: aws.String("roleArn")},
+		}, nil)
+		updated, err := UpdateEBSAddonPodIdentity(ctx, opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should return error if updating addon failed", func() {
+		eksServiceMock.EXPECT().DescribeAddon(ctx, gomock.Any()).Return(&eks.DescribeAddonOutput{
+			Addon: &ekstypes.Addon{},
+		}, nil)
+		expectRole()
+		eksServiceMock.EXPECT().UpdateAddon(ctx, gomock.Any()).Return(nil, errors.New("error updating addon"))
+		updated, err := UpdateEBSAddonPodIdentity(ctx, opts)
+		Expect(err).To(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+})
+
+var _ = Describe("UpdatePodIdentityAssociations", func() {
+	var (
+		mockController                   *gomock.Controller
+		eksServiceMock                   *mock_services.MockEKSServiceInterface
+		updatePodIdentityAssociationOpts *UpdatePodIdentityAssociationsOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		updatePodIdentityAssociationOpts = &UpdatePodIdentityAssociationsOpts{
+			EKSService: eksServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName: "test",
+					PodIdentityAssociations: []eksv1.PodIdentityAssociation{
+						{Namespace: "ns", ServiceAccount: "created", RoleARN: "role1"},
+						{Namespace: "ns", ServiceAccount: "updated", RoleARN: "role2"},
+						{Namespace: "ns", ServiceAccount: "unchanged", RoleARN: "role3"},
+					},
+				},
+			},
+			UpstreamAssociations: []ekstypes.PodIdentityAssociation{
+				{AssociationId: aws.String("a-updated"), Namespace: aws.String("ns"), ServiceAccount: aws.String("updated"), RoleArn: aws.String("role1")},
+				{AssociationId: aws.String("a-unchanged"), Namespace: aws.String("ns"), ServiceAccount: aws.String("unchanged"), RoleArn: aws.String("role3")},
+				{AssociationId: aws.String("a-deleted"), Namespace: aws.String("ns"), ServiceAccount: aws.String("deleted"), RoleArn: aws.String("role4")},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should create, update and delete pod identity associations", func() {
+		eksServiceMock.EXPECT().CreatePodIdentityAssociation(ctx, &eks.CreatePodIdentityAssociationInput{
+			ClusterName:    aws.String("test"),
+			Namespace:      aws.String("ns"),
+			ServiceAccount: aws.String("created"),
+			RoleArn:        aws.String("role1"),
+		}).Return(nil, nil)
+		eksServiceMock.EXPECT().UpdatePodIdentityAssociation(ctx, &eks.UpdatePodIdentityAssociationInput{
+			ClusterName:   aws.String("test"),
+			AssociationId: aws.String("a-updated"),
+			RoleArn:       aws.String("role2"),
+		}).Return(nil, nil)
+		eksServiceMock.EXPECT().DeletePodIdentityAssociation(ctx, &eks.DeletePodIdentityAssociationInput{
+			ClusterName:   aws.String("test"),
+			AssociationId: aws.String("a-deleted"),
+		}).Return(nil, nil)
+
+		updated, err := UpdatePodIdentityAssociations(ctx, updatePodIdentityAssociationOpts)
+		Expect(updated).To(BeTrue())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should not update pod identity associations if they are unset", func() {
+		updatePodIdentityAssociationOpts.Config.Spec.PodIdentityAssociations = nil
+		updated, err := UpdatePodIdentityAssociations(ctx, updatePodIdentityAssociationOpts)
+		Expect(updated).To(BeFalse())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should return error if creating a pod identity association failed", func() {
+		eksServiceMock.EXPECT().CreatePodIdentityAssociation(ctx, gomock.Any()).Return(nil, errors.New("error creating pod identity association"))
+		updated, err := UpdatePodIdentityAssociations(ctx, updatePodIdentityAssociationOpts)
+		Expect(updated).To(BeFalse())
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("UpdateIdentityProviderConfigs", func() {
+	var (
+		mockController                *gomock.Controller
+		eksServiceMock                *mock_services.MockEKSServiceInterface
+		updateIdentityProviderConfigs *UpdateIdentityProviderConfigsOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		updateIdentityProviderConfigs = &UpdateIdentityProviderConfigsOpts{
+			EKSService: eksServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName: "test",
+					IdentityProviderConfigs: []eksv1.IdentityProviderConfig{
+						{Name: "corp", IssuerURL: "https://issuer", ClientID: "client", GroupsClaim: "groups"},
+					},
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should associate missing identity provider configs", func() {
+		eksServiceMock.EXPECT().AssociateIdentityProviderConfig(ctx, &eks.AssociateIdentityProviderConfigInput{
+			ClusterName: aws.String("test"),
+			Oidc: &ekstypes.OidcIdentityProviderConfigRequest{
+				IdentityProviderConfigName: aws.String("corp"),
+				IssuerUrl:                  aws.String("https://issuer"),
+				ClientId:                   aws.String("client"),
+				GroupsClaim:                aws.String("groups"),
+			},
+		}).Return(nil, nil)
+
+		updated, err := UpdateIdentityProviderConfigs(ctx, updateIdentityProviderConfigs)
+		Expect(updated).To(BeTrue())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should not update identity provider configs that match", func() {
+		updateIdentityProviderConfigs.UpstreamConfigs = []ekstypes.OidcIdentityProviderConfig{{
+			IdentityProviderConfigName: aws.String("corp"),
+			IssuerUrl:                  aws.String("https://issuer"),
+			ClientId:                   aws.String("client"),
+			GroupsClaim:                aws.String("groups"),
+			UsernameClaim:              aws.String("sub"),
+			Status:                     ekstypes.ConfigStatusActive,
+		}}
+
+		updated, err := UpdateIdentityProviderConfigs(ctx, updateIdentityProviderConfigs)
+		Expect(updated).To(BeFalse())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should disassociate changed and removed identity provider configs before associating", func() {
+		updateIdentityProviderConfigs.UpstreamConfigs = []ekstypes.OidcIdentityProviderConfig{{
+			IdentityProviderConfigName: aws.String("corp"),
+			IssuerUrl:                  aws.String("https://issuer"),
+			ClientId:                   aws.String("old-client"),
+			Status:                     ekstypes.ConfigStatusActive,
+		}}
+		eksServiceMock.EXPECT().DisassociateIdentityProviderConfig(ctx, &eks.DisassociateIdentityProviderConfigInput{
+			ClusterName:            aws.String("test"),
+			IdentityProviderConfig: &ekstypes.IdentityProviderConfig{Name: aws.String("corp"), Type: aws.String("oidc")},
+		}).Return(nil, nil)
+
+		updated, err := UpdateIdentityProviderConfigs(ctx, updateIdentityProviderConfigs)
+		Expect(updated).To(BeTrue())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should not update identity provider configs while they are pending", func() {
+		updateIdentityProviderConfigs.UpstreamConfigs = []ekstypes.OidcIdentityProviderConfig{{
+			IdentityProviderConfigName: aws.String("old"),
+			Status:                     ekstypes.ConfigStatusDeleting,
+		}}
+
+		updated, err := UpdateIdentityProviderConfigs(ctx, updateIdentityProviderConfigs)
+		Expect(updated).To(BeFalse())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should not update identity provider configs if they are unset", func() {
+		updateIdentityProviderConfigs.Config.Spec.IdentityProviderConfigs = nil
+		updated, err := UpdateIdentityProviderConfigs(ctx, updateIdentityProviderConfigs)
+		Expect(updated).To(BeFalse())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should return error if associating an identity provider config failed", func() {
+		eksServiceMock.EXPECT().AssociateIdentityProviderConfig(ctx, gomock.Any()).Return(nil, errors.New("error associating"))
+		updated, err := UpdateIdentityProviderConfigs(ctx, updateIdentityProviderConfigs)
+		Expect(updated).To(BeFalse())
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("UpdateAuditLogging", func() {
+	var (
+		mockController        *gomock.Controller
+		logsServiceMock       *mock_services.MockCloudWatchLogsServiceInterface
+		cloudWatchServiceMock *mock_services.MockCloudWatchServiceInterface
+		updateAuditLogging    *UpdateAuditLoggingOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		logsServiceMock = mock_services.NewMockCloudWatchLogsServiceInterface(mockController)
+		cloudWatchServiceMock = mock_services.NewMockCloudWatchServiceInterface(mockController)
+		updateAuditLogging = &UpdateAuditLoggingOpts{
+			CloudWatchLogsService: logsServiceMock,
+			CloudWatchService:     cloudWatchServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName: "test",
+					Tags:        map[string]string{"team": "a", "env": "prod"},
+					AuditLogging: &eksv1.AuditLogging{
+						RetentionInDays: 90,
+						AlarmThreshold:  5,
+						AlarmActions:    []string{"arn:aws:sns:us-east-1:123456789012:audit"},
+					},
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should set the retention and put the metric filter and alarm", func() {
+		logsServiceMock.EXPECT().PutRetentionPolicy(ctx, &services.PutRetentionPolicyInput{
+			LogGroupName:    aws.String("/aws/eks/test/cluster"),
+			RetentionInDays: aws.Int32(90),
+		}).Return(&services.PutRetentionPolicyOutput{}, nil)
+		logsServiceMock.EXPECT().PutMetricFilter(ctx, &services.PutMetricFilterInput{
+			LogGroupName:  aws.String("/aws/eks/test/cluster"),
+			FilterName:    aws.String("test-audit-events"),
+			FilterPattern: aws.String(DefaultAuditFilterPattern),
+			MetricTransformations: []services.MetricTransformation{{
+				MetricName:      aws.String("test-audit-events"),
+				MetricNamespace: aws.String("EKS/AuditEvents"),
+				MetricValue:     aws.String("1"),
+				DefaultValue:    aws.Float64(0),
+			}},
+		}).Return(&services.PutMetricFilterOutput{}, nil)
+		cloudWatchServiceMock.EXPECT().PutMetricAlarm(ctx, gomock.Any()).DoAndReturn(
+			func(_ interface{}, input *services.PutMetricAlarmInput) (*services.PutMetricAlarmOutput, error) {
+				Expect(aws.ToString(input.AlarmName)).To(Equal("test-audit-events"))
+				Expect(aws.ToInt32(input.Period)).To(Equal(int32(300)))
+				Expect(aws.ToFloat64(input.Threshold)).To(Equal(float64(5)))
+				Expect(input.AlarmActions).To(Equal([]string{"arn:aws:sns:us-east-1:123456789012:audit"}))
+				Expect(input.Tags).To(Equal([]services.Tag{
+					{Key: aws.String("env"), Value: aws.String("prod")},
+					{Key: aws.String("team"), Value: aws.String("a")},
+				}))
+				return &services.PutMetricAlarmOutput{}, nil
+			})
+
+		Expect(UpdateAuditLogging(ctx, updateAuditLogging)).To(Succeed())
+	})
+
+	It("should remove the retention and alarm removed from the spec", func() {
+		updateAuditLogging.Config.Status.AuditLogging = updateAuditLogging.Config.Spec.AuditLogging
+		updateAuditLogging.Config.Spec.AuditLogging = &eksv1.AuditLogging{FilterPattern: `{ $.verb = "delete" }`}
+		logsServiceMock.EXPECT().DeleteRetentionPolicy(ctx, gomock.Any()).Return(&services.DeleteRetentionPolicyOutput{}, nil)
+		logsServiceMock.EXPECT().PutMetricFilter(ctx, gomock.Any()).DoAndReturn(
+			func(_ interface{}, input *services.PutMetricFilterInput) (*services.PutMetricFilterOutput, error) {
+				Expect(aws.ToString(input.FilterPattern)).To(Equal(`{ $.verb = "delete" }`))
+				return &services.PutMetricFilterOutput{}, nil
+			})
+		cloudWatchServiceMock.EXPECT().DeleteAlarms(ctx, &services.DeleteAlarmsInput{
+			AlarmNames: []string{"test-audit-events"},
+		}).Return(&services.DeleteAlarmsOutput{}, nil)
+
+		Expect(UpdateAuditLogging(ctx, updateAuditLogging)).To(Succeed())
+	})
+
+	It("should remove all audit resources once audit logging is removed from the spec", func() {
+		updateAuditLogging.Config.Status.AuditLogging = updateAuditLogging.Config.Spec.AuditLogging
+		updateAuditLogging.Config.Spec.AuditLogging = nil
+		logsServiceMock.EXPECT().DeleteRetentionPolicy(ctx, gomock.Any()).Return(&services.DeleteRetentionPolicyOutput{}, nil)
+		cloudWatchServiceMock.EXPECT().DeleteAlarms(ctx, gomock.Any()).Return(&services.DeleteAlarmsOutput{}, nil)
+		logsServiceMock.EXPECT().DeleteMetricFilter(ctx, gomock.Any()).Return(&services.DeleteMetricFilterOutput{}, nil)
+
+		Expect(UpdateAuditLogging(ctx, updateAuditLogging)).To(Succeed())
+	})
+
+	It("should fail if the log group doesn't exist yet", func() {
+		logsServiceMock.EXPECT().PutRetentionPolicy(ctx, gomock.Any()).Return(nil, &services.APIError{
+			StatusCode: 400,
+			Code:       services.CloudWatchLogsResourceNotFound,
+		})
+
+		Expect(UpdateAuditLogging(ctx, updateAuditLogging)).ToNot(Succeed())
+	})
+})
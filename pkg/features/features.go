@@ -0,0 +1,121 @@
+package features
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Feature is the name of a feature of the operator that can be enabled or disabled per installation with the
+// --feature-gates flag, so that large or risky features can ship disabled.
+type Feature string
+
+// Stage is the maturity of a feature. Alpha features are disabled by default, beta and GA features are enabled.
+type Stage string
+
+const (
+	Alpha Stage = "alpha"
+	Beta  Stage = "beta"
+	GA    Stage = "ga"
+)
+
+const (
+	// DownstreamProbe checks that the API server of clusters is reachable after their updates finish and records the
+	// result in the DownstreamHealthy condition
+	DownstreamProbe Feature = "DownstreamProbe"
+	// ConcurrentNodegroups creates and deletes the node groups of a cluster concurrently instead of one at a time
+	ConcurrentNodegroups Feature = "ConcurrentNodegroups"
+	// DownstreamNodeCounts counts the ready nodes of each node group in the downstream cluster and records them in the
+	// node group statuses every ten minutes
+	DownstreamNodeCounts Feature = "DownstreamNodeCounts"
+	// ClusterInsights reads the insights EKS reports for clusters, like deprecated APIs or slow admission webhooks that
+	// put the control plane at risk, and records the ones with warnings in the InsightWarnings condition. They are
+	// read at most once every ten minutes per cluster
+	ClusterInsights Feature = "ClusterInsights"
+)
+
+type spec struct {
+	Default bool
+	Stage   Stage
+}
+
+var known = map[Feature]spec{
+	DownstreamProbe:      {Default: true, Stage: Beta},
+	ConcurrentNodegroups: {Default: true, Stage: Beta},
+	DownstreamNodeCounts: {Default: false, Stage: Alpha},
+	ClusterInsights:      {Default: false, Stage: Alpha},
+}
+
+var gates = struct {
+	sync.RWMutex
+	overrides map[Feature]bool
+}{}
+
+// State is whether a feature is enabled, as reported at startup and in the metrics.
+type State struct {
+	Feature Feature
+	Stage   Stage
+	Enabled bool
+}
+
+// Set parses a comma separated list of Feature=bool pairs, e.g. DownstreamProbe=false, and overrides the defaults of
+// the listed features. Unknown features are rejected, so that typos don't go unnoticed.
+func Set(value string) error {
+	overrides := map[Feature]bool{}
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, enabled, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("invalid feature gate [%s], must be in the format Feature=true|false", pair)
+		}
+		feature := Feature(strings.TrimSpace(name))
+		if _, ok := known[feature]; !ok {
+			return fmt.Errorf("unknown feature gate [%s], known gates are %s", feature, strings.Join(Known(), ", "))
+		}
+		parsed, err := strconv.ParseBool(strings.TrimSpace(enabled))
+		if err != nil {
+			return fmt.Errorf("invalid value [%s] of feature gate [%s]: %w", enabled, feature, err)
+		}
+		overrides[feature] = parsed
+	}
+
+	gates.Lock()
+	defer gates.Unlock()
+	gates.overrides = overrides
+	return nil
+}
+
+// Enabled returns whether the feature is enabled.
+func Enabled(feature Feature) bool {
+	gates.RLock()
+	defer gates.RUnlock()
+	if enabled, ok := gates.overrides[feature]; ok {
+		return enabled
+	}
+	return known[feature].Default
+}
+
+// Known returns the names of the known features, sorted by name.
+func Known() []string {
+	names := make([]string, 0, len(known))
+	for feature := range known {
+		names = append(names, string(feature))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// States returns the state of every known feature, sorted by name.
+func States() []State {
+	states := make([]State, 0, len(known))
+	for _, name := range Known() {
+		feature := Feature(name)
+		states = append(states, State{Feature: feature, Stage: known[feature].Stage, Enabled: Enabled(feature)})
+	}
+	return states
+}
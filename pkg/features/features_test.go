@@ -0,0 +1,38 @@
+package features
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSet(t *testing.T) {
+	t.Cleanup(func() { require.NoError(t, Set("")) })
+
+	assert.True(t, Enabled(DownstreamProbe))
+	assert.True(t, Enabled(ConcurrentNodegroups))
+	assert.False(t, Enabled(DownstreamNodeCounts))
+	assert.False(t, Enabled(ClusterInsights))
+
+	require.NoError(t, Set("DownstreamProbe=false, ConcurrentNodegroups=true,"))
+	assert.False(t, Enabled(DownstreamProbe))
+	assert.True(t, Enabled(ConcurrentNodegroups))
+	assert.Equal(t, []State{
+		{Feature: ClusterInsights, Stage: Alpha, Enabled: false},
+		{Feature: ConcurrentNodegroups, Stage: Beta, Enabled: true},
+		{Feature: DownstreamNodeCounts, Stage: Alpha, Enabled: false},
+		{Feature: DownstreamProbe, Stage: Beta, Enabled: false},
+	}, States())
+
+	// overrides are replaced as a whole
+	require.NoError(t, Set("ConcurrentNodegroups=false"))
+	assert.True(t, Enabled(DownstreamProbe))
+	assert.False(t, Enabled(ConcurrentNodegroups))
+
+	assert.ErrorContains(t, Set("Unknown=true"), "unknown feature gate")
+	assert.Error(t, Set("DownstreamProbe"))
+	assert.Error(t, Set("DownstreamProbe=maybe"))
+	// invalid values don't change the gates
+	assert.False(t, Enabled(ConcurrentNodegroups))
+}
@@ -0,0 +1,39 @@
+/*
+Copyright 2019 Wrangler Sample Controller Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by main. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/wrangler/v3/pkg/generic"
+)
+
+// EKSClusterTemplateController interface for managing EKSClusterTemplate resources.
+type EKSClusterTemplateController interface {
+	generic.ControllerInterface[*v1.EKSClusterTemplate, *v1.EKSClusterTemplateList]
+}
+
+// EKSClusterTemplateClient interface for managing EKSClusterTemplate resources in Kubernetes.
+type EKSClusterTemplateClient interface {
+	generic.ClientInterface[*v1.EKSClusterTemplate, *v1.EKSClusterTemplateList]
+}
+
+// EKSClusterTemplateCache interface for retrieving EKSClusterTemplate resources in memory.
+type EKSClusterTemplateCache interface {
+	generic.CacheInterface[*v1.EKSClusterTemplate]
+}
@@ -32,6 +32,8 @@ func init() {
 
 type Interface interface {
 	EKSClusterConfig() EKSClusterConfigController
+	EKSClusterTemplate() EKSClusterTemplateController
+	EKSInventory() EKSInventoryController
 }
 
 func New(controllerFactory controller.SharedControllerFactory) Interface {
@@ -47,3 +49,11 @@ type version struct {
 func (v *version) EKSClusterConfig() EKSClusterConfigController {
 	return generic.NewController[*v1.EKSClusterConfig, *v1.EKSClusterConfigList](schema.GroupVersionKind{Group: "eks.cattle.io", Version: "v1", Kind: "EKSClusterConfig"}, "eksclusterconfigs", true, v.controllerFactory)
 }
+
+func (v *version) EKSClusterTemplate() EKSClusterTemplateController {
+	return generic.NewController[*v1.EKSClusterTemplate, *v1.EKSClusterTemplateList](schema.GroupVersionKind{Group: "eks.cattle.io", Version: "v1", Kind: "EKSClusterTemplate"}, "eksclustertemplates", true, v.controllerFactory)
+}
+
+func (v *version) EKSInventory() EKSInventoryController {
+	return generic.NewController[*v1.EKSInventory, *v1.EKSInventoryList](schema.GroupVersionKind{Group: "eks.cattle.io", Version: "v1", Kind: "EKSInventory"}, "eksinventories", true, v.controllerFactory)
+}
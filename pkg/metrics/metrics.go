@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	lassometrics "github.com/rancher/lasso/pkg/metrics"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rancher/eks-operator/pkg/features"
+)
+
+var featureEnabled = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "eks_operator_feature_enabled",
+	Help: "Whether a feature gate of the operator is enabled (1) or disabled (0).",
+}, []string{"name", "stage"})
+
+// Register registers the controller and workqueue metrics (depth, retries, longest running processor, etc.) and the state
+// of the feature gates with the default prometheus registry. It must be called before any controller is created, as workqueues pick up their metrics
+// provider on creation.
+func Register() {
+	registerFeatureGates()
+	if lassometrics.Enabled() {
+		// already registered through the CATTLE_PROMETHEUS_METRICS environment variable
+		return
+	}
+	lassometrics.MustRegisterWithWorkqueue(prometheus.DefaultRegisterer)
+}
+
+// registerFeatureGates exposes the state of the feature gates, which is fixed once the flags are parsed.
+func registerFeatureGates() {
+	prometheus.MustRegister(featureEnabled)
+	for _, state := range features.States() {
+		value := 0.0
+		if state.Enabled {
+			value = 1
+		}
+		featureEnabled.WithLabelValues(string(state.Feature), string(state.Stage)).Set(value)
+	}
+}
+
+// Serve exposes the metrics registered with the default prometheus registry on the /metrics path of the given address
+// until the context is done.
+func Serve(ctx context.Context, address string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	server := &http.Server{
+		Addr:              address,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		if err := server.Shutdown(context.Background()); err != nil {
+			logrus.Errorf("Error shutting down metrics server: %v", err)
+		}
+	}()
+
+	go func() {
+		logrus.Infof("Serving metrics on [%s]", address)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logrus.Errorf("Error serving metrics: %v", err)
+		}
+	}()
+}
@@ -192,6 +192,343 @@ Outputs:
       - !Join [ ",", [ !Ref Subnet01, !Ref Subnet02, !Ref Subnet03 ] ]
       - !Join [ ",", [ !Ref Subnet01, !Ref Subnet02 ] ]
 
+  VpcId:
+    Description: The VPC Id
+    Value: !Ref VPC
+`
+	PrivateVpcTemplate = `---
+AWSTemplateFormatVersion: '2010-09-09'
+Description: 'Amazon EKS Private VPC'
+
+Parameters:
+
+  VpcBlock:
+    Type: String
+    Default: 192.168.0.0/16
+    Description: The CIDR range for the VPC. This should be a valid private (RFC 1918) CIDR range.
+
+  PublicSubnet01Block:
+    Type: String
+    Default: 192.168.0.0/20
+    Description: CidrBlock for public subnet 01 within the VPC, used only for the NAT gateways
+
+  PublicSubnet02Block:
+    Type: String
+    Default: 192.168.16.0/20
+    Description: CidrBlock for public subnet 02 within the VPC, used only for the NAT gateways
+
+  PrivateSubnet01Block:
+    Type: String
+    Default: 192.168.64.0/18
+    Description: CidrBlock for private subnet 01 within the VPC
+
+  PrivateSubnet02Block:
+    Type: String
+    Default: 192.168.128.0/18
+    Description: CidrBlock for private subnet 02 within the VPC
+
+Metadata:
+  AWS::CloudFormation::Interface:
+    ParameterGroups:
+      -
+        Label:
+          default: "Worker Network Configuration"
+        Parameters:
+          - VpcBlock
+          - PublicSubnet01Block
+          - PublicSubnet02Block
+          - PrivateSubnet01Block
+          - PrivateSubnet02Block
+
+Resources:
+  VPC:
+    Type: AWS::EC2::VPC
+    Properties:
+      CidrBlock:  !Ref VpcBlock
+      EnableDnsSupport: true
+      EnableDnsHostnames: true
+      Tags:
+      - Key: Name
+        Value: !Sub '${AWS::StackName}-VPC'
+
+  InternetGateway:
+    Type: "AWS::EC2::InternetGateway"
+
+  VPCGatewayAttachment:
+    Type: "AWS::EC2::VPCGatewayAttachment"
+    Properties:
+      InternetGatewayId: !Ref InternetGateway
+      VpcId: !Ref VPC
+
+  PublicRouteTable:
+    Type: AWS::EC2::RouteTable
+    Properties:
+      VpcId: !Ref VPC
+      Tags:
+      - Key: Name
+        Value: Public Subnets
+      - Key: Network
+        Value: Public
+
+  PublicRoute:
+    DependsOn: VPCGatewayAttachment
+    Type: AWS::EC2::Route
+    Properties:
+      RouteTableId: !Ref PublicRouteTable
+      DestinationCidrBlock: 0.0.0.0/0
+      GatewayId: !Ref InternetGateway
+
+  PublicSubnet01:
+    Type: AWS::EC2::Subnet
+    Metadata:
+      Comment: Public Subnet 01
+    Properties:
+      MapPublicIpOnLaunch: true
+      AvailabilityZone:
+        Fn::Select:
+        - '0'
+        - Fn::GetAZs:
+            Ref: AWS::Region
+      CidrBlock:
+        Ref: PublicSubnet01Block
+      VpcId:
+        Ref: VPC
+      Tags:
+      - Key: Name
+        Value: !Sub "${AWS::StackName}-PublicSubnet01"
+      - Key: kubernetes.io/role/elb
+        Value: 1
+
+  PublicSubnet02:
+    Type: AWS::EC2::Subnet
+    Metadata:
+      Comment: Public Subnet 02
+    Properties:
+      MapPublicIpOnLaunch: true
+      AvailabilityZone:
+        Fn::Select:
+        - '1'
+        - Fn::GetAZs:
+            Ref: AWS::Region
+      CidrBlock:
+        Ref: PublicSubnet02Block
+      VpcId:
+        Ref: VPC
+      Tags:
+      - Key: Name
+        Value: !Sub "${AWS::StackName}-PublicSubnet02"
+      - Key: kubernetes.io/role/elb
+        Value: 1
+
+  PublicSubnet01RouteTableAssociation:
+    Type: AWS::EC2::SubnetRouteTableAssociation
+    Properties:
+      SubnetId: !Ref PublicSubnet01
+      RouteTableId: !Ref PublicRouteTable
+
+  PublicSubnet02RouteTableAssociation:
+    Type: AWS::EC2::SubnetRouteTableAssociation
+    Properties:
+      SubnetId: !Ref PublicSubnet02
+      RouteTableId: !Ref PublicRouteTable
+
+  NatGatewayEIP01:
+    DependsOn: VPCGatewayAttachment
+    Type: AWS::EC2::EIP
+    Properties:
+      Domain: vpc
+
+  NatGatewayEIP02:
+    DependsOn: VPCGatewayAttachment
+    Type: AWS::EC2::EIP
+    Properties:
+      Domain: vpc
+
+  NatGateway01:
+    Type: AWS::EC2::NatGateway
+    Properties:
+      AllocationId: !GetAtt NatGatewayEIP01.AllocationId
+      SubnetId: !Ref PublicSubnet01
+      Tags:
+      - Key: Name
+        Value: !Sub "${AWS::StackName}-NatGateway01"
+
+  NatGateway02:
+    Type: AWS::EC2::NatGateway
+    Properties:
+      AllocationId: !GetAtt NatGatewayEIP02.AllocationId
+      SubnetId: !Ref PublicSubnet02
+      Tags:
+      - Key: Name
+        Value: !Sub "${AWS::StackName}-NatGateway02"
+
+  PrivateRouteTable01:
+    Type: AWS::EC2::RouteTable
+    Properties:
+      VpcId: !Ref VPC
+      Tags:
+      - Key: Name
+        Value: Private Subnet 01
+      - Key: Network
+        Value: Private
+
+  PrivateRouteTable02:
+    Type: AWS::EC2::RouteTable
+    Properties:
+      VpcId: !Ref VPC
+      Tags:
+      - Key: Name
+        Value: Private Subnet 02
+      - Key: Network
+        Value: Private
+
+  PrivateRoute01:
+    Type: AWS::EC2::Route
+    Properties:
+      RouteTableId: !Ref PrivateRouteTable01
+      DestinationCidrBlock: 0.0.0.0/0
+      NatGatewayId: !Ref NatGateway01
+
+  PrivateRoute02:
+    Type: AWS::EC2::Route
+    Properties:
+      RouteTableId: !Ref PrivateRouteTable02
+      DestinationCidrBlock: 0.0.0.0/0
+      NatGatewayId: !Ref NatGateway02
+
+  PrivateSubnet01:
+    Type: AWS::EC2::Subnet
+    Metadata:
+      Comment: Private Subnet 01
+    Properties:
+      MapPublicIpOnLaunch: false
+      AvailabilityZone:
+        Fn::Select:
+        - '0'
+        - Fn::GetAZs:
+            Ref: AWS::Region
+      CidrBlock:
+        Ref: PrivateSubnet01Block
+      VpcId:
+        Ref: VPC
+      Tags:
+      - Key: Name
+        Value: !Sub "${AWS::StackName}-PrivateSubnet01"
+      - Key: kubernetes.io/role/internal-elb
+        Value: 1
+
+  PrivateSubnet02:
+    Type: AWS::EC2::Subnet
+    Metadata:
+      Comment: Private Subnet 02
+    Properties:
+      MapPublicIpOnLaunch: false
+      AvailabilityZone:
+        Fn::Select:
+        - '1'
+        - Fn::GetAZs:
+            Ref: AWS::Region
+      CidrBlock:
+        Ref: PrivateSubnet02Block
+      VpcId:
+        Ref: VPC
+      Tags:
+      - Key: Name
+        Value: !Sub "${AWS::StackName}-PrivateSubnet02"
+      - Key: kubernetes.io/role/internal-elb
+        Value: 1
+
+  PrivateSubnet01RouteTableAssociation:
+    Type: AWS::EC2::SubnetRouteTableAssociation
+    Properties:
+      SubnetId: !Ref PrivateSubnet01
+      RouteTableId: !Ref PrivateRouteTable01
+
+  PrivateSubnet02RouteTableAssociation:
+    Type: AWS::EC2::SubnetRouteTableAssociation
+    Properties:
+      SubnetId: !Ref PrivateSubnet02
+      RouteTableId: !Ref PrivateRouteTable02
+
+  EndpointSecurityGroup:
+    Type: AWS::EC2::SecurityGroup
+    Properties:
+      GroupDescription: Allows HTTPS from within the VPC to the VPC endpoints
+      VpcId: !Ref VPC
+      SecurityGroupIngress:
+      - IpProtocol: tcp
+        FromPort: 443
+        ToPort: 443
+        CidrIp: !Ref VpcBlock
+
+  S3Endpoint:
+    Type: AWS::EC2::VPCEndpoint
+    Properties:
+      ServiceName: !Sub "com.amazonaws.${AWS::Region}.s3"
+      VpcEndpointType: Gateway
+      VpcId: !Ref VPC
+      RouteTableIds:
+      - !Ref PrivateRouteTable01
+      - !Ref PrivateRouteTable02
+
+  ECRAPIEndpoint:
+    Type: AWS::EC2::VPCEndpoint
+    Properties:
+      ServiceName: !Sub "com.amazonaws.${AWS::Region}.ecr.api"
+      VpcEndpointType: Interface
+      VpcId: !Ref VPC
+      PrivateDnsEnabled: true
+      SubnetIds:
+      - !Ref PrivateSubnet01
+      - !Ref PrivateSubnet02
+      SecurityGroupIds:
+      - !Ref EndpointSecurityGroup
+
+  ECRDKREndpoint:
+    Type: AWS::EC2::VPCEndpoint
+    Properties:
+      ServiceName: !Sub "com.amazonaws.${AWS::Region}.ecr.dkr"
+      VpcEndpointType: Interface
+      VpcId: !Ref VPC
+      PrivateDnsEnabled: true
+      SubnetIds:
+      - !Ref PrivateSubnet01
+      - !Ref PrivateSubnet02
+      SecurityGroupIds:
+      - !Ref EndpointSecurityGroup
+
+  EC2Endpoint:
+    Type: AWS::EC2::VPCEndpoint
+    Properties:
+      ServiceName: !Sub "com.amazonaws.${AWS::Region}.ec2"
+      VpcEndpointType: Interface
+      VpcId: !Ref VPC
+      PrivateDnsEnabled: true
+      SubnetIds:
+      - !Ref PrivateSubnet01
+      - !Ref PrivateSubnet02
+      SecurityGroupIds:
+      - !Ref EndpointSecurityGroup
+
+  STSEndpoint:
+    Type: AWS::EC2::VPCEndpoint
+    Properties:
+      ServiceName: !Sub "com.amazonaws.${AWS::Region}.sts"
+      VpcEndpointType: Interface
+      VpcId: !Ref VPC
+      PrivateDnsEnabled: true
+      SubnetIds:
+      - !Ref PrivateSubnet01
+      - !Ref PrivateSubnet02
+      SecurityGroupIds:
+      - !Ref EndpointSecurityGroup
+
+Outputs:
+
+  SubnetIds:
+    Description: The private subnets in the VPC
+    Value: !Join [ ",", [ !Ref PrivateSubnet01, !Ref PrivateSubnet02 ] ]
+
   VpcId:
     Description: The VPC Id
     Value: !Ref VPC
@@ -201,6 +538,23 @@ AWSTemplateFormatVersion: 2010-09-09
 Description: Amazon EKS - Node Group
 
 
+Parameters:
+
+  PermissionsBoundary:
+    Type: String
+    Default: ""
+    Description: The ARN of the permissions boundary of the role, none if empty
+
+  AdditionalPolicyArns:
+    Type: CommaDelimitedList
+    Default: ""
+    Description: The ARNs of managed policies attached to the role in addition to the node policies
+
+Conditions:
+
+  HasPermissionsBoundary: !Not [!Equals [!Ref PermissionsBoundary, ""]]
+  HasAdditionalPolicies: !Not [!Equals [!Join ["", !Ref AdditionalPolicyArns], ""]]
+
 Resources:
 
   NodeInstanceRole:
@@ -214,10 +568,20 @@ Resources:
               Service: %s
             Action: sts:AssumeRole
       Path: "/"
-      ManagedPolicyArns:
-        - arn:aws:iam::aws:policy/AmazonEKSWorkerNodePolicy
-        - arn:aws:iam::aws:policy/AmazonEKS_CNI_Policy
-        - arn:aws:iam::aws:policy/AmazonEC2ContainerRegistryReadOnly
+      PermissionsBoundary: !If [HasPermissionsBoundary, !Ref PermissionsBoundary, !Ref AWS::NoValue]
+      ManagedPolicyArns: !If
+        - HasAdditionalPolicies
+        - !Split
+          - ","
+          - !Join
+            - ","
+            - - arn:aws:iam::aws:policy/AmazonEKSWorkerNodePolicy
+              - arn:aws:iam::aws:policy/AmazonEKS_CNI_Policy
+              - arn:aws:iam::aws:policy/AmazonEC2ContainerRegistryReadOnly
+              - !Join [",", !Ref AdditionalPolicyArns]
+        - - arn:aws:iam::aws:policy/AmazonEKSWorkerNodePolicy
+          - arn:aws:iam::aws:policy/AmazonEKS_CNI_Policy
+          - arn:aws:iam::aws:policy/AmazonEC2ContainerRegistryReadOnly
 
 Outputs:
 
@@ -230,6 +594,23 @@ AWSTemplateFormatVersion: '2010-09-09'
 Description: 'Amazon EKS Service Role'
 
 
+Parameters:
+
+  PermissionsBoundary:
+    Type: String
+    Default: ""
+    Description: The ARN of the permissions boundary of the role, none if empty
+
+  AdditionalPolicyArns:
+    Type: CommaDelimitedList
+    Default: ""
+    Description: The ARNs of managed policies attached to the role in addition to the EKS cluster policies
+
+Conditions:
+
+  HasPermissionsBoundary: !Not [!Equals [!Ref PermissionsBoundary, ""]]
+  HasAdditionalPolicies: !Not [!Equals [!Join ["", !Ref AdditionalPolicyArns], ""]]
+
 Resources:
 
   AWSServiceRoleForAmazonEKS:
@@ -244,9 +625,18 @@ Resources:
             - eks.amazonaws.com
           Action:
           - sts:AssumeRole
-      ManagedPolicyArns:
-        - arn:aws:iam::aws:policy/AmazonEKSServicePolicy
-        - arn:aws:iam::aws:policy/AmazonEKSClusterPolicy
+      PermissionsBoundary: !If [HasPermissionsBoundary, !Ref PermissionsBoundary, !Ref AWS::NoValue]
+      ManagedPolicyArns: !If
+        - HasAdditionalPolicies
+        - !Split
+          - ","
+          - !Join
+            - ","
+            - - arn:aws:iam::aws:policy/AmazonEKSServicePolicy
+              - arn:aws:iam::aws:policy/AmazonEKSClusterPolicy
+              - !Join [",", !Ref AdditionalPolicyArns]
+        - - arn:aws:iam::aws:policy/AmazonEKSServicePolicy
+          - arn:aws:iam::aws:policy/AmazonEKSClusterPolicy
 
 Outputs:
 
@@ -299,5 +689,46 @@ Outputs:
     Export:
       Name: !Sub "${AWS::StackName}-RoleArn"
 
+`
+
+	EBSCSIDriverPodIdentityTemplate = `---
+AWSTemplateFormatVersion: '2010-09-09'
+Description: 'Amazon EKS EBS CSI Driver Pod Identity Role'
+
+
+Parameters:
+
+  AmazonEBSCSIDriverPolicyArn:
+    Type: String
+    Default: arn:aws:iam::aws:policy/service-role/AmazonEBSCSIDriverPolicy
+    Description: The ARN of the managed policy
+
+Resources:
+
+  AWSEBSCSIDriverPodIdentityRoleForAmazonEKS:
+    Type: AWS::IAM::Role
+    Properties:
+      AssumeRolePolicyDocument:
+        Version: '2012-10-17'
+        Statement:
+        - Effect: Allow
+          Principal:
+            Service:
+            - pods.eks.amazonaws.com
+          Action:
+          - sts:AssumeRole
+          - sts:TagSession
+      Path: "/"
+      ManagedPolicyArns:
+      - !Ref AmazonEBSCSIDriverPolicyArn
+
+Outputs:
+
+  EBSCSIDriverPodIdentityRole:
+    Description: The role the EBS CSI driver assumes through the pod identity association of its add-on
+    Value: !GetAtt AWSEBSCSIDriverPodIdentityRoleForAmazonEKS.Arn
+    Export:
+      Name: !Sub "${AWS::StackName}-RoleArn"
+
 `
 )
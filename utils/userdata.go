@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// HashUserData returns the hex encoded sha256 hash of the given userdata, or an empty string if there is none.
+// The hash allows changes to userdata to be detected without keeping the userdata itself around.
+func HashUserData(userData *string) string {
+	if userData == nil || *userData == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(*userData))
+	return hex.EncodeToString(sum[:])
+}
+
+// RedactUserData returns a representation of the given userdata that is safe to log.
+func RedactUserData(userData *string) string {
+	return RedactUserDataHash(HashUserData(userData))
+}
+
+// RedactUserDataHash returns a representation of userdata with the given hash that is safe to log.
+func RedactUserDataHash(hash string) string {
+	if hash == "" {
+		return "<none>"
+	}
+
+	return fmt.Sprintf("<redacted sha256:%s>", hash)
+}
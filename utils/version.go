@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/blang/semver"
+)
+
+// NormalizeKubernetesVersion returns the kubernetes version in the major.minor format EKS accepts, without a leading v
+// or surrounding spaces. Versions with a patch level are rejected, EKS picks the patch version itself.
+func NormalizeKubernetesVersion(version string) (string, error) {
+	normalized := strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(version), "v"), "V")
+	parts := strings.Split(normalized, ".")
+	if len(parts) == 3 {
+		return "", fmt.Errorf("kubernetes version [%s] has a patch level, EKS only accepts major.minor versions", version)
+	}
+	if len(parts) != 2 {
+		return "", fmt.Errorf("kubernetes version [%s] must be in the major.minor format", version)
+	}
+	for _, part := range parts {
+		if _, err := strconv.ParseUint(part, 10, 64); err != nil || len(part) > 1 && part[0] == '0' {
+			return "", fmt.Errorf("kubernetes version [%s] must be in the major.minor format", version)
+		}
+	}
+	return normalized, nil
+}
+
+// ParseKubernetesVersion normalizes the kubernetes version and parses it as the .0 patch version, so that versions can
+// be compared.
+func ParseKubernetesVersion(version string) (semver.Version, error) {
+	normalized, err := NormalizeKubernetesVersion(version)
+	if err != nil {
+		return semver.Version{}, err
+	}
+	return semver.Parse(normalized + ".0")
+}